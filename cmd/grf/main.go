@@ -0,0 +1,19 @@
+// Command grf contains the development tools of the framework. The `diff-schema` command compares
+// two OpenAPI documents and exits with status 1 if the new one contains breaking changes:
+//
+//	grf diff-schema old.json new.json
+//
+// The commands working with the models, like `gen fake`, `dump`, `reindex` and
+// `warm-cache`, are registered by the projects in their own binaries, see the cli package.
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/glothriel/grf/pkg/cli"
+)
+
+func main() {
+	os.Exit(cli.New().Run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
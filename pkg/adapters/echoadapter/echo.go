@@ -0,0 +1,49 @@
+// Package echoadapter mounts GRF views on Echo, see the adapters package. It's a separate module, so
+// the applications using gin don't depend on Echo.
+package echoadapter
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/views"
+	"github.com/labstack/echo/v4"
+)
+
+// Router registers the routes of the views on Echo
+type Router struct {
+	e          *echo.Echo
+	prefix     string
+	middleware []echo.MiddlewareFunc
+	runner     *views.RouteRunner
+}
+
+// NewRouter returns the views.Router registering the routes on e under prefix, with the route level
+// middleware, for example
+// `views.NewModelViewSet[Person]("/people", driver).RegisterOn(echoadapter.NewRouter(e, "/api"))`
+func NewRouter(e *echo.Echo, prefix string, middleware ...echo.MiddlewareFunc) *Router {
+	return &Router{
+		e:          e,
+		prefix:     views.JoinPaths("/", prefix),
+		middleware: middleware,
+		runner:     views.NewRouteRunner(),
+	}
+}
+
+func (r *Router) Handle(method, path string, handlers ...gin.HandlerFunc) {
+	fullPath := views.JoinPaths(r.prefix, path)
+	handler := echo.WrapHandler(r.runner.Add(method, fullPath, handlers...))
+	r.e.Add(method, echoPath(fullPath), handler, r.middleware...)
+}
+
+func (r *Router) BasePath() string {
+	return r.prefix
+}
+
+// echoPath converts the catch-all params of gin, `*name`, to the ones of Echo, which are unnamed
+func echoPath(path string) string {
+	if i := strings.Index(path, "/*"); i >= 0 {
+		return path[:i] + "/*"
+	}
+	return path
+}
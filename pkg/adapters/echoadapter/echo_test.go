@@ -0,0 +1,82 @@
+package echoadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/views"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockModel struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func serve(e *echo.Echo, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	e.ServeHTTP(w, req)
+	return w
+}
+
+func TestRouter(t *testing.T) {
+	// given
+	e := echo.New()
+	e.GET("/health", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	viewset := views.NewModelViewSet[mockModel]("/mocks", queries.InMemory[mockModel](mockModel{Name: "foo"}))
+	viewset.RegisterOn(NewRouter(e, "/api"))
+
+	// when
+	list := serve(e, http.MethodGet, "/api/mocks", "")
+	create := serve(e, http.MethodPost, "/api/mocks", `{"name": "bar"}`)
+	retrieve := serve(e, http.MethodGet, "/api/mocks/2", "")
+	head := serve(e, http.MethodHead, "/api/mocks/1", "")
+	unhandled := serve(e, http.MethodDelete, "/api/mocks", "")
+	health := serve(e, http.MethodGet, "/health", "")
+
+	// then
+	assert.Equal(t, http.StatusOK, list.Code)
+	assert.Equal(t, `[{"id":1,"name":"foo"}]`, list.Body.String())
+	assert.Equal(t, http.StatusCreated, create.Code)
+	assert.Equal(t, http.StatusOK, retrieve.Code)
+	assert.Equal(t, `{"id":2,"name":"bar"}`, retrieve.Body.String())
+	assert.Equal(t, http.StatusOK, head.Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, unhandled.Code)
+	assert.Equal(t, "GET, HEAD, POST, OPTIONS", unhandled.Header().Get("Allow"))
+	assert.Equal(t, http.StatusNoContent, health.Code)
+}
+
+func TestRouterAppliesTheMiddlewareOfEcho(t *testing.T) {
+	// given
+	e := echo.New()
+	rejectAnonymous := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Header.Get("Authorization") == "" {
+				return c.NoContent(http.StatusUnauthorized)
+			}
+			return next(c)
+		}
+	}
+	views.NewModelViewSet[mockModel]("/mocks", queries.InMemory[mockModel]()).
+		WithName("protectedmock").
+		RegisterOn(NewRouter(e, "/", rejectAnonymous))
+
+	// when
+	anonymous := serve(e, http.MethodGet, "/mocks", "")
+
+	// then
+	assert.Equal(t, http.StatusUnauthorized, anonymous.Code)
+}
+
+func TestEchoPath(t *testing.T) {
+	assert.Equal(t, "/things/:id", echoPath("/things/:id"))
+	assert.Equal(t, "/files/*", echoPath("/files/*path"))
+}
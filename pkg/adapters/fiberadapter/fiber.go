@@ -0,0 +1,57 @@
+// Package fiberadapter mounts GRF views on Fiber, serving them with fasthttp, see the adapters
+// package. It's a separate module, so the applications using gin don't depend on Fiber. The views
+// read the requests and write the responses using gin.Context, so every request is converted to a
+// net/http one, the responses are copied back to fasthttp.
+package fiberadapter
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/views"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// Router registers the routes of the views on a Fiber app
+type Router struct {
+	app        *fiber.App
+	prefix     string
+	middleware []fiber.Handler
+	runner     *views.RouteRunner
+}
+
+// NewRouter returns the views.Router registering the routes on the app under prefix, with the route
+// level middleware, for example
+// `views.NewModelViewSet[Person]("/people", driver).RegisterOn(fiberadapter.NewRouter(app, "/api"))`
+func NewRouter(app *fiber.App, prefix string, middleware ...fiber.Handler) *Router {
+	return &Router{
+		app:        app,
+		prefix:     views.JoinPaths("/", prefix),
+		middleware: middleware,
+		runner:     views.NewRouteRunner(),
+	}
+}
+
+func (r *Router) Handle(method, path string, handlers ...gin.HandlerFunc) {
+	fullPath := views.JoinPaths(r.prefix, path)
+	serve := fasthttpadaptor.NewFastHTTPHandler(r.runner.Add(method, fullPath, handlers...))
+	handler := func(c *fiber.Ctx) error {
+		serve(c.Context())
+		return nil
+	}
+	r.app.Add(method, fiberPath(fullPath), append(slices.Clip(r.middleware), handler)...)
+}
+
+func (r *Router) BasePath() string {
+	return r.prefix
+}
+
+// fiberPath converts the catch-all params of gin, `*name`, to the ones of Fiber, which are unnamed
+func fiberPath(path string) string {
+	if i := strings.Index(path, "/*"); i >= 0 {
+		return path[:i] + "/*"
+	}
+	return path
+}
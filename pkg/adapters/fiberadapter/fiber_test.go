@@ -0,0 +1,165 @@
+package fiberadapter
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/views"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type mockModel struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type response struct {
+	code        int
+	contentType string
+	body        string
+}
+
+func serve(t *testing.T, app *fiber.App, method, path, body string) response {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return response{code: resp.StatusCode, contentType: resp.Header.Get("Content-Type"), body: string(raw)}
+}
+
+func TestRouter(t *testing.T) {
+	// given
+	app := fiber.New()
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusNoContent)
+	})
+	viewset := views.NewModelViewSet[mockModel]("/mocks", queries.InMemory[mockModel](mockModel{Name: "foo"}))
+	viewset.RegisterOn(NewRouter(app, "/api"))
+
+	// when
+	list := serve(t, app, http.MethodGet, "/api/mocks", "")
+	create := serve(t, app, http.MethodPost, "/api/mocks", `{"name": "bar"}`)
+	retrieve := serve(t, app, http.MethodGet, "/api/mocks/2", "")
+	update := serve(t, app, http.MethodPut, "/api/mocks/2", `{"name": "baz"}`)
+	destroy := serve(t, app, http.MethodDelete, "/api/mocks/1", "")
+	unhandled := serve(t, app, http.MethodDelete, "/api/mocks", "")
+	health := serve(t, app, http.MethodGet, "/health", "")
+
+	// then
+	assert.Equal(t, http.StatusOK, list.code)
+	assert.Equal(t, "application/json; charset=utf-8", list.contentType)
+	assert.Equal(t, `[{"id":1,"name":"foo"}]`, list.body)
+	assert.Equal(t, http.StatusCreated, create.code)
+	assert.Equal(t, http.StatusOK, retrieve.code)
+	assert.Equal(t, `{"id":2,"name":"bar"}`, retrieve.body)
+	assert.Equal(t, http.StatusOK, update.code)
+	assert.Equal(t, `{"id":2,"name":"baz"}`, update.body)
+	assert.Equal(t, http.StatusNoContent, destroy.code)
+	assert.Equal(t, http.StatusMethodNotAllowed, unhandled.code)
+	assert.Equal(t, http.StatusNoContent, health.code)
+}
+
+func TestRouterAppliesTheMiddlewareOfFiber(t *testing.T) {
+	// given
+	app := fiber.New()
+	rejectAnonymous := func(c *fiber.Ctx) error {
+		if c.Get("Authorization") == "" {
+			return c.SendStatus(http.StatusUnauthorized)
+		}
+		return c.Next()
+	}
+	views.NewModelViewSet[mockModel]("/mocks", queries.InMemory[mockModel]()).
+		WithName("protectedmock").
+		RegisterOn(NewRouter(app, "/", rejectAnonymous))
+
+	// when
+	anonymous := serve(t, app, http.MethodGet, "/mocks", "")
+
+	// then
+	assert.Equal(t, http.StatusUnauthorized, anonymous.code)
+}
+
+func TestFiberPath(t *testing.T) {
+	assert.Equal(t, "/things/:id", fiberPath("/things/:id"))
+	assert.Equal(t, "/files/*", fiberPath("/files/*path"))
+}
+
+// The benchmarks compare serving the same viewset with gin and with Fiber, in process, without the
+// network. Run them with `go test -bench . -benchmem`.
+
+func benchmarkViewSet(name string) *views.ViewSet[mockModel] {
+	seed := make([]mockModel, 20)
+	for i := range seed {
+		seed[i] = mockModel{Name: "foo"}
+	}
+	return views.NewModelViewSet[mockModel]("/mocks", queries.InMemory[mockModel](seed...)).WithName(name)
+}
+
+func benchmarkGin(b *testing.B, name, method, path, body string) {
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	benchmarkViewSet(name).Register(engine)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		if w.Code >= http.StatusBadRequest {
+			b.Fatalf("unexpected status %d", w.Code)
+		}
+	}
+}
+
+func benchmarkFiber(b *testing.B, name, method, path, body string) {
+	gin.SetMode(gin.ReleaseMode)
+	app := fiber.New()
+	benchmarkViewSet(name).RegisterOn(NewRouter(app, "/"))
+	handler := app.Handler()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var ctx fasthttp.RequestCtx
+		ctx.Request.Header.SetMethod(method)
+		ctx.Request.SetRequestURI(path)
+		ctx.Request.SetBodyString(body)
+		handler(&ctx)
+		if ctx.Response.StatusCode() >= http.StatusBadRequest {
+			b.Fatalf("unexpected status %d", ctx.Response.StatusCode())
+		}
+	}
+}
+
+func BenchmarkGinList(b *testing.B) {
+	benchmarkGin(b, "ginlist", http.MethodGet, "/mocks", "")
+}
+
+func BenchmarkFiberList(b *testing.B) {
+	benchmarkFiber(b, "fiberlist", http.MethodGet, "/mocks", "")
+}
+
+func BenchmarkGinRetrieve(b *testing.B) {
+	benchmarkGin(b, "ginretrieve", http.MethodGet, "/mocks/1", "")
+}
+
+func BenchmarkFiberRetrieve(b *testing.B) {
+	benchmarkFiber(b, "fiberretrieve", http.MethodGet, "/mocks/1", "")
+}
+
+func BenchmarkGinUpdate(b *testing.B) {
+	benchmarkGin(b, "ginupdate", http.MethodPatch, "/mocks/1", `{"name": "bar"}`)
+}
+
+func BenchmarkFiberUpdate(b *testing.B) {
+	benchmarkFiber(b, "fiberupdate", http.MethodPatch, "/mocks/1", `{"name": "bar"}`)
+}
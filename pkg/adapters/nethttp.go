@@ -0,0 +1,72 @@
+// Package adapters mounts GRF views on routers other than gin, implementing views.Router. The
+// routes are registered one by one on the other router, so its routing and middleware apply to
+// them, and the handlers of the views are run by views.RouteRunner. This package adapts the net/http
+// routers, the adapters of the other frameworks are separate modules, like echoadapter, so the
+// applications using gin don't depend on them.
+package adapters
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/views"
+)
+
+// Mux is implemented by the net/http routers accepting the patterns of http.ServeMux introduced in
+// Go 1.22, like `GET /things/{id}`, including http.ServeMux itself. The applications declaring an
+// older Go version in go.mod need to set `httpmuxgo121=0` in GODEBUG.
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// HTTPRouter registers the routes of the views on a net/http router
+type HTTPRouter struct {
+	mux    Mux
+	prefix string
+	runner *views.RouteRunner
+}
+
+// NewHTTPRouter returns the views.Router registering the routes on the mux under prefix, for example
+// `views.NewModelViewSet[Person]("/people", driver).RegisterOn(adapters.NewHTTPRouter(mux, "/api"))`
+func NewHTTPRouter(mux Mux, prefix string) *HTTPRouter {
+	return &HTTPRouter{
+		mux:    mux,
+		prefix: views.JoinPaths("/", prefix),
+		runner: views.NewRouteRunner(),
+	}
+}
+
+func (r *HTTPRouter) Handle(method, path string, handlers ...gin.HandlerFunc) {
+	fullPath := views.JoinPaths(r.prefix, path)
+	handler := r.runner.Add(method, fullPath, handlers...)
+	if method == http.MethodHead {
+		// The mux routes the HEAD requests using the GET patterns, the patterns of both methods
+		// would conflict with the ones of the other paths
+		return
+	}
+	r.mux.Handle(method+" "+muxPattern(fullPath), handler)
+}
+
+func (r *HTTPRouter) BasePath() string {
+	return r.prefix
+}
+
+// muxPattern converts the path params of gin, `:name` and `*name`, to the wildcards of the mux. The
+// paths ending with a slash only match themselves, like in gin, instead of all the paths below them.
+func muxPattern(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			segments[i] = "{" + segment[1:] + "}"
+		case strings.HasPrefix(segment, "*"):
+			segments[i] = "{" + segment[1:] + "...}"
+		}
+	}
+	pattern := strings.Join(segments, "/")
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "{$}"
+	}
+	return pattern
+}
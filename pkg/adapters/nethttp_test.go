@@ -0,0 +1,116 @@
+// The module declares Go 1.21, which keeps the patterns of the mux of the older versions
+//go:debug httpmuxgo121=0
+
+package adapters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/glothriel/grf/pkg/grf"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/views"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockModel struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func serve(mux http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	mux.ServeHTTP(w, req)
+	return w
+}
+
+func TestHTTPRouter(t *testing.T) {
+	// given
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	viewset := views.NewModelViewSet[mockModel]("/mocks", queries.InMemory[mockModel](mockModel{Name: "foo"}))
+	viewset.RegisterOn(NewHTTPRouter(mux, "/api"))
+
+	// when
+	list := serve(mux, http.MethodGet, "/api/mocks", "")
+	create := serve(mux, http.MethodPost, "/api/mocks", `{"name": "bar"}`)
+	retrieve := serve(mux, http.MethodGet, "/api/mocks/2", "")
+	update := serve(mux, http.MethodPatch, "/api/mocks/1", `{"name": "baz"}`)
+	head := serve(mux, http.MethodHead, "/api/mocks/1", "")
+	unhandled := serve(mux, http.MethodDelete, "/api/mocks", "")
+	health := serve(mux, http.MethodGet, "/health", "")
+
+	// then
+	assert.Equal(t, http.StatusOK, list.Code)
+	assert.Equal(t, `[{"id":1,"name":"foo"}]`, list.Body.String())
+	assert.Equal(t, http.StatusCreated, create.Code)
+	assert.Equal(t, http.StatusOK, retrieve.Code)
+	assert.Equal(t, `{"id":2,"name":"bar"}`, retrieve.Body.String())
+	assert.Equal(t, http.StatusOK, update.Code)
+	assert.Equal(t, `{"id":1,"name":"baz"}`, update.Body.String())
+	assert.Equal(t, http.StatusOK, head.Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, unhandled.Code)
+	assert.Equal(t, "GET, HEAD, POST, OPTIONS", unhandled.Header().Get("Allow"))
+	assert.Equal(t, http.StatusNoContent, health.Code)
+}
+
+func TestHTTPRouterAppliesTheMiddlewareOfTheMux(t *testing.T) {
+	// given
+	mux := http.NewServeMux()
+	views.NewModelViewSet[mockModel]("/mocks", queries.InMemory[mockModel]()).
+		WithName("rootmock").
+		RegisterOn(NewHTTPRouter(mux, "/"))
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Served-By", "mux")
+		mux.ServeHTTP(w, r)
+	})
+
+	// when
+	list := serve(handler, http.MethodGet, "/mocks", "")
+	unmatched := serve(handler, http.MethodGet, "/unknown", "")
+
+	// then
+	assert.Equal(t, http.StatusOK, list.Code)
+	assert.Equal(t, "mux", list.Header().Get("X-Served-By"))
+	assert.Equal(t, http.StatusNotFound, unmatched.Code)
+}
+
+func TestHTTPRouterMountsAPIGroups(t *testing.T) {
+	// given
+	mux := http.NewServeMux()
+	grf.NewAPIGroup("/api/v1").Register(
+		views.NewModelViewSet[mockModel]("/mocks", queries.InMemory[mockModel](mockModel{Name: "foo"})).
+			WithName("groupmock"),
+	).MountOn(NewHTTPRouter(mux, "/"))
+
+	// when
+	retrieve := serve(mux, http.MethodGet, "/api/v1/mocks/1", "")
+
+	// then
+	assert.Equal(t, http.StatusOK, retrieve.Code)
+	assert.Equal(t, `{"id":1,"name":"foo"}`, retrieve.Body.String())
+}
+
+func TestMuxPattern(t *testing.T) {
+	for _, tt := range []struct {
+		path     string
+		expected string
+	}{
+		{path: "/things", expected: "/things"},
+		{path: "/things/:id", expected: "/things/{id}"},
+		{path: "/things/:id/parts/:part_id", expected: "/things/{id}/parts/{part_id}"},
+		{path: "/files/*path", expected: "/files/{path...}"},
+		{path: "/things/", expected: "/things/{$}"},
+		{path: "/", expected: "/{$}"},
+	} {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, muxPattern(tt.path))
+		})
+	}
+}
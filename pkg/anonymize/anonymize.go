@@ -0,0 +1,92 @@
+// Package anonymize replaces the personal data stored in the models, for example when the users
+// request erasure of their data, when the retention period expires or when the data is shared with
+// the developers. The anonymizers of the fields are declared once, in a Policy, and reused by all
+// of these use cases.
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/glothriel/grf/pkg/internal/fakenames"
+)
+
+// Anonymizer replaces the value of a field. Anonymizers replacing the values with strings, like
+// Hash or FakeName, should only be used for string fields.
+type Anonymizer func(value any) any
+
+// Redact replaces the values with nil, the field has to be nullable
+func Redact() Anonymizer {
+	return func(any) any {
+		return nil
+	}
+}
+
+// Constant replaces the values with the constant, for example an empty string
+func Constant(constant any) Anonymizer {
+	return func(any) any {
+		return constant
+	}
+}
+
+// Truncate keeps the first characters of the strings, for example the first letter of the name or
+// the area of the postal code. Other values are kept.
+func Truncate(length int) Anonymizer {
+	return func(value any) any {
+		text, ok := value.(string)
+		if !ok {
+			return value
+		}
+		if runes := []rune(text); len(runes) > length {
+			return string(runes[:length])
+		}
+		return text
+	}
+}
+
+// Hash replaces the values with their HMAC-SHA256 hashes, keyed with the secret, so the equal values
+// are still equal after the anonymization, for example to join the data, but they can't be
+// recovered without the secret. Nil values are kept.
+func Hash(secret string) Anonymizer {
+	return func(value any) any {
+		if value == nil {
+			return nil
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(fmt.Sprint(value)))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+}
+
+// FakeName replaces the values with fake full names, like `Casey Porter`. The same values are
+// replaced with the same names, so the data stays consistent. Nil values are kept.
+func FakeName() Anonymizer {
+	return func(value any) any {
+		if value == nil {
+			return nil
+		}
+		sum := fnv.New64a()
+		sum.Write([]byte(fmt.Sprint(value)))
+		index := sum.Sum64()
+		first := fakenames.First[index%uint64(len(fakenames.First))]
+		last := fakenames.Last[(index/uint64(len(fakenames.First)))%uint64(len(fakenames.Last))]
+		return first + " " + last
+	}
+}
+
+// FakeEmail replaces the values with fake addresses in the domain, like `user-3f9a1c0b2d@example.com`.
+// The local parts are HMAC-SHA256 hashes keyed with the secret, like Hash, so the original
+// addresses can't be found by hashing the guessed ones. The same values are replaced with the same
+// addresses, so unique constraints are kept. Nil values are kept.
+func FakeEmail(secret, domain string) Anonymizer {
+	hash := Hash(secret)
+	return func(value any) any {
+		if value == nil {
+			return nil
+		}
+		return "user-" + hash(value).(string)[:10] + "@" + domain
+	}
+}
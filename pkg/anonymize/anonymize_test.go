@@ -0,0 +1,192 @@
+package anonymize
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/dummy"
+	"github.com/glothriel/grf/pkg/queries/gormq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAnonymizers(t *testing.T) {
+	tests := []struct {
+		name       string
+		anonymizer Anonymizer
+		value      any
+		expected   any
+	}{
+		{name: "redact", anonymizer: Redact(), value: "John", expected: nil},
+		{name: "constant", anonymizer: Constant(""), value: "John", expected: ""},
+		{name: "truncate", anonymizer: Truncate(2), value: "Łukasz", expected: "Łu"},
+		{name: "truncate short", anonymizer: Truncate(10), value: "Jo", expected: "Jo"},
+		{name: "truncate non string", anonymizer: Truncate(1), value: 42, expected: 42},
+		{
+			name: "hash", anonymizer: Hash("secret"), value: "john@example.com",
+			expected: "62f6d956c6a553410a5571d75aaf18a7ceaf78addce3d9999da2e289164e8598",
+		},
+		{name: "hash nil", anonymizer: Hash("secret"), value: nil, expected: nil},
+		{
+			name: "fake email", anonymizer: FakeEmail("secret", "example.com"), value: "john@gmail.com",
+			expected: "user-fb5b37608f@example.com",
+		},
+		{name: "fake name nil", anonymizer: FakeName(), value: nil, expected: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			anonymized := tt.anonymizer(tt.value)
+
+			// then
+			assert.Equal(t, tt.expected, anonymized)
+		})
+	}
+}
+
+func TestFakeNameIsConsistent(t *testing.T) {
+	// given
+	anonymizer := FakeName()
+
+	// when
+	first, second, other := anonymizer("John Smith"), anonymizer("John Smith"), anonymizer("Jane Doe")
+
+	// then
+	assert.Regexp(t, `^[A-Z][a-z]+ [A-Z][a-z]+$`, first)
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, other)
+}
+
+type customer struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	City  string `json:"city"`
+}
+
+func TestPolicy(t *testing.T) {
+	// given
+	driver := dummy.InMemoryDriver(
+		customer{Name: "John Smith", Email: "john@gmail.com", City: "Kraków"},
+		customer{Name: "Jane Doe", Email: "jane@gmail.com", City: "Gdańsk"},
+	)
+	policy := NewPolicy[customer]().
+		WithField("name", Constant("Erased")).
+		WithField("email", FakeEmail("secret", "example.com"))
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	var dump bytes.Buffer
+
+	// when
+	erased, eraseErr := policy.Erase(ctx, driver.CRUD(), 1)
+	dumpErr := policy.Dump(ctx, driver.CRUD(), &dump)
+	stored, _ := driver.CRUD().Retrieve(ctx, 2)
+
+	// then
+	assert.NoError(t, eraseErr)
+	assert.Equal(t, models.InternalValue{
+		"id": uint(1), "name": "Erased", "email": "user-fb5b37608f@example.com", "city": "Kraków",
+	}, erased)
+	assert.NoError(t, dumpErr)
+	assert.Equal(t, "Jane Doe", stored["name"])
+	assert.Contains(t, dump.String(), `"city":"Gdańsk"`)
+	assert.NotContains(t, dump.String(), "Jane Doe")
+	assert.NotContains(t, dump.String(), "gmail.com")
+	assert.Panics(t, func() {
+		NewPolicy[customer]().WithField("phone", Redact())
+	})
+}
+
+type contact struct {
+	ID    uint    `gorm:"primaryKey" json:"id"`
+	Name  string  `json:"name"`
+	Phone *string `json:"phone"`
+}
+
+func TestEraseWritesZeroValuesWithGorm(t *testing.T) {
+	// given
+	db, openErr := gorm.Open(sqlite.Open("file::memory:"))
+	require.NoError(t, openErr)
+	require.NoError(t, db.AutoMigrate(&contact{}))
+	phone := "555"
+	require.NoError(t, db.Create(&contact{Name: "John", Phone: &phone}).Error)
+	driver := gormq.Gorm[contact](gormq.Static(db))
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	for _, middleware := range driver.Middleware() {
+		middleware(ctx)
+	}
+	policy := NewPolicy[contact]().WithField("name", Constant("")).WithField("phone", Redact())
+
+	// when
+	erased, eraseErr := policy.Erase(ctx, driver.CRUD(), uint(1))
+	var stored contact
+	readErr := db.First(&stored, 1).Error
+
+	// then
+	assert.NoError(t, eraseErr)
+	assert.Equal(t, "", erased["name"])
+	assert.NoError(t, readErr)
+	assert.Equal(t, contact{ID: 1}, stored)
+}
+
+func TestDumpListsThePagesOfObjects(t *testing.T) {
+	// given
+	seed := make([]customer, 0, dumpPageSize+1)
+	for i := 0; i <= dumpPageSize; i++ {
+		seed = append(seed, customer{Name: "John Smith"})
+	}
+	queries := dummy.InMemoryDriver(seed...).CRUD()
+	windows := []grfctx.Window{}
+	list := queries.List
+	queries.List = func(ctx *gin.Context) ([]models.InternalValue, error) {
+		window, _ := grfctx.CurrentWindow(ctx)
+		windows = append(windows, window)
+		return list(ctx)
+	}
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	var dump bytes.Buffer
+
+	// when
+	dumpErr := NewPolicy[customer]().WithField("name", Redact()).Dump(ctx, queries, &dump)
+
+	// then
+	assert.NoError(t, dumpErr)
+	assert.Equal(t, []grfctx.Window{
+		{Offset: 0, Limit: dumpPageSize}, {Offset: dumpPageSize, Limit: dumpPageSize},
+	}, windows)
+	assert.Equal(t, dumpPageSize+1, bytes.Count(dump.Bytes(), []byte("\n")))
+	_, windowSet := grfctx.CurrentWindow(ctx)
+	assert.False(t, windowSet)
+}
+
+func TestDumpCommand(t *testing.T) {
+	// given
+	customers := dummy.InMemoryDriver(customer{Name: "John Smith", Email: "john@gmail.com", City: "Kraków"})
+	command := NewDumpCommand().
+		WithModel("customers", NewPolicy[customer]().WithField("name", Redact()).Dumper(customers)).
+		WithModel("others", NewPolicy[customer]().Dumper(dummy.InMemoryDriver[customer]()))
+	output := filepath.Join(t.TempDir(), "dump")
+	var stdout, stderr bytes.Buffer
+
+	// when
+	status := command.Run(context.Background(), []string{"-output", output, "customers"}, &stdout, &stderr)
+	unknownStatus := command.Run(context.Background(), []string{"orders"}, &bytes.Buffer{}, &stderr)
+
+	// then
+	assert.Equal(t, 0, status)
+	dumped, readErr := os.ReadFile(filepath.Join(output, "customers.jsonl"))
+	assert.NoError(t, readErr)
+	assert.JSONEq(t, `{"id": 1, "name": null, "email": "john@gmail.com", "city": "Kraków"}`, string(dumped))
+	assert.NoFileExists(t, filepath.Join(output, "others.jsonl"))
+	assert.Equal(t, "customers: written to "+filepath.Join(output, "customers.jsonl")+"\n", stdout.String())
+	assert.Equal(t, 2, unknownStatus)
+	assert.Contains(t, stderr.String(), "unknown model `orders`, expected one of: customers, others")
+}
@@ -0,0 +1,103 @@
+package anonymize
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/crud"
+	"github.com/glothriel/grf/pkg/scheduler"
+)
+
+// Dumper writes the anonymized objects of a model, see Policy.Dumper
+type Dumper func(ctx context.Context, w io.Writer) error
+
+// Dumper returns a Dumper writing the anonymized objects stored by the driver, see Dump
+func (p *Policy[Model]) Dumper(driver queries.Driver[Model]) Dumper {
+	return func(ctx context.Context, w io.Writer) error {
+		return scheduler.WithDriver(driver, func(ginCtx *gin.Context, q *crud.CRUD[Model]) error {
+			return p.Dump(ginCtx, q, w)
+		})(ctx)
+	}
+}
+
+// DumpCommand writes the sanitized dump of the registered models, as the `dump` command of the
+// project's binary, see cli.CLI. Only the project knows its models and drivers:
+//
+//	command := anonymize.NewDumpCommand().
+//		WithModel("customers", customerPolicy.Dumper(customersDriver)).
+//		WithModel("orders", anonymize.NewPolicy[Order]().Dumper(ordersDriver))
+//
+// The models without personal data are registered with empty policies.
+type DumpCommand struct {
+	names   []string
+	dumpers map[string]Dumper
+}
+
+// NewDumpCommand creates a DumpCommand without any models
+func NewDumpCommand() *DumpCommand {
+	return &DumpCommand{dumpers: map[string]Dumper{}}
+}
+
+// WithModel registers the dumper of the model
+func (c *DumpCommand) WithModel(name string, dumper Dumper) *DumpCommand {
+	if _, exists := c.dumpers[name]; !exists {
+		c.names = append(c.names, name)
+	}
+	c.dumpers[name] = dumper
+	return c
+}
+
+// Run writes the anonymized objects of the models named by the arguments, or of all the models, to
+// the `<model>.jsonl` files in the `-output` directory, like `-output dump customers`. It returns the
+// exit status: 0 on success, 1 if the dump couldn't be written and 2 if the arguments are invalid.
+func (c *DumpCommand) Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("dump", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	output := flags.String("output", "dump", "the directory the files are written to")
+	if parseErr := flags.Parse(args); parseErr != nil {
+		return 2
+	}
+	selected := flags.Args()
+	for _, name := range selected {
+		if _, ok := c.dumpers[name]; !ok {
+			fmt.Fprintf(stderr, "unknown model `%s`, expected one of: %s\n", name, strings.Join(c.names, ", "))
+			return 2
+		}
+	}
+	if mkdirErr := os.MkdirAll(*output, 0o750); mkdirErr != nil {
+		fmt.Fprintln(stderr, mkdirErr)
+		return 1
+	}
+	for _, name := range c.names {
+		if len(selected) > 0 && !slices.Contains(selected, name) {
+			continue
+		}
+		path := filepath.Join(*output, name+".jsonl")
+		if dumpErr := c.dump(ctx, name, path); dumpErr != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", name, dumpErr)
+			return 1
+		}
+		fmt.Fprintf(stdout, "%s: written to %s\n", name, path)
+	}
+	return 0
+}
+
+func (c *DumpCommand) dump(ctx context.Context, name, path string) error {
+	file, createErr := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if createErr != nil {
+		return createErr
+	}
+	if dumpErr := c.dumpers[name](ctx, file); dumpErr != nil {
+		file.Close()
+		return dumpErr
+	}
+	return file.Close()
+}
@@ -0,0 +1,94 @@
+package anonymize
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/crud"
+	"github.com/sirupsen/logrus"
+)
+
+// Policy declares the anonymizers of the model fields holding personal data
+type Policy[Model any] struct {
+	anonymizers map[string]Anonymizer
+}
+
+// NewPolicy creates a Policy without fields
+func NewPolicy[Model any]() *Policy[Model] {
+	return &Policy[Model]{anonymizers: map[string]Anonymizer{}}
+}
+
+// WithField anonymizes the field using the anonymizer, the field is the name of the internal value
+// key, usually the JSON name of the model field
+func (p *Policy[Model]) WithField(field string, anonymizer Anonymizer) *Policy[Model] {
+	var m Model
+	if _, ok := models.AsInternalValue(m)[field]; !ok {
+		logrus.Panicf("Could not find field `%s` on model `%s` when registering anonymizer", field, reflect.TypeOf(m))
+	}
+	p.anonymizers[field] = anonymizer
+	return p
+}
+
+// Apply returns a copy of the internal value with the fields anonymized
+func (p *Policy[Model]) Apply(internalValue models.InternalValue) models.InternalValue {
+	anonymized := maps.Clone(internalValue)
+	for field, anonymizer := range p.anonymizers {
+		anonymized[field] = anonymizer(internalValue[field])
+	}
+	return anonymized
+}
+
+// Erase anonymizes the stored object in place, for example to handle the erasure requests of the
+// users, or by a scheduled retention job. The object is looked up like by the detail views. The
+// fields of the policy are written even if anonymized to zero values, see grfctx.SetUpdatedFields.
+func (p *Policy[Model]) Erase(ctx *gin.Context, queries *crud.CRUD[Model], id any) (models.InternalValue, error) {
+	stored, retrieveErr := queries.Retrieve(ctx, id)
+	if retrieveErr != nil {
+		return nil, retrieveErr
+	}
+	grfctx.SetUpdatedFields(ctx, p.fields())
+	defer grfctx.SetUpdatedFields(ctx, nil)
+	return queries.Update(ctx, stored, p.Apply(stored), id)
+}
+
+func (p *Policy[Model]) fields() []string {
+	fields := make([]string, 0, len(p.anonymizers))
+	for field := range p.anonymizers {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// dumpPageSize is the number of objects Dump lists at once
+const dumpPageSize = 500
+
+// Dump writes the anonymized objects listed by the queries as JSON lines, one object per line,
+// for example to share a sanitized copy of the data with the developers. The objects are listed
+// page by page, using the windows of the query drivers, see grfctx.SetWindow.
+func (p *Policy[Model]) Dump(ctx *gin.Context, queries *crud.CRUD[Model], w io.Writer) error {
+	pageCtx := ctx.Copy()
+	encoder := json.NewEncoder(w)
+	for offset := 0; ; offset += dumpPageSize {
+		grfctx.SetWindow(pageCtx, grfctx.Window{Offset: offset, Limit: dumpPageSize})
+		listed, listErr := queries.List(pageCtx)
+		if listErr != nil {
+			return listErr
+		}
+		for _, internalValue := range listed {
+			if encodeErr := encoder.Encode(p.Apply(internalValue)); encodeErr != nil {
+				return encodeErr
+			}
+		}
+		// the last page is shorter, the drivers not supporting the windows list all the objects at once
+		if len(listed) != dumpPageSize {
+			return nil
+		}
+	}
+}
@@ -15,7 +15,7 @@ type AnonymousUserAuthentication struct{}
 func (a *AnonymousUserAuthentication) Authenticate(c *gin.Context) (bool, error) {
 	c.Set("user", &User{
 		Name:  "Anonymous",
-		Email: "anonymous@localhost",
+		Email: AnonymousEmail,
 	})
 	return true, nil
 }
@@ -0,0 +1,38 @@
+package authentication
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Permission decides if the authenticated request may be handled, like DRF's permission classes.
+// The views respond with 403 to the requests without the permission.
+type Permission interface {
+	HasPermission(*gin.Context) bool
+}
+
+// PermissionFunc is a Permission checked by the function, for example using CurrentUser
+type PermissionFunc func(*gin.Context) bool
+
+func (f PermissionFunc) HasPermission(c *gin.Context) bool {
+	return f(c)
+}
+
+// AllowAny permits all the requests, it declares the public views explicitly
+func AllowAny() Permission {
+	return PermissionFunc(func(*gin.Context) bool {
+		return true
+	})
+}
+
+// ReadOnly permits only the safe methods: GET, HEAD and OPTIONS
+func ReadOnly() Permission {
+	return PermissionFunc(func(c *gin.Context) bool {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return true
+		}
+		return false
+	})
+}
@@ -4,3 +4,11 @@ type User struct {
 	Name  string
 	Email string
 }
+
+// AnonymousEmail is the email of the user authenticated by AnonymousUserAuthentication
+const AnonymousEmail = "anonymous@localhost"
+
+// IsAnonymous reports if the user was authenticated by AnonymousUserAuthentication
+func (u *User) IsAnonymous() bool {
+	return u.Email == AnonymousEmail
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache stores values for a limited time. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored under the key, or false if it's missing or expired
+	Get(key string) (any, bool)
+	// Set stores the value under the key for the ttl duration
+	Set(key string, value any, ttl time.Duration)
+	// Delete removes the value stored under the key
+	Delete(key string)
+}
+
+type entry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// InMemoryCache is a Cache storing the values in a map of the current process
+type InMemoryCache struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]entry
+	// nextExpire is the time of the next scan for expired entries
+	nextExpire time.Time
+}
+
+func (c *InMemoryCache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stored, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(stored.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return stored.value, true
+}
+
+func (c *InMemoryCache) Set(key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	c.expire(now)
+	c.entries[key] = entry{value: value, expiresAt: now.Add(ttl)}
+	if c.nextExpire.IsZero() || now.Add(ttl).Before(c.nextExpire) {
+		c.nextExpire = now.Add(ttl)
+	}
+}
+
+func (c *InMemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// expire removes the expired entries, that were never read after expiring, so that the memory
+// usage doesn't grow with the number of distinct keys
+func (c *InMemoryCache) expire(now time.Time) {
+	if c.nextExpire.IsZero() || now.Before(c.nextExpire) {
+		return
+	}
+	c.nextExpire = time.Time{}
+	for key, stored := range c.entries {
+		if !now.Before(stored.expiresAt) {
+			delete(c.entries, key)
+		} else if c.nextExpire.IsZero() || stored.expiresAt.Before(c.nextExpire) {
+			c.nextExpire = stored.expiresAt
+		}
+	}
+}
+
+// NewInMemory creates an empty InMemoryCache
+func NewInMemory() *InMemoryCache {
+	return &InMemoryCache{
+		now:     time.Now,
+		entries: map[string]entry{},
+	}
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryCacheGetSet(t *testing.T) {
+	// given
+	c := NewInMemory()
+
+	// when
+	c.Set("foo", 42, time.Minute)
+	v, ok := c.Get("foo")
+	_, missingOk := c.Get("bar")
+
+	// then
+	assert.True(t, ok)
+	assert.Equal(t, 42, v)
+	assert.False(t, missingOk)
+}
+
+func TestInMemoryCacheExpiresEntries(t *testing.T) {
+	// given
+	now := time.Now()
+	c := NewInMemory()
+	c.now = func() time.Time { return now }
+	c.Set("short", 1, time.Second)
+	c.Set("long", 2, time.Hour)
+
+	// when
+	now = now.Add(time.Minute)
+	_, shortOk := c.Get("short")
+	_, longOk := c.Get("long")
+
+	// then
+	assert.False(t, shortOk)
+	assert.True(t, longOk)
+}
+
+func TestInMemoryCacheRemovesExpiredEntriesOnSet(t *testing.T) {
+	// given
+	now := time.Now()
+	c := NewInMemory()
+	c.now = func() time.Time { return now }
+	c.Set("foo", 1, time.Second)
+
+	// when
+	now = now.Add(time.Minute)
+	c.Set("bar", 2, time.Second)
+
+	// then
+	assert.Len(t, c.entries, 1)
+	assert.Contains(t, c.entries, "bar")
+}
+
+func TestInMemoryCacheDelete(t *testing.T) {
+	// given
+	c := NewInMemory()
+	c.Set("foo", 42, time.Minute)
+
+	// when
+	c.Delete("foo")
+	_, ok := c.Get("foo")
+
+	// then
+	assert.False(t, ok)
+}
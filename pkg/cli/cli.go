@@ -0,0 +1,97 @@
+// Package cli runs the commands of the `grf` binary. The `diff-schema` command is always available,
+// the commands working with the models, like `gen fake`, `dump`, `reindex` and `warm-cache`, need
+// the project's models and drivers, so the projects build their own binary, registering them:
+//
+//	func main() {
+//		os.Exit(maintenanceCommands.Register(cli.New()).
+//			WithCommand("gen fake", fakeCommand.Run).
+//			WithCommand("dump", dumpCommand.Run).
+//			Run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+//	}
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/glothriel/grf/pkg/openapi"
+)
+
+// CommandFunc runs a command with the arguments following its name, and returns the exit status
+type CommandFunc func(ctx context.Context, args []string, stdout, stderr io.Writer) int
+
+// CLI dispatches the arguments to the commands
+type CLI struct {
+	commands map[string]CommandFunc
+}
+
+// New creates a CLI with the `diff-schema` command
+func New() *CLI {
+	return (&CLI{commands: map[string]CommandFunc{}}).WithCommand("diff-schema", DiffSchema)
+}
+
+// WithCommand adds the command, the names can have several words, like `gen fake`
+func (c *CLI) WithCommand(name string, command CommandFunc) *CLI {
+	c.commands[name] = command
+	return c
+}
+
+// Run runs the command named by the leading arguments, the longest matching name is used. It
+// returns the exit status of the command, or 2 if there's no such command.
+func (c *CLI) Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	for words := len(args); words > 0; words-- {
+		if command, ok := c.commands[strings.Join(args[:words], " ")]; ok {
+			return command(ctx, args[words:], stdout, stderr)
+		}
+	}
+	fmt.Fprintf(stderr, "usage: grf <command> [args...], the commands are: %s\n", strings.Join(c.names(), ", "))
+	return 2
+}
+
+func (c *CLI) names() []string {
+	names := make([]string, 0, len(c.commands))
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DiffSchema compares two OpenAPI documents and exits with status 1 if the new one contains
+// breaking changes:
+//
+//	grf diff-schema old.json new.json
+func DiffSchema(_ context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) != 2 {
+		fmt.Fprintln(stderr, "usage: grf diff-schema <old.json> <new.json>")
+		return 2
+	}
+	documents := make([]*openapi.Document, 0, 2)
+	for _, path := range args {
+		raw, readErr := os.ReadFile(path)
+		if readErr != nil {
+			fmt.Fprintln(stderr, readErr)
+			return 2
+		}
+		document, parseErr := openapi.Parse(raw)
+		if parseErr != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", path, parseErr)
+			return 2
+		}
+		documents = append(documents, document)
+	}
+	changes := openapi.Diff(documents[0], documents[1])
+	if len(changes) == 0 {
+		fmt.Fprintln(stdout, "No breaking changes")
+		return 0
+	}
+	fmt.Fprintf(stdout, "Found %d breaking changes:\n", len(changes))
+	for _, change := range changes {
+		fmt.Fprintf(stdout, "  %s\n", change)
+	}
+	return 1
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCLIRun(t *testing.T) {
+	// given
+	var received []string
+	c := New().
+		WithCommand("gen", func(context.Context, []string, io.Writer, io.Writer) int { return 3 }).
+		WithCommand("gen fake", func(_ context.Context, args []string, _, _ io.Writer) int {
+			received = args
+			return 0
+		})
+	var stderr bytes.Buffer
+
+	// when
+	fakeStatus := c.Run(context.Background(), []string{"gen", "fake", "-count", "5"}, io.Discard, io.Discard)
+	genStatus := c.Run(context.Background(), []string{"gen", "other"}, io.Discard, io.Discard)
+	unknownStatus := c.Run(context.Background(), []string{"dump"}, io.Discard, &stderr)
+
+	// then
+	assert.Equal(t, 0, fakeStatus)
+	assert.Equal(t, []string{"-count", "5"}, received)
+	assert.Equal(t, 3, genStatus)
+	assert.Equal(t, 2, unknownStatus)
+	assert.Equal(t, "usage: grf <command> [args...], the commands are: diff-schema, gen, gen fake\n", stderr.String())
+}
+
+func TestDiffSchema(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	oldPath, newPath := filepath.Join(dir, "old.json"), filepath.Join(dir, "new.json")
+	document := `{"openapi": "3.0.0", "paths": {"/things": {"get": {"responses": {"200": {"description": "ok"}}}}}}`
+	assert.NoError(t, os.WriteFile(oldPath, []byte(document), 0o600))
+	assert.NoError(t, os.WriteFile(newPath, []byte(`{"openapi": "3.0.0", "paths": {}}`), 0o600))
+	var stdout bytes.Buffer
+
+	// when
+	unchanged := New().Run(context.Background(), []string{"diff-schema", oldPath, oldPath}, io.Discard, io.Discard)
+	breaking := New().Run(context.Background(), []string{"diff-schema", oldPath, newPath}, &stdout, io.Discard)
+	invalid := New().Run(context.Background(), []string{"diff-schema", oldPath}, io.Discard, io.Discard)
+
+	// then
+	assert.Equal(t, 0, unchanged)
+	assert.Equal(t, 1, breaking)
+	assert.Contains(t, stdout.String(), "Found 1 breaking changes:")
+	assert.Equal(t, 2, invalid)
+}
@@ -2,7 +2,9 @@ package detectors
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"encoding"
+	"encoding/json"
 	"reflect"
 
 	"github.com/glothriel/grf/pkg/fields"
@@ -17,8 +19,8 @@ func FieldTypes[Model any]() map[string]string {
 	var m Model
 	fields := reflect.VisibleFields(reflect.TypeOf(m))
 	for _, field := range fields {
-		if !field.Anonymous {
-			ret[field.Tag.Get("json")] = field.Type.String()
+		if name, included := modelFieldName(field); included {
+			ret[name] = field.Type.String()
 		}
 	}
 	return ret
@@ -31,8 +33,8 @@ func FieldNames[Model any]() map[string]string {
 	var m Model
 	fields := reflect.VisibleFields(reflect.TypeOf(m))
 	for _, field := range fields {
-		if !field.Anonymous {
-			ret[field.Tag.Get("json")] = field.Name
+		if name, included := modelFieldName(field); included {
+			ret[name] = field.Name
 		}
 	}
 	return ret
@@ -43,13 +45,27 @@ func Fields[Model any]() []string {
 	var m Model
 	fields := reflect.VisibleFields(reflect.TypeOf(m))
 	for _, field := range fields {
-		if !field.Anonymous && field.Tag.Get("json") != "" {
-			fieldNames = append(fieldNames, field.Tag.Get("json"))
+		if name, included := modelFieldName(field); included {
+			fieldNames = append(fieldNames, name)
 		}
 	}
 	return fieldNames
 }
 
+// isMapOrStruct returns true for map[string]T and struct types, that can be converted using JSON
+func isMapOrStruct(t reflect.Type) bool {
+	return (t.Kind() == reflect.Map && t.Key().Kind() == reflect.String) || t.Kind() == reflect.Struct
+}
+
+// modelFieldName returns the name of the field as seen by the serializers, or false if the field
+// is embedded or excluded using the field name tag
+func modelFieldName(field reflect.StructField) (string, bool) {
+	if field.Anonymous {
+		return "", false
+	}
+	return models.FieldName(field)
+}
+
 type fieldSettings struct {
 	itsType                   reflect.Type
 	isEncodingTextMarshaler   bool
@@ -61,14 +77,23 @@ type fieldSettings struct {
 	isDataTypesJSON    bool
 
 	isSqlNullInt32 bool
+
+	isSQLScanner   bool
+	isDriverValuer bool
+
+	isJSONMarshaler   bool
+	isJSONUnmarshaler bool
+
+	omitEmpty bool
+
+	isMapOrStruct bool
 }
 
 func getFieldSettings[Model any](fieldName string) *fieldSettings {
 	var entity Model
 	var settings *fieldSettings
 	for _, field := range reflect.VisibleFields(reflect.TypeOf(entity)) {
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == fieldName {
+		if name, included := modelFieldName(field); included && name == fieldName {
 			var theTypeAsAny any
 			reflectedInstance := reflect.New(reflect.TypeOf(reflect.ValueOf(entity).FieldByName(field.Name).Interface())).Elem()
 
@@ -87,6 +112,10 @@ func getFieldSettings[Model any](fieldName string) *fieldSettings {
 			_, isGRFParsable := theTypeAsAny.(fields.GRFParsable)
 			_, isSQLNull32 := theTypeAsAny.(*sql.NullInt32)
 			_, isDataTypesJSON := theTypeAsAny.(*datatypes.JSON)
+			_, isSQLScanner := theTypeAsAny.(sql.Scanner)
+			_, isDriverValuer := theTypeAsAny.(driver.Valuer)
+			_, isJSONMarshaler := theTypeAsAny.(json.Marshaler)
+			_, isJSONUnmarshaler := theTypeAsAny.(json.Unmarshaler)
 
 			settings = &fieldSettings{
 				itsType: reflect.TypeOf(
@@ -99,6 +128,12 @@ func getFieldSettings[Model any](fieldName string) *fieldSettings {
 				isRelation:                fieldMarkedAsRelation,
 				isDataTypesJSON:           isDataTypesJSON,
 				isSqlNullInt32:            isSQLNull32,
+				isSQLScanner:              isSQLScanner,
+				isDriverValuer:            isDriverValuer,
+				isJSONMarshaler:           isJSONMarshaler,
+				isJSONUnmarshaler:         isJSONUnmarshaler,
+				omitEmpty:                 models.FieldOmitsEmpty(field),
+				isMapOrStruct:             isMapOrStruct(field.Type),
 			}
 		}
 	}
@@ -1,10 +1,12 @@
 package detectors
 
 import (
+	"bytes"
 	"database/sql"
 	"encoding"
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"time"
 
@@ -110,6 +112,28 @@ func (p *encodingTextUnmarshalerToInternalValueDetector[Model]) ToInternalValue(
 	return nil, fmt.Errorf("Field `%s` is not a encoding.TextUnmarshaler", fieldName)
 }
 
+type jsonUnmarshalerToInternalValueDetector[Model any] struct{}
+
+func (p *jsonUnmarshalerToInternalValueDetector[Model]) ToInternalValue(fieldName string) (fields.InternalValueFunc, error) {
+	fieldSettings := getFieldSettings[Model](fieldName)
+	if fieldSettings != nil && fieldSettings.isJSONUnmarshaler {
+		return ConvertFuncToInternalValueFuncAdapter(
+			func(v any) (any, error) {
+				rawJSON, marshalErr := json.Marshal(v)
+				if marshalErr != nil {
+					return nil, fmt.Errorf("Field `%s` could not be encoded as JSON: %w", fieldName, marshalErr)
+				}
+				unmarshaler := reflect.New(fieldSettings.itsType).Interface().(json.Unmarshaler)
+				if unmarshalErr := unmarshaler.UnmarshalJSON(rawJSON); unmarshalErr != nil {
+					return nil, fmt.Errorf("Field `%s` could not be parsed: %w", fieldName, unmarshalErr)
+				}
+				return reflect.ValueOf(unmarshaler).Elem().Interface(), nil
+			},
+		), nil
+	}
+	return nil, fmt.Errorf("Field `%s` is not a json.Unmarshaler", fieldName)
+}
+
 type gormDataTypesJSONToInternalValueDetector[Model any] struct{}
 
 func (p *gormDataTypesJSONToInternalValueDetector[Model]) ToInternalValue(fieldName string) (fields.InternalValueFunc, error) {
@@ -143,8 +167,7 @@ func (p *usingSqlNullFieldToInternalValueDetector[Model, sqlNullType]) ToInterna
 	isNullSqlType := false
 	var entity Model
 	for _, field := range reflect.VisibleFields(reflect.TypeOf(entity)) {
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == fieldName {
+		if name, included := modelFieldName(field); included && name == fieldName {
 			var theTypeAsAny any
 			reflectedInstance := reflect.New(reflect.TypeOf(reflect.ValueOf(entity).FieldByName(field.Name).Interface())).Elem()
 
@@ -173,6 +196,65 @@ func (p *usingSqlNullFieldToInternalValueDetector[Model, sqlNullType]) ToInterna
 	return nil, fmt.Errorf("Field `%s` is not a sql.NullInt32", fieldName)
 }
 
+// sqlScannerToInternalValueDetector uses sql.Scanner as a last resort for types, that do not
+// implement any of the more specific interfaces
+type sqlScannerToInternalValueDetector[Model any] struct{}
+
+func (p *sqlScannerToInternalValueDetector[Model]) ToInternalValue(fieldName string) (fields.InternalValueFunc, error) {
+	fieldSettings := getFieldSettings[Model](fieldName)
+	if fieldSettings != nil && fieldSettings.isSQLScanner {
+		return ConvertFuncToInternalValueFuncAdapter(
+			func(v any) (any, error) {
+				scanner := reflect.New(fieldSettings.itsType).Interface().(sql.Scanner)
+				scanErr := scanner.Scan(v)
+				if vAsFloat, isFloat := v.(float64); scanErr != nil && isFloat && vAsFloat == math.Trunc(vAsFloat) {
+					// JSON numbers are always float64, while scanners usually expect int64 for integers
+					scanErr = scanner.Scan(int64(vAsFloat))
+				}
+				if scanErr != nil {
+					return nil, fmt.Errorf("Field `%s` could not be parsed: %w", fieldName, scanErr)
+				}
+				return reflect.ValueOf(scanner).Elem().Interface(), nil
+			},
+		), nil
+	}
+	return nil, fmt.Errorf("Field `%s` is not a sql.Scanner", fieldName)
+}
+
+// mapOrStructToInternalValueDetector converts JSON objects to map[string]T and struct fields. Unknown
+// struct fields are rejected and fields implementing GRFValidatable are validated after parsing.
+type mapOrStructToInternalValueDetector[Model any] struct{}
+
+func (p *mapOrStructToInternalValueDetector[Model]) ToInternalValue(fieldName string) (fields.InternalValueFunc, error) {
+	fieldSettings := getFieldSettings[Model](fieldName)
+	if fieldSettings != nil && fieldSettings.isMapOrStruct {
+		return ConvertFuncToInternalValueFuncAdapter(
+			func(v any) (any, error) {
+				if _, isObject := v.(map[string]any); !isObject {
+					return nil, fmt.Errorf("Field `%s` is not an object", fieldName)
+				}
+				rawJSON, marshalErr := json.Marshal(v)
+				if marshalErr != nil {
+					return nil, fmt.Errorf("Field `%s` could not be encoded as JSON: %w", fieldName, marshalErr)
+				}
+				typedValue := reflect.New(fieldSettings.itsType)
+				decoder := json.NewDecoder(bytes.NewReader(rawJSON))
+				decoder.DisallowUnknownFields()
+				if decodeErr := decoder.Decode(typedValue.Interface()); decodeErr != nil {
+					return nil, fmt.Errorf("Field `%s` could not be parsed: %w", fieldName, decodeErr)
+				}
+				if validatable, ok := typedValue.Interface().(fields.GRFValidatable); ok {
+					if validateErr := validatable.Validate(); validateErr != nil {
+						return nil, validateErr
+					}
+				}
+				return typedValue.Elem().Interface(), nil
+			},
+		), nil
+	}
+	return nil, fmt.Errorf("Field `%s` is not a map or a struct", fieldName)
+}
+
 type chainingToInternalValueDetector[Model any] struct {
 	children []ToInternalValueDetector
 }
@@ -201,6 +283,7 @@ func DefaultToInternalValueDetector[Model any]() ToInternalValueDetector {
 						modelTypeNames: FieldTypes[Model](),
 					},
 					&encodingTextUnmarshalerToInternalValueDetector[Model]{},
+					&jsonUnmarshalerToInternalValueDetector[Model]{},
 					&usingSqlNullFieldToInternalValueDetector[Model, sql.NullBool]{
 						valueFunc: func(v any) (any, error) {
 							vAsBool, ok := v.(bool)
@@ -288,6 +371,8 @@ func DefaultToInternalValueDetector[Model any]() ToInternalValueDetector {
 							}, nil
 						},
 					},
+					&sqlScannerToInternalValueDetector[Model]{},
+					&mapOrStructToInternalValueDetector[Model]{},
 				},
 			},
 		},
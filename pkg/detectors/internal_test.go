@@ -2,8 +2,13 @@ package detectors
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"testing"
 
+	"github.com/glothriel/grf/pkg/types"
+
 	"github.com/stretchr/testify/assert"
 	"gorm.io/datatypes"
 )
@@ -284,3 +289,130 @@ func TestToInternalValueDataTypesJSONNotJSONSerializable(t *testing.T) {
 	assert.NoError(t, internalValueFuncErr)
 	assert.Error(t, ivErr)
 }
+
+type registeredEnum string
+
+func TestToInternalValue_RegisteredType(t *testing.T) {
+	type enumModel struct {
+		Data registeredEnum `json:"data"`
+	}
+	types.RegisterType[registeredEnum](
+		func(v any) (any, error) {
+			vEnum, ok := v.(registeredEnum)
+			if !ok {
+				return nil, fmt.Errorf("`%v` is not a status", v)
+			}
+			return string(vEnum), nil
+		},
+		func(v any) (any, error) {
+			vStr, ok := v.(string)
+			if !ok || (vStr != "open" && vStr != "closed") {
+				return nil, fmt.Errorf("`%v` is not a valid status", v)
+			}
+			return registeredEnum(vStr), nil
+		},
+	)
+
+	testSqlNullModelsToInternalValue[enumModel](t, "open", registeredEnum("open"))
+	testSqlNullModelsToRepresentation[enumModel](t, registeredEnum("closed"), "closed")
+}
+
+type scannableCents struct {
+	cents int64
+}
+
+func (c *scannableCents) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		c.cents = v
+		return nil
+	case string:
+		var parsed int64
+		if _, err := fmt.Sscanf(v, "%d", &parsed); err != nil {
+			return err
+		}
+		c.cents = parsed
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into scannableCents", src)
+}
+
+func (c *scannableCents) Value() (driver.Value, error) {
+	return c.cents, nil
+}
+
+func TestToInternalValue_SQLScanner(t *testing.T) {
+	type centsModel struct {
+		Data scannableCents `json:"data"`
+	}
+
+	testSqlNullModelsToInternalValue[centsModel](t, float64(1250), scannableCents{cents: 1250})
+	testSqlNullModelsToInternalValue[centsModel](t, "99", scannableCents{cents: 99})
+	testSqlNullModelsToRepresentation[centsModel](t, scannableCents{cents: 1250}, int64(1250))
+}
+
+type jsonPoint struct {
+	x, y float64
+}
+
+func (p jsonPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]float64{p.x, p.y})
+}
+
+func (p *jsonPoint) UnmarshalJSON(data []byte) error {
+	var coords []float64
+	if err := json.Unmarshal(data, &coords); err != nil {
+		return err
+	}
+	if len(coords) != 2 {
+		return fmt.Errorf("expected two coordinates, got %d", len(coords))
+	}
+	p.x, p.y = coords[0], coords[1]
+	return nil
+}
+
+func TestToInternalValue_JSONUnmarshaler(t *testing.T) {
+	type pointModel struct {
+		Data jsonPoint `json:"data"`
+	}
+
+	testSqlNullModelsToInternalValue[pointModel](t, []any{1.5, 2.0}, jsonPoint{x: 1.5, y: 2.0})
+	testSqlNullModelsToRepresentation[pointModel](t, jsonPoint{x: 1.5, y: 2.0}, []any{1.5, 2.0})
+}
+
+type validatedRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+func (r *validatedRange) Validate() error {
+	if r.From > r.To {
+		return fmt.Errorf("`from` must not be greater than `to`")
+	}
+	return nil
+}
+
+func TestToInternalValue_MapAndStruct(t *testing.T) {
+	type mapModel struct {
+		Data map[string]int `json:"data"`
+	}
+	type structModel struct {
+		Data validatedRange `json:"data"`
+	}
+
+	testSqlNullModelsToInternalValue[mapModel](t, map[string]any{"a": float64(1)}, map[string]int{"a": 1})
+	testSqlNullModelsToRepresentation[mapModel](t, map[string]int{"a": 1}, map[string]any{"a": float64(1)})
+	testSqlNullModelsToInternalValue[structModel](
+		t, map[string]any{"from": float64(1), "to": float64(2)}, validatedRange{From: 1, To: 2},
+	)
+	testSqlNullModelsToRepresentation[structModel](
+		t, validatedRange{From: 1, To: 2}, map[string]any{"from": float64(1), "to": float64(2)},
+	)
+
+	// when
+	internalValue, _ := DefaultToInternalValueDetector[structModel]().ToInternalValue("data")
+	_, invalidErr := internalValue(map[string]any{"data": map[string]any{"from": float64(3), "to": float64(2)}}, "data", nil)
+
+	// then
+	assert.ErrorContains(t, invalidErr, "must not be greater")
+}
@@ -2,6 +2,7 @@ package detectors
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"encoding"
 	"encoding/json"
 	"fmt"
@@ -34,6 +35,7 @@ func DefaultToRepresentationDetector[Model any]() ToRepresentationDetector[Model
 						modelTypeNames: FieldTypes[Model](),
 					},
 					&encodingTextMarshalerToRepresentationProvider[Model]{},
+					&jsonMarshalerToRepresentationProvider[Model]{},
 					&usingSqlNullFieldToRepresentationProvider[Model, sql.NullBool]{
 						valueFunc: func(v sql.NullBool) any {
 							if v.Valid {
@@ -90,6 +92,8 @@ func DefaultToRepresentationDetector[Model any]() ToRepresentationDetector[Model
 							return nil
 						},
 					},
+					&driverValuerToRepresentationProvider[Model]{},
+					&mapOrStructToRepresentationProvider[Model]{},
 				},
 			},
 		},
@@ -105,14 +109,36 @@ func (p missingFieldSkippingToRepresentationDetector[Model]) ToRepresentation(fi
 	if childErr != nil {
 		return nil, childErr
 	}
+	fieldSettings := getFieldSettings[Model](fieldName)
+	omitEmpty := fieldSettings != nil && fieldSettings.omitEmpty
 	return func(m models.InternalValue, s string, c *gin.Context) (any, error) {
-		if _, ok := m[s]; !ok {
+		v, ok := m[s]
+		if !ok || (omitEmpty && isEmptyValue(v)) {
 			return nil, fields.NewErrorFieldIsNotPresentInPayload(s)
 		}
 		return childDetector(m, s, c)
 	}, nil
 }
 
+// isEmptyValue follows the encoding/json definition of empty values used by `omitempty`
+func isEmptyValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	reflected := reflect.ValueOf(v)
+	switch reflected.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return reflected.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return reflected.IsZero()
+	}
+	return false
+}
+
 type usingGRFRepresentableToRepresentationProvider[Model any] struct{}
 
 func (p usingGRFRepresentableToRepresentationProvider[Model]) ToRepresentation(fieldName string) (fields.RepresentationFunc, error) {
@@ -154,8 +180,7 @@ func (p usingSqlNullFieldToRepresentationProvider[Model, sqlNullType]) ToReprese
 	isNullSqlType := false
 	var entity Model
 	for _, field := range reflect.VisibleFields(reflect.TypeOf(entity)) {
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == fieldName {
+		if name, included := modelFieldName(field); included && name == fieldName {
 			var theTypeAsAny any
 			reflectedInstance := reflect.New(reflect.TypeOf(reflect.ValueOf(entity).FieldByName(field.Name).Interface())).Elem()
 			if reflectedInstance.CanAddr() {
@@ -245,6 +270,96 @@ func (p gormDataTypesJSONToRepresentationProvider[Model]) ToRepresentation(field
 	return nil, fmt.Errorf("Field `%s` is not a encoding.TextMarshaler", fieldName)
 }
 
+// driverValuerToRepresentationProvider uses driver.Valuer as a last resort for types, that do not
+// implement any of the more specific interfaces
+type driverValuerToRepresentationProvider[Model any] struct{}
+
+func (p driverValuerToRepresentationProvider[Model]) ToRepresentation(fieldName string) (fields.RepresentationFunc, error) {
+	fieldSettings := getFieldSettings[Model](fieldName)
+	if fieldSettings != nil && fieldSettings.isDriverValuer {
+		return ConvertFuncToRepresentationFuncAdapter(
+			func(v any) (any, error) {
+				valuer, ok := withPointerReceivers(v, fieldSettings.itsType).(driver.Valuer)
+				if !ok {
+					return nil, fmt.Errorf("Field `%s` is not a driver.Valuer", fieldName)
+				}
+				value, valueErr := valuer.Value()
+				if valueErr != nil {
+					return nil, valueErr
+				}
+				if valueAsBytes, isBytes := value.([]byte); isBytes {
+					return string(valueAsBytes), nil
+				}
+				return value, nil
+			},
+		), nil
+	}
+	return nil, fmt.Errorf("Field `%s` is not a driver.Valuer", fieldName)
+}
+
+type jsonMarshalerToRepresentationProvider[Model any] struct{}
+
+func (p jsonMarshalerToRepresentationProvider[Model]) ToRepresentation(fieldName string) (fields.RepresentationFunc, error) {
+	fieldSettings := getFieldSettings[Model](fieldName)
+	if fieldSettings != nil && fieldSettings.isJSONMarshaler {
+		return ConvertFuncToRepresentationFuncAdapter(
+			func(v any) (any, error) {
+				marshaler, ok := withPointerReceivers(v, fieldSettings.itsType).(json.Marshaler)
+				if !ok {
+					return nil, fmt.Errorf("Field `%s` is not a json.Marshaler", fieldName)
+				}
+				rawJSON, marshalErr := marshaler.MarshalJSON()
+				if marshalErr != nil {
+					return nil, marshalErr
+				}
+				var ret any
+				if unmarshalErr := json.Unmarshal(rawJSON, &ret); unmarshalErr != nil {
+					return nil, fmt.Errorf("Failed to unmarshal field `%s` from JSON: %w", fieldName, unmarshalErr)
+				}
+				return ret, nil
+			},
+		), nil
+	}
+	return nil, fmt.Errorf("Field `%s` is not a json.Marshaler", fieldName)
+}
+
+// mapOrStructToRepresentationProvider converts map[string]T and struct fields to their JSON representation
+type mapOrStructToRepresentationProvider[Model any] struct{}
+
+func (p mapOrStructToRepresentationProvider[Model]) ToRepresentation(fieldName string) (fields.RepresentationFunc, error) {
+	fieldSettings := getFieldSettings[Model](fieldName)
+	if fieldSettings != nil && fieldSettings.isMapOrStruct {
+		return ConvertFuncToRepresentationFuncAdapter(
+			func(v any) (any, error) {
+				if v == nil || reflect.TypeOf(v) != fieldSettings.itsType {
+					return nil, fmt.Errorf("Field `%s` is not a %s", fieldName, fieldSettings.itsType)
+				}
+				rawJSON, marshalErr := json.Marshal(v)
+				if marshalErr != nil {
+					return nil, marshalErr
+				}
+				var ret any
+				if unmarshalErr := json.Unmarshal(rawJSON, &ret); unmarshalErr != nil {
+					return nil, fmt.Errorf("Failed to unmarshal field `%s` from JSON: %w", fieldName, unmarshalErr)
+				}
+				return ret, nil
+			},
+		), nil
+	}
+	return nil, fmt.Errorf("Field `%s` is not a map or a struct", fieldName)
+}
+
+// withPointerReceivers returns a pointer to a copy of v if it's of type itsType, so methods declared
+// on the pointer receiver can be called
+func withPointerReceivers(v any, itsType reflect.Type) any {
+	if v == nil || reflect.TypeOf(v) != itsType {
+		return v
+	}
+	addressable := reflect.New(itsType)
+	addressable.Elem().Set(reflect.ValueOf(v))
+	return addressable.Interface()
+}
+
 type chainingToRepresentationDetector[Model any] struct {
 	children []ToRepresentationDetector[Model]
 }
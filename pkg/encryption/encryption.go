@@ -0,0 +1,136 @@
+// Package encryption encrypts the values of the sensitive fields, like tokens or personal data,
+// before they are stored, see fields.EncryptedField. The keys are identified, so they can be
+// rotated: the values are decrypted using the key they were encrypted with, and re-encrypted with
+// the current key by maintenance.Reencrypt.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks the encrypted values, which look like `enc:<key id>:<base64 of nonce and ciphertext>`
+const prefix = "enc:"
+
+// ErrUnknownKey is returned when decrypting a value encrypted with a key missing from the keyring
+var ErrUnknownKey = errors.New("the value is encrypted with an unknown key")
+
+// Keyring encrypts the values using AES-GCM with the current key, and decrypts them using any of
+// its keys
+type Keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring creates a Keyring encrypting with the key, which has to be 16, 24 or 32 bytes long,
+// selecting AES-128, AES-192 or AES-256. The id is stored with the encrypted values, so it must not
+// be reused for other keys.
+func NewKeyring(id string, key []byte) (*Keyring, error) {
+	k := &Keyring{keys: map[string]cipher.AEAD{}}
+	if addErr := k.add(id, key); addErr != nil {
+		return nil, addErr
+	}
+	k.current = id
+	return k, nil
+}
+
+// WithOldKey adds a key, that is no longer used for encrypting, but still decrypts the values not
+// re-encrypted yet
+func (k *Keyring) WithOldKey(id string, key []byte) (*Keyring, error) {
+	if addErr := k.add(id, key); addErr != nil {
+		return nil, addErr
+	}
+	return k, nil
+}
+
+func (k *Keyring) add(id string, key []byte) error {
+	if id == "" || strings.Contains(id, ":") {
+		return fmt.Errorf("invalid key id `%s`", id)
+	}
+	if _, exists := k.keys[id]; exists {
+		return fmt.Errorf("duplicate key id `%s`", id)
+	}
+	block, blockErr := aes.NewCipher(key)
+	if blockErr != nil {
+		return fmt.Errorf("key `%s`: %w", id, blockErr)
+	}
+	aead, aeadErr := cipher.NewGCM(block)
+	if aeadErr != nil {
+		return fmt.Errorf("key `%s`: %w", id, aeadErr)
+	}
+	k.keys[id] = aead
+	return nil
+}
+
+// CurrentKeyID returns the id of the key used for encrypting
+func (k *Keyring) CurrentKeyID() string {
+	return k.current
+}
+
+// Encrypt encrypts the value with the current key
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	aead := k.keys[k.current]
+	nonce := make([]byte, aead.NonceSize())
+	if _, randErr := rand.Read(nonce); randErr != nil {
+		return "", randErr
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(k.current))
+	return prefix + k.current + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts the value with the key it was encrypted with. Values without the prefix of the
+// encrypted ones are returned as they are, so the fields can be encrypted without migrating the
+// stored plaintext values first.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	id, encoded, encrypted := parse(value)
+	if !encrypted {
+		return value, nil
+	}
+	aead, ok := k.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w `%s`", ErrUnknownKey, id)
+	}
+	sealed, decodeErr := base64.RawStdEncoding.DecodeString(encoded)
+	if decodeErr != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+	plaintext, openErr := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if openErr != nil {
+		return "", fmt.Errorf("could not decrypt the value with key `%s`: %w", id, openErr)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation returns true if the value is not encrypted with the current key, including the
+// plaintext values
+func (k *Keyring) NeedsRotation(value string) bool {
+	id, _, encrypted := parse(value)
+	return !encrypted || id != k.current
+}
+
+// Rotate re-encrypts the value with the current key, if it needs the rotation
+func (k *Keyring) Rotate(value string) (string, bool, error) {
+	if !k.NeedsRotation(value) {
+		return value, false, nil
+	}
+	plaintext, decryptErr := k.Decrypt(value)
+	if decryptErr != nil {
+		return "", false, decryptErr
+	}
+	rotated, encryptErr := k.Encrypt(plaintext)
+	return rotated, encryptErr == nil, encryptErr
+}
+
+func parse(value string) (id, encoded string, encrypted bool) {
+	rest, hasPrefix := strings.CutPrefix(value, prefix)
+	if !hasPrefix {
+		return "", "", false
+	}
+	id, encoded, found := strings.Cut(rest, ":")
+	return id, encoded, found
+}
@@ -0,0 +1,54 @@
+package encryption
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringRotation(t *testing.T) {
+	// given
+	oldKeyring, oldErr := NewKeyring("2023", bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, oldErr)
+	keyring, newErr := NewKeyring("2024", bytes.Repeat([]byte{2}, 32))
+	require.NoError(t, newErr)
+	_, oldKeyErr := keyring.WithOldKey("2023", bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, oldKeyErr)
+	oldValue, encryptErr := oldKeyring.Encrypt("secret")
+	require.NoError(t, encryptErr)
+
+	// when
+	rotated, wasRotated, rotateErr := keyring.Rotate(oldValue)
+	decrypted, decryptErr := keyring.Decrypt(rotated)
+	unchanged, rotatedAgain, _ := keyring.Rotate(rotated)
+	plaintext, plaintextErr := keyring.Decrypt("not encrypted")
+	_, unknownErr := oldKeyring.Decrypt(rotated)
+
+	// then
+	assert.True(t, strings.HasPrefix(oldValue, "enc:2023:"))
+	assert.NoError(t, rotateErr)
+	assert.True(t, wasRotated)
+	assert.True(t, strings.HasPrefix(rotated, "enc:2024:"))
+	assert.NoError(t, decryptErr)
+	assert.Equal(t, "secret", decrypted)
+	assert.False(t, rotatedAgain)
+	assert.Equal(t, rotated, unchanged)
+	assert.NoError(t, plaintextErr)
+	assert.Equal(t, "not encrypted", plaintext)
+	assert.True(t, keyring.NeedsRotation("not encrypted"))
+	assert.ErrorIs(t, unknownErr, ErrUnknownKey)
+}
+
+func TestKeyringInvalidKeys(t *testing.T) {
+	_, shortErr := NewKeyring("short", []byte("too short"))
+	_, idErr := NewKeyring("a:b", bytes.Repeat([]byte{1}, 16))
+	keyring, _ := NewKeyring("2024", bytes.Repeat([]byte{1}, 16))
+	_, duplicateErr := keyring.WithOldKey("2024", bytes.Repeat([]byte{2}, 16))
+
+	assert.Error(t, shortErr)
+	assert.Error(t, idErr)
+	assert.Error(t, duplicateErr)
+}
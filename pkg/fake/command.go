@@ -0,0 +1,71 @@
+package fake
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Seeder creates the fake records of a model, see Generator.Seeder
+type Seeder func(ctx context.Context, count int) error
+
+// Command creates the fake records of the registered models, as the `gen fake` command of the
+// project's binary, see cli.CLI. Only the project knows its models and drivers:
+//
+//	categories := fake.NewGenerator[Category](categorySerializer)
+//	products := fake.NewGenerator[Product](productSerializer).WithRelation("category_id", categories)
+//	command := fake.NewCommand().
+//		WithModel("categories", categories.Seeder(categoriesDriver)).
+//		WithModel("products", products.Seeder(productsDriver))
+type Command struct {
+	names   []string
+	seeders map[string]Seeder
+}
+
+// NewCommand creates a Command without any models
+func NewCommand() *Command {
+	return &Command{seeders: map[string]Seeder{}}
+}
+
+// WithModel registers the seeder of the model. The records are created in the order the models are
+// registered, so the models referenced by the relations have to be registered first.
+func (c *Command) WithModel(name string, seeder Seeder) *Command {
+	if _, exists := c.seeders[name]; !exists {
+		c.names = append(c.names, name)
+	}
+	c.seeders[name] = seeder
+	return c
+}
+
+// Run creates `-count` records of the models named by the arguments, or of all the models, like
+// `-count 100 categories products`. It returns the exit status: 0 on success, 1 if the records
+// couldn't be created and 2 if the arguments are invalid.
+func (c *Command) Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gen fake", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	count := flags.Int("count", 10, "the number of the records created for every model")
+	if parseErr := flags.Parse(args); parseErr != nil {
+		return 2
+	}
+	selected := flags.Args()
+	for _, name := range selected {
+		if _, ok := c.seeders[name]; !ok {
+			fmt.Fprintf(stderr, "unknown model `%s`, expected one of: %s\n", name, strings.Join(c.names, ", "))
+			return 2
+		}
+	}
+	for _, name := range c.names {
+		if len(selected) > 0 && !slices.Contains(selected, name) {
+			continue
+		}
+		if seedErr := c.seeders[name](ctx, *count); seedErr != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", name, seedErr)
+			return 1
+		}
+		fmt.Fprintf(stdout, "%s: created %d records\n", name, *count)
+	}
+	return 0
+}
@@ -0,0 +1,338 @@
+// Package fake generates realistic records for the models, for example to fill the database for
+// demos, load tests or the development of the user interfaces. The values are generated using the
+// descriptions of the serializer's fields, so they respect the types, the choices and the
+// go-playground validators of the fields, and are converted to internal values by the serializer,
+// like the payloads of the requests. The records of the registered models are created by the
+// `gen fake` command of the project's binary, see Command.
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/internal/fakenames"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/crud"
+	"github.com/glothriel/grf/pkg/scheduler"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/sirupsen/logrus"
+)
+
+// ValueFunc generates the value of a field, the sequence is the number of the record, starting at 1
+type ValueFunc func(random *rand.Rand, sequence int) any
+
+// Generator generates the records of the model
+type Generator[Model any] struct {
+	serializer serializers.Serializer
+	fields     map[string]*serializers.FieldMetadata
+	values     map[string]ValueFunc
+	relations  map[string]Related
+	random     *rand.Rand
+	sequence   int
+	createdIDs []any
+}
+
+// Related is implemented by the generators, so the records of other models can reference the
+// records they created
+type Related interface {
+	// CreatedIDs returns the IDs of the created records, like in the payloads of the requests
+	CreatedIDs() []any
+}
+
+// NewGenerator creates a Generator for the serializer, the serializer has to implement
+// serializers.Describer, like the model and validating serializers
+func NewGenerator[Model any](serializer serializers.Serializer) *Generator[Model] {
+	describer, ok := serializer.(serializers.Describer)
+	if !ok {
+		var m Model
+		logrus.Panicf("Serializer of model `%s` can't describe its fields", reflect.TypeOf(m))
+	}
+	return &Generator[Model]{
+		serializer: serializer,
+		fields:     describer.Describe(),
+		values:     map[string]ValueFunc{},
+		relations:  map[string]Related{},
+		random:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// WithSeed makes the generated records repeatable
+func (g *Generator[Model]) WithSeed(seed int64) *Generator[Model] {
+	g.random = rand.New(rand.NewSource(seed))
+	return g
+}
+
+// WithField generates the values of the field using the function, the field is the external name,
+// like in the payloads of the requests
+func (g *Generator[Model]) WithField(field string, valueFunc ValueFunc) *Generator[Model] {
+	if _, ok := g.fields[field]; !ok {
+		var m Model
+		logrus.Panicf("Could not find field `%s` on serializer of model `%s`", field, reflect.TypeOf(m))
+	}
+	g.values[field] = valueFunc
+	return g
+}
+
+// WithValues picks the values of the field from the values, for example from the IDs of the
+// related records
+func (g *Generator[Model]) WithValues(field string, values ...any) *Generator[Model] {
+	return g.WithField(field, func(random *rand.Rand, _ int) any {
+		return values[random.Intn(len(values))]
+	})
+}
+
+// WithRelation picks the values of the field from the IDs of the records created by the related
+// generator, like the `category_id` of the products from the created categories. The related
+// records have to be created first, Create fails otherwise.
+func (g *Generator[Model]) WithRelation(field string, related Related) *Generator[Model] {
+	g.relations[field] = related
+	return g.WithField(field, func(random *rand.Rand, _ int) any {
+		ids := related.CreatedIDs()
+		if len(ids) == 0 {
+			return nil
+		}
+		return ids[random.Intn(len(ids))]
+	})
+}
+
+// CreatedIDs returns the IDs of the records created by Create, converted like the values of the
+// JSON payloads, so they can be used by the related generators
+func (g *Generator[Model]) CreatedIDs() []any {
+	return g.createdIDs
+}
+
+// Generate returns the payload of the next record, like decoded from the JSON body of a request, so
+// the numbers are float64. The read-only fields and the fields of unknown types are omitted, unless
+// they're set using WithField
+func (g *Generator[Model]) Generate() map[string]any {
+	g.sequence++
+	names := make([]string, 0, len(g.fields))
+	for name := range g.fields {
+		names = append(names, name)
+	}
+	// The fields are generated in a stable order, so the seeded generators are repeatable
+	sort.Strings(names)
+	payload := map[string]any{}
+	for _, name := range names {
+		if valueFunc, ok := g.values[name]; ok {
+			payload[name] = valueFunc(g.random, g.sequence)
+			continue
+		}
+		field := g.fields[name]
+		if field.ReadOnly {
+			continue
+		}
+		if value, ok := g.value(name, field); ok {
+			payload[name] = value
+		}
+	}
+	return payload
+}
+
+// Value generates the value of the field, also if it's read-only, the field is the external name.
+// The second value is false for the unknown fields and the fields of unknown types.
+func (g *Generator[Model]) Value(field string) (any, bool) {
+	if valueFunc, ok := g.values[field]; ok {
+		return valueFunc(g.random, g.sequence), true
+	}
+	metadata, ok := g.fields[field]
+	if !ok {
+		return nil, false
+	}
+	return g.value(field, metadata)
+}
+
+// Create generates the records and creates them using the queries, for example the CRUD of the
+// query driver used by the viewset. Use scheduler.WithDriver to prepare the context outside of the
+// requests.
+func (g *Generator[Model]) Create(ctx *gin.Context, queries *crud.CRUD[Model], count int) ([]models.InternalValue, error) {
+	for field, related := range g.relations {
+		if len(related.CreatedIDs()) == 0 {
+			return nil, fmt.Errorf("no related records were created for field `%s`, create them first", field)
+		}
+	}
+	created := make([]models.InternalValue, 0, count)
+	for i := 0; i < count; i++ {
+		internalValue, convertErr := g.serializer.ToInternalValue(g.Generate(), ctx)
+		if convertErr != nil {
+			return created, convertErr
+		}
+		record, createErr := queries.Create(ctx, internalValue)
+		if createErr != nil {
+			return created, createErr
+		}
+		created = append(created, record)
+		g.createdIDs = append(g.createdIDs, payloadValue(record["id"]))
+	}
+	return created, nil
+}
+
+// Seeder returns a Seeder creating the records using the queries of the driver, with the context
+// prepared like the ones of the requests, see scheduler.WithDriver
+func (g *Generator[Model]) Seeder(driver queries.Driver[Model]) Seeder {
+	return func(ctx context.Context, count int) error {
+		return scheduler.WithDriver(driver, func(ginCtx *gin.Context, q *crud.CRUD[Model]) error {
+			_, createErr := g.Create(ginCtx, q, count)
+			return createErr
+		})(ctx)
+	}
+}
+
+// payloadValue converts the value like it was decoded from a JSON payload, so the numbers are float64
+func payloadValue(value any) any {
+	encoded, marshalErr := json.Marshal(value)
+	if marshalErr != nil {
+		return value
+	}
+	var decoded any
+	if unmarshalErr := json.Unmarshal(encoded, &decoded); unmarshalErr != nil {
+		return value
+	}
+	return decoded
+}
+
+func (g *Generator[Model]) value(name string, field *serializers.FieldMetadata) (any, bool) {
+	if len(field.Choices) > 0 {
+		choice := field.Choices[g.random.Intn(len(field.Choices))]
+		if integer, ok := choice.(int64); ok {
+			return float64(integer), true
+		}
+		return choice, true
+	}
+	rules := parseRules(field.Validators)
+	switch field.Type {
+	case "boolean":
+		return g.random.Intn(2) == 1, true
+	case "integer":
+		low, high := rules.bounds(0, 1000)
+		return float64(int64(low) + g.random.Int63n(int64(high-low)+1)), true
+	case "number":
+		low, high := rules.bounds(0, 1000)
+		return float64(int((low+g.random.Float64()*(high-low))*100)) / 100, true
+	case "datetime":
+		return time.Now().UTC().Add(-time.Duration(g.random.Int63n(int64(365 * 24 * time.Hour)))).
+			Truncate(time.Second).Format(time.RFC3339), true
+	case "string":
+		return g.text(name, rules), true
+	}
+	return nil, false
+}
+
+var (
+	cities = []string{
+		"Amsterdam", "Berlin", "Boston", "Dublin", "Krakow", "Lisbon", "Madrid", "Oslo", "Toronto", "Vienna",
+	}
+	words = strings.Fields(
+		"lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor incididunt ut " +
+			"labore et dolore magna aliqua enim ad minim veniam quis nostrud exercitation ullamco laboris",
+	)
+)
+
+// text generates the strings using the formats required by the validators, or guessed from the
+// names of the fields
+func (g *Generator[Model]) text(name string, rules rules) string {
+	first := fakenames.First[g.random.Intn(len(fakenames.First))]
+	last := fakenames.Last[g.random.Intn(len(fakenames.Last))]
+	lowerName := strings.ToLower(name)
+	var text string
+	switch {
+	case rules.has("email") || strings.Contains(lowerName, "email"):
+		// The sequence keeps the addresses unique
+		text = fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(first), strings.ToLower(last), g.sequence)
+	case rules.has("url") || rules.has("uri") || strings.Contains(lowerName, "url") ||
+		strings.Contains(lowerName, "website"):
+		text = fmt.Sprintf("https://example.com/%s-%d", strings.ToLower(last), g.sequence)
+	case rules.has("uuid") || rules.has("uuid4"):
+		text = fmt.Sprintf(
+			"%08x-%04x-4%03x-%04x-%012x", g.random.Uint32(), g.random.Intn(1<<16), g.random.Intn(1<<12),
+			0x8000|g.random.Intn(1<<14), g.random.Int63n(1<<48),
+		)
+	case strings.Contains(lowerName, "first"):
+		text = first
+	case strings.Contains(lowerName, "last") || strings.Contains(lowerName, "surname"):
+		text = last
+	case strings.Contains(lowerName, "name"):
+		text = first + " " + last
+	case strings.Contains(lowerName, "phone"):
+		text = fmt.Sprintf("+1 555 %03d %04d", g.random.Intn(1000), g.random.Intn(10000))
+	case strings.Contains(lowerName, "city"):
+		text = cities[g.random.Intn(len(cities))]
+	default:
+		sentence := make([]string, 3+g.random.Intn(6))
+		for i := range sentence {
+			sentence[i] = words[g.random.Intn(len(words))]
+		}
+		text = strings.ToUpper(sentence[0][:1]) + strings.Join(sentence, " ")[1:]
+	}
+	low, high := rules.bounds(0, -1)
+	for len([]rune(text)) < int(low) {
+		text += " " + words[g.random.Intn(len(words))]
+	}
+	if runes := []rune(text); high >= 0 && len(runes) > int(high) {
+		text = string(runes[:int(high)])
+	}
+	return text
+}
+
+// rules are the parsed go-playground validators of a field, like `min=3` or `email`
+type rules map[string]string
+
+func parseRules(validators []string) rules {
+	parsed := rules{}
+	for _, validator := range validators {
+		name, param, _ := strings.Cut(validator, "=")
+		parsed[name] = param
+	}
+	return parsed
+}
+
+func (r rules) has(name string) bool {
+	_, ok := r[name]
+	return ok
+}
+
+// bounds returns the range of the values, or the lengths of the strings, allowed by the validators,
+// the defaults are used for the missing bounds
+func (r rules) bounds(low, high float64) (float64, float64) {
+	if length, ok := r.number("len"); ok {
+		return length, length
+	}
+	for _, name := range []string{"min", "gte"} {
+		if bound, ok := r.number(name); ok {
+			low = bound
+		}
+	}
+	if bound, ok := r.number("gt"); ok {
+		low = bound + 1
+	}
+	for _, name := range []string{"max", "lte"} {
+		if bound, ok := r.number(name); ok {
+			high = bound
+		}
+	}
+	if bound, ok := r.number("lt"); ok {
+		high = bound - 1
+	}
+	if high >= 0 && high < low {
+		high = low + 1000
+	}
+	return low, high
+}
+
+func (r rules) number(name string) (float64, bool) {
+	param, ok := r[name]
+	if !ok {
+		return 0, false
+	}
+	parsed, parseErr := strconv.ParseFloat(param, 64)
+	return parsed, parseErr == nil
+}
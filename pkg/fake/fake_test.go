@@ -0,0 +1,150 @@
+package fake
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCustomer struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Age      int       `json:"age"`
+	Plan     string    `json:"plan"`
+	Code     string    `json:"code"`
+	Active   bool      `json:"active"`
+	Joined   time.Time `json:"joined"`
+	Referrer string    `json:"referrer"`
+}
+
+func customerSerializer() serializers.Serializer {
+	return serializers.NewValidatingSerializer[mockCustomer](
+		serializers.NewModelSerializer[mockCustomer](),
+		serializers.NewGoPlaygroundValidator[mockCustomer](map[string]any{
+			"email": "required,email",
+			"age":   "required,gte=18,lte=30",
+			"plan":  "required,oneof=free pro",
+			"code":  "required,len=6",
+		}),
+	)
+}
+
+func TestGeneratorGenerate(t *testing.T) {
+	// given
+	generator := NewGenerator[mockCustomer](customerSerializer()).WithSeed(42).
+		WithValues("referrer", "1", "2")
+
+	for i := 0; i < 50; i++ {
+		// when
+		payload := generator.Generate()
+
+		// then
+		assert.NotContains(t, payload, "id")
+		assert.Contains(t, payload["email"], "@example.com")
+		assert.Contains(t, []any{"free", "pro"}, payload["plan"])
+		assert.Contains(t, []any{"1", "2"}, payload["referrer"])
+		assert.Len(t, payload["code"], 6)
+		assert.Len(t, strings.Fields(payload["name"].(string)), 2)
+		age := payload["age"].(float64)
+		assert.True(t, age >= 18 && age <= 30, "age %v out of range", age)
+		_, parseErr := time.Parse(time.RFC3339, payload["joined"].(string))
+		assert.NoError(t, parseErr)
+	}
+}
+
+func TestGeneratorGenerateIsRepeatableWithSeed(t *testing.T) {
+	// given
+	first := NewGenerator[mockCustomer](customerSerializer()).WithSeed(7)
+	second := NewGenerator[mockCustomer](customerSerializer()).WithSeed(7)
+
+	// when
+	firstPayload, secondPayload := first.Generate(), second.Generate()
+
+	// then
+	delete(firstPayload, "joined")
+	delete(secondPayload, "joined")
+	assert.Equal(t, firstPayload, secondPayload)
+}
+
+func TestGeneratorCreate(t *testing.T) {
+	// given
+	driver := queries.InMemory[mockCustomer]()
+	generator := NewGenerator[mockCustomer](customerSerializer()).
+		WithField("referrer", func(_ *rand.Rand, sequence int) any {
+			return "customer-" + string(rune('0'+sequence))
+		})
+	ctx := &gin.Context{Request: &http.Request{Method: http.MethodGet}}
+
+	// when
+	created, createErr := generator.Create(ctx, driver.CRUD(), 3)
+
+	// then
+	assert.NoError(t, createErr)
+	assert.Len(t, created, 3)
+	listed, listErr := driver.CRUD().List(ctx)
+	assert.NoError(t, listErr)
+	assert.Len(t, listed, 3)
+	assert.Equal(t, "customer-3", created[2]["referrer"])
+}
+
+func TestGeneratorWithUnknownFieldPanics(t *testing.T) {
+	assert.Panics(t, func() {
+		NewGenerator[mockCustomer](customerSerializer()).WithValues("nickname", "joe")
+	})
+}
+
+type mockCategory struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type mockProduct struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	CategoryID uint   `json:"category_id"`
+}
+
+func TestCommandCreatesRelatedRecords(t *testing.T) {
+	// given
+	categoriesDriver := queries.InMemory[mockCategory]()
+	productsDriver := queries.InMemory[mockProduct]()
+	categories := NewGenerator[mockCategory](serializers.NewModelSerializer[mockCategory]())
+	products := NewGenerator[mockProduct](serializers.NewModelSerializer[mockProduct]()).
+		WithRelation("category_id", categories)
+	command := NewCommand().
+		WithModel("categories", categories.Seeder(categoriesDriver)).
+		WithModel("products", products.Seeder(productsDriver))
+	var stdout, stderr strings.Builder
+
+	// when
+	unrelatedStatus := NewCommand().WithModel("products", NewGenerator[mockProduct](
+		serializers.NewModelSerializer[mockProduct](),
+	).WithRelation("category_id", NewGenerator[mockCategory](serializers.NewModelSerializer[mockCategory]())).
+		Seeder(productsDriver)).Run(context.Background(), nil, io.Discard, &stderr)
+	status := command.Run(context.Background(), []string{"-count", "3"}, &stdout, io.Discard)
+	unknownStatus := command.Run(context.Background(), []string{"orders"}, io.Discard, io.Discard)
+
+	// then
+	assert.Equal(t, 1, unrelatedStatus)
+	assert.Equal(t, "products: no related records were created for field `category_id`, create them first\n", stderr.String())
+	assert.Equal(t, 0, status)
+	assert.Equal(t, "categories: created 3 records\nproducts: created 3 records\n", stdout.String())
+	assert.Equal(t, 2, unknownStatus)
+	ctx := &gin.Context{Request: &http.Request{Method: http.MethodGet}}
+	listed, listErr := productsDriver.CRUD().List(ctx)
+	assert.NoError(t, listErr)
+	assert.Len(t, listed, 3)
+	for _, product := range listed {
+		assert.Contains(t, []uint{1, 2, 3}, product["category_id"])
+	}
+}
@@ -0,0 +1,72 @@
+package fields
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/types"
+)
+
+// BytesField represents binary data, like thumbnails or signatures, as base64 strings. The model
+// field has to be a []byte, which GORM stores as BLOB or bytea. Both the standard and the URL-safe
+// base64 alphabets are accepted, with or without the padding.
+type BytesField struct {
+	maxSize      int
+	contentTypes []string
+}
+
+// NewBytesField creates a BytesField
+func NewBytesField() *BytesField {
+	return &BytesField{}
+}
+
+// WithMaxSize rejects the values larger than the size in bytes, after decoding
+func (f *BytesField) WithMaxSize(size int) *BytesField {
+	f.maxSize = size
+	return f
+}
+
+// WithContentTypes accepts only the values, which content types detected by
+// http.DetectContentType are one of the content types, like `image/png` or `application/pdf`
+func (f *BytesField) WithContentTypes(contentTypes ...string) *BytesField {
+	f.contentTypes = contentTypes
+	return f
+}
+
+// Field returns a WithField option, that replaces the field's conversions with the ones of the
+// BytesField
+func (f *BytesField) Field() func(oldField Field) {
+	return func(oldField Field) {
+		oldField.WithInternalValueFunc(f.internalValue)
+		oldField.WithRepresentationFunc(func(intVal models.InternalValue, name string, ctx *gin.Context) (any, error) {
+			return types.ConvertBytesToBase64(intVal[name])
+		})
+	}
+}
+
+func (f *BytesField) internalValue(raw map[string]any, name string, ctx *gin.Context) (any, error) {
+	rawValue, ok := raw[name]
+	if !ok {
+		return nil, NewErrorFieldIsNotPresentInPayload(name)
+	}
+	decoded, decodeErr := types.ConvertBase64ToBytes(rawValue)
+	if decodeErr != nil || decoded == nil {
+		return decoded, decodeErr
+	}
+	data := decoded.([]byte)
+	if f.maxSize > 0 && len(data) > f.maxSize {
+		return nil, fmt.Errorf("value can't be larger than %d bytes", f.maxSize)
+	}
+	if len(f.contentTypes) > 0 {
+		contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+		if !slices.Contains(f.contentTypes, contentType) {
+			return nil, fmt.Errorf("content type has to be one of: %s", strings.Join(f.contentTypes, ", "))
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,58 @@
+package fields
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/cache"
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// CachedRepresentationFunc memoizes the results of an expensive RepresentationFunc, for example
+// calling an external API, so list pages don't repeat the same slow work. The results are keyed by
+// the model, the field name, the object ID and the values of the inputs - the internal value keys,
+// that the result depends on. Errors are not cached, neither are the results for objects without ID.
+func CachedRepresentationFunc[Model any](
+	c cache.Cache, ttl time.Duration, f RepresentationFunc, inputs ...string,
+) RepresentationFunc {
+	var m Model
+	modelName := reflect.TypeOf(m).String()
+	return func(intVal models.InternalValue, name string, ctx *gin.Context) (any, error) {
+		id, hasID := intVal["id"]
+		if !hasID || id == nil {
+			return f(intVal, name, ctx)
+		}
+		key := cacheKey(modelName, name, id, intVal, inputs)
+		if cached, ok := c.Get(key); ok {
+			return cached, nil
+		}
+		v, err := f(intVal, name, ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.Set(key, v, ttl)
+		return v, nil
+	}
+}
+
+// Cached returns a WithField option replacing the field's RepresentationFunc with f, memoized
+// using CachedRepresentationFunc
+func Cached[Model any](
+	c cache.Cache, ttl time.Duration, f RepresentationFunc, inputs ...string,
+) func(oldField Field) {
+	return func(oldField Field) {
+		oldField.WithRepresentationFunc(CachedRepresentationFunc[Model](c, ttl, f, inputs...))
+	}
+}
+
+func cacheKey(modelName, fieldName string, id any, intVal models.InternalValue, inputs []string) string {
+	var key strings.Builder
+	fmt.Fprintf(&key, "grf:fields:%s:%s:%v", modelName, fieldName, id)
+	for _, input := range inputs {
+		fmt.Fprintf(&key, ":%s=%#v", input, intVal[input])
+	}
+	return key.String()
+}
@@ -0,0 +1,91 @@
+package fields
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/cache"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingRepresentationFunc struct {
+	calls int
+	err   error
+}
+
+func (c *countingRepresentationFunc) call(intVal models.InternalValue, name string, ctx *gin.Context) (any, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return intVal["name"], nil
+}
+
+func TestCachedRepresentationFuncMemoizesByID(t *testing.T) {
+	// given
+	counter := &countingRepresentationFunc{}
+	f := CachedRepresentationFunc[struct{}](cache.NewInMemory(), time.Minute, counter.call)
+
+	// when
+	first, firstErr := f(models.InternalValue{"id": 1, "name": "foo"}, "computed", nil)
+	second, secondErr := f(models.InternalValue{"id": 1, "name": "bar"}, "computed", nil)
+	other, otherErr := f(models.InternalValue{"id": 2, "name": "baz"}, "computed", nil)
+
+	// then
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	assert.NoError(t, otherErr)
+	assert.Equal(t, "foo", first)
+	assert.Equal(t, "foo", second)
+	assert.Equal(t, "baz", other)
+	assert.Equal(t, 2, counter.calls)
+}
+
+func TestCachedRepresentationFuncKeysByInputs(t *testing.T) {
+	// given
+	counter := &countingRepresentationFunc{}
+	f := CachedRepresentationFunc[struct{}](cache.NewInMemory(), time.Minute, counter.call, "name")
+
+	// when
+	first, _ := f(models.InternalValue{"id": 1, "name": "foo"}, "computed", nil)
+	second, _ := f(models.InternalValue{"id": 1, "name": "bar"}, "computed", nil)
+
+	// then
+	assert.Equal(t, "foo", first)
+	assert.Equal(t, "bar", second)
+	assert.Equal(t, 2, counter.calls)
+}
+
+func TestCachedRepresentationFuncDoesNotCacheErrors(t *testing.T) {
+	// given
+	counter := &countingRepresentationFunc{err: errors.New("external API unavailable")}
+	f := CachedRepresentationFunc[struct{}](cache.NewInMemory(), time.Minute, counter.call)
+
+	// when
+	_, firstErr := f(models.InternalValue{"id": 1}, "computed", nil)
+	_, secondErr := f(models.InternalValue{"id": 1}, "computed", nil)
+
+	// then
+	assert.Error(t, firstErr)
+	assert.Error(t, secondErr)
+	assert.Equal(t, 2, counter.calls)
+}
+
+func TestCachedReplacesRepresentationFunc(t *testing.T) {
+	// given
+	counter := &countingRepresentationFunc{}
+	field := NewField[struct{}]("computed")
+
+	// when
+	Cached[struct{}](cache.NewInMemory(), time.Minute, counter.call)(field)
+	_, _ = field.ToRepresentation(models.InternalValue{"id": 1, "name": "foo"}, nil)
+	v, err := field.ToRepresentation(models.InternalValue{"id": 1, "name": "foo"}, nil)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", v)
+	assert.Equal(t, 1, counter.calls)
+}
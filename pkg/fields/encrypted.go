@@ -0,0 +1,39 @@
+package fields
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/encryption"
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// Encrypted returns a WithField option, that encrypts the values sent by the clients with the
+// current key of the keyring before they're stored, and decrypts the stored values in the
+// representations. The model field has to be a string, long enough for the encrypted values. The
+// keys are rotated using maintenance.Reencrypt.
+func Encrypted(keyring *encryption.Keyring) func(oldField Field) {
+	return func(oldField Field) {
+		oldField.WithInternalValueFunc(func(raw map[string]any, name string, _ *gin.Context) (any, error) {
+			rawValue, ok := raw[name]
+			if !ok {
+				return nil, NewErrorFieldIsNotPresentInPayload(name)
+			}
+			if rawValue == nil {
+				return nil, nil
+			}
+			text, ok := rawValue.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a string, got %T", rawValue)
+			}
+			return keyring.Encrypt(text)
+		})
+		oldField.WithRepresentationFunc(func(intVal models.InternalValue, name string, _ *gin.Context) (any, error) {
+			stored, ok := intVal[name].(string)
+			if !ok {
+				return intVal[name], nil
+			}
+			return keyring.Decrypt(stored)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package fields
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/glothriel/grf/pkg/encryption"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncrypted(t *testing.T) {
+	// given
+	keyring, _ := encryption.NewKeyring("2024", bytes.Repeat([]byte{1}, 32))
+	field := NewField[struct{}]("token")
+	Encrypted(keyring)(field)
+
+	// when
+	stored, storeErr := field.ToInternalValue(map[string]any{"token": "secret"}, nil)
+	represented, reprErr := field.ToRepresentation(models.InternalValue{"token": stored}, nil)
+	_, invalidErr := field.ToInternalValue(map[string]any{"token": 42}, nil)
+
+	// then
+	assert.NoError(t, storeErr)
+	assert.True(t, strings.HasPrefix(stored.(string), "enc:2024:"))
+	assert.NoError(t, reprErr)
+	assert.Equal(t, "secret", represented)
+	assert.EqualError(t, invalidErr, "expected a string, got int")
+}
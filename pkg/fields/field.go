@@ -1,8 +1,12 @@
 package fields
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
 	"github.com/glothriel/grf/pkg/models"
+	"github.com/sirupsen/logrus"
 )
 
 type RepresentationFunc func(models.InternalValue, string, *gin.Context) (any, error)
@@ -20,6 +24,9 @@ func NewErrorFieldIsNotPresentInPayload(name string) ErrorFieldIsNotPresentInPay
 	return ErrorFieldIsNotPresentInPayload{name: name}
 }
 
+// ErrFieldRequired is returned by the required fields missing in the request body
+var ErrFieldRequired = errors.New("This field is required")
+
 type Field interface {
 	Name() string
 	ToRepresentation(models.InternalValue, *gin.Context) (any, error)
@@ -36,10 +43,36 @@ type Field interface {
 	WithInternalValueFunc(InternalValueFunc) Field
 }
 
+// DocumentedField is implemented by the fields documented in the metadata endpoints, like
+// ConcreteField, see Described
+type DocumentedField interface {
+	Description() string
+	Example() any
+	WithDescription(string) Field
+	WithExample(any) Field
+}
+
+// DefaultedField is implemented by the fields filling the keys missing in the request bodies, or
+// rejecting them, like ConcreteField, see Required, DefaultValue and DefaultFunc. The defaults only
+// apply when the objects are created, the required flags to all the actions except the partial
+// updates.
+type DefaultedField interface {
+	IsRequired() bool
+	Default() any
+	WithRequired(bool) Field
+	WithDefault(any) Field
+	WithDefaultFunc(func(*gin.Context) any) Field
+}
+
 type ConcreteField[Model any] struct {
 	name               string
 	representationFunc RepresentationFunc
 	internalValueFunc  InternalValueFunc
+	description        string
+	example            any
+	required           bool
+	defaultValue       any
+	defaultFunc        func(*gin.Context) any
 
 	Readable bool
 	Writable bool
@@ -54,6 +87,16 @@ func (s *ConcreteField[Model]) ToRepresentation(intVal models.InternalValue, ctx
 }
 
 func (s *ConcreteField[Model]) ToInternalValue(reprModel map[string]any, ctx *gin.Context) (any, error) {
+	if _, present := reprModel[s.name]; !present {
+		action := grfctx.CurrentAction(ctx)
+		// The updates keep the stored values instead of the defaults
+		if s.defaultFunc != nil && action != grfctx.ActionUpdate && action != grfctx.ActionPartialUpdate {
+			return s.defaultFunc(ctx), nil
+		}
+		if s.required && action != grfctx.ActionPartialUpdate {
+			return nil, ErrFieldRequired
+		}
+	}
 	return s.internalValueFunc(reprModel, s.name, ctx)
 }
 
@@ -93,6 +136,58 @@ func (s *ConcreteField[Model]) WithInternalValueFunc(f InternalValueFunc) Field
 	return s
 }
 
+func (s *ConcreteField[Model]) Description() string {
+	return s.description
+}
+
+func (s *ConcreteField[Model]) Example() any {
+	return s.example
+}
+
+// WithDescription sets the description of the field, shown in the OPTIONS and form metadata
+func (s *ConcreteField[Model]) WithDescription(description string) Field {
+	s.description = description
+	return s
+}
+
+// WithExample sets an example value of the field, shown in the OPTIONS and form metadata
+func (s *ConcreteField[Model]) WithExample(example any) Field {
+	s.example = example
+	return s
+}
+
+func (s *ConcreteField[Model]) IsRequired() bool {
+	return s.required
+}
+
+// Default returns the value set using WithDefault, the values of WithDefaultFunc are not known
+// until the request
+func (s *ConcreteField[Model]) Default() any {
+	return s.defaultValue
+}
+
+// WithRequired makes ToInternalValue reject the request bodies without the field, instead of
+// leaving the field out of the internal value
+func (s *ConcreteField[Model]) WithRequired(required bool) Field {
+	s.required = required
+	return s
+}
+
+// WithDefault sets the internal value of the field, if it's missing in the request body
+func (s *ConcreteField[Model]) WithDefault(value any) Field {
+	s.defaultValue = value
+	s.defaultFunc = func(*gin.Context) any { return value }
+	return s
+}
+
+// WithDefaultFunc computes the internal value of the field, if it's missing in the request body,
+// for example from the current user or the time of the request
+func (s *ConcreteField[Model]) WithDefaultFunc(defaultFunc func(*gin.Context) any) Field {
+	s.defaultValue = nil
+	s.defaultFunc = defaultFunc
+	return s
+}
+
 func NewField[Model any](name string) Field {
 	return &ConcreteField[Model]{
 		name: name,
@@ -116,3 +211,48 @@ func StaticValue[Model any](v any) func(oldField Field) {
 		)
 	}
 }
+
+// Required returns a WithField option, that makes the field required, see DefaultedField
+func Required() func(oldField Field) {
+	return func(oldField Field) {
+		defaulted(oldField).WithRequired(true)
+	}
+}
+
+// DefaultValue returns a WithField option, that sets the default of the field, see DefaultedField
+func DefaultValue(value any) func(oldField Field) {
+	return func(oldField Field) {
+		defaulted(oldField).WithDefault(value)
+	}
+}
+
+// DefaultFunc returns a WithField option, that computes the default of the field, for example from
+// the current user or the time of the request, see DefaultedField
+func DefaultFunc(defaultFunc func(*gin.Context) any) func(oldField Field) {
+	return func(oldField Field) {
+		defaulted(oldField).WithDefaultFunc(defaultFunc)
+	}
+}
+
+// Described returns a WithField option, that sets the description and the example of the field,
+// nil examples are omitted, see DocumentedField
+func Described(description string, example any) func(oldField Field) {
+	return func(oldField Field) {
+		documented, ok := oldField.(DocumentedField)
+		if !ok {
+			logrus.Panicf("Field `%s` of type `%T` can't be documented", oldField.Name(), oldField)
+		}
+		documented.WithDescription(description)
+		if example != nil {
+			documented.WithExample(example)
+		}
+	}
+}
+
+func defaulted(field Field) DefaultedField {
+	defaulted, ok := field.(DefaultedField)
+	if !ok {
+		logrus.Panicf("Field `%s` of type `%T` doesn't support the defaults", field.Name(), field)
+	}
+	return defaulted
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
 	"github.com/glothriel/grf/pkg/models"
 	"github.com/stretchr/testify/assert"
 )
@@ -173,3 +174,120 @@ func TestFieldDefaultFuncs(t *testing.T) {
 	assert.Equal(t, "foo", reprVal)
 	assert.Nil(t, reprValErr)
 }
+
+func TestSafeIntegerAndStringInteger(t *testing.T) {
+	// given
+	safe := NewField[mockModel]("field")
+	SafeInteger[int64]()(safe)
+	stringified := NewField[mockModel]("field")
+	StringInteger[uint64]()(stringified)
+
+	// when
+	smallRepr, smallReprErr := safe.ToRepresentation(models.InternalValue{"field": int64(42)}, nil)
+	bigRepr, bigReprErr := safe.ToRepresentation(models.InternalValue{"field": int64(1<<53 + 1)}, nil)
+	stringRepr, stringReprErr := stringified.ToRepresentation(models.InternalValue{"field": uint64(42)}, nil)
+	intVal, intValErr := safe.ToInternalValue(map[string]any{"field": "9007199254740993"}, nil)
+	_, missingErr := stringified.ToInternalValue(map[string]any{}, nil)
+
+	// then
+	assert.NoError(t, smallReprErr)
+	assert.Equal(t, int64(42), smallRepr)
+	assert.NoError(t, bigReprErr)
+	assert.Equal(t, "9007199254740993", bigRepr)
+	assert.NoError(t, stringReprErr)
+	assert.Equal(t, "42", stringRepr)
+	assert.NoError(t, intValErr)
+	assert.Equal(t, int64(1<<53+1), intVal)
+	assert.IsType(t, ErrorFieldIsNotPresentInPayload{}, missingErr)
+}
+
+func TestBytesField(t *testing.T) {
+	// given
+	field := NewField[mockModel]("field")
+	NewBytesField().WithMaxSize(16).WithContentTypes("image/png", "text/plain").Field()(field)
+	png := "iVBORw0KGgoAAAAAAAAAAA"
+
+	// when
+	repr, reprErr := field.ToRepresentation(models.InternalValue{"field": []byte("hello")}, nil)
+	text, textErr := field.ToInternalValue(map[string]any{"field": "aGVsbG8"}, nil)
+	image, imageErr := field.ToInternalValue(map[string]any{"field": png}, nil)
+	_, tooLargeErr := field.ToInternalValue(map[string]any{"field": "aGVsbG8gd29ybGQsIGhlbGxvIQ=="}, nil)
+	_, pdfErr := field.ToInternalValue(map[string]any{"field": "JVBERi0xLjQK"}, nil)
+	_, invalidErr := field.ToInternalValue(map[string]any{"field": "not base64!"}, nil)
+	null, nullErr := field.ToInternalValue(map[string]any{"field": nil}, nil)
+
+	// then
+	assert.NoError(t, reprErr)
+	assert.Equal(t, "aGVsbG8=", repr)
+	assert.NoError(t, textErr)
+	assert.Equal(t, []byte("hello"), text)
+	assert.NoError(t, imageErr)
+	assert.Len(t, image, 16)
+	assert.EqualError(t, tooLargeErr, "value can't be larger than 16 bytes")
+	assert.EqualError(t, pdfErr, "content type has to be one of: image/png, text/plain")
+	assert.Error(t, invalidErr)
+	assert.NoError(t, nullErr)
+	assert.Nil(t, null)
+}
+
+func TestFieldDefaultsAndRequired(t *testing.T) {
+	partialCtx := &gin.Context{}
+	grfctx.Set(partialCtx, grfctx.Metadata{Action: grfctx.ActionPartialUpdate})
+	updateCtx := &gin.Context{}
+	grfctx.Set(updateCtx, grfctx.Metadata{Action: grfctx.ActionUpdate})
+	statusField := func(options ...func(oldField Field)) Field {
+		field := NewField[struct{}]("status")
+		for _, option := range options {
+			option(field)
+		}
+		return field
+	}
+
+	for _, tt := range []struct {
+		name          string
+		field         Field
+		ctx           *gin.Context
+		expectedValue any
+		expectedErr   error
+	}{
+		{"static default", statusField(DefaultValue("draft")), nil, "draft", nil},
+		{"default func", statusField(DefaultFunc(func(ctx *gin.Context) any {
+			return ctx.GetString("user")
+		})), func() *gin.Context {
+			ctx := &gin.Context{}
+			ctx.Set("user", "alice")
+			return ctx
+		}(), "alice", nil},
+		{"required", statusField(Required()), nil, nil, ErrFieldRequired},
+		{"not required", statusField(Required(), func(oldField Field) {
+			oldField.(DefaultedField).WithRequired(false)
+		}), nil, nil, nil},
+		{"update without default", statusField(DefaultValue("draft")), updateCtx, nil, nil},
+		{"required in update", statusField(Required()), updateCtx, nil, ErrFieldRequired},
+		{"partial update", statusField(Required(), DefaultValue("draft")), partialCtx, nil, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			missing, missingErr := tt.field.ToInternalValue(map[string]any{}, tt.ctx)
+			present, presentErr := tt.field.ToInternalValue(map[string]any{"status": "published"}, tt.ctx)
+
+			// then
+			assert.Equal(t, tt.expectedErr, missingErr)
+			assert.Equal(t, tt.expectedValue, missing)
+			assert.NoError(t, presentErr)
+			assert.Equal(t, "published", present)
+		})
+	}
+}
+
+func TestDescribed(t *testing.T) {
+	// given
+	field := NewField[struct{}]("name")
+
+	// when
+	Described("Full name of the person", "Jane Doe")(field)
+
+	// then
+	assert.Equal(t, "Full name of the person", field.(DocumentedField).Description())
+	assert.Equal(t, "Jane Doe", field.(DocumentedField).Example())
+}
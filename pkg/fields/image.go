@@ -0,0 +1,264 @@
+package fields
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/storage"
+	"github.com/google/uuid"
+)
+
+// DefaultImageMaxPixels limits the number of the pixels of the images, checked before decoding them,
+// so small files declaring huge dimensions don't exhaust the memory, see ImageField.WithMaxPixels
+const DefaultImageMaxPixels = 40_000_000
+
+// RejectedImageError is returned after the object is saved, if the storage rejects the image of the
+// field, for example storage.ScanningWriter, the views respond with the validation error of the field
+type RejectedImageError struct {
+	Field string
+	Err   *storage.RejectedUploadError
+}
+
+func (e *RejectedImageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RejectedImageError) Unwrap() error {
+	return e.Err
+}
+
+// ImageVariant is a resized copy of the image, for example a thumbnail, generated on upload
+type ImageVariant struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+// ImageProcessor generates the image of a variant, fitting in the width and height of the variant
+type ImageProcessor func(img image.Image, width, height int) (image.Image, error)
+
+// ImageField accepts base64 encoded images, optionally as data URIs, validates them, saves them
+// with their variants in the storage and stores their keys in the model. The images are
+// represented as URLs of the original and the variants:
+//
+//	{"url": "/media/1f3a.png", "variants": {"thumbnail": "/media/1f3a_thumbnail.png"}}
+//
+// Sending the representation back, for example in PUT requests, keeps the image unchanged. The
+// images are saved in the storage after the object is saved, see grfctx.OnSaved.
+type ImageField struct {
+	storage   storage.Writer
+	baseURL   string
+	formats   []string
+	maxBytes  int
+	minWidth  int
+	minHeight int
+	maxWidth  int
+	maxHeight int
+	maxPixels int
+	variants  []ImageVariant
+	processor ImageProcessor
+}
+
+// WithFormats limits the accepted image formats, png, jpeg and gif are accepted by default
+func (f *ImageField) WithFormats(formats ...string) *ImageField {
+	f.formats = formats
+	return f
+}
+
+// WithMaxSize limits the size of the decoded image file in bytes
+func (f *ImageField) WithMaxSize(maxBytes int) *ImageField {
+	f.maxBytes = maxBytes
+	return f
+}
+
+// WithMinDimensions rejects images smaller than width x height pixels
+func (f *ImageField) WithMinDimensions(width, height int) *ImageField {
+	f.minWidth, f.minHeight = width, height
+	return f
+}
+
+// WithMaxDimensions rejects images larger than width x height pixels
+func (f *ImageField) WithMaxDimensions(width, height int) *ImageField {
+	f.maxWidth, f.maxHeight = width, height
+	return f
+}
+
+// WithMaxPixels limits the number of the pixels of the images, DefaultImageMaxPixels by default
+func (f *ImageField) WithMaxPixels(pixels int) *ImageField {
+	f.maxPixels = pixels
+	return f
+}
+
+// WithVariant generates a variant of every uploaded image, fitting in width x height pixels
+func (f *ImageField) WithVariant(name string, width, height int) *ImageField {
+	f.variants = append(f.variants, ImageVariant{Name: name, Width: width, Height: height})
+	return f
+}
+
+// WithProcessor replaces the processor generating the variants, by default ResizeNearest is used
+func (f *ImageField) WithProcessor(processor ImageProcessor) *ImageField {
+	f.processor = processor
+	return f
+}
+
+// Field returns a WithField option, that replaces the field's conversions with the ones of the
+// ImageField. The model field has to be a string, holding the key of the original image.
+func (f *ImageField) Field() func(oldField Field) {
+	return func(oldField Field) {
+		oldField.WithInternalValueFunc(f.internalValue)
+		oldField.WithRepresentationFunc(f.representation)
+	}
+}
+
+func (f *ImageField) internalValue(raw map[string]any, name string, ctx *gin.Context) (any, error) {
+	rawValue, ok := raw[name]
+	if !ok {
+		return nil, NewErrorFieldIsNotPresentInPayload(name)
+	}
+	switch value := rawValue.(type) {
+	case nil:
+		return "", nil
+	case map[string]any:
+		// The representation sent back, the image didn't change
+		return nil, NewErrorFieldIsNotPresentInPayload(name)
+	case string:
+		return f.upload(ctx, name, value)
+	}
+	return nil, errors.New("image has to be a base64 encoded string")
+}
+
+func (f *ImageField) upload(ctx *gin.Context, name, encoded string) (string, error) {
+	if strings.HasPrefix(encoded, "data:") {
+		_, encoded, _ = strings.Cut(encoded, ",")
+	}
+	data, decodeErr := base64.StdEncoding.DecodeString(encoded)
+	if decodeErr != nil {
+		return "", errors.New("image has to be a base64 encoded string")
+	}
+	if f.maxBytes > 0 && len(data) > f.maxBytes {
+		return "", fmt.Errorf("image can't be larger than %d bytes", f.maxBytes)
+	}
+	config, format, configErr := image.DecodeConfig(bytes.NewReader(data))
+	if configErr != nil || !slices.Contains(f.formats, format) {
+		return "", fmt.Errorf("image format has to be one of: %s", strings.Join(f.formats, ", "))
+	}
+	if config.Width < f.minWidth || config.Height < f.minHeight {
+		return "", fmt.Errorf("image has to be at least %dx%d pixels", f.minWidth, f.minHeight)
+	}
+	if (f.maxWidth > 0 && config.Width > f.maxWidth) || (f.maxHeight > 0 && config.Height > f.maxHeight) {
+		return "", fmt.Errorf("image can't be larger than %dx%d pixels", f.maxWidth, f.maxHeight)
+	}
+	if f.maxPixels > 0 && config.Width*config.Height > f.maxPixels {
+		return "", fmt.Errorf("image can't have more than %d pixels", f.maxPixels)
+	}
+	key := uuid.New().String() + "." + format
+	files := map[string][]byte{key: data}
+	if len(f.variants) > 0 {
+		img, _, imgErr := image.Decode(bytes.NewReader(data))
+		if imgErr != nil {
+			return "", imgErr
+		}
+		for _, variant := range f.variants {
+			variantImg, processErr := f.processor(img, variant.Width, variant.Height)
+			if processErr != nil {
+				return "", processErr
+			}
+			var encodedVariant bytes.Buffer
+			if encodeErr := encodeImage(&encodedVariant, variantImg, format); encodeErr != nil {
+				return "", encodeErr
+			}
+			files[variantKey(key, variant.Name)] = encodedVariant.Bytes()
+		}
+	}
+	saveErr := grfctx.OnSaved(ctx, func() error {
+		for fileKey, fileData := range files {
+			saveErr := f.storage.Save(ctx, fileKey, fileData, "image/"+format)
+			var rejectedErr *storage.RejectedUploadError
+			if errors.As(saveErr, &rejectedErr) {
+				return &RejectedImageError{Field: name, Err: rejectedErr}
+			}
+			if saveErr != nil {
+				return saveErr
+			}
+		}
+		return nil
+	})
+	if saveErr != nil {
+		return "", saveErr
+	}
+	return key, nil
+}
+
+func (f *ImageField) representation(intVal models.InternalValue, name string, _ *gin.Context) (any, error) {
+	key, _ := intVal[name].(string)
+	if key == "" {
+		return nil, nil
+	}
+	variants := map[string]any{}
+	for _, variant := range f.variants {
+		variants[variant.Name] = f.url(variantKey(key, variant.Name))
+	}
+	return map[string]any{"url": f.url(key), "variants": variants}, nil
+}
+
+func (f *ImageField) url(key string) string {
+	return strings.TrimSuffix(f.baseURL, "/") + "/" + key
+}
+
+func variantKey(key, variant string) string {
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext) + "_" + variant + ext
+}
+
+func encodeImage(buf *bytes.Buffer, img image.Image, format string) error {
+	switch format {
+	case "png":
+		return png.Encode(buf, img)
+	case "jpeg":
+		return jpeg.Encode(buf, img, nil)
+	case "gif":
+		return gif.Encode(buf, img, nil)
+	}
+	return fmt.Errorf("can't encode %s images", format)
+}
+
+// ResizeNearest is the default ImageProcessor. It scales the image down using nearest neighbor
+// interpolation, keeping its aspect ratio. Images fitting in the dimensions are not scaled up.
+func ResizeNearest(img image.Image, width, height int) (image.Image, error) {
+	bounds := img.Bounds()
+	scale := min(1, float64(width)/float64(bounds.Dx()), float64(height)/float64(bounds.Dy()))
+	resized := image.NewRGBA(image.Rect(
+		0, 0, max(1, int(float64(bounds.Dx())*scale)), max(1, int(float64(bounds.Dy())*scale)),
+	))
+	for y := 0; y < resized.Bounds().Dy(); y++ {
+		for x := 0; x < resized.Bounds().Dx(); x++ {
+			resized.Set(x, y, img.At(bounds.Min.X+int(float64(x)/scale), bounds.Min.Y+int(float64(y)/scale)))
+		}
+	}
+	return resized, nil
+}
+
+// NewImageField creates an ImageField saving the images in the storage. The images are
+// represented as URLs prefixed with baseURL, for example the path of the file download view
+// serving the storage.
+func NewImageField(s storage.Writer, baseURL string) *ImageField {
+	return &ImageField{
+		storage:   s,
+		baseURL:   baseURL,
+		formats:   []string{"png", "jpeg", "gif"},
+		maxPixels: DefaultImageMaxPixels,
+		processor: ResizeNearest,
+	}
+}
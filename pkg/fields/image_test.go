@@ -0,0 +1,164 @@
+package fields
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func encodedPNG(t *testing.T, width, height int) string {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestImageFieldUploadsImageWithVariants(t *testing.T) {
+	// given
+	media := storage.NewInMemory()
+	field := NewField[struct{}]("avatar")
+	NewImageField(media, "/media/").WithVariant("thumbnail", 10, 10).Field()(field)
+
+	// when
+	key, uploadErr := field.ToInternalValue(map[string]any{
+		"avatar": "data:image/png;base64," + encodedPNG(t, 40, 20),
+	}, nil)
+	representation, representationErr := field.ToRepresentation(models.InternalValue{"avatar": key}, nil)
+
+	// then
+	assert.NoError(t, uploadErr)
+	assert.True(t, strings.HasSuffix(key.(string), ".png"))
+	assert.NoError(t, representationErr)
+	thumbnailKey := strings.TrimSuffix(key.(string), ".png") + "_thumbnail.png"
+	assert.Equal(t, map[string]any{
+		"url":      "/media/" + key.(string),
+		"variants": map[string]any{"thumbnail": "/media/" + thumbnailKey},
+	}, representation)
+	thumbnail, openErr := media.Open(context.Background(), thumbnailKey)
+	assert.NoError(t, openErr)
+	config, decodeErr := png.DecodeConfig(thumbnail.Content)
+	assert.NoError(t, decodeErr)
+	assert.Equal(t, []int{10, 5}, []int{config.Width, config.Height})
+}
+
+func TestImageFieldValidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		field         *ImageField
+		value         any
+		expectedError string
+	}{
+		{
+			name:          "not base64",
+			field:         NewImageField(storage.NewInMemory(), "/media"),
+			value:         "not an image!",
+			expectedError: "image has to be a base64 encoded string",
+		},
+		{
+			name:          "unsupported format",
+			field:         NewImageField(storage.NewInMemory(), "/media").WithFormats("jpeg"),
+			value:         encodedPNG(t, 10, 10),
+			expectedError: "image format has to be one of: jpeg",
+		},
+		{
+			name:          "too large file",
+			field:         NewImageField(storage.NewInMemory(), "/media").WithMaxSize(10),
+			value:         encodedPNG(t, 10, 10),
+			expectedError: "image can't be larger than 10 bytes",
+		},
+		{
+			name:          "too small image",
+			field:         NewImageField(storage.NewInMemory(), "/media").WithMinDimensions(20, 20),
+			value:         encodedPNG(t, 30, 10),
+			expectedError: "image has to be at least 20x20 pixels",
+		},
+		{
+			name:          "too large image",
+			field:         NewImageField(storage.NewInMemory(), "/media").WithMaxDimensions(20, 20),
+			value:         encodedPNG(t, 30, 10),
+			expectedError: "image can't be larger than 20x20 pixels",
+		},
+		{
+			name:          "too many pixels",
+			field:         NewImageField(storage.NewInMemory(), "/media").WithMaxPixels(200),
+			value:         encodedPNG(t, 30, 10),
+			expectedError: "image can't have more than 200 pixels",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			field := NewField[struct{}]("avatar")
+			tt.field.Field()(field)
+
+			// when
+			_, err := field.ToInternalValue(map[string]any{"avatar": tt.value}, nil)
+
+			// then
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestImageFieldSavesImageAfterObjectIsSaved(t *testing.T) {
+	// given
+	media := storage.NewInMemory()
+	field := NewField[struct{}]("avatar")
+	NewImageField(media, "/media").Field()(field)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	grfctx.DeferUntilSaved(ctx)
+
+	// when
+	key, uploadErr := field.ToInternalValue(map[string]any{"avatar": encodedPNG(t, 1, 1)}, ctx)
+	_, openBeforeErr := media.Open(context.Background(), key.(string))
+	runErr := grfctx.RunOnSaved(ctx)
+	_, openAfterErr := media.Open(context.Background(), key.(string))
+
+	// then
+	assert.NoError(t, uploadErr)
+	assert.Error(t, openBeforeErr)
+	assert.NoError(t, runErr)
+	assert.NoError(t, openAfterErr)
+}
+
+func TestImageFieldKeepsImageWhenRepresentationIsSentBack(t *testing.T) {
+	// given
+	field := NewField[struct{}]("avatar")
+	NewImageField(storage.NewInMemory(), "/media").Field()(field)
+
+	// when
+	_, err := field.ToInternalValue(map[string]any{"avatar": map[string]any{"url": "/media/a.png"}}, nil)
+
+	// then
+	assert.IsType(t, ErrorFieldIsNotPresentInPayload{}, err)
+}
+
+type rejectingScanner struct{}
+
+func (rejectingScanner) Scan(context.Context, string, []byte) (storage.ScanResult, error) {
+	return storage.ScanResult{Threat: "Test-Signature"}, nil
+}
+
+func TestImageFieldRejectsUploadsFlaggedByScanner(t *testing.T) {
+	// given
+	field := NewField[struct{}]("avatar")
+	NewImageField(storage.NewScanningWriter(storage.NewInMemory(), rejectingScanner{}), "/media").Field()(field)
+
+	// when
+	_, err := field.ToInternalValue(map[string]any{"avatar": encodedPNG(t, 1, 1)}, nil)
+
+	// then
+	var rejected *storage.RejectedUploadError
+	assert.ErrorAs(t, err, &rejected)
+	assert.Equal(t, "Test-Signature", rejected.Threat)
+}
@@ -0,0 +1,41 @@
+package fields
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/types"
+)
+
+// SafeInteger returns a WithField option, that represents the values of the integer field beyond
+// types.MaxSafeInteger as strings, so JavaScript clients don't silently lose their precision, and
+// accepts the integers sent as strings. Use types.FieldTypeMapper.WithSafeIntegers to do it for
+// all the fields.
+func SafeInteger[T types.Integer]() func(oldField Field) {
+	return safeInteger[T](false)
+}
+
+// StringInteger works like SafeInteger, but represents all the values as strings, so the clients
+// can handle them the same way, for example the snowflake IDs
+func StringInteger[T types.Integer]() func(oldField Field) {
+	return safeInteger[T](true)
+}
+
+func safeInteger[T types.Integer](alwaysString bool) func(oldField Field) {
+	toRepresentation := types.ConvertIntegerToSafeNumber(alwaysString)
+	toInternalValue := types.ConvertSafeNumberToInteger[T](types.NumericCoercionStrict)
+	return func(oldField Field) {
+		oldField.WithRepresentationFunc(func(intVal models.InternalValue, name string, ctx *gin.Context) (any, error) {
+			return toRepresentation(intVal[name])
+		})
+		oldField.WithInternalValueFunc(func(raw map[string]any, name string, ctx *gin.Context) (any, error) {
+			rawValue, ok := raw[name]
+			if !ok {
+				return nil, NewErrorFieldIsNotPresentInPayload(name)
+			}
+			if rawValue == nil {
+				return nil, nil
+			}
+			return toInternalValue(rawValue)
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package fields
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/locale"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+)
+
+// requestLocale returns the locale of the request, the zero Locale if it was not resolved
+func requestLocale(ctx *gin.Context) grfctx.Locale {
+	l, _ := grfctx.CurrentLocale(ctx)
+	return l
+}
+
+// DateTimeField represents times in the timezone of the request, see grfctx.Locale. Times sent
+// without an offset, like `2024-05-01T09:30:00` or `2024-05-01`, are also interpreted in it.
+type DateTimeField struct {
+	lenient bool
+}
+
+// NewDateTimeField creates a DateTimeField
+func NewDateTimeField() *DateTimeField {
+	return &DateTimeField{}
+}
+
+// WithLenientParsing additionally accepts unix timestamps, in seconds or milliseconds, as numbers or
+// strings, and the common formats like RFC1123 or offsets without a colon, to ease migrating the
+// legacy clients. The times are still represented in RFC3339.
+func (f *DateTimeField) WithLenientParsing() *DateTimeField {
+	f.lenient = true
+	return f
+}
+
+// Field returns a WithField option, that replaces the field's conversions with the ones of the
+// DateTimeField. The model field has to be a time.Time or *time.Time.
+func (f *DateTimeField) Field() func(oldField Field) {
+	return func(oldField Field) {
+		oldField.WithInternalValueFunc(f.internalValue)
+		oldField.WithRepresentationFunc(f.representation)
+	}
+}
+
+func (f *DateTimeField) representation(intVal models.InternalValue, name string, ctx *gin.Context) (any, error) {
+	var value time.Time
+	switch typed := intVal[name].(type) {
+	case time.Time:
+		value = typed
+	case *time.Time:
+		if typed == nil {
+			return nil, nil
+		}
+		value = *typed
+	default:
+		return intVal[name], nil
+	}
+	return value.In(requestLocale(ctx).Location()).Format(time.RFC3339), nil
+}
+
+func (f *DateTimeField) internalValue(raw map[string]any, name string, ctx *gin.Context) (any, error) {
+	rawValue, ok := raw[name]
+	if !ok {
+		return nil, NewErrorFieldIsNotPresentInPayload(name)
+	}
+	if rawValue == nil {
+		return nil, nil
+	}
+	if number, isNumber := rawValue.(float64); isNumber && f.lenient {
+		if parsed, ok := unixTime(number); ok {
+			return parsed, nil
+		}
+		return nil, f.formatErr()
+	}
+	text, ok := rawValue.(string)
+	if !ok {
+		return nil, f.formatErr()
+	}
+	if parsed, parseErr := time.Parse(time.RFC3339Nano, text); parseErr == nil {
+		return parsed, nil
+	}
+	location := requestLocale(ctx).Location()
+	for _, layout := range []string{"2006-01-02T15:04:05", time.DateTime, time.DateOnly} {
+		if parsed, parseErr := time.ParseInLocation(layout, text, location); parseErr == nil {
+			return parsed, nil
+		}
+	}
+	if !f.lenient {
+		return nil, f.formatErr()
+	}
+	if number, parseErr := strconv.ParseFloat(text, 64); parseErr == nil {
+		if parsed, ok := unixTime(number); ok {
+			return parsed, nil
+		}
+		return nil, f.formatErr()
+	}
+	for _, layout := range lenientLayouts {
+		if parsed, parseErr := time.ParseInLocation(layout, text, location); parseErr == nil {
+			return parsed, nil
+		}
+	}
+	return nil, f.formatErr()
+}
+
+func (f *DateTimeField) formatErr() error {
+	if f.lenient {
+		return errors.New("expected a time in RFC3339 or YYYY-MM-DD format, or a unix timestamp")
+	}
+	return errors.New("expected a time in RFC3339 or YYYY-MM-DD format")
+}
+
+// lenientLayouts are the formats accepted by the lenient DateTimeFields, besides the strict ones
+var lenientLayouts = []string{
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05Z0700",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+}
+
+// unixTimestampMillisThreshold separates the timestamps in seconds from the ones in milliseconds,
+// the larger timestamps in seconds would be later than the year 5000
+const unixTimestampMillisThreshold = 1e11
+
+// maxUnixTimestampMillis bounds the timestamps, so the conversion to int64 doesn't overflow
+const maxUnixTimestampMillis = 1e15
+
+// unixTime converts the unix timestamp in seconds or milliseconds to the time, the non-finite
+// values, like `NaN` and `Inf` accepted by strconv.ParseFloat, and the out of range ones are rejected
+func unixTime(timestamp float64) (time.Time, bool) {
+	if math.IsNaN(timestamp) || math.IsInf(timestamp, 0) || math.Abs(timestamp) >= maxUnixTimestampMillis {
+		return time.Time{}, false
+	}
+	if math.Abs(timestamp) >= unixTimestampMillisThreshold {
+		return time.UnixMilli(int64(timestamp)).UTC(), true
+	}
+	seconds, fraction := math.Modf(timestamp)
+	return time.Unix(int64(seconds), int64(fraction*1e9)).UTC(), true
+}
+
+// MoneyField represents amounts of money with their currency and their text formatted for the
+// locale of the request, see locale.FormatMoney:
+//
+//	{"amount": 1234.5, "currency": "EUR", "formatted": "1.234,50 €"}
+//
+// The currency is read from the currency field of the model, if set, otherwise it's the currency
+// of the request. The amounts are accepted as numbers or the representations sent back.
+type MoneyField struct {
+	currencyField string
+}
+
+// NewMoneyField creates a MoneyField
+func NewMoneyField() *MoneyField {
+	return &MoneyField{}
+}
+
+// WithCurrencyField reads the currency of the amounts from the model field
+func (f *MoneyField) WithCurrencyField(field string) *MoneyField {
+	f.currencyField = field
+	return f
+}
+
+// Field returns a WithField option, that replaces the field's conversions with the ones of the
+// MoneyField. The model field has to be a number.
+func (f *MoneyField) Field() func(oldField Field) {
+	return func(oldField Field) {
+		oldField.WithInternalValueFunc(f.internalValue)
+		oldField.WithRepresentationFunc(f.representation)
+	}
+}
+
+func (f *MoneyField) representation(intVal models.InternalValue, name string, ctx *gin.Context) (any, error) {
+	if intVal[name] == nil {
+		return nil, nil
+	}
+	amount, ok := common.AsFloat(intVal[name])
+	if !ok {
+		return nil, errors.New("amount has to be a number")
+	}
+	l := requestLocale(ctx)
+	currency := l.Currency
+	if modelCurrency, ok := intVal[f.currencyField].(string); ok && modelCurrency != "" {
+		currency = modelCurrency
+	}
+	return map[string]any{
+		"amount":    amount,
+		"currency":  currency,
+		"formatted": locale.FormatMoney(l, amount, currency),
+	}, nil
+}
+
+func (f *MoneyField) internalValue(raw map[string]any, name string, _ *gin.Context) (any, error) {
+	rawValue, ok := raw[name]
+	if !ok {
+		return nil, NewErrorFieldIsNotPresentInPayload(name)
+	}
+	if representation, isRepresentation := rawValue.(map[string]any); isRepresentation {
+		rawValue = representation["amount"]
+	}
+	if rawValue == nil {
+		return nil, nil
+	}
+	amount, ok := rawValue.(float64)
+	if !ok {
+		return nil, errors.New("amount has to be a number")
+	}
+	return amount, nil
+}
+
+// TranslatedField represents texts translated to several languages, stored in a map[string]string
+// model field keyed by the language tags, in the language of the request, see locale.Translate.
+// Objects of translations replace the stored ones, plain texts are stored as the translation to
+// the language of the request.
+type TranslatedField struct {
+	fallback string
+}
+
+// NewTranslatedField creates a TranslatedField falling back to the language, if the text is not
+// translated to the language of the request
+func NewTranslatedField(fallback string) *TranslatedField {
+	return &TranslatedField{fallback: fallback}
+}
+
+// Field returns a WithField option, that replaces the field's conversions with the ones of the
+// TranslatedField. The model field has to be a map[string]string, for GORM it can be stored using
+// the `gorm:"serializer:json"` tag.
+func (f *TranslatedField) Field() func(oldField Field) {
+	return func(oldField Field) {
+		oldField.WithInternalValueFunc(f.internalValue)
+		oldField.WithRepresentationFunc(f.representation)
+	}
+}
+
+func (f *TranslatedField) representation(intVal models.InternalValue, name string, ctx *gin.Context) (any, error) {
+	translations, ok := intVal[name].(map[string]string)
+	if !ok {
+		return intVal[name], nil
+	}
+	translation, ok := locale.Translate(requestLocale(ctx), translations, f.fallback)
+	if !ok {
+		return nil, nil
+	}
+	return translation, nil
+}
+
+func (f *TranslatedField) internalValue(raw map[string]any, name string, ctx *gin.Context) (any, error) {
+	rawValue, ok := raw[name]
+	if !ok {
+		return nil, NewErrorFieldIsNotPresentInPayload(name)
+	}
+	switch value := rawValue.(type) {
+	case nil:
+		return map[string]string{}, nil
+	case string:
+		language := requestLocale(ctx).Language
+		if language == "" {
+			language = f.fallback
+		}
+		return map[string]string{language: value}, nil
+	case map[string]any:
+		translations := map[string]string{}
+		for language, translation := range value {
+			text, isText := translation.(string)
+			if !isText {
+				return nil, errors.New("translations have to be texts")
+			}
+			translations[language] = text
+		}
+		return translations, nil
+	}
+	return nil, errors.New("expected a text or an object of translations")
+}
+
+// ChoiceField represents the values of the choices together with their labels translated to the
+// language of the request using the catalog, so the user interfaces don't duplicate the labels:
+//
+//	{"value": "open", "label": "Offen"}
+//
+// The labels are looked up under the keys made of the prefix and the value, like `status.open`,
+// the values not in the catalog are used as their own labels. The values are accepted as is or as
+// the representations sent back.
+type ChoiceField struct {
+	catalog *locale.Catalog
+	prefix  string
+}
+
+// NewChoiceField creates a ChoiceField looking up the labels under the prefix, like "status."
+func NewChoiceField(catalog *locale.Catalog, prefix string) *ChoiceField {
+	return &ChoiceField{catalog: catalog, prefix: prefix}
+}
+
+// Field returns a WithField option, that replaces the field's conversions with the ones of the
+// ChoiceField. The choices should still be validated, for example using the `oneof` rule.
+func (f *ChoiceField) Field() func(oldField Field) {
+	return func(oldField Field) {
+		oldField.WithInternalValueFunc(f.internalValue)
+		oldField.WithRepresentationFunc(f.representation)
+	}
+}
+
+func (f *ChoiceField) representation(intVal models.InternalValue, name string, ctx *gin.Context) (any, error) {
+	if intVal[name] == nil {
+		return nil, nil
+	}
+	value := fmt.Sprint(intVal[name])
+	label, ok := f.catalog.Message(requestLocale(ctx), f.prefix+value)
+	if !ok {
+		label = value
+	}
+	return map[string]any{"value": intVal[name], "label": label}, nil
+}
+
+func (f *ChoiceField) internalValue(raw map[string]any, name string, _ *gin.Context) (any, error) {
+	rawValue, ok := raw[name]
+	if !ok {
+		return nil, NewErrorFieldIsNotPresentInPayload(name)
+	}
+	if representation, isRepresentation := rawValue.(map[string]any); isRepresentation {
+		return representation["value"], nil
+	}
+	return rawValue, nil
+}
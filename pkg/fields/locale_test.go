@@ -0,0 +1,175 @@
+package fields
+
+import (
+	"math"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/locale"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func localizedCtx(l grfctx.Locale) *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	grfctx.SetLocale(ctx, l)
+	return ctx
+}
+
+func TestDateTimeField(t *testing.T) {
+	// given
+	warsaw, _ := time.LoadLocation("Europe/Warsaw")
+	ctx := localizedCtx(grfctx.Locale{Timezone: warsaw})
+	field := NewField[struct{}]("starts_at")
+	NewDateTimeField().Field()(field)
+	startsAt := time.Date(2024, 5, 1, 7, 30, 0, 0, time.UTC)
+
+	// when
+	representation, representationErr := field.ToRepresentation(models.InternalValue{"starts_at": startsAt}, ctx)
+	withOffset, withOffsetErr := field.ToInternalValue(map[string]any{"starts_at": "2024-05-01T07:30:00Z"}, ctx)
+	local, localErr := field.ToInternalValue(map[string]any{"starts_at": "2024-05-01T09:30:00"}, ctx)
+	_, invalidErr := field.ToInternalValue(map[string]any{"starts_at": "tomorrow"}, ctx)
+
+	// then
+	assert.NoError(t, representationErr)
+	assert.Equal(t, "2024-05-01T09:30:00+02:00", representation)
+	assert.NoError(t, withOffsetErr)
+	assert.True(t, startsAt.Equal(withOffset.(time.Time)))
+	assert.NoError(t, localErr)
+	assert.True(t, startsAt.Equal(local.(time.Time)))
+	assert.Error(t, invalidErr)
+}
+
+func TestDateTimeFieldWithLenientParsing(t *testing.T) {
+	startsAt := time.Date(2024, 5, 1, 7, 30, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name     string
+		raw      any
+		expected time.Time
+	}{
+		{"rfc3339", "2024-05-01T07:30:00Z", startsAt},
+		{"date only", "2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"unix seconds", float64(startsAt.Unix()), startsAt},
+		{"unix milliseconds", float64(startsAt.UnixMilli() + 250), startsAt.Add(250 * time.Millisecond)},
+		{"unix seconds string", "1714548600", startsAt},
+		{"offset without colon", "2024-05-01T09:30:00+0200", startsAt},
+		{"rfc1123", "Wed, 01 May 2024 07:30:00 GMT", startsAt},
+		{"slashes", "2024/05/01 07:30:00", startsAt},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			field := NewField[struct{}]("starts_at")
+			NewDateTimeField().WithLenientParsing().Field()(field)
+
+			// when
+			parsed, parseErr := field.ToInternalValue(map[string]any{"starts_at": tt.raw}, nil)
+			representation, representationErr := field.ToRepresentation(models.InternalValue{"starts_at": parsed}, nil)
+
+			// then
+			assert.NoError(t, parseErr)
+			assert.True(t, tt.expected.Equal(parsed.(time.Time)), "expected %s, got %s", tt.expected, parsed)
+			assert.NoError(t, representationErr)
+			assert.Equal(t, tt.expected.Format(time.RFC3339), representation)
+		})
+	}
+}
+
+func TestDateTimeFieldRejectsNonFiniteTimestamps(t *testing.T) {
+	for _, raw := range []any{"NaN", "Inf", "-Infinity", "1e300", math.Inf(1)} {
+		// given
+		field := NewField[struct{}]("starts_at")
+		NewDateTimeField().WithLenientParsing().Field()(field)
+
+		// when
+		_, parseErr := field.ToInternalValue(map[string]any{"starts_at": raw}, nil)
+
+		// then
+		assert.Error(t, parseErr, "%v", raw)
+	}
+}
+
+func TestDateTimeFieldRejectsTimestampsWithoutLenientParsing(t *testing.T) {
+	// given
+	field := NewField[struct{}]("starts_at")
+	NewDateTimeField().Field()(field)
+
+	// when
+	_, numberErr := field.ToInternalValue(map[string]any{"starts_at": float64(1714548600)}, nil)
+	_, stringErr := field.ToInternalValue(map[string]any{"starts_at": "1714548600"}, nil)
+
+	// then
+	assert.EqualError(t, numberErr, "expected a time in RFC3339 or YYYY-MM-DD format")
+	assert.EqualError(t, stringErr, "expected a time in RFC3339 or YYYY-MM-DD format")
+}
+
+func TestMoneyField(t *testing.T) {
+	// given
+	ctx := localizedCtx(grfctx.Locale{Language: "de", Currency: "USD"})
+	field := NewField[struct{}]("price")
+	NewMoneyField().WithCurrencyField("currency").Field()(field)
+
+	// when
+	modelCurrency, modelCurrencyErr := field.ToRepresentation(models.InternalValue{"price": 1234.5, "currency": "EUR"}, ctx)
+	requestCurrency, requestCurrencyErr := field.ToRepresentation(models.InternalValue{"price": 10}, ctx)
+	sentBack, sentBackErr := field.ToInternalValue(map[string]any{"price": modelCurrency}, ctx)
+
+	// then
+	assert.NoError(t, modelCurrencyErr)
+	assert.Equal(t, map[string]any{"amount": 1234.5, "currency": "EUR", "formatted": "1.234,50 €"}, modelCurrency)
+	assert.NoError(t, requestCurrencyErr)
+	assert.Equal(t, map[string]any{"amount": 10.0, "currency": "USD", "formatted": "10,00 $"}, requestCurrency)
+	assert.NoError(t, sentBackErr)
+	assert.Equal(t, 1234.5, sentBack)
+}
+
+func TestTranslatedField(t *testing.T) {
+	// given
+	ctx := localizedCtx(grfctx.Locale{Language: "pl"})
+	field := NewField[struct{}]("name")
+	NewTranslatedField("en").Field()(field)
+
+	// when
+	translated, translatedErr := field.ToRepresentation(models.InternalValue{"name": map[string]string{"en": "Shoes", "pl": "Buty"}}, ctx)
+	fallback, fallbackErr := field.ToRepresentation(models.InternalValue{"name": map[string]string{"en": "Shoes"}}, ctx)
+	text, textErr := field.ToInternalValue(map[string]any{"name": "Buty"}, ctx)
+	object, objectErr := field.ToInternalValue(map[string]any{"name": map[string]any{"en": "Shoes", "pl": "Buty"}}, ctx)
+
+	// then
+	assert.NoError(t, translatedErr)
+	assert.Equal(t, "Buty", translated)
+	assert.NoError(t, fallbackErr)
+	assert.Equal(t, "Shoes", fallback)
+	assert.NoError(t, textErr)
+	assert.Equal(t, map[string]string{"pl": "Buty"}, text)
+	assert.NoError(t, objectErr)
+	assert.Equal(t, map[string]string{"en": "Shoes", "pl": "Buty"}, object)
+}
+
+func TestChoiceField(t *testing.T) {
+	// given
+	ctx := localizedCtx(grfctx.Locale{Language: "de"})
+	catalog := locale.NewCatalog("en").
+		Add("en", map[string]string{"status.open": "Open"}).
+		Add("de", map[string]string{"status.open": "Offen"})
+	field := NewField[struct{}]("status")
+	NewChoiceField(catalog, "status.").Field()(field)
+
+	// when
+	labeled, labeledErr := field.ToRepresentation(models.InternalValue{"status": "open"}, ctx)
+	unlabeled, unlabeledErr := field.ToRepresentation(models.InternalValue{"status": "archived"}, ctx)
+	sentBack, sentBackErr := field.ToInternalValue(map[string]any{"status": labeled}, ctx)
+	plain, plainErr := field.ToInternalValue(map[string]any{"status": "archived"}, ctx)
+
+	// then
+	assert.NoError(t, labeledErr)
+	assert.Equal(t, map[string]any{"value": "open", "label": "Offen"}, labeled)
+	assert.NoError(t, unlabeledErr)
+	assert.Equal(t, map[string]any{"value": "archived", "label": "archived"}, unlabeled)
+	assert.NoError(t, sentBackErr)
+	assert.Equal(t, "open", sentBack)
+	assert.NoError(t, plainErr)
+	assert.Equal(t, "archived", plain)
+}
@@ -7,3 +7,9 @@ type GRFRepresentable interface {
 type GRFParsable interface {
 	FromRepresentation(any) error
 }
+
+// GRFValidatable can be implemented by map and struct-valued model fields to validate them
+// after they are parsed from the request
+type GRFValidatable interface {
+	Validate() error
+}
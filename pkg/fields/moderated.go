@@ -0,0 +1,115 @@
+package fields
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/moderation"
+)
+
+// ModerationAction decides what happens with the texts flagged by the moderator
+type ModerationAction int
+
+const (
+	// ModerationReject rejects the flagged texts with a validation error
+	ModerationReject ModerationAction = iota
+	// ModerationFlag accepts the flagged texts and pushes them to the moderation queue, once the
+	// object is saved, see grfctx.OnSaved
+	ModerationFlag
+	// ModerationMask accepts the flagged texts with the matched fragments masked. Texts flagged
+	// without matches, for example by external moderation APIs, are rejected.
+	ModerationMask
+)
+
+// ModeratedField checks the texts sent by the clients with a moderation.Moderator during
+// validation, rejecting, flagging or masking the unwanted content. The model field has to be a
+// string.
+type ModeratedField struct {
+	moderator moderation.Moderator
+	action    ModerationAction
+	queue     moderation.Queue
+	mask      rune
+}
+
+// WithAction sets the action applied to the flagged texts, ModerationReject by default
+func (f *ModeratedField) WithAction(action ModerationAction) *ModeratedField {
+	f.action = action
+	return f
+}
+
+// WithQueue sets the queue receiving the texts flagged with the ModerationFlag action
+func (f *ModeratedField) WithQueue(queue moderation.Queue) *ModeratedField {
+	f.queue = queue
+	return f
+}
+
+// WithMask sets the character replacing the matches with the ModerationMask action, `*` by default
+func (f *ModeratedField) WithMask(mask rune) *ModeratedField {
+	f.mask = mask
+	return f
+}
+
+// Field returns a WithField option, that replaces the field's internal value conversion with the
+// moderated one
+func (f *ModeratedField) Field() func(oldField Field) {
+	return func(oldField Field) {
+		oldField.WithInternalValueFunc(f.internalValue)
+	}
+}
+
+func (f *ModeratedField) internalValue(raw map[string]any, name string, ctx *gin.Context) (any, error) {
+	rawValue, ok := raw[name]
+	if !ok {
+		return nil, NewErrorFieldIsNotPresentInPayload(name)
+	}
+	text, ok := rawValue.(string)
+	if !ok {
+		return rawValue, nil
+	}
+	reqCtx, path := context.Background(), ""
+	if ctx != nil && ctx.Request != nil {
+		reqCtx, path = ctx.Request.Context(), ctx.Request.URL.Path
+	}
+	verdict, moderateErr := f.moderator.Moderate(reqCtx, text)
+	if moderateErr != nil {
+		return nil, fmt.Errorf("could not moderate the text: %w", moderateErr)
+	}
+	if !verdict.Flagged {
+		return text, nil
+	}
+	switch f.action {
+	case ModerationFlag:
+		if f.queue == nil {
+			return nil, errors.New("moderation queue is not configured")
+		}
+		flag := moderation.Flag{
+			Field:      name,
+			Path:       path,
+			Text:       text,
+			Categories: verdict.Categories,
+			FlaggedAt:  time.Now(),
+		}
+		if pushErr := grfctx.OnSaved(ctx, func() error {
+			return f.queue.Push(reqCtx, flag)
+		}); pushErr != nil {
+			return nil, pushErr
+		}
+		return text, nil
+	case ModerationMask:
+		if len(verdict.Matches) > 0 {
+			return moderation.Mask(text, verdict.Matches, f.mask), nil
+		}
+	}
+	return nil, fmt.Errorf("text contains disallowed content: %s", strings.Join(verdict.Categories, ", "))
+}
+
+// NewModeratedField creates a ModeratedField checking the texts with the moderator, use
+// moderation.Combine to apply several moderators
+func NewModeratedField(moderator moderation.Moderator) *ModeratedField {
+	return &ModeratedField{moderator: moderator, action: ModerationReject, mask: '*'}
+}
@@ -0,0 +1,92 @@
+package fields
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/moderation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModeratedField(t *testing.T) {
+	queue := moderation.NewInMemoryQueue()
+	tests := []struct {
+		name          string
+		field         *ModeratedField
+		value         string
+		expected      any
+		expectedError string
+		expectedFlags int
+	}{
+		{
+			name:     "clean text",
+			field:    NewModeratedField(moderation.NewProfanity("darn")),
+			value:    "hello",
+			expected: "hello",
+		},
+		{
+			name:          "reject",
+			field:         NewModeratedField(moderation.NewProfanity("darn")),
+			value:         "darn it",
+			expectedError: "text contains disallowed content: profanity",
+		},
+		{
+			name:          "flag",
+			field:         NewModeratedField(moderation.NewProfanity("darn")).WithAction(ModerationFlag).WithQueue(queue),
+			value:         "darn it",
+			expected:      "darn it",
+			expectedFlags: 1,
+		},
+		{
+			name:     "mask",
+			field:    NewModeratedField(moderation.NewProfanity("darn")).WithAction(ModerationMask).WithMask('#'),
+			value:    "darn it",
+			expected: "#### it",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			field := NewField[struct{}]("bio")
+			tt.field.Field()(field)
+
+			// when
+			value, err := field.ToInternalValue(map[string]any{"bio": tt.value}, nil)
+
+			// then
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+			assert.Len(t, queue.Pop(), tt.expectedFlags)
+		})
+	}
+}
+
+func TestModeratedFieldFlagsTextsAfterTheObjectIsSaved(t *testing.T) {
+	// given
+	queue := moderation.NewInMemoryQueue()
+	field := NewField[struct{}]("bio")
+	NewModeratedField(moderation.NewProfanity("darn")).WithAction(ModerationFlag).WithQueue(queue).Field()(field)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("POST", "/profiles", nil)
+	grfctx.DeferUntilSaved(ctx)
+
+	// when
+	value, err := field.ToInternalValue(map[string]any{"bio": "darn it"}, ctx)
+	flaggedBeforeSaving := queue.Pop()
+	runErr := grfctx.RunOnSaved(ctx)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "darn it", value)
+	assert.Empty(t, flaggedBeforeSaving)
+	assert.NoError(t, runErr)
+	flags := queue.Pop()
+	assert.Len(t, flags, 1)
+	assert.Equal(t, "/profiles", flags[0].Path)
+}
@@ -0,0 +1,36 @@
+package filters
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// FieldsQueryParam is the query parameter holding the comma separated fields of sparse responses,
+// for example `?fields=id,name`
+const FieldsQueryParam = "fields"
+
+// ParseFields parses the fields requested by the client, returning nil if there are none. Fields
+// not in allowed result in a serializers.ValidationError.
+func ParseFields(raw string, allowed []string) ([]string, error) {
+	requested := []string{}
+	for _, field := range strings.Split(raw, ",") {
+		if field = strings.TrimSpace(field); field != "" && !slices.Contains(requested, field) {
+			requested = append(requested, field)
+		}
+	}
+	if len(requested) == 0 {
+		return nil, nil
+	}
+	unknown := []string{}
+	for _, field := range requested {
+		if !slices.Contains(allowed, field) {
+			unknown = append(unknown, "unknown field: "+field)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, &serializers.ValidationError{FieldErrors: map[string][]string{FieldsQueryParam: unknown}}
+	}
+	return requested, nil
+}
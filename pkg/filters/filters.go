@@ -0,0 +1,256 @@
+// Package filters parses the query parameters of list requests, like `?name=John` or
+// `?age__gte=18`, into predicates, which the query drivers translate to their queries. The
+// filterable fields and their operators are declared on the viewsets using WithFilterField.
+package filters
+
+import (
+	"cmp"
+	"errors"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// Operator compares the field with the value of the query parameter, it's appended to the field name
+// after a double underscore, for example `age__gte`
+type Operator = grfctx.Operator
+
+const (
+	Exact      = grfctx.OperatorExact
+	Gt         = grfctx.OperatorGt
+	Gte        = grfctx.OperatorGte
+	Lt         = grfctx.OperatorLt
+	Lte        = grfctx.OperatorLte
+	Contains   = grfctx.OperatorContains
+	IContains  = grfctx.OperatorIContains
+	StartsWith = grfctx.OperatorStartsWith
+	// In accepts comma separated values, for example `?status__in=draft,published`
+	In = grfctx.OperatorIn
+	// IsNull accepts `true` or `false`
+	IsNull = grfctx.OperatorIsNull
+)
+
+// The range operators are translated to the comparison operators before they are passed to the
+// query drivers
+const (
+	// Between accepts the inclusive lower and upper bounds separated by a comma, for example
+	// `?price__between=10,20`. Either of them may be omitted, like in `?price__between=10,`.
+	Between Operator = "between"
+	// After matches the values greater than the bound, for example `?created_at__after=2024-01-01`
+	After Operator = "after"
+	// Before matches the values less than the bound
+	Before Operator = "before"
+)
+
+var operators = []Operator{Exact, Gt, Gte, Lt, Lte, Contains, IContains, StartsWith, In, IsNull, Between, After, Before}
+
+// Fields maps the filterable fields to the operators allowed for them
+type Fields map[string][]Operator
+
+// Parse reads the predicates from the query parameters of the request. The values are converted to
+// the types of the model fields, invalid values and operators not allowed for the field result in
+// a serializers.ValidationError. Query parameters of other fields are ignored.
+func Parse[Model any](ctx *gin.Context, fields Fields) ([]grfctx.Predicate, error) {
+	fieldTypes := FieldTypes[Model]()
+	predicates := []grfctx.Predicate{}
+	validationErr := &serializers.ValidationError{FieldErrors: map[string][]string{}}
+	for param, values := range grfctx.QueryValues(ctx) {
+		field, operator := splitParam(param)
+		allowed, filterable := fields[field]
+		if !filterable || len(values) == 0 {
+			continue
+		}
+		if len(allowed) == 0 {
+			allowed = []Operator{Exact}
+		}
+		if !slices.Contains(allowed, operator) {
+			validationErr.FieldErrors[param] = []string{"the " + string(operator) + " operator is not allowed"}
+			continue
+		}
+		fieldPredicates, convertErr := operatorPredicates(fieldTypes[field], field, operator, values[len(values)-1])
+		if convertErr != nil {
+			validationErr.FieldErrors[param] = []string{convertErr.Error()}
+			continue
+		}
+		predicates = append(predicates, fieldPredicates...)
+	}
+	if len(validationErr.FieldErrors) > 0 {
+		return nil, validationErr
+	}
+	return predicates, nil
+}
+
+// FieldTypes returns the types of the model fields by their names
+func FieldTypes[Model any]() map[string]reflect.Type {
+	var m Model
+	types := map[string]reflect.Type{}
+	for _, field := range reflect.VisibleFields(reflect.TypeOf(m)) {
+		if name, included := models.FieldName(field); included && !field.Anonymous {
+			types[name] = field.Type
+		}
+	}
+	return types
+}
+
+// splitParam splits the query parameter into the field and the operator, which is exact if the
+// parameter doesn't end with a known one
+func splitParam(param string) (string, Operator) {
+	field, suffix, found := cutLast(param, "__")
+	if found && slices.Contains(operators, Operator(suffix)) {
+		return field, Operator(suffix)
+	}
+	return param, Exact
+}
+
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
+
+// operatorPredicates converts the value of the query parameter to the predicates of the field, the
+// range operators are translated to the comparison ones
+func operatorPredicates(fieldType reflect.Type, field string, operator Operator, raw string) ([]grfctx.Predicate, error) {
+	switch operator {
+	case After, Before:
+		bound, convertErr := Convert(fieldType, raw)
+		if convertErr != nil {
+			return nil, convertErr
+		}
+		comparison := Gt
+		if operator == Before {
+			comparison = Lt
+		}
+		return []grfctx.Predicate{{Field: field, Operator: comparison, Value: bound}}, nil
+	case Between:
+		return betweenPredicates(fieldType, field, raw)
+	}
+	value, convertErr := operatorValue(fieldType, operator, raw)
+	if convertErr != nil {
+		return nil, convertErr
+	}
+	return []grfctx.Predicate{{Field: field, Operator: operator, Value: value}}, nil
+}
+
+func betweenPredicates(fieldType reflect.Type, field, raw string) ([]grfctx.Predicate, error) {
+	rawLower, rawUpper, found := strings.Cut(raw, ",")
+	if !found || (rawLower == "" && rawUpper == "") {
+		return nil, errors.New("expected the lower and upper bounds separated by a comma")
+	}
+	predicates := []grfctx.Predicate{}
+	var lower, upper any
+	if rawLower != "" {
+		var convertErr error
+		if lower, convertErr = Convert(fieldType, rawLower); convertErr != nil {
+			return nil, convertErr
+		}
+		predicates = append(predicates, grfctx.Predicate{Field: field, Operator: Gte, Value: lower})
+	}
+	if rawUpper != "" {
+		var convertErr error
+		if upper, convertErr = Convert(fieldType, rawUpper); convertErr != nil {
+			return nil, convertErr
+		}
+		predicates = append(predicates, grfctx.Predicate{Field: field, Operator: Lte, Value: upper})
+	}
+	if lower != nil && upper != nil && compareBounds(lower, upper) > 0 {
+		return nil, errors.New("the lower bound is greater than the upper bound")
+	}
+	return predicates, nil
+}
+
+// compareBounds compares the bounds converted by Convert, both of the same type
+func compareBounds(lower, upper any) int {
+	switch typed := lower.(type) {
+	case int64:
+		return cmp.Compare(typed, upper.(int64))
+	case uint64:
+		return cmp.Compare(typed, upper.(uint64))
+	case float64:
+		return cmp.Compare(typed, upper.(float64))
+	case time.Time:
+		return typed.Compare(upper.(time.Time))
+	case string:
+		return strings.Compare(typed, upper.(string))
+	}
+	return 0
+}
+
+func operatorValue(fieldType reflect.Type, operator Operator, raw string) (any, error) {
+	switch operator {
+	case Contains, IContains, StartsWith:
+		return raw, nil
+	case IsNull:
+		isNull, parseErr := strconv.ParseBool(raw)
+		if parseErr != nil {
+			return nil, errors.New("expected true or false")
+		}
+		return isNull, nil
+	case In:
+		values := []any{}
+		for _, rawValue := range strings.Split(raw, ",") {
+			value, convertErr := Convert(fieldType, rawValue)
+			if convertErr != nil {
+				return nil, convertErr
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+	return Convert(fieldType, raw)
+}
+
+// Convert converts the value of a query parameter to the type of the field: numbers, booleans and
+// times, in RFC3339 or 2006-01-02 format, are parsed, other values are kept as strings
+func Convert(fieldType reflect.Type, raw string) (any, error) {
+	if fieldType == nil {
+		return raw, nil
+	}
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType == reflect.TypeOf(time.Time{}) {
+		for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+			if parsed, parseErr := time.Parse(layout, raw); parseErr == nil {
+				return parsed, nil
+			}
+		}
+		return nil, errors.New("expected a time in RFC3339 or YYYY-MM-DD format")
+	}
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, parseErr := strconv.ParseInt(raw, 10, 64)
+		if parseErr != nil {
+			return nil, errors.New("expected an integer")
+		}
+		return value, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, parseErr := strconv.ParseUint(raw, 10, 64)
+		if parseErr != nil {
+			return nil, errors.New("expected a non-negative integer")
+		}
+		return value, nil
+	case reflect.Float32, reflect.Float64:
+		value, parseErr := strconv.ParseFloat(raw, 64)
+		if parseErr != nil {
+			return nil, errors.New("expected a number")
+		}
+		return value, nil
+	case reflect.Bool:
+		value, parseErr := strconv.ParseBool(raw)
+		if parseErr != nil {
+			return nil, errors.New("expected true or false")
+		}
+		return value, nil
+	}
+	return raw, nil
+}
@@ -0,0 +1,111 @@
+package filters
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+type person struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Age       int       `json:"age"`
+	CreatedAt time.Time `json:"created_at"`
+	Nickname  *string   `json:"nickname"`
+}
+
+func TestParse(t *testing.T) {
+	fields := Fields{
+		"name":       {Exact, IContains},
+		"age":        {Gte, Lt, In, Between},
+		"created_at": {Lt, After, Before},
+		"nickname":   {IsNull},
+		"id":         nil,
+	}
+	tests := []struct {
+		name               string
+		query              string
+		expectedPredicates []grfctx.Predicate
+		expectedErrors     map[string][]string
+	}{
+		{
+			name:               "exact",
+			query:              "name=John&id=1",
+			expectedPredicates: []grfctx.Predicate{{Field: "name", Operator: Exact, Value: "John"}, {Field: "id", Operator: Exact, Value: uint64(1)}},
+		},
+		{
+			name:               "operators",
+			query:              "age__gte=18&name__icontains=jo&nickname__isnull=true",
+			expectedPredicates: []grfctx.Predicate{{Field: "age", Operator: Gte, Value: int64(18)}, {Field: "name", Operator: IContains, Value: "jo"}, {Field: "nickname", Operator: IsNull, Value: true}},
+		},
+		{
+			name:               "in and time",
+			query:              "age__in=18,21&created_at__lt=2024-01-02",
+			expectedPredicates: []grfctx.Predicate{{Field: "age", Operator: In, Value: []any{int64(18), int64(21)}}, {Field: "created_at", Operator: Lt, Value: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}},
+		},
+		{
+			name:  "ranges",
+			query: "age__between=18,65&created_at__after=2024-01-01&created_at__before=2024-02-01",
+			expectedPredicates: []grfctx.Predicate{
+				{Field: "age", Operator: Gte, Value: int64(18)},
+				{Field: "age", Operator: Lte, Value: int64(65)},
+				{Field: "created_at", Operator: Gt, Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+				{Field: "created_at", Operator: Lt, Value: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:               "open range",
+			query:              "age__between=18,",
+			expectedPredicates: []grfctx.Predicate{{Field: "age", Operator: Gte, Value: int64(18)}},
+		},
+		{
+			name:  "invalid ranges",
+			query: "age__between=65,18&created_at__after=yesterday&created_at__before=",
+			expectedErrors: map[string][]string{
+				"age__between":       {"the lower bound is greater than the upper bound"},
+				"created_at__after":  {"expected a time in RFC3339 or YYYY-MM-DD format"},
+				"created_at__before": {"expected a time in RFC3339 or YYYY-MM-DD format"},
+			},
+		},
+		{
+			name:           "range without bounds",
+			query:          "age__between=18",
+			expectedErrors: map[string][]string{"age__between": {"expected the lower and upper bounds separated by a comma"}},
+		},
+		{
+			name:               "unknown params",
+			query:              "page=2&email=john@example.com",
+			expectedPredicates: []grfctx.Predicate{},
+		},
+		{
+			name:           "invalid",
+			query:          "age__gte=old&name__gt=A&id__in=1",
+			expectedErrors: map[string][]string{"age__gte": {"expected an integer"}, "name__gt": {"the gt operator is not allowed"}, "id__in": {"the in operator is not allowed"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest("GET", "/people?"+tt.query, nil)
+
+			// when
+			predicates, err := Parse[person](ctx, fields)
+
+			// then
+			if tt.expectedErrors != nil {
+				var validationErr *serializers.ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.expectedErrors, validationErr.FieldErrors)
+				return
+			}
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tt.expectedPredicates, predicates)
+		})
+	}
+}
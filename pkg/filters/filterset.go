@@ -0,0 +1,93 @@
+package filters
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// PredicatesFunc builds the predicates of a custom filter from the value of its query parameter.
+// Returned errors are reported as the validation errors of the parameter.
+type PredicatesFunc func(ctx *gin.Context, raw string) ([]grfctx.Predicate, error)
+
+// Filter declares a query parameter of a FilterSet
+type Filter struct {
+	// Param is the name of the query parameter, for example `min_price`
+	Param string
+	// Field is the filtered field of the model, Param if empty
+	Field string
+	// Operator compares the field with the value of the parameter, exact if empty
+	Operator Operator
+	// Predicates replaces Field and Operator for custom filters, for example `in_stock=true`
+	// translated to `stock > 0`
+	Predicates PredicatesFunc
+}
+
+// FilterSet declares the filters of the lists of a model, like django-filter's FilterSet. Unlike
+// the fields declared using ViewSet.WithFilterField, the query parameters are named independently
+// of the fields, so the same field may be filtered by several parameters, and custom filters can
+// translate the parameters to any predicates. FilterSets can be shared by the viewsets and tested
+// on their own using Parse.
+type FilterSet[Model any] struct {
+	filters []Filter
+}
+
+// NewFilterSet creates a FilterSet with the filters
+func NewFilterSet[Model any](filters ...Filter) *FilterSet[Model] {
+	return &FilterSet[Model]{filters: filters}
+}
+
+// WithFilter adds a filter comparing the field with the value of the parameter using the operator
+func (s *FilterSet[Model]) WithFilter(param, field string, operator Operator) *FilterSet[Model] {
+	s.filters = append(s.filters, Filter{Param: param, Field: field, Operator: operator})
+	return s
+}
+
+// WithCustomFilter adds a filter building the predicates of the parameter using the function
+func (s *FilterSet[Model]) WithCustomFilter(param string, predicates PredicatesFunc) *FilterSet[Model] {
+	s.filters = append(s.filters, Filter{Param: param, Predicates: predicates})
+	return s
+}
+
+// Parse reads the predicates of the filters from the query parameters of the request, the values
+// are converted to the types of the model fields, like by the Parse function. Invalid values result
+// in a serializers.ValidationError, holding the errors of all the parameters.
+func (s *FilterSet[Model]) Parse(ctx *gin.Context) ([]grfctx.Predicate, error) {
+	fieldTypes := FieldTypes[Model]()
+	query := grfctx.QueryValues(ctx)
+	predicates := []grfctx.Predicate{}
+	validationErr := &serializers.ValidationError{FieldErrors: map[string][]string{}}
+	for _, filter := range s.filters {
+		values, ok := query[filter.Param]
+		if !ok || len(values) == 0 {
+			continue
+		}
+		raw := values[len(values)-1]
+		if filter.Predicates != nil {
+			custom, customErr := filter.Predicates(ctx, raw)
+			if customErr != nil {
+				validationErr.FieldErrors[filter.Param] = []string{customErr.Error()}
+				continue
+			}
+			predicates = append(predicates, custom...)
+			continue
+		}
+		field, operator := filter.Field, filter.Operator
+		if field == "" {
+			field = filter.Param
+		}
+		if operator == "" {
+			operator = Exact
+		}
+		fieldPredicates, convertErr := operatorPredicates(fieldTypes[field], field, operator, raw)
+		if convertErr != nil {
+			validationErr.FieldErrors[filter.Param] = []string{convertErr.Error()}
+			continue
+		}
+		predicates = append(predicates, fieldPredicates...)
+	}
+	if len(validationErr.FieldErrors) > 0 {
+		return nil, validationErr
+	}
+	return predicates, nil
+}
@@ -0,0 +1,77 @@
+package filters
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterSetParse(t *testing.T) {
+	filterSet := NewFilterSet[person](
+		Filter{Param: "name"},
+		Filter{Param: "older_than", Field: "age", Operator: Gt},
+	).WithFilter("created_before", "created_at", Lt).WithCustomFilter(
+		"adult", func(_ *gin.Context, raw string) ([]grfctx.Predicate, error) {
+			switch raw {
+			case "true":
+				return []grfctx.Predicate{{Field: "age", Operator: Gte, Value: 18}}, nil
+			case "false":
+				return []grfctx.Predicate{{Field: "age", Operator: Lt, Value: 18}}, nil
+			}
+			return nil, errors.New("expected true or false")
+		},
+	)
+	tests := []struct {
+		name               string
+		query              string
+		expectedPredicates []grfctx.Predicate
+		expectedErrors     map[string][]string
+	}{
+		{
+			name:               "declared filters",
+			query:              "name=John&older_than=30&created_before=2024-01-02",
+			expectedPredicates: []grfctx.Predicate{{Field: "name", Operator: Exact, Value: "John"}, {Field: "age", Operator: Gt, Value: int64(30)}, {Field: "created_at", Operator: Lt, Value: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}},
+		},
+		{
+			name:               "custom filter",
+			query:              "adult=false",
+			expectedPredicates: []grfctx.Predicate{{Field: "age", Operator: Lt, Value: 18}},
+		},
+		{
+			name:               "undeclared params",
+			query:              "age=30&name__icontains=jo",
+			expectedPredicates: []grfctx.Predicate{},
+		},
+		{
+			name:           "invalid",
+			query:          "older_than=old&adult=maybe",
+			expectedErrors: map[string][]string{"older_than": {"expected an integer"}, "adult": {"expected true or false"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest("GET", "/people?"+tt.query, nil)
+
+			// when
+			predicates, err := filterSet.Parse(ctx)
+
+			// then
+			if tt.expectedErrors != nil {
+				var validationErr *serializers.ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.expectedErrors, validationErr.FieldErrors)
+				return
+			}
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tt.expectedPredicates, predicates)
+		})
+	}
+}
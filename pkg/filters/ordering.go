@@ -0,0 +1,24 @@
+package filters
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/glothriel/grf/pkg/grfctx"
+)
+
+// OrderingQueryParam is the query parameter holding the comma separated fields ordering the list,
+// descending if prefixed with `-`, for example `?ordering=name,-created_at`
+const OrderingQueryParam = "ordering"
+
+// ParseOrdering parses the ordering, like `name,-created_at`, skipping the fields not allowed
+func ParseOrdering(raw string, allowed []string) []grfctx.OrderBy {
+	ordering := []grfctx.OrderBy{}
+	for _, term := range strings.Split(raw, ",") {
+		field, descending := strings.CutPrefix(strings.TrimSpace(term), "-")
+		if field != "" && slices.Contains(allowed, field) {
+			ordering = append(ordering, grfctx.OrderBy{Field: field, Descending: descending})
+		}
+	}
+	return ordering
+}
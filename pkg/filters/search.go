@@ -0,0 +1,21 @@
+package filters
+
+import (
+	"strings"
+
+	"github.com/glothriel/grf/pkg/grfctx"
+)
+
+// SearchQueryParam is the query parameter holding the whitespace separated search terms, for
+// example `?search=john doe`
+const SearchQueryParam = "search"
+
+// ParseSearch parses the search terms, returning false if there are none. The objects have to
+// contain every term in any of the fields, ignoring the case.
+func ParseSearch(raw string, fields []string) (grfctx.Search, bool) {
+	terms := strings.Fields(raw)
+	if len(terms) == 0 || len(fields) == 0 {
+		return grfctx.Search{}, false
+	}
+	return grfctx.Search{Fields: fields, Terms: terms}, true
+}
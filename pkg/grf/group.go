@@ -0,0 +1,113 @@
+package grf
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/authentication"
+	"github.com/glothriel/grf/pkg/throttling"
+	"github.com/glothriel/grf/pkg/views"
+	"github.com/sirupsen/logrus"
+)
+
+// Registrable is anything, that can be registered on a gin router, for example views.ViewSet
+type Registrable interface {
+	Register(gin.IRouter)
+}
+
+// routerRegistrable is implemented by registrables, that can be registered on any views.Router
+type routerRegistrable interface {
+	RegisterOn(views.Router)
+}
+
+// groupConfigurable is implemented by registrables, that accept the settings shared by the group
+type groupConfigurable interface {
+	ApplyGroupSettings(views.GroupSettings)
+}
+
+// APIGroup registers multiple viewsets under a common path, declaring the settings shared by
+// them once. Settings configured on the viewsets themselves take precedence over the group's.
+type APIGroup struct {
+	path         string
+	settings     views.GroupSettings
+	registrables []Registrable
+}
+
+// WithAuthentication sets the authentication used by all the viewsets in the group
+func (g *APIGroup) WithAuthentication(a authentication.Authentication) *APIGroup {
+	g.settings.Authentication = a
+	return g
+}
+
+// WithPermissions requires the authenticated requests to all the viewsets in the group to have all
+// the permissions
+func (g *APIGroup) WithPermissions(permissions ...authentication.Permission) *APIGroup {
+	g.settings.Permissions = permissions
+	return g
+}
+
+// WithStrictMode makes the views and the viewsets of the group panic when mounted, if they lack the
+// authentication, the permissions or the pagination of the list, unless they opt out of the checks
+// using WithoutStrictChecks, see views.GroupSettings.Strict
+func (g *APIGroup) WithStrictMode() *APIGroup {
+	g.settings.Strict = true
+	return g
+}
+
+// WithThrottle limits the rate of the requests to all the viewsets in the group
+func (g *APIGroup) WithThrottle(throttles ...throttling.Throttle) *APIGroup {
+	g.settings.Throttles = throttles
+	return g
+}
+
+// WithThrottleQueue makes throttled write requests to all the viewsets in the group wait in the
+// shared queue, instead of being rejected immediately
+func (g *APIGroup) WithThrottleQueue(queue *throttling.Queue) *APIGroup {
+	g.settings.ThrottleQueue = queue
+	return g
+}
+
+// WithMiddleware adds grf middleware to all the viewsets in the group
+func (g *APIGroup) WithMiddleware(middleware ...views.Middleware) *APIGroup {
+	g.settings.Middleware = append(g.settings.Middleware, middleware...)
+	return g
+}
+
+// Register adds the viewsets to the group, they are registered on the router by Mount
+func (g *APIGroup) Register(registrables ...Registrable) *APIGroup {
+	g.registrables = append(g.registrables, registrables...)
+	return g
+}
+
+// Mount applies the group settings to the viewsets and registers them on the router
+func (g *APIGroup) Mount(r gin.IRouter) {
+	rg := r.Group(g.path)
+	for _, registrable := range g.registrables {
+		if configurable, ok := registrable.(groupConfigurable); ok {
+			configurable.ApplyGroupSettings(g.settings)
+		}
+		registrable.Register(rg)
+	}
+}
+
+// MountOn applies the group settings to the viewsets and registers them on any views.Router, like
+// the ones of the adapters package. The registrables have to implement RegisterOn, like the views
+// and the viewsets do.
+func (g *APIGroup) MountOn(r views.Router) {
+	rg := views.PrefixedRouter(r, g.path)
+	for _, registrable := range g.registrables {
+		if configurable, ok := registrable.(groupConfigurable); ok {
+			configurable.ApplyGroupSettings(g.settings)
+		}
+		onRouter, ok := registrable.(routerRegistrable)
+		if !ok {
+			logrus.Panicf("APIGroup: %T can only be registered on gin routers", registrable)
+		}
+		onRouter.RegisterOn(rg)
+	}
+}
+
+func NewAPIGroup(path string) *APIGroup {
+	return &APIGroup{
+		path:         path,
+		registrables: []Registrable{},
+	}
+}
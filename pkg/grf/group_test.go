@@ -0,0 +1,174 @@
+package grf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/authentication"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/throttling"
+	"github.com/glothriel/grf/pkg/views"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type product struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type category struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type headerAuthentication struct{}
+
+func (a *headerAuthentication) Authenticate(ctx *gin.Context) (bool, error) {
+	return ctx.GetHeader("Authorization") == "secret", nil
+}
+
+func get(r *gin.Engine, path string, authorization string) int {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestAPIGroup(t *testing.T) {
+	// given
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	NewAPIGroup("/api").WithAuthentication(
+		&headerAuthentication{},
+	).WithThrottle(
+		throttling.NewRateThrottle(2, time.Hour),
+	).Register(
+		views.NewModelViewSet[product]("/products", queries.InMemory[product]()),
+		views.NewModelViewSet[category]("/categories", queries.InMemory[category]()).WithAuthentication(
+			&authentication.AnonymousUserAuthentication{},
+		),
+	).Mount(r)
+
+	// when
+	unauthenticated := get(r, "/api/products", "")
+	authenticated := get(r, "/api/products", "secret")
+	anonymous := get(r, "/api/categories", "")
+	throttled := get(r, "/api/products", "secret")
+
+	// then
+	assert.Equal(t, http.StatusUnauthorized, unauthenticated)
+	assert.Equal(t, http.StatusOK, authenticated)
+	assert.Equal(t, http.StatusOK, anonymous)
+	assert.Equal(t, http.StatusTooManyRequests, throttled)
+}
+
+func TestAPIGroupPermissions(t *testing.T) {
+	// given
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	NewAPIGroup("/api").WithAuthentication(&headerAuthentication{}).WithPermissions(authentication.ReadOnly()).Register(
+		views.NewModelViewSet[product]("/products", queries.InMemory[product]()),
+		views.NewModelViewSet[category]("/categories", queries.InMemory[category]()).
+			WithPermissions(authentication.AllowAny()),
+		views.NewView("/health", queries.InMemory[product]()).Post(func(ctx *gin.Context) {
+			ctx.Status(http.StatusNoContent)
+		}),
+	).Mount(r)
+
+	// when
+	read := get(r, "/api/products", "secret")
+	denied := request(r, http.MethodPost, "/api/products", "secret")
+	allowed := request(r, http.MethodPost, "/api/categories", "secret")
+	unauthenticated := request(r, http.MethodPost, "/api/categories", "")
+	deniedView := request(r, http.MethodPost, "/api/health", "secret")
+
+	// then
+	assert.Equal(t, http.StatusOK, read)
+	assert.Equal(t, http.StatusForbidden, denied)
+	assert.Equal(t, http.StatusCreated, allowed)
+	assert.Equal(t, http.StatusUnauthorized, unauthenticated)
+	assert.Equal(t, http.StatusForbidden, deniedView)
+}
+
+func request(r *gin.Engine, method, path, authorization string) int {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, strings.NewReader(`{"name": "new"}`))
+	req.Header.Set("Authorization", authorization)
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestStrictMode(t *testing.T) {
+	// given
+	group := func(registrables ...Registrable) *APIGroup {
+		return NewAPIGroup("/api").WithStrictMode().
+			WithAuthentication(&headerAuthentication{}).
+			WithPermissions(authentication.AllowAny()).
+			Register(registrables...)
+	}
+	mount := func(g *APIGroup) func() {
+		return func() {
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			g.Mount(r)
+		}
+	}
+	products := func() *views.ViewSet[product] {
+		return views.NewModelViewSet[product]("/products", queries.InMemory[product]())
+	}
+
+	// when
+	unauthenticated := mount(NewAPIGroup("/api").WithStrictMode().WithPermissions(authentication.AllowAny()).Register(
+		products().WithMandatoryPagination(),
+	))
+	withoutPermissions := mount(NewAPIGroup("/api").WithStrictMode().WithAuthentication(&headerAuthentication{}).Register(
+		products().WithMandatoryPagination(),
+	))
+	unpaginated := mount(group(products()))
+	anonymousView := mount(group(
+		views.NewView("/health", queries.InMemory[product]()).
+			WithAuthentication(&authentication.AnonymousUserAuthentication{}).
+			Get(func(ctx *gin.Context) {}),
+	))
+	compliant := mount(group(
+		products().WithMandatoryPagination(),
+		views.NewView("/health", queries.InMemory[product]()).Get(func(ctx *gin.Context) {}),
+	))
+	optedOut := mount(group(
+		views.NewModelViewSet[category]("/categories", queries.InMemory[category]()).
+			WithAuthentication(&authentication.AnonymousUserAuthentication{}).
+			WithoutStrictChecks(views.StrictAuthentication, views.StrictPagination),
+	))
+	outsideGroup := func() {
+		_, r := gin.CreateTestContext(httptest.NewRecorder())
+		products().Register(r.Group("/api"))
+	}
+
+	// then
+	assert.Equal(t, "Strict mode forbids registering `/products`: it does not set the authentication, "+
+		"use WithoutStrictChecks to opt out deliberately", panicMessage(unauthenticated))
+	assert.Equal(t, "Strict mode forbids registering `/products`: it does not set the permissions, "+
+		"use WithoutStrictChecks to opt out deliberately", panicMessage(withoutPermissions))
+	assert.Equal(t, "Strict mode forbids registering `/products`: its list action is not paginated, "+
+		"use WithoutStrictChecks to opt out deliberately", panicMessage(unpaginated))
+	assert.Equal(t, "Strict mode forbids registering `/health`: it does not set the authentication, "+
+		"use WithoutStrictChecks to opt out deliberately", panicMessage(anonymousView))
+	assert.NotPanics(t, compliant)
+	assert.NotPanics(t, optedOut)
+	assert.NotPanics(t, outsideGroup)
+}
+
+func panicMessage(f func()) (message string) {
+	defer func() {
+		if entry, ok := recover().(*logrus.Entry); ok {
+			message = entry.Message
+		}
+	}()
+	f()
+	return ""
+}
@@ -0,0 +1,504 @@
+// Package grfctx exposes metadata about the request being handled by a GRF view, so middleware,
+// serializers and permissions can branch on it without parsing the HTTP method and path themselves.
+package grfctx
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Action is the viewset action handling the request
+type Action int
+
+const (
+	ActionCreate Action = iota
+	ActionUpdate
+	ActionDestroy
+	ActionList
+	ActionRetrieve
+	ActionCustom
+	ActionPartialUpdate
+	// ActionUnknown is returned when the request is not handled by a GRF view
+	ActionUnknown Action = -1
+)
+
+func (a Action) String() string {
+	switch a {
+	case ActionCreate:
+		return "create"
+	case ActionUpdate:
+		return "update"
+	case ActionDestroy:
+		return "destroy"
+	case ActionList:
+		return "list"
+	case ActionRetrieve:
+		return "retrieve"
+	case ActionCustom:
+		return "custom"
+	case ActionPartialUpdate:
+		return "partial_update"
+	}
+	return "unknown"
+}
+
+// ViewSettings describes the view handling the request
+type ViewSettings struct {
+	// Path is the path the viewset was registered with, for example `/products`
+	Path string
+	// IDParam is the name of the path param holding the ID on detail routes
+	IDParam string
+	// LookupField is the model field, that the value of IDParam is matched against
+	LookupField string
+	// Detail is true for routes operating on a single element
+	Detail bool
+	// Actions lists the standard actions enabled on the viewset
+	Actions []Action
+	// PageSize is the default page size of the list, replacing the paginator's one if positive
+	PageSize int
+	// MaxPageSize limits the page sizes requested by the clients, replacing the paginator's limit
+	// if positive
+	MaxPageSize int
+}
+
+// Metadata describes the request being handled by a GRF view
+type Metadata struct {
+	Action Action
+	// CustomAction is the relative path of the extra action, set only for ActionCustom
+	CustomAction string
+	ModelName    string
+	View         ViewSettings
+}
+
+const metadataCtxKey = "grf.metadata"
+
+// Set stores the metadata in the context
+func Set(ctx *gin.Context, metadata Metadata) {
+	ctx.Set(metadataCtxKey, metadata)
+}
+
+// Get returns the metadata of the request and false if the request is not handled by a GRF view
+func Get(ctx *gin.Context) (Metadata, bool) {
+	if ctx == nil {
+		return Metadata{Action: ActionUnknown}, false
+	}
+	raw, ok := ctx.Get(metadataCtxKey)
+	if !ok {
+		return Metadata{Action: ActionUnknown}, false
+	}
+	return raw.(Metadata), true
+}
+
+// CurrentAction returns the action handling the request
+func CurrentAction(ctx *gin.Context) Action {
+	metadata, _ := Get(ctx)
+	return metadata.Action
+}
+
+// LookupField returns the model field identifying the objects on detail routes, query drivers
+// should look the objects up using it instead of assuming the `id` field
+func LookupField(ctx *gin.Context) string {
+	metadata, _ := Get(ctx)
+	if metadata.View.LookupField == "" {
+		return "id"
+	}
+	return metadata.View.LookupField
+}
+
+// ModelName returns the name of the model the request operates on
+func ModelName(ctx *gin.Context) string {
+	metadata, _ := Get(ctx)
+	return metadata.ModelName
+}
+
+// View returns the settings of the view handling the request
+func View(ctx *gin.Context) ViewSettings {
+	metadata, _ := Get(ctx)
+	return metadata.View
+}
+
+// ParentScope limits nested resources, like `/authors/:author_id/books`, to the ones belonging to
+// the parent from the URL
+type ParentScope struct {
+	// Field is the model field referencing the parent, for example `author_id`
+	Field string
+	// Value is the parent key from the URL, converted to the type of the field
+	Value any
+}
+
+const parentScopeCtxKey = "grf.parent_scope"
+
+// SetParentScope stores the parent scope of the request
+func SetParentScope(ctx *gin.Context, scope ParentScope) {
+	ctx.Set(parentScopeCtxKey, scope)
+}
+
+// Parent returns the parent scope of a nested resource request, query drivers should only list,
+// retrieve, update and destroy the objects matching it
+func Parent(ctx *gin.Context) (ParentScope, bool) {
+	if ctx == nil {
+		return ParentScope{}, false
+	}
+	raw, ok := ctx.Get(parentScopeCtxKey)
+	if !ok {
+		return ParentScope{}, false
+	}
+	scope, ok := raw.(ParentScope)
+	return scope, ok
+}
+
+// Window is the slice of the listed objects requested by the pagination
+type Window struct {
+	Offset int
+	Limit  int
+	// Keyset is set by cursor based paginations, which page the objects by the values of a field
+	// instead of skipping Offset objects
+	Keyset *Keyset
+	// Random requests Limit objects picked at random, instead of skipping Offset objects
+	Random bool
+}
+
+// Keyset orders the listed objects by the field and limits them to the ones after the cursor
+type Keyset struct {
+	Field      string
+	Descending bool
+	// After is the value of the field of the object preceding the window, nil for the first window
+	After any
+}
+
+const windowCtxKey = "grf.window"
+
+// SetWindow stores the window of the list request
+func SetWindow(ctx *gin.Context, window Window) {
+	ctx.Set(windowCtxKey, window)
+}
+
+// CurrentWindow returns the window of a paginated list request, query drivers should skip Offset
+// objects, or the objects up to the Keyset cursor, and list at most Limit of them
+func CurrentWindow(ctx *gin.Context) (Window, bool) {
+	if ctx == nil {
+		return Window{}, false
+	}
+	raw, ok := ctx.Get(windowCtxKey)
+	if !ok {
+		return Window{}, false
+	}
+	window, ok := raw.(Window)
+	return window, ok
+}
+
+// Operator compares the field of a Predicate with its value
+type Operator string
+
+const (
+	OperatorExact      Operator = "exact"
+	OperatorGt         Operator = "gt"
+	OperatorGte        Operator = "gte"
+	OperatorLt         Operator = "lt"
+	OperatorLte        Operator = "lte"
+	OperatorContains   Operator = "contains"
+	OperatorIContains  Operator = "icontains"
+	OperatorStartsWith Operator = "startswith"
+	// OperatorIn matches the fields equal to any of the values, the Value is a []any
+	OperatorIn Operator = "in"
+	// OperatorIsNull matches null fields if the Value is true, and not null ones otherwise
+	OperatorIsNull Operator = "isnull"
+)
+
+// Predicate limits the listed objects to the ones with the field matching the value
+type Predicate struct {
+	Field    string
+	Operator Operator
+	Value    any
+}
+
+const predicatesCtxKey = "grf.predicates"
+
+// AddPredicates adds predicates to the list request, for example parsed from the query parameters
+func AddPredicates(ctx *gin.Context, predicates ...Predicate) {
+	ctx.Set(predicatesCtxKey, append(slices.Clip(Predicates(ctx)), predicates...))
+}
+
+// Predicates returns the predicates of the list request, query drivers should only list, count and
+// check the existence of the objects matching all of them
+func Predicates(ctx *gin.Context) []Predicate {
+	if ctx == nil {
+		return nil
+	}
+	raw, ok := ctx.Get(predicatesCtxKey)
+	if !ok {
+		return nil
+	}
+	predicates, _ := raw.([]Predicate)
+	return predicates
+}
+
+const storedCtxKey = "grf.stored"
+
+// SetStored stores the object being updated, as retrieved before the request body is converted, so
+// the serializers can validate the updates against the whole object
+func SetStored(ctx *gin.Context, stored map[string]any) {
+	ctx.Set(storedCtxKey, stored)
+}
+
+// Stored returns the object being updated, it's not set when creating the objects
+func Stored(ctx *gin.Context) (map[string]any, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	raw, _ := ctx.Get(storedCtxKey)
+	stored, ok := raw.(map[string]any)
+	return stored, ok && stored != nil
+}
+
+const updatePreconditionCtxKey = "grf.update_precondition"
+
+// SetUpdatePrecondition makes the updates conditional on the stored object matching the
+// predicate, for example on its version for optimistic locking, nil removes the precondition
+func SetUpdatePrecondition(ctx *gin.Context, precondition *Predicate) {
+	ctx.Set(updatePreconditionCtxKey, precondition)
+}
+
+// UpdatePrecondition returns the precondition of the update, query drivers should update the
+// object only if it matches it, in the same statement, and return common.ErrPreconditionFailed
+// otherwise
+func UpdatePrecondition(ctx *gin.Context) (Predicate, bool) {
+	if ctx == nil {
+		return Predicate{}, false
+	}
+	raw, _ := ctx.Get(updatePreconditionCtxKey)
+	precondition, ok := raw.(*Predicate)
+	if !ok || precondition == nil {
+		return Predicate{}, false
+	}
+	return *precondition, true
+}
+
+const updatedFieldsCtxKey = "grf.updated_fields"
+
+// SetUpdatedFields makes the updates write the fields even if their new values are zero values,
+// for example the fields cleared by an anonymization policy, nil removes the fields
+func SetUpdatedFields(ctx *gin.Context, fields []string) {
+	ctx.Set(updatedFieldsCtxKey, fields)
+}
+
+// UpdatedFields returns the fields the update has to write explicitly, query drivers skipping the
+// zero values in the updates, like GORM's Updates of structs, should write these fields anyway
+func UpdatedFields(ctx *gin.Context) ([]string, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	raw, _ := ctx.Get(updatedFieldsCtxKey)
+	fields, ok := raw.([]string)
+	return fields, ok && len(fields) > 0
+}
+
+const onSavedCtxKey = "grf.on_saved"
+
+type onSavedCallbacks struct {
+	fns []func() error
+}
+
+// DeferUntilSaved makes the callbacks registered with OnSaved wait for RunOnSaved, the views call
+// it before converting the request body, so the side effects of the conversion, like storing the
+// uploaded files, happen only after the object is saved
+func DeferUntilSaved(ctx *gin.Context) {
+	ctx.Set(onSavedCtxKey, &onSavedCallbacks{})
+}
+
+// OnSaved registers fn to run after the object converted using the ctx is saved, see
+// DeferUntilSaved. Outside of the views it's run immediately, and its error is returned.
+func OnSaved(ctx *gin.Context, fn func() error) error {
+	if ctx != nil {
+		raw, _ := ctx.Get(onSavedCtxKey)
+		if callbacks, ok := raw.(*onSavedCallbacks); ok && callbacks != nil {
+			callbacks.fns = append(callbacks.fns, fn)
+			return nil
+		}
+	}
+	return fn()
+}
+
+// HasOnSaved reports if any callbacks registered with OnSaved are waiting for RunOnSaved
+func HasOnSaved(ctx *gin.Context) bool {
+	raw, _ := ctx.Get(onSavedCtxKey)
+	callbacks, ok := raw.(*onSavedCallbacks)
+	return ok && callbacks != nil && len(callbacks.fns) > 0
+}
+
+// RunOnSaved runs the callbacks registered with OnSaved since DeferUntilSaved, stopping at the first
+// error, the callbacks registered later are run immediately
+func RunOnSaved(ctx *gin.Context) error {
+	raw, _ := ctx.Get(onSavedCtxKey)
+	ctx.Set(onSavedCtxKey, (*onSavedCallbacks)(nil))
+	callbacks, ok := raw.(*onSavedCallbacks)
+	if !ok || callbacks == nil {
+		return nil
+	}
+	for _, fn := range callbacks.fns {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OrderBy orders the listed objects by the field
+type OrderBy struct {
+	Field      string
+	Descending bool
+}
+
+const orderingCtxKey = "grf.ordering"
+
+// SetOrdering stores the ordering of the list request
+func SetOrdering(ctx *gin.Context, ordering []OrderBy) {
+	ctx.Set(orderingCtxKey, ordering)
+}
+
+// Ordering returns the ordering of the list request, query drivers should order the listed objects
+// by the fields, in place of their default ordering
+func Ordering(ctx *gin.Context) []OrderBy {
+	if ctx == nil {
+		return nil
+	}
+	raw, ok := ctx.Get(orderingCtxKey)
+	if !ok {
+		return nil
+	}
+	ordering, _ := raw.([]OrderBy)
+	return ordering
+}
+
+// Search limits the listed objects to the ones containing all the terms, each of them in any of
+// the fields, ignoring the case
+type Search struct {
+	Fields []string
+	Terms  []string
+}
+
+const searchCtxKey = "grf.search"
+
+// SetSearch stores the search of the list request
+func SetSearch(ctx *gin.Context, search Search) {
+	ctx.Set(searchCtxKey, search)
+}
+
+// CurrentSearch returns the search of the list request, query drivers should only list, count and
+// check the existence of the objects matching it, like the predicates
+func CurrentSearch(ctx *gin.Context) (Search, bool) {
+	if ctx == nil {
+		return Search{}, false
+	}
+	raw, ok := ctx.Get(searchCtxKey)
+	if !ok {
+		return Search{}, false
+	}
+	search, ok := raw.(Search)
+	return search, ok
+}
+
+// Locale holds the formatting preferences of the request, used by the fields representing times,
+// amounts of money and translated texts
+type Locale struct {
+	// Language is a BCP 47 language tag, like `en` or `pt-BR`
+	Language string
+	// Timezone is the location the times are represented in, UTC if nil
+	Timezone *time.Location
+	// Currency is an ISO 4217 currency code, like `EUR`
+	Currency string
+}
+
+// Location returns the timezone of the locale, UTC if it's not set
+func (l Locale) Location() *time.Location {
+	if l.Timezone == nil {
+		return time.UTC
+	}
+	return l.Timezone
+}
+
+const localeCtxKey = "grf.locale"
+
+// SetLocale stores the locale of the request
+func SetLocale(ctx *gin.Context, locale Locale) {
+	ctx.Set(localeCtxKey, locale)
+}
+
+// CurrentLocale returns the locale of the request and false if it was not set, for example by
+// locale.Extractor
+func CurrentLocale(ctx *gin.Context) (Locale, bool) {
+	if ctx == nil {
+		return Locale{}, false
+	}
+	raw, ok := ctx.Get(localeCtxKey)
+	if !ok {
+		return Locale{}, false
+	}
+	locale, ok := raw.(Locale)
+	return locale, ok
+}
+
+const queryParamsCtxKey = "grf.query_params"
+
+// SetQueryParams stores the query parameters parsed by the query serializer of the view, so the
+// filtering, the ordering and the pagination read the parsed values, see QueryValues
+func SetQueryParams(ctx *gin.Context, params map[string]any) {
+	ctx.Set(queryParamsCtxKey, params)
+}
+
+// QueryParams returns the query parameters parsed by the query serializer of the view
+func QueryParams(ctx *gin.Context) (map[string]any, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	raw, _ := ctx.Get(queryParamsCtxKey)
+	params, ok := raw.(map[string]any)
+	return params, ok && params != nil
+}
+
+// QueryValues returns the query parameters of the request, with the parsed values of QueryParams
+// replacing the raw ones, so the values normalized or defaulted by the query serializer are used.
+// The times are formatted as RFC 3339 and the arrays become repeated parameters.
+func QueryValues(ctx *gin.Context) url.Values {
+	values := ctx.Request.URL.Query()
+	params, ok := QueryParams(ctx)
+	if !ok {
+		return values
+	}
+	for name, value := range params {
+		if value == nil {
+			continue
+		}
+		if items, isArray := value.([]any); isArray {
+			values[name] = make([]string, 0, len(items))
+			for _, item := range items {
+				values[name] = append(values[name], formatQueryValue(item))
+			}
+			continue
+		}
+		values.Set(name, formatQueryValue(value))
+	}
+	return values
+}
+
+// Query returns the value of the query parameter, like gin's Context.Query, using QueryValues
+func Query(ctx *gin.Context, name string) string {
+	return QueryValues(ctx).Get(name)
+}
+
+func formatQueryValue(value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,194 @@
+package ids
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type uuidGenerator struct{}
+
+func (uuidGenerator) Generate() string {
+	return uuid.New().String()
+}
+
+func (uuidGenerator) Validate(id string) error {
+	if _, parseErr := uuid.Parse(id); parseErr != nil {
+		return invalid("expected an UUID")
+	}
+	return nil
+}
+
+// UUID generates random UUIDs, like BaseModel
+func UUID() Generator {
+	return uuidGenerator{}
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+type ulidGenerator struct{}
+
+func (ulidGenerator) Generate() string {
+	var raw [16]byte
+	milliseconds := uint64(time.Now().UnixMilli())
+	for i := 0; i < 6; i++ {
+		raw[i] = byte(milliseconds >> (40 - 8*i))
+	}
+	randomBytes(raw[6:])
+	return encode(raw[:], crockford, 26)
+}
+
+func (ulidGenerator) Validate(id string) error {
+	if len(id) != 26 || id[0] > '7' || !inAlphabet(strings.ToUpper(id), crockford) {
+		return invalid("expected a ULID")
+	}
+	return nil
+}
+
+// ULID generates the lexicographically sortable identifiers: 48 bits of the time in milliseconds
+// and 80 random bits, encoded using Crockford's base32, like `01HF8Z3V9Q7XK2M4N6P8R0S2T4`
+func ULID() Generator {
+	return ulidGenerator{}
+}
+
+const (
+	base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// ksuidEpoch is the start of the KSUID timestamps, in unix seconds
+	ksuidEpoch = 1400000000
+	maxKSUID   = "aWgEPTl1tmebfsQzFP4bxwgy80V"
+)
+
+type ksuidGenerator struct{}
+
+func (ksuidGenerator) Generate() string {
+	var raw [20]byte
+	seconds := uint32(time.Now().Unix() - ksuidEpoch)
+	for i := 0; i < 4; i++ {
+		raw[i] = byte(seconds >> (24 - 8*i))
+	}
+	randomBytes(raw[4:])
+	return encode(raw[:], base62, 27)
+}
+
+func (ksuidGenerator) Validate(id string) error {
+	// The encodings have the same length, so they're compared like the numbers
+	if len(id) != 27 || !inAlphabet(id, base62) || id > maxKSUID {
+		return invalid("expected a KSUID")
+	}
+	return nil
+}
+
+// KSUID generates the K-sortable identifiers: 32 bits of the time in seconds and 128 random bits,
+// encoded using base62, like `2Xb1UuQ0H8bXvUyPBvLHwA2fE9g`
+func KSUID() Generator {
+	return ksuidGenerator{}
+}
+
+// SnowflakeEpoch is the start of the Snowflake timestamps, in unix milliseconds
+const SnowflakeEpoch = 1288834974657
+
+type snowflakeGenerator struct {
+	mu       sync.Mutex
+	node     int64
+	last     int64
+	sequence int64
+}
+
+func (g *snowflakeGenerator) Generate() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Now().UnixMilli() - SnowflakeEpoch
+	if now < g.last {
+		// The clock went back, the IDs keep growing
+		now = g.last
+	}
+	if now == g.last {
+		g.sequence = (g.sequence + 1) & 0xfff
+		if g.sequence == 0 {
+			// The sequence of the millisecond is exhausted
+			for now <= g.last {
+				time.Sleep(100 * time.Microsecond)
+				now = time.Now().UnixMilli() - SnowflakeEpoch
+			}
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.last = now
+	return strconv.FormatInt(now<<22|g.node<<12|g.sequence, 10)
+}
+
+func (g *snowflakeGenerator) Validate(id string) error {
+	if parsed, parseErr := strconv.ParseInt(id, 10, 64); parseErr != nil || parsed <= 0 || strconv.FormatInt(parsed, 10) != id {
+		return invalid("expected a Snowflake ID")
+	}
+	return nil
+}
+
+// Snowflake generates the time-ordered 63 bit integers: 41 bits of the time in milliseconds since
+// SnowflakeEpoch, 10 bits of the node and 12 bits of the sequence. Every instance of the application
+// needs an unique node, between 0 and 1023. The IDs are stored as decimal strings.
+func Snowflake(node int) Generator {
+	if node < 0 || node > 1023 {
+		logrus.Panicf("Snowflake node has to be between 0 and 1023, got %d", node)
+	}
+	return &snowflakeGenerator{node: int64(node), last: -1}
+}
+
+type prefixedGenerator struct {
+	prefix string
+	child  Generator
+}
+
+func (g *prefixedGenerator) Generate() string {
+	return g.prefix + g.child.Generate()
+}
+
+func (g *prefixedGenerator) Validate(id string) error {
+	unprefixed, ok := strings.CutPrefix(id, g.prefix)
+	if !ok {
+		return invalid("expected an ID starting with `%s`", g.prefix)
+	}
+	return g.child.Validate(unprefixed)
+}
+
+// Prefixed prefixes the IDs generated by the child with the type of the object and an underscore,
+// like Prefixed("per", ULID()) generating `per_01HF8Z3V9Q7XK2M4N6P8R0S2T4`, so the IDs are
+// recognizable in the logs and the support tickets
+func Prefixed(prefix string, child Generator) Generator {
+	return &prefixedGenerator{prefix: prefix + "_", child: child}
+}
+
+// encode writes the big-endian number as the digits of the alphabet, left-padded to the length
+func encode(raw []byte, alphabet string, length int) string {
+	number := new(big.Int).SetBytes(raw)
+	base := big.NewInt(int64(len(alphabet)))
+	digit := new(big.Int)
+	encoded := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		number.DivMod(number, base, digit)
+		encoded[i] = alphabet[digit.Int64()]
+	}
+	return string(encoded)
+}
+
+func inAlphabet(id, alphabet string) bool {
+	for _, char := range id {
+		if !strings.ContainsRune(alphabet, char) {
+			return false
+		}
+	}
+	return true
+}
+
+func randomBytes(buffer []byte) {
+	if _, readErr := rand.Read(buffer); readErr != nil {
+		logrus.Panicf("Could not read random bytes: %s", readErr)
+	}
+}
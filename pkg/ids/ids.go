@@ -0,0 +1,79 @@
+// Package ids generates the IDs of the created objects using the strategies configured per model,
+// like ULIDs, KSUIDs, Snowflakes or prefixed IDs such as `per_01HF...`. The query drivers assign the
+// IDs on create, so the strategy is applied consistently, no matter which driver stores the model,
+// and the serializers reject the IDs, that the strategy couldn't have generated.
+package ids
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Generator generates the IDs and validates the IDs received from the clients
+type Generator interface {
+	Generate() string
+	Validate(id string) error
+}
+
+// ErrInvalidID is wrapped by the errors of the IDs rejected by the generators
+var ErrInvalidID = errors.New("invalid ID")
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[reflect.Type]Generator{}
+)
+
+// Register makes the query drivers generate the IDs of the model using the generator. The `id`
+// field of the model has to be a string.
+func Register[Model any](generator Generator) {
+	var m Model
+	if _, ok := models.AsInternalValue(m)["id"].(string); !ok {
+		logrus.Panicf("Could not register the ID generator of model `%T`: the `id` field has to be a string", m)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[reflect.TypeOf(m)] = generator
+}
+
+// For returns the generator registered for the model
+func For[Model any]() (Generator, bool) {
+	var m Model
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	generator, ok := registry[reflect.TypeOf(m)]
+	return generator, ok
+}
+
+// Assign sets the `id` of the internal value using the generator registered for the model, or
+// validates it, if it's already set. It returns false, if no generator is registered, so the
+// driver assigns the IDs itself.
+func Assign[Model any](internalValue models.InternalValue) (bool, error) {
+	generator, ok := For[Model]()
+	if !ok {
+		return false, nil
+	}
+	if id, set := internalValue["id"].(string); set && id != "" {
+		return true, generator.Validate(id)
+	}
+	internalValue["id"] = generator.Generate()
+	return true, nil
+}
+
+// Validate checks the ID using the generator registered for the model, every ID is valid if no
+// generator is registered
+func Validate[Model any](id string) error {
+	generator, ok := For[Model]()
+	if !ok {
+		return nil
+	}
+	return generator.Validate(id)
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidID, fmt.Sprintf(format, args...))
+}
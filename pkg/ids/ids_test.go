@@ -0,0 +1,92 @@
+package ids
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerators(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		generator Generator
+		pattern   string
+		invalid   []string
+	}{
+		{"UUID", UUID(), `^[0-9a-f-]{36}$`, []string{"", "01HF8Z3V9Q7XK2M4N6P8R0S2T4"}},
+		{"ULID", ULID(), `^[0-7][0-9A-HJKMNP-TV-Z]{25}$`, []string{"", "81HF8Z3V9Q7XK2M4N6P8R0S2T4", "01HF8Z3V9Q7XK2M4N6P8R0S2TU"}},
+		{"KSUID", KSUID(), `^[0-9A-Za-z]{27}$`, []string{"", "aWgEPTl1tmebfsQzFP4bxwgy80W", "2Xb1UuQ0H8bXvUyPBvLHwA2fE9-"}},
+		{"Snowflake", Snowflake(5), `^[0-9]{18,19}$`, []string{"", "-1", "012", "abc"}},
+		{"Prefixed", Prefixed("per", ULID()), `^per_[0-7][0-9A-HJKMNP-TV-Z]{25}$`, []string{"01HF8Z3V9Q7XK2M4N6P8R0S2T4", "per_abc"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			generated := make([]string, 100)
+			for i := range generated {
+				generated[i] = tc.generator.Generate()
+			}
+
+			// then
+			seen := map[string]bool{}
+			for _, id := range generated {
+				assert.Regexp(t, tc.pattern, id)
+				assert.NoError(t, tc.generator.Validate(id))
+				assert.False(t, seen[id], "duplicated ID %s", id)
+				seen[id] = true
+			}
+			for _, id := range tc.invalid {
+				assert.ErrorIs(t, tc.generator.Validate(id), ErrInvalidID, id)
+			}
+		})
+	}
+}
+
+func TestSnowflakeIsSortable(t *testing.T) {
+	// given
+	generator := Snowflake(1)
+
+	// when
+	generated := make([]string, 5000)
+	for i := range generated {
+		generated[i] = generator.Generate()
+	}
+
+	// then
+	assert.True(t, sort.StringsAreSorted(generated))
+}
+
+type registeredModel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type numericModel struct {
+	ID uint `json:"id"`
+}
+
+func TestAssign(t *testing.T) {
+	// given
+	Register[registeredModel](Prefixed("reg", KSUID()))
+	generated := models.InternalValue{"name": "generated"}
+	provided := models.InternalValue{"id": "reg_2Xb1UuQ0H8bXvUyPBvLHwA2fE9g"}
+	invalidValue := models.InternalValue{"id": "other_2Xb1UuQ0H8bXvUyPBvLHwA2fE9g"}
+
+	// when
+	generatedOk, generatedErr := Assign[registeredModel](generated)
+	_, providedErr := Assign[registeredModel](provided)
+	_, invalidErr := Assign[registeredModel](invalidValue)
+	unregisteredOk, unregisteredErr := Assign[numericModel](models.InternalValue{})
+
+	// then
+	assert.True(t, generatedOk)
+	assert.NoError(t, generatedErr)
+	assert.Regexp(t, `^reg_[0-9A-Za-z]{27}$`, generated["id"])
+	assert.NoError(t, providedErr)
+	assert.Equal(t, "reg_2Xb1UuQ0H8bXvUyPBvLHwA2fE9g", provided["id"])
+	assert.ErrorIs(t, invalidErr, ErrInvalidID)
+	assert.False(t, unregisteredOk)
+	assert.NoError(t, unregisteredErr)
+	assert.Panics(t, func() { Register[numericModel](ULID()) })
+}
@@ -34,6 +34,11 @@ type StringModel struct {
 	Value string `json:"value" gorm:"column:value"`
 }
 
+type BytesModel struct {
+	models.BaseModel
+	Value []byte `json:"value" gorm:"column:value"`
+}
+
 type IntModel struct {
 	models.BaseModel
 	Value int `json:"value" gorm:"column:value"`
@@ -99,6 +104,29 @@ type NestedJSONModel struct {
 	Value datatypes.JSON `json:"value" gorm:"column:value"`
 }
 
+type MapModel struct {
+	models.BaseModel
+	Value map[string]int `json:"value" gorm:"column:value;serializer:json"`
+}
+
+type Dimensions struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Unit   string `json:"unit"`
+}
+
+func (d *Dimensions) Validate() error {
+	if d.Unit != "cm" && d.Unit != "in" {
+		return fmt.Errorf("unit must be either `cm` or `in`")
+	}
+	return nil
+}
+
+type StructModel struct {
+	models.BaseModel
+	Value Dimensions `json:"value" gorm:"column:value;serializer:json"`
+}
+
 func DoTestTypes(t *testing.T, dialector gorm.Dialector) { // nolint: funlen
 	tests := []struct {
 		name        string
@@ -158,6 +186,29 @@ func DoTestTypes(t *testing.T, dialector gorm.Dialector) { // nolint: funlen
 				return registerModel[StringModel]("/string_field", dialector)
 			},
 		},
+		{
+			name:    "Bytes type",
+			baseURL: "/bytes_field",
+			okBodies: []map[string]any{
+				{"value": "aGVsbG8gd29ybGQ="},
+				{"value": "aGVsbG8gd29ybGQ"},
+				{"value": "_-8"},
+			},
+			okResponses: []map[string]any{
+				{"value": "aGVsbG8gd29ybGQ="},
+				{"value": "aGVsbG8gd29ybGQ="},
+				{"value": "/+8="},
+			},
+			errorBodies: []map[string]any{
+				{"value": 1},
+				{"value": "not base64!"},
+				{"value": []int{1, 2, 3}},
+				{"value": true},
+			},
+			router: func() *gin.Engine {
+				return registerModel[BytesModel]("/bytes_field", dialector)
+			},
+		},
 		{
 			name:    "Integer type",
 			baseURL: "/int_field",
@@ -470,6 +521,46 @@ func DoTestTypes(t *testing.T, dialector gorm.Dialector) { // nolint: funlen
 				return registerModel[NestedJSONModel]("/nested_json_field", dialector)
 			},
 		},
+		{
+			name:    "Map type",
+			baseURL: "/map_field",
+			okBodies: []map[string]any{
+				{"value": map[string]any{"foo": 1, "bar": 2}},
+				{"value": map[string]any{}},
+			},
+			okResponses: []map[string]any{
+				{"value": map[string]any{"foo": 1.0, "bar": 2.0}},
+				{"value": map[string]any{}},
+			},
+			errorBodies: []map[string]any{
+				{"value": map[string]any{"foo": "bar"}},
+				{"value": map[string]any{"foo": 1.5}},
+				{"value": []int{1, 2, 3}},
+				{"value": "hello world"},
+			},
+			router: func() *gin.Engine {
+				return registerModel[MapModel]("/map_field", dialector)
+			},
+		},
+		{
+			name:    "Struct type",
+			baseURL: "/struct_field",
+			okBodies: []map[string]any{
+				{"value": map[string]any{"width": 10, "height": 20, "unit": "cm"}},
+			},
+			okResponses: []map[string]any{
+				{"value": map[string]any{"width": 10.0, "height": 20.0, "unit": "cm"}},
+			},
+			errorBodies: []map[string]any{
+				{"value": map[string]any{"width": 10, "height": 20, "unit": "m"}},
+				{"value": map[string]any{"width": "10", "height": 20, "unit": "cm"}},
+				{"value": map[string]any{"width": 10, "height": 20, "unit": "cm", "depth": 5}},
+				{"value": []int{1, 2, 3}},
+			},
+			router: func() *gin.Engine {
+				return registerModel[StructModel]("/struct_field", dialector)
+			},
+		},
 		{
 			name:    "Nested JSON field numeric (only postgres supports them)",
 			baseURL: "/nested_json_field",
@@ -0,0 +1,13 @@
+// Package fakenames holds the names shared by the fake data generators and the anonymizers
+package fakenames
+
+var (
+	First = []string{
+		"Alex", "Blake", "Casey", "Dana", "Eden", "Finley", "Gray", "Harper", "Indigo", "Jordan",
+		"Kai", "Logan", "Morgan", "Noel", "Oakley", "Parker", "Quinn", "Riley", "Sage", "Taylor",
+	}
+	Last = []string{
+		"Adams", "Brooks", "Carter", "Dawson", "Ellis", "Fisher", "Grant", "Hayes", "Irving", "Jensen",
+		"Keller", "Lawson", "Mercer", "Nolan", "Owens", "Porter", "Reed", "Sutton", "Turner", "Walsh",
+	}
+)
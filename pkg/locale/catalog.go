@@ -0,0 +1,34 @@
+package locale
+
+import "github.com/glothriel/grf/pkg/grfctx"
+
+// Catalog holds the translations of the messages, like the labels of the choices, keyed by the
+// message keys and the languages
+type Catalog struct {
+	fallback string
+	messages map[string]map[string]string
+}
+
+// NewCatalog creates an empty Catalog falling back to the language, if a message is not translated
+// to the language of the request
+func NewCatalog(fallback string) *Catalog {
+	return &Catalog{fallback: fallback, messages: map[string]map[string]string{}}
+}
+
+// Add adds the translations of the messages to the language, keyed by the message keys, like
+// Add("de", map[string]string{"status.open": "Offen"})
+func (c *Catalog) Add(language string, messages map[string]string) *Catalog {
+	for key, message := range messages {
+		if c.messages[key] == nil {
+			c.messages[key] = map[string]string{}
+		}
+		c.messages[key][language] = message
+	}
+	return c
+}
+
+// Message returns the translation of the message to the language of the locale, see Translate. The
+// second value is false if the message is not translated.
+func (c *Catalog) Message(l grfctx.Locale, key string) (string, bool) {
+	return Translate(l, c.messages[key], c.fallback)
+}
@@ -0,0 +1,120 @@
+package locale
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/glothriel/grf/pkg/grfctx"
+)
+
+// separators are the decimal and grouping separators of the languages not using the English ones
+var separators = map[string][2]string{
+	"cs": {",", " "},
+	"da": {",", "."},
+	"de": {",", "."},
+	"es": {",", "."},
+	"fi": {",", " "},
+	"fr": {",", " "},
+	"id": {",", "."},
+	"it": {",", "."},
+	"nb": {",", " "},
+	"nl": {",", "."},
+	"pl": {",", " "},
+	"pt": {",", "."},
+	"ru": {",", " "},
+	"sv": {",", " "},
+	"tr": {",", "."},
+	"uk": {",", " "},
+}
+
+// currencySymbols are the symbols of the common currencies, other currencies are formatted using
+// their codes
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"CNY": "¥",
+	"INR": "₹",
+	"KRW": "₩",
+	"PLN": "zł",
+	"CZK": "Kč",
+	"UAH": "₴",
+	"TRY": "₺",
+	"BRL": "R$",
+}
+
+// zeroDecimalCurrencies don't have minor units
+var zeroDecimalCurrencies = []string{"JPY", "KRW", "CLP", "ISK", "VND", "HUF"}
+
+// CurrencyDecimals returns the number of the decimal places of the currency's amounts
+func CurrencyDecimals(currency string) int {
+	if slices.Contains(zeroDecimalCurrencies, currency) {
+		return 0
+	}
+	return 2
+}
+
+// FormatNumber formats the number with the decimal places, using the separators of the locale's
+// language, for example `1,234.5` in English and `1.234,5` in German
+func FormatNumber(l grfctx.Locale, value float64, decimals int) string {
+	decimalSeparator, groupSeparator := ".", ","
+	if languageSeparators, ok := separators[BaseLanguage(l.Language)]; ok {
+		decimalSeparator, groupSeparator = languageSeparators[0], languageSeparators[1]
+	}
+	formatted := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	integer, fraction, hasFraction := strings.Cut(formatted, ".")
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteString(groupSeparator)
+		}
+		grouped.WriteRune(digit)
+	}
+	if hasFraction {
+		grouped.WriteString(decimalSeparator + fraction)
+	}
+	if value < 0 && strings.ContainsFunc(formatted, func(r rune) bool { return r >= '1' && r <= '9' }) {
+		return "-" + grouped.String()
+	}
+	return grouped.String()
+}
+
+// FormatMoney formats the amount of the currency, or the locale's currency if empty, like `$1,234.50`
+// in English and `1.234,50 €` in the other languages
+func FormatMoney(l grfctx.Locale, amount float64, currency string) string {
+	if currency == "" {
+		currency = l.Currency
+	}
+	number := FormatNumber(l, amount, CurrencyDecimals(currency))
+	symbol, hasSymbol := currencySymbols[currency]
+	if !hasSymbol {
+		symbol = currency
+	}
+	if symbol == "" {
+		return number
+	}
+	language := BaseLanguage(l.Language)
+	if hasSymbol && (language == "en" || language == "") {
+		if sign, unsigned, negative := strings.Cut(number, "-"); negative && sign == "" {
+			return "-" + symbol + unsigned
+		}
+		return symbol + number
+	}
+	return number + " " + symbol
+}
+
+// Translate picks the translation of the locale's language, falling back to its base language,
+// like `pt` for `pt-BR`, and then to the fallback language. The second value is false if none of
+// them is translated.
+func Translate(l grfctx.Locale, translations map[string]string, fallback string) (string, bool) {
+	candidates := []string{l.Language, BaseLanguage(l.Language), fallback}
+	for _, candidate := range candidates {
+		if translation, ok := translations[candidate]; ok && candidate != "" {
+			return translation, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,176 @@
+// Package locale resolves the language, timezone and currency of the requests, storing them in
+// grfctx.Locale, and formats the values according to them, so the fields don't have to make the
+// formatting decisions themselves.
+package locale
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+)
+
+// The query parameters and headers read by the Extractor, the language is also negotiated using
+// the Accept-Language header
+const (
+	LanguageQueryParam = "lang"
+	TimezoneQueryParam = "tz"
+	CurrencyQueryParam = "currency"
+	TimezoneHeader     = "X-Timezone"
+	CurrencyHeader     = "X-Currency"
+)
+
+// ProfileFunc returns the preferences of the authenticated user, for example stored in the user's
+// profile. Empty fields of the returned locale are resolved from the other sources.
+type ProfileFunc func(ctx *gin.Context) grfctx.Locale
+
+// Extractor resolves the locale of the requests. Each of the language, timezone and currency is
+// taken from the first source holding a valid value: the query parameters, the user's profile, the
+// headers and finally the defaults.
+type Extractor struct {
+	defaults  grfctx.Locale
+	languages []string
+	profile   ProfileFunc
+}
+
+// NewExtractor creates an Extractor falling back to the defaults
+func NewExtractor(defaults grfctx.Locale) *Extractor {
+	return &Extractor{defaults: defaults}
+}
+
+// WithLanguages limits the languages to the supported ones, the requested languages are matched
+// with them by their base language, for example `pt-BR` is matched with `pt`. All the languages
+// are accepted by default.
+func (e *Extractor) WithLanguages(languages ...string) *Extractor {
+	e.languages = languages
+	return e
+}
+
+// WithProfile reads the preferences of the authenticated user, taking precedence over the headers
+func (e *Extractor) WithProfile(profile ProfileFunc) *Extractor {
+	e.profile = profile
+	return e
+}
+
+// Extract resolves the locale of the request
+func (e *Extractor) Extract(ctx *gin.Context) grfctx.Locale {
+	var profile grfctx.Locale
+	if e.profile != nil {
+		profile = e.profile(ctx)
+	}
+	resolved := e.defaults
+	candidates := append(
+		[]string{ctx.Query(LanguageQueryParam), profile.Language}, acceptedLanguages(ctx.GetHeader("Accept-Language"))...,
+	)
+	for _, candidate := range candidates {
+		if language, ok := e.supportedLanguage(candidate); ok {
+			resolved.Language = language
+			break
+		}
+	}
+	if location, ok := parseTimezone(ctx.Query(TimezoneQueryParam)); ok {
+		resolved.Timezone = location
+	} else if profile.Timezone != nil {
+		resolved.Timezone = profile.Timezone
+	} else if location, ok := parseTimezone(ctx.GetHeader(TimezoneHeader)); ok {
+		resolved.Timezone = location
+	}
+	for _, raw := range []string{ctx.Query(CurrencyQueryParam), profile.Currency, ctx.GetHeader(CurrencyHeader)} {
+		if currency, ok := parseCurrency(raw); ok {
+			resolved.Currency = currency
+			break
+		}
+	}
+	return resolved
+}
+
+// Middleware stores the locale of the request, see grfctx.CurrentLocale. It has to run after the
+// authentication, if the profile is used.
+func (e *Extractor) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		grfctx.SetLocale(ctx, e.Extract(ctx))
+		ctx.Next()
+	}
+}
+
+func (e *Extractor) supportedLanguage(tag string) (string, bool) {
+	tag = strings.TrimSpace(strings.ReplaceAll(tag, "_", "-"))
+	if tag == "" || tag == "*" {
+		return "", false
+	}
+	if len(e.languages) == 0 {
+		return tag, true
+	}
+	for _, language := range e.languages {
+		if strings.EqualFold(language, tag) {
+			return language, true
+		}
+	}
+	base := BaseLanguage(tag)
+	for _, language := range e.languages {
+		if strings.EqualFold(BaseLanguage(language), base) {
+			return language, true
+		}
+	}
+	return "", false
+}
+
+// BaseLanguage returns the language of the tag without the region, like `pt` for `pt-BR`
+func BaseLanguage(tag string) string {
+	base, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	return strings.ToLower(base)
+}
+
+// acceptedLanguages returns the languages of the Accept-Language header, in the order of their
+// quality values
+func acceptedLanguages(header string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+	languages := []weighted{}
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, parseErr := strconv.ParseFloat(value, 64)
+			if parseErr != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if tag != "" && quality > 0 {
+			languages = append(languages, weighted{tag: tag, quality: quality})
+		}
+	}
+	slices.SortStableFunc(languages, func(a, b weighted) int {
+		return cmp.Compare(b.quality, a.quality)
+	})
+	tags := make([]string, 0, len(languages))
+	for _, language := range languages {
+		tags = append(tags, language.tag)
+	}
+	return tags
+}
+
+func parseTimezone(raw string) (*time.Location, bool) {
+	if raw == "" {
+		return nil, false
+	}
+	location, loadErr := time.LoadLocation(raw)
+	return location, loadErr == nil
+}
+
+func parseCurrency(raw string) (string, bool) {
+	currency := strings.ToUpper(strings.TrimSpace(raw))
+	if len(currency) != 3 || slices.ContainsFunc([]rune(currency), func(r rune) bool {
+		return r < 'A' || r > 'Z'
+	}) {
+		return "", false
+	}
+	return currency, true
+}
@@ -0,0 +1,145 @@
+package locale
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract(t *testing.T) {
+	warsaw, _ := time.LoadLocation("Europe/Warsaw")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	newYork, _ := time.LoadLocation("America/New_York")
+	tests := []struct {
+		name     string
+		query    string
+		headers  map[string]string
+		profile  grfctx.Locale
+		expected grfctx.Locale
+	}{
+		{
+			name:     "defaults",
+			expected: grfctx.Locale{Language: "en", Timezone: time.UTC, Currency: "USD"},
+		},
+		{
+			name:     "headers",
+			headers:  map[string]string{"Accept-Language": "fr;q=0.5, pt-BR, en;q=0.8", TimezoneHeader: "Asia/Tokyo", CurrencyHeader: "jpy"},
+			expected: grfctx.Locale{Language: "pt", Timezone: tokyo, Currency: "JPY"},
+		},
+		{
+			name:     "profile over headers",
+			headers:  map[string]string{"Accept-Language": "pl", TimezoneHeader: "Asia/Tokyo", CurrencyHeader: "JPY"},
+			profile:  grfctx.Locale{Language: "de", Timezone: newYork},
+			expected: grfctx.Locale{Language: "de", Timezone: newYork, Currency: "JPY"},
+		},
+		{
+			name:     "query params over profile",
+			query:    "lang=pl-PL&tz=Europe/Warsaw&currency=PLN",
+			profile:  grfctx.Locale{Language: "de", Timezone: newYork, Currency: "EUR"},
+			expected: grfctx.Locale{Language: "pl", Timezone: warsaw, Currency: "PLN"},
+		},
+		{
+			name:     "invalid and unsupported values",
+			query:    "lang=ja&tz=Mars/Olympus&currency=euros",
+			headers:  map[string]string{"Accept-Language": "es, *;q=0.1"},
+			expected: grfctx.Locale{Language: "en", Timezone: time.UTC, Currency: "USD"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			extractor := NewExtractor(grfctx.Locale{Language: "en", Timezone: time.UTC, Currency: "USD"}).WithLanguages(
+				"en", "pl", "de", "pt",
+			).WithProfile(func(*gin.Context) grfctx.Locale {
+				return tt.profile
+			})
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest("GET", "/products?"+tt.query, nil)
+			for header, value := range tt.headers {
+				ctx.Request.Header.Set(header, value)
+			}
+
+			// when
+			extractor.Middleware()(ctx)
+
+			// then
+			resolved, ok := grfctx.CurrentLocale(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected.Language, resolved.Language)
+			assert.Equal(t, tt.expected.Timezone.String(), resolved.Location().String())
+			assert.Equal(t, tt.expected.Currency, resolved.Currency)
+		})
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		language string
+		amount   float64
+		currency string
+		expected string
+	}{
+		{language: "en", amount: 1234.5, currency: "USD", expected: "$1,234.50"},
+		{language: "en-GB", amount: -1234567.891, currency: "GBP", expected: "-£1,234,567.89"},
+		{language: "de", amount: 1234.5, currency: "EUR", expected: "1.234,50 €"},
+		{language: "pl", amount: 999.999, currency: "PLN", expected: "1 000,00 zł"},
+		{language: "ja", amount: 1234, currency: "JPY", expected: "1,234 ¥"},
+		{language: "en", amount: 12, currency: "CHF", expected: "12.00 CHF"},
+		{language: "en", amount: -0.001, currency: "USD", expected: "$0.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			// when
+			formatted := FormatMoney(grfctx.Locale{Language: tt.language}, tt.amount, tt.currency)
+
+			// then
+			assert.Equal(t, tt.expected, formatted)
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	translations := map[string]string{"en": "Shoes", "pt": "Sapatos", "pl": "Buty"}
+	tests := []struct {
+		language string
+		expected string
+	}{
+		{language: "pl", expected: "Buty"},
+		{language: "pt-BR", expected: "Sapatos"},
+		{language: "de", expected: "Shoes"},
+		{language: "", expected: "Shoes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			// when
+			translated, ok := Translate(grfctx.Locale{Language: tt.language}, translations, "en")
+
+			// then
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, translated)
+		})
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	// given
+	catalog := NewCatalog("en").
+		Add("en", map[string]string{"status.open": "Open", "status.closed": "Closed"}).
+		Add("de", map[string]string{"status.open": "Offen"})
+
+	// when
+	translated, translatedOk := catalog.Message(grfctx.Locale{Language: "de-AT"}, "status.open")
+	fallback, fallbackOk := catalog.Message(grfctx.Locale{Language: "de"}, "status.closed")
+	_, missingOk := catalog.Message(grfctx.Locale{Language: "de"}, "status.archived")
+
+	// then
+	assert.True(t, translatedOk)
+	assert.Equal(t, "Offen", translated)
+	assert.True(t, fallbackOk)
+	assert.Equal(t, "Closed", fallback)
+	assert.False(t, missingOk)
+}
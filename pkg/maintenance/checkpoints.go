@@ -0,0 +1,83 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Checkpoints store the cursors of the tasks, the ID of the last processed object
+type Checkpoints interface {
+	// Load returns the cursor of the task, nil if there's none
+	Load(ctx context.Context, task string) (any, error)
+	// Save stores the cursor of the task, nil clears it when the task completes
+	Save(ctx context.Context, task string, cursor any) error
+}
+
+// FileCheckpoints store the cursors of the tasks in a JSON file. The numeric IDs are loaded as
+// float64, the query drivers compare them with the stored IDs by their values.
+type FileCheckpoints struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileCheckpoints creates FileCheckpoints stored in the file, which is created when the first
+// cursor is saved
+func NewFileCheckpoints(path string) *FileCheckpoints {
+	return &FileCheckpoints{path: path}
+}
+
+func (c *FileCheckpoints) Load(_ context.Context, task string) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cursors, readErr := c.read()
+	return cursors[task], readErr
+}
+
+func (c *FileCheckpoints) Save(_ context.Context, task string, cursor any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cursors, readErr := c.read()
+	if readErr != nil {
+		return readErr
+	}
+	if cursor == nil {
+		delete(cursors, task)
+	} else {
+		cursors[task] = cursor
+	}
+	encoded, marshalErr := json.MarshalIndent(cursors, "", "  ")
+	if marshalErr != nil {
+		return marshalErr
+	}
+	// the file is replaced atomically, so interrupting the task doesn't corrupt it
+	temporary, createErr := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if createErr != nil {
+		return createErr
+	}
+	defer os.Remove(temporary.Name())
+	if _, writeErr := temporary.Write(encoded); writeErr != nil {
+		temporary.Close()
+		return writeErr
+	}
+	if closeErr := temporary.Close(); closeErr != nil {
+		return closeErr
+	}
+	return os.Rename(temporary.Name(), c.path)
+}
+
+func (c *FileCheckpoints) read() (map[string]any, error) {
+	cursors := map[string]any{}
+	encoded, readErr := os.ReadFile(c.path)
+	if errors.Is(readErr, fs.ErrNotExist) {
+		return cursors, nil
+	}
+	if readErr != nil {
+		return nil, readErr
+	}
+	return cursors, json.Unmarshal(encoded, &cursors)
+}
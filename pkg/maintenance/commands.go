@@ -0,0 +1,134 @@
+package maintenance
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/glothriel/grf/pkg/cli"
+)
+
+// Commands runs the tasks as the commands of the project's `grf` binary, see cli.CLI, as only the
+// project knows its models and drivers:
+//
+//	commands := maintenance.NewCommands().
+//		WithCommand("reindex", maintenance.Reindex("products", productsDriver, indexer)).
+//		WithCommand("warm-cache",
+//			maintenance.WarmCache("products", productsDriver, serializer),
+//			maintenance.WarmResponses("products", productsDriver, router, productURL),
+//		).
+//		WithCommand("reencrypt", maintenance.Reencrypt("users", usersDriver, keyring, "api_token"))
+//	os.Exit(commands.Register(cli.New()).Run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+type Commands struct {
+	commands map[string][]Task
+}
+
+// NewCommands creates Commands without any commands
+func NewCommands() *Commands {
+	return &Commands{commands: map[string][]Task{}}
+}
+
+// WithCommand adds the tasks to the command, they're run one by one
+func (c *Commands) WithCommand(name string, tasks ...Task) *Commands {
+	c.commands[name] = append(c.commands[name], tasks...)
+	return c
+}
+
+// Has checks if the command exists
+func (c *Commands) Has(name string) bool {
+	_, ok := c.commands[name]
+	return ok
+}
+
+// Register adds the commands to the CLI
+func (c *Commands) Register(target *cli.CLI) *cli.CLI {
+	for _, name := range c.names() {
+		name := name
+		target.WithCommand(name, func(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+			return c.Run(ctx, append([]string{name}, args...), stdout, stderr)
+		})
+	}
+	return target
+}
+
+// Run runs the command named by the first argument, with the `-batch-size`, `-rate` and
+// `-checkpoint` flags, and optionally only the tasks named by the remaining arguments, like
+// `reindex -rate 1000 products`. With `-checkpoint <file>`, the progress is stored in the file and
+// the interrupted tasks resume after their last processed batch, the checkpoints are keyed by the
+// command and the task, like `reindex/products`.
+// The progress is written to stdout. It returns the exit status: 0 on success, 1 if any of the
+// tasks failed and 2 if the arguments are invalid.
+func (c *Commands) Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || !c.Has(args[0]) {
+		fmt.Fprintf(stderr, "usage: <%s> [-batch-size N] [-rate N] [-checkpoint FILE] [task...]\n", strings.Join(c.names(), "|"))
+		return 2
+	}
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	options := Options{Command: args[0], Progress: func(p Progress) { printProgress(stdout, p) }}
+	flags.IntVar(&options.BatchSize, "batch-size", DefaultBatchSize, "the number of the objects processed at once")
+	flags.Float64Var(&options.Rate, "rate", 0, "the maximum number of the objects processed per second, 0 for no limit")
+	checkpoint := flags.String("checkpoint", "", "the file storing the progress of the tasks, to resume the interrupted ones")
+	if parseErr := flags.Parse(args[1:]); parseErr != nil {
+		return 2
+	}
+	if *checkpoint != "" {
+		options.Checkpoints = NewFileCheckpoints(*checkpoint)
+	}
+	tasks, selectErr := c.selectTasks(args[0], flags.Args())
+	if selectErr != nil {
+		fmt.Fprintln(stderr, selectErr)
+		return 2
+	}
+	status := 0
+	for _, task := range tasks {
+		if runErr := task.Run(ctx, options); runErr != nil {
+			fmt.Fprintln(stderr, runErr)
+			status = 1
+			continue
+		}
+		fmt.Fprintf(stdout, "%s: done\n", task.Name)
+	}
+	return status
+}
+
+func (c *Commands) selectTasks(command string, names []string) ([]Task, error) {
+	if len(names) == 0 {
+		return c.commands[command], nil
+	}
+	selected := make([]Task, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, task := range c.commands[command] {
+			if task.Name == name {
+				selected = append(selected, task)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown task `%s` of command `%s`", name, command)
+		}
+	}
+	return selected, nil
+}
+
+func (c *Commands) names() []string {
+	names := make([]string, 0, len(c.commands))
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func printProgress(w io.Writer, p Progress) {
+	if p.Total < 0 {
+		fmt.Fprintf(w, "%s: %d objects (%s)\n", p.Task, p.Done, p.Elapsed.Round(time.Millisecond))
+		return
+	}
+	fmt.Fprintf(w, "%s: %d/%d objects (%s)\n", p.Task, p.Done, p.Total, p.Elapsed.Round(time.Millisecond))
+}
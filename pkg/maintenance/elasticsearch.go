@@ -0,0 +1,112 @@
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// defaultElasticsearchTimeout bounds the bulk requests, so an unresponsive cluster fails the task
+// instead of blocking it
+const defaultElasticsearchTimeout = 30 * time.Second
+
+// ElasticsearchIndexer is an Indexer storing the objects in an Elasticsearch index using the bulk
+// API, the documents are the objects encoded as JSON with their IDs as the document IDs
+type ElasticsearchIndexer struct {
+	url     string
+	index   string
+	client  *http.Client
+	headers http.Header
+}
+
+// NewElasticsearchIndexer creates an ElasticsearchIndexer storing the objects in the index of the
+// cluster at the URL, like `http://localhost:9200`, the requests time out after 30 seconds, see
+// WithClient
+func NewElasticsearchIndexer(url, index string) *ElasticsearchIndexer {
+	return &ElasticsearchIndexer{
+		url:     strings.TrimSuffix(url, "/"),
+		index:   index,
+		client:  &http.Client{Timeout: defaultElasticsearchTimeout},
+		headers: http.Header{},
+	}
+}
+
+// WithClient replaces the default HTTP client, for example to change the timeout
+func (i *ElasticsearchIndexer) WithClient(client *http.Client) *ElasticsearchIndexer {
+	i.client = client
+	return i
+}
+
+// WithHeader sets a header of the requests, for example Authorization
+func (i *ElasticsearchIndexer) WithHeader(key, value string) *ElasticsearchIndexer {
+	i.headers.Set(key, value)
+	return i
+}
+
+func (i *ElasticsearchIndexer) Index(ctx context.Context, objects []models.InternalValue) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, object := range objects {
+		action := map[string]any{"index": map[string]any{"_index": i.index, "_id": fmt.Sprint(object["id"])}}
+		if encodeErr := encoder.Encode(action); encodeErr != nil {
+			return encodeErr
+		}
+		if encodeErr := encoder.Encode(object); encodeErr != nil {
+			return fmt.Errorf("could not encode %v: %w", object["id"], encodeErr)
+		}
+	}
+	request, requestErr := http.NewRequestWithContext(ctx, http.MethodPost, i.url+"/_bulk", &body)
+	if requestErr != nil {
+		return requestErr
+	}
+	request.Header = i.headers.Clone()
+	request.Header.Set("Content-Type", "application/x-ndjson")
+	response, doErr := i.client.Do(request)
+	if doErr != nil {
+		return doErr
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("elasticsearch responded with status %d", response.StatusCode)
+	}
+	return bulkError(response)
+}
+
+// bulkError returns the error of the first document that wasn't indexed, the bulk API responds
+// with 200 even if some of them failed
+func bulkError(response *http.Response) error {
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			ID     string `json:"_id"`
+			Status int    `json:"status"`
+			Error  *struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if decodeErr := json.NewDecoder(response.Body).Decode(&result); decodeErr != nil {
+		return fmt.Errorf("could not decode the bulk response: %w", decodeErr)
+	}
+	if !result.Errors {
+		return nil
+	}
+	for _, item := range result.Items {
+		for _, outcome := range item {
+			if outcome.Error != nil {
+				return fmt.Errorf(
+					"could not index %s: %s: %s", outcome.ID, outcome.Error.Type, outcome.Error.Reason,
+				)
+			}
+		}
+	}
+	return errors.New("could not index some of the objects")
+}
@@ -0,0 +1,263 @@
+// Package maintenance rebuilds the data derived from the stored objects, like the search indexes or
+// the cached fields, and rotates the encryption keys, by iterating all the objects using the query
+// drivers. The tasks are exposed as the commands of the project's binary, like `reindex` and
+// `warm-cache`, see Commands.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/encryption"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/queries/crud"
+	"github.com/glothriel/grf/pkg/scheduler"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// DefaultBatchSize is the number of the objects processed at once, if Options don't set it
+const DefaultBatchSize = 500
+
+// Progress is reported after every batch of the task
+type Progress struct {
+	Task string
+	Done int
+	// Total is the number of the objects, -1 if the driver can't count them
+	Total   int
+	Elapsed time.Duration
+}
+
+// Options control the runs of the tasks
+type Options struct {
+	BatchSize int
+	// Rate limits the number of the processed objects per second, so the rebuild doesn't overload
+	// the database or the search cluster serving the traffic. Zero disables the limit.
+	Rate     float64
+	Progress func(Progress)
+	// Checkpoints store the progress of the tasks after every batch, so the interrupted runs resume
+	// after the last processed batch instead of starting over
+	Checkpoints Checkpoints
+	// Command scopes the checkpoints, so the tasks with the same name in different commands, like
+	// `products` of `reindex` and `warm-cache`, don't resume each other. It's set by Commands.Run.
+	Command string
+}
+
+// checkpointKey identifies the checkpoint of the task, `<command>/<task>` or the task name if the
+// command isn't set
+func (o Options) checkpointKey(task string) string {
+	if o.Command == "" {
+		return task
+	}
+	return o.Command + "/" + task
+}
+
+// BatchFunc processes a batch of the objects, the context is prepared by the middleware of the
+// query driver
+type BatchFunc func(ctx *gin.Context, batch []models.InternalValue) error
+
+// Task processes all the objects of a model in batches, ordered by their IDs
+type Task struct {
+	Name string
+	run  func(ctx context.Context, options Options) error
+}
+
+// Run processes all the objects, stopping on the first error or when the context is cancelled
+func (t Task) Run(ctx context.Context, options Options) error {
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultBatchSize
+	}
+	return t.run(ctx, options)
+}
+
+// NewTask creates a Task passing the batches of the objects stored by the driver to the function.
+// The objects are paged by their IDs, like the keyset pagination, so the objects created during
+// the run don't shift the batches. The runs resumed from a checkpoint don't report the total.
+func NewTask[Model any](name string, driver queries.Driver[Model], fn BatchFunc) Task {
+	return Task{Name: name, run: func(ctx context.Context, options Options) error {
+		started := time.Now()
+		progress := Progress{Task: name, Total: -1}
+		var after any
+		key := options.checkpointKey(name)
+		if options.Checkpoints != nil {
+			var loadErr error
+			if after, loadErr = options.Checkpoints.Load(ctx, key); loadErr != nil {
+				return fmt.Errorf("%s: could not load the checkpoint: %w", name, loadErr)
+			}
+		}
+		// completed clears the checkpoint, so the next run starts over
+		completed := func() error {
+			if options.Checkpoints == nil {
+				return nil
+			}
+			return options.Checkpoints.Save(ctx, key, nil)
+		}
+		for {
+			var batch []models.InternalValue
+			listErr := scheduler.WithDriver(driver, func(driverCtx *gin.Context, q *crud.CRUD[Model]) error {
+				if after == nil {
+					if total, countErr := queries.Count(driverCtx, driver); countErr == nil {
+						progress.Total = total
+					} else if !errors.Is(countErr, queries.ErrCountUnsupported) {
+						return countErr
+					}
+				}
+				grfctx.SetWindow(driverCtx, grfctx.Window{
+					Limit: options.BatchSize, Keyset: &grfctx.Keyset{Field: "id", After: after},
+				})
+				var err error
+				if batch, err = q.List(driverCtx); err != nil || len(batch) == 0 {
+					return err
+				}
+				return fn(driverCtx, batch)
+			})(ctx)
+			if listErr != nil {
+				return fmt.Errorf("%s failed after %d objects: %w", name, progress.Done, listErr)
+			}
+			if len(batch) == 0 {
+				return completed()
+			}
+			after = batch[len(batch)-1]["id"]
+			progress.Done += len(batch)
+			progress.Elapsed = time.Since(started)
+			if options.Checkpoints != nil {
+				if saveErr := options.Checkpoints.Save(ctx, key, after); saveErr != nil {
+					return fmt.Errorf("%s: could not save the checkpoint: %w", name, saveErr)
+				}
+			}
+			if options.Progress != nil {
+				options.Progress(progress)
+			}
+			if len(batch) < options.BatchSize {
+				return completed()
+			}
+			if waitErr := throttle(ctx, options.Rate, progress); waitErr != nil {
+				return waitErr
+			}
+		}
+	}}
+}
+
+// Indexer stores the objects in a search index, like Elasticsearch, for example using its bulk API
+type Indexer interface {
+	Index(ctx context.Context, objects []models.InternalValue) error
+}
+
+// Reindex creates a Task storing all the objects in the index
+func Reindex[Model any](name string, driver queries.Driver[Model], indexer Indexer) Task {
+	return NewTask(name, driver, func(ctx *gin.Context, batch []models.InternalValue) error {
+		return indexer.Index(ctx.Request.Context(), batch)
+	})
+}
+
+// WarmCache creates a Task representing all the objects using the serializer, which populates the
+// cached fields, see fields.Cached, so the first requests after a deploy or a cache flush are fast.
+// The cached responses are warmed by WarmResponses.
+func WarmCache[Model any](name string, driver queries.Driver[Model], serializer serializers.Serializer) Task {
+	return NewTask(name, driver, func(ctx *gin.Context, batch []models.InternalValue) error {
+		for _, object := range batch {
+			if _, reprErr := serializer.ToRepresentation(object, ctx); reprErr != nil {
+				return fmt.Errorf("could not represent %v: %w", object["id"], reprErr)
+			}
+		}
+		return nil
+	})
+}
+
+// WarmResponses creates a Task requesting the objects from the handler, like the gin engine of the
+// project, which populates the response cache of its middleware, so the first requests after a
+// deploy or a cache flush are fast. The path returns the URL of the object, like
+// `/products/42`. The requests are anonymous, so only the responses cached for all the clients are
+// warmed, and any status other than 200 fails the task.
+func WarmResponses[Model any](
+	name string, driver queries.Driver[Model], handler http.Handler, path func(object models.InternalValue) string,
+) Task {
+	return NewTask(name, driver, func(ctx *gin.Context, batch []models.InternalValue) error {
+		for _, object := range batch {
+			request, requestErr := http.NewRequestWithContext(ctx.Request.Context(), http.MethodGet, path(object), nil)
+			if requestErr != nil {
+				return requestErr
+			}
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+			if response.Code != http.StatusOK {
+				return fmt.Errorf("%s responded with status %d", request.URL, response.Code)
+			}
+		}
+		return nil
+	})
+}
+
+// Reencrypt creates a Task rotating the keys of the encrypted fields, see fields.Encrypted. The
+// values of the fields, that aren't encrypted with the current key of the keyring, are decrypted
+// with the old keys and encrypted with the current one, the plaintext values are encrypted. Only
+// the objects with rotated values are updated, with the rotated fields merged into the stored
+// objects, so the changes of the other fields made during the run aren't overwritten, every batch in
+// a transaction, if the driver supports them. Run it with Checkpoints, so an interrupted rotation resumes where it stopped.
+func Reencrypt[Model any](name string, driver queries.Driver[Model], keyring *encryption.Keyring, fieldNames ...string) Task {
+	return NewTask(name, driver, func(ctx *gin.Context, batch []models.InternalValue) error {
+		return queries.Atomic(ctx, driver, func() error {
+			for _, object := range batch {
+				rotated := models.InternalValue{}
+				changed := false
+				for _, field := range fieldNames {
+					value, ok := object[field].(string)
+					if !ok {
+						continue
+					}
+					rotatedValue, rotatedField, rotateErr := keyring.Rotate(value)
+					if rotateErr != nil {
+						return fmt.Errorf("could not re-encrypt %s of %v: %w", field, object["id"], rotateErr)
+					}
+					if rotatedField {
+						rotated[field] = rotatedValue
+						changed = true
+					}
+				}
+				if !changed {
+					continue
+				}
+				// the rotated fields are merged into the stored object, as the updates replace the
+				// objects, so the changes of the other fields made since the batch was listed are kept
+				stored, retrieveErr := driver.CRUD().Retrieve(ctx, object["id"])
+				if errors.Is(retrieveErr, common.ErrorNotFound) {
+					continue
+				}
+				if retrieveErr != nil {
+					return fmt.Errorf("could not retrieve %v: %w", object["id"], retrieveErr)
+				}
+				merged := stored.Merge(rotated, models.MergeReplace)
+				if _, updateErr := driver.CRUD().Update(ctx, stored, merged, object["id"]); updateErr != nil {
+					return fmt.Errorf("could not update %v: %w", object["id"], updateErr)
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// throttle waits until the processed objects don't exceed the rate
+func throttle(ctx context.Context, rate float64, progress Progress) error {
+	if rate <= 0 {
+		return nil
+	}
+	wait := time.Duration(float64(progress.Done)/rate*float64(time.Second)) - progress.Elapsed
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,291 @@
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/cli"
+	"github.com/glothriel/grf/pkg/encryption"
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/crud"
+	"github.com/glothriel/grf/pkg/queries/dummy"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+type product struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type recordingIndexer struct {
+	batches [][]any
+	err     error
+}
+
+func (i *recordingIndexer) Index(_ context.Context, objects []models.InternalValue) error {
+	ids := []any{}
+	for _, object := range objects {
+		ids = append(ids, object["id"])
+	}
+	i.batches = append(i.batches, ids)
+	return i.err
+}
+
+func seededDriver() queries.Driver[product] {
+	return queries.InMemory(
+		product{Name: "a"}, product{Name: "b"}, product{Name: "c"}, product{Name: "d"}, product{Name: "e"},
+	)
+}
+
+func TestReindex(t *testing.T) {
+	// given
+	indexer := &recordingIndexer{}
+	progress := []Progress{}
+	task := Reindex("products", seededDriver(), indexer)
+
+	// when
+	err := task.Run(context.Background(), Options{BatchSize: 2, Progress: func(p Progress) {
+		p.Elapsed = 0
+		progress = append(progress, p)
+	}})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, [][]any{{uint(1), uint(2)}, {uint(3), uint(4)}, {uint(5)}}, indexer.batches)
+	assert.Equal(t, []Progress{
+		{Task: "products", Done: 2, Total: 5},
+		{Task: "products", Done: 4, Total: 5},
+		{Task: "products", Done: 5, Total: 5},
+	}, progress)
+}
+
+func TestWarmCache(t *testing.T) {
+	// given
+	represented := []any{}
+	serializer := serializers.NewModelSerializer[product]().WithField("name", func(oldField fields.Field) {
+		oldField.WithRepresentationFunc(func(intVal models.InternalValue, name string, _ *gin.Context) (any, error) {
+			represented = append(represented, intVal["id"])
+			return intVal[name], nil
+		})
+	})
+
+	// when
+	err := WarmCache("products", seededDriver(), serializer).Run(context.Background(), Options{BatchSize: 3})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []any{uint(1), uint(2), uint(3), uint(4), uint(5)}, represented)
+}
+
+func TestCommands(t *testing.T) {
+	// given
+	indexer := &recordingIndexer{}
+	failing := &recordingIndexer{err: errors.New("cluster unavailable")}
+	commands := NewCommands().
+		WithCommand("reindex", Reindex("products", seededDriver(), indexer), Reindex("orders", seededDriver(), failing))
+	var stdout, stderr bytes.Buffer
+
+	// when
+	selectedStatus := commands.Run(context.Background(), []string{"reindex", "-batch-size", "5", "products"}, &stdout, &stderr)
+	allStatus := commands.Run(context.Background(), []string{"reindex"}, &bytes.Buffer{}, &stderr)
+	unknownStatus := commands.Run(context.Background(), []string{"warm-cache"}, &bytes.Buffer{}, &stderr)
+
+	// then
+	assert.Equal(t, 0, selectedStatus)
+	assert.Regexp(t, `^products: 5/5 objects \(.*\)\nproducts: done\n$`, stdout.String())
+	assert.Equal(t, 1, allStatus)
+	assert.Contains(t, stderr.String(), "orders failed after 0 objects: cluster unavailable")
+	assert.Equal(t, 2, unknownStatus)
+	assert.Contains(t, stderr.String(), "usage: <reindex> [-batch-size N] [-rate N] [-checkpoint FILE] [task...]")
+}
+
+func TestCommandsRegister(t *testing.T) {
+	// given
+	indexer := &recordingIndexer{}
+	c := NewCommands().WithCommand("reindex", Reindex("products", seededDriver(), indexer)).Register(cli.New())
+	var stdout bytes.Buffer
+
+	// when
+	status := c.Run(context.Background(), []string{"reindex", "-batch-size", "5"}, &stdout, &bytes.Buffer{})
+
+	// then
+	assert.Equal(t, 0, status)
+	assert.Equal(t, [][]any{{uint(1), uint(2), uint(3), uint(4), uint(5)}}, indexer.batches)
+	assert.Contains(t, stdout.String(), "products: done")
+}
+
+func TestCommandsCheckpointsPerCommand(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	// the reindex was interrupted after the fourth object
+	assert.NoError(t, NewFileCheckpoints(path).Save(context.Background(), "reindex/products", uint(4)))
+	reindexed, refreshed := &recordingIndexer{}, &recordingIndexer{}
+	commands := NewCommands().
+		WithCommand("reindex", Reindex("products", seededDriver(), reindexed)).
+		WithCommand("refresh", Reindex("products", seededDriver(), refreshed))
+
+	// when
+	refreshStatus := commands.Run(context.Background(), []string{"refresh", "-checkpoint", path}, &bytes.Buffer{}, &bytes.Buffer{})
+	reindexStatus := commands.Run(context.Background(), []string{"reindex", "-checkpoint", path}, &bytes.Buffer{}, &bytes.Buffer{})
+
+	// then
+	assert.Equal(t, 0, refreshStatus)
+	assert.Equal(t, [][]any{{uint(1), uint(2), uint(3), uint(4), uint(5)}}, refreshed.batches)
+	assert.Equal(t, 0, reindexStatus)
+	assert.Equal(t, [][]any{{uint(5)}}, reindexed.batches)
+}
+
+func TestWarmResponses(t *testing.T) {
+	// given
+	requested := []string{}
+	router := gin.New()
+	router.GET("/products/:id", func(ctx *gin.Context) {
+		requested = append(requested, ctx.Param("id"))
+		if ctx.Param("id") == "4" {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusOK)
+	})
+	task := WarmResponses("products", seededDriver(), router, func(object models.InternalValue) string {
+		return fmt.Sprintf("/products/%v", object["id"])
+	})
+
+	// when
+	err := task.Run(context.Background(), Options{BatchSize: 2})
+
+	// then
+	assert.EqualError(t, err, "products failed after 2 objects: /products/4 responded with status 404")
+	assert.Equal(t, []string{"1", "2", "3", "4"}, requested)
+}
+
+func TestElasticsearchIndexer(t *testing.T) {
+	tests := []struct {
+		name          string
+		response      string
+		expectedError string
+	}{
+		{
+			name:     "all documents indexed",
+			response: `{"errors": false, "items": [{"index": {"_id": "1", "status": 201}}]}`,
+		},
+		{
+			name: "failed document",
+			response: `{"errors": true, "items": [
+				{"index": {"_id": "1", "status": 201}},
+				{"index": {"_id": "2", "status": 400, "error": {"type": "mapper_parsing_exception", "reason": "bad name"}}}
+			]}`,
+			expectedError: "could not index 2: mapper_parsing_exception: bad name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var path, contentType, authorization, body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path, contentType, authorization = r.URL.Path, r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+				raw, _ := io.ReadAll(r.Body)
+				body = string(raw)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+			indexer := NewElasticsearchIndexer(server.URL+"/", "products").WithHeader("Authorization", "ApiKey secret")
+
+			// when
+			err := indexer.Index(context.Background(), []models.InternalValue{
+				{"id": uint(1), "name": "a"}, {"id": uint(2), "name": "b"},
+			})
+
+			// then
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+			assert.Equal(t, "/_bulk", path)
+			assert.Equal(t, "application/x-ndjson", contentType)
+			assert.Equal(t, "ApiKey secret", authorization)
+			assert.Equal(t, `{"index":{"_id":"1","_index":"products"}}
+{"id":1,"name":"a"}
+{"index":{"_id":"2","_index":"products"}}
+{"id":2,"name":"b"}
+`, body)
+		})
+	}
+}
+
+type account struct {
+	ID    uint   `json:"id"`
+	Token string `json:"token"`
+	Name  string `json:"name"`
+}
+
+// updateRecordingDriver records the values passed to the updates
+type updateRecordingDriver struct {
+	*dummy.InMemoryQueryDriver[account]
+	updates []models.InternalValue
+}
+
+func (d *updateRecordingDriver) CRUD() *crud.CRUD[account] {
+	q := d.InMemoryQueryDriver.CRUD()
+	update := q.Update
+	return q.WithUpdate(func(ctx *gin.Context, old, new models.InternalValue, id any) (models.InternalValue, error) {
+		d.updates = append(d.updates, new)
+		return update(ctx, old, new, id)
+	})
+}
+
+func TestReencrypt(t *testing.T) {
+	// given
+	oldKey, newKey := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	oldKeyring, _ := encryption.NewKeyring("old", oldKey)
+	keyring, _ := encryption.NewKeyring("new", newKey)
+	_, _ = keyring.WithOldKey("old", oldKey)
+	seed := []account{}
+	for _, token := range []string{"a", "b", "c", "d", "e"} {
+		encrypted, _ := oldKeyring.Encrypt(token)
+		seed = append(seed, account{Token: encrypted, Name: token})
+	}
+	seed[4].Token = "e"
+	driver := &updateRecordingDriver{InMemoryQueryDriver: queries.InMemory(seed...)}
+	checkpoints := NewFileCheckpoints(filepath.Join(t.TempDir(), "checkpoints.json"))
+	// the previous run was interrupted after the first batch
+	assert.NoError(t, NewFileCheckpoints(checkpoints.path).Save(context.Background(), "accounts", uint(2)))
+
+	// when
+	err := Reencrypt("accounts", driver, keyring, "token").Run(context.Background(), Options{
+		BatchSize: 2, Checkpoints: checkpoints,
+	})
+
+	// then
+	assert.NoError(t, err)
+	stored, _ := driver.CRUD().List(nil)
+	for i, object := range stored {
+		token := object["token"].(string)
+		decrypted, decryptErr := keyring.Decrypt(token)
+		assert.NoError(t, decryptErr)
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}[i], decrypted)
+		assert.Equal(t, i < 2, keyring.NeedsRotation(token), "object %v", object["id"])
+		assert.Equal(t, decrypted, object["name"])
+	}
+	// the rotated fields are merged into the stored objects
+	assert.Len(t, driver.updates, 3)
+	for _, updated := range driver.updates {
+		assert.Len(t, updated, 3)
+		assert.Contains(t, updated, "name")
+	}
+	cursor, loadErr := checkpoints.Load(context.Background(), "accounts")
+	assert.NoError(t, loadErr)
+	assert.Nil(t, cursor)
+}
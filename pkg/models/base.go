@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -36,8 +37,11 @@ func AsInternalValue[Model any](entity Model) InternalValue {
 	out := make(InternalValue)
 	fields := reflect.VisibleFields(reflect.TypeOf(entity))
 	for _, field := range fields {
-		if !field.Anonymous {
-			out[field.Tag.Get("json")] = v.FieldByName(field.Name).Interface()
+		if field.Anonymous {
+			continue
+		}
+		if name, included := FieldName(field); included {
+			out[name] = v.FieldByName(field.Name).Interface()
 		}
 	}
 	return out
@@ -47,9 +51,13 @@ func AsInternalValue[Model any](entity Model) InternalValue {
 func AsModel[Model any](i InternalValue) (Model, error) {
 	var entity Model
 	decoder, decoderErr := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
-		TagName: "json",
+		TagName: fieldNameTag,
 		Result:  &entity,
 		Squash:  true,
+		MatchName: func(mapKey, fieldName string) bool {
+			// Untagged fields are matched using their snake_cased names
+			return strings.EqualFold(mapKey, fieldName) || mapKey == SnakeCase(fieldName)
+		},
 	})
 
 	if decoderErr != nil {
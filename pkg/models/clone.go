@@ -0,0 +1,126 @@
+package models
+
+import "reflect"
+
+// MergeStrategy defines how InternalValue.Merge resolves keys present in both values
+type MergeStrategy int
+
+const (
+	// MergeReplace replaces the existing values with the values from the other InternalValue
+	MergeReplace MergeStrategy = iota
+	// MergeDeep recursively merges nested maps, other values (including slices) are replaced
+	MergeDeep
+	// MergeKeepExisting only adds keys, that are not present yet
+	MergeKeepExisting
+)
+
+// Clone returns a deep copy of the InternalValue. Nested maps and slices are copied recursively,
+// so modifying the clone never affects the original. Other values, like structs, are copied by
+// value, which means that pointers stored in the InternalValue are still shared.
+func (i InternalValue) Clone() InternalValue {
+	if i == nil {
+		return nil
+	}
+	cloned := make(InternalValue, len(i))
+	for k, v := range i {
+		cloned[k] = deepCopy(v)
+	}
+	return cloned
+}
+
+// Merge returns a new InternalValue with the values of other merged into a copy of i, according to
+// the strategy. Neither i nor other are modified and the result does not share maps or slices with them.
+func (i InternalValue) Merge(other InternalValue, strategy MergeStrategy) InternalValue {
+	merged := i.Clone()
+	if merged == nil {
+		merged = InternalValue{}
+	}
+	mergeInto(merged, other, strategy)
+	return merged
+}
+
+func mergeInto(target map[string]any, other map[string]any, strategy MergeStrategy) {
+	for k, v := range other {
+		existing, exists := target[k]
+		switch {
+		case !exists:
+			target[k] = deepCopy(v)
+		case strategy == MergeKeepExisting:
+			continue
+		case strategy == MergeDeep:
+			existingMap, existingIsMap := asStringMap(existing)
+			otherMap, otherIsMap := asStringMap(v)
+			if existingIsMap && otherIsMap {
+				mergeInto(existingMap, otherMap, strategy)
+				continue
+			}
+			target[k] = deepCopy(v)
+		default:
+			target[k] = deepCopy(v)
+		}
+	}
+}
+
+func asStringMap(v any) (map[string]any, bool) {
+	switch typed := v.(type) {
+	case map[string]any:
+		return typed, true
+	case InternalValue:
+		return typed, true
+	}
+	return nil, false
+}
+
+func deepCopy(v any) any {
+	switch typed := v.(type) {
+	case nil:
+		return nil
+	case InternalValue:
+		return typed.Clone()
+	case map[string]any:
+		return map[string]any(InternalValue(typed).Clone())
+	case []any:
+		if typed == nil {
+			return typed
+		}
+		cloned := make([]any, len(typed))
+		for idx, elem := range typed {
+			cloned[idx] = deepCopy(elem)
+		}
+		return cloned
+	}
+	reflected := reflect.ValueOf(v)
+	switch reflected.Kind() {
+	case reflect.Map:
+		if reflected.IsNil() {
+			return v
+		}
+		cloned := reflect.MakeMapWithSize(reflected.Type(), reflected.Len())
+		iter := reflected.MapRange()
+		for iter.Next() {
+			cloned.SetMapIndex(iter.Key(), deepCopyValue(iter.Value(), reflected.Type().Elem()))
+		}
+		return cloned.Interface()
+	case reflect.Slice:
+		if reflected.IsNil() {
+			return v
+		}
+		cloned := reflect.MakeSlice(reflected.Type(), reflected.Len(), reflected.Len())
+		for idx := 0; idx < reflected.Len(); idx++ {
+			cloned.Index(idx).Set(deepCopyValue(reflected.Index(idx), reflected.Type().Elem()))
+		}
+		return cloned.Interface()
+	}
+	return v
+}
+
+func deepCopyValue(v reflect.Value, elemType reflect.Type) reflect.Value {
+	if v.Kind() == reflect.Interface && v.IsNil() {
+		return reflect.Zero(elemType)
+	}
+	copied := deepCopy(v.Interface())
+	if copied == nil {
+		return reflect.Zero(elemType)
+	}
+	return reflect.ValueOf(copied)
+}
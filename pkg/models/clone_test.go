@@ -0,0 +1,82 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInternalValueClone(t *testing.T) {
+	// given
+	original := InternalValue{
+		"name":   "foo",
+		"nested": map[string]any{"tags": []any{"a", "b"}},
+		"typed":  []string{"x"},
+		"labels": map[string]int{"a": 1},
+	}
+
+	// when
+	cloned := original.Clone()
+	cloned["name"] = "bar"
+	cloned["nested"].(map[string]any)["tags"].([]any)[0] = "changed"
+	cloned["typed"].([]string)[0] = "changed"
+	cloned["labels"].(map[string]int)["a"] = 2
+
+	// then
+	assert.Equal(t, InternalValue{
+		"name":   "foo",
+		"nested": map[string]any{"tags": []any{"a", "b"}},
+		"typed":  []string{"x"},
+		"labels": map[string]int{"a": 1},
+	}, original)
+	assert.Nil(t, InternalValue(nil).Clone())
+}
+
+func TestInternalValueMerge(t *testing.T) {
+	base := InternalValue{
+		"name":     "foo",
+		"settings": map[string]any{"color": "red", "size": 1.0},
+	}
+	other := InternalValue{
+		"name":     "bar",
+		"settings": map[string]any{"size": 2.0},
+		"extra":    true,
+	}
+	tests := []struct {
+		name     string
+		strategy MergeStrategy
+		expected InternalValue
+	}{
+		{
+			name:     "replace",
+			strategy: MergeReplace,
+			expected: InternalValue{"name": "bar", "settings": map[string]any{"size": 2.0}, "extra": true},
+		},
+		{
+			name:     "deep",
+			strategy: MergeDeep,
+			expected: InternalValue{
+				"name": "bar", "settings": map[string]any{"color": "red", "size": 2.0}, "extra": true,
+			},
+		},
+		{
+			name:     "keep existing",
+			strategy: MergeKeepExisting,
+			expected: InternalValue{
+				"name": "foo", "settings": map[string]any{"color": "red", "size": 1.0}, "extra": true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			merged := base.Merge(other, tt.strategy)
+
+			// then
+			assert.Equal(t, tt.expected, merged)
+			assert.Equal(t, "foo", base["name"])
+			assert.Equal(t, map[string]any{"color": "red", "size": 1.0}, base["settings"])
+			assert.Equal(t, map[string]any{"size": 2.0}, other["settings"])
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrKeyNotFound is returned by the InternalValue getters when the key is not present
+var ErrKeyNotFound = errors.New("key not found in internal value")
+
+// GetString returns the value of the key as a string
+func (i InternalValue) GetString(key string) (string, error) {
+	v, ok := i[key]
+	if !ok {
+		return "", fmt.Errorf("`%s`: %w", key, ErrKeyNotFound)
+	}
+	switch typed := v.(type) {
+	case string:
+		return typed, nil
+	case fmt.Stringer:
+		return typed.String(), nil
+	}
+	return "", fmt.Errorf("`%s`: expected a string, got `%T`", key, v)
+}
+
+// GetInt returns the value of the key as an int64. Any integer type is accepted, as well as floats
+// without a fractional part, as that's how JSON numbers are decoded.
+func (i InternalValue) GetInt(key string) (int64, error) {
+	v, ok := i[key]
+	if !ok {
+		return 0, fmt.Errorf("`%s`: %w", key, ErrKeyNotFound)
+	}
+	reflected := reflect.ValueOf(v)
+	switch reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflected.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if reflected.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("`%s`: value %d overflows int64", key, reflected.Uint())
+		}
+		return int64(reflected.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := reflected.Float()
+		if math.Mod(f, 1) != 0 || f > math.MaxInt64 || f < math.MinInt64 {
+			return 0, fmt.Errorf("`%s`: value %v is not an integer", key, f)
+		}
+		return int64(f), nil
+	}
+	return 0, fmt.Errorf("`%s`: expected an integer, got `%T`", key, v)
+}
+
+// GetBool returns the value of the key as a bool
+func (i InternalValue) GetBool(key string) (bool, error) {
+	v, ok := i[key]
+	if !ok {
+		return false, fmt.Errorf("`%s`: %w", key, ErrKeyNotFound)
+	}
+	typed, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("`%s`: expected a bool, got `%T`", key, v)
+	}
+	return typed, nil
+}
+
+// GetUUID returns the value of the key as an uuid.UUID, parsing it if it's stored as a string
+func (i InternalValue) GetUUID(key string) (uuid.UUID, error) {
+	v, ok := i[key]
+	if !ok {
+		return uuid.Nil, fmt.Errorf("`%s`: %w", key, ErrKeyNotFound)
+	}
+	switch typed := v.(type) {
+	case uuid.UUID:
+		return typed, nil
+	case string:
+		parsed, parseErr := uuid.Parse(typed)
+		if parseErr != nil {
+			return uuid.Nil, fmt.Errorf("`%s`: %w", key, parseErr)
+		}
+		return parsed, nil
+	case []byte:
+		parsed, parseErr := uuid.FromBytes(typed)
+		if parseErr != nil {
+			return uuid.Nil, fmt.Errorf("`%s`: %w", key, parseErr)
+		}
+		return parsed, nil
+	}
+	return uuid.Nil, fmt.Errorf("`%s`: expected an UUID, got `%T`", key, v)
+}
+
+// GetTime returns the value of the key as a time.Time, parsing it if it's stored as an RFC3339 string
+func (i InternalValue) GetTime(key string) (time.Time, error) {
+	v, ok := i[key]
+	if !ok {
+		return time.Time{}, fmt.Errorf("`%s`: %w", key, ErrKeyNotFound)
+	}
+	switch typed := v.(type) {
+	case time.Time:
+		return typed, nil
+	case *time.Time:
+		if typed != nil {
+			return *typed, nil
+		}
+	case string:
+		parsed, parseErr := time.Parse(time.RFC3339, typed)
+		if parseErr != nil {
+			return time.Time{}, fmt.Errorf("`%s`: %w", key, parseErr)
+		}
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("`%s`: expected a time, got `%T`", key, v)
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInternalValueGetters(t *testing.T) {
+	// given
+	id := uuid.New()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	iv := InternalValue{
+		"name":       "foo",
+		"count":      float64(3),
+		"fraction":   1.5,
+		"small":      int8(-2),
+		"active":     true,
+		"id":         id,
+		"id_string":  id.String(),
+		"created_at": now,
+		"updated_at": "2024-01-02T03:04:05Z",
+	}
+
+	// when
+	name, nameErr := iv.GetString("name")
+	count, countErr := iv.GetInt("count")
+	small, smallErr := iv.GetInt("small")
+	_, fractionErr := iv.GetInt("fraction")
+	active, activeErr := iv.GetBool("active")
+	parsedID, idErr := iv.GetUUID("id")
+	parsedIDString, idStringErr := iv.GetUUID("id_string")
+	createdAt, createdAtErr := iv.GetTime("created_at")
+	updatedAt, updatedAtErr := iv.GetTime("updated_at")
+	_, wrongTypeErr := iv.GetString("count")
+	_, missingErr := iv.GetString("missing")
+
+	// then
+	assert.NoError(t, nameErr)
+	assert.Equal(t, "foo", name)
+	assert.NoError(t, countErr)
+	assert.Equal(t, int64(3), count)
+	assert.NoError(t, smallErr)
+	assert.Equal(t, int64(-2), small)
+	assert.ErrorContains(t, fractionErr, "not an integer")
+	assert.NoError(t, activeErr)
+	assert.True(t, active)
+	assert.NoError(t, idErr)
+	assert.Equal(t, id, parsedID)
+	assert.NoError(t, idStringErr)
+	assert.Equal(t, id, parsedIDString)
+	assert.NoError(t, createdAtErr)
+	assert.Equal(t, now, createdAt)
+	assert.NoError(t, updatedAtErr)
+	assert.True(t, now.Equal(updatedAt))
+	assert.ErrorContains(t, wrongTypeErr, "expected a string")
+	assert.True(t, errors.Is(missingErr, ErrKeyNotFound))
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+var fieldNameTag = "json"
+
+// SetFieldNameTag configures the struct tag, that is used to derive field names of the models.
+// Defaults to `json`, but can be changed for example to `grf_name`, if the API should use
+// different names than other JSON encoders.
+func SetFieldNameTag(tag string) {
+	fieldNameTag = tag
+}
+
+// FieldNameTag returns the struct tag, that is used to derive field names of the models
+func FieldNameTag() string {
+	return fieldNameTag
+}
+
+// FieldName returns the name of the struct field as seen by GRF and false if the field should
+// be ignored. Fields without a name in the tag fall back to the snake_cased Go field name, fields
+// tagged with "-" and unexported fields are ignored.
+func FieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	tag := f.Tag.Get(fieldNameTag)
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return SnakeCase(f.Name), true
+	}
+	return name, true
+}
+
+// FieldOmitsEmpty returns true if the struct field is tagged with the `omitempty` option
+func FieldOmitsEmpty(f reflect.StructField) bool {
+	_, options, _ := strings.Cut(f.Tag.Get(fieldNameTag), ",")
+	for _, option := range strings.Split(options, ",") {
+		if strings.TrimSpace(option) == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
+
+// SnakeCase converts Go identifiers to snake_case, keeping acronyms together, e.g. `UserID`
+// becomes `user_id` and `HTTPStatus` becomes `http_status`.
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				builder.WriteRune('_')
+			}
+			builder.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
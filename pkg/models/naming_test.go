@@ -0,0 +1,89 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type untaggedModel struct {
+	ID        uint
+	UserName  string
+	HTTPCode  int    `json:",omitempty"`
+	Ignored   string `json:"-"`
+	Renamed   string `json:"other" api:"renamed_by_api"`
+	unexposed string
+}
+
+func TestFieldName(t *testing.T) {
+	tests := []struct {
+		field             string
+		expectedName      string
+		expectedIncluded  bool
+		expectedOmitEmpty bool
+	}{
+		{field: "ID", expectedName: "id", expectedIncluded: true},
+		{field: "UserName", expectedName: "user_name", expectedIncluded: true},
+		{field: "HTTPCode", expectedName: "http_code", expectedIncluded: true, expectedOmitEmpty: true},
+		{field: "Ignored", expectedName: "", expectedIncluded: false},
+		{field: "Renamed", expectedName: "other", expectedIncluded: true},
+		{field: "unexposed", expectedName: "", expectedIncluded: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			// given
+			field, _ := reflect.TypeOf(untaggedModel{}).FieldByName(tt.field)
+
+			// when
+			name, included := FieldName(field)
+
+			// then
+			assert.Equal(t, tt.expectedName, name)
+			assert.Equal(t, tt.expectedIncluded, included)
+			assert.Equal(t, tt.expectedOmitEmpty, FieldOmitsEmpty(field))
+		})
+	}
+}
+
+func TestSetFieldNameTag(t *testing.T) {
+	// given
+	SetFieldNameTag("api")
+	defer SetFieldNameTag("json")
+	field, _ := reflect.TypeOf(untaggedModel{}).FieldByName("Renamed")
+
+	// when
+	name, included := FieldName(field)
+	model, modelErr := AsModel[untaggedModel](InternalValue{"renamed_by_api": "foo"})
+
+	// then
+	assert.Equal(t, "renamed_by_api", name)
+	assert.True(t, included)
+	assert.NoError(t, modelErr)
+	assert.Equal(t, "foo", model.Renamed)
+}
+
+func TestAsModelAndAsInternalValueWithUntaggedFields(t *testing.T) {
+	// given
+	internalValue := InternalValue{
+		"id":        uint(1),
+		"user_name": "bar",
+		"http_code": 404,
+		"other":     "baz",
+	}
+
+	// when
+	model, err := AsModel[untaggedModel](internalValue)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, untaggedModel{ID: 1, UserName: "bar", HTTPCode: 404, Renamed: "baz"}, model)
+	assert.Equal(t, internalValue, AsInternalValue(model))
+}
+
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, "user_id", SnakeCase("UserID"))
+	assert.Equal(t, "http_status", SnakeCase("HTTPStatus"))
+	assert.Equal(t, "name", SnakeCase("Name"))
+	assert.Equal(t, "address2_line", SnakeCase("Address2Line"))
+}
@@ -0,0 +1,96 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// HTTPModerator is an adapter for external moderation APIs. It posts `{"text": "..."}` to the URL
+// and expects a `{"flagged": true, "categories": ["harassment"]}` response. APIs with other
+// formats can be adapted with WithRequestFunc and WithResponseFunc.
+type HTTPModerator struct {
+	url          string
+	client       *http.Client
+	headers      http.Header
+	requestFunc  func(text string) any
+	responseFunc func(body []byte) (Verdict, error)
+}
+
+// WithClient replaces the default HTTP client, for example to set a timeout
+func (m *HTTPModerator) WithClient(client *http.Client) *HTTPModerator {
+	m.client = client
+	return m
+}
+
+// WithHeader sets a header of the requests, for example Authorization
+func (m *HTTPModerator) WithHeader(key, value string) *HTTPModerator {
+	m.headers.Set(key, value)
+	return m
+}
+
+// WithRequestFunc replaces the function building the JSON body of the requests
+func (m *HTTPModerator) WithRequestFunc(f func(text string) any) *HTTPModerator {
+	m.requestFunc = f
+	return m
+}
+
+// WithResponseFunc replaces the function parsing the bodies of the responses
+func (m *HTTPModerator) WithResponseFunc(f func(body []byte) (Verdict, error)) *HTTPModerator {
+	m.responseFunc = f
+	return m
+}
+
+func (m *HTTPModerator) Moderate(ctx context.Context, text string) (Verdict, error) {
+	body, marshalErr := json.Marshal(m.requestFunc(text))
+	if marshalErr != nil {
+		return Verdict{}, marshalErr
+	}
+	request, requestErr := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(body))
+	if requestErr != nil {
+		return Verdict{}, requestErr
+	}
+	request.Header = m.headers.Clone()
+	request.Header.Set("Content-Type", "application/json")
+	response, doErr := m.client.Do(request)
+	if doErr != nil {
+		return Verdict{}, doErr
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return Verdict{}, fmt.Errorf("moderation API responded with status %d", response.StatusCode)
+	}
+	var responseBody bytes.Buffer
+	if _, readErr := responseBody.ReadFrom(response.Body); readErr != nil {
+		return Verdict{}, readErr
+	}
+	return m.responseFunc(responseBody.Bytes())
+}
+
+func defaultModerationRequest(text string) any {
+	return map[string]string{"text": text}
+}
+
+func defaultModerationResponse(body []byte) (Verdict, error) {
+	var parsed struct {
+		Flagged    bool     `json:"flagged"`
+		Categories []string `json:"categories"`
+	}
+	if unmarshalErr := json.Unmarshal(body, &parsed); unmarshalErr != nil {
+		return Verdict{}, unmarshalErr
+	}
+	return Verdict{Flagged: parsed.Flagged, Categories: parsed.Categories}, nil
+}
+
+// NewHTTPModerator creates an HTTPModerator posting the texts to the URL
+func NewHTTPModerator(url string) *HTTPModerator {
+	return &HTTPModerator{
+		url:          url,
+		client:       http.DefaultClient,
+		headers:      http.Header{},
+		requestFunc:  defaultModerationRequest,
+		responseFunc: defaultModerationResponse,
+	}
+}
@@ -0,0 +1,157 @@
+// Package moderation detects unwanted content, like profanity or personal data, in texts submitted
+// by the users. The moderators are applied to serializer fields using fields.NewModeratedField.
+package moderation
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Match is a fragment of the text, that caused it to be flagged. Start and End are byte offsets.
+type Match struct {
+	Category string
+	Start    int
+	End      int
+}
+
+// Verdict is the result of the moderation
+type Verdict struct {
+	Flagged    bool
+	Categories []string
+	// Matches are the flagged fragments of the text. Moderators, that only classify the whole text,
+	// for example external moderation APIs, don't return them.
+	Matches []Match
+}
+
+// Moderator checks the text for unwanted content
+type Moderator interface {
+	Moderate(ctx context.Context, text string) (Verdict, error)
+}
+
+// Combine creates a Moderator merging the verdicts of all the moderators
+func Combine(moderators ...Moderator) Moderator {
+	return combined(moderators)
+}
+
+type combined []Moderator
+
+func (c combined) Moderate(ctx context.Context, text string) (Verdict, error) {
+	verdict := Verdict{}
+	for _, moderator := range c {
+		partial, err := moderator.Moderate(ctx, text)
+		if err != nil {
+			return Verdict{}, err
+		}
+		verdict.Flagged = verdict.Flagged || partial.Flagged
+		for _, category := range partial.Categories {
+			if !slices.Contains(verdict.Categories, category) {
+				verdict.Categories = append(verdict.Categories, category)
+			}
+		}
+		verdict.Matches = append(verdict.Matches, partial.Matches...)
+	}
+	return verdict, nil
+}
+
+// Patterns flags the texts matching any of the regular expressions, the keys of the map are
+// the categories of the matches
+type Patterns map[string]*regexp.Regexp
+
+func (p Patterns) Moderate(_ context.Context, text string) (Verdict, error) {
+	verdict := Verdict{}
+	categories := make([]string, 0, len(p))
+	for category := range p {
+		categories = append(categories, category)
+	}
+	slices.Sort(categories)
+	for _, category := range categories {
+		found := p[category].FindAllStringIndex(text, -1)
+		if len(found) == 0 {
+			continue
+		}
+		verdict.Flagged = true
+		verdict.Categories = append(verdict.Categories, category)
+		for _, match := range found {
+			verdict.Matches = append(verdict.Matches, Match{Category: category, Start: match[0], End: match[1]})
+		}
+	}
+	return verdict, nil
+}
+
+// NewProfanity creates a Moderator flagging the texts containing any of the words, case
+// insensitive, in the `profanity` category
+func NewProfanity(words ...string) Patterns {
+	quoted := make([]string, 0, len(words))
+	for _, word := range words {
+		quoted = append(quoted, regexp.QuoteMeta(word))
+	}
+	return Patterns{"profanity": regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)}
+}
+
+// NewPII creates a Moderator flagging the texts containing email addresses and phone numbers,
+// in the `email` and `phone` categories
+func NewPII() Patterns {
+	return Patterns{
+		"email": regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+		"phone": regexp.MustCompile(`\+?\d[\d -]{7,}\d`),
+	}
+}
+
+// Mask replaces every character of the matches with the mask character
+func Mask(text string, matches []Match, mask rune) string {
+	var masked strings.Builder
+	for i, r := range text {
+		if slices.ContainsFunc(matches, func(m Match) bool { return i >= m.Start && i < m.End }) {
+			masked.WriteRune(mask)
+			continue
+		}
+		masked.WriteRune(r)
+	}
+	return masked.String()
+}
+
+// Flag is a text accepted by the moderated field, waiting for a review
+type Flag struct {
+	Field      string
+	Path       string
+	Text       string
+	Categories []string
+	FlaggedAt  time.Time
+}
+
+// Queue keeps the flagged texts for a review by the moderators
+type Queue interface {
+	Push(ctx context.Context, flag Flag) error
+}
+
+// InMemoryQueue holds the flags until they are popped, they are lost on restart and each instance
+// of the server has its own queue
+type InMemoryQueue struct {
+	mu    sync.Mutex
+	flags []Flag
+}
+
+func (q *InMemoryQueue) Push(_ context.Context, flag Flag) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.flags = append(q.flags, flag)
+	return nil
+}
+
+// Pop removes and returns all the flags from the queue
+func (q *InMemoryQueue) Pop() []Flag {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	flags := q.flags
+	q.flags = nil
+	return flags
+}
+
+// NewInMemoryQueue creates an empty InMemoryQueue
+func NewInMemoryQueue() *InMemoryQueue {
+	return &InMemoryQueue{}
+}
@@ -0,0 +1,55 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCombinedPatterns(t *testing.T) {
+	// given
+	moderator := Combine(NewProfanity("darn", "heck"), NewPII())
+	text := "Darn, call me at +48 600 100 200 or mail jan@example.com"
+
+	// when
+	verdict, err := moderator.Moderate(context.Background(), text)
+	clean, cleanErr := moderator.Moderate(context.Background(), "Darnell, what the hecklers said")
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, verdict.Flagged)
+	assert.Equal(t, []string{"profanity", "email", "phone"}, verdict.Categories)
+	assert.Equal(t, "****, call me at *************** or mail ***************", Mask(text, verdict.Matches, '*'))
+	assert.NoError(t, cleanErr)
+	assert.False(t, clean.Flagged)
+}
+
+func TestHTTPModerator(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body["text"] == "you are an idiot" {
+			w.Write([]byte(`{"flagged": true, "categories": ["harassment"]}`))
+			return
+		}
+		w.Write([]byte(`{"flagged": false}`))
+	}))
+	defer server.Close()
+	moderator := NewHTTPModerator(server.URL).WithHeader("Authorization", "Bearer token")
+
+	// when
+	flagged, flaggedErr := moderator.Moderate(context.Background(), "you are an idiot")
+	clean, cleanErr := moderator.Moderate(context.Background(), "have a nice day")
+
+	// then
+	assert.NoError(t, flaggedErr)
+	assert.Equal(t, Verdict{Flagged: true, Categories: []string{"harassment"}}, flagged)
+	assert.NoError(t, cleanErr)
+	assert.False(t, clean.Flagged)
+}
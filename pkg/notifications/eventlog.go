@@ -0,0 +1,105 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrInvalidCursor is returned by the event logs for the cursors they didn't issue
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrCursorExpired is returned by the event logs, when the events following the cursor are no
+// longer retained, the consumer has to resync the objects instead of replaying the events
+var ErrCursorExpired = errors.New("the events following the cursor are no longer retained")
+
+// Type returns the type of the event, used to filter the replayed events, like `Order.updated`
+func (e Event) Type() string {
+	return e.Model + "." + string(e.Kind)
+}
+
+// LoggedEvent is an event stored in an EventLog
+type LoggedEvent struct {
+	// Cursor identifies the position of the event in the log, replaying since the cursor returns
+	// the events logged after this one
+	Cursor string
+	Time   time.Time
+	Event  Event
+}
+
+// EventLog stores the events sent by the notifiers, so the consumers, that missed the deliveries,
+// like webhooks that were down, can replay them, see Notifier.WithEventLog
+type EventLog interface {
+	Append(ctx context.Context, event Event) error
+	// Since returns at most limit events logged after the cursor, in the order they were logged,
+	// or since the oldest retained event if the cursor is empty. The types limit the events to the
+	// ones of the types, like `Order.updated`, or of the models, like `Order`, all the events are
+	// returned if there are none.
+	Since(ctx context.Context, cursor string, types []string, limit int) ([]LoggedEvent, error)
+}
+
+// MatchesTypes returns true if the event is of any of the types or models, or there are no types
+func MatchesTypes(event Event, types []string) bool {
+	return len(types) == 0 || slices.Contains(types, event.Type()) || slices.Contains(types, event.Model)
+}
+
+// MemoryEventLog retains the most recent events in memory, the cursors are the sequence numbers of
+// the events. The events are lost on restarts, so durable logs should be used if the consumers
+// rely on the replays.
+type MemoryEventLog struct {
+	mu       sync.RWMutex
+	capacity int
+	events   []LoggedEvent
+	sequence uint64
+	now      func() time.Time
+}
+
+// NewMemoryEventLog creates a MemoryEventLog retaining at most capacity events
+func NewMemoryEventLog(capacity int) *MemoryEventLog {
+	return &MemoryEventLog{capacity: capacity, now: time.Now}
+}
+
+func (l *MemoryEventLog) Append(_ context.Context, event Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sequence++
+	l.events = append(l.events, LoggedEvent{
+		Cursor: strconv.FormatUint(l.sequence, 10),
+		Time:   l.now().UTC(),
+		Event:  event,
+	})
+	if len(l.events) > l.capacity {
+		l.events = l.events[len(l.events)-l.capacity:]
+	}
+	return nil
+}
+
+func (l *MemoryEventLog) Since(_ context.Context, cursor string, types []string, limit int) ([]LoggedEvent, error) {
+	var after uint64
+	if cursor != "" {
+		var parseErr error
+		if after, parseErr = strconv.ParseUint(cursor, 10, 64); parseErr != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if after > l.sequence {
+		return nil, ErrInvalidCursor
+	}
+	// the sequence number of the oldest retained event
+	oldest := l.sequence - uint64(len(l.events)) + 1
+	if cursor != "" && after+1 < oldest {
+		return nil, ErrCursorExpired
+	}
+	events := []LoggedEvent{}
+	for i := int(after + 1 - min(after+1, oldest)); i < len(l.events) && len(events) < limit; i++ {
+		if MatchesTypes(l.events[i].Event, types) {
+			events = append(events, l.events[i])
+		}
+	}
+	return events, nil
+}
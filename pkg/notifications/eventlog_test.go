@@ -0,0 +1,43 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryEventLog(t *testing.T) {
+	// given
+	log := NewMemoryEventLog(3)
+	notifier := NewNotifier().WithEventLog(log)
+	for _, event := range []Event{
+		{Kind: Created, Model: "Order", Object: map[string]any{"id": 1}},
+		{Kind: Created, Model: "Invoice", Object: map[string]any{"id": 1}},
+		{Kind: Updated, Model: "Order", Object: map[string]any{"id": 1, "status": "paid"}, Previous: map[string]any{"id": 1}},
+		{Kind: Destroyed, Model: "Order", Object: map[string]any{"id": 1}},
+	} {
+		assert.NoError(t, notifier.Notify(context.Background(), event))
+	}
+
+	// when
+	all, allErr := log.Since(context.Background(), "", nil, 10)
+	orders, ordersErr := log.Since(context.Background(), "2", []string{"Order"}, 1)
+	updates, updatesErr := log.Since(context.Background(), "2", []string{"Order.updated", "Invoice.created"}, 10)
+	_, expiredErr := log.Since(context.Background(), "0", nil, 10)
+	_, invalidErr := log.Since(context.Background(), "5", nil, 10)
+
+	// then
+	assert.NoError(t, allErr)
+	assert.NoError(t, ordersErr)
+	assert.NoError(t, updatesErr)
+	assert.Len(t, all, 3)
+	assert.Equal(t, []string{"2", "3", "4"}, []string{all[0].Cursor, all[1].Cursor, all[2].Cursor})
+	assert.Len(t, orders, 1)
+	assert.Equal(t, "Order.updated", orders[0].Event.Type())
+	assert.Equal(t, []string{"status"}, orders[0].Event.Changes)
+	assert.Len(t, updates, 1)
+	assert.Equal(t, "3", updates[0].Cursor)
+	assert.ErrorIs(t, expiredErr, ErrCursorExpired)
+	assert.ErrorIs(t, invalidErr, ErrInvalidCursor)
+}
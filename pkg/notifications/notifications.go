@@ -0,0 +1,299 @@
+// Package notifications sends templated messages, like emails, Slack messages or webhooks, on the
+// lifecycle events of the models, so features like "notify the owner when the status changes"
+// don't need custom plumbing. Notifiers are subscribed to the viewsets using ViewSet.WithNotifier.
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EventKind is the lifecycle event of the model
+type EventKind string
+
+const (
+	Created   EventKind = "created"
+	Updated   EventKind = "updated"
+	Destroyed EventKind = "destroyed"
+)
+
+// Event is passed to the templates and the conditions of the rules
+type Event struct {
+	Kind EventKind
+	// Model is the name of the model type, for example `Order`
+	Model string
+	// Object is the representation of the created or updated object, or of the destroyed one
+	Object map[string]any
+	// Previous is the representation of the object before the update, nil for other events
+	Previous map[string]any
+	// Changes are the fields changed by the update, sorted, nil for other events. They are computed
+	// by Notifier.Notify, if not set.
+	Changes []string
+}
+
+// Changed returns true if the value of the field was changed by the update, it can be used in the
+// templates, like `{{if .Changed "status"}}`. It's always true for other events.
+func (e Event) Changed(field string) bool {
+	if e.Kind != Updated {
+		return true
+	}
+	return !reflect.DeepEqual(e.Object[field], e.Previous[field])
+}
+
+// changedFields returns the sorted fields of the representations with different values
+func changedFields(object, previous map[string]any) []string {
+	changes := []string{}
+	for field, value := range object {
+		if previousValue, ok := previous[field]; !ok || !reflect.DeepEqual(value, previousValue) {
+			changes = append(changes, field)
+		}
+	}
+	for field := range previous {
+		if _, ok := object[field]; !ok {
+			changes = append(changes, field)
+		}
+	}
+	slices.Sort(changes)
+	return changes
+}
+
+// Message is a rendered notification
+type Message struct {
+	// To are the recipients, like email addresses, ignored by the senders posting to a fixed URL
+	To      []string
+	Subject string
+	Body    string
+	Event   Event
+}
+
+// Sender delivers the messages
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Condition decides if the rule applies to the event
+type Condition func(event Event) bool
+
+// FieldChanged is a Condition matching the events changing the field, see Event.Changed
+func FieldChanged(field string) Condition {
+	return func(event Event) bool {
+		return event.Changed(field)
+	}
+}
+
+// Rule renders the messages of the matching events and delivers them using the sender. The
+// subject, body and recipients are text/template templates executed with the Event, for example
+// `Order {{.Object.id}} is {{.Object.status}}`. Invalid templates panic when the rule is built.
+type Rule struct {
+	sender     Sender
+	kinds      []EventKind
+	fields     []string
+	conditions []Condition
+	subject    *template.Template
+	body       *template.Template
+	recipients []*template.Template
+}
+
+// NewRule creates a Rule sending the body to the sender on all the events
+func NewRule(sender Sender, body string) *Rule {
+	return &Rule{sender: sender, body: parseTemplate("body", body)}
+}
+
+// On limits the rule to the kinds of events
+func (r *Rule) On(kinds ...EventKind) *Rule {
+	r.kinds = append(r.kinds, kinds...)
+	return r
+}
+
+// OnFields subscribes the rule only to the updates changing any of the fields, the updates of other
+// fields are skipped, so the consumers, like webhooks, don't receive the changes they don't care
+// about. The created and destroyed events are not affected.
+func (r *Rule) OnFields(fields ...string) *Rule {
+	r.fields = append(r.fields, fields...)
+	return r
+}
+
+// When limits the rule to the events matching the condition, all the conditions have to match
+func (r *Rule) When(condition Condition) *Rule {
+	r.conditions = append(r.conditions, condition)
+	return r
+}
+
+// WithSubject sets the template of the subject
+func (r *Rule) WithSubject(subject string) *Rule {
+	r.subject = parseTemplate("subject", subject)
+	return r
+}
+
+// WithRecipients sets the templates of the recipients, for example `{{.Object.owner_email}}`.
+// Recipients rendered as empty strings, or missing from the representation, are skipped.
+func (r *Rule) WithRecipients(recipients ...string) *Rule {
+	for _, recipient := range recipients {
+		r.recipients = append(r.recipients, parseTemplate("recipient", recipient))
+	}
+	return r
+}
+
+func (r *Rule) matches(event Event) bool {
+	if len(r.kinds) > 0 && !slices.Contains(r.kinds, event.Kind) {
+		return false
+	}
+	if len(r.fields) > 0 && event.Kind == Updated && !slices.ContainsFunc(r.fields, func(field string) bool {
+		return slices.Contains(event.Changes, field)
+	}) {
+		return false
+	}
+	for _, condition := range r.conditions {
+		if !condition(event) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Rule) render(event Event) (Message, error) {
+	message := Message{Event: event}
+	var renderErr error
+	if message.Body, renderErr = execute(r.body, event); renderErr != nil {
+		return Message{}, renderErr
+	}
+	if message.Subject, renderErr = execute(r.subject, event); renderErr != nil {
+		return Message{}, renderErr
+	}
+	for _, recipient := range r.recipients {
+		to, recipientErr := execute(recipient, event)
+		if recipientErr != nil {
+			return Message{}, recipientErr
+		}
+		// text/template renders the missing keys of the maps as `<no value>`
+		if to = strings.TrimSpace(to); to != "" && to != "<no value>" {
+			message.To = append(message.To, to)
+		}
+	}
+	return message, nil
+}
+
+// Notifier sends the messages of its rules
+type Notifier struct {
+	rules    []*Rule
+	eventLog EventLog
+	inFlight sync.WaitGroup
+}
+
+// NewNotifier creates a Notifier with the rules
+func NewNotifier(rules ...*Rule) *Notifier {
+	return &Notifier{rules: rules}
+}
+
+// WithRule adds the rule
+func (n *Notifier) WithRule(rule *Rule) *Notifier {
+	n.rules = append(n.rules, rule)
+	return n
+}
+
+// WithEventLog stores all the events in the log, so the consumers can replay the events they
+// missed, see views.NewEventReplayView
+func (n *Notifier) WithEventLog(log EventLog) *Notifier {
+	n.eventLog = log
+	return n
+}
+
+// Subscribed returns true if any of the rules may apply to the kind of events, or the events are
+// logged, so the callers can skip preparing the events nobody is interested in
+func (n *Notifier) Subscribed(kind EventKind) bool {
+	return n.eventLog != nil || slices.ContainsFunc(n.rules, func(r *Rule) bool {
+		return len(r.kinds) == 0 || slices.Contains(r.kinds, kind)
+	})
+}
+
+// Notify logs the event, if the notifier has an event log, and sends the messages of all the rules
+// matching it. Failures of a rule don't stop the other ones, the errors are joined.
+func (n *Notifier) Notify(ctx context.Context, event Event) error {
+	event = withChanges(event)
+	return errors.Join(n.log(ctx, event), n.send(ctx, event))
+}
+
+// Dispatch logs the event, if the notifier has an event log, and sends the messages of the rules
+// matching it in the background, so slow senders don't delay the caller. The sends outlive the
+// cancellation of the ctx, their failures are logged. Wait blocks until they complete.
+func (n *Notifier) Dispatch(ctx context.Context, event Event) error {
+	event = withChanges(event)
+	if logErr := n.log(ctx, event); logErr != nil {
+		return logErr
+	}
+	n.inFlight.Add(1)
+	go func() {
+		defer n.inFlight.Done()
+		if sendErr := n.send(context.WithoutCancel(ctx), event); sendErr != nil {
+			logrus.Errorf("Failed to notify about %s %s: %s", event.Model, event.Kind, sendErr)
+		}
+	}()
+	return nil
+}
+
+// Wait blocks until the messages sent in the background by Dispatch are delivered or fail, for
+// example on shutdown
+func (n *Notifier) Wait() {
+	n.inFlight.Wait()
+}
+
+func withChanges(event Event) Event {
+	if event.Kind == Updated && event.Changes == nil {
+		event.Changes = changedFields(event.Object, event.Previous)
+	}
+	return event
+}
+
+func (n *Notifier) log(ctx context.Context, event Event) error {
+	if n.eventLog == nil {
+		return nil
+	}
+	if appendErr := n.eventLog.Append(ctx, event); appendErr != nil {
+		return fmt.Errorf("logging %s %s event: %w", event.Model, event.Kind, appendErr)
+	}
+	return nil
+}
+
+// send sends the messages of all the rules matching the event, failures of a rule don't stop the
+// other ones
+func (n *Notifier) send(ctx context.Context, event Event) error {
+	errs := []error{}
+	for _, rule := range n.rules {
+		if !rule.matches(event) {
+			continue
+		}
+		message, renderErr := rule.render(event)
+		if renderErr != nil {
+			errs = append(errs, renderErr)
+			continue
+		}
+		if sendErr := rule.sender.Send(ctx, message); sendErr != nil {
+			errs = append(errs, fmt.Errorf("sending %s %s notification: %w", event.Model, event.Kind, sendErr))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func parseTemplate(name, text string) *template.Template {
+	return template.Must(template.New(name).Parse(text))
+}
+
+func execute(tmpl *template.Template, event Event) (string, error) {
+	if tmpl == nil {
+		return "", nil
+	}
+	var rendered bytes.Buffer
+	if executeErr := tmpl.Execute(&rendered, event); executeErr != nil {
+		return "", executeErr
+	}
+	return rendered.String(), nil
+}
@@ -0,0 +1,163 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSender struct {
+	messages []Message
+}
+
+func (s *recordingSender) Send(_ context.Context, message Message) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestNotifierRules(t *testing.T) {
+	// given
+	sender := &recordingSender{}
+	notifier := NewNotifier(
+		NewRule(sender, "Order {{.Object.id}} is {{.Object.status}}").
+			On(Updated).
+			When(FieldChanged("status")).
+			WithSubject("Order {{.Object.id}} {{.Kind}}").
+			WithRecipients("{{.Object.owner}}", "{{.Object.watcher}}"),
+	)
+	order := map[string]any{"id": 1, "status": "shipped", "owner": "jane@example.com"}
+
+	// when
+	createdErr := notifier.Notify(context.Background(), Event{Kind: Created, Model: "Order", Object: order})
+	unchangedErr := notifier.Notify(context.Background(), Event{
+		Kind: Updated, Model: "Order", Object: order, Previous: map[string]any{"id": 1, "status": "shipped"},
+	})
+	changedErr := notifier.Notify(context.Background(), Event{
+		Kind: Updated, Model: "Order", Object: order, Previous: map[string]any{"id": 1, "status": "paid"},
+	})
+
+	// then
+	assert.NoError(t, createdErr)
+	assert.NoError(t, unchangedErr)
+	assert.NoError(t, changedErr)
+	assert.Len(t, sender.messages, 1)
+	assert.Equal(t, []string{"jane@example.com"}, sender.messages[0].To)
+	assert.Equal(t, "Order 1 updated", sender.messages[0].Subject)
+	assert.Equal(t, "Order 1 is shipped", sender.messages[0].Body)
+	assert.True(t, notifier.Subscribed(Updated))
+	assert.False(t, notifier.Subscribed(Destroyed))
+}
+
+func TestRuleOnFields(t *testing.T) {
+	// given
+	sender := &recordingSender{}
+	notifier := NewNotifier(NewRule(sender, "Order {{.Object.id}} changed").OnFields("status", "total"))
+	previous := map[string]any{"id": 1, "status": "paid", "total": 10, "note": ""}
+
+	// when
+	createdErr := notifier.Notify(context.Background(), Event{Kind: Created, Model: "Order", Object: previous})
+	noteErr := notifier.Notify(context.Background(), Event{
+		Kind: Updated, Model: "Order", Previous: previous,
+		Object: map[string]any{"id": 1, "status": "paid", "total": 10, "note": "fragile"},
+	})
+	statusErr := notifier.Notify(context.Background(), Event{
+		Kind: Updated, Model: "Order", Previous: previous,
+		Object: map[string]any{"id": 1, "status": "shipped", "total": 10, "note": "fragile"},
+	})
+
+	// then
+	assert.NoError(t, createdErr)
+	assert.NoError(t, noteErr)
+	assert.NoError(t, statusErr)
+	assert.Len(t, sender.messages, 2)
+	assert.Nil(t, sender.messages[0].Event.Changes)
+	assert.Equal(t, []string{"note", "status"}, sender.messages[1].Event.Changes)
+}
+
+func TestEmailSender(t *testing.T) {
+	// given
+	var sentTo []string
+	var sent string
+	sender := NewEmailSender("smtp.example.com:587", "shop@example.com")
+	sender.sendMail = func(_ string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+		sentTo, sent = to, string(msg)
+		return nil
+	}
+
+	// when
+	err := sender.Send(context.Background(), Message{
+		To: []string{"jane@example.com"}, Subject: "Shipped\r\nBcc: eve@example.com", Body: "Your order was shipped",
+	})
+	noRecipientsErr := sender.Send(context.Background(), Message{Body: "Your order was shipped"})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"jane@example.com"}, sentTo)
+	assert.Equal(t, "From: shop@example.com\r\nTo: jane@example.com\r\nSubject: Shipped  Bcc: eve@example.com\r\n"+
+		"MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\nYour order was shipped", sent)
+	assert.Error(t, noRecipientsErr)
+}
+
+func TestWebhookSenders(t *testing.T) {
+	// given
+	bodies := []map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+	}))
+	defer server.Close()
+	message := Message{
+		Subject: "Order shipped", Body: "Order 1 is shipped",
+		Event: Event{Kind: Updated, Model: "Order", Object: map[string]any{"id": 1}},
+	}
+
+	// when
+	webhookErr := NewWebhookSender(server.URL).Send(context.Background(), message)
+	slackErr := NewSlackSender(server.URL).Send(context.Background(), message)
+
+	// then
+	assert.NoError(t, webhookErr)
+	assert.NoError(t, slackErr)
+	assert.Equal(t, []map[string]any{
+		{
+			"event": "updated", "model": "Order", "subject": "Order shipped", "body": "Order 1 is shipped",
+			"object": map[string]any{"id": float64(1)},
+		},
+		{"text": "*Order shipped*\nOrder 1 is shipped"},
+	}, bodies)
+}
+
+type cancelCheckingSender struct {
+	canceled bool
+}
+
+func (s *cancelCheckingSender) Send(ctx context.Context, _ Message) error {
+	s.canceled = ctx.Err() != nil
+	return nil
+}
+
+func TestNotifierDispatch(t *testing.T) {
+	// given
+	sender := &cancelCheckingSender{}
+	log := NewMemoryEventLog(10)
+	notifier := NewNotifier(NewRule(sender, "Order {{.Object.id}} {{.Kind}}")).WithEventLog(log)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// when
+	dispatchErr := notifier.Dispatch(ctx, Event{Kind: Created, Model: "Order", Object: map[string]any{"id": 1}})
+	cancel()
+	notifier.Wait()
+	logged, sinceErr := log.Since(context.Background(), "", nil, 10)
+
+	// then
+	assert.NoError(t, dispatchErr)
+	assert.NoError(t, sinceErr)
+	assert.Len(t, logged, 1)
+	assert.False(t, sender.canceled)
+}
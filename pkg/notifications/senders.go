@@ -0,0 +1,148 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// EmailSender sends the messages as plain text emails using SMTP
+type EmailSender struct {
+	addr     string
+	from     string
+	auth     smtp.Auth
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailSender creates an EmailSender using the SMTP server at the address, like
+// `smtp.example.com:587`, sending from the address
+func NewEmailSender(addr, from string) *EmailSender {
+	return &EmailSender{addr: addr, from: from, sendMail: smtp.SendMail}
+}
+
+// WithAuth authenticates to the SMTP server, for example using smtp.PlainAuth
+func (s *EmailSender) WithAuth(auth smtp.Auth) *EmailSender {
+	s.auth = auth
+	return s
+}
+
+func (s *EmailSender) Send(_ context.Context, message Message) error {
+	if len(message.To) == 0 {
+		return errors.New("the email has no recipients")
+	}
+	var email bytes.Buffer
+	fmt.Fprintf(&email, "From: %s\r\n", s.from)
+	fmt.Fprintf(&email, "To: %s\r\n", strings.Join(message.To, ", "))
+	fmt.Fprintf(&email, "Subject: %s\r\n", headerValue(message.Subject))
+	email.WriteString("MIME-Version: 1.0\r\n")
+	email.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	email.WriteString(message.Body)
+	return s.sendMail(s.addr, s.auth, s.from, message.To, email.Bytes())
+}
+
+// headerValue prevents the rendered values from injecting headers
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
+
+// WebhookSender posts the messages as JSON to a URL. By default the body is:
+//
+//	{"event": "updated", "model": "Order", "subject": "...", "body": "...", "object": {...}}
+//
+// The updates also include the changed fields, like `"changes": ["status"]`.
+// Other formats can be produced with WithPayloadFunc, see NewSlackSender.
+type WebhookSender struct {
+	url         string
+	client      *http.Client
+	headers     http.Header
+	payloadFunc func(message Message) any
+}
+
+// defaultWebhookTimeout bounds the requests of the webhook senders, so unresponsive endpoints
+// don't pile up the deliveries
+const defaultWebhookTimeout = 10 * time.Second
+
+// NewWebhookSender creates a WebhookSender posting to the URL, the requests time out after 10
+// seconds, see WithClient
+func NewWebhookSender(url string) *WebhookSender {
+	return &WebhookSender{
+		url:         url,
+		client:      &http.Client{Timeout: defaultWebhookTimeout},
+		headers:     http.Header{},
+		payloadFunc: defaultWebhookPayload,
+	}
+}
+
+// NewSlackSender creates a WebhookSender posting to a Slack incoming webhook, the subject is
+// prepended to the body in bold
+func NewSlackSender(webhookURL string) *WebhookSender {
+	return NewWebhookSender(webhookURL).WithPayloadFunc(slackPayload)
+}
+
+// WithClient replaces the default HTTP client, for example to change the timeout
+func (s *WebhookSender) WithClient(client *http.Client) *WebhookSender {
+	s.client = client
+	return s
+}
+
+// WithHeader sets a header of the requests, for example Authorization
+func (s *WebhookSender) WithHeader(key, value string) *WebhookSender {
+	s.headers.Set(key, value)
+	return s
+}
+
+// WithPayloadFunc replaces the function building the JSON body of the requests
+func (s *WebhookSender) WithPayloadFunc(f func(message Message) any) *WebhookSender {
+	s.payloadFunc = f
+	return s
+}
+
+func (s *WebhookSender) Send(ctx context.Context, message Message) error {
+	body, marshalErr := json.Marshal(s.payloadFunc(message))
+	if marshalErr != nil {
+		return marshalErr
+	}
+	request, requestErr := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if requestErr != nil {
+		return requestErr
+	}
+	request.Header = s.headers.Clone()
+	request.Header.Set("Content-Type", "application/json")
+	response, doErr := s.client.Do(request)
+	if doErr != nil {
+		return doErr
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+	return nil
+}
+
+func defaultWebhookPayload(message Message) any {
+	payload := map[string]any{
+		"event":   message.Event.Kind,
+		"model":   message.Event.Model,
+		"subject": message.Subject,
+		"body":    message.Body,
+		"object":  message.Event.Object,
+	}
+	if message.Event.Changes != nil {
+		payload["changes"] = message.Event.Changes
+	}
+	return payload
+}
+
+func slackPayload(message Message) any {
+	text := message.Body
+	if message.Subject != "" {
+		text = "*" + message.Subject + "*\n" + text
+	}
+	return map[string]string{"text": text}
+}
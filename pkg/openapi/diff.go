@@ -0,0 +1,339 @@
+// Package openapi describes the APIs using OpenAPI 3 documents and detects breaking changes between
+// two versions of a document, for example to fail the CI pipeline when a change of the API would
+// break the existing clients. The documents of the viewsets are built by ViewSet.OpenAPI, but the
+// compared ones can be generated by any tool, only the paths, the parameters, the JSON request and
+// response bodies and the schemas referenced from `#/components/schemas` are compared.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Document is the part of an OpenAPI 3 document built by the viewsets and compared by Diff
+type Document struct {
+	OpenAPI    string               `json:"openapi,omitempty"`
+	Info       *Info                `json:"info,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas,omitempty"`
+	} `json:"components"`
+}
+
+// Info holds the title and the version of the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// NewDocument creates an OpenAPI 3.0 document without any paths
+func NewDocument(title, version string) *Document {
+	document := &Document{
+		OpenAPI: "3.0.3",
+		Info:    &Info{Title: title, Version: version},
+		Paths:   map[string]*PathItem{},
+	}
+	document.Components.Schemas = map[string]*Schema{}
+	return document
+}
+
+// PathItem holds the operations of a path
+type PathItem struct {
+	Get        *Operation  `json:"get,omitempty"`
+	Put        *Operation  `json:"put,omitempty"`
+	Post       *Operation  `json:"post,omitempty"`
+	Delete     *Operation  `json:"delete,omitempty"`
+	Options    *Operation  `json:"options,omitempty"`
+	Head       *Operation  `json:"head,omitempty"`
+	Patch      *Operation  `json:"patch,omitempty"`
+	Parameters []Parameter `json:"parameters,omitempty"`
+}
+
+func (p *PathItem) operations() map[string]*Operation {
+	operations := map[string]*Operation{}
+	for method, operation := range map[string]*Operation{
+		"GET": p.Get, "PUT": p.Put, "POST": p.Post, "DELETE": p.Delete,
+		"OPTIONS": p.Options, "HEAD": p.Head, "PATCH": p.Patch,
+	} {
+		if operation != nil {
+			operations[method] = operation
+		}
+	}
+	return operations
+}
+
+// Operation is a single method of a path
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path, query, header or cookie parameter of an operation
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// RequestBody is the body of the requests of an operation
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the part of a JSON schema built by the viewsets, only the references, the types, the
+// properties and the items are compared by Diff
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        any                `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Nullable    bool               `json:"nullable,omitempty"`
+	ReadOnly    bool               `json:"readOnly,omitempty"`
+	WriteOnly   bool               `json:"writeOnly,omitempty"`
+	Enum        []any              `json:"enum,omitempty"`
+	Default     any                `json:"default,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Example     any                `json:"example,omitempty"`
+}
+
+// typeName returns the type of the schema, including the format, like `string (date-time)`. OpenAPI
+// 3.1 lists of types are joined with `|`.
+func (s *Schema) typeName() string {
+	var name string
+	switch typed := s.Type.(type) {
+	case string:
+		name = typed
+	case []any:
+		types := make([]string, 0, len(typed))
+		for _, t := range typed {
+			types = append(types, fmt.Sprint(t))
+		}
+		sort.Strings(types)
+		name = strings.Join(types, "|")
+	}
+	if s.Format != "" {
+		name += " (" + s.Format + ")"
+	}
+	return name
+}
+
+// Change is a breaking change of the API
+type Change struct {
+	// Operation is the method and the path, like `GET /products`, or only the path if it was removed
+	Operation string `json:"operation"`
+	Message   string `json:"message"`
+}
+
+func (c Change) String() string {
+	return c.Operation + ": " + c.Message
+}
+
+// Parse decodes an OpenAPI 3 document in JSON
+func Parse(raw []byte) (*Document, error) {
+	var document Document
+	if decodeErr := json.Unmarshal(raw, &document); decodeErr != nil {
+		return nil, fmt.Errorf("could not decode the OpenAPI document: %w", decodeErr)
+	}
+	return &document, nil
+}
+
+// Diff returns the changes of the new document breaking the clients of the old one: removed paths,
+// operations and fields, changed types, and new required parameters and request fields
+func Diff(oldDocument, newDocument *Document) []Change {
+	d := &differ{old: oldDocument, new: newDocument}
+	for _, path := range sortedKeys(oldDocument.Paths) {
+		newItem, ok := newDocument.Paths[path]
+		if !ok {
+			d.report(path, "path removed")
+			continue
+		}
+		oldItem := oldDocument.Paths[path]
+		newOperations := newItem.operations()
+		oldOperations := oldItem.operations()
+		for _, method := range sortedKeys(oldOperations) {
+			operation := method + " " + path
+			newOperation, ok := newOperations[method]
+			if !ok {
+				d.report(operation, "operation removed")
+				continue
+			}
+			d.operation(
+				operation, oldOperations[method], newOperation,
+				append(append([]Parameter{}, oldItem.Parameters...), oldOperations[method].Parameters...),
+				append(append([]Parameter{}, newItem.Parameters...), newOperation.Parameters...),
+			)
+		}
+	}
+	return d.changes
+}
+
+type differ struct {
+	old, new *Document
+	changes  []Change
+}
+
+func (d *differ) report(operation, format string, args ...any) {
+	d.changes = append(d.changes, Change{Operation: operation, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *differ) operation(name string, oldOperation, newOperation *Operation, oldParameters, newParameters []Parameter) {
+	d.parameters(name, oldParameters, newParameters)
+
+	oldBody, newBody := oldOperation.RequestBody, newOperation.RequestBody
+	if newBody != nil && newBody.Required && (oldBody == nil || !oldBody.Required) {
+		d.report(name, "request body is now required")
+	}
+	if oldBody != nil && newBody != nil {
+		if oldSchema, newSchema := jsonSchema(oldBody.Content), jsonSchema(newBody.Content); oldSchema != nil && newSchema != nil {
+			d.schema(name, "request field", "", oldSchema, newSchema, true, map[[2]*Schema]bool{})
+		}
+	}
+
+	for _, status := range sortedKeys(oldOperation.Responses) {
+		oldResponse, newResponse := oldOperation.Responses[status], newOperation.Responses[status]
+		if oldResponse == nil || newResponse == nil {
+			continue
+		}
+		oldSchema, newSchema := jsonSchema(oldResponse.Content), jsonSchema(newResponse.Content)
+		if oldSchema != nil && newSchema != nil {
+			d.schema(name, "response "+status+" field", "", oldSchema, newSchema, false, map[[2]*Schema]bool{})
+		}
+	}
+}
+
+func (d *differ) parameters(name string, oldParameters, newParameters []Parameter) {
+	old := map[string]Parameter{}
+	for _, parameter := range oldParameters {
+		old[parameter.In+" "+parameter.Name] = parameter
+	}
+	for _, parameter := range newParameters {
+		oldParameter, existed := old[parameter.In+" "+parameter.Name]
+		switch {
+		case !existed && parameter.Required:
+			d.report(name, "required %s parameter `%s` added", parameter.In, parameter.Name)
+		case existed && parameter.Required && !oldParameter.Required:
+			d.report(name, "%s parameter `%s` is now required", parameter.In, parameter.Name)
+		case existed && oldParameter.Schema != nil && parameter.Schema != nil:
+			oldType := d.resolve(d.old, oldParameter.Schema).typeName()
+			newType := d.resolve(d.new, parameter.Schema).typeName()
+			if oldType != newType {
+				d.report(name, "%s parameter `%s` changed type from %s to %s", parameter.In, parameter.Name, oldType, newType)
+			}
+		}
+	}
+}
+
+// schema compares the schemas of the bodies, the input schemas are the request bodies, where new
+// required fields break the clients. The pairs of compared schemas are tracked, so the recursive
+// schemas are compared once.
+func (d *differ) schema(
+	operation, kind, field string, oldSchema, newSchema *Schema, input bool, compared map[[2]*Schema]bool,
+) {
+	oldSchema, newSchema = d.resolve(d.old, oldSchema), d.resolve(d.new, newSchema)
+	if compared[[2]*Schema{oldSchema, newSchema}] {
+		return
+	}
+	compared[[2]*Schema{oldSchema, newSchema}] = true
+
+	if oldType, newType := oldSchema.typeName(), newSchema.typeName(); oldType != newType && oldType != "" {
+		d.report(operation, "%s `%s` changed type from %s to %s", kind, displayField(field), oldType, newType)
+		return
+	}
+	if oldSchema.Items != nil && newSchema.Items != nil {
+		d.schema(operation, kind, field+"[]", oldSchema.Items, newSchema.Items, input, compared)
+	}
+	for _, property := range sortedKeys(oldSchema.Properties) {
+		newProperty, ok := newSchema.Properties[property]
+		if !ok {
+			d.report(operation, "%s `%s` removed", kind, joinField(field, property))
+			continue
+		}
+		d.schema(operation, kind, joinField(field, property), oldSchema.Properties[property], newProperty, input, compared)
+	}
+	if !input {
+		return
+	}
+	oldRequired := map[string]bool{}
+	for _, property := range oldSchema.Required {
+		oldRequired[property] = true
+	}
+	for _, property := range newSchema.Required {
+		if oldRequired[property] {
+			continue
+		}
+		if _, existed := oldSchema.Properties[property]; existed {
+			d.report(operation, "%s `%s` is now required", kind, joinField(field, property))
+		} else {
+			d.report(operation, "required %s `%s` added", kind, joinField(field, property))
+		}
+	}
+}
+
+// resolve follows the references to the schemas of the components
+func (d *differ) resolve(document *Document, schema *Schema) *Schema {
+	for seen := 0; schema.Ref != "" && seen < 32; seen++ {
+		name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+		referenced := document.Components.Schemas[name]
+		if !ok || referenced == nil {
+			return &Schema{}
+		}
+		schema = referenced
+	}
+	return schema
+}
+
+// jsonSchema returns the schema of the JSON content
+func jsonSchema(content map[string]MediaType) *Schema {
+	for _, mediaType := range sortedKeys(content) {
+		if strings.HasPrefix(mediaType, "application/json") || strings.HasSuffix(mediaType, "+json") {
+			return content[mediaType].Schema
+		}
+	}
+	return nil
+}
+
+func joinField(field, property string) string {
+	if field == "" {
+		return property
+	}
+	return field + "." + property
+}
+
+func displayField(field string) string {
+	if field == "" {
+		return "(body)"
+	}
+	return field
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
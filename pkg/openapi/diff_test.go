@@ -0,0 +1,138 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const oldDocument = `{
+  "openapi": "3.0.3",
+  "paths": {
+    "/products": {
+      "get": {
+        "parameters": [{"name": "page", "in": "query", "schema": {"type": "integer"}}],
+        "responses": {"200": {"content": {"application/json": {"schema": {
+          "type": "array", "items": {"$ref": "#/components/schemas/Product"}
+        }}}}}
+      },
+      "post": {
+        "requestBody": {"content": {"application/json": {"schema": {
+          "type": "object",
+          "required": ["name"],
+          "properties": {"name": {"type": "string"}, "price": {"type": "number"}, "notes": {"type": "string"}}
+        }}}},
+        "responses": {"201": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Product"}}}}}
+      }
+    },
+    "/products/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+      "get": {},
+      "delete": {}
+    },
+    "/legacy": {"get": {}}
+  },
+  "components": {"schemas": {"Product": {
+    "type": "object",
+    "properties": {
+      "id": {"type": "integer"},
+      "name": {"type": "string"},
+      "price": {"type": "number"},
+      "category": {"$ref": "#/components/schemas/Category"}
+    }
+  }, "Category": {
+    "type": "object",
+    "properties": {"id": {"type": "integer"}, "slug": {"type": "string"}}
+  }}}
+}`
+
+const newDocument = `{
+  "openapi": "3.0.3",
+  "paths": {
+    "/products": {
+      "get": {
+        "parameters": [
+          {"name": "page", "in": "query", "schema": {"type": "string"}},
+          {"name": "X-Tenant", "in": "header", "required": true, "schema": {"type": "string"}}
+        ],
+        "responses": {"200": {"content": {"application/json": {"schema": {
+          "type": "array", "items": {"$ref": "#/components/schemas/Product"}
+        }}}}}
+      },
+      "post": {
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {
+          "type": "object",
+          "required": ["name", "price", "sku"],
+          "properties": {"name": {"type": "string"}, "price": {"type": "number"}, "sku": {"type": "string"}}
+        }}}},
+        "responses": {"201": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Product"}}}}}
+      }
+    },
+    "/products/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+      "get": {}
+    }
+  },
+  "components": {"schemas": {"Product": {
+    "type": "object",
+    "properties": {
+      "id": {"type": "integer"},
+      "name": {"type": "string"},
+      "price": {"type": "string", "format": "decimal"},
+      "category": {"$ref": "#/components/schemas/Category"},
+      "tags": {"type": "array", "items": {"type": "string"}}
+    }
+  }, "Category": {
+    "type": "object",
+    "properties": {"id": {"type": "integer"}}
+  }}}
+}`
+
+func TestDiff(t *testing.T) {
+	// given
+	oldParsed, oldErr := Parse([]byte(oldDocument))
+	newParsed, newErr := Parse([]byte(newDocument))
+	assert.NoError(t, oldErr)
+	assert.NoError(t, newErr)
+
+	// when
+	changes := Diff(oldParsed, newParsed)
+
+	// then
+	messages := make([]string, 0, len(changes))
+	for _, change := range changes {
+		messages = append(messages, change.String())
+	}
+	assert.Equal(t, []string{
+		"/legacy: path removed",
+		"GET /products: query parameter `page` changed type from integer to string",
+		"GET /products: required header parameter `X-Tenant` added",
+		"GET /products: response 200 field `[].category.slug` removed",
+		"GET /products: response 200 field `[].price` changed type from number to string (decimal)",
+		"POST /products: request body is now required",
+		"POST /products: request field `notes` removed",
+		"POST /products: request field `price` is now required",
+		"POST /products: required request field `sku` added",
+		"POST /products: response 201 field `category.slug` removed",
+		"POST /products: response 201 field `price` changed type from number to string (decimal)",
+		"DELETE /products/{id}: operation removed",
+	}, messages)
+}
+
+func TestDiffWithoutChanges(t *testing.T) {
+	// given
+	parsed, parseErr := Parse([]byte(oldDocument))
+	assert.NoError(t, parseErr)
+
+	// when
+	changes := Diff(parsed, parsed)
+
+	// then
+	assert.Empty(t, changes)
+}
+
+func TestParseInvalidDocument(t *testing.T) {
+	_, parseErr := Parse([]byte(`{"paths": []}`))
+
+	assert.ErrorContains(t, parseErr, "could not decode the OpenAPI document")
+}
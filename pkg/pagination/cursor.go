@@ -0,0 +1,157 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+)
+
+// CursorQueryParam is the query parameter holding the cursor of CursorPagination
+const CursorQueryParam = "cursor"
+
+// CursorResponse is the response body of CursorPagination. Next and Previous are the URLs of the
+// neighbouring pages, relative to the host, or nil if there is no such page.
+type CursorResponse struct {
+	Next     *string `json:"next"`
+	Previous *string `json:"previous"`
+	Results  []any   `json:"results"`
+}
+
+// cursor is encoded in the `cursor` query parameter, it holds the ordering of the pagination, so
+// cursors of other orderings are rejected, and the value of the field the page starts after
+type cursor struct {
+	Field      string `json:"f"`
+	Descending bool   `json:"d"`
+	Value      any    `json:"v"`
+	// Reverse is set for cursors of previous pages, which are listed in the reversed order
+	Reverse bool `json:"r,omitempty"`
+}
+
+// CursorPagination pages the objects by the values of a single field, like DRF's CursorPagination.
+// The clients receive opaque cursors in the links of the neighbouring pages, the query drivers
+// translate them to keyset queries, like `WHERE id > 42 ORDER BY id LIMIT 21`, so paging large
+// tables doesn't require OFFSET scans. The responses don't contain the count of the objects.
+//
+// The field should be unique and not nullable, otherwise the objects sharing its value may be
+// skipped. Its values are passed in the cursors using JSON, so they should be numbers, strings or
+// times, the query drivers convert them back to the types of the model's field.
+type CursorPagination struct {
+	field       string
+	descending  bool
+	pageSize    int
+	maxPageSize int
+}
+
+// NewCursorPagination creates CursorPagination ordering the objects by the field, descending if
+// prefixed with `-`, for example `-created_at`. The page size is 100 if not positive.
+func NewCursorPagination(ordering string, pageSize int) *CursorPagination {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	field, descending := strings.CutPrefix(ordering, "-")
+	return &CursorPagination{field: field, descending: descending, pageSize: pageSize}
+}
+
+// WithMaxPageSize allows the clients to choose the page size using the `page_size` query
+// parameter, up to the limit
+func (p *CursorPagination) WithMaxPageSize(maxPageSize int) *CursorPagination {
+	p.maxPageSize = maxPageSize
+	return p
+}
+
+// Window implements Paginator, one more object than the page size is requested to check if there
+// is a next page
+func (p *CursorPagination) Window(ctx *gin.Context) (grfctx.Window, error) {
+	keyset := &grfctx.Keyset{Field: p.field, Descending: p.descending}
+	if raw := grfctx.Query(ctx, CursorQueryParam); raw != "" {
+		c, decodeErr := p.decode(raw)
+		if decodeErr != nil {
+			return grfctx.Window{}, decodeErr
+		}
+		keyset.After = c.Value
+		keyset.Descending = p.descending != c.Reverse
+	}
+	return grfctx.Window{Limit: p.size(ctx) + 1, Keyset: keyset}, nil
+}
+
+// Format implements Paginator
+func (p *CursorPagination) Format(ctx *gin.Context, page Page) (any, error) {
+	results, internalValues := page.Results, page.InternalValues
+	hasMore := len(results) >= page.Window.Limit
+	if hasMore {
+		results, internalValues = results[:page.Window.Limit-1], internalValues[:page.Window.Limit-1]
+	}
+	reversed := page.Window.Keyset.Descending != p.descending
+	if reversed {
+		results, internalValues = slices.Clone(results), slices.Clone(internalValues)
+		slices.Reverse(results)
+		slices.Reverse(internalValues)
+	}
+	response := CursorResponse{Results: results}
+	if len(internalValues) == 0 {
+		return response, nil
+	}
+	// Forward pages have a next page if more objects were listed, and a previous one if they
+	// start after a cursor. Reversed pages were reached from their next page.
+	hasNext, hasPrevious := hasMore, page.Window.Keyset.After != nil
+	if reversed {
+		hasNext, hasPrevious = true, hasMore
+	}
+	if hasNext {
+		next := p.pageURL(ctx, cursor{Value: internalValues[len(internalValues)-1][p.field]})
+		response.Next = &next
+	}
+	if hasPrevious {
+		previous := p.pageURL(ctx, cursor{Value: internalValues[0][p.field], Reverse: true})
+		response.Previous = &previous
+	}
+	return response, nil
+}
+
+func (p *CursorPagination) size(ctx *gin.Context) int {
+	defaultSize, maxSize := pageSizes(ctx, p.pageSize, p.maxPageSize)
+	if maxSize <= 0 {
+		return defaultSize
+	}
+	return requestedPageSize(ctx, PageSizeQueryParam, defaultSize, maxSize)
+}
+
+func (p *CursorPagination) decode(raw string) (cursor, error) {
+	encoded, decodeErr := base64.RawURLEncoding.DecodeString(raw)
+	if decodeErr != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var c cursor
+	if parseErr := decoder.Decode(&c); parseErr != nil || c.Value == nil {
+		return cursor{}, ErrInvalidCursor
+	}
+	if c.Field != p.field || c.Descending != p.descending {
+		return cursor{}, ErrInvalidCursor
+	}
+	if number, ok := c.Value.(json.Number); ok {
+		if integer, intErr := number.Int64(); intErr == nil {
+			c.Value = integer
+		} else if float, floatErr := number.Float64(); floatErr == nil {
+			c.Value = float
+		}
+	}
+	return c, nil
+}
+
+// pageURL returns the URL of the request with the cursor replaced
+func (p *CursorPagination) pageURL(ctx *gin.Context, c cursor) string {
+	c.Field, c.Descending = p.field, p.descending
+	encoded, _ := json.Marshal(c)
+	query := ctx.Request.URL.Query()
+	query.Set(CursorQueryParam, base64.RawURLEncoding.EncodeToString(encoded))
+	location := url.URL{Path: ctx.Request.URL.Path, RawQuery: query.Encode()}
+	return location.String()
+}
@@ -0,0 +1,78 @@
+package pagination
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+)
+
+const (
+	// LimitQueryParam is the query parameter holding the maximum number of objects in the response
+	LimitQueryParam = "limit"
+	// OffsetQueryParam is the query parameter holding the number of objects to skip
+	OffsetQueryParam = "offset"
+)
+
+// LimitOffsetPagination reads the window from the `limit` and `offset` query parameters, like DRF's
+// LimitOffsetPagination. Invalid values fall back to the defaults, offsets out of range result in
+// empty pages. The response body is the same as of PageNumberPagination.
+type LimitOffsetPagination struct {
+	defaultLimit int
+	maxLimit     int
+}
+
+// NewLimitOffsetPagination creates LimitOffsetPagination with the limit used when the request
+// doesn't contain the `limit` query parameter, 100 if not positive
+func NewLimitOffsetPagination(defaultLimit int) *LimitOffsetPagination {
+	if defaultLimit <= 0 {
+		defaultLimit = defaultPageSize
+	}
+	return &LimitOffsetPagination{defaultLimit: defaultLimit}
+}
+
+// WithMaxLimit limits the limit requested by the clients, larger limits are reduced to it
+func (p *LimitOffsetPagination) WithMaxLimit(maxLimit int) *LimitOffsetPagination {
+	p.maxLimit = maxLimit
+	return p
+}
+
+// Window implements Paginator
+func (p *LimitOffsetPagination) Window(ctx *gin.Context) (grfctx.Window, error) {
+	defaultLimit, maxLimit := pageSizes(ctx, p.defaultLimit, p.maxLimit)
+	limit := requestedPageSize(ctx, LimitQueryParam, defaultLimit, maxLimit)
+	offset := 0
+	if requested, parseErr := strconv.Atoi(grfctx.Query(ctx, OffsetQueryParam)); parseErr == nil && requested > 0 {
+		offset = requested
+	}
+	return grfctx.Window{Offset: offset, Limit: limit}, nil
+}
+
+// Format implements Paginator
+func (p *LimitOffsetPagination) Format(ctx *gin.Context, page Page) (any, error) {
+	response := PageNumberResponse{Count: page.Count, Results: page.Results}
+	if page.Window.Offset+page.Window.Limit < page.Count {
+		next := limitOffsetURL(ctx, page.Window.Limit, page.Window.Offset+page.Window.Limit)
+		response.Next = &next
+	}
+	if page.Window.Offset > 0 {
+		previous := limitOffsetURL(ctx, page.Window.Limit, max(page.Window.Offset-page.Window.Limit, 0))
+		response.Previous = &previous
+	}
+	return response, nil
+}
+
+// limitOffsetURL returns the URL of the request with the limit and offset replaced, the offset
+// parameter is removed for the first page
+func limitOffsetURL(ctx *gin.Context, limit, offset int) string {
+	query := ctx.Request.URL.Query()
+	query.Set(LimitQueryParam, strconv.Itoa(limit))
+	if offset == 0 {
+		query.Del(OffsetQueryParam)
+	} else {
+		query.Set(OffsetQueryParam, strconv.Itoa(offset))
+	}
+	location := url.URL{Path: ctx.Request.URL.Path, RawQuery: query.Encode()}
+	return location.String()
+}
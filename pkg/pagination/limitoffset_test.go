@@ -0,0 +1,74 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitOffsetPagination(t *testing.T) {
+	tests := []struct {
+		name             string
+		query            string
+		expectedWindow   grfctx.Window
+		expectedNext     string
+		expectedPrevious string
+	}{
+		{
+			name:           "defaults",
+			query:          "",
+			expectedWindow: grfctx.Window{Offset: 0, Limit: 10},
+			expectedNext:   "/products?limit=10&offset=10",
+		},
+		{
+			name:             "middle",
+			query:            "limit=5&offset=10",
+			expectedWindow:   grfctx.Window{Offset: 10, Limit: 5},
+			expectedNext:     "/products?limit=5&offset=15",
+			expectedPrevious: "/products?limit=5&offset=5",
+		},
+		{
+			name:             "max limit",
+			query:            "limit=500&offset=5",
+			expectedWindow:   grfctx.Window{Offset: 5, Limit: 20},
+			expectedPrevious: "/products?limit=20",
+		},
+		{
+			name:           "invalid values",
+			query:          "limit=all&offset=-5",
+			expectedWindow: grfctx.Window{Offset: 0, Limit: 10},
+			expectedNext:   "/products?limit=10&offset=10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest("GET", "/products?"+tt.query, nil)
+			paginator := NewLimitOffsetPagination(10).WithMaxLimit(20)
+
+			// when
+			window, windowErr := paginator.Window(ctx)
+			formatted, formatErr := paginator.Format(ctx, Page{Results: []any{}, Count: 25, Window: window})
+
+			// then
+			assert.NoError(t, windowErr)
+			assert.NoError(t, formatErr)
+			assert.Equal(t, tt.expectedWindow, window)
+			response := formatted.(PageNumberResponse)
+			if tt.expectedNext == "" {
+				assert.Nil(t, response.Next)
+			} else {
+				assert.Equal(t, tt.expectedNext, *response.Next)
+			}
+			if tt.expectedPrevious == "" {
+				assert.Nil(t, response.Previous)
+			} else {
+				assert.Equal(t, tt.expectedPrevious, *response.Previous)
+			}
+		})
+	}
+}
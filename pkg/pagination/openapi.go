@@ -0,0 +1,76 @@
+package pagination
+
+import "github.com/glothriel/grf/pkg/openapi"
+
+// Documented is implemented by the paginators describing their query parameters and response bodies
+// in the OpenAPI documents, see ViewSet.OpenAPI
+type Documented interface {
+	OpenAPIParameters() []openapi.Parameter
+	// OpenAPISchema returns the schema of the response body, the results are described by the schema
+	OpenAPISchema(results *openapi.Schema) *openapi.Schema
+}
+
+// OpenAPIParameters implements Documented
+func (p *PageNumberPagination) OpenAPIParameters() []openapi.Parameter {
+	return []openapi.Parameter{
+		integerParameter(PageQueryParam, "The page number, starting from 1"),
+		integerParameter(PageSizeQueryParam, "The number of the objects on a page"),
+	}
+}
+
+// OpenAPISchema implements Documented
+func (p *PageNumberPagination) OpenAPISchema(results *openapi.Schema) *openapi.Schema {
+	return envelopeSchema(results, true)
+}
+
+// OpenAPIParameters implements Documented
+func (p *LimitOffsetPagination) OpenAPIParameters() []openapi.Parameter {
+	return []openapi.Parameter{
+		integerParameter(LimitQueryParam, "The maximum number of the objects in the response"),
+		integerParameter(OffsetQueryParam, "The number of the objects to skip"),
+	}
+}
+
+// OpenAPISchema implements Documented
+func (p *LimitOffsetPagination) OpenAPISchema(results *openapi.Schema) *openapi.Schema {
+	return envelopeSchema(results, true)
+}
+
+// OpenAPIParameters implements Documented, the page size can be chosen only if the maximum is set
+func (p *CursorPagination) OpenAPIParameters() []openapi.Parameter {
+	parameters := []openapi.Parameter{{
+		Name: CursorQueryParam, In: "query", Description: "The cursor from the links of the neighbouring pages",
+		Schema: &openapi.Schema{Type: "string"},
+	}}
+	if p.maxPageSize > 0 {
+		parameters = append(parameters, integerParameter(PageSizeQueryParam, "The number of the objects on a page"))
+	}
+	return parameters
+}
+
+// OpenAPISchema implements Documented
+func (p *CursorPagination) OpenAPISchema(results *openapi.Schema) *openapi.Schema {
+	return envelopeSchema(results, false)
+}
+
+func integerParameter(name, description string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: description, Schema: &openapi.Schema{Type: "integer"}}
+}
+
+// envelopeSchema describes PageNumberResponse, or CursorResponse if the pages aren't counted
+func envelopeSchema(results *openapi.Schema, counted bool) *openapi.Schema {
+	schema := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"next":     {Type: "string", Nullable: true},
+			"previous": {Type: "string", Nullable: true},
+			"results":  {Type: "array", Items: results},
+		},
+		Required: []string{"next", "previous", "results"},
+	}
+	if counted {
+		schema.Properties["count"] = &openapi.Schema{Type: "integer"}
+		schema.Required = append([]string{"count"}, schema.Required...)
+	}
+	return schema
+}
@@ -0,0 +1,106 @@
+package pagination
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+)
+
+const (
+	// PageQueryParam is the query parameter holding the page number, starting from 1
+	PageQueryParam = "page"
+	// PageSizeQueryParam is the query parameter holding the number of objects on a page
+	PageSizeQueryParam = "page_size"
+
+	// DefaultMaxPageSize limits the page size requested by the clients of the viewsets paginated by
+	// WithMandatoryPagination, unless they set another maximum
+	DefaultMaxPageSize = 1000
+
+	defaultPageSize = 100
+)
+
+// PageNumberResponse is the response body of PageNumberPagination and LimitOffsetPagination. Next
+// and Previous are the URLs of the neighbouring pages, relative to the host, or nil if there is no
+// such page.
+type PageNumberResponse struct {
+	Count    int     `json:"count"`
+	Next     *string `json:"next"`
+	Previous *string `json:"previous"`
+	Results  []any   `json:"results"`
+}
+
+// PageNumberPagination reads the page number from the `page` query parameter and the page size from
+// the `page_size` query parameter, like DRF's PageNumberPagination. Invalid page sizes fall back to
+// the default one, pages out of range are responded with 404.
+type PageNumberPagination struct {
+	pageSize    int
+	maxPageSize int
+}
+
+// NewPageNumberPagination creates PageNumberPagination with the default page size, used when the
+// request doesn't contain the `page_size` query parameter, 100 if not positive
+func NewPageNumberPagination(pageSize int) *PageNumberPagination {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	return &PageNumberPagination{pageSize: pageSize}
+}
+
+// WithMaxPageSize limits the page size requested by the clients, larger page sizes are reduced to
+// the limit
+func (p *PageNumberPagination) WithMaxPageSize(maxPageSize int) *PageNumberPagination {
+	p.maxPageSize = maxPageSize
+	return p
+}
+
+// Window implements Paginator
+func (p *PageNumberPagination) Window(ctx *gin.Context) (grfctx.Window, error) {
+	page := 1
+	if rawPage := grfctx.Query(ctx, PageQueryParam); rawPage != "" {
+		var parseErr error
+		page, parseErr = strconv.Atoi(rawPage)
+		if parseErr != nil || page < 1 {
+			return grfctx.Window{}, ErrInvalidPage
+		}
+	}
+	pageSize := p.size(ctx)
+	return grfctx.Window{Offset: (page - 1) * pageSize, Limit: pageSize}, nil
+}
+
+// Format implements Paginator
+func (p *PageNumberPagination) Format(ctx *gin.Context, page Page) (any, error) {
+	if page.Window.Offset > 0 && page.Window.Offset >= page.Count {
+		return nil, ErrInvalidPage
+	}
+	number := page.Window.Offset/page.Window.Limit + 1
+	response := PageNumberResponse{Count: page.Count, Results: page.Results}
+	if page.Window.Offset+page.Window.Limit < page.Count {
+		next := pageURL(ctx, number+1)
+		response.Next = &next
+	}
+	if number > 1 {
+		previous := pageURL(ctx, number-1)
+		response.Previous = &previous
+	}
+	return response, nil
+}
+
+func (p *PageNumberPagination) size(ctx *gin.Context) int {
+	defaultSize, maxSize := pageSizes(ctx, p.pageSize, p.maxPageSize)
+	return requestedPageSize(ctx, PageSizeQueryParam, defaultSize, maxSize)
+}
+
+// pageURL returns the URL of the request with the page number replaced, the page parameter is
+// removed for the first page
+func pageURL(ctx *gin.Context, number int) string {
+	query := ctx.Request.URL.Query()
+	if number == 1 {
+		query.Del(PageQueryParam)
+	} else {
+		query.Set(PageQueryParam, strconv.Itoa(number))
+	}
+	location := url.URL{Path: ctx.Request.URL.Path, RawQuery: query.Encode()}
+	return location.String()
+}
@@ -0,0 +1,42 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageNumberPaginationWindow(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedWindow grfctx.Window
+		expectedErr    error
+	}{
+		{name: "no params", query: "", expectedWindow: grfctx.Window{Offset: 0, Limit: 10}},
+		{name: "page", query: "page=3", expectedWindow: grfctx.Window{Offset: 20, Limit: 10}},
+		{name: "page size", query: "page=3&page_size=5", expectedWindow: grfctx.Window{Offset: 10, Limit: 5}},
+		{name: "max page size", query: "page_size=500", expectedWindow: grfctx.Window{Offset: 0, Limit: 50}},
+		{name: "invalid page size", query: "page_size=-1", expectedWindow: grfctx.Window{Offset: 0, Limit: 10}},
+		{name: "zero page", query: "page=0", expectedErr: ErrInvalidPage},
+		{name: "not a number", query: "page=last", expectedErr: ErrInvalidPage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest("GET", "/products?"+tt.query, nil)
+			paginator := NewPageNumberPagination(10).WithMaxPageSize(50)
+
+			// when
+			window, err := paginator.Window(ctx)
+
+			// then
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expectedWindow, window)
+		})
+	}
+}
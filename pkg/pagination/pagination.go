@@ -0,0 +1,70 @@
+// Package pagination splits the results of the list views into pages, independently of the query
+// driver. Paginators are enabled using ViewSet.WithPagination.
+package pagination
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+)
+
+// ErrInvalidPage is returned for pages out of range, it's written as 404 response
+var ErrInvalidPage = fmt.Errorf("invalid page: %w", common.ErrorNotFound)
+
+// ErrInvalidCursor is returned for malformed cursors and cursors of other orderings, it's written as
+// 404 response
+var ErrInvalidCursor = fmt.Errorf("invalid cursor: %w", common.ErrorNotFound)
+
+// Page is a single page of the list
+type Page struct {
+	// Results are the representations of the listed objects
+	Results []any
+	// InternalValues are the listed objects, ordered like the results
+	InternalValues []models.InternalValue
+	// Count is the number of objects matching the filters of the request, on all the pages. It's
+	// not set for keyset windows, which aren't counted.
+	Count int
+	// Window is the slice of the list the results come from
+	Window grfctx.Window
+}
+
+// Paginator reads the requested page from the request and builds the response body. The list view
+// passes the window to the query driver, see grfctx.CurrentWindow, and counts the objects if the
+// driver implements queries.Counter, unless the window is a keyset one.
+type Paginator interface {
+	// Window returns the slice of the list requested
+	Window(ctx *gin.Context) (grfctx.Window, error)
+	// Format builds the response body of the page
+	Format(ctx *gin.Context, page Page) (any, error)
+}
+
+// pageSizes returns the default and the maximum page size, the ones configured on the view, see
+// grfctx.ViewSettings, take precedence over the paginator's ones
+func pageSizes(ctx *gin.Context, defaultSize, maxSize int) (int, int) {
+	if metadata, ok := grfctx.Get(ctx); ok {
+		if metadata.View.PageSize > 0 {
+			defaultSize = metadata.View.PageSize
+		}
+		if metadata.View.MaxPageSize > 0 {
+			maxSize = metadata.View.MaxPageSize
+		}
+	}
+	return defaultSize, maxSize
+}
+
+// requestedPageSize reads the page size from the query parameter, falling back to the default one
+// for missing and invalid values, and limits it to the maximum, if positive
+func requestedPageSize(ctx *gin.Context, param string, defaultSize, maxSize int) int {
+	size := defaultSize
+	if requested, parseErr := strconv.Atoi(grfctx.Query(ctx, param)); parseErr == nil && requested > 0 {
+		size = requested
+	}
+	if maxSize > 0 && size > maxSize {
+		size = maxSize
+	}
+	return size
+}
@@ -11,6 +11,23 @@ type Pagination interface {
 	Format(*gin.Context, []any) (any, error)
 }
 
+// NoPagination is the Pagination of the query drivers not paginating the lists by themselves, the
+// paginations of the views are applied using the windows, see grfctx.CurrentWindow
+type NoPagination struct{}
+
+func (NoPagination) Apply(*gin.Context) {}
+
+func (NoPagination) Format(_ *gin.Context, elems []any) (any, error) {
+	return elems, nil
+}
+
+// Paginates reports if the lists are paginated by the query driver's pagination, such drivers can't
+// be combined with the paginations of the views
+func Paginates(pagination Pagination) bool {
+	_, none := pagination.(NoPagination)
+	return !none
+}
+
 type CompositeQueryMod struct {
 	children []QueryMod
 }
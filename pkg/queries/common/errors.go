@@ -4,3 +4,39 @@ import "errors"
 
 var ErrorInternal = errors.New("internal error")
 var ErrorNotFound = errors.New("not found")
+
+// ErrOperationNotAllowed is wrapped by the errors of the drivers, that don't support the operation,
+// like the write operations of the read-only drivers, the views respond to them with 405
+var ErrOperationNotAllowed = errors.New("operation not allowed")
+
+// ErrCountUnsupported is returned by the drivers, that can't count the objects
+var ErrCountUnsupported = errors.New("the query driver doesn't support counting")
+
+// ErrPreconditionFailed is returned by the updates of objects not matching the precondition, see
+// grfctx.UpdatePrecondition
+var ErrPreconditionFailed = errors.New("the stored object doesn't match the precondition of the update")
+
+// The kinds of DatabaseError, they can be checked using errors.Is
+var (
+	ErrUniqueViolation      = errors.New("unique constraint violated")
+	ErrForeignKeyViolation  = errors.New("foreign key constraint violated")
+	ErrCheckViolation       = errors.New("check constraint violated")
+	ErrSerializationFailure = errors.New("could not serialize access due to concurrent update")
+)
+
+// DatabaseError is a vendor specific error of the database, translated by the query driver to one
+// of the kinds, like ErrUniqueViolation. The fields are resolved from the constraint, if possible.
+type DatabaseError struct {
+	Kind       error
+	Constraint string
+	Fields     []string
+	Err        error
+}
+
+func (e *DatabaseError) Error() string {
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+func (e *DatabaseError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
@@ -0,0 +1,34 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// KeysetValue converts the value a keyset window starts after to the Go type of the model's field.
+// The cursors are passed in JSON, so for example times come back as RFC3339 strings, which the
+// databases would compare as text. Values of fields not found in the model are returned unchanged.
+func KeysetValue[Model any](field string, value any) (any, error) {
+	var m Model
+	for _, structField := range reflect.VisibleFields(reflect.TypeOf(m)) {
+		if name, included := models.FieldName(structField); !included || name != field || structField.Anonymous {
+			continue
+		}
+		if reflect.TypeOf(value).AssignableTo(structField.Type) {
+			return value, nil
+		}
+		typed := reflect.New(structField.Type)
+		encoded, encodeErr := json.Marshal(value)
+		if encodeErr == nil {
+			encodeErr = json.Unmarshal(encoded, typed.Interface())
+		}
+		if encodeErr != nil {
+			return nil, fmt.Errorf("invalid cursor value of the field `%s`: %w", field, ErrorNotFound)
+		}
+		return typed.Elem().Interface(), nil
+	}
+	return value, nil
+}
@@ -0,0 +1,194 @@
+package common
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// ApplyWindow slices the objects listed in memory according to the window. Objects are ordered and
+// filtered by the keyset, if set, so applying a keyset window to already windowed objects doesn't
+// change them. Random windows pick the objects at random. Offset windows only skip the objects,
+// keeping their order.
+func ApplyWindow(internalValues []models.InternalValue, window grfctx.Window) []models.InternalValue {
+	if window.Random {
+		shuffled := slices.Clone(internalValues)
+		rand.Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+		return shuffled[:min(window.Limit, len(shuffled))]
+	}
+	if window.Keyset == nil {
+		start := min(window.Offset, len(internalValues))
+		end := min(window.Offset+window.Limit, len(internalValues))
+		return internalValues[start:end]
+	}
+	keyset := window.Keyset
+	direction := 1
+	if keyset.Descending {
+		direction = -1
+	}
+	windowed := make([]models.InternalValue, 0, len(internalValues))
+	for _, internalValue := range internalValues {
+		if keyset.After == nil || direction*Compare(internalValue[keyset.Field], keyset.After) > 0 {
+			windowed = append(windowed, internalValue)
+		}
+	}
+	slices.SortStableFunc(windowed, func(a, b models.InternalValue) int {
+		return direction * Compare(a[keyset.Field], b[keyset.Field])
+	})
+	return windowed[:min(window.Limit, len(windowed))]
+}
+
+// SortByOrdering sorts the objects listed in memory by the fields of the ordering, keeping the order
+// of the objects with equal values
+func SortByOrdering(internalValues []models.InternalValue, ordering []grfctx.OrderBy) {
+	slices.SortStableFunc(internalValues, func(a, b models.InternalValue) int {
+		for _, orderBy := range ordering {
+			if result := Compare(a[orderBy.Field], b[orderBy.Field]); result != 0 {
+				if orderBy.Descending {
+					return -result
+				}
+				return result
+			}
+		}
+		return 0
+	})
+}
+
+// Compare orders the values of a field: numbers of any type, strings and times are compared by
+// their values, other values by their string representations. Times are also compared with RFC3339
+// strings, as they are encoded in JSON.
+func Compare(a, b any) int {
+	aTime, aIsTime := asTime(a)
+	bTime, bIsTime := asTime(b)
+	if aIsTime && bIsTime {
+		return aTime.Compare(bTime)
+	}
+	aNumber, aIsNumber := AsFloat(a)
+	bNumber, bIsNumber := AsFloat(b)
+	if aIsNumber && bIsNumber {
+		switch {
+		case aNumber < bNumber:
+			return -1
+		case aNumber > bNumber:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+}
+
+// AsFloat converts numbers of any type to float64
+func AsFloat(value any) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+func asTime(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		parsed, parseErr := time.Parse(time.RFC3339Nano, v)
+		return parsed, parseErr == nil
+	}
+	return time.Time{}, false
+}
+
+// Matches checks if the fields of the object are equal to the values of the conditions, compared
+// using their string representations, as the values may come from query parameters
+func Matches(internalValue models.InternalValue, conditions map[string]any) bool {
+	for field, value := range conditions {
+		if fmt.Sprintf("%v", internalValue[field]) != fmt.Sprintf("%v", value) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesPredicates checks if the object matches all the predicates, evaluated in memory
+func MatchesPredicates(internalValue models.InternalValue, predicates []grfctx.Predicate) bool {
+	for _, predicate := range predicates {
+		if !matchesPredicate(internalValue[predicate.Field], predicate) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesSearch checks if the internal value contains every term of the search in any of its fields,
+// ignoring the case
+func MatchesSearch(internalValue models.InternalValue, search grfctx.Search) bool {
+	for _, term := range search.Terms {
+		found := false
+		for _, field := range search.Fields {
+			predicate := grfctx.Predicate{Field: field, Operator: grfctx.OperatorIContains, Value: term}
+			if matchesPredicate(internalValue[field], predicate) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesPredicate(fieldValue any, predicate grfctx.Predicate) bool {
+	if predicate.Operator == grfctx.OperatorIsNull {
+		isNull := fieldValue == nil
+		if v := reflect.ValueOf(fieldValue); v.Kind() == reflect.Pointer {
+			isNull = v.IsNil()
+		}
+		return isNull == (predicate.Value == true)
+	}
+	// Like in SQL, null fields don't match any other operator
+	if v := reflect.ValueOf(fieldValue); v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return false
+		}
+		fieldValue = v.Elem().Interface()
+	}
+	if fieldValue == nil {
+		return false
+	}
+	text, value := fmt.Sprintf("%v", fieldValue), fmt.Sprintf("%v", predicate.Value)
+	switch predicate.Operator {
+	case grfctx.OperatorGt:
+		return Compare(fieldValue, predicate.Value) > 0
+	case grfctx.OperatorGte:
+		return Compare(fieldValue, predicate.Value) >= 0
+	case grfctx.OperatorLt:
+		return Compare(fieldValue, predicate.Value) < 0
+	case grfctx.OperatorLte:
+		return Compare(fieldValue, predicate.Value) <= 0
+	case grfctx.OperatorContains:
+		return strings.Contains(text, value)
+	case grfctx.OperatorIContains:
+		return strings.Contains(strings.ToLower(text), strings.ToLower(value))
+	case grfctx.OperatorStartsWith:
+		return strings.HasPrefix(text, value)
+	case grfctx.OperatorIn:
+		values, _ := predicate.Value.([]any)
+		return slices.ContainsFunc(values, func(candidate any) bool {
+			return Compare(fieldValue, candidate) == 0
+		})
+	}
+	return Compare(fieldValue, predicate.Value) == 0
+}
@@ -0,0 +1,83 @@
+package common
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// Stats are the statistics of the values of a numeric field, null values are skipped
+type Stats struct {
+	Count int      `json:"count"`
+	Min   *float64 `json:"min"`
+	Max   *float64 `json:"max"`
+	Avg   *float64 `json:"avg"`
+	// Percentiles map the requested percentiles, like "50", to their values, linearly interpolated
+	// between the closest values
+	Percentiles map[string]float64 `json:"percentiles"`
+}
+
+// ComputeStats computes the statistics of the values in memory
+func ComputeStats(values []float64, percentiles []float64) Stats {
+	stats := Stats{Count: len(values), Percentiles: map[string]float64{}}
+	if len(values) == 0 {
+		return stats
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	sum := 0.0
+	for _, value := range sorted {
+		sum += value
+	}
+	minValue, maxValue, avg := sorted[0], sorted[len(sorted)-1], sum/float64(len(sorted))
+	stats.Min, stats.Max, stats.Avg = &minValue, &maxValue, &avg
+	for _, percentile := range percentiles {
+		position := PercentilePosition(percentile, len(sorted))
+		lower := int(math.Floor(position))
+		upper := min(lower+1, len(sorted)-1)
+		stats.Percentiles[PercentileKey(percentile)] = Interpolate(sorted[lower], sorted[upper], position)
+	}
+	return stats
+}
+
+// PercentilePosition returns the position of the percentile in the sorted values, the values at the
+// floor of the position and the next one are interpolated
+func PercentilePosition(percentile float64, count int) float64 {
+	return percentile / 100 * float64(count-1)
+}
+
+// Interpolate returns the value between the lower and the upper one, at the fractional part of the
+// position
+func Interpolate(lower, upper, position float64) float64 {
+	return lower + (upper-lower)*(position-math.Floor(position))
+}
+
+// PercentileKey formats the percentile as the key of Stats.Percentiles
+func PercentileKey(percentile float64) string {
+	return strconv.FormatFloat(percentile, 'f', -1, 64)
+}
+
+// NumericValues returns the numeric values of the field of the objects, skipping null values and
+// dereferencing pointers
+func NumericValues(internalValues []models.InternalValue, field string) []float64 {
+	values := make([]float64, 0, len(internalValues))
+	for _, internalValue := range internalValues {
+		value := reflect.ValueOf(internalValue[field])
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+		if !value.IsValid() {
+			continue
+		}
+		if number, ok := AsFloat(value.Interface()); ok {
+			values = append(values, number)
+		}
+	}
+	return values
+}
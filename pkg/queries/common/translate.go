@@ -0,0 +1,112 @@
+package common
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ErrorTranslator translates the vendor specific errors of the database to DatabaseError, returning
+// other errors unchanged. The table and its columns are used to resolve the fields of the
+// constraints.
+type ErrorTranslator func(err error, table string, columns []string) error
+
+var (
+	sqlStatePattern   = regexp.MustCompile(`\(SQLSTATE (\w{5})\)`)
+	mysqlErrorPattern = regexp.MustCompile(`^Error (\d+)`)
+	// PostgreSQL and MySQL quote the constraint names differently, MySQL prefixes the unique keys
+	// with the table name
+	constraintPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`constraint "([^"]+)"`),
+		regexp.MustCompile("CONSTRAINT `([^`]+)`"),
+		regexp.MustCompile(`(?i)constraint '([^']+)'`),
+		regexp.MustCompile(`for key '(?:[^'.]+\.)?([^']+)'`),
+	}
+	// SQLite reports the columns of the violated unique constraints and the names of the check ones
+	sqliteUniquePattern = regexp.MustCompile(`UNIQUE constraint failed: (.+)$`)
+	sqliteCheckPattern  = regexp.MustCompile(`CHECK constraint failed: (\w+)`)
+)
+
+// TranslateError is the default ErrorTranslator of the SQL drivers, supporting PostgreSQL, MySQL and
+// SQLite. Deadlocks and locked databases are also translated to ErrSerializationFailure, as the
+// operation can be retried.
+func TranslateError(err error, table string, columns []string) error {
+	var databaseErr *DatabaseError
+	if err == nil || errors.As(err, &databaseErr) {
+		return err
+	}
+	message := err.Error()
+	kind := errorKind(err, message)
+	if kind == nil {
+		return err
+	}
+	databaseErr = &DatabaseError{Kind: kind, Err: err}
+	if match := sqliteUniquePattern.FindStringSubmatch(message); match != nil {
+		for _, column := range strings.Split(match[1], ", ") {
+			databaseErr.Fields = append(databaseErr.Fields, strings.TrimPrefix(column, table+"."))
+		}
+		return databaseErr
+	}
+	if match := sqliteCheckPattern.FindStringSubmatch(message); match != nil {
+		databaseErr.Constraint = match[1]
+	}
+	for _, pattern := range constraintPatterns {
+		if match := pattern.FindStringSubmatch(message); match != nil {
+			databaseErr.Constraint = match[1]
+			break
+		}
+	}
+	if field := constraintField(databaseErr.Constraint, table, columns); field != "" {
+		databaseErr.Fields = []string{field}
+	}
+	return databaseErr
+}
+
+func errorKind(err error, message string) error {
+	sqlState := ""
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		sqlState = stateErr.SQLState()
+	} else if match := sqlStatePattern.FindStringSubmatch(message); match != nil {
+		sqlState = match[1]
+	}
+	mysqlNumber := ""
+	if match := mysqlErrorPattern.FindStringSubmatch(message); match != nil {
+		mysqlNumber = match[1]
+	}
+	switch {
+	case sqlState == "23505" || mysqlNumber == "1062" || strings.Contains(message, "UNIQUE constraint failed"):
+		return ErrUniqueViolation
+	case sqlState == "23503" || mysqlNumber == "1451" || mysqlNumber == "1452" ||
+		strings.Contains(message, "FOREIGN KEY constraint failed"):
+		return ErrForeignKeyViolation
+	case sqlState == "23514" || mysqlNumber == "3819" || strings.Contains(message, "CHECK constraint failed"):
+		return ErrCheckViolation
+	case sqlState == "40001" || sqlState == "40P01" || mysqlNumber == "1213" ||
+		strings.Contains(message, "database is locked"):
+		return ErrSerializationFailure
+	}
+	return nil
+}
+
+// constraintField resolves the column of the constraint from its name, following the naming
+// conventions of GORM, like `idx_products_sku`, and PostgreSQL, like `products_sku_key`
+func constraintField(constraint, table string, columns []string) string {
+	name := strings.ToLower(constraint)
+	for _, prefix := range []string{"idx_", "uni_", "fk_", "chk_"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	name = strings.TrimPrefix(name, strings.ToLower(table)+"_")
+	for _, suffix := range []string{"_key", "_fkey", "_check"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	if slices.Contains(columns, name) {
+		return name
+	}
+	// Foreign keys of GORM are named after the relations, like `fk_photos_product` for `product_id`
+	if slices.Contains(columns, name+"_id") {
+		return name + "_id"
+	}
+	return ""
+}
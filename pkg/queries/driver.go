@@ -1,6 +1,8 @@
 package queries
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	"github.com/glothriel/grf/pkg/queries/common"
 	"github.com/glothriel/grf/pkg/queries/crud"
@@ -15,3 +17,117 @@ type Driver[Model any] interface {
 
 	Middleware() []gin.HandlerFunc
 }
+
+// Transactional is implemented by drivers, that can run several operations atomically
+type Transactional interface {
+	// Atomic runs fn in a transaction, which is rolled back if fn returns an error. The operations
+	// called with the ctx inside fn are part of the transaction.
+	Atomic(ctx *gin.Context, fn func() error) error
+}
+
+// Atomic runs fn in a transaction if the driver implements Transactional, otherwise it just calls
+// fn, so the operations made before an error are not rolled back. The callbacks registered with
+// AfterCommit inside fn are run after the outermost Atomic succeeds, and dropped if it fails.
+func Atomic[Model any](ctx *gin.Context, driver Driver[Model], fn func() error) error {
+	callbacks, nested := afterCommitCallbacksOf(ctx)
+	if !nested {
+		callbacks = &afterCommitCallbacks{}
+		ctx.Set(afterCommitKey, callbacks)
+		defer ctx.Set(afterCommitKey, nil)
+	}
+	registered := len(callbacks.fns)
+	var err error
+	if transactional, ok := driver.(Transactional); ok {
+		err = transactional.Atomic(ctx, fn)
+	} else {
+		err = fn()
+	}
+	if err != nil {
+		// the callbacks of the rolled back operations are dropped, even if the outer Atomic succeeds
+		callbacks.fns = callbacks.fns[:registered]
+		return err
+	}
+	if !nested {
+		for _, callback := range callbacks.fns {
+			callback()
+		}
+	}
+	return nil
+}
+
+// afterCommitKey stores the callbacks registered with AfterCommit inside Atomic
+const afterCommitKey = "grf:queries:after_commit"
+
+type afterCommitCallbacks struct {
+	fns []func()
+}
+
+func afterCommitCallbacksOf(ctx *gin.Context) (*afterCommitCallbacks, bool) {
+	stored, _ := ctx.Get(afterCommitKey)
+	callbacks, ok := stored.(*afterCommitCallbacks)
+	return callbacks, ok && callbacks != nil
+}
+
+// AfterCommit runs fn after the transaction of the Atomic call it's made in is committed, or
+// immediately when it's made outside of Atomic, so the side effects, like notifications, are not
+// triggered by the operations, that are rolled back
+func AfterCommit(ctx *gin.Context, fn func()) {
+	if callbacks, ok := afterCommitCallbacksOf(ctx); ok {
+		callbacks.fns = append(callbacks.fns, fn)
+		return
+	}
+	fn()
+}
+
+// Counter is implemented by drivers, that can count the objects matching the filters of the
+// request, ignoring its window, see grfctx.CurrentWindow
+type Counter interface {
+	Count(ctx *gin.Context) (int, error)
+}
+
+// ErrCountUnsupported is returned by Count if the driver doesn't implement Counter, or can't count
+// the objects, see common.ErrCountUnsupported
+var ErrCountUnsupported = common.ErrCountUnsupported
+
+// Count counts the objects matching the filters of the request if the driver implements Counter
+func Count[Model any](ctx *gin.Context, driver Driver[Model]) (int, error) {
+	if counter, ok := driver.(Counter); ok {
+		return counter.Count(ctx)
+	}
+	return 0, ErrCountUnsupported
+}
+
+// Exister is implemented by drivers, that can check if an object matching the filters of the request
+// and the conditions exists without fetching it, for example using an EXISTS query. The conditions
+// map the fields to the values, as received from the client.
+type Exister interface {
+	Exists(ctx *gin.Context, conditions map[string]any) (bool, error)
+}
+
+// ErrExistsUnsupported is returned by Exists if the driver doesn't implement Exister
+var ErrExistsUnsupported = errors.New("the query driver doesn't support existence checks")
+
+// Exists checks if an object matching the conditions exists if the driver implements Exister
+func Exists[Model any](ctx *gin.Context, driver Driver[Model], conditions map[string]any) (bool, error) {
+	if exister, ok := driver.(Exister); ok {
+		return exister.Exists(ctx, conditions)
+	}
+	return false, ErrExistsUnsupported
+}
+
+// Aggregator is implemented by drivers, that can compute the statistics of a numeric field of the
+// objects matching the filters of the request, without fetching the objects
+type Aggregator interface {
+	Stats(ctx *gin.Context, field string, percentiles []float64) (common.Stats, error)
+}
+
+// ErrStatsUnsupported is returned by Stats if the driver doesn't implement Aggregator
+var ErrStatsUnsupported = errors.New("the query driver doesn't support statistics")
+
+// Stats computes the statistics of the field if the driver implements Aggregator
+func Stats[Model any](ctx *gin.Context, driver Driver[Model], field string, percentiles []float64) (common.Stats, error) {
+	if aggregator, ok := driver.(Aggregator); ok {
+		return aggregator.Stats(ctx, field, percentiles)
+	}
+	return common.Stats{}, ErrStatsUnsupported
+}
@@ -0,0 +1,38 @@
+package queries
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAfterCommit(t *testing.T) {
+	// given
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	driver := InMemory[migratedModel]()
+	called := []string{}
+
+	// when
+	AfterCommit(ctx, func() { called = append(called, "outside") })
+	committedErr := Atomic(ctx, driver, func() error {
+		AfterCommit(ctx, func() { called = append(called, "committed") })
+		_ = Atomic(ctx, driver, func() error {
+			AfterCommit(ctx, func() { called = append(called, "rolled back savepoint") })
+			return errors.New("nested failure")
+		})
+		called = append(called, "before commit")
+		return nil
+	})
+	rolledBackErr := Atomic(ctx, driver, func() error {
+		AfterCommit(ctx, func() { called = append(called, "rolled back") })
+		return errors.New("failure")
+	})
+
+	// then
+	assert.NoError(t, committedErr)
+	assert.EqualError(t, rolledBackErr, "failure")
+	assert.Equal(t, []string{"outside", "before commit", "committed"}, called)
+}
@@ -0,0 +1,253 @@
+package queries
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/queries/crud"
+	"github.com/sirupsen/logrus"
+)
+
+// DualWriteSource selects one of the drivers of DualWriteDriver
+type DualWriteSource int
+
+const (
+	// DualWriteOld is the driver of the datastore being migrated from
+	DualWriteOld DualWriteSource = iota
+	// DualWriteNew is the driver of the datastore being migrated to
+	DualWriteNew
+)
+
+// Divergence describes a difference between the old and the new datastore, found by
+// DualWriteDriver
+type Divergence struct {
+	Model     string
+	Operation string
+	ID        any
+	// Fields are the names of the fields with different values, empty if the secondary operation
+	// failed
+	Fields []string
+	// Err is the error of the operation of the secondary driver
+	Err error
+}
+
+// DualWriteDriver supports zero-downtime migrations between datastores. It writes to both the
+// old and the new driver and reads from the primary one, see DualWrite.
+type DualWriteDriver[Model any] struct {
+	old          Driver[Model]
+	new          Driver[Model]
+	primary      DualWriteSource
+	compareReads bool
+	onDivergence func(ctx *gin.Context, divergence Divergence)
+}
+
+// DualWrite creates a driver writing to both the old and the new driver, that reads from the old
+// one until WithPrimary switches it. The writes are made using the primary driver first, its
+// results are responded, and then repeated on the secondary one, with the ID assigned by the
+// primary. Inside Atomic, the secondary writes are made after the primary transaction commits, and
+// dropped if it's rolled back. Failures of the secondary driver don't fail the requests, they are
+// reported as divergences, logged by default.
+func DualWrite[Model any](old, new Driver[Model]) *DualWriteDriver[Model] {
+	return &DualWriteDriver[Model]{
+		old:          old,
+		new:          new,
+		onDivergence: logDivergence,
+	}
+}
+
+// WithPrimary selects the driver used for reads and the first of the writes
+func (d *DualWriteDriver[Model]) WithPrimary(primary DualWriteSource) *DualWriteDriver[Model] {
+	d.primary = primary
+	return d
+}
+
+// WithReadComparison retrieves the objects from the secondary driver as well and reports the
+// differences, so the migrated data can be verified before switching the primary driver
+func (d *DualWriteDriver[Model]) WithReadComparison(compare bool) *DualWriteDriver[Model] {
+	d.compareReads = compare
+	return d
+}
+
+// WithOnDivergence replaces the logging of the divergences, for example with metrics
+func (d *DualWriteDriver[Model]) WithOnDivergence(onDivergence func(ctx *gin.Context, divergence Divergence)) *DualWriteDriver[Model] {
+	d.onDivergence = onDivergence
+	return d
+}
+
+func (d *DualWriteDriver[Model]) drivers() (primary, secondary Driver[Model]) {
+	if d.primary == DualWriteNew {
+		return d.new, d.old
+	}
+	return d.old, d.new
+}
+
+func (d *DualWriteDriver[Model]) CRUD() *crud.CRUD[Model] {
+	primary, secondary := d.drivers()
+	return &crud.CRUD[Model]{
+		List: primary.CRUD().List,
+		Retrieve: func(ctx *gin.Context, id any) (models.InternalValue, error) {
+			stored, err := primary.CRUD().Retrieve(ctx, id)
+			if err != nil || !d.compareReads {
+				return stored, err
+			}
+			secondaryStored, secondaryErr := secondary.CRUD().Retrieve(d.secondaryContext(ctx), id)
+			d.compare(ctx, "retrieve", id, stored, secondaryStored, secondaryErr)
+			return stored, nil
+		},
+		Create: func(ctx *gin.Context, new models.InternalValue) (models.InternalValue, error) {
+			created, err := primary.CRUD().Create(ctx, maps.Clone(new))
+			if err != nil {
+				return nil, err
+			}
+			stored := maps.Clone(created)
+			d.afterPrimaryCommit(ctx, func() {
+				secondaryCreated, secondaryErr := secondary.CRUD().Create(d.secondaryContext(ctx), maps.Clone(stored))
+				d.compare(ctx, "create", stored["id"], stored, secondaryCreated, secondaryErr)
+			})
+			return created, nil
+		},
+		Update: func(ctx *gin.Context, old, new models.InternalValue, id any) (models.InternalValue, error) {
+			updated, err := primary.CRUD().Update(ctx, old, maps.Clone(new), id)
+			if err != nil {
+				return nil, err
+			}
+			previous, stored := maps.Clone(old), maps.Clone(updated)
+			d.afterPrimaryCommit(ctx, func() {
+				secondaryUpdated, secondaryErr := secondary.CRUD().Update(
+					d.secondaryContext(ctx), maps.Clone(previous), maps.Clone(stored), id,
+				)
+				d.compare(ctx, "update", id, stored, secondaryUpdated, secondaryErr)
+			})
+			return updated, nil
+		},
+		Destroy: func(ctx *gin.Context, id any) error {
+			if err := primary.CRUD().Destroy(ctx, id); err != nil {
+				return err
+			}
+			d.afterPrimaryCommit(ctx, func() {
+				if secondaryErr := secondary.CRUD().Destroy(d.secondaryContext(ctx), id); secondaryErr != nil {
+					d.diverged(ctx, Divergence{Operation: "destroy", ID: id, Err: secondaryErr})
+				}
+			})
+			return nil
+		},
+	}
+}
+
+// afterPrimaryCommit runs the secondary write after the transaction of the primary driver commits,
+// see AfterCommit. The writes of the primary drivers, that aren't Transactional, can't be rolled
+// back, so they are repeated on the secondary driver immediately.
+func (d *DualWriteDriver[Model]) afterPrimaryCommit(ctx *gin.Context, write func()) {
+	primary, _ := d.drivers()
+	if _, transactional := primary.(Transactional); !transactional {
+		write()
+		return
+	}
+	AfterCommit(ctx, write)
+}
+
+// secondaryContext returns a copy of the request context initialized by the middleware of the
+// secondary driver, so the drivers storing their state in the context don't interfere
+func (d *DualWriteDriver[Model]) secondaryContext(ctx *gin.Context) *gin.Context {
+	_, secondary := d.drivers()
+	request := &http.Request{Method: http.MethodGet, URL: &url.URL{}, Header: http.Header{}}
+	if ctx.Request != nil {
+		request = ctx.Request
+	}
+	detached := &gin.Context{Request: request, Keys: maps.Clone(ctx.Keys)}
+	// the secondary writes made after the commit don't belong to the primary transaction
+	delete(detached.Keys, afterCommitKey)
+	for _, middleware := range secondary.Middleware() {
+		middleware(detached)
+	}
+	return detached
+}
+
+// compare reports the fields with different values in the primary and secondary internal values
+func (d *DualWriteDriver[Model]) compare(
+	ctx *gin.Context, operation string, id any, primary, secondary models.InternalValue, secondaryErr error,
+) {
+	if secondaryErr != nil {
+		d.diverged(ctx, Divergence{Operation: operation, ID: id, Err: secondaryErr})
+		return
+	}
+	var differentFields []string
+	for field, value := range primary {
+		secondaryValue, ok := secondary[field]
+		if !ok || fmt.Sprint(value) != fmt.Sprint(secondaryValue) {
+			differentFields = append(differentFields, field)
+		}
+	}
+	slices.Sort(differentFields)
+	if len(differentFields) > 0 {
+		d.diverged(ctx, Divergence{Operation: operation, ID: id, Fields: differentFields})
+	}
+}
+
+func (d *DualWriteDriver[Model]) diverged(ctx *gin.Context, divergence Divergence) {
+	var m Model
+	divergence.Model = reflect.TypeOf(m).Name()
+	d.onDivergence(ctx, divergence)
+}
+
+func logDivergence(_ *gin.Context, divergence Divergence) {
+	entry := logrus.WithFields(logrus.Fields{
+		"model":     divergence.Model,
+		"operation": divergence.Operation,
+		"id":        divergence.ID,
+	})
+	if divergence.Err != nil {
+		entry.WithError(divergence.Err).Warn("Dual write: the secondary driver failed")
+		return
+	}
+	entry.WithField("fields", divergence.Fields).Warn("Dual write: the datastores diverged")
+}
+
+func (d *DualWriteDriver[Model]) Pagination() common.Pagination {
+	primary, _ := d.drivers()
+	return primary.Pagination()
+}
+
+func (d *DualWriteDriver[Model]) Filter() common.QueryMod {
+	primary, _ := d.drivers()
+	return primary.Filter()
+}
+
+func (d *DualWriteDriver[Model]) Order() common.QueryMod {
+	primary, _ := d.drivers()
+	return primary.Order()
+}
+
+func (d *DualWriteDriver[Model]) Middleware() []gin.HandlerFunc {
+	primary, _ := d.drivers()
+	return primary.Middleware()
+}
+
+// Atomic runs fn in a transaction of the primary driver, the writes of the secondary driver are
+// made after it commits, and dropped if it's rolled back
+func (d *DualWriteDriver[Model]) Atomic(ctx *gin.Context, fn func() error) error {
+	primary, _ := d.drivers()
+	return Atomic(ctx, primary, fn)
+}
+
+func (d *DualWriteDriver[Model]) Count(ctx *gin.Context) (int, error) {
+	primary, _ := d.drivers()
+	return Count(ctx, primary)
+}
+
+func (d *DualWriteDriver[Model]) Exists(ctx *gin.Context, conditions map[string]any) (bool, error) {
+	primary, _ := d.drivers()
+	return Exists(ctx, primary, conditions)
+}
+
+func (d *DualWriteDriver[Model]) Stats(ctx *gin.Context, field string, percentiles []float64) (common.Stats, error) {
+	primary, _ := d.drivers()
+	return Stats(ctx, primary, field, percentiles)
+}
@@ -0,0 +1,118 @@
+package queries
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type migratedModel struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestDualWrite(t *testing.T) {
+	for _, primary := range []DualWriteSource{DualWriteOld, DualWriteNew} {
+		// given
+		old := InMemory(migratedModel{ID: 1, Name: "first"}, migratedModel{ID: 2, Name: "second"})
+		new := InMemory(migratedModel{ID: 1, Name: "first"}, migratedModel{ID: 2, Name: "second"})
+		divergences := []Divergence{}
+		driver := DualWrite[migratedModel](old, new).WithPrimary(primary).WithOnDivergence(
+			func(_ *gin.Context, divergence Divergence) { divergences = append(divergences, divergence) },
+		)
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+		// when
+		created, createErr := driver.CRUD().Create(ctx, models.InternalValue{"name": "third"})
+		_, updateErr := driver.CRUD().Update(
+			ctx, models.InternalValue{"id": uint(1), "name": "first"}, models.InternalValue{"id": uint(1), "name": "updated"}, uint(1),
+		)
+		destroyErr := driver.CRUD().Destroy(ctx, uint(2))
+
+		// then
+		assert.NoError(t, createErr)
+		assert.NoError(t, updateErr)
+		assert.NoError(t, destroyErr)
+		assert.Equal(t, uint(3), created["id"])
+		assert.Empty(t, divergences)
+		for _, d := range []Driver[migratedModel]{old, new} {
+			stored, _ := d.CRUD().List(ctx)
+			assert.ElementsMatch(t, []models.InternalValue{
+				{"id": uint(1), "name": "updated"},
+				{"id": uint(3), "name": "third"},
+			}, stored)
+		}
+	}
+}
+
+func TestDualWriteRolledBackTransaction(t *testing.T) {
+	// given
+	old := InMemory(migratedModel{ID: 1, Name: "first"})
+	new := InMemory(migratedModel{ID: 1, Name: "first"})
+	divergences := []Divergence{}
+	driver := DualWrite[migratedModel](old, new).WithOnDivergence(
+		func(_ *gin.Context, divergence Divergence) { divergences = append(divergences, divergence) },
+	)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	rollback := errors.New("rollback")
+
+	// when
+	rolledBackErr := Atomic(ctx, driver, func() error {
+		if _, createErr := driver.CRUD().Create(ctx, models.InternalValue{"name": "second"}); createErr != nil {
+			return createErr
+		}
+		if destroyErr := driver.CRUD().Destroy(ctx, uint(1)); destroyErr != nil {
+			return destroyErr
+		}
+		return rollback
+	})
+	committedErr := Atomic(ctx, driver, func() error {
+		_, createErr := driver.CRUD().Create(ctx, models.InternalValue{"name": "third"})
+		return createErr
+	})
+
+	// then
+	assert.ErrorIs(t, rolledBackErr, rollback)
+	assert.NoError(t, committedErr)
+	assert.Empty(t, divergences)
+	for _, d := range []Driver[migratedModel]{old, new} {
+		stored, _ := d.CRUD().List(ctx)
+		assert.ElementsMatch(t, []models.InternalValue{
+			{"id": uint(1), "name": "first"},
+			{"id": uint(2), "name": "third"},
+		}, stored)
+	}
+}
+
+func TestDualWriteDivergences(t *testing.T) {
+	// given
+	old := InMemory(migratedModel{ID: 1, Name: "first"}, migratedModel{ID: 2, Name: "second"})
+	new := InMemory(migratedModel{ID: 1, Name: "stale"})
+	divergences := []Divergence{}
+	driver := DualWrite[migratedModel](old, new).WithReadComparison(true).WithOnDivergence(
+		func(_ *gin.Context, divergence Divergence) { divergences = append(divergences, divergence) },
+	)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	// when
+	first, firstErr := driver.CRUD().Retrieve(ctx, uint(1))
+	second, secondErr := driver.CRUD().Retrieve(ctx, uint(2))
+	destroyErr := driver.CRUD().Destroy(ctx, uint(2))
+
+	// then
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	assert.NoError(t, destroyErr)
+	assert.Equal(t, "first", first["name"])
+	assert.Equal(t, "second", second["name"])
+	assert.Equal(t, []Divergence{
+		{Model: "migratedModel", Operation: "retrieve", ID: uint(1), Fields: []string{"name"}},
+		{Model: "migratedModel", Operation: "retrieve", ID: uint(2), Err: common.ErrorNotFound},
+		{Model: "migratedModel", Operation: "destroy", ID: uint(2), Err: common.ErrorNotFound},
+	}, divergences)
+}
@@ -2,8 +2,13 @@ package dummy
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/ids"
 	"github.com/glothriel/grf/pkg/models"
 	"github.com/glothriel/grf/pkg/queries/common"
 	"github.com/glothriel/grf/pkg/queries/crud"
@@ -11,30 +16,69 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// FilterFunc decides if the object is listed, it's the in-memory counterpart of gormq.GormFilterFunc
+type FilterFunc func(ctx *gin.Context, internalValue models.InternalValue) bool
+
 // InMemoryQueryDriver is a dummy query driver that stores all data in memory.
 type InMemoryQueryDriver[Model any] struct {
 	list     crud.ListQueryFunc
 	create   crud.CreateQueryFunc
-	retrieve func(id any) (models.InternalValue, error)
-	update   func(id any, new models.InternalValue) (models.InternalValue, error)
-	delete   func(id any) error
+	retrieve func(ctx *gin.Context, id any) (models.InternalValue, error)
+	update   func(ctx *gin.Context, id any, new models.InternalValue) (models.InternalValue, error)
+	delete   func(ctx *gin.Context, id any) error
+	atomic   func(fn func() error) error
+	count    func(ctx *gin.Context) int
+
+	filter     FilterFunc
+	ordering   []grfctx.OrderBy
+	pagination Pagination
 
 	q *crud.CRUD[Model]
 }
 
 // Pagination implements db.QueryDriver interface
 func (d InMemoryQueryDriver[Model]) Pagination() common.Pagination {
-	return dummyPagination[Model]{}
+	if _, none := d.pagination.(*NoPagination); none || d.pagination == nil {
+		return common.NoPagination{}
+	}
+	return dummyPagination[Model]{child: d.pagination}
 }
 
 // Filter implements db.QueryDriver interface
 func (d InMemoryQueryDriver[Model]) Filter() common.QueryMod {
-	return dummyQueryMod{}
+	return dummyQueryMod{key: filterCtxKey}
 }
 
 // Order implements db.QueryDriver interface
 func (d InMemoryQueryDriver[Model]) Order() common.QueryMod {
-	return dummyQueryMod{}
+	return dummyQueryMod{key: orderCtxKey}
+}
+
+// WithFilter filters the listed and counted objects using the function, like gormq's WithFilter it
+// applies to the views calling Filter().Apply, like the list view, but not to the detail ones
+func (d *InMemoryQueryDriver[Model]) WithFilter(filter FilterFunc) *InMemoryQueryDriver[Model] {
+	d.filter = filter
+	return d
+}
+
+// WithOrderBy sets the default ordering of the listed objects, comma separated fields prefixed
+// with `-` for the descending order, for example `-price,name`. The ordering requested using
+// grfctx.SetOrdering takes precedence.
+func (d *InMemoryQueryDriver[Model]) WithOrderBy(ordering string) *InMemoryQueryDriver[Model] {
+	d.ordering = []grfctx.OrderBy{}
+	for _, field := range strings.Split(ordering, ",") {
+		field = strings.TrimSpace(field)
+		if name, descending := strings.CutPrefix(field, "-"); name != "" {
+			d.ordering = append(d.ordering, grfctx.OrderBy{Field: name, Descending: descending})
+		}
+	}
+	return d
+}
+
+// WithPagination slices the listed objects using the pagination, like gormq's WithPagination
+func (d *InMemoryQueryDriver[Model]) WithPagination(pagination Pagination) *InMemoryQueryDriver[Model] {
+	d.pagination = pagination
+	return d
 }
 
 // CRUD implements db.QueryDriver interface
@@ -44,16 +88,46 @@ func (d InMemoryQueryDriver[Model]) CRUD() *crud.CRUD[Model] {
 	}).WithUpdate(func(
 		ctx *gin.Context, old models.InternalValue, new models.InternalValue, id any,
 	) (models.InternalValue, error) {
-		return d.update(id, new)
+		return d.update(ctx, id, new)
 	}).WithDestroy(func(ctx *gin.Context, id any) error {
-		return d.delete(id)
+		return d.delete(ctx, id)
 	}).WithRetrieve(func(ctx *gin.Context, id any) (models.InternalValue, error) {
-		return d.retrieve(id)
+		return d.retrieve(ctx, id)
 	}).WithList(func(ctx *gin.Context) ([]models.InternalValue, error) {
 		return d.list(ctx)
 	})
 }
 
+// Atomic implements queries.Transactional, the storage is restored if fn returns an error
+func (d InMemoryQueryDriver[Model]) Atomic(_ *gin.Context, fn func() error) error {
+	return d.atomic(fn)
+}
+
+// Count implements queries.Counter
+func (d InMemoryQueryDriver[Model]) Count(ctx *gin.Context) (int, error) {
+	return d.count(ctx), nil
+}
+
+// Exists implements queries.Exister
+func (d InMemoryQueryDriver[Model]) Exists(ctx *gin.Context, conditions map[string]any) (bool, error) {
+	list, listErr := d.list(ctx)
+	if listErr != nil {
+		return false, listErr
+	}
+	return slices.ContainsFunc(list, func(elem models.InternalValue) bool {
+		return common.Matches(elem, conditions)
+	}), nil
+}
+
+// Stats implements queries.Aggregator
+func (d InMemoryQueryDriver[Model]) Stats(ctx *gin.Context, field string, percentiles []float64) (common.Stats, error) {
+	list, listErr := d.list(ctx)
+	if listErr != nil {
+		return common.Stats{}, listErr
+	}
+	return common.ComputeStats(common.NumericValues(list, field), percentiles), nil
+}
+
 func (d *InMemoryQueryDriver[Model]) WithCreate(f crud.CreateQueryFunc) *InMemoryQueryDriver[Model] {
 	d.create = f
 	return d
@@ -64,20 +138,62 @@ func (d InMemoryQueryDriver[Model]) Middleware() []gin.HandlerFunc {
 	return []gin.HandlerFunc{}
 }
 
+const (
+	filterCtxKey     = "grf.dummy.filter"
+	orderCtxKey      = "grf.dummy.order"
+	paginationCtxKey = "grf.dummy.pagination"
+)
+
+// Pagination slices the objects listed in memory, it's the in-memory counterpart of gormq.Pagination.
+// The limits and offsets are applied by the paginations of the views instead, see
+// pagination.LimitOffsetPagination.
+type Pagination interface {
+	Apply(ctx *gin.Context, internalValues []models.InternalValue) []models.InternalValue
+	Format(ctx *gin.Context, elems []any) (any, error)
+}
+
+// NoPagination lists all the objects, unless the views paginate them
+type NoPagination struct{}
+
+func (p *NoPagination) Apply(_ *gin.Context, internalValues []models.InternalValue) []models.InternalValue {
+	return internalValues
+}
+
+func (p *NoPagination) Format(_ *gin.Context, elems []any) (any, error) {
+	return elems, nil
+}
+
 type dummyPagination[Model any] struct {
+	child Pagination
 }
 
-func (d dummyPagination[Model]) Apply(*gin.Context) {
+func (d dummyPagination[Model]) Apply(ctx *gin.Context) {
+	ctx.Set(paginationCtxKey, true)
 }
 
-func (d dummyPagination[Model]) Format(_ *gin.Context, models []any) (any, error) {
-	return models, nil
+func (d dummyPagination[Model]) Format(ctx *gin.Context, models []any) (any, error) {
+	if d.child == nil {
+		return models, nil
+	}
+	return d.child.Format(ctx, models)
 }
 
+// dummyQueryMod marks the context, so the driver's filter or ordering is applied to the lists
 type dummyQueryMod struct {
+	key string
 }
 
-func (d dummyQueryMod) Apply(*gin.Context) {
+func (d dummyQueryMod) Apply(ctx *gin.Context) {
+	ctx.Set(d.key, true)
+}
+
+// applied checks if the query mod was applied in the context
+func applied(ctx *gin.Context, key string) bool {
+	if ctx == nil {
+		return false
+	}
+	_, ok := ctx.Get(key)
+	return ok
 }
 
 // InMemoryDriver creates InMemoryQueryDriver with given seed data.
@@ -86,42 +202,97 @@ func InMemoryDriver[Model any](seed ...Model) *InMemoryQueryDriver[Model] {
 	var newID = newIDGenerator[Model](storage)
 	driver := &InMemoryQueryDriver[Model]{
 		q: &crud.CRUD[Model]{},
-		list: func(*gin.Context) ([]models.InternalValue, error) {
-			ivs := make([]models.InternalValue, len(storage))
-			i := 0
-			for _, v := range storage {
-				ivs[i] = v
-				i++
-			}
-			return ivs, nil
-		},
-		retrieve: func(id any) (models.InternalValue, error) {
-			elem, ok := storage[fmt.Sprintf("%v", id)]
+		retrieve: func(ctx *gin.Context, id any) (models.InternalValue, error) {
+			key, ok := lookup(storage, grfctx.LookupField(ctx), id)
+			ok = ok && inParentScope(ctx, storage[key])
 			if !ok {
 				return nil, common.ErrorNotFound
 			}
-			return elem, nil
+			return storage[key], nil
 		},
 		create: func(_ *gin.Context, m models.InternalValue) (models.InternalValue, error) {
-			m["id"] = newID()
-			storage[fmt.Sprintf("%v", m["id"])] = m
+			generated, generateErr := ids.Assign[Model](m)
+			if generateErr != nil {
+				return nil, generateErr
+			}
+			if !generated {
+				m["id"] = newID()
+			}
+			key := fmt.Sprintf("%v", m["id"])
+			if _, exists := storage[key]; exists {
+				// the IDs sent by the clients must not overwrite the stored objects
+				return nil, &common.DatabaseError{
+					Kind: common.ErrUniqueViolation, Fields: []string{"id"}, Err: fmt.Errorf("id `%s` already exists", key),
+				}
+			}
+			storage[key] = m
 			return m, nil
 		},
-		update: func(id any, m models.InternalValue) (models.InternalValue, error) {
-			if _, ok := storage[fmt.Sprintf("%v", id)]; !ok {
+		update: func(ctx *gin.Context, id any, m models.InternalValue) (models.InternalValue, error) {
+			key, ok := lookup(storage, grfctx.LookupField(ctx), id)
+			ok = ok && inParentScope(ctx, storage[key])
+			if !ok {
 				return nil, common.ErrorNotFound
 			}
-			storage[fmt.Sprintf("%v", id)] = m
+			if precondition, conditional := grfctx.UpdatePrecondition(ctx); conditional &&
+				!common.MatchesPredicates(storage[key], []grfctx.Predicate{precondition}) {
+				return nil, common.ErrPreconditionFailed
+			}
+			storage[key] = m
 			return m, nil
 		},
-		delete: func(id any) error {
-			if _, ok := storage[fmt.Sprintf("%v", id)]; !ok {
+		delete: func(ctx *gin.Context, id any) error {
+			key, ok := lookup(storage, grfctx.LookupField(ctx), id)
+			ok = ok && inParentScope(ctx, storage[key])
+			if !ok {
 				return common.ErrorNotFound
 			}
-			delete(storage, fmt.Sprintf("%v", id))
+			delete(storage, key)
+			return nil
+		},
+		atomic: func(fn func() error) error {
+			snapshot := maps.Clone(storage)
+			if err := fn(); err != nil {
+				clear(storage)
+				maps.Copy(storage, snapshot)
+				return err
+			}
 			return nil
 		},
 	}
+	driver.list = func(ctx *gin.Context) ([]models.InternalValue, error) {
+		ivs := make([]models.InternalValue, 0, len(storage))
+		for _, v := range storage {
+			if driver.listed(ctx, v) {
+				ivs = append(ivs, v)
+			}
+		}
+		// The storage is unordered, the elements are sorted by their IDs to keep the lists stable
+		slices.SortFunc(ivs, func(a, b models.InternalValue) int {
+			return common.Compare(a["id"], b["id"])
+		})
+		if ordering := grfctx.Ordering(ctx); len(ordering) > 0 {
+			common.SortByOrdering(ivs, ordering)
+		} else if applied(ctx, orderCtxKey) {
+			common.SortByOrdering(ivs, driver.ordering)
+		}
+		if window, windowed := grfctx.CurrentWindow(ctx); windowed {
+			ivs = common.ApplyWindow(ivs, window)
+		}
+		if driver.pagination != nil && applied(ctx, paginationCtxKey) {
+			ivs = driver.pagination.Apply(ctx, ivs)
+		}
+		return ivs, nil
+	}
+	driver.count = func(ctx *gin.Context) int {
+		count := 0
+		for _, v := range storage {
+			if driver.listed(ctx, v) {
+				count++
+			}
+		}
+		return count
+	}
 	for _, m := range seed {
 		intVal := models.AsInternalValue(m)
 		_, createErr := driver.create(nil, intVal)
@@ -133,6 +304,39 @@ func InMemoryDriver[Model any](seed ...Model) *InMemoryQueryDriver[Model] {
 	return driver
 }
 
+// lookup returns the storage key of the element with the field equal to the value
+func lookup(storage map[any]models.InternalValue, field string, value any) (any, bool) {
+	if field == "id" {
+		key := fmt.Sprintf("%v", value)
+		_, ok := storage[key]
+		return key, ok
+	}
+	for key, elem := range storage {
+		if fmt.Sprintf("%v", elem[field]) == fmt.Sprintf("%v", value) {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// inParentScope checks if the element belongs to the parent of a nested resource, if any
+func inParentScope(ctx *gin.Context, elem models.InternalValue) bool {
+	scope, ok := grfctx.Parent(ctx)
+	return !ok || fmt.Sprintf("%v", elem[scope.Field]) == fmt.Sprintf("%v", scope.Value)
+}
+
+// listed checks if the element is listed in the request, matching its parent scope, predicates,
+// search and the filter of the driver
+func (d *InMemoryQueryDriver[Model]) listed(ctx *gin.Context, elem models.InternalValue) bool {
+	if search, ok := grfctx.CurrentSearch(ctx); ok && !common.MatchesSearch(elem, search) {
+		return false
+	}
+	if d.filter != nil && applied(ctx, filterCtxKey) && !d.filter(ctx, elem) {
+		return false
+	}
+	return inParentScope(ctx, elem) && common.MatchesPredicates(elem, grfctx.Predicates(ctx))
+}
+
 func newIDGenerator[Model any](storage map[any]models.InternalValue) func() any {
 	var currModel Model
 	intVal := models.AsInternalValue(currModel)
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/ids"
 	"github.com/glothriel/grf/pkg/models"
 	"github.com/glothriel/grf/pkg/queries/common"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +35,39 @@ func TestDummyList(t *testing.T) {
 	}, list)
 }
 
+func TestDummyListWithDriverFilterOrderingAndWindow(t *testing.T) {
+	// given
+	driver := InMemoryDriver(
+		MockModel{Foo: "b"}, MockModel{Foo: "hidden"}, MockModel{Foo: "c"}, MockModel{Foo: "a"}, MockModel{Foo: "d"},
+	).WithFilter(func(_ *gin.Context, iv models.InternalValue) bool {
+		return iv["foo"] != "hidden"
+	}).WithOrderBy("-foo")
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	grfctx.SetWindow(ctx, grfctx.Window{Offset: 1, Limit: 2})
+	unmodifiedCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	// when
+	driver.Pagination().Apply(ctx)
+	driver.Filter().Apply(ctx)
+	driver.Order().Apply(ctx)
+	list, listErr := driver.CRUD().List(ctx)
+	count, countErr := driver.Count(ctx)
+	unmodified, unmodifiedErr := driver.CRUD().List(unmodifiedCtx)
+
+	// then
+	assert.NoError(t, listErr)
+	assert.Equal(t, []models.InternalValue{
+		{"id": uint(3), "foo": "c"},
+		{"id": uint(1), "foo": "b"},
+	}, list)
+	assert.NoError(t, countErr)
+	assert.Equal(t, 4, count)
+	assert.NoError(t, unmodifiedErr)
+	assert.Equal(t, []any{"b", "hidden", "c", "a", "d"}, []any{
+		unmodified[0]["foo"], unmodified[1]["foo"], unmodified[2]["foo"], unmodified[3]["foo"], unmodified[4]["foo"],
+	})
+}
+
 func TestDummyRetrievie(t *testing.T) {
 	// given
 	driver := InMemoryDriver(MockModel{Foo: "bar"})
@@ -183,3 +218,29 @@ func TestFormat(t *testing.T) {
 	assert.Equal(t, []any{1}, formatted)
 	assert.NoError(t, err)
 }
+
+type generatedIDModel struct {
+	ID  string `json:"id"`
+	Foo string `json:"foo"`
+}
+
+func TestDummyCreateWithIDGenerator(t *testing.T) {
+	// given
+	ids.Register[generatedIDModel](ids.Prefixed("gen", ids.ULID()))
+	driver := InMemoryDriver[generatedIDModel]()
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	// when
+	created, createErr := driver.CRUD().Create(ctx, models.InternalValue{"foo": "bar"})
+	_, invalidErr := driver.CRUD().Create(ctx, models.InternalValue{"id": "1", "foo": "bar"})
+	_, duplicateErr := driver.CRUD().Create(ctx, models.InternalValue{"id": created["id"], "foo": "overwritten"})
+	stored, retrieveErr := driver.CRUD().Retrieve(ctx, created["id"])
+
+	// then
+	assert.NoError(t, createErr)
+	assert.Regexp(t, `^gen_[0-9A-Z]{26}$`, created["id"])
+	assert.ErrorIs(t, invalidErr, ids.ErrInvalidID)
+	assert.ErrorIs(t, duplicateErr, common.ErrUniqueViolation)
+	assert.NoError(t, retrieveErr)
+	assert.Equal(t, "bar", stored["foo"])
+}
@@ -0,0 +1,322 @@
+// Package entq implements a query driver over the clients generated by entgo.io. The driver is a
+// funcq.Driver, whose functions build the queries and the mutations of the generated builders, so
+// the filters, the search, the ordering and the pagination of the views are translated to ent
+// predicates and run by the database. It's a separate module, so GRF doesn't depend on ent.
+package entq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/queries/funcq"
+	"github.com/sirupsen/logrus"
+)
+
+// New creates the driver of the Entity, using the client generated by ent, for example:
+//
+//	driver := entq.New[ent.User](client)
+//
+// The entities are queried with the entity client of the same name, `client.User`, and the
+// transactions of the driver are started with `client.Tx`. The operations can be replaced or
+// disabled, like `entq.New[ent.User](client).WithDelete(nil)`, see funcq.Driver.
+func New[Entity any](client any) *funcq.Driver[Entity] {
+	d := &driver[Entity]{client: reflect.ValueOf(client)}
+	entityType := reflect.TypeOf(*new(Entity))
+	if entityType.Kind() != reflect.Struct {
+		logrus.Panicf("entq: the entity `%s` is not a struct generated by ent", entityType)
+	}
+	d.name, d.pkgPath = entityType.Name(), entityType.PkgPath()
+	if d.client.Kind() != reflect.Pointer || d.client.Elem().Kind() != reflect.Struct ||
+		!d.client.Elem().FieldByName(d.name).IsValid() || !d.client.MethodByName("Tx").IsValid() {
+		logrus.Panicf("entq: `%T` is not an ent client with the `%s` entity client", client, d.name)
+	}
+	return funcq.New[Entity](d.list).
+		WithCount(d.count).
+		WithCreate(d.create).
+		WithUpdate(d.update).
+		WithDelete(d.delete).
+		WithTx(d.tx).
+		WithNotFound(d.isNotFound)
+}
+
+// driver calls the generated builders using reflection, as their types differ for every entity
+type driver[Entity any] struct {
+	client  reflect.Value
+	name    string
+	pkgPath string
+}
+
+type txCtxKey struct{}
+
+// entityClient returns the entity client, like `client.User`, of the transaction of the context, if
+// there's one
+func (d *driver[Entity]) entityClient(ctx context.Context) reflect.Value {
+	if tx, ok := ctx.Value(txCtxKey{}).(reflect.Value); ok {
+		return tx.Elem().FieldByName(d.name)
+	}
+	return d.client.Elem().FieldByName(d.name)
+}
+
+func (d *driver[Entity]) list(ctx context.Context, query funcq.Query) ([]Entity, error) {
+	q, queryErr := d.query(ctx, query)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	orderBy := make([]func(*sql.Selector), 0, len(query.Ordering))
+	if query.Random {
+		orderBy = append(orderBy, sql.OrderByRand())
+	} else {
+		for _, o := range query.Ordering {
+			options := []sql.OrderTermOption{}
+			if o.Descending {
+				options = append(options, sql.OrderDesc())
+			}
+			orderBy = append(orderBy, sql.OrderByField(o.Field, options...).ToFunc())
+		}
+		if query.Offset > 0 {
+			q = call(q, "Offset", reflect.ValueOf(query.Offset))[0]
+		}
+	}
+	q = call(q, "Order", selectors(q, "Order", orderBy)...)[0]
+	if query.Limit > 0 {
+		q = call(q, "Limit", reflect.ValueOf(query.Limit))[0]
+	}
+	found, allErr := results(call(q, "All", reflect.ValueOf(ctx)))
+	if allErr != nil {
+		return nil, allErr
+	}
+	entities := make([]Entity, found.Len())
+	for i := range entities {
+		entities[i] = *found.Index(i).Interface().(*Entity)
+	}
+	return entities, nil
+}
+
+func (d *driver[Entity]) count(ctx context.Context, query funcq.Query) (int, error) {
+	q, queryErr := d.query(ctx, query)
+	if queryErr != nil {
+		return 0, queryErr
+	}
+	count, countErr := results(call(q, "Count", reflect.ValueOf(ctx)))
+	if countErr != nil {
+		return 0, countErr
+	}
+	return int(count.Int()), nil
+}
+
+// query returns the query builder, like *ent.UserQuery, with the predicates and the search of the
+// query
+func (d *driver[Entity]) query(ctx context.Context, query funcq.Query) (reflect.Value, error) {
+	q := call(d.entityClient(ctx), "Query")[0]
+	where := make([]func(*sql.Selector), 0, len(query.Predicates)+1)
+	for _, p := range query.Predicates {
+		predicate, predicateErr := Predicate(p)
+		if predicateErr != nil {
+			return reflect.Value{}, predicateErr
+		}
+		where = append(where, predicate)
+	}
+	if query.Search != nil {
+		where = append(where, Search(*query.Search))
+	}
+	return call(q, "Where", selectors(q, "Where", where)...)[0], nil
+}
+
+func (d *driver[Entity]) create(ctx context.Context, object Entity, fields []string) (Entity, error) {
+	builder := call(d.entityClient(ctx), "Create")[0]
+	values := models.AsInternalValue(object)
+	if id := reflect.ValueOf(values["id"]); id.IsValid() && !id.IsZero() {
+		setID := call(builder, "Mutation")[0].MethodByName("SetID")
+		if !setID.IsValid() || !id.CanConvert(setID.Type().In(0)) {
+			return object, fmt.Errorf("entq: the ID `%v` of `%s` can't be set", values["id"], d.name)
+		}
+		setID.Call([]reflect.Value{id.Convert(setID.Type().In(0))})
+	}
+	if setErr := setFields(builder, values, fields); setErr != nil {
+		return object, setErr
+	}
+	return save[Entity](ctx, builder)
+}
+
+func (d *driver[Entity]) update(ctx context.Context, id any, object Entity, fields []string) (Entity, error) {
+	builder, idErr := d.withID(ctx, "UpdateOneID", id)
+	if idErr != nil {
+		return object, idErr
+	}
+	precondition, conditional := funcq.Precondition(ctx)
+	if conditional {
+		predicate, predicateErr := Predicate(precondition)
+		if predicateErr != nil {
+			return object, predicateErr
+		}
+		builder = call(builder, "Where", selectors(builder, "Where", []func(*sql.Selector){predicate})...)[0]
+	}
+	if setErr := setFields(builder, models.AsInternalValue(object), fields); setErr != nil {
+		return object, setErr
+	}
+	updated, saveErr := save[Entity](ctx, builder)
+	if conditional && d.isNotFound(saveErr) {
+		return object, common.ErrPreconditionFailed
+	}
+	return updated, saveErr
+}
+
+func (d *driver[Entity]) delete(ctx context.Context, id any) error {
+	builder, idErr := d.withID(ctx, "DeleteOneID", id)
+	if idErr != nil {
+		return idErr
+	}
+	_, execErr := results(call(builder, "Exec", reflect.ValueOf(ctx)))
+	return execErr
+}
+
+// withID calls the method of the entity client taking the ID, converting it to the type of the IDs
+func (d *driver[Entity]) withID(ctx context.Context, method string, id any) (reflect.Value, error) {
+	fn := d.entityClient(ctx).MethodByName(method)
+	idType, idValue := fn.Type().In(0), reflect.ValueOf(id)
+	if !idValue.IsValid() || !idValue.CanConvert(idType) {
+		return reflect.Value{}, fmt.Errorf("entq: the ID `%v` of `%s` is not a %s", id, d.name, idType)
+	}
+	return fn.Call([]reflect.Value{idValue.Convert(idType)})[0], nil
+}
+
+// tx runs fn in a transaction of the client, the entity clients of the transaction are used with
+// the context passed to fn
+func (d *driver[Entity]) tx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, txErr := results(call(d.client, "Tx", reflect.ValueOf(ctx)))
+	if txErr != nil {
+		return txErr
+	}
+	if fnErr := fn(context.WithValue(ctx, txCtxKey{}, tx)); fnErr != nil {
+		_, rollbackErr := results(call(tx, "Rollback"))
+		return errors.Join(fnErr, rollbackErr)
+	}
+	_, commitErr := results(call(tx, "Commit"))
+	return commitErr
+}
+
+// isNotFound recognizes the *NotFoundError generated by ent for the package of the Entity, which
+// is returned by the updates and the deletes of missing entities
+func (d *driver[Entity]) isNotFound(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		errType := reflect.TypeOf(err)
+		if errType.Kind() == reflect.Pointer && errType.Elem().Name() == "NotFoundError" &&
+			errType.Elem().PkgPath() == d.pkgPath {
+			return true
+		}
+	}
+	return false
+}
+
+// Predicate translates the predicate of the request to the ent predicate of the field, it can be
+// converted to the predicates of the generated packages, like `predicate.User(p)`
+func Predicate(p grfctx.Predicate) (func(*sql.Selector), error) {
+	switch p.Operator {
+	case grfctx.OperatorExact:
+		return sql.FieldEQ(p.Field, p.Value), nil
+	case grfctx.OperatorGt:
+		return sql.FieldGT(p.Field, p.Value), nil
+	case grfctx.OperatorGte:
+		return sql.FieldGTE(p.Field, p.Value), nil
+	case grfctx.OperatorLt:
+		return sql.FieldLT(p.Field, p.Value), nil
+	case grfctx.OperatorLte:
+		return sql.FieldLTE(p.Field, p.Value), nil
+	case grfctx.OperatorContains:
+		return sql.FieldContains(p.Field, fmt.Sprint(p.Value)), nil
+	case grfctx.OperatorIContains:
+		return sql.FieldContainsFold(p.Field, fmt.Sprint(p.Value)), nil
+	case grfctx.OperatorStartsWith:
+		return sql.FieldHasPrefix(p.Field, fmt.Sprint(p.Value)), nil
+	case grfctx.OperatorIn:
+		values, ok := p.Value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("entq: the values of `%s` are not a list: %v", p.Field, p.Value)
+		}
+		return sql.FieldIn(p.Field, values...), nil
+	case grfctx.OperatorIsNull:
+		if p.Value == true {
+			return sql.FieldIsNull(p.Field), nil
+		}
+		return sql.FieldNotNull(p.Field), nil
+	}
+	return nil, fmt.Errorf("entq: unsupported operator `%s` of `%s`", p.Operator, p.Field)
+}
+
+// Search returns the ent predicate matching the entities containing every term of the search in
+// any of its fields, ignoring the case
+func Search(search grfctx.Search) func(*sql.Selector) {
+	terms := make([]func(*sql.Selector), 0, len(search.Terms))
+	for _, term := range search.Terms {
+		fields := make([]func(*sql.Selector), 0, len(search.Fields))
+		for _, field := range search.Fields {
+			fields = append(fields, sql.FieldContainsFold(field, term))
+		}
+		terms = append(terms, sql.OrPredicates(fields...))
+	}
+	return sql.AndPredicates(terms...)
+}
+
+// setFields sets the fields of the mutation of the builder to the values, the nil values clear the
+// nillable fields
+func setFields(builder reflect.Value, values models.InternalValue, fields []string) error {
+	mutation := call(builder, "Mutation")[0].Interface().(ent.Mutation)
+	for _, field := range fields {
+		if field == "id" || field == "edges" {
+			continue
+		}
+		value := reflect.ValueOf(values[field])
+		if !value.IsValid() || (value.Kind() == reflect.Pointer && value.IsNil()) {
+			if clearErr := mutation.ClearField(field); clearErr != nil {
+				return clearErr
+			}
+			continue
+		}
+		if value.Kind() == reflect.Pointer {
+			value = value.Elem()
+		}
+		if setErr := mutation.SetField(field, value.Interface()); setErr != nil {
+			return setErr
+		}
+	}
+	return nil
+}
+
+func save[Entity any](ctx context.Context, builder reflect.Value) (Entity, error) {
+	saved, saveErr := results(call(builder, "Save", reflect.ValueOf(ctx)))
+	if saveErr != nil {
+		return *new(Entity), saveErr
+	}
+	return *saved.Interface().(*Entity), nil
+}
+
+// selectors converts the functions to the type of the variadic argument of the method, like the
+// predicate.User of `Where(...predicate.User)`
+func selectors(builder reflect.Value, method string, fns []func(*sql.Selector)) []reflect.Value {
+	argType := builder.MethodByName(method).Type().In(0).Elem()
+	converted := make([]reflect.Value, len(fns))
+	for i, fn := range fns {
+		converted[i] = reflect.ValueOf(fn).Convert(argType)
+	}
+	return converted
+}
+
+func call(v reflect.Value, method string, args ...reflect.Value) []reflect.Value {
+	return v.MethodByName(method).Call(args)
+}
+
+// results splits the results of the methods returning a value and an error, or only an error
+func results(out []reflect.Value) (reflect.Value, error) {
+	err, _ := out[len(out)-1].Interface().(error)
+	if len(out) == 1 {
+		return reflect.Value{}, err
+	}
+	return out[0], err
+}
@@ -0,0 +1,191 @@
+package entq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/filters"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/pagination"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/queries/entq/internal/ent"
+	"github.com/glothriel/grf/pkg/queries/entq/internal/ent/enttest"
+	"github.com/glothriel/grf/pkg/views"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openClient(t *testing.T) *ent.Client {
+	client := enttest.Open(t, "sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func request(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func titles(t *testing.T, w *httptest.ResponseRecorder) []string {
+	var listed []map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	found := []string{}
+	for _, book := range listed {
+		found = append(found, book["title"].(string))
+	}
+	return found
+}
+
+func TestDriver(t *testing.T) {
+	// given
+	client := openClient(t)
+	viewset := views.NewModelViewSet[ent.Book]("/books", New[ent.Book](client)).
+		WithFilterField("pages", filters.Gte, filters.In).
+		WithFilterField("author", filters.Exact).
+		WithSearchFields("title", "author").
+		WithOrderingFields("title", "pages")
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+	for _, body := range []string{
+		`{"title": "Dune", "author": "Herbert", "pages": 412}`,
+		`{"title": "Solaris", "author": "Lem", "pages": 204}`,
+		`{"title": "Fiasco", "author": "Lem", "pages": 322}`,
+	} {
+		require.Equal(t, 201, request(r, "POST", "/books", body).Code)
+	}
+
+	// when
+	filtered := request(r, "GET", "/books?pages__gte=300&ordering=-pages", "")
+	in := request(r, "GET", "/books?pages__in=204,412&ordering=title", "")
+	searched := request(r, "GET", "/books?search=lem%20SOL", "")
+	retrieved := request(r, "GET", "/books/2", "")
+	updated := request(r, "PATCH", "/books/3", `{"pages": 300}`)
+	deleted := request(r, "DELETE", "/books/1", "")
+	missing := request(r, "GET", "/books/1", "")
+	remaining := request(r, "GET", "/books?ordering=title", "")
+
+	// then
+	assert.Equal(t, []string{"Dune", "Fiasco"}, titles(t, filtered))
+	assert.Equal(t, []string{"Dune", "Solaris"}, titles(t, in))
+	assert.Equal(t, []string{"Solaris"}, titles(t, searched))
+	assert.JSONEq(t, `{"id": 2, "title": "Solaris", "author": "Lem", "pages": 204}`, retrieved.Body.String())
+	assert.Equal(t, 200, updated.Code)
+	assert.JSONEq(t, `{"id": 3, "title": "Fiasco", "author": "Lem", "pages": 300}`, updated.Body.String())
+	assert.Equal(t, 204, deleted.Code)
+	assert.Equal(t, 404, missing.Code)
+	assert.Equal(t, []string{"Fiasco", "Solaris"}, titles(t, remaining))
+	stored := client.Book.GetX(context.Background(), 3)
+	assert.Equal(t, 300, stored.Pages)
+}
+
+func TestDriverPagination(t *testing.T) {
+	// given
+	client := openClient(t)
+	for _, title := range []string{"A", "B", "C", "D", "E"} {
+		client.Book.Create().SetTitle(title).SaveX(context.Background())
+	}
+	viewset := views.NewModelViewSet[ent.Book]("/books", New[ent.Book](client)).
+		WithPagination(pagination.NewLimitOffsetPagination(2))
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	page := request(r, "GET", "/books?limit=2&offset=1", "")
+
+	// then
+	assert.Equal(t, 200, page.Code)
+	var body struct {
+		Count   int              `json:"count"`
+		Results []map[string]any `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(page.Body.Bytes(), &body))
+	assert.Equal(t, 5, body.Count)
+	require.Len(t, body.Results, 2)
+	assert.Equal(t, "B", body.Results[0]["title"])
+	assert.Equal(t, "C", body.Results[1]["title"])
+}
+
+func TestDriverRollsBackTheTransactions(t *testing.T) {
+	// given
+	client := openClient(t)
+	viewset := views.NewModelViewSet[ent.Book]("/books", New[ent.Book](client)).WithBulkCreate().WithMiddleware(
+		func(next views.OperationFunc) views.OperationFunc {
+			return func(op *views.Operation) (*views.OperationResult, error) {
+				if op.Kind == views.OperationCreate && op.InternalValue["title"] == "Broken" {
+					return nil, errors.New("insert failed")
+				}
+				return next(op)
+			}
+		},
+	)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	failed := request(r, "POST", "/books", `[{"title": "Dune"}, {"title": "Broken"}]`)
+	succeeded := request(r, "POST", "/books", `[{"title": "Dune"}, {"title": "Solaris"}]`)
+
+	// then
+	assert.Equal(t, 500, failed.Code)
+	assert.Equal(t, 201, succeeded.Code)
+	assert.Equal(t, []string{"Dune", "Solaris"}, titles(t, request(r, "GET", "/books", "")))
+}
+
+func TestDriverAppliesTheUpdatePreconditions(t *testing.T) {
+	// given
+	client := openClient(t)
+	client.Book.Create().SetTitle("Dune").SetPages(412).SaveX(context.Background())
+	driver := New[ent.Book](client)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("PATCH", "/books/1", nil)
+
+	// when
+	grfctx.SetUpdatePrecondition(ctx, &grfctx.Predicate{Field: "pages", Operator: grfctx.OperatorExact, Value: 412})
+	_, updateErr := driver.CRUD().Update(ctx, nil, models.InternalValue{"pages": 500}, 1)
+	_, conflictErr := driver.CRUD().Update(ctx, nil, models.InternalValue{"pages": 600}, 1)
+
+	// then
+	assert.NoError(t, updateErr)
+	assert.ErrorIs(t, conflictErr, common.ErrPreconditionFailed)
+	assert.Equal(t, 500, client.Book.GetX(context.Background(), 1).Pages)
+}
+
+func TestDriverTranslatesTheNotFoundErrors(t *testing.T) {
+	// given
+	client := openClient(t)
+	d := &driver[ent.Book]{client: reflect.ValueOf(client), name: "Book", pkgPath: "github.com/glothriel/grf/pkg/queries/entq/internal/ent"}
+
+	// when
+	deleteErr := d.delete(context.Background(), 1)
+
+	// then
+	assert.True(t, ent.IsNotFound(deleteErr))
+	assert.True(t, d.isNotFound(deleteErr))
+	assert.False(t, d.isNotFound(errors.New("other")))
+}
+
+func TestPredicate(t *testing.T) {
+	_, unsupportedErr := Predicate(grfctx.Predicate{Field: "title", Operator: "regex", Value: "x"})
+	_, invalidInErr := Predicate(grfctx.Predicate{Field: "title", Operator: grfctx.OperatorIn, Value: "x"})
+
+	assert.EqualError(t, unsupportedErr, "entq: unsupported operator `regex` of `title`")
+	assert.Error(t, invalidInErr)
+}
+
+func TestNewPanicsWithoutTheEntityClient(t *testing.T) {
+	type Author struct {
+		ID int `json:"id"`
+	}
+	assert.Panics(t, func() { New[Author](openClient(t)) })
+}
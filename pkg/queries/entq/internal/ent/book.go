@@ -0,0 +1,136 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/glothriel/grf/pkg/queries/entq/internal/ent/book"
+)
+
+// Book is the model entity for the Book schema.
+type Book struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// Title holds the value of the "title" field.
+	Title string `json:"title,omitempty"`
+	// Author holds the value of the "author" field.
+	Author string `json:"author,omitempty"`
+	// Pages holds the value of the "pages" field.
+	Pages int `json:"pages,omitempty"`
+	// ShelfID holds the value of the "shelf_id" field.
+	ShelfID      int `json:"shelf_id,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*Book) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case book.FieldID, book.FieldPages, book.FieldShelfID:
+			values[i] = new(sql.NullInt64)
+		case book.FieldTitle, book.FieldAuthor:
+			values[i] = new(sql.NullString)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the Book fields.
+func (_m *Book) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case book.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			_m.ID = int(value.Int64)
+		case book.FieldTitle:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field title", values[i])
+			} else if value.Valid {
+				_m.Title = value.String
+			}
+		case book.FieldAuthor:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field author", values[i])
+			} else if value.Valid {
+				_m.Author = value.String
+			}
+		case book.FieldPages:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field pages", values[i])
+			} else if value.Valid {
+				_m.Pages = int(value.Int64)
+			}
+		case book.FieldShelfID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field shelf_id", values[i])
+			} else if value.Valid {
+				_m.ShelfID = int(value.Int64)
+			}
+		default:
+			_m.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the Book.
+// This includes values selected through modifiers, order, etc.
+func (_m *Book) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this Book.
+// Note that you need to call Book.Unwrap() before calling this method if this Book
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (_m *Book) Update() *BookUpdateOne {
+	return NewBookClient(_m.config).UpdateOne(_m)
+}
+
+// Unwrap unwraps the Book entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (_m *Book) Unwrap() *Book {
+	_tx, ok := _m.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: Book is not a transactional entity")
+	}
+	_m.config.driver = _tx.drv
+	return _m
+}
+
+// String implements the fmt.Stringer.
+func (_m *Book) String() string {
+	var builder strings.Builder
+	builder.WriteString("Book(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", _m.ID))
+	builder.WriteString("title=")
+	builder.WriteString(_m.Title)
+	builder.WriteString(", ")
+	builder.WriteString("author=")
+	builder.WriteString(_m.Author)
+	builder.WriteString(", ")
+	builder.WriteString("pages=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Pages))
+	builder.WriteString(", ")
+	builder.WriteString("shelf_id=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ShelfID))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// Books is a parsable slice of Book.
+type Books []*Book
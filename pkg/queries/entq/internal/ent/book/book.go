@@ -0,0 +1,80 @@
+// Code generated by ent, DO NOT EDIT.
+
+package book
+
+import (
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	// Label holds the string label denoting the book type in the database.
+	Label = "book"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldTitle holds the string denoting the title field in the database.
+	FieldTitle = "title"
+	// FieldAuthor holds the string denoting the author field in the database.
+	FieldAuthor = "author"
+	// FieldPages holds the string denoting the pages field in the database.
+	FieldPages = "pages"
+	// FieldShelfID holds the string denoting the shelf_id field in the database.
+	FieldShelfID = "shelf_id"
+	// Table holds the table name of the book in the database.
+	Table = "books"
+)
+
+// Columns holds all SQL columns for book fields.
+var Columns = []string{
+	FieldID,
+	FieldTitle,
+	FieldAuthor,
+	FieldPages,
+	FieldShelfID,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// DefaultAuthor holds the default value on creation for the "author" field.
+	DefaultAuthor string
+	// DefaultPages holds the default value on creation for the "pages" field.
+	DefaultPages int
+	// DefaultShelfID holds the default value on creation for the "shelf_id" field.
+	DefaultShelfID int
+)
+
+// OrderOption defines the ordering options for the Book queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByTitle orders the results by the title field.
+func ByTitle(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTitle, opts...).ToFunc()
+}
+
+// ByAuthor orders the results by the author field.
+func ByAuthor(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAuthor, opts...).ToFunc()
+}
+
+// ByPages orders the results by the pages field.
+func ByPages(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPages, opts...).ToFunc()
+}
+
+// ByShelfID orders the results by the shelf_id field.
+func ByShelfID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldShelfID, opts...).ToFunc()
+}
@@ -0,0 +1,298 @@
+// Code generated by ent, DO NOT EDIT.
+
+package book
+
+import (
+	"entgo.io/ent/dialect/sql"
+	"github.com/glothriel/grf/pkg/queries/entq/internal/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.Book {
+	return predicate.Book(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.Book {
+	return predicate.Book(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.Book {
+	return predicate.Book(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.Book {
+	return predicate.Book(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.Book {
+	return predicate.Book(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.Book {
+	return predicate.Book(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.Book {
+	return predicate.Book(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.Book {
+	return predicate.Book(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.Book {
+	return predicate.Book(sql.FieldLTE(FieldID, id))
+}
+
+// Title applies equality check predicate on the "title" field. It's identical to TitleEQ.
+func Title(v string) predicate.Book {
+	return predicate.Book(sql.FieldEQ(FieldTitle, v))
+}
+
+// Author applies equality check predicate on the "author" field. It's identical to AuthorEQ.
+func Author(v string) predicate.Book {
+	return predicate.Book(sql.FieldEQ(FieldAuthor, v))
+}
+
+// Pages applies equality check predicate on the "pages" field. It's identical to PagesEQ.
+func Pages(v int) predicate.Book {
+	return predicate.Book(sql.FieldEQ(FieldPages, v))
+}
+
+// ShelfID applies equality check predicate on the "shelf_id" field. It's identical to ShelfIDEQ.
+func ShelfID(v int) predicate.Book {
+	return predicate.Book(sql.FieldEQ(FieldShelfID, v))
+}
+
+// TitleEQ applies the EQ predicate on the "title" field.
+func TitleEQ(v string) predicate.Book {
+	return predicate.Book(sql.FieldEQ(FieldTitle, v))
+}
+
+// TitleNEQ applies the NEQ predicate on the "title" field.
+func TitleNEQ(v string) predicate.Book {
+	return predicate.Book(sql.FieldNEQ(FieldTitle, v))
+}
+
+// TitleIn applies the In predicate on the "title" field.
+func TitleIn(vs ...string) predicate.Book {
+	return predicate.Book(sql.FieldIn(FieldTitle, vs...))
+}
+
+// TitleNotIn applies the NotIn predicate on the "title" field.
+func TitleNotIn(vs ...string) predicate.Book {
+	return predicate.Book(sql.FieldNotIn(FieldTitle, vs...))
+}
+
+// TitleGT applies the GT predicate on the "title" field.
+func TitleGT(v string) predicate.Book {
+	return predicate.Book(sql.FieldGT(FieldTitle, v))
+}
+
+// TitleGTE applies the GTE predicate on the "title" field.
+func TitleGTE(v string) predicate.Book {
+	return predicate.Book(sql.FieldGTE(FieldTitle, v))
+}
+
+// TitleLT applies the LT predicate on the "title" field.
+func TitleLT(v string) predicate.Book {
+	return predicate.Book(sql.FieldLT(FieldTitle, v))
+}
+
+// TitleLTE applies the LTE predicate on the "title" field.
+func TitleLTE(v string) predicate.Book {
+	return predicate.Book(sql.FieldLTE(FieldTitle, v))
+}
+
+// TitleContains applies the Contains predicate on the "title" field.
+func TitleContains(v string) predicate.Book {
+	return predicate.Book(sql.FieldContains(FieldTitle, v))
+}
+
+// TitleHasPrefix applies the HasPrefix predicate on the "title" field.
+func TitleHasPrefix(v string) predicate.Book {
+	return predicate.Book(sql.FieldHasPrefix(FieldTitle, v))
+}
+
+// TitleHasSuffix applies the HasSuffix predicate on the "title" field.
+func TitleHasSuffix(v string) predicate.Book {
+	return predicate.Book(sql.FieldHasSuffix(FieldTitle, v))
+}
+
+// TitleEqualFold applies the EqualFold predicate on the "title" field.
+func TitleEqualFold(v string) predicate.Book {
+	return predicate.Book(sql.FieldEqualFold(FieldTitle, v))
+}
+
+// TitleContainsFold applies the ContainsFold predicate on the "title" field.
+func TitleContainsFold(v string) predicate.Book {
+	return predicate.Book(sql.FieldContainsFold(FieldTitle, v))
+}
+
+// AuthorEQ applies the EQ predicate on the "author" field.
+func AuthorEQ(v string) predicate.Book {
+	return predicate.Book(sql.FieldEQ(FieldAuthor, v))
+}
+
+// AuthorNEQ applies the NEQ predicate on the "author" field.
+func AuthorNEQ(v string) predicate.Book {
+	return predicate.Book(sql.FieldNEQ(FieldAuthor, v))
+}
+
+// AuthorIn applies the In predicate on the "author" field.
+func AuthorIn(vs ...string) predicate.Book {
+	return predicate.Book(sql.FieldIn(FieldAuthor, vs...))
+}
+
+// AuthorNotIn applies the NotIn predicate on the "author" field.
+func AuthorNotIn(vs ...string) predicate.Book {
+	return predicate.Book(sql.FieldNotIn(FieldAuthor, vs...))
+}
+
+// AuthorGT applies the GT predicate on the "author" field.
+func AuthorGT(v string) predicate.Book {
+	return predicate.Book(sql.FieldGT(FieldAuthor, v))
+}
+
+// AuthorGTE applies the GTE predicate on the "author" field.
+func AuthorGTE(v string) predicate.Book {
+	return predicate.Book(sql.FieldGTE(FieldAuthor, v))
+}
+
+// AuthorLT applies the LT predicate on the "author" field.
+func AuthorLT(v string) predicate.Book {
+	return predicate.Book(sql.FieldLT(FieldAuthor, v))
+}
+
+// AuthorLTE applies the LTE predicate on the "author" field.
+func AuthorLTE(v string) predicate.Book {
+	return predicate.Book(sql.FieldLTE(FieldAuthor, v))
+}
+
+// AuthorContains applies the Contains predicate on the "author" field.
+func AuthorContains(v string) predicate.Book {
+	return predicate.Book(sql.FieldContains(FieldAuthor, v))
+}
+
+// AuthorHasPrefix applies the HasPrefix predicate on the "author" field.
+func AuthorHasPrefix(v string) predicate.Book {
+	return predicate.Book(sql.FieldHasPrefix(FieldAuthor, v))
+}
+
+// AuthorHasSuffix applies the HasSuffix predicate on the "author" field.
+func AuthorHasSuffix(v string) predicate.Book {
+	return predicate.Book(sql.FieldHasSuffix(FieldAuthor, v))
+}
+
+// AuthorEqualFold applies the EqualFold predicate on the "author" field.
+func AuthorEqualFold(v string) predicate.Book {
+	return predicate.Book(sql.FieldEqualFold(FieldAuthor, v))
+}
+
+// AuthorContainsFold applies the ContainsFold predicate on the "author" field.
+func AuthorContainsFold(v string) predicate.Book {
+	return predicate.Book(sql.FieldContainsFold(FieldAuthor, v))
+}
+
+// PagesEQ applies the EQ predicate on the "pages" field.
+func PagesEQ(v int) predicate.Book {
+	return predicate.Book(sql.FieldEQ(FieldPages, v))
+}
+
+// PagesNEQ applies the NEQ predicate on the "pages" field.
+func PagesNEQ(v int) predicate.Book {
+	return predicate.Book(sql.FieldNEQ(FieldPages, v))
+}
+
+// PagesIn applies the In predicate on the "pages" field.
+func PagesIn(vs ...int) predicate.Book {
+	return predicate.Book(sql.FieldIn(FieldPages, vs...))
+}
+
+// PagesNotIn applies the NotIn predicate on the "pages" field.
+func PagesNotIn(vs ...int) predicate.Book {
+	return predicate.Book(sql.FieldNotIn(FieldPages, vs...))
+}
+
+// PagesGT applies the GT predicate on the "pages" field.
+func PagesGT(v int) predicate.Book {
+	return predicate.Book(sql.FieldGT(FieldPages, v))
+}
+
+// PagesGTE applies the GTE predicate on the "pages" field.
+func PagesGTE(v int) predicate.Book {
+	return predicate.Book(sql.FieldGTE(FieldPages, v))
+}
+
+// PagesLT applies the LT predicate on the "pages" field.
+func PagesLT(v int) predicate.Book {
+	return predicate.Book(sql.FieldLT(FieldPages, v))
+}
+
+// PagesLTE applies the LTE predicate on the "pages" field.
+func PagesLTE(v int) predicate.Book {
+	return predicate.Book(sql.FieldLTE(FieldPages, v))
+}
+
+// ShelfIDEQ applies the EQ predicate on the "shelf_id" field.
+func ShelfIDEQ(v int) predicate.Book {
+	return predicate.Book(sql.FieldEQ(FieldShelfID, v))
+}
+
+// ShelfIDNEQ applies the NEQ predicate on the "shelf_id" field.
+func ShelfIDNEQ(v int) predicate.Book {
+	return predicate.Book(sql.FieldNEQ(FieldShelfID, v))
+}
+
+// ShelfIDIn applies the In predicate on the "shelf_id" field.
+func ShelfIDIn(vs ...int) predicate.Book {
+	return predicate.Book(sql.FieldIn(FieldShelfID, vs...))
+}
+
+// ShelfIDNotIn applies the NotIn predicate on the "shelf_id" field.
+func ShelfIDNotIn(vs ...int) predicate.Book {
+	return predicate.Book(sql.FieldNotIn(FieldShelfID, vs...))
+}
+
+// ShelfIDGT applies the GT predicate on the "shelf_id" field.
+func ShelfIDGT(v int) predicate.Book {
+	return predicate.Book(sql.FieldGT(FieldShelfID, v))
+}
+
+// ShelfIDGTE applies the GTE predicate on the "shelf_id" field.
+func ShelfIDGTE(v int) predicate.Book {
+	return predicate.Book(sql.FieldGTE(FieldShelfID, v))
+}
+
+// ShelfIDLT applies the LT predicate on the "shelf_id" field.
+func ShelfIDLT(v int) predicate.Book {
+	return predicate.Book(sql.FieldLT(FieldShelfID, v))
+}
+
+// ShelfIDLTE applies the LTE predicate on the "shelf_id" field.
+func ShelfIDLTE(v int) predicate.Book {
+	return predicate.Book(sql.FieldLTE(FieldShelfID, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.Book) predicate.Book {
+	return predicate.Book(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.Book) predicate.Book {
+	return predicate.Book(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.Book) predicate.Book {
+	return predicate.Book(sql.NotPredicates(p))
+}
@@ -0,0 +1,264 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/glothriel/grf/pkg/queries/entq/internal/ent/book"
+)
+
+// BookCreate is the builder for creating a Book entity.
+type BookCreate struct {
+	config
+	mutation *BookMutation
+	hooks    []Hook
+}
+
+// SetTitle sets the "title" field.
+func (_c *BookCreate) SetTitle(v string) *BookCreate {
+	_c.mutation.SetTitle(v)
+	return _c
+}
+
+// SetAuthor sets the "author" field.
+func (_c *BookCreate) SetAuthor(v string) *BookCreate {
+	_c.mutation.SetAuthor(v)
+	return _c
+}
+
+// SetNillableAuthor sets the "author" field if the given value is not nil.
+func (_c *BookCreate) SetNillableAuthor(v *string) *BookCreate {
+	if v != nil {
+		_c.SetAuthor(*v)
+	}
+	return _c
+}
+
+// SetPages sets the "pages" field.
+func (_c *BookCreate) SetPages(v int) *BookCreate {
+	_c.mutation.SetPages(v)
+	return _c
+}
+
+// SetNillablePages sets the "pages" field if the given value is not nil.
+func (_c *BookCreate) SetNillablePages(v *int) *BookCreate {
+	if v != nil {
+		_c.SetPages(*v)
+	}
+	return _c
+}
+
+// SetShelfID sets the "shelf_id" field.
+func (_c *BookCreate) SetShelfID(v int) *BookCreate {
+	_c.mutation.SetShelfID(v)
+	return _c
+}
+
+// SetNillableShelfID sets the "shelf_id" field if the given value is not nil.
+func (_c *BookCreate) SetNillableShelfID(v *int) *BookCreate {
+	if v != nil {
+		_c.SetShelfID(*v)
+	}
+	return _c
+}
+
+// Mutation returns the BookMutation object of the builder.
+func (_c *BookCreate) Mutation() *BookMutation {
+	return _c.mutation
+}
+
+// Save creates the Book in the database.
+func (_c *BookCreate) Save(ctx context.Context) (*Book, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *BookCreate) SaveX(ctx context.Context) *Book {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *BookCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *BookCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *BookCreate) defaults() {
+	if _, ok := _c.mutation.Author(); !ok {
+		v := book.DefaultAuthor
+		_c.mutation.SetAuthor(v)
+	}
+	if _, ok := _c.mutation.Pages(); !ok {
+		v := book.DefaultPages
+		_c.mutation.SetPages(v)
+	}
+	if _, ok := _c.mutation.ShelfID(); !ok {
+		v := book.DefaultShelfID
+		_c.mutation.SetShelfID(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *BookCreate) check() error {
+	if _, ok := _c.mutation.Title(); !ok {
+		return &ValidationError{Name: "title", err: errors.New(`ent: missing required field "Book.title"`)}
+	}
+	if _, ok := _c.mutation.Author(); !ok {
+		return &ValidationError{Name: "author", err: errors.New(`ent: missing required field "Book.author"`)}
+	}
+	if _, ok := _c.mutation.Pages(); !ok {
+		return &ValidationError{Name: "pages", err: errors.New(`ent: missing required field "Book.pages"`)}
+	}
+	if _, ok := _c.mutation.ShelfID(); !ok {
+		return &ValidationError{Name: "shelf_id", err: errors.New(`ent: missing required field "Book.shelf_id"`)}
+	}
+	return nil
+}
+
+func (_c *BookCreate) sqlSave(ctx context.Context) (*Book, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *BookCreate) createSpec() (*Book, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Book{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(book.Table, sqlgraph.NewFieldSpec(book.FieldID, field.TypeInt))
+	)
+	if value, ok := _c.mutation.Title(); ok {
+		_spec.SetField(book.FieldTitle, field.TypeString, value)
+		_node.Title = value
+	}
+	if value, ok := _c.mutation.Author(); ok {
+		_spec.SetField(book.FieldAuthor, field.TypeString, value)
+		_node.Author = value
+	}
+	if value, ok := _c.mutation.Pages(); ok {
+		_spec.SetField(book.FieldPages, field.TypeInt, value)
+		_node.Pages = value
+	}
+	if value, ok := _c.mutation.ShelfID(); ok {
+		_spec.SetField(book.FieldShelfID, field.TypeInt, value)
+		_node.ShelfID = value
+	}
+	return _node, _spec
+}
+
+// BookCreateBulk is the builder for creating many Book entities in bulk.
+type BookCreateBulk struct {
+	config
+	err      error
+	builders []*BookCreate
+}
+
+// Save creates the Book entities in the database.
+func (_c *BookCreateBulk) Save(ctx context.Context) ([]*Book, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*Book, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*BookMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *BookCreateBulk) SaveX(ctx context.Context) []*Book {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *BookCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *BookCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,351 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/glothriel/grf/pkg/queries/entq/internal/ent/book"
+	"github.com/glothriel/grf/pkg/queries/entq/internal/ent/predicate"
+)
+
+// BookUpdate is the builder for updating Book entities.
+type BookUpdate struct {
+	config
+	hooks    []Hook
+	mutation *BookMutation
+}
+
+// Where appends a list predicates to the BookUpdate builder.
+func (_u *BookUpdate) Where(ps ...predicate.Book) *BookUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetTitle sets the "title" field.
+func (_u *BookUpdate) SetTitle(v string) *BookUpdate {
+	_u.mutation.SetTitle(v)
+	return _u
+}
+
+// SetNillableTitle sets the "title" field if the given value is not nil.
+func (_u *BookUpdate) SetNillableTitle(v *string) *BookUpdate {
+	if v != nil {
+		_u.SetTitle(*v)
+	}
+	return _u
+}
+
+// SetAuthor sets the "author" field.
+func (_u *BookUpdate) SetAuthor(v string) *BookUpdate {
+	_u.mutation.SetAuthor(v)
+	return _u
+}
+
+// SetNillableAuthor sets the "author" field if the given value is not nil.
+func (_u *BookUpdate) SetNillableAuthor(v *string) *BookUpdate {
+	if v != nil {
+		_u.SetAuthor(*v)
+	}
+	return _u
+}
+
+// SetPages sets the "pages" field.
+func (_u *BookUpdate) SetPages(v int) *BookUpdate {
+	_u.mutation.ResetPages()
+	_u.mutation.SetPages(v)
+	return _u
+}
+
+// SetNillablePages sets the "pages" field if the given value is not nil.
+func (_u *BookUpdate) SetNillablePages(v *int) *BookUpdate {
+	if v != nil {
+		_u.SetPages(*v)
+	}
+	return _u
+}
+
+// AddPages adds value to the "pages" field.
+func (_u *BookUpdate) AddPages(v int) *BookUpdate {
+	_u.mutation.AddPages(v)
+	return _u
+}
+
+// SetShelfID sets the "shelf_id" field.
+func (_u *BookUpdate) SetShelfID(v int) *BookUpdate {
+	_u.mutation.ResetShelfID()
+	_u.mutation.SetShelfID(v)
+	return _u
+}
+
+// SetNillableShelfID sets the "shelf_id" field if the given value is not nil.
+func (_u *BookUpdate) SetNillableShelfID(v *int) *BookUpdate {
+	if v != nil {
+		_u.SetShelfID(*v)
+	}
+	return _u
+}
+
+// AddShelfID adds value to the "shelf_id" field.
+func (_u *BookUpdate) AddShelfID(v int) *BookUpdate {
+	_u.mutation.AddShelfID(v)
+	return _u
+}
+
+// Mutation returns the BookMutation object of the builder.
+func (_u *BookUpdate) Mutation() *BookMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *BookUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *BookUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *BookUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *BookUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (_u *BookUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(book.Table, book.Columns, sqlgraph.NewFieldSpec(book.FieldID, field.TypeInt))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Title(); ok {
+		_spec.SetField(book.FieldTitle, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Author(); ok {
+		_spec.SetField(book.FieldAuthor, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Pages(); ok {
+		_spec.SetField(book.FieldPages, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedPages(); ok {
+		_spec.AddField(book.FieldPages, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ShelfID(); ok {
+		_spec.SetField(book.FieldShelfID, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedShelfID(); ok {
+		_spec.AddField(book.FieldShelfID, field.TypeInt, value)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{book.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// BookUpdateOne is the builder for updating a single Book entity.
+type BookUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *BookMutation
+}
+
+// SetTitle sets the "title" field.
+func (_u *BookUpdateOne) SetTitle(v string) *BookUpdateOne {
+	_u.mutation.SetTitle(v)
+	return _u
+}
+
+// SetNillableTitle sets the "title" field if the given value is not nil.
+func (_u *BookUpdateOne) SetNillableTitle(v *string) *BookUpdateOne {
+	if v != nil {
+		_u.SetTitle(*v)
+	}
+	return _u
+}
+
+// SetAuthor sets the "author" field.
+func (_u *BookUpdateOne) SetAuthor(v string) *BookUpdateOne {
+	_u.mutation.SetAuthor(v)
+	return _u
+}
+
+// SetNillableAuthor sets the "author" field if the given value is not nil.
+func (_u *BookUpdateOne) SetNillableAuthor(v *string) *BookUpdateOne {
+	if v != nil {
+		_u.SetAuthor(*v)
+	}
+	return _u
+}
+
+// SetPages sets the "pages" field.
+func (_u *BookUpdateOne) SetPages(v int) *BookUpdateOne {
+	_u.mutation.ResetPages()
+	_u.mutation.SetPages(v)
+	return _u
+}
+
+// SetNillablePages sets the "pages" field if the given value is not nil.
+func (_u *BookUpdateOne) SetNillablePages(v *int) *BookUpdateOne {
+	if v != nil {
+		_u.SetPages(*v)
+	}
+	return _u
+}
+
+// AddPages adds value to the "pages" field.
+func (_u *BookUpdateOne) AddPages(v int) *BookUpdateOne {
+	_u.mutation.AddPages(v)
+	return _u
+}
+
+// SetShelfID sets the "shelf_id" field.
+func (_u *BookUpdateOne) SetShelfID(v int) *BookUpdateOne {
+	_u.mutation.ResetShelfID()
+	_u.mutation.SetShelfID(v)
+	return _u
+}
+
+// SetNillableShelfID sets the "shelf_id" field if the given value is not nil.
+func (_u *BookUpdateOne) SetNillableShelfID(v *int) *BookUpdateOne {
+	if v != nil {
+		_u.SetShelfID(*v)
+	}
+	return _u
+}
+
+// AddShelfID adds value to the "shelf_id" field.
+func (_u *BookUpdateOne) AddShelfID(v int) *BookUpdateOne {
+	_u.mutation.AddShelfID(v)
+	return _u
+}
+
+// Mutation returns the BookMutation object of the builder.
+func (_u *BookUpdateOne) Mutation() *BookMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the BookUpdate builder.
+func (_u *BookUpdateOne) Where(ps ...predicate.Book) *BookUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *BookUpdateOne) Select(field string, fields ...string) *BookUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated Book entity.
+func (_u *BookUpdateOne) Save(ctx context.Context) (*Book, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *BookUpdateOne) SaveX(ctx context.Context) *Book {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *BookUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *BookUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (_u *BookUpdateOne) sqlSave(ctx context.Context) (_node *Book, err error) {
+	_spec := sqlgraph.NewUpdateSpec(book.Table, book.Columns, sqlgraph.NewFieldSpec(book.FieldID, field.TypeInt))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Book.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, book.FieldID)
+		for _, f := range fields {
+			if !book.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != book.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Title(); ok {
+		_spec.SetField(book.FieldTitle, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Author(); ok {
+		_spec.SetField(book.FieldAuthor, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Pages(); ok {
+		_spec.SetField(book.FieldPages, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedPages(); ok {
+		_spec.AddField(book.FieldPages, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ShelfID(); ok {
+		_spec.SetField(book.FieldShelfID, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedShelfID(); ok {
+		_spec.AddField(book.FieldShelfID, field.TypeInt, value)
+	}
+	_node = &Book{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{book.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,4 @@
+// Package ent holds the client generated by ent for the schema of the tests
+package ent
+
+//go:generate go run -mod=mod entgo.io/ent/cmd/ent generate ./schema
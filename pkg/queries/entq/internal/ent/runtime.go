@@ -0,0 +1,28 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"github.com/glothriel/grf/pkg/queries/entq/internal/ent/book"
+	"github.com/glothriel/grf/pkg/queries/entq/internal/ent/schema"
+)
+
+// The init function reads all schema descriptors with runtime code
+// (default values, validators, hooks and policies) and stitches it
+// to their package variables.
+func init() {
+	bookFields := schema.Book{}.Fields()
+	_ = bookFields
+	// bookDescAuthor is the schema descriptor for author field.
+	bookDescAuthor := bookFields[1].Descriptor()
+	// book.DefaultAuthor holds the default value on creation for the author field.
+	book.DefaultAuthor = bookDescAuthor.Default.(string)
+	// bookDescPages is the schema descriptor for pages field.
+	bookDescPages := bookFields[2].Descriptor()
+	// book.DefaultPages holds the default value on creation for the pages field.
+	book.DefaultPages = bookDescPages.Default.(int)
+	// bookDescShelfID is the schema descriptor for shelf_id field.
+	bookDescShelfID := bookFields[3].Descriptor()
+	// book.DefaultShelfID holds the default value on creation for the shelf_id field.
+	book.DefaultShelfID = bookDescShelfID.Default.(int)
+}
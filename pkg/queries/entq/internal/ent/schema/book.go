@@ -0,0 +1,20 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+)
+
+// Book holds the schema of the books served in the tests
+type Book struct {
+	ent.Schema
+}
+
+func (Book) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("title"),
+		field.String("author").Default(""),
+		field.Int("pages").Default(0),
+		field.Int("shelf_id").Default(0),
+	}
+}
@@ -0,0 +1,370 @@
+// Package funcq implements a query driver calling functions for every operation, so any data access
+// layer can be exposed through the viewsets, for example the clients of other services. The driver
+// doesn't depend on any of them, the entq module builds the driver of the clients generated by
+// entgo.io on top of it.
+package funcq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/ids"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/queries/crud"
+)
+
+// Query describes the objects requested by the views, the list function translates it to the
+// predicates and the modifiers of the generated query builder, for example:
+//
+//	q := client.User.Query()
+//	for _, p := range query.Predicates {
+//		q.Where(sql.FieldEQ(p.Field, p.Value))
+//	}
+//	for _, o := range query.Ordering {
+//		if o.Descending {
+//			q.Order(ent.Desc(o.Field))
+//		} else {
+//			q.Order(ent.Asc(o.Field))
+//		}
+//	}
+type Query struct {
+	// Predicates include the filters of the request, the parent scope of nested resources and the
+	// lookup of the retrieved objects, all of them have to match
+	Predicates []grfctx.Predicate
+	// Search is the search of the request, every term has to be contained by any of the fields
+	Search   *grfctx.Search
+	Ordering []grfctx.OrderBy
+	// Offset and Limit are the window of the request, Limit is 0 if the objects are not limited
+	Offset int
+	Limit  int
+	// Random requests Limit objects picked at random, ignoring Offset and Ordering
+	Random bool
+}
+
+// ListFunc returns the objects matching the query
+type ListFunc[Model any] func(ctx context.Context, query Query) ([]Model, error)
+
+// CreateFunc creates the object, setting the fields present in the request
+type CreateFunc[Model any] func(ctx context.Context, object Model, fields []string) (Model, error)
+
+// UpdateFunc updates the fields present in the request of the object with the ID
+type UpdateFunc[Model any] func(ctx context.Context, id any, object Model, fields []string) (Model, error)
+
+// DeleteFunc deletes the object with the ID
+type DeleteFunc func(ctx context.Context, id any) error
+
+// TxFunc runs fn in a transaction, passing it the context holding the transaction, for example:
+//
+//	func(ctx context.Context, fn func(context.Context) error) error {
+//		tx, err := client.Tx(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		if err := fn(ent.NewTxContext(ctx, tx)); err != nil {
+//			return errors.Join(err, tx.Rollback())
+//		}
+//		return tx.Commit()
+//	}
+//
+// The other functions then use the client of the transaction, if `ent.TxFromContext(ctx)` is set.
+type TxFunc func(ctx context.Context, fn func(ctx context.Context) error) error
+
+// CountFunc returns the number of the objects matching the query, the window isn't set
+type CountFunc func(ctx context.Context, query Query) (int, error)
+
+// Driver is a query driver calling the functions, see New
+type Driver[Model any] struct {
+	list       ListFunc[Model]
+	count      CountFunc
+	create     CreateFunc[Model]
+	update     UpdateFunc[Model]
+	delete     DeleteFunc
+	tx         TxFunc
+	isNotFound func(error) bool
+}
+
+// New creates a driver listing the objects using the function, the other operations are not
+// allowed until their functions are set. The objects are retrieved, and checked before updates and
+// deletes, using the list function, so the lookup field and the parent scope of nested resources
+// are respected.
+func New[Model any](list ListFunc[Model]) *Driver[Model] {
+	return &Driver[Model]{list: list}
+}
+
+// WithCount sets the function counting the objects, like `client.User.Query().Where(...).Count(ctx)`,
+// without it the paginations don't report the counts, see queries.ErrCountUnsupported
+func (d *Driver[Model]) WithCount(count CountFunc) *Driver[Model] {
+	d.count = count
+	return d
+}
+
+// WithCreate sets the function creating the objects, like `client.User.Create().SetName(...).Save(ctx)`
+func (d *Driver[Model]) WithCreate(create CreateFunc[Model]) *Driver[Model] {
+	d.create = create
+	return d
+}
+
+// WithUpdate sets the function updating the objects, like `client.User.UpdateOneID(id)...Save(ctx)`
+func (d *Driver[Model]) WithUpdate(update UpdateFunc[Model]) *Driver[Model] {
+	d.update = update
+	return d
+}
+
+// WithDelete sets the function deleting the objects, like `client.User.DeleteOneID(id).Exec(ctx)`
+func (d *Driver[Model]) WithDelete(deleteFunc DeleteFunc) *Driver[Model] {
+	d.delete = deleteFunc
+	return d
+}
+
+// WithTx makes the driver transactional, see TxFunc
+func (d *Driver[Model]) WithTx(tx TxFunc) *Driver[Model] {
+	d.tx = tx
+	return d
+}
+
+// WithNotFound sets the function recognizing the not found errors of the client, usually
+// `ent.IsNotFound`, so they are responded with 404
+func (d *Driver[Model]) WithNotFound(isNotFound func(error) bool) *Driver[Model] {
+	d.isNotFound = isNotFound
+	return d
+}
+
+// ErrOperationNotConfigured is returned by the operations, whose functions were not set
+var ErrOperationNotConfigured = fmt.Errorf("the operation is not configured: %w", common.ErrOperationNotAllowed)
+
+func (d *Driver[Model]) CRUD() *crud.CRUD[Model] {
+	return &crud.CRUD[Model]{
+		List: func(ctx *gin.Context) ([]models.InternalValue, error) {
+			query := d.query(ctx)
+			if window, ok := grfctx.CurrentWindow(ctx); ok {
+				query.Offset, query.Limit, query.Random = window.Offset, window.Limit, window.Random
+				if window.Keyset != nil {
+					query.Offset = 0
+					query.Ordering = []grfctx.OrderBy{{Field: window.Keyset.Field, Descending: window.Keyset.Descending}}
+					if window.Keyset.After != nil {
+						operator := grfctx.OperatorGt
+						if window.Keyset.Descending {
+							operator = grfctx.OperatorLt
+						}
+						query.Predicates = append(query.Predicates, grfctx.Predicate{
+							Field: window.Keyset.Field, Operator: operator, Value: window.Keyset.After,
+						})
+					}
+				}
+			}
+			return d.listInternalValues(ctx, query)
+		},
+		Retrieve: d.retrieve,
+		Create: func(ctx *gin.Context, m models.InternalValue) (models.InternalValue, error) {
+			if d.create == nil {
+				return nil, ErrOperationNotConfigured
+			}
+			if _, generateErr := ids.Assign[Model](m); generateErr != nil {
+				return nil, generateErr
+			}
+			object, asModelErr := models.AsModel[Model](m)
+			if asModelErr != nil {
+				return nil, asModelErr
+			}
+			created, createErr := d.create(requestContext(ctx), object, fields(m, ""))
+			if createErr != nil {
+				return nil, d.translate(createErr)
+			}
+			return models.AsInternalValue(created), nil
+		},
+		Update: func(ctx *gin.Context, old models.InternalValue, new models.InternalValue, id any) (
+			models.InternalValue, error,
+		) {
+			if d.update == nil {
+				return nil, ErrOperationNotConfigured
+			}
+			stored, retrieveErr := d.retrieve(ctx, id)
+			if retrieveErr != nil {
+				return nil, retrieveErr
+			}
+			updateCtx := requestContext(ctx)
+			if precondition, conditional := grfctx.UpdatePrecondition(ctx); conditional {
+				if !common.MatchesPredicates(stored, []grfctx.Predicate{precondition}) {
+					return nil, common.ErrPreconditionFailed
+				}
+				updateCtx = context.WithValue(updateCtx, preconditionCtxKey{}, precondition)
+			}
+			merged := maps.Clone(stored)
+			maps.Copy(merged, new)
+			object, asModelErr := models.AsModel[Model](merged)
+			if asModelErr != nil {
+				return nil, asModelErr
+			}
+			updated, updateErr := d.update(updateCtx, stored["id"], object, fields(new, "id"))
+			if updateErr != nil {
+				return nil, d.translate(updateErr)
+			}
+			return models.AsInternalValue(updated), nil
+		},
+		Destroy: func(ctx *gin.Context, id any) error {
+			if d.delete == nil {
+				return ErrOperationNotConfigured
+			}
+			stored, retrieveErr := d.retrieve(ctx, id)
+			if retrieveErr != nil {
+				return retrieveErr
+			}
+			return d.translate(d.delete(requestContext(ctx), stored["id"]))
+		},
+	}
+}
+
+func (d *Driver[Model]) retrieve(ctx *gin.Context, id any) (models.InternalValue, error) {
+	query := d.scoped(ctx)
+	query.Predicates = append(query.Predicates, grfctx.Predicate{
+		Field: grfctx.LookupField(ctx), Operator: grfctx.OperatorExact, Value: id,
+	})
+	query.Limit = 1
+	found, listErr := d.listInternalValues(ctx, query)
+	if listErr != nil {
+		return nil, listErr
+	}
+	if len(found) == 0 {
+		return nil, common.ErrorNotFound
+	}
+	return found[0], nil
+}
+
+// Count implements queries.Counter using the function set by WithCount, it returns
+// common.ErrCountUnsupported if there's none
+func (d *Driver[Model]) Count(ctx *gin.Context) (int, error) {
+	if d.count == nil {
+		return 0, common.ErrCountUnsupported
+	}
+	query := d.query(ctx)
+	query.Ordering = nil
+	count, countErr := d.count(requestContext(ctx), query)
+	return count, d.translate(countErr)
+}
+
+// Exists implements queries.Exister by listing at most one object matching the conditions
+func (d *Driver[Model]) Exists(ctx *gin.Context, conditions map[string]any) (bool, error) {
+	query := d.query(ctx)
+	query.Ordering = nil
+	for field, value := range conditions {
+		query.Predicates = append(query.Predicates, grfctx.Predicate{Field: field, Operator: grfctx.OperatorExact, Value: value})
+	}
+	query.Limit = 1
+	found, listErr := d.list(requestContext(ctx), query)
+	return len(found) > 0, d.translate(listErr)
+}
+
+// Atomic implements queries.Transactional if WithTx was used, the requests use the context holding
+// the transaction inside fn. Nested calls use the transaction of the outer one.
+func (d *Driver[Model]) Atomic(ctx *gin.Context, fn func() error) error {
+	if d.tx == nil || ctx.GetBool(txCtxKey) {
+		return fn()
+	}
+	request := ctx.Request
+	defer func() {
+		ctx.Request = request
+		ctx.Set(txCtxKey, false)
+	}()
+	return d.tx(requestContext(ctx), func(txCtx context.Context) error {
+		if request != nil {
+			ctx.Request = request.WithContext(txCtx)
+		}
+		ctx.Set(txCtxKey, true)
+		return fn()
+	})
+}
+
+const txCtxKey = "grf:funcq:tx"
+
+func (d *Driver[Model]) Pagination() common.Pagination {
+	return common.NoPagination{}
+}
+
+func (d *Driver[Model]) Filter() common.QueryMod {
+	return common.NewCompositeQueryMod()
+}
+
+func (d *Driver[Model]) Order() common.QueryMod {
+	return common.NewCompositeQueryMod()
+}
+
+func (d *Driver[Model]) Middleware() []gin.HandlerFunc {
+	return []gin.HandlerFunc{}
+}
+
+// scoped returns the query of the objects in the parent scope of the request
+func (d *Driver[Model]) scoped(ctx *gin.Context) Query {
+	query := Query{}
+	if scope, ok := grfctx.Parent(ctx); ok {
+		query.Predicates = append(query.Predicates, grfctx.Predicate{
+			Field: scope.Field, Operator: grfctx.OperatorExact, Value: scope.Value,
+		})
+	}
+	return query
+}
+
+// query returns the query of the listed objects, without the window
+func (d *Driver[Model]) query(ctx *gin.Context) Query {
+	query := d.scoped(ctx)
+	query.Predicates = append(query.Predicates, grfctx.Predicates(ctx)...)
+	if search, ok := grfctx.CurrentSearch(ctx); ok {
+		query.Search = &search
+	}
+	query.Ordering = grfctx.Ordering(ctx)
+	return query
+}
+
+func (d *Driver[Model]) listInternalValues(ctx *gin.Context, query Query) ([]models.InternalValue, error) {
+	found, listErr := d.list(requestContext(ctx), query)
+	if listErr != nil {
+		return nil, d.translate(listErr)
+	}
+	internalValues := make([]models.InternalValue, 0, len(found))
+	for _, object := range found {
+		internalValues = append(internalValues, models.AsInternalValue(object))
+	}
+	return internalValues, nil
+}
+
+func (d *Driver[Model]) translate(err error) error {
+	if err != nil && d.isNotFound != nil && d.isNotFound(err) {
+		return errors.Join(common.ErrorNotFound, err)
+	}
+	return err
+}
+
+// fields returns the fields present in the internal value, except the skipped one
+func fields(m models.InternalValue, skipped string) []string {
+	present := make([]string, 0, len(m))
+	for field := range m {
+		if field != skipped {
+			present = append(present, field)
+		}
+	}
+	slices.Sort(present)
+	return present
+}
+
+type preconditionCtxKey struct{}
+
+// Precondition returns the precondition of the update passed to the UpdateFunc, the stored object
+// is checked before the update, but the function should also apply it, like
+// `client.User.Update().Where(user.ID(id), user.Version(v))`, returning common.ErrPreconditionFailed
+// when nothing is updated, so concurrent updates are rejected too
+func Precondition(ctx context.Context) (grfctx.Predicate, bool) {
+	precondition, ok := ctx.Value(preconditionCtxKey{}).(grfctx.Predicate)
+	return precondition, ok
+}
+
+func requestContext(ctx *gin.Context) context.Context {
+	if ctx != nil && ctx.Request != nil {
+		return ctx.Request.Context()
+	}
+	return context.Background()
+}
@@ -0,0 +1,185 @@
+package funcq
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/ids"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	TeamID int    `json:"team_id"`
+}
+
+var errNotFound = errors.New("user not found")
+
+// fakeClient stands for a generated client, like the ent one, translating the queries in memory
+type fakeClient struct {
+	users         []user
+	queries       []Query
+	updatedFields [][]string
+}
+
+func (c *fakeClient) driver() *Driver[user] {
+	return New[user](func(_ context.Context, query Query) ([]user, error) {
+		c.queries = append(c.queries, query)
+		found := []user{}
+		for _, u := range c.users {
+			if common.MatchesPredicates(models.AsInternalValue(u), query.Predicates) {
+				found = append(found, u)
+			}
+		}
+		if query.Limit > 0 && len(found) > query.Limit {
+			found = found[:query.Limit]
+		}
+		return found, nil
+	}).WithCount(func(_ context.Context, query Query) (int, error) {
+		count := 0
+		for _, u := range c.users {
+			if common.MatchesPredicates(models.AsInternalValue(u), query.Predicates) {
+				count++
+			}
+		}
+		return count, nil
+	}).WithCreate(func(_ context.Context, u user, _ []string) (user, error) {
+		u.ID = len(c.users) + 1
+		c.users = append(c.users, u)
+		return u, nil
+	}).WithUpdate(func(_ context.Context, id any, u user, fields []string) (user, error) {
+		c.updatedFields = append(c.updatedFields, fields)
+		for i := range c.users {
+			if c.users[i].ID == id {
+				c.users[i] = u
+				return u, nil
+			}
+		}
+		return user{}, errNotFound
+	}).WithDelete(func(_ context.Context, id any) error {
+		for i := range c.users {
+			if c.users[i].ID == id {
+				c.users = append(c.users[:i], c.users[i+1:]...)
+				return nil
+			}
+		}
+		return errNotFound
+	}).WithNotFound(func(err error) bool { return errors.Is(err, errNotFound) })
+}
+
+func TestDriver(t *testing.T) {
+	// given
+	client := &fakeClient{users: []user{{ID: 1, Name: "jane", TeamID: 1}, {ID: 2, Name: "john", TeamID: 2}}}
+	driver := client.driver()
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	grfctx.SetParentScope(ctx, grfctx.ParentScope{Field: "team_id", Value: 1})
+
+	// when
+	created, createErr := driver.CRUD().Create(ctx, models.InternalValue{"name": "jim", "team_id": 1})
+	updated, updateErr := driver.CRUD().Update(ctx, nil, models.InternalValue{"name": "janet"}, 1)
+	_, otherTeamErr := driver.CRUD().Update(ctx, nil, models.InternalValue{"name": "johnny"}, 2)
+	destroyErr := driver.CRUD().Destroy(ctx, 3)
+	listed, listErr := driver.CRUD().List(ctx)
+	count, countErr := driver.Count(ctx)
+
+	// then
+	assert.NoError(t, createErr)
+	assert.Equal(t, models.InternalValue{"id": 3, "name": "jim", "team_id": 1}, created)
+	assert.NoError(t, updateErr)
+	assert.Equal(t, models.InternalValue{"id": 1, "name": "janet", "team_id": 1}, updated)
+	assert.ErrorIs(t, otherTeamErr, common.ErrorNotFound)
+	assert.NoError(t, destroyErr)
+	assert.NoError(t, listErr)
+	assert.Equal(t, []models.InternalValue{{"id": 1, "name": "janet", "team_id": 1}}, listed)
+	assert.NoError(t, countErr)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []grfctx.Predicate{
+		{Field: "team_id", Operator: grfctx.OperatorExact, Value: 1},
+		{Field: "id", Operator: grfctx.OperatorExact, Value: 1},
+	}, client.queries[0].Predicates)
+	assert.Equal(t, 1, client.queries[0].Limit)
+	assert.Equal(t, [][]string{{"name"}}, client.updatedFields)
+}
+
+func TestDriverWithoutFunctions(t *testing.T) {
+	// given
+	driver := New[user](func(context.Context, Query) ([]user, error) {
+		return []user{{ID: 1, Name: "jane"}}, nil
+	})
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	// when
+	_, countErr := driver.Count(ctx)
+	_, createErr := driver.CRUD().Create(ctx, models.InternalValue{"name": "jim"})
+	destroyErr := driver.CRUD().Destroy(ctx, 1)
+
+	// then
+	assert.ErrorIs(t, countErr, common.ErrCountUnsupported)
+	assert.ErrorIs(t, createErr, common.ErrOperationNotAllowed)
+	assert.ErrorIs(t, destroyErr, common.ErrOperationNotAllowed)
+}
+
+func TestDriverAtomic(t *testing.T) {
+	// given
+	type txKey struct{}
+	transactions := 0
+	var usedTx bool
+	driver := New[user](func(ctx context.Context, _ Query) ([]user, error) {
+		usedTx = ctx.Value(txKey{}) != nil
+		return nil, nil
+	}).WithTx(func(ctx context.Context, fn func(context.Context) error) error {
+		transactions++
+		return fn(context.WithValue(ctx, txKey{}, true))
+	})
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", "/", nil)
+
+	// when
+	atomicErr := driver.Atomic(ctx, func() error {
+		return driver.Atomic(ctx, func() error {
+			_, listErr := driver.CRUD().List(ctx)
+			return listErr
+		})
+	})
+
+	// then
+	assert.NoError(t, atomicErr)
+	assert.Equal(t, 1, transactions)
+	assert.True(t, usedTx)
+	assert.Nil(t, ctx.Request.Context().Value(txKey{}))
+}
+
+type generatedIDUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestDriverCreateAssignsGeneratedIDs(t *testing.T) {
+	// given
+	ids.Register[generatedIDUser](ids.Prefixed("usr", ids.ULID()))
+	var createdFields []string
+	driver := New[generatedIDUser](func(context.Context, Query) ([]generatedIDUser, error) {
+		return nil, nil
+	}).WithCreate(func(_ context.Context, u generatedIDUser, fields []string) (generatedIDUser, error) {
+		createdFields = fields
+		return u, nil
+	})
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	// when
+	created, createErr := driver.CRUD().Create(ctx, models.InternalValue{"name": "jane"})
+	_, invalidErr := driver.CRUD().Create(ctx, models.InternalValue{"id": "1", "name": "jane"})
+
+	// then
+	assert.NoError(t, createErr)
+	assert.Regexp(t, `^usr_[0-9A-Z]{26}$`, created["id"])
+	assert.ElementsMatch(t, []string{"id", "name"}, createdFields)
+	assert.ErrorIs(t, invalidErr, ids.ErrInvalidID)
+}
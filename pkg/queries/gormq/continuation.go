@@ -0,0 +1,196 @@
+package gormq
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/serializers"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// ContinuationQueryParam is the query parameter used to pass the continuation token
+	ContinuationQueryParam = "continuation"
+
+	defaultContinuationBatchSize = 100
+)
+
+var errContinuationBudgetExceeded = errors.New("continuation budget exceeded")
+
+// ContinuationPagination scans the table in primary key order, in batches, until the time budget
+// or the request context deadline is reached. If the scan was cut short, the rows scanned so far
+// are returned together with a continuation token, which the client passes in the `continuation`
+// query parameter to resume where the scan stopped, instead of the whole request failing:
+//
+//	{"results": [...], "continuation": "eyJhZnRlciI6NDJ9"}
+//
+// When the scan completes, `continuation` is null. Meant for filters too slow to list the whole
+// table within a single request, as the rows are always ordered by the primary key, WithOrderBy
+// should not be used together with this pagination.
+type ContinuationPagination struct {
+	// Budget is the maximum time spent scanning in a single request. When zero, only the request
+	// context deadline is respected.
+	Budget time.Duration
+	// BatchSize is the number of rows fetched in a single query, 100 by default. The budget is
+	// checked between batches, a batch still running when the budget is exceeded is cancelled.
+	BatchSize int
+}
+
+func (p *ContinuationPagination) Apply(c *gin.Context, db *gorm.DB) *gorm.DB {
+	scan := &continuationScan{batchSize: p.BatchSize}
+	if scan.batchSize <= 0 {
+		scan.batchSize = defaultContinuationBatchSize
+	}
+	ctxSetContinuationScan(c, scan)
+	parent := context.Background()
+	if c.Request != nil {
+		parent = c.Request.Context()
+	}
+	if p.Budget > 0 {
+		scan.ctx, scan.cancel = context.WithTimeout(parent, p.Budget)
+	} else {
+		scan.ctx, scan.cancel = context.WithCancel(parent)
+	}
+	if token := c.Query(ContinuationQueryParam); token != "" {
+		after, decodeErr := decodeContinuationToken(token)
+		if decodeErr != nil {
+			scan.err = &serializers.ValidationError{FieldErrors: map[string][]string{
+				ContinuationQueryParam: {"invalid continuation token"},
+			}}
+			return db
+		}
+		if after != nil {
+			db = db.Clauses(clause.Gt{
+				Column: clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey}, Value: after,
+			})
+		}
+	}
+	return db.WithContext(scan.ctx)
+}
+
+func (p *ContinuationPagination) Format(c *gin.Context, entities []any) (any, error) {
+	var token any
+	if scan, ok := ctxContinuationScan(c); ok && scan.partial {
+		encoded, encodeErr := encodeContinuationToken(scan.last)
+		if encodeErr != nil {
+			return nil, encodeErr
+		}
+		token = encoded
+	}
+	return map[string]any{
+		"results":      entities,
+		"continuation": token,
+	}, nil
+}
+
+// continuationScan holds the state of a single ContinuationPagination scan
+type continuationScan struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	batchSize int
+	err       error
+
+	// partial is set when the scan was stopped before reaching the end of the table
+	partial bool
+	// last is the primary key of the last scanned row
+	last any
+}
+
+func ctxSetContinuationScan(ctx *gin.Context, scan *continuationScan) {
+	ctx.Set("db:gorm:continuation", scan)
+}
+
+func ctxContinuationScan(ctx *gin.Context) (*continuationScan, bool) {
+	anyVal, ok := ctx.Get("db:gorm:continuation")
+	if !ok {
+		return nil, false
+	}
+	scan, ok := anyVal.(*continuationScan)
+	return scan, ok
+}
+
+// listInBatches is used instead of a single Find query, when the ContinuationPagination is used
+func listInBatches[Model any](
+	ctx *gin.Context, scan *continuationScan, preloadedQueriesMap map[string]bool,
+) ([]models.InternalValue, error) {
+	defer scan.cancel()
+	if scan.err != nil {
+		return nil, scan.err
+	}
+	var empty Model
+	rawEntities := []models.InternalValue{}
+	batch := []Model{}
+	findErr := withPredicates(ctx, withParentScope(ctx, CtxQuery(ctx))).Model(&empty).FindInBatches(&batch, scan.batchSize, func(_ *gorm.DB, _ int) error {
+		for _, entity := range batch {
+			internalValue := asInternalValueWithPreloads(entity, preloadedQueriesMap)
+			rawEntities = append(rawEntities, internalValue)
+			scan.last = internalValue["id"]
+		}
+		if scan.ctx.Err() != nil {
+			return errContinuationBudgetExceeded
+		}
+		return nil
+	}).Error
+	if errors.Is(findErr, errContinuationBudgetExceeded) || errors.Is(findErr, context.DeadlineExceeded) {
+		scan.partial = true
+		if scan.last == nil {
+			// Nothing was scanned, the client can retry with the same token
+			scan.last = ctxContinuationAfter(ctx)
+		}
+		return rawEntities, nil
+	}
+	if findErr != nil {
+		return nil, findErr
+	}
+	return rawEntities, nil
+}
+
+// ctxContinuationAfter returns the primary key from the token passed in the request, nil meaning
+// that the scan starts from the beginning of the table
+func ctxContinuationAfter(ctx *gin.Context) any {
+	after, decodeErr := decodeContinuationToken(ctx.Query(ContinuationQueryParam))
+	if decodeErr != nil {
+		return nil
+	}
+	return after
+}
+
+type continuationToken struct {
+	After any `json:"after"`
+}
+
+func encodeContinuationToken(after any) (string, error) {
+	encoded, marshalErr := json.Marshal(continuationToken{After: after})
+	if marshalErr != nil {
+		return "", marshalErr
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+func decodeContinuationToken(token string) (any, error) {
+	decoded, decodeErr := base64.RawURLEncoding.DecodeString(token)
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	var t continuationToken
+	decoder := json.NewDecoder(bytes.NewReader(decoded))
+	decoder.UseNumber()
+	if unmarshalErr := decoder.Decode(&t); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	// Integer primary keys would lose precision as float64
+	if number, isNumber := t.After.(json.Number); isNumber {
+		if asInt, intErr := number.Int64(); intErr == nil {
+			return asInt, nil
+		}
+		return number.Float64()
+	}
+	return t.After, nil
+}
@@ -0,0 +1,111 @@
+package gormq
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func prepareContinuationCtx(
+	t *testing.T, db *gorm.DB, pagination *ContinuationPagination, url string,
+) (*gin.Context, *GormQueryDriver[MockModel]) {
+	ctx, queryDriver := prepareCtx[MockModel](t, db)
+	ctx.Request = httptest.NewRequest("GET", url, nil)
+	queryDriver.WithPagination(pagination).Pagination().Apply(ctx)
+	return ctx, queryDriver
+}
+
+func createMockModels(t *testing.T, db *gorm.DB, count int) {
+	assert.NoError(t, db.AutoMigrate(&MockModel{}))
+	for i := 0; i < count; i++ {
+		assert.NoError(t, db.Create(&MockModel{Foo: "bar"}).Error)
+	}
+}
+
+func listIDs(t *testing.T, ctx *gin.Context, queryDriver *GormQueryDriver[MockModel]) []any {
+	list, listErr := queryDriver.CRUD().List(ctx)
+	assert.NoError(t, listErr)
+	ids := []any{}
+	for _, elem := range list {
+		ids = append(ids, elem["id"])
+	}
+	return ids
+}
+
+func TestContinuationPaginationCompleteScan(t *testing.T) {
+	// given
+	db := prepareGorm(t)
+	createMockModels(t, db, 5)
+	ctx, queryDriver := prepareContinuationCtx(t, db, &ContinuationPagination{BatchSize: 2}, "/mocks")
+
+	// when
+	ids := listIDs(t, ctx, queryDriver)
+	formatted, formatErr := queryDriver.Pagination().Format(ctx, []any{})
+
+	// then
+	assert.NoError(t, formatErr)
+	assert.Equal(t, []any{uint(1), uint(2), uint(3), uint(4), uint(5)}, ids)
+	assert.Nil(t, formatted.(map[string]any)["continuation"])
+}
+
+func TestContinuationPaginationBudgetExceededReturnsPartialPage(t *testing.T) {
+	// given
+	db := prepareGorm(t)
+	createMockModels(t, db, 6)
+	slowDB := db.Session(&gorm.Session{})
+	assert.NoError(t, slowDB.Callback().Query().After("gorm:query").Register("test:slow", func(*gorm.DB) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	ctx, queryDriver := prepareContinuationCtx(
+		t, slowDB, &ContinuationPagination{BatchSize: 2, Budget: 75 * time.Millisecond}, "/mocks",
+	)
+
+	// when
+	ids := listIDs(t, ctx, queryDriver)
+	formatted, formatErr := queryDriver.Pagination().Format(ctx, []any{})
+	token := formatted.(map[string]any)["continuation"]
+	assert.NoError(t, slowDB.Callback().Query().Remove("test:slow"))
+	resumedCtx, resumedQueryDriver := prepareContinuationCtx(
+		t, db, &ContinuationPagination{BatchSize: 2}, "/mocks?continuation="+token.(string),
+	)
+	resumedIDs := listIDs(t, resumedCtx, resumedQueryDriver)
+
+	// then
+	assert.NoError(t, formatErr)
+	assert.Equal(t, []any{uint(1), uint(2), uint(3), uint(4)}, ids)
+	assert.Equal(t, []any{uint(5), uint(6)}, resumedIDs)
+}
+
+func TestContinuationPaginationInvalidToken(t *testing.T) {
+	// given
+	db := prepareGorm(t)
+	createMockModels(t, db, 1)
+	ctx, queryDriver := prepareContinuationCtx(
+		t, db, &ContinuationPagination{}, "/mocks?continuation=not-a-token",
+	)
+
+	// when
+	_, listErr := queryDriver.CRUD().List(ctx)
+
+	// then
+	assert.IsType(t, &serializers.ValidationError{}, listErr)
+}
+
+func TestContinuationTokenKeepsIntegerPrecision(t *testing.T) {
+	// given
+	var id int64 = 1<<62 + 1
+
+	// when
+	token, encodeErr := encodeContinuationToken(id)
+	decoded, decodeErr := decodeContinuationToken(token)
+
+	// then
+	assert.NoError(t, encodeErr)
+	assert.NoError(t, decodeErr)
+	assert.Equal(t, id, decoded)
+}
@@ -0,0 +1,65 @@
+package gormq
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/queries/crud"
+	"gorm.io/gorm"
+)
+
+// ErrorTranslator translates the vendor specific errors of the database, see common.ErrorTranslator
+type ErrorTranslator = common.ErrorTranslator
+
+// TranslateError is the default ErrorTranslator, see common.TranslateError, the records not found
+// by GORM are returned unchanged
+func TranslateError(err error, table string, columns []string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return common.TranslateError(err, table, columns)
+}
+
+// WithErrorTranslator replaces the ErrorTranslator of the driver, TranslateError by default
+func (g *GormQueryDriver[Model]) WithErrorTranslator(translator ErrorTranslator) *GormQueryDriver[Model] {
+	g.errorTranslator = translator
+	return g
+}
+
+// withErrorTranslation translates the errors of the queries using the translator
+func withErrorTranslation[Model any](queries *crud.CRUD[Model], translator ErrorTranslator) *crud.CRUD[Model] {
+	if translator == nil {
+		return queries
+	}
+	translate := func(ctx *gin.Context, err error) error {
+		if err == nil {
+			return nil
+		}
+		var empty Model
+		stmt := &gorm.Statement{DB: CtxQuery(ctx)}
+		if parseErr := stmt.Parse(&empty); parseErr != nil {
+			return err
+		}
+		return translator(err, stmt.Schema.Table, stmt.Schema.DBNames)
+	}
+	list, retrieve, create, update, destroy := queries.List, queries.Retrieve, queries.Create, queries.Update, queries.Destroy
+	return queries.WithList(func(ctx *gin.Context) ([]models.InternalValue, error) {
+		listed, err := list(ctx)
+		return listed, translate(ctx, err)
+	}).WithRetrieve(func(ctx *gin.Context, id any) (models.InternalValue, error) {
+		retrieved, err := retrieve(ctx, id)
+		return retrieved, translate(ctx, err)
+	}).WithCreate(func(ctx *gin.Context, m models.InternalValue) (models.InternalValue, error) {
+		created, err := create(ctx, m)
+		return created, translate(ctx, err)
+	}).WithUpdate(func(
+		ctx *gin.Context, old models.InternalValue, new models.InternalValue, id any,
+	) (models.InternalValue, error) {
+		updated, err := update(ctx, old, new, id)
+		return updated, translate(ctx, err)
+	}).WithDestroy(func(ctx *gin.Context, id any) error {
+		return translate(ctx, destroy(ctx, id))
+	})
+}
@@ -0,0 +1,142 @@
+package gormq
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type sqlStateError struct {
+	state   string
+	message string
+}
+
+func (e *sqlStateError) Error() string {
+	return e.message
+}
+
+func (e *sqlStateError) SQLState() string {
+	return e.state
+}
+
+func TestTranslateError(t *testing.T) {
+	columns := []string{"id", "sku", "price", "category_id"}
+	tests := []struct {
+		name               string
+		err                error
+		expectedKind       error
+		expectedConstraint string
+		expectedFields     []string
+	}{
+		{
+			name:               "postgres unique",
+			err:                errors.New(`ERROR: duplicate key value violates unique constraint "products_sku_key" (SQLSTATE 23505)`),
+			expectedKind:       common.ErrUniqueViolation,
+			expectedConstraint: "products_sku_key",
+			expectedFields:     []string{"sku"},
+		},
+		{
+			name:               "postgres foreign key with sql state",
+			err:                &sqlStateError{state: "23503", message: `violates foreign key constraint "fk_products_category"`},
+			expectedKind:       common.ErrForeignKeyViolation,
+			expectedConstraint: "fk_products_category",
+			expectedFields:     []string{"category_id"},
+		},
+		{
+			name:               "postgres check",
+			err:                errors.New(`ERROR: new row for relation "products" violates check constraint "chk_products_price" (SQLSTATE 23514)`),
+			expectedKind:       common.ErrCheckViolation,
+			expectedConstraint: "chk_products_price",
+			expectedFields:     []string{"price"},
+		},
+		{
+			name:         "postgres serialization failure",
+			err:          errors.New(`ERROR: could not serialize access due to concurrent update (SQLSTATE 40001)`),
+			expectedKind: common.ErrSerializationFailure,
+		},
+		{
+			name:               "mysql unique",
+			err:                errors.New(`Error 1062 (23000): Duplicate entry 'A-1' for key 'products.idx_products_sku'`),
+			expectedKind:       common.ErrUniqueViolation,
+			expectedConstraint: "idx_products_sku",
+			expectedFields:     []string{"sku"},
+		},
+		{
+			name:               "mysql foreign key",
+			err:                errors.New("Error 1452 (23000): Cannot add or update a child row: a foreign key constraint fails (`shop`.`products`, CONSTRAINT `fk_products_category` FOREIGN KEY (`category_id`) REFERENCES `categories` (`id`))"),
+			expectedKind:       common.ErrForeignKeyViolation,
+			expectedConstraint: "fk_products_category",
+			expectedFields:     []string{"category_id"},
+		},
+		{
+			name:               "mysql check",
+			err:                errors.New(`Error 3819 (HY000): Check constraint 'chk_products_price' is violated.`),
+			expectedKind:       common.ErrCheckViolation,
+			expectedConstraint: "chk_products_price",
+			expectedFields:     []string{"price"},
+		},
+		{
+			name:           "sqlite unique",
+			err:            errors.New(`UNIQUE constraint failed: products.sku, products.price`),
+			expectedKind:   common.ErrUniqueViolation,
+			expectedFields: []string{"sku", "price"},
+		},
+		{
+			name:         "sqlite foreign key",
+			err:          fmt.Errorf("could not delete entity: %w", errors.New("FOREIGN KEY constraint failed")),
+			expectedKind: common.ErrForeignKeyViolation,
+		},
+		{
+			name:               "sqlite check",
+			err:                errors.New(`CHECK constraint failed: chk_products_price`),
+			expectedKind:       common.ErrCheckViolation,
+			expectedConstraint: "chk_products_price",
+			expectedFields:     []string{"price"},
+		},
+		{
+			name: "other",
+			err:  errors.New("connection refused"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			translated := TranslateError(tt.err, "products", columns)
+
+			// then
+			assert.ErrorIs(t, translated, tt.err)
+			var databaseErr *common.DatabaseError
+			if tt.expectedKind == nil {
+				assert.False(t, errors.As(translated, &databaseErr))
+				return
+			}
+			assert.ErrorIs(t, translated, tt.expectedKind)
+			assert.True(t, errors.As(translated, &databaseErr))
+			assert.Equal(t, tt.expectedConstraint, databaseErr.Constraint)
+			assert.Equal(t, tt.expectedFields, databaseErr.Fields)
+		})
+	}
+}
+
+type checkedModel struct {
+	ID    uint `gorm:"primaryKey" json:"id"`
+	Price int  `gorm:"check:chk_checked_models_price,price > 0" json:"price"`
+}
+
+func TestGormDBTranslatesErrors(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareCtx[checkedModel](t)
+
+	// when
+	_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"price": -1})
+
+	// then
+	assert.ErrorIs(t, createErr, common.ErrCheckViolation)
+	var databaseErr *common.DatabaseError
+	assert.True(t, errors.As(createErr, &databaseErr))
+	assert.Equal(t, []string{"price"}, databaseErr.Fields)
+}
@@ -2,15 +2,21 @@ package gormq
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
 	"reflect"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/glothriel/grf/pkg/detectors"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/ids"
 	"github.com/glothriel/grf/pkg/models"
 	"github.com/glothriel/grf/pkg/queries/common"
 	"github.com/glothriel/grf/pkg/queries/crud"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type GormFilterFunc func(ctx *gin.Context, db *gorm.DB) *gorm.DB
@@ -42,12 +48,14 @@ type GormQueryDriver[Model any] struct {
 	preloadedQueries []string
 	order            *gormQueryMod[Model]
 	pagination       *gormPagination[Model]
+	indexes          []*Index
+	errorTranslator  ErrorTranslator
 
 	middleware []gin.HandlerFunc
 }
 
 func (g GormQueryDriver[Model]) CRUD() *crud.CRUD[Model] {
-	return GormQueries[Model](g.preloadedQueries)
+	return withUniqueIndexes(withErrorTranslation(GormQueries[Model](g.preloadedQueries), g.errorTranslator), g.indexes)
 }
 
 func (g GormQueryDriver[Model]) Filter() common.QueryMod {
@@ -59,13 +67,98 @@ func (g GormQueryDriver[Model]) Order() common.QueryMod {
 }
 
 func (g GormQueryDriver[Model]) Pagination() common.Pagination {
+	if _, none := g.pagination.child.(*NoPagination); none {
+		return common.NoPagination{}
+	}
 	return g.pagination
 }
 
+// Count implements queries.Counter, the objects are counted ignoring the window of the request
+func (g GormQueryDriver[Model]) Count(ctx *gin.Context) (int, error) {
+	var empty Model
+	var count int64
+	// The session keeps the query of the context intact for listing the objects afterwards
+	query := withPredicates(ctx, withParentScope(ctx, CtxQuery(ctx).Session(&gorm.Session{}))).Model(&empty)
+	// Preloads aren't needed to count the objects
+	query.Statement.Preloads = nil
+	countErr := query.Count(&count).Error
+	return int(count), countErr
+}
+
+// Stats implements queries.Aggregator, computing the count, minimum, maximum and average in a single
+// query. Each percentile is computed by fetching the two closest values in the column order, so
+// the column should be indexed.
+func (g GormQueryDriver[Model]) Stats(ctx *gin.Context, field string, percentiles []float64) (common.Stats, error) {
+	var empty Model
+	column := clause.Column{Name: field}
+	scoped := func() *gorm.DB {
+		query := withPredicates(ctx, withParentScope(ctx, CtxQuery(ctx).Session(&gorm.Session{}))).Model(&empty)
+		query.Statement.Preloads = nil
+		// Aggregates can't be combined with the ordering and pagination of the driver
+		delete(query.Statement.Clauses, "ORDER BY")
+		delete(query.Statement.Clauses, "LIMIT")
+		return query
+	}
+	var row struct {
+		Count int
+		Min   *float64
+		Max   *float64
+		Avg   *float64
+	}
+	if aggregateErr := scoped().Select(
+		"COUNT(?) AS count, MIN(?) AS min, MAX(?) AS max, AVG(?) AS avg", column, column, column, column,
+	).Scan(&row).Error; aggregateErr != nil {
+		return common.Stats{}, aggregateErr
+	}
+	stats := common.Stats{Count: row.Count, Min: row.Min, Max: row.Max, Avg: row.Avg, Percentiles: map[string]float64{}}
+	if row.Count == 0 {
+		return stats, nil
+	}
+	for _, percentile := range percentiles {
+		position := common.PercentilePosition(percentile, row.Count)
+		var values []float64
+		if pluckErr := scoped().Where(clause.Neq{Column: column, Value: nil}).Order(
+			clause.OrderByColumn{Column: column, Reorder: true},
+		).Offset(int(math.Floor(position))).Limit(2).Pluck(field, &values).Error; pluckErr != nil {
+			return common.Stats{}, pluckErr
+		}
+		if len(values) == 0 {
+			continue
+		}
+		stats.Percentiles[common.PercentileKey(percentile)] = common.Interpolate(
+			values[0], values[len(values)-1], position,
+		)
+	}
+	return stats, nil
+}
+
+// Exists implements queries.Exister using an EXISTS query, the fields are used as column names
+func (g GormQueryDriver[Model]) Exists(ctx *gin.Context, conditions map[string]any) (bool, error) {
+	var empty Model
+	query := withPredicates(ctx, withParentScope(ctx, CtxQuery(ctx).Session(&gorm.Session{}))).Model(&empty).Select("1")
+	query.Statement.Preloads = nil
+	for field, value := range conditions {
+		query = query.Where(clause.Eq{Column: clause.Column{Name: field}, Value: value})
+	}
+	var exists bool
+	existsErr := CtxQuery(ctx).Session(&gorm.Session{NewDB: true}).Raw("SELECT EXISTS (?)", query).Scan(&exists).Error
+	return exists, existsErr
+}
+
 func (g GormQueryDriver[Model]) Middleware() []gin.HandlerFunc {
 	return g.middleware
 }
 
+// Atomic implements queries.Transactional, the queries run with the ctx inside fn use the transaction
+func (g GormQueryDriver[Model]) Atomic(ctx *gin.Context, fn func() error) error {
+	previousQuery := CtxQuery(ctx)
+	defer CtxSetQuery(ctx, previousQuery)
+	return previousQuery.Transaction(func(tx *gorm.DB) error {
+		CtxSetQuery(ctx, tx)
+		return fn()
+	})
+}
+
 func (g *GormQueryDriver[Model]) WithFilter(filterFunc GormFilterFunc) *GormQueryDriver[Model] {
 	g.filter.modFunc = filterFunc
 	return g
@@ -119,6 +212,7 @@ func Gorm[Model any](factory GormORMFactory) *GormQueryDriver[Model] {
 		pagination: &gormPagination[Model]{
 			child: &NoPagination{},
 		},
+		errorTranslator: TranslateError,
 		middleware: []gin.HandlerFunc{
 			func(ctx *gin.Context) {
 				CtxSetFactory(ctx, factory)
@@ -131,7 +225,6 @@ func Gorm[Model any](factory GormORMFactory) *GormQueryDriver[Model] {
 
 // GormQueries returns default queries providing basic CRUD functionality
 func GormQueries[Model any](preloadedQueries []string) *crud.CRUD[Model] {
-	ConvertFromDBToInternalValue := FromDBConverter[Model]()
 	var empty Model
 	var preloadedQueriesMap = make(map[string]bool)
 	for _, query := range preloadedQueries {
@@ -139,49 +232,40 @@ func GormQueries[Model any](preloadedQueries []string) *crud.CRUD[Model] {
 	}
 	return &crud.CRUD[Model]{
 		List: func(ctx *gin.Context) ([]models.InternalValue, error) {
+			if scan, ok := ctxContinuationScan(ctx); ok {
+				return listInBatches[Model](ctx, scan, preloadedQueriesMap)
+			}
 			rawEntities := []models.InternalValue{}
 			typedEntities := []Model{}
-			findErr := CtxQuery(ctx).Model(&empty).Find(&typedEntities).Error
+			var findErr error
+			if window, ok := grfctx.CurrentWindow(ctx); ok && window.Random {
+				typedEntities, findErr = findRandom[Model](ctx, window.Limit)
+			} else {
+				findErr = withWindow[Model](ctx, withOrdering(ctx, withPredicates(ctx, withParentScope(ctx, CtxQuery(ctx))))).Model(&empty).Find(&typedEntities).Error
+			}
 			if findErr != nil {
 				return nil, findErr
 			}
 			for _, entity := range typedEntities {
-				iv := models.AsInternalValue(entity)
-				for k, v := range iv {
-					if _, ok := preloadedQueriesMap[k]; ok {
-						vValue := reflect.ValueOf(v)
-
-						if vValue.Kind() == reflect.Slice {
-							newSlice := make([]any, vValue.Len())
-
-							for i := 0; i < vValue.Len(); i++ {
-								newSlice[i] = models.AsInternalValue(vValue.Index(i).Interface())
-							}
-
-							iv[k] = newSlice
-						} else {
-							iv[k] = models.AsInternalValue(v)
-						}
-					} else {
-						iv[k] = v
-					}
-				}
-				rawEntities = append(rawEntities, iv)
+				rawEntities = append(rawEntities, asInternalValueWithPreloads(entity, preloadedQueriesMap))
 			}
 			return rawEntities, findErr
 		},
 		Retrieve: func(ctx *gin.Context, id any) (models.InternalValue, error) {
-			var rawEntity map[string]any
-			retrieveErr := CtxQuery(ctx).Model(&empty).First(&rawEntity, "id = ?", id).Error
+			var entity Model
+			retrieveErr := withParentScope(ctx, CtxQuery(ctx)).Model(&empty).First(&entity, lookupCondition(ctx, id)).Error
 			if retrieveErr != nil {
 				if retrieveErr == gorm.ErrRecordNotFound {
 					return nil, common.ErrorNotFound
 				}
 				return nil, retrieveErr
 			}
-			return ConvertFromDBToInternalValue(rawEntity)
+			return asInternalValueWithPreloads(entity, preloadedQueriesMap), nil
 		},
 		Create: func(ctx *gin.Context, m models.InternalValue) (models.InternalValue, error) {
+			if _, generateErr := ids.Assign[Model](m); generateErr != nil {
+				return nil, generateErr
+			}
 			entity, asModelErr := models.AsModel[Model](m)
 			if asModelErr != nil {
 				return nil, asModelErr
@@ -196,16 +280,28 @@ func GormQueries[Model any](preloadedQueries []string) *crud.CRUD[Model] {
 			if asModelErr != nil {
 				return nil, asModelErr
 			}
-			updateErr := CtxQuery(ctx).Model(&entity).Updates(&entity).Error
-			if updateErr != nil {
-				return nil, updateErr
+			query := CtxQuery(ctx).Model(&entity)
+			precondition, conditional := grfctx.UpdatePrecondition(ctx)
+			if conditional {
+				query = query.Where(predicateExpression(precondition))
+			}
+			// Updates of structs skip the zero values, unless their columns are selected
+			if fields, explicit := grfctx.UpdatedFields(ctx); explicit {
+				query = query.Select(fields)
+			}
+			updateResult := query.Updates(&entity)
+			if updateResult.Error != nil {
+				return nil, updateResult.Error
+			}
+			if conditional && updateResult.RowsAffected == 0 {
+				return nil, common.ErrPreconditionFailed
 			}
 			return models.AsInternalValue(entity), nil
 		},
 		Destroy: func(ctx *gin.Context, id any) error {
 			var m Model
 			errWrapMsg := "could not delete entity"
-			queryResult := CtxQuery(ctx).Model(&empty).Delete(&m, "id = ?", id)
+			queryResult := withParentScope(ctx, CtxQuery(ctx)).Model(&empty).Delete(&m, lookupCondition(ctx, id))
 			if queryResult.Error != nil {
 				return fmt.Errorf(
 					"%s: query error: %w", errWrapMsg, queryResult.Error,
@@ -219,6 +315,31 @@ func GormQueries[Model any](preloadedQueries []string) *crud.CRUD[Model] {
 	}
 }
 
+// asInternalValueWithPreloads converts the entity to an InternalValue, also converting preloaded relations
+func asInternalValueWithPreloads[Model any](entity Model, preloadedQueriesMap map[string]bool) models.InternalValue {
+	iv := models.AsInternalValue(entity)
+	for k, v := range iv {
+		if _, ok := preloadedQueriesMap[k]; ok {
+			vValue := reflect.ValueOf(v)
+
+			if vValue.Kind() == reflect.Slice {
+				newSlice := make([]any, vValue.Len())
+
+				for i := 0; i < vValue.Len(); i++ {
+					newSlice[i] = models.AsInternalValue(vValue.Index(i).Interface())
+				}
+
+				iv[k] = newSlice
+			} else {
+				iv[k] = models.AsInternalValue(v)
+			}
+		} else {
+			iv[k] = v
+		}
+	}
+	return iv
+}
+
 // FromDBConverter internally uses *sql.Scanner to convert a map[string]any to an InternalValue
 // as GORM does this only for structs
 func FromDBConverter[Model any]() func(map[string]any) (models.InternalValue, error) {
@@ -235,3 +356,153 @@ func FromDBConverter[Model any]() func(map[string]any) (models.InternalValue, er
 		return intVal, nil
 	}
 }
+
+// lookupCondition matches the objects by the lookup field of the view, the field is used as the
+// column name
+func lookupCondition(ctx *gin.Context, id any) clause.Expression {
+	return clause.Eq{Column: clause.Column{Name: grfctx.LookupField(ctx)}, Value: id}
+}
+
+// withWindow limits the query to the window of a paginated request, if any. Keyset windows are
+// translated to a WHERE clause on the keyset column, replacing the ordering of the query, so the
+// pages don't require OFFSET scans. Random windows are listed by findRandom.
+func withWindow[Model any](ctx *gin.Context, db *gorm.DB) *gorm.DB {
+	window, ok := grfctx.CurrentWindow(ctx)
+	if !ok {
+		return db
+	}
+	if window.Keyset == nil {
+		return db.Offset(window.Offset).Limit(window.Limit)
+	}
+	column := clause.Column{Name: window.Keyset.Field}
+	if window.Keyset.After != nil {
+		after, convertErr := common.KeysetValue[Model](window.Keyset.Field, window.Keyset.After)
+		if convertErr != nil {
+			_ = db.AddError(convertErr)
+			return db
+		}
+		if window.Keyset.Descending {
+			db = db.Where(clause.Lt{Column: column, Value: after})
+		} else {
+			db = db.Where(clause.Gt{Column: column, Value: after})
+		}
+	}
+	return db.Order(clause.OrderByColumn{Column: column, Desc: window.Keyset.Descending, Reorder: true}).Limit(window.Limit)
+}
+
+// withOrdering orders the query by the ordering of the request, if any, replacing the ordering set
+// before, the fields are used as column names
+func withOrdering(ctx *gin.Context, db *gorm.DB) *gorm.DB {
+	for i, orderBy := range grfctx.Ordering(ctx) {
+		db = db.Order(clause.OrderByColumn{
+			Column: clause.Column{Name: orderBy.Field}, Desc: orderBy.Descending, Reorder: i == 0,
+		})
+	}
+	return db
+}
+
+// findRandom picks up to limit objects matching the predicates of the request at random. The rows
+// are counted first and fetched at random offsets in the primary key order, so the database
+// doesn't sort all the rows by random values. Each object is fetched by a separate query, which
+// suits the small samples, like the single objects of views.WithRandom.
+func findRandom[Model any](ctx *gin.Context, limit int) ([]Model, error) {
+	var empty Model
+	scoped := func() *gorm.DB {
+		return withPredicates(ctx, withParentScope(ctx, CtxQuery(ctx).Session(&gorm.Session{}))).Model(&empty)
+	}
+	var count int64
+	counted := scoped()
+	counted.Statement.Preloads = nil
+	if countErr := counted.Count(&count).Error; countErr != nil {
+		return nil, countErr
+	}
+	picked := []Model{}
+	offsets := map[int64]bool{}
+	for int64(len(offsets)) < min(int64(limit), count) {
+		offset := rand.Int63n(count)
+		if offsets[offset] {
+			continue
+		}
+		offsets[offset] = true
+		var batch []Model
+		findErr := scoped().Order(clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey}, Reorder: true,
+		}).Offset(int(offset)).Limit(1).Find(&batch).Error
+		if findErr != nil {
+			return nil, findErr
+		}
+		picked = append(picked, batch...)
+	}
+	return picked, nil
+}
+
+// withPredicates limits the query to the objects matching the predicates and the search of the
+// request, the fields are used as column names
+func withPredicates(ctx *gin.Context, db *gorm.DB) *gorm.DB {
+	for _, predicate := range grfctx.Predicates(ctx) {
+		db = db.Where(predicateExpression(predicate))
+	}
+	if search, ok := grfctx.CurrentSearch(ctx); ok {
+		for _, term := range search.Terms {
+			matches := make([]clause.Expression, 0, len(search.Fields))
+			for _, field := range search.Fields {
+				matches = append(matches, like(clause.Column{Name: field}, "%"+escapeLike(term)+"%", true))
+			}
+			db = db.Where(clause.Or(matches...))
+		}
+	}
+	return db
+}
+
+func predicateExpression(predicate grfctx.Predicate) clause.Expression {
+	column := clause.Column{Name: predicate.Field}
+	switch predicate.Operator {
+	case grfctx.OperatorGt:
+		return clause.Gt{Column: column, Value: predicate.Value}
+	case grfctx.OperatorGte:
+		return clause.Gte{Column: column, Value: predicate.Value}
+	case grfctx.OperatorLt:
+		return clause.Lt{Column: column, Value: predicate.Value}
+	case grfctx.OperatorLte:
+		return clause.Lte{Column: column, Value: predicate.Value}
+	case grfctx.OperatorContains:
+		return like(column, "%"+escapeLike(predicate.Value)+"%", false)
+	case grfctx.OperatorIContains:
+		return like(column, "%"+escapeLike(predicate.Value)+"%", true)
+	case grfctx.OperatorStartsWith:
+		return like(column, escapeLike(predicate.Value)+"%", false)
+	case grfctx.OperatorIn:
+		values, _ := predicate.Value.([]any)
+		return clause.IN{Column: column, Values: values}
+	case grfctx.OperatorIsNull:
+		if predicate.Value == true {
+			return clause.Eq{Column: column, Value: nil}
+		}
+		return clause.Neq{Column: column, Value: nil}
+	}
+	return clause.Eq{Column: column, Value: predicate.Value}
+}
+
+// like matches the column against the pattern, using `!` as the escape character, as backslashes
+// are handled differently by the databases
+func like(column clause.Column, pattern string, caseInsensitive bool) clause.Expression {
+	if caseInsensitive {
+		return clause.Expr{SQL: "LOWER(?) LIKE LOWER(?) ESCAPE '!'", Vars: []any{column, pattern}}
+	}
+	return clause.Expr{SQL: "? LIKE ? ESCAPE '!'", Vars: []any{column, pattern}}
+}
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+func escapeLike(value any) string {
+	return likeEscaper.Replace(fmt.Sprintf("%v", value))
+}
+
+// withParentScope limits the query to the objects of the parent of a nested resource, if any
+func withParentScope(ctx *gin.Context, db *gorm.DB) *gorm.DB {
+	scope, ok := grfctx.Parent(ctx)
+	if !ok {
+		return db
+	}
+	return db.Where(clause.Eq{Column: clause.Column{Name: scope.Field}, Value: scope.Value})
+}
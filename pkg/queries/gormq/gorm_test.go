@@ -1,12 +1,16 @@
 package gormq
 
 import (
+	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
 	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/pagination"
+	"github.com/glothriel/grf/pkg/queries/common"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -152,6 +156,252 @@ func TestGormDBUpdateQuery(t *testing.T) {
 	assert.Equal(t, models.InternalValue{"foo": "baz", "id": intVal["id"]}, item)
 }
 
+func TestGormDBConditionalUpdate(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareCtx[MockModel](t)
+	intVal, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"foo": "bar"})
+
+	// when
+	grfctx.SetUpdatePrecondition(ctx, &grfctx.Predicate{Field: "foo", Operator: grfctx.OperatorExact, Value: "stale"})
+	_, staleErr := queryDriver.CRUD().Update(ctx, intVal, models.InternalValue{"id": intVal["id"], "foo": "baz"}, intVal["id"])
+	grfctx.SetUpdatePrecondition(ctx, &grfctx.Predicate{Field: "foo", Operator: grfctx.OperatorExact, Value: "bar"})
+	item, updateErr := queryDriver.CRUD().Update(ctx, intVal, models.InternalValue{"id": intVal["id"], "foo": "baz"}, intVal["id"])
+
+	// then
+	assert.NoError(t, createErr)
+	assert.ErrorIs(t, staleErr, common.ErrPreconditionFailed)
+	assert.NoError(t, updateErr)
+	assert.Equal(t, models.InternalValue{"foo": "baz", "id": intVal["id"]}, item)
+}
+
+func TestGormDBRetrieveAndDestroyByLookupField(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareCtx[MockModel](t)
+	grfctx.Set(ctx, grfctx.Metadata{View: grfctx.ViewSettings{LookupField: "foo"}})
+
+	// when
+	_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"foo": "bar"})
+	item, retrieveErr := queryDriver.CRUD().Retrieve(ctx, "bar")
+	deleteErr := queryDriver.CRUD().Destroy(ctx, "bar")
+	_, afterDeleteErr := queryDriver.CRUD().Retrieve(ctx, "bar")
+
+	// then
+	assert.NoError(t, createErr)
+	assert.NoError(t, retrieveErr)
+	assert.Equal(t, models.InternalValue{"id": uint(1), "foo": "bar"}, item)
+	assert.NoError(t, deleteErr)
+	assert.ErrorIs(t, afterDeleteErr, common.ErrorNotFound)
+}
+
+func TestGormDBParentScope(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareCtx[MockModel](t)
+	for _, foo := range []string{"bar", "baz", "bar"} {
+		_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"foo": foo})
+		assert.NoError(t, createErr)
+	}
+	grfctx.SetParentScope(ctx, grfctx.ParentScope{Field: "foo", Value: "bar"})
+
+	// when
+	listed, listErr := queryDriver.CRUD().List(ctx)
+	_, retrieveErr := queryDriver.CRUD().Retrieve(ctx, uint(2))
+	deleteErr := queryDriver.CRUD().Destroy(ctx, uint(2))
+
+	// then
+	assert.NoError(t, listErr)
+	assert.Equal(t, []models.InternalValue{{"id": uint(1), "foo": "bar"}, {"id": uint(3), "foo": "bar"}}, listed)
+	assert.ErrorIs(t, retrieveErr, common.ErrorNotFound)
+	assert.ErrorIs(t, deleteErr, common.ErrorNotFound)
+}
+
+func TestGormDBCountAndWindow(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareCtx[MockModel](t)
+	for _, foo := range []string{"a", "b", "c", "d", "e"} {
+		_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"foo": foo})
+		assert.NoError(t, createErr)
+	}
+	queryDriver.WithOrderBy("foo DESC").Order().Apply(ctx)
+	grfctx.SetWindow(ctx, grfctx.Window{Offset: 1, Limit: 2})
+
+	// when
+	count, countErr := queryDriver.Count(ctx)
+	listed, listErr := queryDriver.CRUD().List(ctx)
+
+	// then
+	assert.NoError(t, countErr)
+	assert.NoError(t, listErr)
+	assert.Equal(t, 5, count)
+	assert.Equal(t, []models.InternalValue{{"id": uint(4), "foo": "d"}, {"id": uint(3), "foo": "c"}}, listed)
+}
+
+func TestGormDBKeysetWindow(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareCtx[MockModel](t)
+	for _, foo := range []string{"a", "b", "c", "d", "e"} {
+		_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"foo": foo})
+		assert.NoError(t, createErr)
+	}
+	queryDriver.WithOrderBy("foo ASC").Order().Apply(ctx)
+	grfctx.SetWindow(ctx, grfctx.Window{Limit: 2, Keyset: &grfctx.Keyset{Field: "id", Descending: true, After: 4}})
+
+	// when
+	listed, listErr := queryDriver.CRUD().List(ctx)
+
+	// then
+	assert.NoError(t, listErr)
+	assert.Equal(t, []models.InternalValue{{"id": uint(3), "foo": "c"}, {"id": uint(2), "foo": "b"}}, listed)
+}
+
+type timedModel struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func TestGormDBCursorPaginationByTime(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareCtx[timedModel](t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"created_at": start.Add(time.Duration(i) * time.Hour)})
+		assert.NoError(t, createErr)
+	}
+	paginator := pagination.NewCursorPagination("-created_at", 2)
+	fetch := func(path string) (pagination.CursorResponse, []any) {
+		ctx.Request = httptest.NewRequest(http.MethodGet, path, nil)
+		window, windowErr := paginator.Window(ctx)
+		assert.NoError(t, windowErr)
+		grfctx.SetWindow(ctx, window)
+		listed, listErr := queryDriver.CRUD().List(ctx)
+		assert.NoError(t, listErr)
+		results := make([]any, 0, len(listed))
+		for _, internalValue := range listed {
+			results = append(results, internalValue["id"])
+		}
+		formatted, formatErr := paginator.Format(ctx, pagination.Page{Results: results, InternalValues: listed, Window: window})
+		assert.NoError(t, formatErr)
+		page := formatted.(pagination.CursorResponse)
+		return page, page.Results
+	}
+
+	// when
+	first, firstIDs := fetch("/timed")
+	second, secondIDs := fetch(*first.Next)
+	third, thirdIDs := fetch(*second.Next)
+	_, backIDs := fetch(*third.Previous)
+
+	// then
+	assert.Equal(t, []any{uint(5), uint(4)}, firstIDs)
+	assert.Equal(t, []any{uint(3), uint(2)}, secondIDs)
+	assert.Equal(t, []any{uint(1)}, thirdIDs)
+	assert.Nil(t, third.Next)
+	assert.Equal(t, []any{uint(3), uint(2)}, backIDs)
+}
+
+func TestGormDBRandomWindow(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareCtx[MockModel](t)
+	for _, foo := range []string{"a", "b", "c"} {
+		_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"foo": foo})
+		assert.NoError(t, createErr)
+	}
+
+	// when
+	grfctx.SetWindow(ctx, grfctx.Window{Limit: 2, Random: true})
+	listed, listErr := queryDriver.CRUD().List(ctx)
+	grfctx.SetWindow(ctx, grfctx.Window{Limit: 5, Random: true})
+	all, allErr := queryDriver.CRUD().List(ctx)
+
+	// then
+	assert.NoError(t, listErr)
+	assert.Len(t, listed, 2)
+	assert.NotEqual(t, listed[0]["id"], listed[1]["id"])
+	assert.NoError(t, allErr)
+	assert.ElementsMatch(t, []any{"a", "b", "c"}, []any{all[0]["foo"], all[1]["foo"], all[2]["foo"]})
+}
+
+func TestGormDBExists(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareCtx[MockModel](t)
+	_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"foo": "bar"})
+	assert.NoError(t, createErr)
+
+	// when
+	existing, existingErr := queryDriver.Exists(ctx, map[string]any{"foo": "bar", "id": "1"})
+	missing, missingErr := queryDriver.Exists(ctx, map[string]any{"foo": "baz"})
+
+	// then
+	assert.NoError(t, existingErr)
+	assert.NoError(t, missingErr)
+	assert.True(t, existing)
+	assert.False(t, missing)
+}
+
+func TestGormDBPredicates(t *testing.T) {
+	tests := []struct {
+		name          string
+		predicates    []grfctx.Predicate
+		expectedFoos  []string
+		expectedCount int
+	}{
+		{name: "exact", predicates: []grfctx.Predicate{{Field: "foo", Operator: grfctx.OperatorExact, Value: "bar"}}, expectedFoos: []string{"bar"}},
+		{name: "gt", predicates: []grfctx.Predicate{{Field: "id", Operator: grfctx.OperatorGt, Value: 2}}, expectedFoos: []string{"50%_off"}},
+		{name: "lte", predicates: []grfctx.Predicate{{Field: "id", Operator: grfctx.OperatorLte, Value: 2}}, expectedFoos: []string{"bar", "Baz"}},
+		{name: "icontains", predicates: []grfctx.Predicate{{Field: "foo", Operator: grfctx.OperatorIContains, Value: "BA"}}, expectedFoos: []string{"bar", "Baz"}},
+		{name: "contains escaped", predicates: []grfctx.Predicate{{Field: "foo", Operator: grfctx.OperatorContains, Value: "%_"}}, expectedFoos: []string{"50%_off"}},
+		{name: "startswith", predicates: []grfctx.Predicate{{Field: "foo", Operator: grfctx.OperatorStartsWith, Value: "50%"}}, expectedFoos: []string{"50%_off"}},
+		{name: "in", predicates: []grfctx.Predicate{{Field: "foo", Operator: grfctx.OperatorIn, Value: []any{"bar", "Baz"}}}, expectedFoos: []string{"bar", "Baz"}},
+		{name: "isnull", predicates: []grfctx.Predicate{{Field: "foo", Operator: grfctx.OperatorIsNull, Value: false}}, expectedFoos: []string{"bar", "Baz", "50%_off"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx, queryDriver := prepareCtx[MockModel](t)
+			for _, foo := range []string{"bar", "Baz", "50%_off"} {
+				_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"foo": foo})
+				assert.NoError(t, createErr)
+			}
+			grfctx.AddPredicates(ctx, tt.predicates...)
+
+			// when
+			listed, listErr := queryDriver.CRUD().List(ctx)
+			count, countErr := queryDriver.Count(ctx)
+
+			// then
+			assert.NoError(t, listErr)
+			assert.NoError(t, countErr)
+			foos := []string{}
+			for _, item := range listed {
+				foos = append(foos, item["foo"].(string))
+			}
+			assert.Equal(t, tt.expectedFoos, foos)
+			assert.Equal(t, len(tt.expectedFoos), count)
+		})
+	}
+}
+
+func TestGormDBStats(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareCtx[MockModel](t)
+	for _, foo := range []string{"a", "b", "c", "d", "e"} {
+		_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"foo": foo})
+		assert.NoError(t, createErr)
+	}
+	queryDriver.WithOrderBy("foo DESC").Order().Apply(ctx)
+	grfctx.AddPredicates(ctx, grfctx.Predicate{Field: "id", Operator: grfctx.OperatorGt, Value: 1})
+
+	// when
+	stats, statsErr := queryDriver.Stats(ctx, "id", []float64{50, 90})
+
+	// then
+	assert.NoError(t, statsErr)
+	assert.Equal(t, 4, stats.Count)
+	assert.Equal(t, 2.0, *stats.Min)
+	assert.Equal(t, 5.0, *stats.Max)
+	assert.Equal(t, 3.5, *stats.Avg)
+	assert.InDeltaMapValues(t, map[string]float64{"50": 3.5, "90": 4.7}, stats.Percentiles, 1e-9)
+}
+
 func TestGormDBDestroyQuery(t *testing.T) {
 	// given
 	ctx, queryDriver := prepareCtx[MockModel](t)
@@ -189,6 +439,8 @@ func TestGormPagination(t *testing.T) {
 	// then
 	assert.NoError(t, formatErr)
 	assert.Equal(t, []any{1, 2, 3}, formatted)
+	assert.False(t, common.Paginates(queryDriver.Pagination()))
+	assert.True(t, common.Paginates(queryDriver.WithPagination(&ContinuationPagination{}).Pagination()))
 }
 
 func TestGormOrder(t *testing.T) {
@@ -244,3 +496,46 @@ func TestGormFilter(t *testing.T) {
 		{"id": uint(2), "foo": "alice"},
 	}, list)
 }
+
+func TestGormDBOrdering(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareCtx[MockModel](t)
+	for _, foo := range []string{"b", "a", "c", "a"} {
+		_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"foo": foo})
+		assert.NoError(t, createErr)
+	}
+	queryDriver.WithOrderBy("id ASC").Order().Apply(ctx)
+	grfctx.SetOrdering(ctx, []grfctx.OrderBy{{Field: "foo"}, {Field: "id", Descending: true}})
+
+	// when
+	listed, listErr := queryDriver.CRUD().List(ctx)
+
+	// then
+	assert.NoError(t, listErr)
+	ids := []any{}
+	for _, item := range listed {
+		ids = append(ids, item["id"])
+	}
+	assert.Equal(t, []any{uint(4), uint(2), uint(1), uint(3)}, ids)
+}
+
+func TestGormDBSearch(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareCtx[MockModel](t)
+	for _, foo := range []string{"green peas", "canned beans", "50%_off beans"} {
+		_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"foo": foo})
+		assert.NoError(t, createErr)
+	}
+	grfctx.SetSearch(ctx, grfctx.Search{Fields: []string{"foo"}, Terms: []string{"BEANS", "%_"}})
+
+	// when
+	listed, listErr := queryDriver.CRUD().List(ctx)
+	count, countErr := queryDriver.Count(ctx)
+
+	// then
+	assert.NoError(t, listErr)
+	assert.NoError(t, countErr)
+	assert.Len(t, listed, 1)
+	assert.Equal(t, "50%_off beans", listed[0]["foo"])
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,197 @@
+package gormq
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/crud"
+	"github.com/glothriel/grf/pkg/serializers"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Index declares an index of the model's table, created by GormQueryDriver.Migrate. The fields are
+// used as column names. Unique indexes are also checked before creating and updating the objects,
+// so the violations are responded with validation errors of the fields, instead of database errors.
+type Index struct {
+	Name   string
+	Fields []string
+	Unique bool
+	// Where makes the index partial, limiting it to the rows matching the SQL condition, for example
+	// `deleted_at IS NULL`. The objects can't be checked against the condition before saving them, so
+	// only the duplicate key errors of partial unique indexes are mapped to validation errors.
+	Where string
+}
+
+// NewIndex declares a non-unique index of the fields
+func NewIndex(fields ...string) *Index {
+	return &Index{Fields: fields}
+}
+
+// UniqueTogether declares a unique index of the fields, a single field makes it unique on its own
+func UniqueTogether(fields ...string) *Index {
+	return &Index{Fields: fields, Unique: true}
+}
+
+// WithName sets the name of the index, by default it's derived from the table and the fields, like
+// `idx_products_owner_id_name`
+func (i *Index) WithName(name string) *Index {
+	i.Name = name
+	return i
+}
+
+// WithWhere makes the index partial, see Index.Where. Partial indexes are supported by PostgreSQL,
+// SQLite and SQL Server.
+func (i *Index) WithWhere(condition string) *Index {
+	i.Where = condition
+	return i
+}
+
+func (i *Index) name(table string) string {
+	if i.Name != "" {
+		return i.Name
+	}
+	return "idx_" + table + "_" + strings.Join(i.Fields, "_")
+}
+
+// WithIndex declares the indexes of the model's table, see Index
+func (g *GormQueryDriver[Model]) WithIndex(indexes ...*Index) *GormQueryDriver[Model] {
+	g.indexes = append(g.indexes, indexes...)
+	return g
+}
+
+// Migrate migrates the model's table using AutoMigrate, and creates the declared indexes that
+// don't exist yet
+func (g GormQueryDriver[Model]) Migrate(db *gorm.DB) error {
+	var empty Model
+	if migrateErr := db.AutoMigrate(&empty); migrateErr != nil {
+		return migrateErr
+	}
+	table, tableErr := tableName[Model](db)
+	if tableErr != nil {
+		return tableErr
+	}
+	for _, index := range g.indexes {
+		name := index.name(table)
+		if db.Migrator().HasIndex(&empty, name) {
+			continue
+		}
+		columns := make([]string, 0, len(index.Fields))
+		for _, field := range index.Fields {
+			columns = append(columns, db.Statement.Quote(field))
+		}
+		sql := fmt.Sprintf(
+			"CREATE INDEX %s ON %s (%s)", db.Statement.Quote(name), db.Statement.Quote(table), strings.Join(columns, ", "),
+		)
+		if index.Unique {
+			sql = "CREATE UNIQUE" + strings.TrimPrefix(sql, "CREATE")
+		}
+		if index.Where != "" {
+			sql += " WHERE " + index.Where
+		}
+		if createErr := db.Exec(sql).Error; createErr != nil {
+			return fmt.Errorf("could not create index %s: %w", name, createErr)
+		}
+	}
+	return nil
+}
+
+func tableName[Model any](db *gorm.DB) (string, error) {
+	var empty Model
+	stmt := &gorm.Statement{DB: db}
+	if parseErr := stmt.Parse(&empty); parseErr != nil {
+		return "", parseErr
+	}
+	return stmt.Schema.Table, nil
+}
+
+// withUniqueIndexes checks the unique indexes before creating and updating the objects, and maps
+// the duplicate key errors of the database to the validation errors of the fields
+func withUniqueIndexes[Model any](queries *crud.CRUD[Model], indexes []*Index) *crud.CRUD[Model] {
+	unique := []*Index{}
+	for _, index := range indexes {
+		if index.Unique {
+			unique = append(unique, index)
+		}
+	}
+	if len(unique) == 0 {
+		return queries
+	}
+	create, update := queries.Create, queries.Update
+	return queries.WithCreate(func(ctx *gin.Context, m models.InternalValue) (models.InternalValue, error) {
+		if checkErr := checkUniqueIndexes[Model](ctx, unique, m, nil, nil); checkErr != nil {
+			return nil, checkErr
+		}
+		created, createErr := create(ctx, m)
+		return created, uniqueViolationErr[Model](ctx, unique, createErr)
+	}).WithUpdate(func(
+		ctx *gin.Context, old models.InternalValue, new models.InternalValue, id any,
+	) (models.InternalValue, error) {
+		if checkErr := checkUniqueIndexes[Model](ctx, unique, new, old, id); checkErr != nil {
+			return nil, checkErr
+		}
+		updated, updateErr := update(ctx, old, new, id)
+		return updated, uniqueViolationErr[Model](ctx, unique, updateErr)
+	})
+}
+
+// checkUniqueIndexes returns the validation error of the first unique index already holding the
+// values of the internal value, skipping the object with the id, see serializers.UniqueValidator.
+// Partial indexes are skipped, as the objects can't be checked against their conditions.
+func checkUniqueIndexes[Model any](
+	ctx *gin.Context, indexes []*Index, intVal, current models.InternalValue, id any,
+) error {
+	var empty Model
+	for _, index := range indexes {
+		if index.Where != "" {
+			continue
+		}
+		validator := serializers.UniqueValidator{Fields: index.Fields}
+		checkErr := validator.Validate(intVal, current, func(values map[string]any) (bool, error) {
+			query := CtxQuery(ctx).Session(&gorm.Session{NewDB: true}).Model(&empty)
+			for _, field := range index.Fields {
+				query = query.Where(clause.Eq{Column: clause.Column{Name: field}, Value: values[field]})
+			}
+			if id != nil {
+				query = query.Not(lookupCondition(ctx, id))
+			}
+			var count int64
+			countErr := query.Limit(1).Count(&count).Error
+			return count > 0, countErr
+		})
+		if checkErr != nil {
+			return checkErr
+		}
+	}
+	return nil
+}
+
+// uniqueViolationErr maps the duplicate key error of the database to the validation error of the
+// violated index, as the objects may be saved concurrently after the checks. The index is found by
+// its name in the error message, or by its columns for SQLite, which doesn't report the names.
+func uniqueViolationErr[Model any](ctx *gin.Context, indexes []*Index, err error) error {
+	if err == nil || errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	table, tableErr := tableName[Model](CtxQuery(ctx))
+	if tableErr != nil {
+		return err
+	}
+	message := err.Error()
+	for _, index := range indexes {
+		columns := make([]string, 0, len(index.Fields))
+		for _, field := range index.Fields {
+			columns = append(columns, table+"."+field)
+		}
+		name := index.name(table)
+		// PostgreSQL quotes the names with double quotes, MySQL with single quotes prefixed by the table
+		if strings.Contains(message, `"`+name+`"`) || strings.Contains(message, "."+name+"'") ||
+			strings.HasSuffix(message, "UNIQUE constraint failed: "+strings.Join(columns, ", ")) {
+			return serializers.UniqueViolation(index.Fields...)
+		}
+	}
+	return err
+}
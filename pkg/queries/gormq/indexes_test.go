@@ -0,0 +1,133 @@
+package gormq
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+type indexedModel struct {
+	ID      uint    `gorm:"primaryKey" json:"id"`
+	OwnerID uint    `json:"owner_id"`
+	Name    string  `json:"name"`
+	Email   *string `json:"email"`
+	Deleted bool    `json:"deleted"`
+}
+
+func prepareIndexedCtx(t *testing.T, indexes ...*Index) (*gin.Context, *GormQueryDriver[indexedModel]) {
+	db := prepareGorm(t)
+	queryDriver := Gorm[indexedModel](Static(db)).WithIndex(indexes...)
+	assert.NoError(t, queryDriver.Migrate(db))
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	for _, middleware := range queryDriver.Middleware() {
+		middleware(ctx)
+	}
+	return ctx, queryDriver
+}
+
+func TestGormDBMigrateCreatesIndexes(t *testing.T) {
+	// given
+	db := prepareGorm(t)
+	queryDriver := Gorm[indexedModel](Static(db)).WithIndex(
+		UniqueTogether("owner_id", "name"),
+		NewIndex("email").WithName("idx_email_active").WithWhere("deleted = false"),
+	)
+
+	// when
+	migrateErr := queryDriver.Migrate(db)
+	migrateAgainErr := queryDriver.Migrate(db)
+
+	// then
+	assert.NoError(t, migrateErr)
+	assert.NoError(t, migrateAgainErr)
+	assert.True(t, db.Migrator().HasIndex(&indexedModel{}, "idx_indexed_models_owner_id_name"))
+	assert.True(t, db.Migrator().HasIndex(&indexedModel{}, "idx_email_active"))
+}
+
+func TestGormDBUniqueIndexChecks(t *testing.T) {
+	email := "jane@example.com"
+	tests := []struct {
+		name          string
+		intVal        models.InternalValue
+		expectedError map[string][]string
+	}{
+		{
+			name:   "unique together",
+			intVal: models.InternalValue{"owner_id": uint(1), "name": "Jane"},
+			expectedError: map[string][]string{
+				"owner_id": {"the combination of owner_id, name is already used"},
+				"name":     {"the combination of owner_id, name is already used"},
+			},
+		},
+		{name: "another owner", intVal: models.InternalValue{"owner_id": uint(2), "name": "Jane"}},
+		{
+			name:          "partial",
+			intVal:        models.InternalValue{"owner_id": uint(2), "name": "John", "email": &email},
+			expectedError: map[string][]string{"email": {"this value is already used"}},
+		},
+		{name: "null", intVal: models.InternalValue{"owner_id": uint(2), "name": "John", "email": nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx, queryDriver := prepareIndexedCtx(
+				t, UniqueTogether("owner_id", "name"), UniqueTogether("email").WithWhere("deleted = false"),
+			)
+			_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"owner_id": uint(1), "name": "Jane", "email": &email})
+			assert.NoError(t, createErr)
+			_, createDeletedErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"owner_id": uint(3), "name": "Jane", "email": &email, "deleted": true})
+			assert.NoError(t, createDeletedErr)
+
+			// when
+			_, err := queryDriver.CRUD().Create(ctx, tt.intVal)
+
+			// then
+			if tt.expectedError == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, &serializers.ValidationError{FieldErrors: tt.expectedError}, err)
+		})
+	}
+}
+
+func TestGormDBUniqueIndexUpdateSkipsItself(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareIndexedCtx(t, UniqueTogether("name"))
+	created, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"name": "Jane"})
+	assert.NoError(t, createErr)
+	_, createOtherErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"name": "John"})
+	assert.NoError(t, createOtherErr)
+
+	// when
+	_, updateErr := queryDriver.CRUD().Update(ctx, created, models.InternalValue{"id": created["id"], "name": "Jane"}, created["id"])
+	_, conflictErr := queryDriver.CRUD().Update(ctx, created, models.InternalValue{"id": created["id"], "name": "John"}, created["id"])
+
+	// then
+	assert.NoError(t, updateErr)
+	assert.Equal(t, &serializers.ValidationError{
+		FieldErrors: map[string][]string{"name": {"this value is already used"}},
+	}, conflictErr)
+}
+
+func TestGormDBUniqueViolationMapping(t *testing.T) {
+	// given
+	ctx, queryDriver := prepareIndexedCtx(t, UniqueTogether("owner_id", "name"))
+	_, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"owner_id": uint(1), "name": "Jane"})
+	assert.NoError(t, createErr)
+
+	// when
+	insertErr := CtxQuery(ctx).Create(&indexedModel{OwnerID: 1, Name: "Jane"}).Error
+	err := uniqueViolationErr[indexedModel](ctx, queryDriver.indexes, insertErr)
+
+	// then
+	assert.Error(t, insertErr)
+	assert.Equal(t, &serializers.ValidationError{FieldErrors: map[string][]string{
+		"owner_id": {"the combination of owner_id, name is already used"},
+		"name":     {"the combination of owner_id, name is already used"},
+	}}, err)
+}
@@ -1,18 +1,19 @@
 package gormq
 
 import (
-	"strconv"
-
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
+	"github.com/glothriel/grf/pkg/pagination"
 	"gorm.io/gorm"
 )
 
+// Pagination paginates the lists by modifying the query, like ContinuationPagination. The limits
+// and offsets are applied by the paginations of the views instead, see pagination.LimitOffsetPagination.
 type Pagination interface {
 	Apply(*gin.Context, *gorm.DB) *gorm.DB
 	Format(*gin.Context, []any) (any, error)
 }
 
+// NoPagination lists all the objects, unless the views paginate them
 type NoPagination struct{}
 
 func (p *NoPagination) Apply(_ *gin.Context, db *gorm.DB) *gorm.DB {
@@ -23,30 +24,25 @@ func (p *NoPagination) Format(_ *gin.Context, entities []any) (any, error) {
 	return entities, nil
 }
 
-type LimitOffsetPagination struct {
-}
+// LimitOffsetPagination reads the `limit` and `offset` query parameters, the missing and invalid
+// values are ignored.
+//
+// Deprecated: the lists paginated by the query driver can't be paginated by the views, use
+// pagination.LimitOffsetPagination with views.ViewSet.WithPagination instead.
+type LimitOffsetPagination struct{}
 
 func (p *LimitOffsetPagination) Apply(c *gin.Context, db *gorm.DB) *gorm.DB {
-	if c.Query("limit") != "" {
-		limit, conversionErr := strconv.Atoi(c.Query("limit"))
-		if conversionErr == nil {
-			db = db.Limit(limit)
-		} else {
-			logrus.Debug("Failed to convert limit to int in LimitOffsetPagination")
-		}
+	// Without the default limit, the window's limit is only set if requested
+	window, _ := (&pagination.LimitOffsetPagination{}).Window(c)
+	if window.Limit > 0 {
+		db = db.Limit(window.Limit)
 	}
-	if c.Query("offset") != "" {
-		offset, conversionErr := strconv.Atoi(c.Query("offset"))
-		if conversionErr == nil {
-			db = db.Offset(offset)
-		} else {
-			logrus.Debug("Failed to convert offset to int in LimitOffsetPagination")
-		}
+	if window.Offset > 0 {
+		db = db.Offset(window.Offset)
 	}
-
 	return db
 }
 
-func (p *LimitOffsetPagination) Format(c *gin.Context, entities []any) (any, error) {
+func (p *LimitOffsetPagination) Format(_ *gin.Context, entities []any) (any, error) {
 	return entities, nil
 }
@@ -1,11 +1,14 @@
 package gormq
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"reflect"
 
 	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
 	"github.com/sirupsen/logrus"
 )
 
@@ -13,9 +16,11 @@ func SQLScannerOrPassthrough[Model any]() func(map[string]any, string, *gin.Cont
 	var entity Model
 	jsonTagsToFieldNames := map[string]string{}
 	for _, field := range reflect.VisibleFields(reflect.TypeOf(entity)) {
-		jsonTag := field.Tag.Get("json")
-		if jsonTag != "" {
-			jsonTagsToFieldNames[jsonTag] = field.Name
+		if field.Anonymous {
+			continue
+		}
+		if name, included := models.FieldName(field); included {
+			jsonTagsToFieldNames[name] = field.Name
 		}
 	}
 	fieldBlueprints := map[string]reflect.Type{}
@@ -36,6 +41,9 @@ func SQLScannerOrPassthrough[Model any]() func(map[string]any, string, *gin.Cont
 
 		scanner, ok := reflectedInstance.(sql.Scanner)
 		if !ok {
+			if isJSONColumn(fieldBlueprints[name]) {
+				return fromJSONColumn(reprModel[name], fieldBlueprints[name], name)
+			}
 			logrus.Debugf("Field `%s` is not a sql.Scanner, returning value as is", name)
 			return reprModel[name], nil
 		}
@@ -48,3 +56,30 @@ func SQLScannerOrPassthrough[Model any]() func(map[string]any, string, *gin.Cont
 		return reflect.ValueOf(scanner).Elem().Interface(), nil
 	}
 }
+
+// isJSONColumn returns true for map[string]T and struct fields, that are stored in JSON columns,
+// for example using `gorm:"serializer:json"` tag
+func isJSONColumn(t reflect.Type) bool {
+	return (t.Kind() == reflect.Map && t.Key().Kind() == reflect.String) || t.Kind() == reflect.Struct
+}
+
+func fromJSONColumn(value any, t reflect.Type, name string) (any, error) {
+	var rawJSON []byte
+	switch v := value.(type) {
+	case []byte:
+		rawJSON = v
+	case string:
+		rawJSON = []byte(v)
+	default:
+		return value, nil
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(rawJSON), []byte("{")) {
+		// Not a JSON object, eg. time.Time stored as text
+		return value, nil
+	}
+	typedValue := reflect.New(t)
+	if unmarshalErr := json.Unmarshal(rawJSON, typedValue.Interface()); unmarshalErr != nil {
+		return nil, fmt.Errorf("could not convert field from db `%s`: %s", name, unmarshalErr)
+	}
+	return typedValue.Elem().Interface(), nil
+}
@@ -66,3 +66,19 @@ func TestSQLScannerOrPassthroughWhenIsNotSqlScanner(t *testing.T) {
 	assert.Equal(t, "foo", value)
 	assert.Nil(t, valueErr)
 }
+
+type mockJSONColumnModel struct {
+	Field map[string]int `json:"field"`
+}
+
+func TestSQLScannerOrPassthroughWhenIsJSONColumn(t *testing.T) {
+	// given
+	fromDBFunc := SQLScannerOrPassthrough[mockJSONColumnModel]()
+
+	// when
+	value, valueErr := fromDBFunc(map[string]any{"field": `{"foo": 1}`}, "field", nil)
+
+	// then
+	assert.Equal(t, map[string]int{"foo": 1}, value)
+	assert.Nil(t, valueErr)
+}
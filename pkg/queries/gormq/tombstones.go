@@ -0,0 +1,106 @@
+package gormq
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/tombstones"
+)
+
+// tombstonesPurgeInterval limits how often Record removes the tombstones older than the retention
+const tombstonesPurgeInterval = time.Minute
+
+// TombstoneRecord is the row of the deletion log table, it has to be migrated by the application,
+// for example using `db.AutoMigrate(&gormq.TombstoneRecord{})`
+type TombstoneRecord struct {
+	ID        uint      `gorm:"primaryKey"`
+	Model     string    `gorm:"size:255;index:idx_grf_tombstones_model_deleted_at,priority:1"`
+	ObjectID  string    `gorm:"size:255"`
+	DeletedAt time.Time `gorm:"index:idx_grf_tombstones_model_deleted_at,priority:2"`
+}
+
+func (TombstoneRecord) TableName() string {
+	return "grf_tombstones"
+}
+
+// Tombstones keeps the deletion log in the `grf_tombstones` table, using the database of the
+// request. The table is shared by all the models, so it can back all the viewsets. The object IDs
+// are stored as strings and the times in UTC.
+type Tombstones[Model any] struct {
+	model     string
+	retention time.Duration
+	now       func() time.Time
+
+	mu         sync.Mutex
+	lastPurged time.Time
+}
+
+// Record adds the tombstone to the deletion log, removing the ones older than the retention, at most
+// once per minute
+func (t *Tombstones[Model]) Record(ctx *gin.Context, id any) error {
+	now := t.now().UTC()
+	if createErr := New(ctx).Create(&TombstoneRecord{
+		Model: t.model, ObjectID: fmt.Sprint(id), DeletedAt: now,
+	}).Error; createErr != nil {
+		return createErr
+	}
+	t.mu.Lock()
+	shouldPurge := t.retention > 0 && now.Sub(t.lastPurged) >= tombstonesPurgeInterval
+	if shouldPurge {
+		t.lastPurged = now
+	}
+	t.mu.Unlock()
+	if shouldPurge {
+		return t.Purge(ctx)
+	}
+	return nil
+}
+
+// Purge removes the tombstones older than the retention
+func (t *Tombstones[Model]) Purge(ctx *gin.Context) error {
+	return New(ctx).Where(
+		"model = ? AND deleted_at < ?", t.model, t.now().UTC().Add(-t.retention),
+	).Delete(&TombstoneRecord{}).Error
+}
+
+func (t *Tombstones[Model]) DeletedSince(ctx *gin.Context, since time.Time) ([]any, error) {
+	var records []TombstoneRecord
+	if findErr := New(ctx).Where(
+		"model = ? AND deleted_at > ?", t.model, since.UTC(),
+	).Order("deleted_at").Find(&records).Error; findErr != nil {
+		return nil, findErr
+	}
+	ids := make([]any, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ObjectID)
+	}
+	return ids, nil
+}
+
+func (t *Tombstones[Model]) DeletedBetween(ctx *gin.Context, from, to time.Time) ([]tombstones.Tombstone, error) {
+	var records []TombstoneRecord
+	if findErr := New(ctx).Where(
+		"model = ? AND deleted_at >= ? AND deleted_at < ?", t.model, from.UTC(), to.UTC(),
+	).Order("deleted_at").Find(&records).Error; findErr != nil {
+		return nil, findErr
+	}
+	found := make([]tombstones.Tombstone, 0, len(records))
+	for _, record := range records {
+		found = append(found, tombstones.Tombstone{ID: record.ObjectID, DeletedAt: record.DeletedAt})
+	}
+	return found, nil
+}
+
+// NewTombstones creates Tombstones of the Model, keeping them for the retention period. Zero
+// retention keeps the tombstones forever.
+func NewTombstones[Model any](retention time.Duration) *Tombstones[Model] {
+	var m Model
+	return &Tombstones[Model]{
+		model:     reflect.TypeOf(m).String(),
+		retention: retention,
+		now:       time.Now,
+	}
+}
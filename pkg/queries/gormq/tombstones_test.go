@@ -0,0 +1,39 @@
+package gormq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glothriel/grf/pkg/tombstones"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTombstones(t *testing.T) {
+	// given
+	db := prepareGorm(t)
+	assert.NoError(t, db.AutoMigrate(&TombstoneRecord{}))
+	ctx, _ := prepareCtx[MockModel](t, db)
+	at := func(hour int) time.Time { return time.Date(2024, 1, 1, hour, 0, 0, 0, time.UTC) }
+	now := at(10)
+	mocks := NewTombstones[MockModel](150 * time.Minute)
+	mocks.now = func() time.Time { return now }
+	others := NewTombstones[struct{}](0)
+	others.now = func() time.Time { return at(12) }
+
+	// when
+	for id := 1; id <= 4; id++ {
+		now = at(9 + id)
+		assert.NoError(t, mocks.Record(ctx, id))
+	}
+	assert.NoError(t, others.Record(ctx, 5))
+	since, sinceErr := mocks.DeletedSince(ctx, at(11))
+	between, betweenErr := mocks.DeletedBetween(ctx, at(0), at(13))
+
+	// then
+	assert.NoError(t, sinceErr)
+	assert.NoError(t, betweenErr)
+	assert.Equal(t, []any{"3", "4"}, since)
+	assert.Equal(t, []tombstones.Tombstone{
+		{ID: "2", DeletedAt: at(11)}, {ID: "3", DeletedAt: at(12)},
+	}, between)
+}
@@ -311,3 +311,23 @@ func TestDestroyTxErr(t *testing.T) {
 		})
 	}
 }
+
+func TestAtomicRollsBackOnError(t *testing.T) {
+	// given
+	db := prepareGorm(t)
+	ctx, queryDriver := prepareCtx[MockModel](t, db)
+
+	// when
+	atomicErr := queryDriver.Atomic(ctx, func() error {
+		if _, createErr := queryDriver.CRUD().Create(ctx, models.InternalValue{"foo": "bar"}); createErr != nil {
+			return createErr
+		}
+		return errors.New("second insert failed")
+	})
+	listed, listErr := queryDriver.CRUD().List(ctx)
+
+	// then
+	assert.EqualError(t, atomicErr, "second insert failed")
+	assert.NoError(t, listErr)
+	assert.Empty(t, listed)
+}
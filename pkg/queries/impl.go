@@ -1,9 +1,13 @@
 package queries
 
 import (
+	"database/sql"
+
 	"github.com/gin-gonic/gin"
 	"github.com/glothriel/grf/pkg/queries/dummy"
+	"github.com/glothriel/grf/pkg/queries/funcq"
 	gormdb "github.com/glothriel/grf/pkg/queries/gormq"
+	"github.com/glothriel/grf/pkg/queries/sqlq"
 	"gorm.io/gorm"
 )
 
@@ -22,3 +26,16 @@ func DynamicGORM[Model any](dbFunc func(*gin.Context) *gorm.DB) *gormdb.GormQuer
 		gormdb.Dynamic(dbFunc),
 	)
 }
+
+// SQL creates a driver using plain database/sql, for the projects not using GORM. The objects are
+// stored in the table, using the fields of the model as the column names.
+func SQL[Model any](db *sql.DB, table string) *sqlq.Driver[Model] {
+	return sqlq.New[Model](db, table)
+}
+
+// Func creates a driver calling the functions, for example the ones using the clients of other
+// services, listing the objects using the function, see funcq.New. The clients generated by
+// entgo.io are served by the driver of the entq module.
+func Func[Model any](list funcq.ListFunc[Model]) *funcq.Driver[Model] {
+	return funcq.New[Model](list)
+}
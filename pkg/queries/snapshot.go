@@ -0,0 +1,112 @@
+package queries
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/queries/crud"
+	"github.com/glothriel/grf/pkg/queries/dummy"
+	"github.com/glothriel/grf/pkg/storage"
+)
+
+// ErrReadOnly is returned by the write operations of the read-only drivers
+var ErrReadOnly = fmt.Errorf("the resource is read-only: %w", common.ErrOperationNotAllowed)
+
+// SnapshotDriver serves the objects of a snapshot exported to a storage, see Snapshot
+type SnapshotDriver[Model any] struct {
+	index *dummy.InMemoryQueryDriver[Model]
+}
+
+// Snapshot loads the snapshot stored under the key and creates a read-only driver serving it, for
+// example in archival or demo environments. The snapshot is a JSON lines file, with `.jsonl` or
+// `.ndjson` extension, holding a JSON object of the model in every line. The objects are indexed
+// in memory, so the filters, the search, the ordering and the pagination of the viewsets work like
+// with the InMemory driver. The write operations fail with ErrReadOnly.
+func Snapshot[Model any](ctx context.Context, s storage.Storage, key string) (*SnapshotDriver[Model], error) {
+	if extension := path.Ext(key); extension != ".jsonl" && extension != ".ndjson" {
+		return nil, fmt.Errorf("snapshot `%s`: unsupported format `%s`, only JSON lines are supported", key, extension)
+	}
+	object, openErr := s.Open(ctx, key)
+	if openErr != nil {
+		return nil, fmt.Errorf("snapshot `%s`: %w", key, openErr)
+	}
+	defer object.Content.Close()
+	records := []Model{}
+	decoder := json.NewDecoder(object.Content)
+	for {
+		var record Model
+		decodeErr := decoder.Decode(&record)
+		if errors.Is(decodeErr, io.EOF) {
+			break
+		}
+		if decodeErr != nil {
+			return nil, fmt.Errorf("snapshot `%s`: record %d: %w", key, len(records)+1, decodeErr)
+		}
+		records = append(records, record)
+	}
+	return &SnapshotDriver[Model]{index: InMemory(records...)}, nil
+}
+
+// WithOrderBy sets the default ordering of the lists, see dummy.InMemoryQueryDriver.WithOrderBy
+func (d *SnapshotDriver[Model]) WithOrderBy(ordering string) *SnapshotDriver[Model] {
+	d.index.WithOrderBy(ordering)
+	return d
+}
+
+// WithPagination paginates the lists, see dummy.InMemoryQueryDriver.WithPagination
+func (d *SnapshotDriver[Model]) WithPagination(pagination dummy.Pagination) *SnapshotDriver[Model] {
+	d.index.WithPagination(pagination)
+	return d
+}
+
+func (d *SnapshotDriver[Model]) CRUD() *crud.CRUD[Model] {
+	index := d.index.CRUD()
+	return &crud.CRUD[Model]{
+		List:     index.List,
+		Retrieve: index.Retrieve,
+		Create: func(*gin.Context, models.InternalValue) (models.InternalValue, error) {
+			return nil, ErrReadOnly
+		},
+		Update: func(*gin.Context, models.InternalValue, models.InternalValue, any) (models.InternalValue, error) {
+			return nil, ErrReadOnly
+		},
+		Destroy: func(*gin.Context, any) error {
+			return ErrReadOnly
+		},
+	}
+}
+
+func (d *SnapshotDriver[Model]) Pagination() common.Pagination {
+	return d.index.Pagination()
+}
+
+func (d *SnapshotDriver[Model]) Filter() common.QueryMod {
+	return d.index.Filter()
+}
+
+func (d *SnapshotDriver[Model]) Order() common.QueryMod {
+	return d.index.Order()
+}
+
+func (d *SnapshotDriver[Model]) Middleware() []gin.HandlerFunc {
+	return d.index.Middleware()
+}
+
+func (d *SnapshotDriver[Model]) Count(ctx *gin.Context) (int, error) {
+	return d.index.Count(ctx)
+}
+
+func (d *SnapshotDriver[Model]) Exists(ctx *gin.Context, conditions map[string]any) (bool, error) {
+	return d.index.Exists(ctx, conditions)
+}
+
+func (d *SnapshotDriver[Model]) Stats(ctx *gin.Context, field string, percentiles []float64) (common.Stats, error) {
+	return d.index.Stats(ctx, field, percentiles)
+}
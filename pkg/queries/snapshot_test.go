@@ -0,0 +1,73 @@
+package queries
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	tests := []struct {
+		name          string
+		key           string
+		data          string
+		expectedCount int
+		expectedErr   string
+	}{
+		{
+			name:          "json lines",
+			key:           "archive/products.jsonl",
+			data:          "{\"id\": 1, \"name\": \"first\"}\n\n{\"id\": 2, \"name\": \"second\"}\n",
+			expectedCount: 2,
+		},
+		{
+			name:        "invalid record",
+			key:         "archive/products.ndjson",
+			data:        "{\"id\": 1, \"name\": \"first\"}\n{\"id\": \"2\"}\n",
+			expectedErr: "snapshot `archive/products.ndjson`: record 2: json: cannot unmarshal string into Go struct field migratedModel.id of type uint",
+		},
+		{
+			name:        "unsupported format",
+			key:         "archive/products.parquet",
+			expectedErr: "snapshot `archive/products.parquet`: unsupported format `.parquet`, only JSON lines are supported",
+		},
+		{
+			name:        "missing",
+			key:         "archive/missing.jsonl",
+			expectedErr: "snapshot `archive/missing.jsonl`: object not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			s := storage.NewInMemory()
+			if tt.data != "" {
+				s.Put(tt.key, []byte(tt.data), "")
+			}
+
+			// when
+			driver, err := Snapshot[migratedModel](context.Background(), s, tt.key)
+
+			// then
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			count, countErr := driver.Count(ctx)
+			assert.NoError(t, countErr)
+			assert.Equal(t, tt.expectedCount, count)
+			retrieved, retrieveErr := driver.CRUD().Retrieve(ctx, uint(2))
+			assert.NoError(t, retrieveErr)
+			assert.Equal(t, "second", retrieved["name"])
+			_, createErr := driver.CRUD().Create(ctx, models.InternalValue{"name": "third"})
+			assert.ErrorIs(t, createErr, ErrReadOnly)
+		})
+	}
+}
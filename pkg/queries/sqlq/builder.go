@@ -0,0 +1,236 @@
+package sqlq
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/glothriel/grf/pkg/grfctx"
+)
+
+// Dialect describes the differences between the SQL databases, that matter to the driver
+type Dialect struct {
+	Name string
+	// Placeholder returns the placeholder of the n-th argument of the query, starting from 1
+	Placeholder func(n int) string
+	// Quote quotes the identifiers, like table and column names
+	Quote func(identifier string) string
+	// Returning is set for the databases, that return the generated IDs using a RETURNING clause,
+	// instead of sql.Result.LastInsertId
+	Returning bool
+}
+
+func questionMark(int) string {
+	return "?"
+}
+
+func doubleQuoted(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+var (
+	SQLite = Dialect{Name: "sqlite", Placeholder: questionMark, Quote: doubleQuoted}
+	MySQL  = Dialect{
+		Name:        "mysql",
+		Placeholder: questionMark,
+		Quote: func(identifier string) string {
+			return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+		},
+	}
+	Postgres = Dialect{
+		Name: "postgres",
+		Placeholder: func(n int) string {
+			return "$" + strconv.Itoa(n)
+		},
+		Quote:     doubleQuoted,
+		Returning: true,
+	}
+)
+
+// Condition is a fragment of a WHERE clause, using `?` as the placeholders of the arguments, which
+// are replaced with the placeholders of the dialect when the query is built
+type Condition struct {
+	SQL  string
+	Args []any
+}
+
+// query is a small builder of the SELECT, UPDATE and DELETE statements of a single table
+type query struct {
+	dialect    Dialect
+	table      string
+	conditions []Condition
+	orderBy    []string
+	limit      int
+	offset     int
+}
+
+func newQuery(dialect Dialect, table string) *query {
+	return &query{dialect: dialect, table: table}
+}
+
+func (q *query) where(sql string, args ...any) *query {
+	q.conditions = append(q.conditions, Condition{SQL: sql, Args: args})
+	return q
+}
+
+func (q *query) whereColumn(column, operator string, value any) *query {
+	return q.where(q.dialect.Quote(column)+" "+operator+" ?", value)
+}
+
+func (q *query) order(column string, descending bool) *query {
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+	q.orderBy = append(q.orderBy, q.dialect.Quote(column)+" "+direction)
+	return q
+}
+
+// whereClause returns the WHERE clause of the conditions, with the arguments
+func (q *query) whereClause() (string, []any) {
+	if len(q.conditions) == 0 {
+		return "", nil
+	}
+	parts := make([]string, 0, len(q.conditions))
+	args := []any{}
+	for _, condition := range q.conditions {
+		parts = append(parts, "("+condition.SQL+")")
+		args = append(args, condition.Args...)
+	}
+	return " WHERE " + strings.Join(parts, " AND "), args
+}
+
+func (q *query) selectSQL(columns []string) (string, []any) {
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, q.dialect.Quote(column))
+	}
+	where, args := q.whereClause()
+	statement := "SELECT " + strings.Join(quoted, ", ") + " FROM " + q.dialect.Quote(q.table) + where
+	if len(q.orderBy) > 0 {
+		statement += " ORDER BY " + strings.Join(q.orderBy, ", ")
+	}
+	// OFFSET requires LIMIT in some of the databases, the windows always have both
+	if q.limit > 0 {
+		statement += " LIMIT " + strconv.Itoa(q.limit)
+		if q.offset > 0 {
+			statement += " OFFSET " + strconv.Itoa(q.offset)
+		}
+	}
+	return q.rebind(statement), args
+}
+
+func (q *query) countSQL() (string, []any) {
+	where, args := q.whereClause()
+	return q.rebind("SELECT COUNT(*) FROM " + q.dialect.Quote(q.table) + where), args
+}
+
+func (q *query) existsSQL() (string, []any) {
+	where, args := q.whereClause()
+	return q.rebind("SELECT EXISTS (SELECT 1 FROM " + q.dialect.Quote(q.table) + where + ")"), args
+}
+
+func (q *query) updateSQL(columns []string, values []any) (string, []any) {
+	assignments := make([]string, 0, len(columns))
+	for _, column := range columns {
+		assignments = append(assignments, q.dialect.Quote(column)+" = ?")
+	}
+	where, args := q.whereClause()
+	statement := "UPDATE " + q.dialect.Quote(q.table) + " SET " + strings.Join(assignments, ", ") + where
+	return q.rebind(statement), append(values, args...)
+}
+
+func (q *query) deleteSQL() (string, []any) {
+	where, args := q.whereClause()
+	return q.rebind("DELETE FROM " + q.dialect.Quote(q.table) + where), args
+}
+
+func (q *query) insertSQL(columns []string, values []any, returning string) (string, []any) {
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, q.dialect.Quote(column))
+	}
+	statement := "INSERT INTO " + q.dialect.Quote(q.table)
+	if len(columns) == 0 {
+		statement += " DEFAULT VALUES"
+	} else {
+		statement += " (" + strings.Join(quoted, ", ") + ") VALUES (" +
+			strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	}
+	if returning != "" {
+		statement += " RETURNING " + q.dialect.Quote(returning)
+	}
+	return q.rebind(statement), values
+}
+
+// rebind replaces the `?` placeholders with the placeholders of the dialect
+func (q *query) rebind(statement string) string {
+	if q.dialect.Placeholder(1) == "?" {
+		return statement
+	}
+	var rebound strings.Builder
+	n := 0
+	for _, r := range statement {
+		if r != '?' {
+			rebound.WriteRune(r)
+			continue
+		}
+		n++
+		rebound.WriteString(q.dialect.Placeholder(n))
+	}
+	return rebound.String()
+}
+
+// wherePredicate adds the condition of the predicate, the field is used as the column name
+func (q *query) wherePredicate(predicate grfctx.Predicate) *query {
+	column := q.dialect.Quote(predicate.Field)
+	switch predicate.Operator {
+	case grfctx.OperatorGt:
+		return q.where(column+" > ?", predicate.Value)
+	case grfctx.OperatorGte:
+		return q.where(column+" >= ?", predicate.Value)
+	case grfctx.OperatorLt:
+		return q.where(column+" < ?", predicate.Value)
+	case grfctx.OperatorLte:
+		return q.where(column+" <= ?", predicate.Value)
+	case grfctx.OperatorContains:
+		return q.where(column+" LIKE ? ESCAPE '!'", "%"+escapeLike(predicate.Value)+"%")
+	case grfctx.OperatorIContains:
+		return q.where("LOWER("+column+") LIKE LOWER(?) ESCAPE '!'", "%"+escapeLike(predicate.Value)+"%")
+	case grfctx.OperatorStartsWith:
+		return q.where(column+" LIKE ? ESCAPE '!'", escapeLike(predicate.Value)+"%")
+	case grfctx.OperatorIn:
+		values, _ := predicate.Value.([]any)
+		if len(values) == 0 {
+			return q.where("1 = 0")
+		}
+		return q.where(column+" IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")+")", values...)
+	case grfctx.OperatorIsNull:
+		if predicate.Value == true {
+			return q.where(column + " IS NULL")
+		}
+		return q.where(column + " IS NOT NULL")
+	}
+	return q.where(column+" = ?", predicate.Value)
+}
+
+// whereSearch adds the conditions of the search terms, every term has to be contained by any of the
+// fields, ignoring the case
+func (q *query) whereSearch(search grfctx.Search) *query {
+	for _, term := range search.Terms {
+		matches := make([]string, 0, len(search.Fields))
+		args := make([]any, 0, len(search.Fields))
+		for _, field := range search.Fields {
+			matches = append(matches, "LOWER("+q.dialect.Quote(field)+") LIKE LOWER(?) ESCAPE '!'")
+			args = append(args, "%"+escapeLike(term)+"%")
+		}
+		q.where(strings.Join(matches, " OR "), args...)
+	}
+	return q
+}
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+func escapeLike(value any) string {
+	return likeEscaper.Replace(fmt.Sprintf("%v", value))
+}
@@ -0,0 +1,527 @@
+// Package sqlq provides a query driver using plain database/sql, for the projects not using GORM.
+// It works with any *sql.DB, including the ones wrapped by sqlx, and builds the queries needed by
+// the views itself: the filters, the search, the ordering and the windows of the pagination.
+package sqlq
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/ids"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/queries/crud"
+)
+
+// Executor runs the queries, it's implemented by *sql.DB and *sql.Tx, as well as their sqlx
+// counterparts
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// FilterFunc returns the condition limiting the listed objects, like GORM driver's WithFilter it
+// applies to the lists and not to the detail routes
+type FilterFunc func(ctx *gin.Context) Condition
+
+type column struct {
+	name  string
+	index []int
+}
+
+// Driver is a query driver storing the objects of the model in a table, the fields of the model are
+// used as the column names
+type Driver[Model any] struct {
+	db              *sql.DB
+	table           string
+	dialect         Dialect
+	columns         []column
+	primaryKey      string
+	filter          FilterFunc
+	ordering        []grfctx.OrderBy
+	errorTranslator common.ErrorTranslator
+}
+
+// New creates a driver for the model stored in the table. The dialect is detected using the type of
+// the database driver, it can be set explicitly using WithDialect.
+func New[Model any](db *sql.DB, table string) *Driver[Model] {
+	return &Driver[Model]{
+		db:              db,
+		table:           table,
+		dialect:         detectDialect(db),
+		columns:         modelColumns[Model](),
+		primaryKey:      "id",
+		errorTranslator: common.TranslateError,
+	}
+}
+
+// WithDialect sets the dialect of the database, see SQLite, MySQL and Postgres
+func (d *Driver[Model]) WithDialect(dialect Dialect) *Driver[Model] {
+	d.dialect = dialect
+	return d
+}
+
+// WithPrimaryKey sets the column of the primary key, `id` by default, it's used to retrieve the
+// created objects, returned by the database if they're created without it
+func (d *Driver[Model]) WithPrimaryKey(column string) *Driver[Model] {
+	d.primaryKey = column
+	return d
+}
+
+// WithFilter limits the listed and counted objects using the condition returned by the function
+func (d *Driver[Model]) WithFilter(filter FilterFunc) *Driver[Model] {
+	d.filter = filter
+	return d
+}
+
+// WithOrderBy sets the default ordering of the lists, in the format of the `ordering` query
+// parameter, for example `-created_at,id`. The ordering requested by the clients takes precedence.
+func (d *Driver[Model]) WithOrderBy(ordering string) *Driver[Model] {
+	d.ordering = []grfctx.OrderBy{}
+	for _, term := range strings.Split(ordering, ",") {
+		field, descending := strings.CutPrefix(strings.TrimSpace(term), "-")
+		if field != "" {
+			d.ordering = append(d.ordering, grfctx.OrderBy{Field: field, Descending: descending})
+		}
+	}
+	return d
+}
+
+// WithErrorTranslator replaces the translation of the database errors, see common.TranslateError
+func (d *Driver[Model]) WithErrorTranslator(translator common.ErrorTranslator) *Driver[Model] {
+	d.errorTranslator = translator
+	return d
+}
+
+func (d *Driver[Model]) CRUD() *crud.CRUD[Model] {
+	return &crud.CRUD[Model]{
+		List: func(ctx *gin.Context) ([]models.InternalValue, error) {
+			if window, ok := grfctx.CurrentWindow(ctx); ok && window.Random {
+				return d.listRandom(ctx, window.Limit)
+			}
+			q := d.scoped(ctx)
+			d.applyOrdering(ctx, q)
+			if windowErr := d.applyWindow(ctx, q); windowErr != nil {
+				return nil, windowErr
+			}
+			statement, args := q.selectSQL(d.columnNames())
+			return d.query(ctx, statement, args)
+		},
+		Retrieve: func(ctx *gin.Context, id any) (models.InternalValue, error) {
+			return d.retrieve(ctx, grfctx.LookupField(ctx), id, true)
+		},
+		Create: func(ctx *gin.Context, m models.InternalValue) (models.InternalValue, error) {
+			if _, generateErr := ids.Assign[Model](m); generateErr != nil {
+				return nil, generateErr
+			}
+			columns, values, valuesErr := d.values(m, "")
+			if valuesErr != nil {
+				return nil, valuesErr
+			}
+			id, hasID := m[d.primaryKey]
+			returning := ""
+			if !hasID && d.dialect.Returning {
+				returning = d.primaryKey
+			}
+			statement, args := newQuery(d.dialect, d.table).insertSQL(columns, values, returning)
+			if returning != "" {
+				if scanErr := d.executor(ctx).QueryRowContext(requestContext(ctx), statement, args...).Scan(&id); scanErr != nil {
+					return nil, d.translate(scanErr)
+				}
+			} else {
+				result, execErr := d.executor(ctx).ExecContext(requestContext(ctx), statement, args...)
+				if execErr != nil {
+					return nil, d.translate(execErr)
+				}
+				if !hasID {
+					lastID, lastIDErr := result.LastInsertId()
+					if lastIDErr != nil {
+						return nil, lastIDErr
+					}
+					id = lastID
+				}
+			}
+			return d.retrieve(ctx, d.primaryKey, id, false)
+		},
+		Update: func(ctx *gin.Context, old models.InternalValue, new models.InternalValue, id any) (
+			models.InternalValue, error,
+		) {
+			lookupField := grfctx.LookupField(ctx)
+			columns, values, valuesErr := d.values(new, lookupField)
+			if valuesErr != nil {
+				return nil, valuesErr
+			}
+			if len(columns) > 0 {
+				q := withParentScope(ctx, newQuery(d.dialect, d.table).whereColumn(lookupField, "=", id))
+				precondition, conditional := grfctx.UpdatePrecondition(ctx)
+				if conditional {
+					q.wherePredicate(precondition)
+				}
+				statement, args := q.updateSQL(columns, values)
+				result, execErr := d.executor(ctx).ExecContext(requestContext(ctx), statement, args...)
+				if execErr != nil {
+					return nil, d.translate(execErr)
+				}
+				if affected, affectedErr := result.RowsAffected(); conditional && affectedErr == nil && affected == 0 {
+					return nil, common.ErrPreconditionFailed
+				}
+			}
+			return d.retrieve(ctx, lookupField, id, true)
+		},
+		Destroy: func(ctx *gin.Context, id any) error {
+			q := withParentScope(ctx, newQuery(d.dialect, d.table).whereColumn(grfctx.LookupField(ctx), "=", id))
+			statement, args := q.deleteSQL()
+			result, execErr := d.executor(ctx).ExecContext(requestContext(ctx), statement, args...)
+			if execErr != nil {
+				return fmt.Errorf("could not delete entity: query error: %w", d.translate(execErr))
+			}
+			if affected, affectedErr := result.RowsAffected(); affectedErr == nil && affected == 0 {
+				return common.ErrorNotFound
+			}
+			return nil
+		},
+	}
+}
+
+func (d *Driver[Model]) retrieve(ctx *gin.Context, field string, value any, inParentScope bool) (models.InternalValue, error) {
+	q := newQuery(d.dialect, d.table).whereColumn(field, "=", value)
+	if inParentScope {
+		withParentScope(ctx, q)
+	}
+	q.limit = 1
+	statement, args := q.selectSQL(d.columnNames())
+	found, queryErr := d.query(ctx, statement, args)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	if len(found) == 0 {
+		return nil, common.ErrorNotFound
+	}
+	return found[0], nil
+}
+
+// Count implements queries.Counter, the objects are counted ignoring the window of the request
+func (d *Driver[Model]) Count(ctx *gin.Context) (int, error) {
+	statement, args := d.scoped(ctx).countSQL()
+	var count int
+	if scanErr := d.executor(ctx).QueryRowContext(requestContext(ctx), statement, args...).Scan(&count); scanErr != nil {
+		return 0, d.translate(scanErr)
+	}
+	return count, nil
+}
+
+// Exists implements queries.Exister using an EXISTS query, the fields are used as column names
+func (d *Driver[Model]) Exists(ctx *gin.Context, conditions map[string]any) (bool, error) {
+	q := d.scoped(ctx)
+	for field, value := range conditions {
+		q.whereColumn(field, "=", value)
+	}
+	statement, args := q.existsSQL()
+	var exists bool
+	if scanErr := d.executor(ctx).QueryRowContext(requestContext(ctx), statement, args...).Scan(&exists); scanErr != nil {
+		return false, d.translate(scanErr)
+	}
+	return exists, nil
+}
+
+// Atomic implements queries.Transactional, the queries run with the ctx inside fn use the
+// transaction. Nested calls use the transaction of the outer one.
+func (d *Driver[Model]) Atomic(ctx *gin.Context, fn func() error) error {
+	if tx, ok := ctx.Get(d.txCtxKey()); ok && tx.(*sql.Tx) != nil {
+		return fn()
+	}
+	tx, beginErr := d.db.BeginTx(requestContext(ctx), nil)
+	if beginErr != nil {
+		return beginErr
+	}
+	ctx.Set(d.txCtxKey(), tx)
+	defer ctx.Set(d.txCtxKey(), (*sql.Tx)(nil))
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+	if err := fn(); err != nil {
+		return err
+	}
+	committed = true
+	return tx.Commit()
+}
+
+func (d *Driver[Model]) Pagination() common.Pagination {
+	return common.NoPagination{}
+}
+
+func (d *Driver[Model]) Filter() common.QueryMod {
+	return appliedMod{key: filterCtxKey}
+}
+
+func (d *Driver[Model]) Order() common.QueryMod {
+	return appliedMod{key: orderCtxKey}
+}
+
+func (d *Driver[Model]) Middleware() []gin.HandlerFunc {
+	return []gin.HandlerFunc{}
+}
+
+// scoped returns the query of the objects matching the parent scope, the predicates, the search and
+// the filter of the request
+func (d *Driver[Model]) scoped(ctx *gin.Context) *query {
+	q := withParentScope(ctx, newQuery(d.dialect, d.table))
+	for _, predicate := range grfctx.Predicates(ctx) {
+		q.wherePredicate(predicate)
+	}
+	if search, ok := grfctx.CurrentSearch(ctx); ok {
+		q.whereSearch(search)
+	}
+	if d.filter != nil && applied(ctx, filterCtxKey) {
+		if condition := d.filter(ctx); condition.SQL != "" {
+			q.where(condition.SQL, condition.Args...)
+		}
+	}
+	return q
+}
+
+// applyOrdering orders the query by the ordering of the request, or the default one
+func (d *Driver[Model]) applyOrdering(ctx *gin.Context, q *query) {
+	ordering := grfctx.Ordering(ctx)
+	if len(ordering) == 0 && applied(ctx, orderCtxKey) {
+		ordering = d.ordering
+	}
+	for _, orderBy := range ordering {
+		q.order(orderBy.Field, orderBy.Descending)
+	}
+}
+
+// applyWindow limits the query to the window of a paginated request, keyset windows replace the
+// ordering of the query. Random windows are listed by listRandom.
+func (d *Driver[Model]) applyWindow(ctx *gin.Context, q *query) error {
+	window, ok := grfctx.CurrentWindow(ctx)
+	if !ok {
+		return nil
+	}
+	q.limit = window.Limit
+	switch {
+	case window.Keyset != nil:
+		if window.Keyset.After != nil {
+			operator := ">"
+			if window.Keyset.Descending {
+				operator = "<"
+			}
+			after, convertErr := common.KeysetValue[Model](window.Keyset.Field, window.Keyset.After)
+			if convertErr != nil {
+				return convertErr
+			}
+			q.whereColumn(window.Keyset.Field, operator, after)
+		}
+		q.orderBy = nil
+		q.order(window.Keyset.Field, window.Keyset.Descending)
+	default:
+		q.offset = window.Offset
+	}
+	return nil
+}
+
+// listRandom picks up to limit objects matching the predicates of the request at random. The rows
+// are counted first and fetched at random offsets in the primary key order, like the GORM driver
+// does, so the database doesn't sort all the rows by random values.
+func (d *Driver[Model]) listRandom(ctx *gin.Context, limit int) ([]models.InternalValue, error) {
+	count, countErr := d.Count(ctx)
+	if countErr != nil {
+		return nil, countErr
+	}
+	picked := []models.InternalValue{}
+	offsets := map[int]bool{}
+	for len(offsets) < min(limit, count) {
+		offset := rand.Intn(count)
+		if offsets[offset] {
+			continue
+		}
+		offsets[offset] = true
+		q := d.scoped(ctx)
+		q.order(d.primaryKey, false)
+		q.limit, q.offset = 1, offset
+		statement, args := q.selectSQL(d.columnNames())
+		found, queryErr := d.query(ctx, statement, args)
+		if queryErr != nil {
+			return nil, queryErr
+		}
+		picked = append(picked, found...)
+	}
+	return picked, nil
+}
+
+// query runs the SELECT statement and scans the rows into the fields of the model
+func (d *Driver[Model]) query(ctx *gin.Context, statement string, args []any) ([]models.InternalValue, error) {
+	rows, queryErr := d.executor(ctx).QueryContext(requestContext(ctx), statement, args...)
+	if queryErr != nil {
+		return nil, d.translate(queryErr)
+	}
+	defer rows.Close()
+	found := []models.InternalValue{}
+	for rows.Next() {
+		var entity Model
+		value := reflect.ValueOf(&entity).Elem()
+		destinations := make([]any, len(d.columns))
+		for i, c := range d.columns {
+			destinations[i] = value.FieldByIndex(c.index).Addr().Interface()
+		}
+		if scanErr := rows.Scan(destinations...); scanErr != nil {
+			return nil, scanErr
+		}
+		found = append(found, models.AsInternalValue(entity))
+	}
+	return found, d.translate(rows.Err())
+}
+
+// values returns the columns present in the internal value, except the skipped one, and their
+// values converted to the types of the model fields
+func (d *Driver[Model]) values(m models.InternalValue, skipped string) ([]string, []any, error) {
+	entity, asModelErr := models.AsModel[Model](m)
+	if asModelErr != nil {
+		return nil, nil, asModelErr
+	}
+	value := reflect.ValueOf(entity)
+	columns := []string{}
+	values := []any{}
+	for _, c := range d.columns {
+		if _, present := m[c.name]; !present || c.name == skipped {
+			continue
+		}
+		columns = append(columns, c.name)
+		values = append(values, value.FieldByIndex(c.index).Interface())
+	}
+	return columns, values, nil
+}
+
+func (d *Driver[Model]) columnNames() []string {
+	names := make([]string, 0, len(d.columns))
+	for _, c := range d.columns {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+func (d *Driver[Model]) translate(err error) error {
+	if err == nil || d.errorTranslator == nil {
+		return err
+	}
+	return d.errorTranslator(err, d.table, d.columnNames())
+}
+
+// executor returns the transaction of the request, if any, or the database
+func (d *Driver[Model]) executor(ctx *gin.Context) Executor {
+	if tx, ok := ctx.Get(d.txCtxKey()); ok && tx.(*sql.Tx) != nil {
+		return tx.(*sql.Tx)
+	}
+	return d.db
+}
+
+// txCtxKey is unique for the database, so the drivers of different models sharing the database
+// share the transactions
+func (d *Driver[Model]) txCtxKey() string {
+	return fmt.Sprintf("grf:sqlq:tx:%p", d.db)
+}
+
+func withParentScope(ctx *gin.Context, q *query) *query {
+	if scope, ok := grfctx.Parent(ctx); ok {
+		q.whereColumn(scope.Field, "=", scope.Value)
+	}
+	return q
+}
+
+func requestContext(ctx *gin.Context) context.Context {
+	if ctx != nil && ctx.Request != nil {
+		return ctx.Request.Context()
+	}
+	return context.Background()
+}
+
+const (
+	filterCtxKey = "grf:sqlq:filter"
+	orderCtxKey  = "grf:sqlq:order"
+)
+
+// appliedMod marks the query mod as applied, so the filter and the default ordering are only used
+// by the views applying them, like the list view
+type appliedMod struct {
+	key string
+}
+
+func (m appliedMod) Apply(ctx *gin.Context) {
+	ctx.Set(m.key, true)
+}
+
+func applied(ctx *gin.Context, key string) bool {
+	value, ok := ctx.Get(key)
+	return ok && value == true
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	bytesType   = reflect.TypeOf([]byte{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// modelColumns returns the fields of the model stored in columns: the fields of the basic types,
+// times, byte slices and sql.Scanner implementations, relations are skipped
+func modelColumns[Model any]() []column {
+	var m Model
+	columns := []column{}
+	for _, field := range reflect.VisibleFields(reflect.TypeOf(m)) {
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		name, included := models.FieldName(field)
+		if !included || !isColumnType(field.Type) {
+			continue
+		}
+		columns = append(columns, column{name: name, index: field.Index})
+	}
+	return columns
+}
+
+func isColumnType(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(scannerType) {
+		return true
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType || t == bytesType || reflect.PointerTo(t).Implements(scannerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// detectDialect detects the dialect using the package of the database driver, SQLite is used if
+// it's not recognized
+func detectDialect(db *sql.DB) Dialect {
+	driverType := reflect.TypeOf(db.Driver())
+	for driverType.Kind() == reflect.Pointer {
+		driverType = driverType.Elem()
+	}
+	driverPackage := driverType.PkgPath()
+	switch {
+	case strings.Contains(driverPackage, "pq") || strings.Contains(driverPackage, "pgx"):
+		return Postgres
+	case strings.Contains(driverPackage, "mysql"):
+		return MySQL
+	}
+	return SQLite
+}
@@ -0,0 +1,261 @@
+package sqlq
+
+import (
+	"database/sql"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type book struct {
+	ID       int64  `json:"id"`
+	Title    string `json:"title"`
+	Pages    int    `json:"pages"`
+	AuthorID int64  `json:"author_id"`
+}
+
+func setupDB(t *testing.T) *sql.DB {
+	gormDB, openErr := gorm.Open(sqlite.Open(":memory:"))
+	require.NoError(t, openErr)
+	db, dbErr := gormDB.DB()
+	require.NoError(t, dbErr)
+	// every connection to :memory: opens a different database
+	db.SetMaxOpenConns(1)
+	_, createErr := db.Exec(`CREATE TABLE books (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		title TEXT NOT NULL UNIQUE,
+		pages INTEGER NOT NULL DEFAULT 0,
+		author_id INTEGER NOT NULL DEFAULT 0
+	)`)
+	require.NoError(t, createErr)
+	return db
+}
+
+func newContext() *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", "/", nil)
+	return ctx
+}
+
+func TestCRUD(t *testing.T) {
+	// given
+	driver := New[book](setupDB(t), "books")
+	ctx := newContext()
+
+	// when
+	created, createErr := driver.CRUD().Create(ctx, models.InternalValue{"title": "Dune", "pages": 412})
+	updated, updateErr := driver.CRUD().Update(ctx, created, models.InternalValue{"pages": 500}, created["id"])
+	duplicate, duplicateErr := driver.CRUD().Create(ctx, models.InternalValue{"title": "Dune"})
+	destroyErr := driver.CRUD().Destroy(ctx, created["id"])
+	_, retrieveErr := driver.CRUD().Retrieve(ctx, created["id"])
+	secondDestroyErr := driver.CRUD().Destroy(ctx, created["id"])
+
+	// then
+	assert.NoError(t, createErr)
+	assert.Equal(t, models.InternalValue{"id": int64(1), "title": "Dune", "pages": 412, "author_id": int64(0)}, created)
+	assert.NoError(t, updateErr)
+	assert.Equal(t, 500, updated["pages"])
+	assert.Equal(t, "Dune", updated["title"])
+	assert.Nil(t, duplicate)
+	assert.ErrorIs(t, duplicateErr, common.ErrUniqueViolation)
+	assert.NoError(t, destroyErr)
+	assert.ErrorIs(t, retrieveErr, common.ErrorNotFound)
+	assert.ErrorIs(t, secondDestroyErr, common.ErrorNotFound)
+}
+
+type author struct {
+	Code int64  `json:"code"`
+	Name string `json:"name"`
+}
+
+func TestCreateWithPrimaryKey(t *testing.T) {
+	// given
+	db := setupDB(t)
+	_, createTableErr := db.Exec(`CREATE TABLE authors (code INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL)`)
+	require.NoError(t, createTableErr)
+	driver := New[author](db, "authors").WithPrimaryKey("code")
+
+	// when
+	created, createErr := driver.CRUD().Create(newContext(), models.InternalValue{"name": "Frank Herbert"})
+
+	// then
+	assert.NoError(t, createErr)
+	assert.Equal(t, models.InternalValue{"code": int64(1), "name": "Frank Herbert"}, created)
+}
+
+func TestList(t *testing.T) {
+	driver := New[book](setupDB(t), "books").WithOrderBy("-pages")
+	seedCtx := newContext()
+	for _, seed := range []models.InternalValue{
+		{"title": "Dune", "pages": 412, "author_id": 1},
+		{"title": "Children of Dune", "pages": 444, "author_id": 1},
+		{"title": "Neuromancer", "pages": 271, "author_id": 2},
+		{"title": "100%_done", "pages": 10, "author_id": 2},
+	} {
+		_, createErr := driver.CRUD().Create(seedCtx, seed)
+		require.NoError(t, createErr)
+	}
+
+	tests := []struct {
+		name          string
+		prepare       func(ctx *gin.Context)
+		expectedTitle []string
+		expectedCount int
+	}{
+		{
+			name:          "default ordering",
+			prepare:       func(ctx *gin.Context) { driver.Order().Apply(ctx) },
+			expectedTitle: []string{"Children of Dune", "Dune", "Neuromancer", "100%_done"},
+			expectedCount: 4,
+		},
+		{
+			name: "predicates and requested ordering",
+			prepare: func(ctx *gin.Context) {
+				driver.Order().Apply(ctx)
+				grfctx.AddPredicates(ctx, grfctx.Predicate{Field: "pages", Operator: grfctx.OperatorGt, Value: 300})
+				grfctx.SetOrdering(ctx, []grfctx.OrderBy{{Field: "title"}})
+			},
+			expectedTitle: []string{"Children of Dune", "Dune"},
+			expectedCount: 2,
+		},
+		{
+			name: "search escapes the wildcards",
+			prepare: func(ctx *gin.Context) {
+				grfctx.SetSearch(ctx, grfctx.Search{Fields: []string{"title"}, Terms: []string{"%_"}})
+			},
+			expectedTitle: []string{"100%_done"},
+			expectedCount: 1,
+		},
+		{
+			name: "parent scope and window",
+			prepare: func(ctx *gin.Context) {
+				driver.Order().Apply(ctx)
+				grfctx.SetParentScope(ctx, grfctx.ParentScope{Field: "author_id", Value: 1})
+				grfctx.SetWindow(ctx, grfctx.Window{Offset: 1, Limit: 1})
+			},
+			expectedTitle: []string{"Dune"},
+			expectedCount: 2,
+		},
+		{
+			name: "keyset window",
+			prepare: func(ctx *gin.Context) {
+				grfctx.SetWindow(ctx, grfctx.Window{Limit: 2, Keyset: &grfctx.Keyset{Field: "pages", After: 271}})
+			},
+			expectedTitle: []string{"Dune", "Children of Dune"},
+			expectedCount: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx := newContext()
+			tt.prepare(ctx)
+
+			// when
+			listed, listErr := driver.CRUD().List(ctx)
+			count, countErr := driver.Count(ctx)
+
+			// then
+			assert.NoError(t, listErr)
+			assert.NoError(t, countErr)
+			titles := []string{}
+			for _, listedBook := range listed {
+				titles = append(titles, listedBook["title"].(string))
+			}
+			assert.Equal(t, tt.expectedTitle, titles)
+			assert.Equal(t, tt.expectedCount, count)
+		})
+	}
+}
+
+func TestRandomWindow(t *testing.T) {
+	// given
+	driver := New[book](setupDB(t), "books")
+	for _, title := range []string{"Dune", "Neuromancer", "Hyperion"} {
+		_, createErr := driver.CRUD().Create(newContext(), models.InternalValue{"title": title})
+		require.NoError(t, createErr)
+	}
+	ctx := newContext()
+	grfctx.AddPredicates(ctx, grfctx.Predicate{Field: "title", Operator: grfctx.OperatorIn, Value: []any{"Dune", "Neuromancer"}})
+
+	// when
+	grfctx.SetWindow(ctx, grfctx.Window{Limit: 1, Random: true})
+	listed, listErr := driver.CRUD().List(ctx)
+	grfctx.SetWindow(ctx, grfctx.Window{Limit: 5, Random: true})
+	all, allErr := driver.CRUD().List(ctx)
+
+	// then
+	assert.NoError(t, listErr)
+	assert.Len(t, listed, 1)
+	assert.NoError(t, allErr)
+	titles := []any{}
+	for _, listedBook := range all {
+		titles = append(titles, listedBook["title"])
+	}
+	assert.ElementsMatch(t, []any{"Dune", "Neuromancer"}, titles)
+}
+
+type event struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func TestKeysetWindowByTime(t *testing.T) {
+	// given
+	db := setupDB(t)
+	_, createTableErr := db.Exec(`CREATE TABLE events (id INTEGER PRIMARY KEY AUTOINCREMENT, created_at DATETIME NOT NULL)`)
+	require.NoError(t, createTableErr)
+	driver := New[event](db, "events")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		_, createErr := driver.CRUD().Create(newContext(), models.InternalValue{"created_at": start.Add(time.Duration(i) * time.Hour)})
+		require.NoError(t, createErr)
+	}
+	ctx := newContext()
+	// the cursors carry the times as RFC3339 strings
+	grfctx.SetWindow(ctx, grfctx.Window{Limit: 2, Keyset: &grfctx.Keyset{
+		Field: "created_at", Descending: true, After: "2024-01-01T03:00:00Z",
+	}})
+
+	// when
+	listed, listErr := driver.CRUD().List(ctx)
+
+	// then
+	assert.NoError(t, listErr)
+	ids := []any{}
+	for _, listedEvent := range listed {
+		ids = append(ids, listedEvent["id"])
+	}
+	assert.Equal(t, []any{int64(3), int64(2)}, ids)
+}
+
+func TestAtomic(t *testing.T) {
+	// given
+	driver := New[book](setupDB(t), "books")
+	ctx := newContext()
+	failure := errors.New("failure")
+
+	// when
+	atomicErr := driver.Atomic(ctx, func() error {
+		if _, createErr := driver.CRUD().Create(ctx, models.InternalValue{"title": "Dune"}); createErr != nil {
+			return createErr
+		}
+		return failure
+	})
+	exists, existsErr := driver.Exists(ctx, map[string]any{"title": "Dune"})
+
+	// then
+	assert.ErrorIs(t, atomicErr, failure)
+	assert.NoError(t, existsErr)
+	assert.False(t, exists)
+}
@@ -0,0 +1,193 @@
+// Package resolvers provides fields, which values are resolved from other services, so
+// representations spanning multiple microservices don't require hand-written aggregation handlers.
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/views"
+)
+
+// BatchResolveFunc resolves multiple keys at once. The returned map is keyed by the keys formatted
+// with fmt.Sprint, keys missing in the map are considered not found.
+type BatchResolveFunc func(ctx context.Context, keys []any) (map[string]any, error)
+
+// Resolver resolves field values from an external service
+type Resolver struct {
+	name        string
+	resolve     BatchResolveFunc
+	timeout     time.Duration
+	fallback    any
+	hasFallback bool
+}
+
+// WithTimeout limits the time of a single call to the external service
+func (r *Resolver) WithTimeout(timeout time.Duration) *Resolver {
+	r.timeout = timeout
+	return r
+}
+
+// WithFallback sets the value used when the external service fails or doesn't know the key.
+// Without a fallback, failures are reported as errors of the field and unknown keys are represented
+// as null.
+func (r *Resolver) WithFallback(fallback any) *Resolver {
+	r.fallback = fallback
+	r.hasFallback = true
+	return r
+}
+
+// RepresentationFunc returns a fields.RepresentationFunc resolving the value of the keyField
+// internal value key. Keys prefetched by the Prefetch middleware are taken from the request
+// context, others are resolved one by one.
+func (r *Resolver) RepresentationFunc(keyField string) fields.RepresentationFunc {
+	return func(intVal models.InternalValue, _ string, ctx *gin.Context) (any, error) {
+		key := intVal[keyField]
+		if key == nil {
+			return r.notFound(), nil
+		}
+		prefetched, ok := r.prefetched(ctx)
+		if !ok {
+			resolved, resolveErr := r.call(ctx, []any{key})
+			prefetched = &prefetchResult{values: resolved, err: resolveErr}
+		}
+		if prefetched.err != nil {
+			if r.hasFallback {
+				return r.fallback, nil
+			}
+			return nil, prefetched.err
+		}
+		value, found := prefetched.values[fmt.Sprint(key)]
+		if !found {
+			return r.notFound(), nil
+		}
+		return value, nil
+	}
+}
+
+// Field returns a WithField option, that makes the field read-only and resolves its value
+// using RepresentationFunc
+func (r *Resolver) Field(keyField string) func(oldField fields.Field) {
+	return func(oldField fields.Field) {
+		oldField.WithReadOnly()
+		oldField.WithRepresentationFunc(r.RepresentationFunc(keyField))
+	}
+}
+
+// Prefetch returns a middleware resolving the keyField keys of all the listed objects in a single
+// batch call, instead of calling the external service for every object separately
+func (r *Resolver) Prefetch(keyField string) views.Middleware {
+	return func(next views.OperationFunc) views.OperationFunc {
+		return func(op *views.Operation) (*views.OperationResult, error) {
+			result, err := next(op)
+			if err != nil || op.Kind != views.OperationList {
+				return result, err
+			}
+			keys := []any{}
+			seen := map[string]bool{}
+			for _, intVal := range result.InternalValues {
+				key := intVal[keyField]
+				if key == nil || seen[fmt.Sprint(key)] {
+					continue
+				}
+				seen[fmt.Sprint(key)] = true
+				keys = append(keys, key)
+			}
+			resolved, resolveErr := r.call(op.Ctx, keys)
+			op.Ctx.Set(r.ctxKey(), &prefetchResult{values: resolved, err: resolveErr})
+			return result, nil
+		}
+	}
+}
+
+func (r *Resolver) call(ctx *gin.Context, keys []any) (map[string]any, error) {
+	if len(keys) == 0 {
+		return map[string]any{}, nil
+	}
+	parent := context.Background()
+	if ctx != nil && ctx.Request != nil {
+		parent = ctx.Request.Context()
+	}
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		parent, cancel = context.WithTimeout(parent, r.timeout)
+		defer cancel()
+	}
+	resolved, err := r.resolve(parent, keys)
+	if err != nil {
+		return nil, fmt.Errorf("resolver `%s`: %w", r.name, err)
+	}
+	return resolved, nil
+}
+
+func (r *Resolver) notFound() any {
+	if r.hasFallback {
+		return r.fallback
+	}
+	return nil
+}
+
+type prefetchResult struct {
+	values map[string]any
+	err    error
+}
+
+func (r *Resolver) ctxKey() string {
+	return "grf:resolvers:" + r.name
+}
+
+func (r *Resolver) prefetched(ctx *gin.Context) (*prefetchResult, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	anyVal, ok := ctx.Get(r.ctxKey())
+	if !ok {
+		return nil, false
+	}
+	result, ok := anyVal.(*prefetchResult)
+	return result, ok
+}
+
+// New creates a Resolver. The name is used in error messages and has to be unique, as it
+// identifies the prefetched values in the request context.
+func New(name string, resolve BatchResolveFunc) *Resolver {
+	return &Resolver{name: name, resolve: resolve}
+}
+
+// HTTPBatch returns a BatchResolveFunc calling an HTTP batch endpoint. The keys are POSTed as
+// `{"keys": [...]}` and the endpoint is expected to respond with a JSON object keyed by the keys,
+// for example `{"1": {"name": "John"}, "2": {"name": "Jane"}}`. gRPC or other services can be used
+// by implementing BatchResolveFunc directly.
+func HTTPBatch(client *http.Client, url string) BatchResolveFunc {
+	return func(ctx context.Context, keys []any) (map[string]any, error) {
+		body, marshalErr := json.Marshal(map[string]any{"keys": keys})
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+		req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if reqErr != nil {
+			return nil, reqErr
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, doErr := client.Do(req)
+		if doErr != nil {
+			return nil, doErr
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+		}
+		resolved := map[string]any{}
+		if decodeErr := json.NewDecoder(resp.Body).Decode(&resolved); decodeErr != nil {
+			return nil, decodeErr
+		}
+		return resolved, nil
+	}
+}
@@ -0,0 +1,152 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/glothriel/grf/pkg/views"
+	"github.com/stretchr/testify/assert"
+)
+
+type order struct {
+	ID     uint `json:"id"`
+	UserID uint `json:"user_id"`
+}
+
+func newOrdersViewSet(resolver *Resolver, prefetch bool) *gin.Engine {
+	viewset := views.NewViewSet[order](
+		"/orders",
+		queries.InMemory[order](order{UserID: 1}, order{UserID: 2}, order{UserID: 1}, order{UserID: 3}),
+		serializers.NewModelSerializer[order]().WithNewField(
+			fields.NewField[order]("user"),
+		).WithField("user", resolver.Field("user_id")),
+	).WithActions(views.ActionList, views.ActionRetrieve)
+	if prefetch {
+		viewset.WithMiddleware(resolver.Prefetch("user_id"))
+	}
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+	return r
+}
+
+func get(r *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+type usersService struct {
+	calls [][]any
+}
+
+func (s *usersService) resolve(_ context.Context, keys []any) (map[string]any, error) {
+	s.calls = append(s.calls, keys)
+	names := map[string]any{"1": "John", "2": "Jane"}
+	resolved := map[string]any{}
+	for _, key := range keys {
+		if name, ok := names[fmt.Sprint(key)]; ok {
+			resolved[fmt.Sprint(key)] = name
+		}
+	}
+	return resolved, nil
+}
+
+func TestPrefetchResolvesListInSingleBatch(t *testing.T) {
+	// given
+	users := &usersService{}
+	r := newOrdersViewSet(New("users", users.resolve), true)
+
+	// when
+	w := get(r, "/orders")
+
+	// then
+	var listed []map[string]any
+	assert.Equal(t, 200, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	assert.ElementsMatch(t, []map[string]any{
+		{"id": 1.0, "user_id": 1.0, "user": "John"},
+		{"id": 2.0, "user_id": 2.0, "user": "Jane"},
+		{"id": 3.0, "user_id": 1.0, "user": "John"},
+		{"id": 4.0, "user_id": 3.0, "user": nil},
+	}, listed)
+	assert.Len(t, users.calls, 1)
+	assert.ElementsMatch(t, []any{uint(1), uint(2), uint(3)}, users.calls[0])
+}
+
+func TestResolverWithoutPrefetchResolvesOneByOne(t *testing.T) {
+	// given
+	users := &usersService{}
+	r := newOrdersViewSet(New("users", users.resolve).WithFallback("unknown"), false)
+
+	// when
+	w := get(r, "/orders/4")
+
+	// then
+	assert.Equal(t, 200, w.Code)
+	assert.JSONEq(t, `{"id": 4, "user_id": 3, "user": "unknown"}`, w.Body.String())
+	assert.Equal(t, [][]any{{uint(3)}}, users.calls)
+}
+
+func TestResolverFailure(t *testing.T) {
+	// given
+	failing := func(ctx context.Context, keys []any) (map[string]any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	withFallback := newOrdersViewSet(New("users", failing).WithTimeout(time.Millisecond).WithFallback("unknown"), true)
+	withoutFallback := newOrdersViewSet(New("users", failing).WithTimeout(time.Millisecond), true)
+
+	// when
+	fallbackResponse := get(withFallback, "/orders/1")
+	errorResponse := get(withoutFallback, "/orders")
+
+	// then
+	assert.Equal(t, 200, fallbackResponse.Code)
+	assert.JSONEq(t, `{"id": 1, "user_id": 1, "user": "unknown"}`, fallbackResponse.Body.String())
+	assert.Equal(t, 400, errorResponse.Code)
+	assert.Contains(t, errorResponse.Body.String(), "resolver `users`: context deadline exceeded")
+}
+
+func TestHTTPBatch(t *testing.T) {
+	// given
+	var received map[string][]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`{"1": {"name": "John"}}`))
+	}))
+	defer server.Close()
+
+	// when
+	resolved, err := HTTPBatch(server.Client(), server.URL)(context.Background(), []any{1, 2})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]any{"keys": {1.0, 2.0}}, received)
+	assert.Equal(t, map[string]any{"1": map[string]any{"name": "John"}}, resolved)
+}
+
+func TestHTTPBatchUnexpectedStatus(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	// when
+	_, err := HTTPBatch(server.Client(), server.URL)(context.Background(), []any{1})
+
+	// then
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -0,0 +1,178 @@
+// Package saga coordinates writes spanning multiple viewsets or query drivers, for example creating
+// an order and decrementing the stock of its product. Every step has a compensating action, that
+// undoes it when one of the later steps fails, as the writes can't share a single transaction.
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/views"
+	"github.com/sirupsen/logrus"
+)
+
+// StepFunc performs or compensates a step. The value is the result of the operation the saga is
+// hooked to, for example the created order, or nil when the saga is run directly.
+type StepFunc func(ctx *gin.Context, value models.InternalValue) error
+
+type step struct {
+	name       string
+	do         StepFunc
+	compensate StepFunc
+}
+
+// Saga is a sequence of steps, that are either all applied or all compensated
+type Saga struct {
+	name  string
+	steps []step
+}
+
+// New creates an empty saga, the name is used in the errors and logs
+func New(name string) *Saga {
+	return &Saga{name: name}
+}
+
+// WithStep adds a step to the saga. The compensate function is called when any of the later steps
+// fails, it may be nil for steps, that don't need to be undone, like sending notifications at the
+// end of the saga.
+func (s *Saga) WithStep(name string, do, compensate StepFunc) *Saga {
+	s.steps = append(s.steps, step{name: name, do: do, compensate: compensate})
+	return s
+}
+
+// Error is returned when one of the steps fails. Err is the error of the failed step, it's
+// unwrapped, so the callers can check it using errors.Is and errors.As.
+type Error struct {
+	Saga string
+	Step string
+	Err  error
+	// CompensationErrors are the errors of the compensating actions, that failed. When not empty,
+	// the writes of the saga are only partially undone and may require manual intervention.
+	CompensationErrors []error
+}
+
+func (e *Error) Error() string {
+	message := fmt.Sprintf("saga `%s` failed at step `%s`: %s", e.Saga, e.Step, e.Err)
+	if len(e.CompensationErrors) == 0 {
+		return message
+	}
+	compensationErrors := make([]string, 0, len(e.CompensationErrors))
+	for _, err := range e.CompensationErrors {
+		compensationErrors = append(compensationErrors, err.Error())
+	}
+	return fmt.Sprintf("%s, compensation failed: %s", message, strings.Join(compensationErrors, "; "))
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Run performs the steps in order. When a step fails, the compensating actions of the already
+// performed steps are called in the reverse order and *Error is returned.
+func (s *Saga) Run(ctx *gin.Context, value models.InternalValue) error {
+	for i, current := range s.steps {
+		if err := current.do(ctx, value); err != nil {
+			return &Error{
+				Saga:               s.name,
+				Step:               current.name,
+				Err:                err,
+				CompensationErrors: s.compensate(ctx, value, s.steps[:i]),
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Saga) compensate(ctx *gin.Context, value models.InternalValue, performed []step) []error {
+	var compensationErrors []error
+	for i := len(performed) - 1; i >= 0; i-- {
+		if performed[i].compensate == nil {
+			continue
+		}
+		if err := performed[i].compensate(ctx, value); err != nil {
+			compensationErrors = append(
+				compensationErrors, fmt.Errorf("step `%s`: %w", performed[i].name, err),
+			)
+		}
+	}
+	return compensationErrors
+}
+
+// Hook returns a middleware running the saga after the create and update operations of the
+// viewset, with the stored value passed to the steps. When the saga fails, the write of the
+// viewset is compensated as well: created objects are destroyed and updated objects are restored.
+//
+// The error of the failed step is returned as the error of the action, so validation errors
+// returned by the steps are reported as 400 responses. When the compensation fails, *Error is
+// returned instead and logged.
+func (s *Saga) Hook() views.Middleware {
+	return func(next views.OperationFunc) views.OperationFunc {
+		return func(op *views.Operation) (*views.OperationResult, error) {
+			result, err := next(op)
+			if err != nil || (op.Kind != views.OperationCreate && op.Kind != views.OperationUpdate) {
+				return result, err
+			}
+			runErr := s.Run(op.Ctx, result.InternalValue)
+			var sagaErr *Error
+			if !errors.As(runErr, &sagaErr) {
+				return result, runErr
+			}
+			if undoErr := undo(next, op, result); undoErr != nil {
+				sagaErr.CompensationErrors = append(
+					sagaErr.CompensationErrors, fmt.Errorf("operation: %w", undoErr),
+				)
+			}
+			if len(sagaErr.CompensationErrors) > 0 {
+				logrus.WithField("saga", s.name).Error(sagaErr.Error())
+				return nil, sagaErr
+			}
+			return nil, sagaErr.Err
+		}
+	}
+}
+
+// undo compensates the write of the viewset using the rest of the middleware chain
+func undo(next views.OperationFunc, op *views.Operation, result *views.OperationResult) error {
+	if op.Kind == views.OperationCreate {
+		_, err := next(&views.Operation{
+			Ctx:       op.Ctx,
+			Action:    op.Action,
+			Kind:      views.OperationDestroy,
+			ModelName: op.ModelName,
+			ID:        result.InternalValue[grfctx.LookupField(op.Ctx)],
+		})
+		return err
+	}
+	// the changed fields are restored explicitly, as the drivers may skip the zero values of the
+	// updates, see grfctx.SetUpdatedFields
+	grfctx.SetUpdatedFields(op.Ctx, changedFields(op.OldInternalValue, result.InternalValue))
+	defer grfctx.SetUpdatedFields(op.Ctx, nil)
+	_, err := next(&views.Operation{
+		Ctx:              op.Ctx,
+		Action:           op.Action,
+		Kind:             views.OperationUpdate,
+		ModelName:        op.ModelName,
+		ID:               op.ID,
+		InternalValue:    op.OldInternalValue,
+		OldInternalValue: result.InternalValue,
+	})
+	return err
+}
+
+// changedFields returns the sorted names of the fields of the old value, that differ in the new one
+func changedFields(old, new models.InternalValue) []string {
+	fields := []string{}
+	for field, value := range old {
+		if newValue, ok := new[field]; !ok || !reflect.DeepEqual(value, newValue) {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
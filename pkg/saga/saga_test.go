@@ -0,0 +1,180 @@
+package saga
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/glothriel/grf/pkg/views"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type order struct {
+	ID        uint `json:"id"`
+	ProductID uint `json:"product_id"`
+	Quantity  int  `json:"quantity"`
+}
+
+type warehouse struct {
+	stock    map[uint]int
+	reserved []uint
+}
+
+func (w *warehouse) decrement(ctx *gin.Context, value models.InternalValue) error {
+	productID, quantity := value["product_id"].(uint), value["quantity"].(int)
+	if w.stock[productID] < quantity {
+		return &serializers.ValidationError{FieldErrors: map[string][]string{
+			"quantity": {"not enough items in stock"},
+		}}
+	}
+	w.stock[productID] -= quantity
+	return nil
+}
+
+func (w *warehouse) increment(ctx *gin.Context, value models.InternalValue) error {
+	w.stock[value["product_id"].(uint)] += value["quantity"].(int)
+	return nil
+}
+
+func newOrdersRouter(s *Saga) *gin.Engine {
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	views.NewViewSet[order](
+		"/orders", queries.InMemory[order](), serializers.NewModelSerializer[order](),
+	).WithActions(views.ActionList, views.ActionCreate).WithMiddleware(s.Hook()).Register(r)
+	return r
+}
+
+func request(r *gin.Engine, method string, body any) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	payload, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, "/orders", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestSagaHook(t *testing.T) {
+	tests := []struct {
+		name           string
+		quantity       int
+		chargeErr      error
+		expectedStatus int
+		expectedStock  int
+		expectedOrders int
+	}{
+		{
+			name:           "all steps succeed",
+			quantity:       2,
+			expectedStatus: http.StatusCreated,
+			expectedStock:  3,
+			expectedOrders: 1,
+		},
+		{
+			name:           "first step fails with validation error",
+			quantity:       6,
+			expectedStatus: http.StatusBadRequest,
+			expectedStock:  5,
+			expectedOrders: 0,
+		},
+		{
+			name:           "later step fails",
+			quantity:       2,
+			chargeErr:      errors.New("payment declined"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedStock:  5,
+			expectedOrders: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			w := &warehouse{stock: map[uint]int{1: 5}}
+			r := newOrdersRouter(New("place order").WithStep(
+				"decrement stock", w.decrement, w.increment,
+			).WithStep("charge", func(*gin.Context, models.InternalValue) error {
+				return tt.chargeErr
+			}, nil))
+
+			// when
+			response := request(r, http.MethodPost, map[string]any{"product_id": 1, "quantity": tt.quantity})
+
+			// then
+			assert.Equal(t, tt.expectedStatus, response.Code)
+			assert.Equal(t, tt.expectedStock, w.stock[1])
+			var orders []map[string]any
+			assert.NoError(t, json.Unmarshal(request(r, http.MethodGet, nil).Body.Bytes(), &orders))
+			assert.Len(t, orders, tt.expectedOrders)
+		})
+	}
+}
+
+func TestSagaRunCompensationErrors(t *testing.T) {
+	// given
+	compensated := []string{}
+	compensate := func(name string, err error) StepFunc {
+		return func(*gin.Context, models.InternalValue) error {
+			compensated = append(compensated, name)
+			return err
+		}
+	}
+	s := New("import").WithStep(
+		"first", func(*gin.Context, models.InternalValue) error { return nil }, compensate("first", nil),
+	).WithStep(
+		"second", func(*gin.Context, models.InternalValue) error { return nil }, compensate("second", errors.New("timeout")),
+	).WithStep(
+		"third", func(*gin.Context, models.InternalValue) error { return errors.New("boom") }, compensate("third", nil),
+	)
+
+	// when
+	err := s.Run(nil, nil)
+
+	// then
+	var sagaErr *Error
+	assert.ErrorAs(t, err, &sagaErr)
+	assert.Equal(t, "third", sagaErr.Step)
+	assert.Equal(t, []string{"second", "first"}, compensated)
+	assert.EqualError(t, err, "saga `import` failed at step `third`: boom, compensation failed: step `second`: timeout")
+}
+
+type profile struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	Nickname string `json:"nickname"`
+	Karma    int    `json:"karma"`
+}
+
+func TestSagaHookRestoresZeroValuesWithGorm(t *testing.T) {
+	// given
+	db, openErr := gorm.Open(sqlite.Open("file::memory:"))
+	require.NoError(t, openErr)
+	require.NoError(t, db.AutoMigrate(&profile{}))
+	require.NoError(t, db.Create(&profile{}).Error)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	views.NewModelViewSet[profile]("/profiles", queries.GORM[profile](db)).WithMiddleware(
+		New("update profile").WithStep("sync", func(*gin.Context, models.InternalValue) error {
+			return errors.New("sync failed")
+		}, nil).Hook(),
+	).Register(r)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/profiles/1", bytes.NewBufferString(`{"nickname": "neo", "karma": 5}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	// when
+	r.ServeHTTP(w, req)
+	var stored profile
+	readErr := db.First(&stored, 1).Error
+
+	// then
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NoError(t, readErr)
+	assert.Equal(t, profile{ID: 1}, stored)
+}
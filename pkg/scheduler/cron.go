@@ -0,0 +1,149 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when the job runs next
+type Schedule interface {
+	// Next returns the first time the job runs after the given time
+	Next(after time.Time) time.Time
+}
+
+// Every runs the job in fixed intervals, counted from the previous run
+func Every(interval time.Duration) Schedule {
+	return every(interval)
+}
+
+type every time.Duration
+
+func (e every) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(e))
+}
+
+// cronDescriptors are the shortcuts of the common expressions
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSchedule holds the allowed values of the fields as bit sets
+type cronSchedule struct {
+	minutes, hours, days, months, weekdays uint64
+	// The day of the month and the day of the week match either of them, unless one is `*`
+	daysRestricted, weekdaysRestricted bool
+}
+
+// ParseCron parses the standard five field cron expression: minute, hour, day of the month, month
+// and day of the week, for example `30 3 * * 1-5` runs at 3:30 on weekdays. The fields accept `*`,
+// values, ranges, lists and steps, like `*/15` or `1-10/2`, and the day of the week is 0-7, where
+// both 0 and 7 are Sunday. The @yearly, @monthly, @weekly, @daily and @hourly shortcuts are also
+// supported. The times are evaluated in the location of the time passed to Next.
+func ParseCron(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[expr]; ok {
+		expr = descriptor
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields", expr)
+	}
+	ranges := []struct{ min, max int }{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	bits := make([]uint64, len(parts))
+	for i, part := range parts {
+		parsed, parseErr := parseCronField(part, ranges[i].min, ranges[i].max)
+		if parseErr != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, parseErr)
+		}
+		bits[i] = parsed
+	}
+	// Sunday is both 0 and 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &cronSchedule{
+		minutes: bits[0], hours: bits[1], days: bits[2], months: bits[3], weekdays: bits[4],
+		daysRestricted: parts[2] != "*", weekdaysRestricted: parts[4] != "*",
+	}, nil
+}
+
+// MustParseCron is like ParseCron, but panics if the expression is invalid
+func MustParseCron(expr string) Schedule {
+	schedule, parseErr := ParseCron(expr)
+	if parseErr != nil {
+		panic(parseErr)
+	}
+	return schedule
+}
+
+func parseCronField(field string, minValue, maxValue int) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		valueRange, rawStep, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var stepErr error
+			if step, stepErr = strconv.Atoi(rawStep); stepErr != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", rawStep)
+			}
+		}
+		low, high := minValue, maxValue
+		if valueRange != "*" {
+			rawLow, rawHigh, isRange := strings.Cut(valueRange, "-")
+			var lowErr, highErr error
+			low, lowErr = strconv.Atoi(rawLow)
+			high = low
+			if isRange {
+				high, highErr = strconv.Atoi(rawHigh)
+			} else if hasStep {
+				high = maxValue
+			}
+			if lowErr != nil || highErr != nil || low < minValue || high > maxValue || low > high {
+				return 0, fmt.Errorf("invalid value %q, expected %d-%d", valueRange, minValue, maxValue)
+			}
+		}
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+// Next implements Schedule, searching up to five years ahead. Times skipped or repeated by the
+// daylight saving time changes follow the behaviour of time.Date.
+func (c *cronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case c.months&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case c.hours&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case c.minutes&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	day := c.days&(1<<t.Day()) != 0
+	weekday := c.weekdays&(1<<int(t.Weekday())) != 0
+	if c.daysRestricted && c.weekdaysRestricted {
+		return day || weekday
+	}
+	return day && weekday
+}
@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	after := time.Date(2024, 5, 1, 10, 17, 30, 0, time.UTC) // Wednesday
+	tests := []struct {
+		expr         string
+		expectedNext time.Time
+	}{
+		{expr: "* * * * *", expectedNext: time.Date(2024, 5, 1, 10, 18, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", expectedNext: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)},
+		{expr: "0 3 * * *", expectedNext: time.Date(2024, 5, 2, 3, 0, 0, 0, time.UTC)},
+		{expr: "30 9 * * 1-5", expectedNext: time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC)},
+		{expr: "0 0 * * 7", expectedNext: time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 12 15 * 1", expectedNext: time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)},
+		{expr: "0,30 8-10/2 * 6 *", expectedNext: time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)},
+		{expr: "@monthly", expectedNext: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", expectedNext: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			// when
+			schedule, parseErr := ParseCron(tt.expr)
+
+			// then
+			assert.NoError(t, parseErr)
+			assert.Equal(t, tt.expectedNext, schedule.Next(after))
+		})
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@often"} {
+		t.Run(expr, func(t *testing.T) {
+			// when
+			_, parseErr := ParseCron(expr)
+
+			// then
+			assert.Error(t, parseErr)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/crud"
+)
+
+// DriverJobFunc is the work of a job using a query driver
+type DriverJobFunc[Model any] func(ctx *gin.Context, queries *crud.CRUD[Model]) error
+
+// WithDriver adapts the function to a JobFunc, running it with a context prepared by the middleware
+// of the query driver, like the contexts of the requests, so the job can use the same queries as
+// the viewsets. Predicates and other request state can be set on the context using grfctx, for
+// example to list only the rows deleted more than 30 days ago.
+func WithDriver[Model any](driver queries.Driver[Model], fn DriverJobFunc[Model]) JobFunc {
+	return func(parent context.Context) error {
+		request := (&http.Request{Method: http.MethodGet, URL: &url.URL{}, Header: http.Header{}}).WithContext(parent)
+		ctx := &gin.Context{Request: request}
+		for _, middleware := range driver.Middleware() {
+			middleware(ctx)
+		}
+		return fn(ctx, driver.CRUD())
+	}
+}
@@ -0,0 +1,166 @@
+// Package scheduler runs recurring jobs, like purging soft-deleted rows or expiring stale exports,
+// in the process serving the API. The jobs can use the query drivers of the viewsets, see
+// WithDriver. Each job runs at most once at a time, the runs overlapping with the previous ones are
+// skipped. The scheduler doesn't coordinate multiple instances of the application, the jobs should
+// either be idempotent or be scheduled by a single instance.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrJobRunning is returned by Run if the previous run of the job didn't finish yet
+var ErrJobRunning = errors.New("the job is already running")
+
+// ErrUnknownJob is returned by Run for jobs, that weren't added to the scheduler
+var ErrUnknownJob = errors.New("unknown job")
+
+// JobFunc is the work of the job, the context is cancelled when the scheduler stops
+type JobFunc func(ctx context.Context) error
+
+// JobStats is a snapshot of the job metrics
+type JobStats struct {
+	// Runs is the total number of the finished runs, including the failed ones
+	Runs int64
+	// Failures is the total number of runs, that returned an error or panicked
+	Failures int64
+	// Skipped is the total number of runs skipped, because the previous run didn't finish yet
+	Skipped int64
+	// Running is true while the job runs
+	Running bool
+	// LastRun is the start time of the last finished run
+	LastRun time.Time
+	// LastDuration is the duration of the last finished run
+	LastDuration time.Duration
+	// LastError is the error of the last finished run, nil if it succeeded
+	LastError error
+}
+
+type job struct {
+	name     string
+	schedule Schedule
+	fn       JobFunc
+
+	mu    sync.Mutex
+	stats JobStats
+}
+
+// Scheduler runs the jobs according to their schedules
+type Scheduler struct {
+	jobs []*job
+	now  func() time.Time
+}
+
+// NewScheduler creates a Scheduler without jobs
+func NewScheduler() *Scheduler {
+	return &Scheduler{now: time.Now}
+}
+
+// WithJob adds the job, the names have to be unique. Use MustParseCron or Every to create the
+// schedule, for example MustParseCron("0 3 * * *") runs the job daily at 3:00.
+func (s *Scheduler) WithJob(name string, schedule Schedule, fn JobFunc) *Scheduler {
+	for _, existing := range s.jobs {
+		if existing.name == name {
+			logrus.Panicf("Job `%s` is already scheduled", name)
+		}
+	}
+	s.jobs = append(s.jobs, &job{name: name, schedule: schedule, fn: fn})
+	return s
+}
+
+// Start runs the jobs according to their schedules until the context is cancelled, waiting for
+// the running jobs to finish before returning
+func (s *Scheduler) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, j := range s.jobs {
+		wg.Add(1)
+		go func(j *job) {
+			defer wg.Done()
+			s.loop(ctx, j, &wg)
+		}(j)
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job, wg *sync.WaitGroup) {
+	for {
+		next := j.schedule.Next(s.now())
+		if next.IsZero() {
+			logrus.Warnf("Job `%s` has no upcoming runs", j.name)
+			return
+		}
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if runErr := s.run(ctx, j); runErr != nil && !errors.Is(runErr, ErrJobRunning) {
+				logrus.Errorf("Job `%s` failed: %s", j.name, runErr)
+			}
+		}()
+	}
+}
+
+// Run runs the job immediately, for example from a management command, returning its error. It
+// returns ErrJobRunning without running the job if it's already running.
+func (s *Scheduler) Run(ctx context.Context, name string) error {
+	for _, j := range s.jobs {
+		if j.name == name {
+			return s.run(ctx, j)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownJob, name)
+}
+
+func (s *Scheduler) run(ctx context.Context, j *job) (runErr error) {
+	j.mu.Lock()
+	if j.stats.Running {
+		j.stats.Skipped++
+		j.mu.Unlock()
+		logrus.Warnf("Skipping job `%s`, the previous run didn't finish yet", j.name)
+		return ErrJobRunning
+	}
+	j.stats.Running = true
+	j.mu.Unlock()
+
+	started := s.now()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			runErr = fmt.Errorf("job panicked: %v", recovered)
+		}
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		j.stats.Running = false
+		j.stats.Runs++
+		j.stats.LastRun = started
+		j.stats.LastDuration = s.now().Sub(started)
+		j.stats.LastError = runErr
+		if runErr != nil {
+			j.stats.Failures++
+		}
+	}()
+	return j.fn(ctx)
+}
+
+// Stats returns the current metrics of the jobs by their names, for example to export them to a
+// monitoring system
+func (s *Scheduler) Stats() map[string]JobStats {
+	stats := make(map[string]JobStats, len(s.jobs))
+	for _, j := range s.jobs {
+		j.mu.Lock()
+		stats[j.name] = j.stats
+		j.mu.Unlock()
+	}
+	return stats
+}
@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/crud"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerRunSkipsOverlappingRuns(t *testing.T) {
+	// given
+	started, release := make(chan struct{}), make(chan struct{})
+	scheduler := NewScheduler().WithJob("export", Every(time.Hour), func(ctx context.Context) error {
+		close(started)
+		<-release
+		return errors.New("export failed")
+	})
+	firstErr := make(chan error)
+	go func() {
+		firstErr <- scheduler.Run(context.Background(), "export")
+	}()
+	<-started
+
+	// when
+	overlappingErr := scheduler.Run(context.Background(), "export")
+	running := scheduler.Stats()["export"].Running
+	close(release)
+
+	// then
+	assert.ErrorIs(t, overlappingErr, ErrJobRunning)
+	assert.True(t, running)
+	assert.EqualError(t, <-firstErr, "export failed")
+	stats := scheduler.Stats()["export"]
+	assert.Equal(t, int64(1), stats.Runs)
+	assert.Equal(t, int64(1), stats.Failures)
+	assert.Equal(t, int64(1), stats.Skipped)
+	assert.False(t, stats.Running)
+	assert.EqualError(t, stats.LastError, "export failed")
+	assert.ErrorIs(t, scheduler.Run(context.Background(), "import"), ErrUnknownJob)
+}
+
+func TestSchedulerStart(t *testing.T) {
+	// given
+	var runs atomic.Int64
+	scheduler := NewScheduler().WithJob("tick", Every(10*time.Millisecond), func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}).WithJob("panic", Every(10*time.Millisecond), func(ctx context.Context) error {
+		panic("boom")
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+
+	// when
+	scheduler.Start(ctx)
+
+	// then
+	assert.GreaterOrEqual(t, runs.Load(), int64(2))
+	stats := scheduler.Stats()
+	assert.Equal(t, runs.Load(), stats["tick"].Runs)
+	assert.Equal(t, stats["panic"].Runs, stats["panic"].Failures)
+	assert.EqualError(t, stats["panic"].LastError, "job panicked: boom")
+}
+
+type purgeable struct {
+	ID      uint `json:"id"`
+	Expired bool `json:"expired"`
+}
+
+func TestWithDriver(t *testing.T) {
+	// given
+	driver := queries.InMemory(purgeable{Expired: true}, purgeable{}, purgeable{Expired: true})
+	purge := WithDriver(driver, func(ctx *gin.Context, q *crud.CRUD[purgeable]) error {
+		grfctx.AddPredicates(ctx, grfctx.Predicate{Field: "expired", Operator: grfctx.OperatorExact, Value: true})
+		expired, listErr := q.List(ctx)
+		if listErr != nil {
+			return listErr
+		}
+		for _, row := range expired {
+			if destroyErr := q.Destroy(ctx, row["id"]); destroyErr != nil {
+				return destroyErr
+			}
+		}
+		return nil
+	})
+
+	// when
+	err := NewScheduler().WithJob("purge", MustParseCron("@daily"), purge).Run(context.Background(), "purge")
+
+	// then
+	assert.NoError(t, err)
+	count, countErr := driver.Count(&gin.Context{})
+	assert.NoError(t, countErr)
+	assert.Equal(t, 1, count)
+}
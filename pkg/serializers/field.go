@@ -1,25 +1,35 @@
 package serializers
 
 import (
+	"fmt"
+
 	"github.com/gin-gonic/gin"
 	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/grfctx"
 	"github.com/glothriel/grf/pkg/models"
 )
 
 type SerializerField[Model any] struct {
-	fields.Field
+	*fields.ConcreteField[Model]
 	serializer Serializer
 }
 
+// ToRepresentation renders the nested object or the list of nested objects stored in the field
+// using the serializer, the reverse of ToInternalValue
 func (s *SerializerField[Model]) ToRepresentation(iv models.InternalValue, c *gin.Context) (any, error) {
-	fieldValue := iv[s.Name()]
-	asSlice, isSlice := fieldValue.([]any)
-	if isSlice {
-		result := make([]any, 0)
-		for _, item := range asSlice {
-			itemIV, isInternalValue := item.(models.InternalValue)
-			if !isInternalValue {
-				return nil, fields.NewErrorFieldIsNotPresentInPayload(s.Name())
+	switch typed := iv[s.Name()].(type) {
+	case nil:
+		return nil, nil
+	case models.InternalValue:
+		return s.serializer.ToRepresentation(typed, c)
+	case map[string]any:
+		return s.serializer.ToRepresentation(typed, c)
+	case []any:
+		result := make([]any, 0, len(typed))
+		for i, item := range typed {
+			itemIV, isObject := asInternalValue(item)
+			if !isObject {
+				return nil, fmt.Errorf("could not represent `%s[%d]`: expected an object, got `%T`", s.Name(), i, item)
 			}
 			serialized, err := s.serializer.ToRepresentation(itemIV, c)
 			if err != nil {
@@ -29,13 +39,62 @@ func (s *SerializerField[Model]) ToRepresentation(iv models.InternalValue, c *gi
 		}
 		return result, nil
 	}
-	return s.serializer.ToRepresentation(iv, c)
+	return nil, fmt.Errorf("could not represent `%s`: expected an object or a list of objects, got `%T`", s.Name(), iv[s.Name()])
+}
+
+func asInternalValue(value any) (models.InternalValue, bool) {
+	switch typed := value.(type) {
+	case models.InternalValue:
+		return typed, true
+	case map[string]any:
+		return typed, true
+	}
+	return nil, false
 }
 
+// ToInternalValue converts the nested object or the list of nested objects using the serializer. The
+// errors of the nested fields are returned as *ValidationError, keyed by their paths relative to the
+// field, like `street` or `[2].price`.
 func (s *SerializerField[Model]) ToInternalValue(raw map[string]any, c *gin.Context) (any, error) {
-	return s.serializer.ToInternalValue(raw, c)
+	value, present := raw[s.Name()]
+	if !present {
+		if s.IsRequired() && grfctx.CurrentAction(c) != grfctx.ActionPartialUpdate {
+			return nil, fields.ErrFieldRequired
+		}
+		return nil, fields.NewErrorFieldIsNotPresentInPayload(s.Name())
+	}
+	switch typed := value.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		return s.serializer.ToInternalValue(typed, c)
+	case []any:
+		result := make([]any, 0, len(typed))
+		fieldErrors := map[string][]string{}
+		for i, item := range typed {
+			path := fmt.Sprintf("[%d]", i)
+			itemMap, isMap := item.(map[string]any)
+			if !isMap {
+				fieldErrors[path] = append(fieldErrors[path], "expected an object")
+				continue
+			}
+			itemIV, err := s.serializer.ToInternalValue(itemMap, c)
+			if err != nil {
+				for nestedPath, messages := range nestedErrors(path, err) {
+					fieldErrors[nestedPath] = append(fieldErrors[nestedPath], messages...)
+				}
+				continue
+			}
+			result = append(result, itemIV)
+		}
+		if len(fieldErrors) > 0 {
+			return nil, &ValidationError{FieldErrors: fieldErrors}
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("expected an object or a list of objects, got `%T`", value)
 }
 
 func NewSerializerField[Model any](name string, serializer Serializer) fields.Field {
-	return &SerializerField[Model]{fields.NewField[Model](name), serializer}
+	return &SerializerField[Model]{fields.NewField[Model](name).(*fields.ConcreteField[Model]), serializer}
 }
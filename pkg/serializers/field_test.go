@@ -0,0 +1,105 @@
+package serializers
+
+import (
+	"testing"
+
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type nestedMockModel struct {
+	Street string `json:"street"`
+	Price  int    `json:"price"`
+}
+
+func TestSerializerFieldNestedErrorPaths(t *testing.T) {
+	tests := []struct {
+		name           string
+		raw            map[string]any
+		expectedIntVal models.InternalValue
+		expectedErrors map[string][]string
+	}{
+		{
+			name: "valid",
+			raw: map[string]any{
+				"foo": "bar", "address": map[string]any{"street": "Main"}, "items": []any{map[string]any{"street": "Side"}},
+			},
+			expectedIntVal: models.InternalValue{
+				"foo": "bar", "address": models.InternalValue{"street": "Main"},
+				"items": []any{models.InternalValue{"street": "Side"}},
+			},
+		},
+		{
+			name:           "invalid object",
+			raw:            map[string]any{"address": map[string]any{}},
+			expectedErrors: map[string][]string{"address.street": {"This field is required"}},
+		},
+		{
+			name: "invalid list elements",
+			raw: map[string]any{"items": []any{
+				map[string]any{"street": "Main"}, "Side", map[string]any{"street": "Main", "price": "cheap"},
+			}},
+			expectedErrors: map[string][]string{
+				"items[1]":       {"expected an object"},
+				"items[2].price": {"Error converting request value to internal value for type `int`: Expected a number, got a string"},
+			},
+		},
+		{
+			name:           "not an object",
+			raw:            map[string]any{"address": "Main"},
+			expectedErrors: map[string][]string{"address": {"expected an object or a list of objects, got `string`"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			nested := NewModelSerializer[nestedMockModel]().WithField("street", fields.Required())
+			serializer := NewModelSerializer[mockModel]().WithNewField(
+				NewSerializerField[nestedMockModel]("address", nested),
+			).WithNewField(
+				NewSerializerField[nestedMockModel]("items", nested),
+			)
+
+			// when
+			intVal, err := serializer.ToInternalValue(tt.raw, nil)
+
+			// then
+			if tt.expectedErrors != nil {
+				assert.Equal(t, &ValidationError{FieldErrors: tt.expectedErrors}, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedIntVal, intVal)
+		})
+	}
+}
+
+func TestSerializerFieldRepresentsTheValueOfTheField(t *testing.T) {
+	// given
+	nested := NewModelSerializer[nestedMockModel]()
+	serializer := NewModelSerializer[mockModel]().WithNewField(
+		NewSerializerField[nestedMockModel]("address", nested),
+	).WithNewField(
+		NewSerializerField[nestedMockModel]("items", nested),
+	)
+	raw := map[string]any{
+		"foo": "bar", "address": map[string]any{"street": "Main", "price": 1.0},
+		"items": []any{map[string]any{"street": "Side", "price": 2.0}},
+	}
+
+	// when
+	intVal, intValErr := serializer.ToInternalValue(raw, nil)
+	repr, reprErr := serializer.ToRepresentation(intVal, nil)
+	withoutAddress, withoutAddressErr := serializer.ToRepresentation(models.InternalValue{"foo": "bar", "address": nil}, nil)
+
+	// then
+	assert.NoError(t, intValErr)
+	assert.NoError(t, reprErr)
+	assert.Equal(t, Representation{
+		"foo": "bar", "address": Representation{"street": "Main", "price": 1},
+		"items": []any{Representation{"street": "Side", "price": 2}},
+	}, repr)
+	assert.NoError(t, withoutAddressErr)
+	assert.Nil(t, withoutAddress["address"])
+}
@@ -0,0 +1,121 @@
+package serializers
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// URLResolver builds the URLs of the objects and extracts the IDs from them, like views.URLs
+type URLResolver interface {
+	Reverse(ctx *gin.Context, name string, id any) (string, error)
+	Resolve(name, rawURL string) (string, error)
+}
+
+// HyperlinkedModelSerializer is a ModelSerializer rendering the `url` field with the URL of the
+// object and the related objects as their URLs instead of their raw IDs, so the clients can follow
+// the links without knowing the layout of the API. The URLs are built using the paths of the
+// viewsets registered in the resolver.
+type HyperlinkedModelSerializer[Model any] struct {
+	*ModelSerializer[Model]
+
+	resolver  URLResolver
+	relations map[string]string
+}
+
+// NewHyperlinkedModelSerializer creates a HyperlinkedModelSerializer with all the model fields and
+// the read-only `url` field linking to the object in the viewset registered under the name, like
+// NewHyperlinkedModelSerializer[Product](views.URLs, "product")
+func NewHyperlinkedModelSerializer[Model any](resolver URLResolver, name string) *HyperlinkedModelSerializer[Model] {
+	s := &HyperlinkedModelSerializer[Model]{
+		ModelSerializer: NewModelSerializer[Model](),
+		resolver:        resolver,
+		relations:       map[string]string{},
+	}
+	s.WithNewField(fields.NewField[Model]("url").WithRepresentationFunc(
+		func(intVal models.InternalValue, _ string, ctx *gin.Context) (any, error) {
+			return resolver.Reverse(ctx, name, intVal["id"])
+		},
+	).WithReadOnly())
+	return s
+}
+
+// WithHyperlinkedRelation renders the field holding the ID of the related object, like `author_id`,
+// as the URL of the object in the viewset registered under the name. The field accepts the URLs in
+// the request bodies, the URLs not pointing to the viewset are rejected.
+func (s *HyperlinkedModelSerializer[Model]) WithHyperlinkedRelation(field, name string) *HyperlinkedModelSerializer[Model] {
+	toInternalValue, detectErr := s.toInternalValueDetector.ToInternalValue(field)
+	if detectErr != nil {
+		var m Model
+		logrus.Panicf("Could not register hyperlinked relation `%s` on model `%s`: %s", field, reflect.TypeOf(m), detectErr)
+	}
+	s.WithField(field, func(oldField fields.Field) {
+		oldField.WithRepresentationFunc(func(intVal models.InternalValue, fieldName string, ctx *gin.Context) (any, error) {
+			if intVal[fieldName] == nil {
+				return nil, nil
+			}
+			return s.resolver.Reverse(ctx, name, intVal[fieldName])
+		}).WithInternalValueFunc(func(raw map[string]any, fieldName string, ctx *gin.Context) (any, error) {
+			rawURL, present := raw[fieldName]
+			if !present {
+				return nil, fields.NewErrorFieldIsNotPresentInPayload(fieldName)
+			}
+			if rawURL == nil {
+				return toInternalValue(raw, fieldName, ctx)
+			}
+			urlString, ok := rawURL.(string)
+			if !ok {
+				return nil, errors.New("expected a URL")
+			}
+			id, resolveErr := s.resolver.Resolve(name, urlString)
+			if resolveErr != nil {
+				return nil, resolveErr
+			}
+			return s.idToInternalValue(toInternalValue, fieldName, id, ctx)
+		})
+	})
+	s.relations[field] = name
+	return s
+}
+
+// idToInternalValue converts the ID extracted from the URL using the converter of the model field,
+// passing it like decoded from JSON: as a number, if the field doesn't accept strings
+func (s *HyperlinkedModelSerializer[Model]) idToInternalValue(
+	toInternalValue fields.InternalValueFunc, fieldName, id string, ctx *gin.Context,
+) (any, error) {
+	internalValue, convertErr := toInternalValue(map[string]any{fieldName: id}, fieldName, ctx)
+	if convertErr == nil {
+		return internalValue, nil
+	}
+	number, parseErr := strconv.ParseFloat(id, 64)
+	if parseErr != nil {
+		return nil, convertErr
+	}
+	return toInternalValue(map[string]any{fieldName: number}, fieldName, ctx)
+}
+
+// Describe returns the metadata of the fields, the `url` field and the hyperlinked relations are
+// strings
+func (s *HyperlinkedModelSerializer[Model]) Describe() map[string]*FieldMetadata {
+	described := s.ModelSerializer.Describe()
+	for _, field := range append([]string{"url"}, mapKeys(s.relations)...) {
+		if metadata, ok := described[s.externalName(field)]; ok {
+			metadata.Type = "string"
+			metadata.Validators = append(metadata.Validators, "url")
+		}
+	}
+	return described
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,207 @@
+package serializers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// ListValidationError holds the validation errors of a collection, like the body of a bulk request
+type ListValidationError struct {
+	// Errors are the errors of the elements, indexed like the elements. Valid elements have no errors.
+	Errors []map[string][]string
+	// ListErrors are the errors of the whole collection, like too many elements
+	ListErrors []string
+}
+
+func (e *ListValidationError) Error() string {
+	invalid := 0
+	for _, elementErrors := range e.Errors {
+		if len(elementErrors) > 0 {
+			invalid++
+		}
+	}
+	message := fmt.Sprintf("%d of %d elements are invalid", invalid, len(e.Errors))
+	if len(e.ListErrors) > 0 {
+		message = strings.Join(e.ListErrors, ", ") + ", " + message
+	}
+	return message
+}
+
+// AddError adds the error of the field of the element at the index
+func (e *ListValidationError) AddError(index int, field, message string) {
+	e.Errors[index][field] = append(e.Errors[index][field], message)
+}
+
+// Valid returns true if neither the collection nor its elements have errors
+func (e *ListValidationError) Valid() bool {
+	for _, elementErrors := range e.Errors {
+		if len(elementErrors) > 0 {
+			return false
+		}
+	}
+	return len(e.ListErrors) == 0
+}
+
+// ListValidator validates a whole collection, for example to detect duplicates across the elements.
+// The elements, that failed the conversion, are nil.
+type ListValidator interface {
+	ValidateList(items []models.InternalValue, errs *ListValidationError)
+}
+
+type simpleListValidator struct {
+	validateFunc func([]models.InternalValue, *ListValidationError)
+}
+
+func (v *simpleListValidator) ValidateList(items []models.InternalValue, errs *ListValidationError) {
+	v.validateFunc(items, errs)
+}
+
+// NewSimpleListValidator creates a ListValidator from the function
+func NewSimpleListValidator(validateFunc func(items []models.InternalValue, errs *ListValidationError)) ListValidator {
+	return &simpleListValidator{validateFunc: validateFunc}
+}
+
+// lengthValidator is implemented by the list validators checking only the number of the elements,
+// they're run before the elements are converted, so oversized collections are rejected cheaply
+type lengthValidator interface {
+	ValidateLength(length int, errs *ListValidationError)
+}
+
+type maxItemsValidator struct {
+	max int
+}
+
+func (v maxItemsValidator) ValidateLength(length int, errs *ListValidationError) {
+	if length > v.max {
+		errs.ListErrors = append(errs.ListErrors, fmt.Sprintf("expected at most %d elements", v.max))
+	}
+}
+
+func (v maxItemsValidator) ValidateList(items []models.InternalValue, errs *ListValidationError) {
+	v.ValidateLength(len(items), errs)
+}
+
+// MaxItems rejects collections with more than max elements, before any of the elements is converted
+func MaxItems(max int) ListValidator {
+	return maxItemsValidator{max: max}
+}
+
+// MinItems rejects collections with less than min elements
+func MinItems(min int) ListValidator {
+	return NewSimpleListValidator(func(items []models.InternalValue, errs *ListValidationError) {
+		if len(items) < min {
+			errs.ListErrors = append(errs.ListErrors, fmt.Sprintf("expected at least %d elements", min))
+		}
+	})
+}
+
+// UniqueItems rejects the elements with the same values of the fields as one of the previous
+// elements, the fields are the internal names
+func UniqueItems(fieldNames ...string) ListValidator {
+	return NewSimpleListValidator(func(items []models.InternalValue, errs *ListValidationError) {
+		seen := map[string]int{}
+		for i, item := range items {
+			if item == nil {
+				continue
+			}
+			values := make([]string, len(fieldNames))
+			for j, fieldName := range fieldNames {
+				values[j] = fmt.Sprintf("%#v", item[fieldName])
+			}
+			key := strings.Join(values, "\x00")
+			first, duplicate := seen[key]
+			if !duplicate {
+				seen[key] = i
+				continue
+			}
+			for _, fieldName := range fieldNames {
+				errs.AddError(i, fieldName, fmt.Sprintf("duplicates the element %d", first))
+			}
+		}
+	})
+}
+
+// ListSerializer converts collections of elements using the child serializer, validating the whole
+// collection using the list validators, for example in the bulk views
+type ListSerializer[Model any] struct {
+	child      Serializer
+	validators []ListValidator
+}
+
+// NewListSerializer creates a ListSerializer converting the elements with the child
+func NewListSerializer[Model any](child Serializer, validators ...ListValidator) *ListSerializer[Model] {
+	return &ListSerializer[Model]{child: child, validators: validators}
+}
+
+// Child returns the serializer of the elements
+func (s *ListSerializer[Model]) Child() Serializer {
+	return s.child
+}
+
+// AddValidator adds a validator of the collections
+func (s *ListSerializer[Model]) AddValidator(validator ListValidator) *ListSerializer[Model] {
+	s.validators = append(s.validators, validator)
+	return s
+}
+
+// ToInternalValues converts the elements, that have to be JSON objects, and validates the collection.
+// The returned error is a *ListValidationError with the errors of every element, if the
+// collection or any of its elements is invalid.
+func (s *ListSerializer[Model]) ToInternalValues(raw []any, ctx *gin.Context) ([]models.InternalValue, error) {
+	lengthErrs := &ListValidationError{Errors: []map[string][]string{}}
+	for _, validator := range s.validators {
+		if lengthValidator, ok := validator.(lengthValidator); ok {
+			lengthValidator.ValidateLength(len(raw), lengthErrs)
+		}
+	}
+	if !lengthErrs.Valid() {
+		return nil, lengthErrs
+	}
+	internalValues := make([]models.InternalValue, len(raw))
+	errs := &ListValidationError{Errors: make([]map[string][]string, len(raw))}
+	for i, rawElement := range raw {
+		errs.Errors[i] = map[string][]string{}
+		rawMap, ok := rawElement.(map[string]any)
+		if !ok {
+			errs.AddError(i, "all", "expected an object")
+			continue
+		}
+		internalValue, fromRawErr := s.child.ToInternalValue(rawMap, ctx)
+		var fieldsErr *ValidationError
+		if errors.As(fromRawErr, &fieldsErr) {
+			errs.Errors[i] = fieldsErr.FieldErrors
+			continue
+		}
+		if fromRawErr != nil {
+			errs.AddError(i, "all", fromRawErr.Error())
+			continue
+		}
+		internalValues[i] = internalValue
+	}
+	for _, validator := range s.validators {
+		if _, checkedLength := validator.(lengthValidator); !checkedLength {
+			validator.ValidateList(internalValues, errs)
+		}
+	}
+	if !errs.Valid() {
+		return nil, errs
+	}
+	return internalValues, nil
+}
+
+// ToRepresentations converts the internal values using the child serializer
+func (s *ListSerializer[Model]) ToRepresentations(internalValues []models.InternalValue, ctx *gin.Context) ([]Representation, error) {
+	representations := make([]Representation, 0, len(internalValues))
+	for _, internalValue := range internalValues {
+		representation, serializeErr := s.child.ToRepresentation(internalValue, ctx)
+		if serializeErr != nil {
+			return nil, serializeErr
+		}
+		representations = append(representations, representation)
+	}
+	return representations, nil
+}
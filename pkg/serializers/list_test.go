@@ -0,0 +1,52 @@
+package serializers
+
+import (
+	"testing"
+
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListSerializerToInternalValues(t *testing.T) {
+	// given
+	serializer := NewListSerializer[anotherMockModel](
+		NewModelSerializer[anotherMockModel](), MinItems(1), UniqueItems("foo", "bar"),
+	)
+
+	// when
+	internalValues, err := serializer.ToInternalValues([]any{
+		map[string]any{"foo": "1", "bar": "1"},
+		map[string]any{"foo": "1", "bar": "2"},
+	}, nil)
+	_, duplicatedErr := serializer.ToInternalValues([]any{
+		map[string]any{"foo": "1", "bar": "1"},
+		"foo",
+		map[string]any{"foo": "1", "bar": "1"},
+	}, nil)
+	_, emptyErr := serializer.ToInternalValues([]any{}, nil)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []models.InternalValue{{"foo": "1", "bar": "1"}, {"foo": "1", "bar": "2"}}, internalValues)
+	assert.Equal(t, &ListValidationError{Errors: []map[string][]string{
+		{},
+		{"all": {"expected an object"}},
+		{"foo": {"duplicates the element 0"}, "bar": {"duplicates the element 0"}},
+	}}, duplicatedErr)
+	assert.EqualError(t, duplicatedErr, "2 of 3 elements are invalid")
+	assert.EqualError(t, emptyErr, "expected at least 1 elements, 0 of 0 elements are invalid")
+}
+
+func TestListSerializerChecksMaxItemsBeforeConvertingElements(t *testing.T) {
+	// given
+	serializer := NewListSerializer[anotherMockModel](NewModelSerializer[anotherMockModel](), MaxItems(2))
+
+	// when
+	_, err := serializer.ToInternalValues([]any{"foo", "bar", "baz"}, nil)
+
+	// then
+	assert.Equal(t, &ListValidationError{
+		Errors:     []map[string][]string{},
+		ListErrors: []string{"expected at most 2 elements"},
+	}, err)
+}
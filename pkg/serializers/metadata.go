@@ -0,0 +1,236 @@
+package serializers
+
+import (
+	"database/sql/driver"
+	"encoding"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// FieldMetadata describes a serializer field, for example in OPTIONS responses
+type FieldMetadata struct {
+	// Type is the JSON type of the field: boolean, integer, number, string, datetime, array,
+	// object or field, if the type is unknown
+	Type      string `json:"type"`
+	Required  bool   `json:"required"`
+	ReadOnly  bool   `json:"read_only"`
+	WriteOnly bool   `json:"write_only"`
+	// CreateOnly fields are writable only when creating the objects
+	CreateOnly bool     `json:"create_only,omitempty"`
+	Validators []string `json:"validators,omitempty"`
+	// Choices are the only values accepted by the field, if the validators limit them
+	Choices []any `json:"choices,omitempty"`
+	// Default is the value used by the database or the schema if the field is omitted
+	Default     any    `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+	Example     any    `json:"example,omitempty"`
+}
+
+// Describer is implemented by serializers, that can describe their fields
+type Describer interface {
+	Describe() map[string]*FieldMetadata
+}
+
+// ValidatorDescriber is implemented by validators, that can add their constraints to the
+// descriptions of the fields
+type ValidatorDescriber interface {
+	DescribeFields(fields map[string]*FieldMetadata)
+}
+
+// Describe returns the metadata of the serializer's fields, keyed by their external names. The
+// types are detected using the types of the model fields.
+func (s *ModelSerializer[Model]) Describe() map[string]*FieldMetadata {
+	var m Model
+	modelFields := map[string]reflect.StructField{}
+	for _, field := range reflect.VisibleFields(reflect.TypeOf(m)) {
+		if name, included := models.FieldName(field); included && !field.Anonymous {
+			modelFields[name] = field
+		}
+	}
+	described := make(map[string]*FieldMetadata, len(s.Fields))
+	for name, field := range s.Fields {
+		fieldType := "field"
+		var defaultValue any
+		if modelField, ok := modelFields[name]; ok {
+			fieldType = jsonType(modelField.Type)
+			defaultValue = gormDefault(modelField, fieldType)
+		}
+		metadata := &FieldMetadata{
+			Type:       fieldType,
+			ReadOnly:   field.IsReadable() && !field.IsWritable(),
+			WriteOnly:  field.IsWritable() && !field.IsReadable(),
+			CreateOnly: s.createOnly[name],
+		}
+		if defaulted, ok := field.(fields.DefaultedField); ok {
+			metadata.Required = defaulted.IsRequired()
+			if fieldDefault := defaulted.Default(); fieldDefault != nil {
+				defaultValue = fieldDefault
+			}
+		}
+		metadata.Default = defaultValue
+		if documented, ok := field.(fields.DocumentedField); ok {
+			metadata.Description, metadata.Example = documented.Description(), documented.Example()
+		}
+		described[s.externalName(name)] = metadata
+	}
+	return described
+}
+
+// Describe returns the metadata of the child serializer, including the constraints of the
+// validators implementing ValidatorDescriber
+func (s *ValidatingSerializer[Model]) Describe() map[string]*FieldMetadata {
+	describer, ok := s.child.(Describer)
+	if !ok {
+		return map[string]*FieldMetadata{}
+	}
+	described := describer.Describe()
+	for _, validator := range s.validators {
+		if validatorDescriber, ok := validator.(ValidatorDescriber); ok {
+			validatorDescriber.DescribeFields(described)
+		}
+	}
+	return described
+}
+
+// DescribeFields adds the go-playground rules to the validators of the fields, the fields with the
+// `required` rule are marked as required
+func (v *goPlaygroundValidator[Model]) DescribeFields(fields map[string]*FieldMetadata) {
+	for fieldName, rule := range v.rules {
+		field, ok := fields[fieldName]
+		if !ok {
+			continue
+		}
+		ruleString, ok := rule.(string)
+		if !ok {
+			continue
+		}
+		for _, tag := range strings.Split(ruleString, ",") {
+			if tag == "required" {
+				field.Required = true
+				continue
+			}
+			if choices, ok := strings.CutPrefix(tag, "oneof="); ok {
+				for _, choice := range strings.Fields(choices) {
+					field.Choices = append(field.Choices, typedValue(field.Type, choice))
+				}
+			}
+			field.Validators = append(field.Validators, tag)
+		}
+	}
+}
+
+// DescribeFields marks the fields required by the schema as required and adds the enums, defaults,
+// descriptions and examples of the schema's properties
+func (v *jsonSchemaValidator) DescribeFields(fields map[string]*FieldMetadata) {
+	for _, fieldName := range v.schema.Required {
+		if field, ok := fields[fieldName]; ok {
+			field.Required = true
+		}
+	}
+	for fieldName, property := range v.schema.Properties {
+		field, ok := fields[fieldName]
+		if !ok {
+			continue
+		}
+		if len(property.Enum) > 0 {
+			field.Choices = property.Enum
+		}
+		if property.Default != nil {
+			field.Default = property.Default
+		}
+		if field.Description == "" {
+			field.Description = property.Description
+		}
+		if field.Example == nil && len(property.Examples) > 0 {
+			field.Example = property.Examples[0]
+		}
+	}
+}
+
+// gormDefault returns the value of the `default` option of the gorm tag, database functions like
+// `now()` are skipped, as their values are not known up front
+func gormDefault(field reflect.StructField, fieldType string) any {
+	for _, option := range strings.Split(field.Tag.Get("gorm"), ";") {
+		key, value, _ := strings.Cut(option, ":")
+		if !strings.EqualFold(strings.TrimSpace(key), "default") || strings.Contains(value, "(") {
+			continue
+		}
+		return typedValue(fieldType, strings.Trim(strings.TrimSpace(value), "'"))
+	}
+	return nil
+}
+
+// typedValue converts the value from a tag to the JSON type of the field, the value is returned
+// unchanged if it can't be converted
+func typedValue(fieldType, value string) any {
+	switch fieldType {
+	case "integer":
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	case "number":
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	case "boolean":
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return value
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	driverValuerType    = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	byteSliceType       = reflect.TypeOf([]byte{})
+	jsonTypesByBaseKind = map[reflect.Kind]string{
+		reflect.Bool:    "boolean",
+		reflect.Int:     "integer",
+		reflect.Int8:    "integer",
+		reflect.Int16:   "integer",
+		reflect.Int32:   "integer",
+		reflect.Int64:   "integer",
+		reflect.Uint:    "integer",
+		reflect.Uint8:   "integer",
+		reflect.Uint16:  "integer",
+		reflect.Uint32:  "integer",
+		reflect.Uint64:  "integer",
+		reflect.Float32: "number",
+		reflect.Float64: "number",
+		reflect.String:  "string",
+		reflect.Slice:   "array",
+		reflect.Array:   "array",
+		reflect.Map:     "object",
+		reflect.Struct:  "object",
+	}
+)
+
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return "datetime"
+	case t == byteSliceType:
+		return "string"
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return "string"
+	case t.Kind() == reflect.Struct && t.Implements(driverValuerType):
+		// sql.NullString and similar types are represented as their values
+		if t.NumField() == 2 && t.Field(1).Name == "Valid" {
+			return jsonType(t.Field(0).Type)
+		}
+	}
+	if jsonType, ok := jsonTypesByBaseKind[t.Kind()]; ok {
+		return jsonType
+	}
+	return "field"
+}
@@ -0,0 +1,65 @@
+package serializers
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type describedTypesModel struct {
+	ID       uuid.UUID                 `json:"id"`
+	Nickname sql.NullString            `json:"nickname"`
+	Score    float64                   `json:"score"`
+	Tags     models.SliceField[string] `json:"tags"`
+	Extra    map[string]string         `json:"extra"`
+	Active   bool                      `json:"active"`
+	Password string                    `json:"password"`
+}
+
+func TestModelSerializerDescribe(t *testing.T) {
+	// given
+	serializer := NewValidatingSerializer[describedTypesModel](
+		NewModelSerializer[describedTypesModel]().
+			WithField("password", func(oldField fields.Field) { oldField.WithWriteOnly() }).
+			WithNewField(fields.NewField[describedTypesModel]("computed").WithReadOnly()),
+		NewJSONSchemaValidator(map[string]any{"type": "object", "required": []string{"password"}}),
+	)
+
+	// when
+	described := serializer.Describe()
+
+	// then
+	assert.Equal(t, map[string]*FieldMetadata{
+		"id":       {Type: "string", ReadOnly: true},
+		"nickname": {Type: "string"},
+		"score":    {Type: "number"},
+		"tags":     {Type: "array"},
+		"extra":    {Type: "object"},
+		"active":   {Type: "boolean"},
+		"password": {Type: "string", Required: true, WriteOnly: true},
+		"computed": {Type: "field", ReadOnly: true},
+	}, described)
+}
+
+func TestJSONSchemaValidatorDescribesChoicesAndDefaults(t *testing.T) {
+	// given
+	serializer := NewValidatingSerializer[describedTypesModel](
+		NewModelSerializer[describedTypesModel](),
+		NewJSONSchemaValidator(map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"nickname": map[string]any{"type": "string", "enum": []any{"ace", "rookie"}, "default": "rookie"},
+			},
+		}),
+	)
+
+	// when
+	described := serializer.Describe()
+
+	// then
+	assert.Equal(t, &FieldMetadata{Type: "string", Choices: []any{"ace", "rookie"}, Default: "rookie"}, described["nickname"])
+}
@@ -1,12 +1,17 @@
 package serializers
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/glothriel/grf/pkg/detectors"
 	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/ids"
 	"github.com/glothriel/grf/pkg/models"
 	"github.com/sirupsen/logrus"
 )
@@ -16,9 +21,23 @@ type ModelSerializer[Model any] struct {
 
 	toRepresentationDetector detectors.ToRepresentationDetector[Model]
 	toInternalValueDetector  detectors.ToInternalValueDetector
+	namingStrategy           NamingStrategy
+	createOnly               map[string]bool
+	// externalNames are the names set using WithSource, keyed by the internal names
+	externalNames   map[string]string
+	validationFuncs []ValidationFunc
+	// aliases are the deprecated external names set using WithAlias, keyed by the aliases
+	aliases    map[string]string
+	aliasUsage map[string]*atomic.Int64
 }
 
+// ValidationFunc validates the internal value as a whole, for example to compare its fields. It can
+// return a *ValidationError to report the errors of the specific fields, keyed by their internal
+// names, other errors are reported under the `all` key.
+type ValidationFunc func(intVal models.InternalValue, ctx *gin.Context) error
+
 func (s *ModelSerializer[Model]) ToInternalValue(raw map[string]any, ctx *gin.Context) (models.InternalValue, error) {
+	raw = s.toInternalNames(raw)
 	intVMap := make(map[string]any)
 	superfluousFields := make([]string, 0)
 
@@ -39,10 +58,20 @@ func (s *ModelSerializer[Model]) ToInternalValue(raw map[string]any, ctx *gin.Co
 			if isMissingFieldErr {
 				continue
 			}
-			return nil, &ValidationError{FieldErrors: map[string][]string{k: {err.Error()}}}
+			return nil, &ValidationError{FieldErrors: nestedErrors(s.externalName(k), err)}
+		}
+		if _, present := raw[k]; present && s.createOnly[k] && isUpdate(ctx) && !equalsStored(ctx, k, intV) {
+			return nil, &ValidationError{FieldErrors: map[string][]string{
+				s.externalName(k): {fmt.Sprintf("Field `%s` can only be set when creating", s.externalName(k))},
+			}}
 		}
 		intVMap[k] = intV
 	}
+	if id, ok := intVMap["id"].(string); ok {
+		if invalidErr := ids.Validate[Model](id); invalidErr != nil {
+			return nil, &ValidationError{FieldErrors: map[string][]string{s.externalName("id"): {invalidErr.Error()}}}
+		}
+	}
 	if len(superfluousFields) > 0 {
 		errMap := map[string][]string{}
 		for _, field := range superfluousFields {
@@ -50,6 +79,13 @@ func (s *ModelSerializer[Model]) ToInternalValue(raw map[string]any, ctx *gin.Co
 		}
 		return nil, &ValidationError{FieldErrors: errMap}
 	}
+	validated := models.InternalValue(intVMap)
+	if stored, ok := grfctx.Stored(ctx); ok && isUpdate(ctx) {
+		validated = models.InternalValue(stored).Merge(validated, models.MergeReplace)
+	}
+	if validateErr := s.Validate(validated, ctx); validateErr != nil {
+		return nil, validateErr
+	}
 	return intVMap, nil
 }
 
@@ -68,19 +104,159 @@ func (s *ModelSerializer[Model]) ToRepresentation(intVal models.InternalValue, c
 			}
 			return nil, &ValidationError{
 				FieldErrors: map[string][]string{
-					field.Name(): {
+					s.externalName(field.Name()): {
 						err.Error(),
 					},
 				},
 			}
 		}
-		raw[field.Name()] = value
+		raw[s.externalName(field.Name())] = value
 	}
 	return raw, nil
 }
 
+// WithNamingStrategy changes the names of the fields in the request and response payloads, for
+// example to convert a snake_case model to a camelCase API without touching the struct tags.
+// Fields are still referenced by their internal names in the other serializer methods.
+func (s *ModelSerializer[Model]) WithNamingStrategy(strategy NamingStrategy) *ModelSerializer[Model] {
+	s.namingStrategy = strategy
+	return s
+}
+
+// WithSource exposes the field with the source internal name under the external name in the request
+// and response payloads, so the API can evolve without renaming the struct fields or the columns,
+// for example WithSource("displayName", "name"). It takes precedence over the naming strategy.
+func (s *ModelSerializer[Model]) WithSource(externalName, source string) *ModelSerializer[Model] {
+	s.WithField(source, func(fields.Field) {})
+	if s.externalNames == nil {
+		s.externalNames = map[string]string{}
+	}
+	s.externalNames[source] = externalName
+	return s
+}
+
+// WithAlias keeps accepting the field under the deprecated name in the request payloads, after it
+// was renamed, for example WithAlias("name", "full_name") after WithSource("name", "full_name")
+// hid the old name. The responses only use the current name. If the payload contains both names,
+// the current one wins. AliasUsage counts the payloads using the aliases, so the old names can be
+// removed once the clients migrate.
+func (s *ModelSerializer[Model]) WithAlias(fieldName, alias string) *ModelSerializer[Model] {
+	s.WithField(fieldName, func(fields.Field) {})
+	if s.aliases == nil {
+		s.aliases = map[string]string{}
+		s.aliasUsage = map[string]*atomic.Int64{}
+	}
+	s.aliases[alias] = fieldName
+	s.aliasUsage[alias] = &atomic.Int64{}
+	return s
+}
+
+// AliasUsage returns the number of payloads, that used each of the aliases, for example to export
+// them to a monitoring system
+func (s *ModelSerializer[Model]) AliasUsage() map[string]int64 {
+	usage := make(map[string]int64, len(s.aliasUsage))
+	for alias, counter := range s.aliasUsage {
+		usage[alias] = counter.Load()
+	}
+	return usage
+}
+
+func (s *ModelSerializer[Model]) externalName(fieldName string) string {
+	if externalName, ok := s.externalNames[fieldName]; ok {
+		return externalName
+	}
+	if s.namingStrategy == nil {
+		return fieldName
+	}
+	return s.namingStrategy(fieldName)
+}
+
+func (s *ModelSerializer[Model]) toInternalNames(raw map[string]any) map[string]any {
+	raw = s.resolveAliases(raw)
+	if s.namingStrategy == nil && len(s.externalNames) == 0 {
+		return raw
+	}
+	externalToInternal := make(map[string]string, len(s.Fields))
+	for fieldName := range s.Fields {
+		externalToInternal[s.externalName(fieldName)] = fieldName
+	}
+	renamed := make(map[string]any, len(raw))
+	for k, v := range raw {
+		if internalName, ok := externalToInternal[k]; ok {
+			renamed[internalName] = v
+			continue
+		}
+		if _, hasSource := s.externalNames[k]; hasSource {
+			// The fields exposed using WithSource are not accepted under their internal names
+			continue
+		}
+		renamed[k] = v
+	}
+	return renamed
+}
+
+// resolveAliases renames the aliases present in the payload to the current external names
+func (s *ModelSerializer[Model]) resolveAliases(raw map[string]any) map[string]any {
+	if len(s.aliases) == 0 {
+		return raw
+	}
+	var resolved map[string]any
+	for alias, fieldName := range s.aliases {
+		value, present := raw[alias]
+		if !present {
+			continue
+		}
+		if resolved == nil {
+			resolved = make(map[string]any, len(raw))
+			for k, v := range raw {
+				resolved[k] = v
+			}
+		}
+		delete(resolved, alias)
+		s.aliasUsage[alias].Add(1)
+		externalName := s.externalName(fieldName)
+		if _, hasCurrent := raw[externalName]; !hasCurrent {
+			resolved[externalName] = value
+		}
+	}
+	if resolved == nil {
+		return raw
+	}
+	return resolved
+}
+
+// Validate runs the validation functions after the conversion of the fields, all of them run and
+// their errors are merged
 func (s *ModelSerializer[Model]) Validate(intVal models.InternalValue, ctx *gin.Context) error {
-	return nil
+	merged := &ValidationError{FieldErrors: map[string][]string{}}
+	for _, validationFunc := range s.validationFuncs {
+		validateErr := validationFunc(intVal, ctx)
+		if validateErr == nil {
+			continue
+		}
+		var fieldsErr *ValidationError
+		if !errors.As(validateErr, &fieldsErr) {
+			merged.FieldErrors["all"] = append(merged.FieldErrors["all"], validateErr.Error())
+			continue
+		}
+		for fieldName, messages := range fieldsErr.FieldErrors {
+			merged.FieldErrors[s.externalName(fieldName)] = append(merged.FieldErrors[s.externalName(fieldName)], messages...)
+		}
+	}
+	if len(merged.FieldErrors) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// WithValidationFunc adds a validation of the whole internal value, run after the conversion of the
+// fields, for example to check that `end_date` is after `start_date`. The updates are validated
+// merged into the stored object, see grfctx.Stored, so the functions see the stored values of the
+// fields missing in partial updates. The fields can still be missing when the serializer is used
+// outside of the viewsets, so the functions should skip the checks of the missing fields.
+func (s *ModelSerializer[Model]) WithValidationFunc(validationFunc ValidationFunc) *ModelSerializer[Model] {
+	s.validationFuncs = append(s.validationFuncs, validationFunc)
+	return s
 }
 
 func (s *ModelSerializer[Model]) WithNewField(field fields.Field) *ModelSerializer[Model] {
@@ -98,6 +274,61 @@ func (s *ModelSerializer[Model]) WithField(name string, updateFunc func(oldField
 	return s
 }
 
+// ReadOnlyFields makes the fields read-only, they're included in the responses, but ignored in the
+// request bodies
+func (s *ModelSerializer[Model]) ReadOnlyFields(names ...string) *ModelSerializer[Model] {
+	for _, name := range names {
+		s.WithField(name, func(oldField fields.Field) { oldField.WithReadOnly() })
+	}
+	return s
+}
+
+// WriteOnlyFields makes the fields write-only, like passwords, they're accepted in the request
+// bodies, but never included in the responses
+func (s *ModelSerializer[Model]) WriteOnlyFields(names ...string) *ModelSerializer[Model] {
+	for _, name := range names {
+		s.WithField(name, func(oldField fields.Field) { oldField.WithWriteOnly() })
+	}
+	return s
+}
+
+// CreateOnlyFields makes the fields writable only when creating the objects, like usernames or
+// slugs, update and partial update requests changing them are rejected with a validation error,
+// the values equal to the stored ones, see grfctx.Stored, are accepted
+func (s *ModelSerializer[Model]) CreateOnlyFields(names ...string) *ModelSerializer[Model] {
+	if s.createOnly == nil {
+		s.createOnly = map[string]bool{}
+	}
+	for _, name := range names {
+		s.WithField(name, func(oldField fields.Field) { oldField.WithReadWrite() })
+		s.createOnly[name] = true
+	}
+	return s
+}
+
+// equalsStored checks if the value equals the one of the object being updated, so the clients
+// sending back the whole representation don't have to drop the create-only fields
+func equalsStored(ctx *gin.Context, name string, value any) bool {
+	stored, ok := grfctx.Stored(ctx)
+	if !ok {
+		return false
+	}
+	storedValue, ok := stored[name]
+	if !ok {
+		return false
+	}
+	if storedTime, isTime := storedValue.(time.Time); isTime {
+		valueTime, isTime := value.(time.Time)
+		return isTime && storedTime.Equal(valueTime)
+	}
+	return reflect.DeepEqual(storedValue, value)
+}
+
+func isUpdate(ctx *gin.Context) bool {
+	action := grfctx.CurrentAction(ctx)
+	return action == grfctx.ActionUpdate || action == grfctx.ActionPartialUpdate
+}
+
 func (s *ModelSerializer[Model]) WithModelFields(passedFields []string) *ModelSerializer[Model] {
 
 	s.Fields = make(map[string]fields.Field)
@@ -137,10 +368,15 @@ func NewModelSerializer[Model any]() *ModelSerializer[Model] {
 }
 
 func NewModelSerializerWithFields[Model any](fieldList []string) *ModelSerializer[Model] {
-	return (&ModelSerializer[Model]{
+	s := (&ModelSerializer[Model]{
 		toRepresentationDetector: detectors.DefaultToRepresentationDetector[Model](),
 		toInternalValueDetector:  detectors.DefaultToInternalValueDetector[Model](),
 	}).WithModelFields(
 		fieldList,
-	).WithField("id", func(oldField fields.Field) { oldField.WithReadOnly() })
+	)
+	// Models without IDs, like the ones describing query parameters, are allowed
+	if _, hasID := s.Fields["id"]; !hasID {
+		return s
+	}
+	return s.WithField("id", func(oldField fields.Field) { oldField.WithReadOnly() })
 }
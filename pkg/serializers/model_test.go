@@ -7,6 +7,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/glothriel/grf/pkg/detectors"
 	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/ids"
 	"github.com/glothriel/grf/pkg/models"
 	"github.com/stretchr/testify/assert"
 )
@@ -258,3 +260,266 @@ func TestWithModelFieldsPanicsWhenRepresentationDetectorErr(t *testing.T) {
 		serializer.WithModelFields([]string{"foo"})
 	})
 }
+
+type partiallyTaggedMockModel struct {
+	ID       string `json:"id"`
+	UserName string
+	Nickname string `json:"nickname,omitempty"`
+	Secret   string `json:"-"`
+}
+
+func TestModelSerializerPartiallyTaggedModel(t *testing.T) {
+	// given
+	serializer := NewModelSerializer[partiallyTaggedMockModel]()
+
+	// when
+	withNickname, withNicknameErr := serializer.ToRepresentation(
+		models.InternalValue{"id": "1", "user_name": "foo", "nickname": "bar"}, nil,
+	)
+	withoutNickname, withoutNicknameErr := serializer.ToRepresentation(
+		models.InternalValue{"id": "1", "user_name": "foo", "nickname": ""}, nil,
+	)
+
+	// then
+	assert.ElementsMatch(t, []string{"id", "user_name", "nickname"}, detectors.Fields[partiallyTaggedMockModel]())
+	assert.NoError(t, withNicknameErr)
+	assert.Equal(t, Representation{"id": "1", "user_name": "foo", "nickname": "bar"}, withNickname)
+	assert.NoError(t, withoutNicknameErr)
+	assert.Equal(t, Representation{"id": "1", "user_name": "foo"}, withoutNickname)
+}
+
+func TestModelSerializerReadOnlyAndWriteOnlyFields(t *testing.T) {
+	// given
+	serializer := NewModelSerializer[anotherMockModel]().ReadOnlyFields("foo").WriteOnlyFields("bar")
+
+	// when
+	intVal, intValErr := serializer.ToInternalValue(map[string]any{"foo": "1", "bar": "2"}, nil)
+	repr, reprErr := serializer.ToRepresentation(models.InternalValue{"id": "1", "foo": "1", "bar": "2"}, nil)
+
+	// then
+	assert.NoError(t, intValErr)
+	assert.Equal(t, models.InternalValue{"bar": "2"}, intVal)
+	assert.NoError(t, reprErr)
+	assert.Equal(t, Representation{"id": "1", "foo": "1"}, repr)
+	assert.Panics(t, func() { NewModelSerializer[anotherMockModel]().ReadOnlyFields("baz") })
+}
+
+func TestModelSerializerCreateOnlyFields(t *testing.T) {
+	serializer := NewModelSerializer[anotherMockModel]().CreateOnlyFields("foo")
+
+	for _, tt := range []struct {
+		name        string
+		action      grfctx.Action
+		expectedErr bool
+	}{
+		{"create", grfctx.ActionCreate, false},
+		{"update", grfctx.ActionUpdate, true},
+		{"partial update", grfctx.ActionPartialUpdate, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx := &gin.Context{}
+			grfctx.Set(ctx, grfctx.Metadata{Action: tt.action})
+			grfctx.SetStored(ctx, map[string]any{"id": "1", "foo": "0", "bar": "0"})
+
+			// when
+			intVal, intValErr := serializer.ToInternalValue(map[string]any{"foo": "1", "bar": "2"}, ctx)
+			withoutFoo, withoutFooErr := serializer.ToInternalValue(map[string]any{"bar": "2"}, ctx)
+			unchanged, unchangedErr := serializer.ToInternalValue(map[string]any{"foo": "0", "bar": "2"}, ctx)
+
+			// then
+			if tt.expectedErr {
+				assert.Equal(t, &ValidationError{FieldErrors: map[string][]string{
+					"foo": {"Field `foo` can only be set when creating"},
+				}}, intValErr)
+			} else {
+				assert.NoError(t, intValErr)
+				assert.Equal(t, models.InternalValue{"foo": "1", "bar": "2"}, intVal)
+			}
+			assert.NoError(t, withoutFooErr)
+			assert.Equal(t, models.InternalValue{"bar": "2"}, withoutFoo)
+			assert.NoError(t, unchangedErr)
+			assert.Equal(t, models.InternalValue{"foo": "0", "bar": "2"}, unchanged)
+			assert.True(t, serializer.Describe()["foo"].CreateOnly)
+		})
+	}
+}
+
+func TestModelSerializerFieldDefaultsAndRequired(t *testing.T) {
+	// given
+	serializer := NewModelSerializer[anotherMockModel]().
+		WithField("foo", fields.Required()).
+		WithField("bar", fields.DefaultValue("baz"))
+
+	updateCtx := &gin.Context{}
+	grfctx.Set(updateCtx, grfctx.Metadata{Action: grfctx.ActionUpdate})
+
+	// when
+	intVal, intValErr := serializer.ToInternalValue(map[string]any{"foo": "1"}, nil)
+	updated, updateErr := serializer.ToInternalValue(map[string]any{"foo": "1"}, updateCtx)
+	_, missingErr := serializer.ToInternalValue(map[string]any{"bar": "2"}, nil)
+	described := serializer.Describe()
+
+	// then
+	assert.NoError(t, intValErr)
+	assert.Equal(t, models.InternalValue{"foo": "1", "bar": "baz"}, intVal)
+	assert.NoError(t, updateErr)
+	assert.Equal(t, models.InternalValue{"foo": "1"}, updated)
+	assert.Equal(t, &ValidationError{FieldErrors: map[string][]string{"foo": {"This field is required"}}}, missingErr)
+	assert.True(t, described["foo"].Required)
+	assert.Equal(t, "baz", described["bar"].Default)
+}
+
+func TestModelSerializerWithValidationFunc(t *testing.T) {
+	// given
+	serializer := NewModelSerializer[camelCaseMockModel]().WithNamingStrategy(CamelCaseNaming).WithValidationFunc(
+		func(intVal models.InternalValue, _ *gin.Context) error {
+			if intVal["created_by"] == intVal["user_name"] {
+				return &ValidationError{FieldErrors: map[string][]string{"created_by": {"must differ from the user name"}}}
+			}
+			return nil
+		},
+	).WithValidationFunc(func(intVal models.InternalValue, _ *gin.Context) error {
+		if intVal["created_by"] == "" {
+			return errors.New("created_by is required")
+		}
+		return nil
+	})
+
+	// when
+	valid, validErr := serializer.ToInternalValue(map[string]any{"createdBy": "a", "userName": "b"}, nil)
+	_, sameErr := serializer.ToInternalValue(map[string]any{"createdBy": "", "userName": ""}, nil)
+
+	// then
+	assert.NoError(t, validErr)
+	assert.Equal(t, models.InternalValue{"created_by": "a", "user_name": "b"}, valid)
+	assert.Equal(t, &ValidationError{FieldErrors: map[string][]string{
+		"createdBy": {"must differ from the user name"},
+		"all":       {"created_by is required"},
+	}}, sameErr)
+}
+
+func TestModelSerializerValidatesUpdatesMergedIntoStoredObject(t *testing.T) {
+	// given
+	serializer := NewModelSerializer[camelCaseMockModel]().WithValidationFunc(
+		func(intVal models.InternalValue, _ *gin.Context) error {
+			if intVal["created_by"] == intVal["user_name"] {
+				return &ValidationError{FieldErrors: map[string][]string{"created_by": {"must differ from the user name"}}}
+			}
+			return nil
+		},
+	)
+	ctx := &gin.Context{}
+	grfctx.Set(ctx, grfctx.Metadata{Action: grfctx.ActionPartialUpdate})
+	grfctx.SetStored(ctx, models.InternalValue{"created_by": "a", "user_name": "b"})
+
+	// when
+	valid, validErr := serializer.ToInternalValue(map[string]any{"created_by": "c"}, ctx)
+	_, sameErr := serializer.ToInternalValue(map[string]any{"created_by": "b"}, ctx)
+
+	// then
+	assert.NoError(t, validErr)
+	assert.Equal(t, models.InternalValue{"created_by": "c"}, valid)
+	assert.Equal(t, &ValidationError{FieldErrors: map[string][]string{
+		"created_by": {"must differ from the user name"},
+	}}, sameErr)
+}
+
+type clientIDMockModel struct {
+	ID string `json:"id"`
+}
+
+func TestModelSerializerValidatesIDsUsingGenerator(t *testing.T) {
+	// given
+	ids.Register[clientIDMockModel](ids.ULID())
+	serializer := NewModelSerializer[clientIDMockModel]().CreateOnlyFields("id")
+
+	// when
+	valid, validErr := serializer.ToInternalValue(map[string]any{"id": "01HF8Z3V9Q7XK2M4N6P8R0S2T4"}, nil)
+	_, invalidErr := serializer.ToInternalValue(map[string]any{"id": "1"}, nil)
+
+	// then
+	assert.NoError(t, validErr)
+	assert.Equal(t, models.InternalValue{"id": "01HF8Z3V9Q7XK2M4N6P8R0S2T4"}, valid)
+	assert.Equal(t, &ValidationError{FieldErrors: map[string][]string{
+		"id": {"invalid ID: expected a ULID"},
+	}}, invalidErr)
+}
+
+type camelCaseMockModel struct {
+	ID        string `json:"id"`
+	CreatedBy string `json:"created_by"`
+	UserName  string
+}
+
+func TestModelSerializerWithNamingStrategy(t *testing.T) {
+	// given
+	serializer := NewModelSerializer[camelCaseMockModel]().WithNamingStrategy(CamelCaseNaming)
+
+	// when
+	repr, reprErr := serializer.ToRepresentation(
+		models.InternalValue{"id": "1", "created_by": "foo", "user_name": "bar"}, nil,
+	)
+	intVal, intValErr := serializer.ToInternalValue(map[string]any{"createdBy": "foo", "userName": "bar"}, nil)
+
+	// then
+	assert.NoError(t, reprErr)
+	assert.Equal(t, Representation{"id": "1", "createdBy": "foo", "userName": "bar"}, repr)
+	assert.NoError(t, intValErr)
+	assert.Equal(t, models.InternalValue{"created_by": "foo", "user_name": "bar"}, intVal)
+}
+
+func TestModelSerializerWithSource(t *testing.T) {
+	// given
+	serializer := NewModelSerializer[camelCaseMockModel]().
+		WithNamingStrategy(CamelCaseNaming).
+		WithSource("author", "created_by")
+
+	// when
+	repr, reprErr := serializer.ToRepresentation(
+		models.InternalValue{"id": "1", "created_by": "foo", "user_name": "bar"}, nil,
+	)
+	intVal, intValErr := serializer.ToInternalValue(map[string]any{"author": "foo", "userName": "bar"}, nil)
+	internalNameIntVal, internalNameErr := serializer.ToInternalValue(map[string]any{"created_by": "foo"}, nil)
+
+	// then
+	assert.NoError(t, reprErr)
+	assert.Equal(t, Representation{"id": "1", "author": "foo", "userName": "bar"}, repr)
+	assert.NoError(t, intValErr)
+	assert.Equal(t, models.InternalValue{"created_by": "foo", "user_name": "bar"}, intVal)
+	assert.NoError(t, internalNameErr)
+	assert.Equal(t, models.InternalValue{}, internalNameIntVal)
+	assert.Contains(t, serializer.Describe(), "author")
+	assert.Panics(t, func() { NewModelSerializer[camelCaseMockModel]().WithSource("author", "creator") })
+}
+
+func TestModelSerializerWithAlias(t *testing.T) {
+	// given
+	serializer := NewModelSerializer[camelCaseMockModel]().
+		WithSource("author", "created_by").
+		WithAlias("created_by", "created_by").
+		WithAlias("created_by", "creator")
+
+	// when
+	aliasIntVal, aliasErr := serializer.ToInternalValue(map[string]any{"creator": "foo"}, nil)
+	bothIntVal, bothErr := serializer.ToInternalValue(map[string]any{"author": "foo", "created_by": "bar"}, nil)
+	repr, reprErr := serializer.ToRepresentation(models.InternalValue{"id": "1", "created_by": "foo", "user_name": "bar"}, nil)
+
+	// then
+	assert.NoError(t, aliasErr)
+	assert.Equal(t, models.InternalValue{"created_by": "foo"}, aliasIntVal)
+	assert.NoError(t, bothErr)
+	assert.Equal(t, models.InternalValue{"created_by": "foo"}, bothIntVal)
+	assert.NoError(t, reprErr)
+	assert.Equal(t, Representation{"id": "1", "author": "foo", "user_name": "bar"}, repr)
+	assert.Equal(t, map[string]int64{"created_by": 1, "creator": 1}, serializer.AliasUsage())
+	assert.Panics(t, func() { NewModelSerializer[camelCaseMockModel]().WithAlias("creator", "author") })
+}
+
+func TestNamingStrategies(t *testing.T) {
+	assert.Equal(t, "createdAt", CamelCaseNaming("created_at"))
+	assert.Equal(t, "userId", CamelCaseNaming("UserID"))
+	assert.Equal(t, "created-at", KebabCaseNaming("createdAt"))
+	assert.Equal(t, "created_at", SnakeCaseNaming("created-at"))
+	assert.Equal(t, "id", CamelCaseNaming("id"))
+}
@@ -0,0 +1,40 @@
+package serializers
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// NamingStrategy derives the external (request and response payload) name of a field from its
+// internal name, which is either taken from the struct tag or is the snake_cased Go field name.
+type NamingStrategy func(fieldName string) string
+
+// SnakeCaseNaming uses snake_case external field names, e.g. `created_at`
+func SnakeCaseNaming(fieldName string) string {
+	return strings.Join(nameWords(fieldName), "_")
+}
+
+// KebabCaseNaming uses kebab-case external field names, e.g. `created-at`
+func KebabCaseNaming(fieldName string) string {
+	return strings.Join(nameWords(fieldName), "-")
+}
+
+// CamelCaseNaming uses camelCase external field names, e.g. `createdAt`
+func CamelCaseNaming(fieldName string) string {
+	words := nameWords(fieldName)
+	for i := 1; i < len(words); i++ {
+		runes := []rune(words[i])
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, "")
+}
+
+// nameWords splits a field name written in any of the supported conventions into lowercase words
+func nameWords(fieldName string) []string {
+	return strings.FieldsFunc(models.SnakeCase(fieldName), func(r rune) bool {
+		return r == '_' || r == '-'
+	})
+}
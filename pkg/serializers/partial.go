@@ -0,0 +1,21 @@
+package serializers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// IsPartial returns true when the request is a partial update (PATCH). Partial updates only
+// contain the fields, that the client wants to change, so serializers convert and validate only
+// these fields and the view merges them with the stored object.
+func IsPartial(ctx *gin.Context) bool {
+	return grfctx.CurrentAction(ctx) == grfctx.ActionPartialUpdate
+}
+
+// PartialValidator is implemented by validators, that can validate internal values of partial
+// updates, which lack the fields absent in the request body. ValidatingSerializer uses it for
+// partial updates instead of Validate.
+type PartialValidator interface {
+	ValidatePartial(models.InternalValue) error
+}
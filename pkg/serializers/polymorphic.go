@@ -0,0 +1,114 @@
+package serializers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// PolymorphicSerializer converts heterogeneous objects, like the payment methods of different kinds
+// stored in one table, using the child serializer selected by the value of the discriminator
+// field, for example `type`. The discriminator is required in the request bodies, including the
+// partial updates.
+type PolymorphicSerializer struct {
+	discriminator string
+	children      map[string]Serializer
+}
+
+// NewPolymorphicSerializer creates a PolymorphicSerializer without types, the discriminator is the
+// name of the field selecting the child serializer
+func NewPolymorphicSerializer(discriminator string) *PolymorphicSerializer {
+	return &PolymorphicSerializer{discriminator: discriminator, children: map[string]Serializer{}}
+}
+
+// WithType converts the objects with the value of the discriminator using the serializer
+func (s *PolymorphicSerializer) WithType(value string, serializer Serializer) *PolymorphicSerializer {
+	s.children[value] = serializer
+	return s
+}
+
+func (s *PolymorphicSerializer) ToInternalValue(raw map[string]any, ctx *gin.Context) (models.InternalValue, error) {
+	value, child, childErr := s.child(raw[s.discriminator])
+	if childErr != nil {
+		return nil, childErr
+	}
+	internalValue, convertErr := child.ToInternalValue(raw, ctx)
+	if convertErr != nil {
+		return nil, convertErr
+	}
+	if _, ok := internalValue[s.discriminator]; !ok {
+		internalValue[s.discriminator] = value
+	}
+	return internalValue, nil
+}
+
+func (s *PolymorphicSerializer) ToRepresentation(intVal models.InternalValue, ctx *gin.Context) (Representation, error) {
+	value, child, childErr := s.child(intVal[s.discriminator])
+	if childErr != nil {
+		return nil, childErr
+	}
+	representation, convertErr := child.ToRepresentation(intVal, ctx)
+	if convertErr != nil {
+		return nil, convertErr
+	}
+	if _, ok := representation[s.discriminator]; !ok {
+		representation[s.discriminator] = value
+	}
+	return representation, nil
+}
+
+// Describe returns the fields of all the child serializers implementing Describer, the
+// discriminator is required and its choices are the types
+func (s *PolymorphicSerializer) Describe() map[string]*FieldMetadata {
+	described := map[string]*FieldMetadata{}
+	for _, value := range s.types() {
+		describer, ok := s.children[value].(Describer)
+		if !ok {
+			continue
+		}
+		for name, field := range describer.Describe() {
+			if _, exists := described[name]; !exists {
+				described[name] = field
+			}
+		}
+	}
+	discriminator, ok := described[s.discriminator]
+	if !ok {
+		discriminator = &FieldMetadata{Type: "string"}
+		described[s.discriminator] = discriminator
+	}
+	discriminator.Required = true
+	discriminator.Choices = []any{}
+	for _, value := range s.types() {
+		discriminator.Choices = append(discriminator.Choices, value)
+	}
+	return described
+}
+
+func (s *PolymorphicSerializer) child(rawValue any) (string, Serializer, error) {
+	if rawValue == nil {
+		return "", nil, &ValidationError{FieldErrors: map[string][]string{
+			s.discriminator: {"This field is required"},
+		}}
+	}
+	value := fmt.Sprint(rawValue)
+	child, ok := s.children[value]
+	if !ok {
+		return "", nil, &ValidationError{FieldErrors: map[string][]string{
+			s.discriminator: {fmt.Sprintf("Unknown type `%s`, expected one of: %s", value, strings.Join(s.types(), ", "))},
+		}}
+	}
+	return value, child, nil
+}
+
+func (s *PolymorphicSerializer) types() []string {
+	types := make([]string, 0, len(s.children))
+	for value := range s.children {
+		types = append(types, value)
+	}
+	sort.Strings(types)
+	return types
+}
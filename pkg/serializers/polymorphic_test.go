@@ -0,0 +1,79 @@
+package serializers
+
+import (
+	"testing"
+
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPaymentMethod struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	CardNumber string `json:"card_number"`
+	IBAN       string `json:"iban"`
+}
+
+func paymentMethodSerializer() *PolymorphicSerializer {
+	return NewPolymorphicSerializer("type").
+		WithType("card", NewModelSerializerWithFields[mockPaymentMethod]([]string{"id", "type", "card_number"})).
+		WithType("bank", NewModelSerializerWithFields[mockPaymentMethod]([]string{"id", "iban"}))
+}
+
+func TestPolymorphicSerializerToRepresentation(t *testing.T) {
+	// given
+	serializer := paymentMethodSerializer()
+
+	// when
+	card, cardErr := serializer.ToRepresentation(
+		models.InternalValue{"id": "1", "type": "card", "card_number": "4242", "iban": ""}, nil,
+	)
+	bank, bankErr := serializer.ToRepresentation(
+		models.InternalValue{"id": "2", "type": "bank", "card_number": "", "iban": "PL61"}, nil,
+	)
+	_, unknownErr := serializer.ToRepresentation(models.InternalValue{"id": "3", "type": "cash"}, nil)
+
+	// then
+	assert.NoError(t, cardErr)
+	assert.Equal(t, Representation{"id": "1", "type": "card", "card_number": "4242"}, card)
+	assert.NoError(t, bankErr)
+	assert.Equal(t, Representation{"id": "2", "type": "bank", "iban": "PL61"}, bank)
+	assert.Equal(t, &ValidationError{FieldErrors: map[string][]string{
+		"type": {"Unknown type `cash`, expected one of: bank, card"},
+	}}, unknownErr)
+}
+
+func TestPolymorphicSerializerToInternalValue(t *testing.T) {
+	// given
+	serializer := paymentMethodSerializer()
+
+	// when
+	card, cardErr := serializer.ToInternalValue(map[string]any{"type": "card", "card_number": "4242", "iban": "PL61"}, nil)
+	bank, bankErr := serializer.ToInternalValue(map[string]any{"type": "bank", "iban": "PL61"}, nil)
+	_, missingErr := serializer.ToInternalValue(map[string]any{"iban": "PL61"}, nil)
+
+	// then
+	assert.NoError(t, cardErr)
+	assert.Equal(t, models.InternalValue{"type": "card", "card_number": "4242"}, card)
+	assert.NoError(t, bankErr)
+	assert.Equal(t, models.InternalValue{"type": "bank", "iban": "PL61"}, bank)
+	assert.Equal(t, &ValidationError{FieldErrors: map[string][]string{"type": {"This field is required"}}}, missingErr)
+}
+
+func TestPolymorphicSerializerDescribe(t *testing.T) {
+	// when
+	described := paymentMethodSerializer().Describe()
+
+	// then
+	assert.ElementsMatch(t, []string{"id", "type", "card_number", "iban"}, keys(described))
+	assert.True(t, described["type"].Required)
+	assert.Equal(t, []any{"bank", "card"}, described["type"].Choices)
+}
+
+func keys(described map[string]*FieldMetadata) []string {
+	names := make([]string, 0, len(described))
+	for name := range described {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,112 @@
+package serializers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// QuerySerializer parses the query parameters of the request using the fields of the child
+// serializer, the same way request bodies are parsed. The parameters are all strings, so they are
+// first converted to the JSON types of the fields: numbers, booleans and times are parsed and the
+// array fields collect all the values of repeated parameters, like `?tag=a&tag=b`.
+type QuerySerializer struct {
+	child Serializer
+}
+
+// NewQuerySerializer creates a QuerySerializer. The types of the parameters are detected if the
+// child is a Describer, like ModelSerializer, otherwise they are passed to it as strings.
+func NewQuerySerializer(child Serializer) *QuerySerializer {
+	return &QuerySerializer{child: child}
+}
+
+// Parse returns the internal value of the query parameters. The parameters unknown to the child
+// serializer are ignored, so they can be used by pagination, filtering or ordering. Invalid values
+// are reported as *ValidationError.
+func (s *QuerySerializer) Parse(ctx *gin.Context) (models.InternalValue, error) {
+	query := ctx.Request.URL.Query()
+	var described map[string]*FieldMetadata
+	if describer, ok := s.child.(Describer); ok {
+		described = describer.Describe()
+	}
+	raw := map[string]any{}
+	fieldErrors := map[string][]string{}
+	for name, values := range query {
+		if len(values) == 0 {
+			continue
+		}
+		if described == nil {
+			raw[name] = values[0]
+			continue
+		}
+		field, ok := described[name]
+		if !ok || field.ReadOnly {
+			continue
+		}
+		value, convertErr := queryValue(field.Type, values)
+		if convertErr != nil {
+			fieldErrors[name] = []string{convertErr.Error()}
+			continue
+		}
+		raw[name] = value
+	}
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{FieldErrors: fieldErrors}
+	}
+	return s.child.ToInternalValue(raw, ctx)
+}
+
+// Describe returns the descriptions of the parameters, if the child serializer is a Describer
+func (s *QuerySerializer) Describe() map[string]*FieldMetadata {
+	if describer, ok := s.child.(Describer); ok {
+		return describer.Describe()
+	}
+	return map[string]*FieldMetadata{}
+}
+
+// queryValue converts the values of the query parameter to the JSON type of the field, only the
+// last value is used for non-array fields
+func queryValue(fieldType string, values []string) (any, error) {
+	raw := strings.TrimSpace(values[len(values)-1])
+	switch fieldType {
+	case "array":
+		items := make([]any, 0, len(values))
+		for _, value := range values {
+			items = append(items, value)
+		}
+		return items, nil
+	case "integer", "number":
+		parsed, parseErr := strconv.ParseFloat(raw, 64)
+		if parseErr != nil || math.IsInf(parsed, 0) || math.IsNaN(parsed) {
+			return nil, fmt.Errorf("Value `%s` is not a valid number", raw)
+		}
+		if fieldType == "integer" {
+			// the numbers are float64, like the ones of the JSON request bodies
+			integer, intErr := strconv.ParseInt(raw, 10, 64)
+			if intErr != nil {
+				return nil, fmt.Errorf("Value `%s` is not a valid integer", raw)
+			}
+			return float64(integer), nil
+		}
+		return parsed, nil
+	case "boolean":
+		parsed, parseErr := strconv.ParseBool(raw)
+		if parseErr != nil {
+			return nil, fmt.Errorf("Value `%s` is not a valid boolean", raw)
+		}
+		return parsed, nil
+	case "datetime":
+		for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+			if parsed, parseErr := time.Parse(layout, raw); parseErr == nil {
+				return parsed, nil
+			}
+		}
+		return nil, fmt.Errorf("Value `%s` is not a time in RFC3339 or YYYY-MM-DD format", raw)
+	}
+	return values[len(values)-1], nil
+}
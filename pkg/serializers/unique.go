@@ -0,0 +1,64 @@
+package serializers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// UniqueLookupFunc reports if any stored object other than the validated one holds the values of
+// the fields
+type UniqueLookupFunc func(values map[string]any) (bool, error)
+
+// UniqueValidator rejects the objects holding the same values of the fields as another stored
+// object, a single field makes it unique on its own. It backs the unique validators of the views and
+// the unique indexes of the GORM driver, so their violations are reported the same way, see
+// UniqueViolation.
+type UniqueValidator struct {
+	Fields []string
+}
+
+// Validate checks the internal value using the lookup. The values missing or null are not checked,
+// like in the databases, and neither are the updates of the current object not changing any of the
+// fields.
+func (u UniqueValidator) Validate(
+	intVal models.InternalValue, current models.InternalValue, taken UniqueLookupFunc,
+) error {
+	values := make(map[string]any, len(u.Fields))
+	changed := current == nil
+	for _, field := range u.Fields {
+		value := intVal[field]
+		if value == nil {
+			return nil
+		}
+		values[field] = value
+		changed = changed || !reflect.DeepEqual(current[field], value)
+	}
+	if !changed {
+		return nil
+	}
+	isTaken, lookupErr := taken(values)
+	if lookupErr != nil {
+		return lookupErr
+	}
+	if isTaken {
+		return UniqueViolation(u.Fields...)
+	}
+	return nil
+}
+
+// UniqueViolation describes the violation of the uniqueness of the fields on each of them, like the
+// errors of the unique constraints of the database
+func UniqueViolation(fields ...string) *ValidationError {
+	message := "this value is already used"
+	if len(fields) > 1 {
+		message = fmt.Sprintf("the combination of %s is already used", strings.Join(fields, ", "))
+	}
+	validationErr := &ValidationError{FieldErrors: map[string][]string{}}
+	for _, field := range fields {
+		validationErr.FieldErrors[field] = []string{message}
+	}
+	return validationErr
+}
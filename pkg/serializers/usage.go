@@ -0,0 +1,156 @@
+package serializers
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+)
+
+// FieldsQueryParam is the query parameter clients use to declare which fields they are interested in
+const FieldsQueryParam = "fields"
+
+// FieldsUsedHeader is a sampling header, that clients can send to report which fields of the
+// response they actually consumed, without requesting a sparse response
+const FieldsUsedHeader = "X-GRF-Fields-Used"
+
+// FieldUsage is a summary of how often a single representation field was emitted and consumed
+type FieldUsage struct {
+	Field    string
+	Emitted  int64
+	Consumed int64
+}
+
+// FieldUsageTracker collects statistics about representation fields, that clients actually consume.
+// Only requests that declare consumed fields (using FieldsQueryParam or FieldsUsedHeader) are sampled.
+type FieldUsageTracker struct {
+	mu       sync.Mutex
+	samples  int64
+	emitted  map[string]int64
+	consumed map[string]int64
+}
+
+// Record stores a single sample
+func (t *FieldUsageTracker) Record(emitted []string, consumed []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.samples++
+	for _, field := range emitted {
+		t.emitted[field]++
+	}
+	for _, field := range consumed {
+		t.consumed[field]++
+	}
+}
+
+// Samples returns the number of recorded samples
+func (t *FieldUsageTracker) Samples() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.samples
+}
+
+// Usage returns usage of all the fields seen so far, sorted by field name
+func (t *FieldUsageTracker) Usage() []FieldUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	names := map[string]bool{}
+	for field := range t.emitted {
+		names[field] = true
+	}
+	for field := range t.consumed {
+		names[field] = true
+	}
+	usage := make([]FieldUsage, 0, len(names))
+	for field := range names {
+		usage = append(usage, FieldUsage{
+			Field:    field,
+			Emitted:  t.emitted[field],
+			Consumed: t.consumed[field],
+		})
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].Field < usage[j].Field
+	})
+	return usage
+}
+
+// Unused returns fields, that were emitted, but never consumed by any sampled client
+func (t *FieldUsageTracker) Unused() []string {
+	unused := []string{}
+	for _, usage := range t.Usage() {
+		if usage.Emitted > 0 && usage.Consumed == 0 {
+			unused = append(unused, usage.Field)
+		}
+	}
+	return unused
+}
+
+func NewFieldUsageTracker() *FieldUsageTracker {
+	return &FieldUsageTracker{
+		emitted:  map[string]int64{},
+		consumed: map[string]int64{},
+	}
+}
+
+// FieldUsageTrackingSerializer is an opt-in instrumentation wrapper, that records which fields
+// of the representation are consumed by the clients. Its output can be used to guide trimming
+// oversized default serializers.
+type FieldUsageTrackingSerializer struct {
+	child   Serializer
+	tracker *FieldUsageTracker
+}
+
+func (s *FieldUsageTrackingSerializer) ToInternalValue(raw map[string]any, ctx *gin.Context) (models.InternalValue, error) {
+	return s.child.ToInternalValue(raw, ctx)
+}
+
+func (s *FieldUsageTrackingSerializer) ToRepresentation(intVal models.InternalValue, ctx *gin.Context) (Representation, error) {
+	representation, err := s.child.ToRepresentation(intVal, ctx)
+	if err != nil {
+		return representation, err
+	}
+	consumed, declared := declaredFields(ctx)
+	if !declared {
+		return representation, nil
+	}
+	emitted := make([]string, 0, len(representation))
+	for field := range representation {
+		emitted = append(emitted, field)
+	}
+	consumedAndEmitted := make([]string, 0, len(consumed))
+	for _, field := range consumed {
+		if _, ok := representation[field]; ok {
+			consumedAndEmitted = append(consumedAndEmitted, field)
+		}
+	}
+	s.tracker.Record(emitted, consumedAndEmitted)
+	return representation, nil
+}
+
+func NewFieldUsageTrackingSerializer(child Serializer, tracker *FieldUsageTracker) *FieldUsageTrackingSerializer {
+	return &FieldUsageTrackingSerializer{child: child, tracker: tracker}
+}
+
+func declaredFields(ctx *gin.Context) ([]string, bool) {
+	if ctx == nil || ctx.Request == nil {
+		return nil, false
+	}
+	raw := ctx.Request.Header.Get(FieldsUsedHeader)
+	if raw == "" {
+		raw = ctx.Query(FieldsQueryParam)
+	}
+	if raw == "" {
+		return nil, false
+	}
+	fields := []string{}
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields, len(fields) > 0
+}
@@ -0,0 +1,60 @@
+package serializers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func usageCtx(target string, header string) *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", target, nil)
+	if header != "" {
+		ctx.Request.Header.Set(FieldsUsedHeader, header)
+	}
+	return ctx
+}
+
+func TestFieldUsageTrackingSerializerRecordsDeclaredFields(t *testing.T) {
+	// given
+	tracker := NewFieldUsageTracker()
+	serializer := NewFieldUsageTrackingSerializer(NewModelSerializer[anotherMockModel](), tracker)
+	iv := models.InternalValue{"id": "1", "foo": "a", "bar": "b"}
+
+	// when
+	_, err1 := serializer.ToRepresentation(iv, usageCtx("/mocks?fields=foo,nonexistent", ""))
+	_, err2 := serializer.ToRepresentation(iv, usageCtx("/mocks", "foo, id"))
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, int64(2), tracker.Samples())
+	assert.Equal(t, []FieldUsage{
+		{Field: "bar", Emitted: 2, Consumed: 0},
+		{Field: "foo", Emitted: 2, Consumed: 2},
+		{Field: "id", Emitted: 2, Consumed: 1},
+	}, tracker.Usage())
+	assert.Equal(t, []string{"bar"}, tracker.Unused())
+}
+
+func TestFieldUsageTrackingSerializerSkipsUndeclared(t *testing.T) {
+	// given
+	tracker := NewFieldUsageTracker()
+	serializer := NewFieldUsageTrackingSerializer(NewModelSerializer[anotherMockModel](), tracker)
+
+	// when
+	repr, err := serializer.ToRepresentation(models.InternalValue{"foo": "a"}, usageCtx("/mocks", ""))
+	_, nilCtxErr := serializer.ToRepresentation(models.InternalValue{"foo": "a"}, nil)
+	intVal, intValErr := serializer.ToInternalValue(map[string]any{"foo": "a"}, nil)
+
+	// then
+	assert.NoError(t, err)
+	assert.NoError(t, nilCtxErr)
+	assert.NoError(t, intValErr)
+	assert.Equal(t, Representation{"foo": "a"}, repr)
+	assert.Equal(t, models.InternalValue{"foo": "a"}, intVal)
+	assert.Equal(t, int64(0), tracker.Samples())
+}
@@ -2,6 +2,7 @@ package serializers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -31,8 +32,14 @@ func (s *ValidatingSerializer[Model]) ToRepresentation(intVal models.InternalVal
 
 func (s *ValidatingSerializer[Model]) validate(intVal models.InternalValue, ctx *gin.Context) error {
 	errors := make([]error, 0)
+	partial := IsPartial(ctx)
 	for _, validator := range s.validators {
-		err := validator.Validate(intVal)
+		var err error
+		if partialValidator, ok := validator.(PartialValidator); ok && partial {
+			err = partialValidator.ValidatePartial(intVal)
+		} else {
+			err = validator.Validate(intVal)
+		}
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -64,14 +71,61 @@ type Validator interface {
 
 type goPlaygroundValidator[Model any] struct {
 	rules map[string]any
+	// messages are the overrides set using WithMessage, keyed by the fields and the tags
+	messages map[string]map[string]string
+}
+
+// WithMessage overrides the message of the violations of the tag by the field, like
+// WithMessage("name", "required", "Name is required"). The `*` field matches all the fields. The
+// `{field}` and `{param}` placeholders are replaced with the name of the field and the param of the
+// tag, like `130` of `lt=130`.
+func (v *goPlaygroundValidator[Model]) WithMessage(field, tag, message string) *goPlaygroundValidator[Model] {
+	if v.messages == nil {
+		v.messages = map[string]map[string]string{}
+	}
+	if v.messages[field] == nil {
+		v.messages[field] = map[string]string{}
+	}
+	v.messages[field][tag] = message
+	return v
+}
+
+// message returns the message of the violation, the override set using WithMessage if any
+func (v *goPlaygroundValidator[Model]) message(fieldName string, violation playgroundValidate.FieldError) string {
+	for _, field := range []string{fieldName, "*"} {
+		if message, ok := v.messages[field][violation.Tag()]; ok {
+			return strings.NewReplacer("{field}", fieldName, "{param}", violation.Param()).Replace(message)
+		}
+	}
+	return strings.Replace(violation.Error(), "''", fmt.Sprintf("'%s'", fieldName), -1)
 }
 
 func (v *goPlaygroundValidator[Model]) Validate(intVal models.InternalValue) (err error) {
+	return v.validate(intVal, v.rules)
+}
 
+// ValidatePartial only checks the rules of the fields present in the internal value
+func (v *goPlaygroundValidator[Model]) ValidatePartial(intVal models.InternalValue) error {
+	rules := make(map[string]any, len(intVal))
+	for fieldName, rule := range v.rules {
+		if _, ok := intVal[fieldName]; ok {
+			rules[fieldName] = rule
+		}
+	}
+	return v.validate(intVal, rules)
+}
+
+func (v *goPlaygroundValidator[Model]) validate(intVal models.InternalValue, rules map[string]any) error {
 	validator := playgroundValidate.New()
-	validationErrorsByFieldName := validator.ValidateMap(intVal, v.rules)
+	validationErrorsByFieldName := validator.ValidateMap(intVal, rules)
 	validationErr := &ValidationError{FieldErrors: make(map[string][]string)}
 	for fieldName, violation := range validationErrorsByFieldName {
+		if len(v.messages) > 0 {
+			for _, fieldErr := range violation.(playgroundValidate.ValidationErrors) {
+				validationErr.FieldErrors[fieldName] = append(validationErr.FieldErrors[fieldName], v.message(fieldName, fieldErr))
+			}
+			continue
+		}
 		// For some reason ValidateMap includes an empty field name, replace it with the actual field name
 		validationErr.FieldErrors[fieldName] = []string{
 			strings.Replace(
@@ -100,6 +154,27 @@ type ValidationError struct {
 	FieldErrors map[string][]string
 }
 
+// nestedErrors returns the errors of a nested serializer keyed by the paths of the fields, prefixed
+// with the path of the parent, like `address.street` or `items[2].price`. The errors of the whole
+// nested object are keyed by the path of the parent, errors other than *ValidationError as well.
+func nestedErrors(path string, err error) map[string][]string {
+	var fieldsErr *ValidationError
+	if !errors.As(err, &fieldsErr) {
+		return map[string][]string{path: {err.Error()}}
+	}
+	prefixed := make(map[string][]string, len(fieldsErr.FieldErrors))
+	for field, messages := range fieldsErr.FieldErrors {
+		nestedPath := path + "." + field
+		if field == "all" {
+			nestedPath = path
+		} else if strings.HasPrefix(field, "[") {
+			nestedPath = path + field
+		}
+		prefixed[nestedPath] = append(prefixed[nestedPath], messages...)
+	}
+	return prefixed
+}
+
 // Uses string builder to build error message
 func (e *ValidationError) Error() string {
 	var sb strings.Builder
@@ -170,11 +245,26 @@ func TransformError(err *jsonschema.ValidationError) map[string][]string {
 }
 
 func (v *jsonSchemaValidator) Validate(intVal models.InternalValue) error {
+	return v.validate(intVal, false)
+}
 
+// ValidatePartial ignores the violations of the `required` keyword, as partial updates lack the
+// fields absent in the request body
+func (v *jsonSchemaValidator) ValidatePartial(intVal models.InternalValue) error {
+	return v.validate(intVal, true)
+}
+
+func (v *jsonSchemaValidator) validate(intVal models.InternalValue, partial bool) error {
 	if validateErr := v.schema.Validate(
 		map[string]any(intVal),
 	); validateErr != nil {
 		jsonSchemaValidationErr, ok := validateErr.(*jsonschema.ValidationError)
+		if ok && partial {
+			jsonSchemaValidationErr = withoutRequired(jsonSchemaValidationErr)
+			if jsonSchemaValidationErr == nil {
+				return nil
+			}
+		}
 		if !ok {
 			return &ValidationError{
 				FieldErrors: map[string][]string{
@@ -193,6 +283,29 @@ func (v *jsonSchemaValidator) Validate(intVal models.InternalValue) error {
 	return nil
 }
 
+// withoutRequired returns the error without the violations of the `required` keyword, or nil if
+// there are no other violations
+func withoutRequired(err *jsonschema.ValidationError) *jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		if strings.HasSuffix(err.KeywordLocation, "/required") {
+			return nil
+		}
+		return err
+	}
+	causes := make([]*jsonschema.ValidationError, 0, len(err.Causes))
+	for _, cause := range err.Causes {
+		if filtered := withoutRequired(cause); filtered != nil {
+			causes = append(causes, filtered)
+		}
+	}
+	if len(causes) == 0 {
+		return nil
+	}
+	filtered := *err
+	filtered.Causes = causes
+	return &filtered
+}
+
 func NewJSONSchemaValidator(rawSchema map[string]any) Validator {
 	rawSchema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
 	rawSchema["$id"] = "https://glothriel.github.io/grf/schema.json"
@@ -200,7 +313,13 @@ func NewJSONSchemaValidator(rawSchema map[string]any) Validator {
 	if marshalErr != nil {
 		logrus.Panicf("Error marshaling JSONSchema: %s", marshalErr)
 	}
-	compiledSchema, compileErr := jsonschema.CompileString("schema.json", string(encodedSchema))
+	compiler := jsonschema.NewCompiler()
+	// Annotations, like defaults, are used to describe the fields
+	compiler.ExtractAnnotations = true
+	if addErr := compiler.AddResource("schema.json", strings.NewReader(string(encodedSchema))); addErr != nil {
+		logrus.Panicf("Error compiling JSONSchema: %s", addErr)
+	}
+	compiledSchema, compileErr := compiler.Compile("schema.json")
 	if compileErr != nil {
 		logrus.Panicf("Error compiling JSONSchema: %s", compileErr)
 	}
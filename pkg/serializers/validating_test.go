@@ -2,8 +2,11 @@ package serializers
 
 import (
 	"errors"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
 	"github.com/glothriel/grf/pkg/models"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -57,6 +60,27 @@ func TestGoPlaygroundValidatorErr(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestGoPlaygroundValidatorWithMessage(t *testing.T) {
+	// given
+	validator := NewGoPlaygroundValidator[mockValidatedModel](
+		map[string]any{
+			"name":    "required",
+			"surname": "required",
+			"age":     "required,gt=0,lt=130",
+		},
+	).WithMessage("name", "required", "Name is required").WithMessage("*", "lt", "{field} has to be less than {param}")
+
+	// when
+	err := validator.Validate(map[string]any{"age": 2000})
+
+	// then
+	assert.Equal(t, &ValidationError{FieldErrors: map[string][]string{
+		"name":    {"Name is required"},
+		"surname": {"Key: 'surname' Error:Field validation for 'surname' failed on the 'required' tag"},
+		"age":     {"age has to be less than 130"},
+	}}, err)
+}
+
 func TestValidatingSerializerToInternalValue(t *testing.T) {
 	// given
 	serializer := NewValidatingSerializer[mockValidatedModel](
@@ -312,3 +336,34 @@ func TestSimpleValidator(t *testing.T) {
 	// then
 	assert.Error(t, err)
 }
+
+func TestValidatingSerializerPartialUpdate(t *testing.T) {
+	// given
+	serializer := NewValidatingSerializer[mockValidatedModel](
+		NewModelSerializer[mockValidatedModel](),
+		NewGoPlaygroundValidator[mockValidatedModel](map[string]any{
+			"name": "required",
+			"age":  "required,gt=0,lt=130",
+		}),
+		NewJSONSchemaValidator(map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"age": map[string]any{"type": "number"}},
+			"required":   []string{"name", "age"},
+		}),
+	)
+	partialCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	grfctx.Set(partialCtx, grfctx.Metadata{Action: grfctx.ActionPartialUpdate})
+	updateCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	grfctx.Set(updateCtx, grfctx.Metadata{Action: grfctx.ActionUpdate})
+
+	// when
+	partialIntVal, partialErr := serializer.ToInternalValue(map[string]any{"age": 30.0}, partialCtx)
+	_, invalidPartialErr := serializer.ToInternalValue(map[string]any{"age": 200.0}, partialCtx)
+	_, updateErr := serializer.ToInternalValue(map[string]any{"age": 30.0}, updateCtx)
+
+	// then
+	assert.NoError(t, partialErr)
+	assert.Equal(t, models.InternalValue{"age": 30}, partialIntVal)
+	assert.Contains(t, invalidPartialErr.(*ValidationError).FieldErrors, "age")
+	assert.Contains(t, updateErr.(*ValidationError).FieldErrors, "name")
+}
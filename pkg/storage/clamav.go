@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamAVChunkSize is the size of the chunks streamed to clamd, it has to be smaller than its
+// StreamMaxLength setting
+const clamAVChunkSize = 64 * 1024
+
+// ClamAV scans the uploads using the clamd daemon, streaming them with the INSTREAM command
+type ClamAV struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// WithTimeout limits the time of a single scan, 30 seconds by default
+func (c *ClamAV) WithTimeout(timeout time.Duration) *ClamAV {
+	c.timeout = timeout
+	return c
+}
+
+func (c *ClamAV) Scan(ctx context.Context, _ string, data []byte) (ScanResult, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, dialErr := dialer.DialContext(ctx, c.network, c.address)
+	if dialErr != nil {
+		return ScanResult{}, dialErr
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if deadlineErr := conn.SetDeadline(deadline); deadlineErr != nil {
+		return ScanResult{}, deadlineErr
+	}
+	if _, writeErr := conn.Write([]byte("zINSTREAM\x00")); writeErr != nil {
+		return ScanResult{}, writeErr
+	}
+	for _, chunk := range chunks(data, clamAVChunkSize) {
+		if writeErr := writeClamAVChunk(conn, chunk); writeErr != nil {
+			return ScanResult{}, writeErr
+		}
+	}
+	// A zero length chunk ends the stream
+	if writeErr := writeClamAVChunk(conn, nil); writeErr != nil {
+		return ScanResult{}, writeErr
+	}
+	response, readErr := io.ReadAll(conn)
+	if readErr != nil {
+		return ScanResult{}, readErr
+	}
+	return parseClamAVResponse(string(bytes.TrimRight(response, "\x00\n")))
+}
+
+func writeClamAVChunk(w io.Writer, chunk []byte) error {
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(chunk)))
+	if _, writeErr := w.Write(size); writeErr != nil {
+		return writeErr
+	}
+	_, writeErr := w.Write(chunk)
+	return writeErr
+}
+
+// parseClamAVResponse parses the responses like `stream: OK` or `stream: Eicar-Signature FOUND`
+func parseClamAVResponse(response string) (ScanResult, error) {
+	verdict := response
+	if _, streamVerdict, ok := strings.Cut(response, ": "); ok {
+		verdict = streamVerdict
+	}
+	switch {
+	case verdict == "OK":
+		return ScanResult{Clean: true}, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		return ScanResult{Threat: strings.TrimSuffix(verdict, " FOUND")}, nil
+	case strings.HasSuffix(verdict, " ERROR"):
+		return ScanResult{}, errors.New(strings.TrimSuffix(verdict, " ERROR"))
+	}
+	return ScanResult{}, fmt.Errorf("unexpected clamd response: %q", response)
+}
+
+func chunks(data []byte, size int) [][]byte {
+	result := [][]byte{}
+	for len(data) > 0 {
+		n := min(size, len(data))
+		result = append(result, data[:n])
+		data = data[n:]
+	}
+	return result
+}
+
+// NewClamAV creates a ClamAV scanner connecting to clamd at the address, for example
+// NewClamAV("tcp", "localhost:3310") or NewClamAV("unix", "/run/clamav/clamd.ctl")
+func NewClamAV(network, address string) *ClamAV {
+	return &ClamAV{network: network, address: address, timeout: 30 * time.Second}
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClamd accepts INSTREAM commands and reports the streams containing the signature as infected
+func fakeClamd(t *testing.T, signature []byte) string {
+	listener, listenErr := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, listenErr)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				command := make([]byte, len("zINSTREAM\x00"))
+				if _, readErr := io.ReadFull(conn, command); readErr != nil || string(command) != "zINSTREAM\x00" {
+					conn.Write([]byte("UNKNOWN COMMAND\x00"))
+					return
+				}
+				var stream bytes.Buffer
+				for {
+					var size uint32
+					if readErr := binary.Read(conn, binary.BigEndian, &size); readErr != nil {
+						return
+					}
+					if size == 0 {
+						break
+					}
+					if _, readErr := io.CopyN(&stream, conn, int64(size)); readErr != nil {
+						return
+					}
+				}
+				if bytes.Contains(stream.Bytes(), signature) {
+					conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+					return
+				}
+				conn.Write([]byte("stream: OK\x00"))
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestClamAV(t *testing.T) {
+	// given
+	scanner := NewClamAV("tcp", fakeClamd(t, []byte("EICAR")))
+	large := bytes.Repeat([]byte("a"), 3*clamAVChunkSize)
+
+	// when
+	clean, cleanErr := scanner.Scan(context.Background(), "clean.txt", []byte("hello"))
+	infected, infectedErr := scanner.Scan(context.Background(), "infected.bin", append(large, []byte("EICAR")...))
+
+	// then
+	assert.NoError(t, cleanErr)
+	assert.Equal(t, ScanResult{Clean: true}, clean)
+	assert.NoError(t, infectedErr)
+	assert.Equal(t, ScanResult{Threat: "Eicar-Test-Signature"}, infected)
+}
+
+func TestParseClamAVResponse(t *testing.T) {
+	_, sizeErr := parseClamAVResponse("INSTREAM size limit exceeded. ERROR")
+	_, streamErr := parseClamAVResponse("stream: Can't allocate memory ERROR")
+
+	assert.EqualError(t, sizeErr, "INSTREAM size limit exceeded.")
+	assert.EqualError(t, streamErr, "Can't allocate memory")
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+)
+
+// ScanResult is the verdict of an UploadScanner
+type ScanResult struct {
+	Clean bool
+	// Threat names the detected threat, if the upload is not clean
+	Threat string
+}
+
+// UploadScanner checks uploads, for example for viruses, before they are stored
+type UploadScanner interface {
+	Scan(ctx context.Context, key string, data []byte) (ScanResult, error)
+}
+
+// RejectedUploadError is returned when the scanner detects a threat in the upload
+type RejectedUploadError struct {
+	Key    string
+	Threat string
+	// Quarantined is true if the upload was kept in the quarantine storage
+	Quarantined bool
+}
+
+func (e *RejectedUploadError) Error() string {
+	return fmt.Sprintf("upload rejected: %s detected", e.Threat)
+}
+
+// ScanningWriter scans the objects before saving them in the child Writer. Objects with threats
+// are rejected with RejectedUploadError and saved in the quarantine storage, if it's set, so they
+// can be inspected later. Scanner failures reject the uploads as well.
+type ScanningWriter struct {
+	child      Writer
+	scanner    UploadScanner
+	quarantine Writer
+}
+
+// WithQuarantine sets the storage, that keeps the rejected uploads
+func (w *ScanningWriter) WithQuarantine(quarantine Writer) *ScanningWriter {
+	w.quarantine = quarantine
+	return w
+}
+
+func (w *ScanningWriter) Save(ctx context.Context, key string, data []byte, contentType string) error {
+	result, scanErr := w.scanner.Scan(ctx, key, data)
+	if scanErr != nil {
+		return fmt.Errorf("could not scan the upload: %w", scanErr)
+	}
+	if result.Clean {
+		return w.child.Save(ctx, key, data, contentType)
+	}
+	rejected := &RejectedUploadError{Key: key, Threat: result.Threat}
+	if w.quarantine != nil {
+		if quarantineErr := w.quarantine.Save(ctx, key, data, contentType); quarantineErr != nil {
+			return fmt.Errorf("could not quarantine the upload: %w", quarantineErr)
+		}
+		rejected.Quarantined = true
+	}
+	return rejected
+}
+
+// NewScanningWriter creates a ScanningWriter saving the clean objects in the child Writer
+func NewScanningWriter(child Writer, scanner UploadScanner) *ScanningWriter {
+	return &ScanningWriter{child: child, scanner: scanner}
+}
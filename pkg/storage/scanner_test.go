@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type signatureScanner struct {
+	signature []byte
+	err       error
+}
+
+func (s signatureScanner) Scan(_ context.Context, _ string, data []byte) (ScanResult, error) {
+	if s.err != nil {
+		return ScanResult{}, s.err
+	}
+	if bytes.Contains(data, s.signature) {
+		return ScanResult{Threat: "Test-Signature"}, nil
+	}
+	return ScanResult{Clean: true}, nil
+}
+
+func TestScanningWriter(t *testing.T) {
+	// given
+	uploads, quarantine := NewInMemory(), NewInMemory()
+	writer := NewScanningWriter(uploads, signatureScanner{signature: []byte("EICAR")}).WithQuarantine(quarantine)
+
+	// when
+	cleanErr := writer.Save(context.Background(), "clean.txt", []byte("hello"), "text/plain")
+	infectedErr := writer.Save(context.Background(), "infected.txt", []byte("xEICARx"), "text/plain")
+
+	// then
+	assert.NoError(t, cleanErr)
+	_, openErr := uploads.Open(context.Background(), "clean.txt")
+	assert.NoError(t, openErr)
+
+	var rejected *RejectedUploadError
+	assert.ErrorAs(t, infectedErr, &rejected)
+	assert.Equal(t, &RejectedUploadError{Key: "infected.txt", Threat: "Test-Signature", Quarantined: true}, rejected)
+	assert.Equal(t, "upload rejected: Test-Signature detected", infectedErr.Error())
+	_, notStoredErr := uploads.Open(context.Background(), "infected.txt")
+	assert.ErrorIs(t, notStoredErr, ErrNotFound)
+	_, quarantinedErr := quarantine.Open(context.Background(), "infected.txt")
+	assert.NoError(t, quarantinedErr)
+}
+
+func TestScanningWriterRejectsUploadsWhenScannerFails(t *testing.T) {
+	// given
+	uploads := NewInMemory()
+	writer := NewScanningWriter(uploads, signatureScanner{err: errors.New("connection refused")})
+
+	// when
+	saveErr := writer.Save(context.Background(), "file.txt", []byte("hello"), "text/plain")
+
+	// then
+	assert.EqualError(t, saveErr, "could not scan the upload: connection refused")
+	_, openErr := uploads.Open(context.Background(), "file.txt")
+	assert.ErrorIs(t, openErr, ErrNotFound)
+}
@@ -0,0 +1,174 @@
+// Package storage provides access to binary objects, like uploaded files, served by the file
+// views
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when the storage doesn't contain the object
+var ErrNotFound = errors.New("object not found")
+
+// Object is an opened object of the storage, the caller has to close its content
+type Object struct {
+	Content io.ReadSeekCloser
+	// Name is the file name suggested to the clients downloading the object
+	Name        string
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+	// ETag identifies the version of the object, it has to be a quoted string
+	ETag string
+}
+
+// Storage provides the objects by their keys. The keys are cleaned like slash-separated paths, so
+// `/a/../b` and `b` are the same key.
+type Storage interface {
+	Open(ctx context.Context, key string) (*Object, error)
+}
+
+// Writer saves the objects in the storage, replacing the existing ones
+type Writer interface {
+	Save(ctx context.Context, key string, data []byte, contentType string) error
+}
+
+// Dir serves the files of a local directory, the keys are slash-separated paths relative to it
+type Dir struct {
+	root string
+}
+
+func (d *Dir) Open(_ context.Context, key string) (*Object, error) {
+	// Cleaned keys don't contain `..` elements, so they can't escape the root directory
+	name := filepath.Join(d.root, filepath.FromSlash(cleanKey(key)))
+	f, openErr := os.Open(name)
+	if errors.Is(openErr, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if openErr != nil {
+		return nil, openErr
+	}
+	info, statErr := f.Stat()
+	if statErr != nil {
+		f.Close()
+		return nil, statErr
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, ErrNotFound
+	}
+	return &Object{
+		Content:     f,
+		Name:        info.Name(),
+		ContentType: detectContentType(info.Name()),
+		Size:        info.Size(),
+		ModTime:     info.ModTime(),
+		ETag:        fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()),
+	}, nil
+}
+
+func (d *Dir) Save(_ context.Context, key string, data []byte, _ string) error {
+	name := filepath.Join(d.root, filepath.FromSlash(cleanKey(key)))
+	if mkdirErr := os.MkdirAll(filepath.Dir(name), 0o755); mkdirErr != nil {
+		return mkdirErr
+	}
+	return os.WriteFile(name, data, 0o644)
+}
+
+// NewDir creates a Dir storage serving the files of the root directory
+func NewDir(root string) *Dir {
+	return &Dir{root: root}
+}
+
+type inMemoryObject struct {
+	name        string
+	contentType string
+	data        []byte
+	modTime     time.Time
+	etag        string
+}
+
+// InMemory keeps the objects in a map, it's mostly useful for testing
+type InMemory struct {
+	mu      sync.RWMutex
+	objects map[string]inMemoryObject
+}
+
+// Put stores the object under the key, the content type is detected from the key's extension
+// if it's empty
+func (s *InMemory) Put(key string, data []byte, contentType string) {
+	name := path.Base(key)
+	if contentType == "" {
+		contentType = detectContentType(name)
+	}
+	sum := sha256.Sum256(data)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[cleanKey(key)] = inMemoryObject{
+		name:        name,
+		contentType: contentType,
+		data:        data,
+		modTime:     time.Now(),
+		etag:        `"` + hex.EncodeToString(sum[:]) + `"`,
+	}
+}
+
+func (s *InMemory) Save(_ context.Context, key string, data []byte, contentType string) error {
+	s.Put(key, data, contentType)
+	return nil
+}
+
+func (s *InMemory) Open(_ context.Context, key string) (*Object, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	object, ok := s.objects[cleanKey(key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &Object{
+		Content:     nopCloser{bytes.NewReader(object.data)},
+		Name:        object.name,
+		ContentType: object.contentType,
+		Size:        int64(len(object.data)),
+		ModTime:     object.modTime,
+		ETag:        object.etag,
+	}, nil
+}
+
+// NewInMemory creates an empty InMemory storage
+func NewInMemory() *InMemory {
+	return &InMemory{objects: map[string]inMemoryObject{}}
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// cleanKey normalizes the key to a slash-separated path without the leading slash, so keys taken
+// from wildcard path params, like `/reports/2024.txt`, match the stored ones
+func cleanKey(key string) string {
+	return strings.TrimPrefix(path.Clean("/"+key), "/")
+}
+
+func detectContentType(name string) string {
+	if detected := mime.TypeByExtension(path.Ext(name)); detected != "" {
+		return detected
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDir(t *testing.T) {
+	// given
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "files"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "files", "report.pdf"), []byte("%PDF"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "secret"), []byte("secret"), 0o600))
+	storage := NewDir(filepath.Join(root, "files"))
+
+	// when
+	object, openErr := storage.Open(context.Background(), "/report.pdf")
+	_, escapeErr := storage.Open(context.Background(), "../secret")
+	_, dirErr := storage.Open(context.Background(), "/")
+
+	// then
+	assert.NoError(t, openErr)
+	defer object.Content.Close()
+	content, readErr := io.ReadAll(object.Content)
+	assert.NoError(t, readErr)
+	assert.Equal(t, "%PDF", string(content))
+	assert.Equal(t, "report.pdf", object.Name)
+	assert.Equal(t, "application/pdf", object.ContentType)
+	assert.Equal(t, int64(4), object.Size)
+	assert.NotEmpty(t, object.ETag)
+	assert.ErrorIs(t, escapeErr, ErrNotFound)
+	assert.ErrorIs(t, dirErr, ErrNotFound)
+}
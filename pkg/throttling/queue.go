@@ -0,0 +1,100 @@
+package throttling
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// defaultQueueRetryInterval is used when the throttle doesn't report when its quota resets
+const defaultQueueRetryInterval = 100 * time.Millisecond
+
+// QueueStats is a snapshot of the Queue metrics
+type QueueStats struct {
+	// Depth is the number of requests waiting in the queue right now
+	Depth int64
+	// Queued is the total number of requests, that entered the queue
+	Queued int64
+	// Admitted is the total number of queued requests, that were eventually allowed
+	Admitted int64
+	// Rejected is the total number of requests rejected, because the queue was full or they would
+	// have to wait longer than the timeout
+	Rejected int64
+}
+
+// Queue lets throttled write requests wait until the throttle's quota resets, instead of rejecting
+// them immediately, which smooths short spikes. The queue is bounded: requests that don't fit in
+// it or would have to wait longer than the timeout are still rejected.
+type Queue struct {
+	size    int64
+	timeout time.Duration
+	sleep   func(ctx context.Context, d time.Duration) bool
+
+	depth    atomic.Int64
+	queued   atomic.Int64
+	admitted atomic.Int64
+	rejected atomic.Int64
+}
+
+// Wait queues the request until `retry` allows it, or rejects it. `retry` is called after every
+// wait and returns whether the request is allowed and when its quota resets, if known.
+func (q *Queue) Wait(ctx context.Context, resetAt time.Time, retry func() (bool, time.Time)) bool {
+	if q.depth.Add(1) > q.size {
+		q.depth.Add(-1)
+		q.rejected.Add(1)
+		return false
+	}
+	defer q.depth.Add(-1)
+	q.queued.Add(1)
+	deadline := time.Now().Add(q.timeout)
+	for {
+		wait := defaultQueueRetryInterval
+		if !resetAt.IsZero() {
+			wait = time.Until(resetAt)
+		}
+		if time.Now().Add(wait).After(deadline) || !q.sleep(ctx, wait) {
+			q.rejected.Add(1)
+			return false
+		}
+		var allowed bool
+		allowed, resetAt = retry()
+		if allowed {
+			q.admitted.Add(1)
+			return true
+		}
+	}
+}
+
+// Stats returns the current metrics of the queue, for example to export them to a monitoring system
+func (q *Queue) Stats() QueueStats {
+	return QueueStats{
+		Depth:    q.depth.Load(),
+		Queued:   q.queued.Load(),
+		Admitted: q.admitted.Load(),
+		Rejected: q.rejected.Load(),
+	}
+}
+
+// sleepContext sleeps for d, returning false if the context was cancelled earlier
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// NewQueue creates a Queue holding at most `size` requests, each waiting at most `timeout`
+func NewQueue(size int, timeout time.Duration) *Queue {
+	return &Queue{
+		size:    int64(size),
+		timeout: timeout,
+		sleep:   sleepContext,
+	}
+}
@@ -0,0 +1,82 @@
+package throttling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func noSleep(context.Context, time.Duration) bool {
+	return true
+}
+
+func TestQueueAdmitsRequestOnceAllowed(t *testing.T) {
+	// given
+	queue := NewQueue(1, time.Minute)
+	queue.sleep = noSleep
+	attempts := 0
+
+	// when
+	allowed := queue.Wait(context.Background(), time.Now().Add(time.Second), func() (bool, time.Time) {
+		attempts++
+		return attempts == 2, time.Now().Add(time.Second)
+	})
+
+	// then
+	assert.True(t, allowed)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, QueueStats{Depth: 0, Queued: 1, Admitted: 1, Rejected: 0}, queue.Stats())
+}
+
+func TestQueueRejectsWhenResetIsAfterTimeout(t *testing.T) {
+	// given
+	queue := NewQueue(1, time.Second)
+	queue.sleep = noSleep
+
+	// when
+	allowed := queue.Wait(context.Background(), time.Now().Add(time.Minute), func() (bool, time.Time) {
+		return true, time.Time{}
+	})
+
+	// then
+	assert.False(t, allowed)
+	assert.Equal(t, QueueStats{Depth: 0, Queued: 1, Admitted: 0, Rejected: 1}, queue.Stats())
+}
+
+func TestQueueRejectsWhenFull(t *testing.T) {
+	// given
+	queue := NewQueue(1, time.Minute)
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	queue.sleep = func(context.Context, time.Duration) bool {
+		close(entered)
+		<-release
+		return true
+	}
+	go queue.Wait(context.Background(), time.Time{}, func() (bool, time.Time) { return true, time.Time{} })
+	<-entered
+
+	// when
+	allowed := queue.Wait(context.Background(), time.Time{}, func() (bool, time.Time) { return true, time.Time{} })
+	stats := queue.Stats()
+	close(release)
+
+	// then
+	assert.False(t, allowed)
+	assert.Equal(t, QueueStats{Depth: 1, Queued: 1, Admitted: 0, Rejected: 1}, stats)
+}
+
+func TestQueueRejectsWhenContextIsCancelled(t *testing.T) {
+	// given
+	queue := NewQueue(1, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// when
+	allowed := queue.Wait(ctx, time.Now().Add(time.Second), func() (bool, time.Time) { return true, time.Time{} })
+
+	// then
+	assert.False(t, allowed)
+}
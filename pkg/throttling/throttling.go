@@ -0,0 +1,158 @@
+package throttling
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/authentication"
+)
+
+// Throttle decides whether the request is allowed to proceed
+type Throttle interface {
+	Allow(*gin.Context) bool
+}
+
+// Quota describes how many requests the client may still make in the current window
+type Quota struct {
+	Limit     int
+	Remaining int
+	// Reset is the time, when the current window ends and Remaining is reset to Limit
+	Reset time.Time
+}
+
+// SetHeaders sets both the X-RateLimit-* headers (Reset being an unix timestamp) and the IETF
+// RateLimit-* headers (Reset being the number of seconds until the window ends)
+func (q Quota) SetHeaders(header http.Header, now time.Time) {
+	resetAfter := int64(math.Ceil(q.Reset.Sub(now).Seconds()))
+	if resetAfter < 0 {
+		resetAfter = 0
+	}
+	header.Set("X-RateLimit-Limit", strconv.Itoa(q.Limit))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(q.Remaining))
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(q.Reset.Unix(), 10))
+	header.Set("RateLimit-Limit", strconv.Itoa(q.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(q.Remaining))
+	header.Set("RateLimit-Reset", strconv.FormatInt(resetAfter, 10))
+}
+
+// QuotaThrottle is implemented by throttles, that can report the client's quota, which is then
+// exposed in the rate limit headers of every response
+type QuotaThrottle interface {
+	Throttle
+	AllowWithQuota(*gin.Context) (bool, Quota)
+}
+
+// PeekingThrottle is implemented by throttles, that can check the request without counting it. The
+// views peek all such throttles first, so the requests denied by another throttle, or waiting in a
+// Queue, don't consume their quota.
+type PeekingThrottle interface {
+	QuotaThrottle
+	Peek(*gin.Context) (bool, Quota)
+}
+
+// KeyFunc returns the key, that requests are counted by, for example the client IP or the user ID
+type KeyFunc func(*gin.Context) string
+
+// ClientIP counts requests by the client IP
+func ClientIP(ctx *gin.Context) string {
+	return ctx.ClientIP()
+}
+
+// UserOrClientIP counts the requests of the authenticated users by their emails, or names if they
+// don't have any, see authentication.CurrentUser, and the requests of the anonymous users by the
+// client IP
+func UserOrClientIP(ctx *gin.Context) string {
+	user, userErr := authentication.CurrentUser(ctx)
+	if userErr != nil || user.IsAnonymous() {
+		return "ip:" + ctx.ClientIP()
+	}
+	if user.Email != "" {
+		return "user:" + user.Email
+	}
+	return "user:" + user.Name
+}
+
+type window struct {
+	start time.Time
+	count int
+}
+
+// RateThrottle allows at most `limit` requests per key in a fixed time window
+type RateThrottle struct {
+	limit   int
+	period  time.Duration
+	keyFunc KeyFunc
+	now     func() time.Time
+
+	mu      sync.Mutex
+	windows map[string]*window
+}
+
+func (t *RateThrottle) Allow(ctx *gin.Context) bool {
+	allowed, _ := t.AllowWithQuota(ctx)
+	return allowed
+}
+
+func (t *RateThrottle) AllowWithQuota(ctx *gin.Context) (bool, Quota) {
+	return t.check(ctx, true)
+}
+
+// Peek implements PeekingThrottle, it checks the request without counting it
+func (t *RateThrottle) Peek(ctx *gin.Context) (bool, Quota) {
+	return t.check(ctx, false)
+}
+
+// check reports whether the request is allowed in the current window of its key, counting it if
+// consume is set and it's allowed
+func (t *RateThrottle) check(ctx *gin.Context, consume bool) (bool, Quota) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	key := t.keyFunc(ctx)
+	current, ok := t.windows[key]
+	if !ok || now.Sub(current.start) >= t.period {
+		t.expire(now)
+		current = &window{start: now}
+		t.windows[key] = current
+	}
+	allowed := current.count < t.limit
+	if allowed && consume {
+		current.count++
+	}
+	return allowed, Quota{
+		Limit:     t.limit,
+		Remaining: t.limit - current.count,
+		Reset:     current.start.Add(t.period),
+	}
+}
+
+// expire removes windows, that already ended, so that the memory usage doesn't grow with the
+// number of distinct keys
+func (t *RateThrottle) expire(now time.Time) {
+	for key, w := range t.windows {
+		if now.Sub(w.start) >= t.period {
+			delete(t.windows, key)
+		}
+	}
+}
+
+// WithKeyFunc changes how the requests are grouped, by default they are grouped by ClientIP
+func (t *RateThrottle) WithKeyFunc(keyFunc KeyFunc) *RateThrottle {
+	t.keyFunc = keyFunc
+	return t
+}
+
+// NewRateThrottle creates a throttle allowing `limit` requests per `period` for every client
+func NewRateThrottle(limit int, period time.Duration) *RateThrottle {
+	return &RateThrottle{
+		limit:   limit,
+		period:  period,
+		keyFunc: ClientIP,
+		now:     time.Now,
+		windows: map[string]*window{},
+	}
+}
@@ -0,0 +1,125 @@
+package throttling
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/authentication"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateThrottle(t *testing.T) {
+	// given
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	throttle := NewRateThrottle(2, time.Minute).WithKeyFunc(func(ctx *gin.Context) string {
+		return ctx.GetHeader("X-User")
+	})
+	throttle.now = func() time.Time { return now }
+	ctx := func(user string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		c.Request.Header.Set("X-User", user)
+		return c
+	}
+
+	// when
+	first, second, third := throttle.Allow(ctx("a")), throttle.Allow(ctx("a")), throttle.Allow(ctx("a"))
+	otherUser := throttle.Allow(ctx("b"))
+	now = now.Add(time.Minute)
+	afterWindow := throttle.Allow(ctx("a"))
+
+	// then
+	assert.True(t, first)
+	assert.True(t, second)
+	assert.False(t, third)
+	assert.True(t, otherUser)
+	assert.True(t, afterWindow)
+}
+
+func TestUserOrClientIP(t *testing.T) {
+	// given
+	ctx := func(user *authentication.User) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		if user != nil {
+			c.Set("user", user)
+		}
+		return c
+	}
+
+	// when
+	unauthenticated := UserOrClientIP(ctx(nil))
+	anonymous := UserOrClientIP(ctx(&authentication.User{Name: "Anonymous", Email: authentication.AnonymousEmail}))
+	withEmail := UserOrClientIP(ctx(&authentication.User{Name: "Jane", Email: "jane@example.com"}))
+	withoutEmail := UserOrClientIP(ctx(&authentication.User{Name: "jane"}))
+
+	// then
+	assert.Equal(t, "ip:192.0.2.1", unauthenticated)
+	assert.Equal(t, "ip:192.0.2.1", anonymous)
+	assert.Equal(t, "user:jane@example.com", withEmail)
+	assert.Equal(t, "user:jane", withoutEmail)
+}
+
+func TestRateThrottleReportsQuota(t *testing.T) {
+	// given
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	throttle := NewRateThrottle(2, time.Minute)
+	throttle.now = func() time.Time { return now }
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", "/", nil)
+
+	// when
+	_, first := throttle.AllowWithQuota(ctx)
+	now = now.Add(10 * time.Second)
+	_, second := throttle.AllowWithQuota(ctx)
+	allowed, third := throttle.AllowWithQuota(ctx)
+
+	// then
+	reset := time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC)
+	assert.Equal(t, Quota{Limit: 2, Remaining: 1, Reset: reset}, first)
+	assert.Equal(t, Quota{Limit: 2, Remaining: 0, Reset: reset}, second)
+	assert.False(t, allowed)
+	assert.Equal(t, Quota{Limit: 2, Remaining: 0, Reset: reset}, third)
+}
+
+func TestRateThrottlePeek(t *testing.T) {
+	// given
+	throttle := NewRateThrottle(1, time.Minute)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", "/", nil)
+
+	// when
+	peeked, peekedQuota := throttle.Peek(ctx)
+	allowed := throttle.Allow(ctx)
+	peekedAfter, peekedAfterQuota := throttle.Peek(ctx)
+
+	// then
+	assert.True(t, peeked)
+	assert.Equal(t, 1, peekedQuota.Remaining)
+	assert.True(t, allowed)
+	assert.False(t, peekedAfter)
+	assert.Equal(t, 0, peekedAfterQuota.Remaining)
+}
+
+func TestQuotaSetHeaders(t *testing.T) {
+	// given
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	quota := Quota{Limit: 100, Remaining: 42, Reset: now.Add(1500 * time.Millisecond)}
+	header := http.Header{}
+
+	// when
+	quota.SetHeaders(header, now)
+
+	// then
+	assert.Equal(t, http.Header{
+		"X-Ratelimit-Limit":     {"100"},
+		"X-Ratelimit-Remaining": {"42"},
+		"X-Ratelimit-Reset":     {"1704067201"},
+		"Ratelimit-Limit":       {"100"},
+		"Ratelimit-Remaining":   {"42"},
+		"Ratelimit-Reset":       {"2"},
+	}, header)
+}
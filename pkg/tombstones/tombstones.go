@@ -0,0 +1,79 @@
+// Package tombstones keeps the log of deleted objects, so that the deletions can be reported to
+// clients, that sync incrementally, or replayed, for example to webhooks
+package tombstones
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Tombstone is a record of a deleted object
+type Tombstone struct {
+	ID        any
+	DeletedAt time.Time
+}
+
+// Tombstones record the IDs of deleted objects. The deletions are recorded by viewsets configured
+// with views.ViewSet.WithTombstones.
+type Tombstones interface {
+	Record(ctx *gin.Context, id any) error
+	// DeletedSince returns the IDs of the objects deleted after since
+	DeletedSince(ctx *gin.Context, since time.Time) ([]any, error)
+	// DeletedBetween returns the tombstones of the objects deleted in the [from, to) time range
+	DeletedBetween(ctx *gin.Context, from, to time.Time) ([]Tombstone, error)
+}
+
+// InMemory records the tombstones in a slice, which isn't shared between the instances of the
+// server, see gormq.Tombstones for a persistent log. Tombstones older than the retention
+// are removed, clients with older cursors won't be notified about these deletions.
+type InMemory struct {
+	retention time.Duration
+	now       func() time.Time
+
+	mu         sync.Mutex
+	tombstones []Tombstone
+}
+
+func (t *InMemory) Record(_ *gin.Context, id any) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	expired := 0
+	for t.retention > 0 && expired < len(t.tombstones) && now.Sub(t.tombstones[expired].DeletedAt) > t.retention {
+		expired++
+	}
+	t.tombstones = append(t.tombstones[expired:], Tombstone{ID: id, DeletedAt: now})
+	return nil
+}
+
+func (t *InMemory) DeletedSince(_ *gin.Context, since time.Time) ([]any, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ids := []any{}
+	for _, ts := range t.tombstones {
+		if ts.DeletedAt.After(since) {
+			ids = append(ids, ts.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (t *InMemory) DeletedBetween(_ *gin.Context, from, to time.Time) ([]Tombstone, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tombstones := []Tombstone{}
+	for _, ts := range t.tombstones {
+		if !ts.DeletedAt.Before(from) && ts.DeletedAt.Before(to) {
+			tombstones = append(tombstones, ts)
+		}
+	}
+	return tombstones, nil
+}
+
+// NewInMemory creates an InMemory tombstones storage keeping the tombstones for the retention period.
+// Zero retention keeps the tombstones forever.
+func NewInMemory(retention time.Duration) *InMemory {
+	return &InMemory{retention: retention, now: time.Now}
+}
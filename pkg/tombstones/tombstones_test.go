@@ -0,0 +1,30 @@
+package tombstones
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemory(t *testing.T) {
+	// given
+	at := func(hour int) time.Time { return time.Date(2024, 1, 1, hour, 0, 0, 0, time.UTC) }
+	now := at(10)
+	storage := NewInMemory(2 * time.Hour)
+	storage.now = func() time.Time { return now }
+
+	// when
+	for id := 1; id <= 4; id++ {
+		now = at(9 + id)
+		assert.NoError(t, storage.Record(nil, id))
+	}
+	since, sinceErr := storage.DeletedSince(nil, at(11))
+	between, betweenErr := storage.DeletedBetween(nil, at(0), at(13))
+
+	// then
+	assert.NoError(t, sinceErr)
+	assert.NoError(t, betweenErr)
+	assert.Equal(t, []any{3, 4}, since)
+	assert.Equal(t, []Tombstone{{ID: 2, DeletedAt: at(11)}, {ID: 3, DeletedAt: at(12)}}, between)
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// ConvertBytesToBase64 represents byte slices as standard base64 strings
+func ConvertBytesToBase64(in any) (any, error) {
+	if in == nil {
+		return nil, nil
+	}
+	bytes, ok := in.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("Expected type `[]uint8`, got `%T`", in)
+	}
+	if bytes == nil {
+		return nil, nil
+	}
+	return base64.StdEncoding.EncodeToString(bytes), nil
+}
+
+// ConvertBase64ToBytes decodes base64 strings, both the standard and the URL-safe alphabets are
+// accepted, with or without the padding
+func ConvertBase64ToBytes(in any) (any, error) {
+	if in == nil {
+		return nil, nil
+	}
+	text, ok := in.(string)
+	if !ok {
+		return nil, fmt.Errorf("Expected a base64 encoded string, got `%T`", in)
+	}
+	text = strings.TrimRight(text, "=")
+	encoding := base64.RawStdEncoding
+	if strings.ContainsAny(text, "-_") {
+		encoding = base64.RawURLEncoding
+	}
+	decoded, decodeErr := encoding.DecodeString(text)
+	if decodeErr != nil {
+		return nil, fmt.Errorf("Value is not a valid base64 encoded string")
+	}
+	return decoded, nil
+}
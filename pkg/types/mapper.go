@@ -3,6 +3,7 @@ package types
 import (
 	"fmt"
 	"math"
+	"reflect"
 	"time"
 )
 
@@ -26,6 +27,9 @@ type FieldType struct {
 
 type FieldTypeMapper struct {
 	Registered map[string]FieldType
+
+	numericCoercion NumericCoercion
+	safeIntegers    bool
 }
 
 func (s *FieldTypeMapper) ToRepresentation(typeString string) (ConvertFunc, error) {
@@ -64,6 +68,21 @@ func (s *FieldTypeMapper) Register(typeString string, fieldType FieldType) {
 	s.Registered[typeString] = fieldType
 }
 
+// RegisterType teaches the mapper how to convert values of application-specific types (custom ID types,
+// enums and so on), so that they don't have to be overridden on every serializer. Types have to be
+// registered before the serializers using them are created, as the detection happens on creation.
+func (s *FieldTypeMapper) RegisterType(t reflect.Type, toRepresentation ConvertFunc, toInternalValue ConvertFunc) {
+	s.Register(t.String(), FieldType{
+		InternalToResponse: toRepresentation,
+		RequestToInternal:  toInternalValue,
+	})
+}
+
+// RegisterType registers a type in the global mapper, see FieldTypeMapper.RegisterType
+func RegisterType[T any](toRepresentation ConvertFunc, toInternalValue ConvertFunc) {
+	Mapper().RegisterType(reflect.TypeOf((*T)(nil)).Elem(), toRepresentation, toInternalValue)
+}
+
 func DefaultFieldTypeMapper() *FieldTypeMapper {
 	registered := make(map[string]FieldType)
 	// all the types that can be directly decoded to and from JSON registered as passthrough
@@ -71,10 +90,6 @@ func DefaultFieldTypeMapper() *FieldTypeMapper {
 		InternalToResponse: ConvertPassThroughWithTypeValidation[string],
 		RequestToInternal:  ConvertPassThroughWithTypeValidation[string],
 	}
-	registered["float64"] = FieldType{
-		InternalToResponse: ConvertPassThroughWithTypeValidation[float64],
-		RequestToInternal:  ConvertPassThroughWithTypeValidation[float64],
-	}
 	registered["bool"] = FieldType{
 		InternalToResponse: ConvertPassThroughWithTypeValidation[bool],
 		RequestToInternal:  ConvertPassThroughWithTypeValidation[bool],
@@ -83,97 +98,12 @@ func DefaultFieldTypeMapper() *FieldTypeMapper {
 		InternalToResponse: ConvertPassThroughWithTypeValidation[time.Time],
 		RequestToInternal:  ConvertPassThroughWithTypeValidation[time.Time],
 	}
-	registered["int"] = FieldType{
-		InternalToResponse: ConvertPassThrough,
-		RequestToInternal: ConvertFloatDynamic(
-			func(f float64) any {
-				return int(f)
-			},
-			true,
-		),
-	}
-	registered["int8"] = FieldType{
-		InternalToResponse: ConvertPassThrough,
-		RequestToInternal: ConvertFloatDynamic(
-			func(f float64) any {
-				return int8(f)
-			},
-			true,
-		),
-	}
-
-	registered["int16"] = FieldType{
-		InternalToResponse: ConvertPassThrough,
-		RequestToInternal: ConvertFloatDynamic(
-			func(f float64) any {
-				return int16(f)
-			},
-			true,
-		),
-	}
-	registered["int32"] = FieldType{
-		InternalToResponse: ConvertPassThrough,
-		RequestToInternal: ConvertFloatDynamic(
-			func(f float64) any {
-				return int32(f)
-			},
-			true,
-		),
-	}
-	registered["int64"] = FieldType{
-		InternalToResponse: ConvertPassThrough,
-		RequestToInternal: ConvertFloatDynamic(
-			func(f float64) any {
-				return int64(f)
-			},
-			true,
-		),
-	}
-	registered["uint"] = FieldType{
-		InternalToResponse: ConvertPassThrough,
-		RequestToInternal: ConvertFloatDynamic(
-			func(f float64) any {
-				return uint(f)
-			},
-			false,
-		),
-	}
-	registered["uint8"] = FieldType{
-		InternalToResponse: ConvertPassThrough,
-		RequestToInternal: ConvertFloatDynamic(
-			func(f float64) any {
-				return uint8(f)
-			},
-			false,
-		),
-	}
-	registered["uint16"] = FieldType{
-		InternalToResponse: ConvertPassThrough,
-		RequestToInternal: ConvertFloatDynamic(
-			func(f float64) any {
-				return uint16(f)
-			},
-			false,
-		),
-	}
-	registered["uint32"] = FieldType{
-		InternalToResponse: ConvertPassThrough,
-		RequestToInternal: ConvertFloatDynamic(
-			func(f float64) any {
-				return uint32(f)
-			},
-			false,
-		),
-	}
-	registered["uint64"] = FieldType{
-		InternalToResponse: ConvertPassThrough,
-		RequestToInternal: ConvertFloatDynamic(
-			func(f float64) any {
-				return uint64(f)
-			},
-			false,
-		),
+	// byte slices are stored as BLOBs, and represented as base64 strings, like by encoding/json
+	registered["[]uint8"] = FieldType{
+		InternalToResponse: ConvertBytesToBase64,
+		RequestToInternal:  ConvertBase64ToBytes,
 	}
+	registerNumericTypes(registered, NumericCoercionStrict, false)
 
 	return &FieldTypeMapper{
 		Registered: registered,
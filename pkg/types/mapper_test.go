@@ -0,0 +1,54 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCustomID int
+
+func TestRegisterType(t *testing.T) {
+	// given
+	mapper := DefaultFieldTypeMapper()
+
+	// when
+	mapper.RegisterType(
+		reflect.TypeOf(mockCustomID(0)),
+		func(v any) (any, error) {
+			return fmt.Sprintf("id_%d", v.(mockCustomID)), nil
+		},
+		func(v any) (any, error) {
+			var id int
+			_, err := fmt.Sscanf(strings.TrimPrefix(v.(string), "id_"), "%d", &id)
+			return mockCustomID(id), err
+		},
+	)
+	toRepresentation, toRepresentationErr := mapper.ToRepresentation("types.mockCustomID")
+	toInternalValue, toInternalValueErr := mapper.ToInternalValue("types.mockCustomID")
+
+	// then
+	assert.NoError(t, toRepresentationErr)
+	assert.NoError(t, toInternalValueErr)
+	repr, reprErr := toRepresentation(mockCustomID(7))
+	assert.NoError(t, reprErr)
+	assert.Equal(t, "id_7", repr)
+	iv, ivErr := toInternalValue("id_8")
+	assert.NoError(t, ivErr)
+	assert.Equal(t, mockCustomID(8), iv)
+}
+
+func TestRegisterTypeGlobal(t *testing.T) {
+	// given
+	type localType struct{ V string }
+
+	// when
+	RegisterType[localType](ConvertPassThrough, ConvertPassThrough)
+	_, err := Mapper().ToRepresentation(reflect.TypeOf(localType{}).String())
+
+	// then
+	assert.NoError(t, err)
+}
@@ -0,0 +1,211 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// NumericCoercion controls how request values are converted to numeric model fields
+type NumericCoercion int
+
+const (
+	// NumericCoercionStrict accepts only JSON numbers, that can be converted to the field type
+	// without losing information: fractions and out of range values are rejected
+	NumericCoercionStrict NumericCoercion = iota
+	// NumericCoercionAllowStrings works like NumericCoercionStrict, but also accepts numbers
+	// sent as strings, e.g. "42"
+	NumericCoercionAllowStrings
+	// NumericCoercionLenient accepts numbers sent as strings and truncates fractions when
+	// converting to integer fields. Out of range values are still rejected. Meant for legacy clients.
+	NumericCoercionLenient
+)
+
+// Integer is implemented by the integer types supported by the numeric conversions
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// MaxSafeInteger is the largest integer, that JavaScript clients can represent exactly, 2^53 - 1
+const MaxSafeInteger = 1<<53 - 1
+
+// WithNumericCoercion re-registers all the built-in numeric types using the given coercion mode.
+// Similarly to RegisterType, it has to be called before the serializers are created.
+func (s *FieldTypeMapper) WithNumericCoercion(mode NumericCoercion) *FieldTypeMapper {
+	s.numericCoercion = mode
+	registerNumericTypes(s.Registered, mode, s.safeIntegers)
+	return s
+}
+
+// WithSafeIntegers re-registers the int, int64, uint and uint64 types, so the values beyond
+// MaxSafeInteger are represented as strings instead of losing precision in JavaScript clients, and
+// the integers are accepted as strings as well. Similarly to RegisterType, it has to be called
+// before the serializers are created.
+func (s *FieldTypeMapper) WithSafeIntegers() *FieldTypeMapper {
+	s.safeIntegers = true
+	registerNumericTypes(s.Registered, s.numericCoercion, s.safeIntegers)
+	return s
+}
+
+func registerNumericTypes(registered map[string]FieldType, mode NumericCoercion, safeIntegers bool) {
+	registered["float64"] = FieldType{
+		InternalToResponse: ConvertPassThroughWithTypeValidation[float64],
+		RequestToInternal:  ConvertNumberToFloat64(mode),
+	}
+	registered["float32"] = FieldType{
+		InternalToResponse: ConvertPassThroughWithTypeValidation[float32],
+		RequestToInternal:  ConvertNumberToFloat32(mode),
+	}
+	registered["int"] = integerFieldType[int](mode)
+	registered["int8"] = integerFieldType[int8](mode)
+	registered["int16"] = integerFieldType[int16](mode)
+	registered["int32"] = integerFieldType[int32](mode)
+	registered["int64"] = integerFieldType[int64](mode)
+	registered["uint"] = integerFieldType[uint](mode)
+	registered["uint8"] = integerFieldType[uint8](mode)
+	registered["uint16"] = integerFieldType[uint16](mode)
+	registered["uint32"] = integerFieldType[uint32](mode)
+	registered["uint64"] = integerFieldType[uint64](mode)
+	if safeIntegers {
+		registered["int"] = safeIntegerFieldType[int](mode)
+		registered["int64"] = safeIntegerFieldType[int64](mode)
+		registered["uint"] = safeIntegerFieldType[uint](mode)
+		registered["uint64"] = safeIntegerFieldType[uint64](mode)
+	}
+}
+
+func safeIntegerFieldType[T Integer](mode NumericCoercion) FieldType {
+	return FieldType{
+		InternalToResponse: ConvertIntegerToSafeNumber(false),
+		RequestToInternal:  ConvertSafeNumberToInteger[T](mode),
+	}
+}
+
+func integerFieldType[T Integer](mode NumericCoercion) FieldType {
+	return FieldType{
+		InternalToResponse: ConvertPassThrough,
+		RequestToInternal:  ConvertNumberToInteger[T](mode),
+	}
+}
+
+// ConvertNumberToInteger converts request values to the integer type T, rejecting values that
+// would be silently changed by the conversion
+func ConvertNumberToInteger[T Integer](mode NumericCoercion) ConvertFunc {
+	return func(in any) (any, error) {
+		f, err := numberFromRequest(in, mode)
+		if err != nil {
+			return nil, err
+		}
+		var zero T
+		if math.Mod(f, 1) != 0 {
+			if mode != NumericCoercionLenient {
+				return nil, fmt.Errorf("Value %v is not an integer", f)
+			}
+			f = math.Trunc(f)
+		}
+		isUnsigned := zero-1 > 0
+		converted := T(f)
+		if (isUnsigned && f < 0) || float64(converted) != f {
+			return nil, fmt.Errorf("Value %v is out of range for type `%T`", f, zero)
+		}
+		return converted, nil
+	}
+}
+
+// ConvertNumberToFloat64 converts request values to float64
+func ConvertNumberToFloat64(mode NumericCoercion) ConvertFunc {
+	return func(in any) (any, error) {
+		return numberFromRequest(in, mode)
+	}
+}
+
+// ConvertNumberToFloat32 converts request values to float32, rejecting values out of its range
+func ConvertNumberToFloat32(mode NumericCoercion) ConvertFunc {
+	return func(in any) (any, error) {
+		f, err := numberFromRequest(in, mode)
+		if err != nil {
+			return nil, err
+		}
+		if math.Abs(f) > math.MaxFloat32 {
+			return nil, fmt.Errorf("Value %v is out of range for type `float32`", f)
+		}
+		return float32(f), nil
+	}
+}
+
+func numberFromRequest(in any, mode NumericCoercion) (float64, error) {
+	switch v := in.(type) {
+	case float64:
+		return v, nil
+	case string:
+		if mode == NumericCoercionStrict {
+			return 0, fmt.Errorf("Expected a number, got a string")
+		}
+		f, parseErr := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if parseErr != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return 0, fmt.Errorf("Value `%s` is not a valid number", v)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("Expected type `float64`, got `%T`", in)
+}
+
+// ConvertIntegerToSafeNumber represents the integers beyond MaxSafeInteger as strings, or all the
+// integers if alwaysString is set, so the clients can handle all the values of the field the same
+// way. Other values are passed through.
+func ConvertIntegerToSafeNumber(alwaysString bool) ConvertFunc {
+	return func(in any) (any, error) {
+		value := reflect.ValueOf(in)
+		for value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return nil, nil
+			}
+			value = value.Elem()
+		}
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if integer := value.Int(); alwaysString || integer > MaxSafeInteger || integer < -MaxSafeInteger {
+				return strconv.FormatInt(integer, 10), nil
+			}
+			return value.Interface(), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if integer := value.Uint(); alwaysString || integer > MaxSafeInteger {
+				return strconv.FormatUint(integer, 10), nil
+			}
+			return value.Interface(), nil
+		}
+		return in, nil
+	}
+}
+
+// ConvertSafeNumberToInteger converts request values to the integer type T like
+// ConvertNumberToInteger, but also accepts the integers sent as strings, which are parsed exactly,
+// and rejects the numbers beyond MaxSafeInteger, as they could have lost precision when decoded
+func ConvertSafeNumberToInteger[T Integer](mode NumericCoercion) ConvertFunc {
+	fallback := ConvertNumberToInteger[T](mode)
+	return func(in any) (any, error) {
+		switch v := in.(type) {
+		case string:
+			var zero T
+			bits := reflect.TypeOf(zero).Bits()
+			text := strings.TrimSpace(v)
+			if zero-1 > 0 {
+				if parsed, parseErr := strconv.ParseUint(text, 10, bits); parseErr == nil {
+					return T(parsed), nil
+				}
+			} else if parsed, parseErr := strconv.ParseInt(text, 10, bits); parseErr == nil {
+				return T(parsed), nil
+			}
+			if mode != NumericCoercionLenient {
+				return nil, fmt.Errorf("Value `%s` is not a valid integer of type `%T`", v, zero)
+			}
+		case float64:
+			if math.Abs(v) > MaxSafeInteger {
+				return nil, fmt.Errorf("Value %v exceeds the safe integer range, it has to be sent as a string", v)
+			}
+		}
+		return fallback(in)
+	}
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumericCoercion(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        NumericCoercion
+		typeString  string
+		input       any
+		expected    any
+		expectedErr string
+	}{
+		{name: "strict integer", mode: NumericCoercionStrict, typeString: "int", input: float64(42), expected: 42},
+		{name: "strict fraction", mode: NumericCoercionStrict, typeString: "int", input: 4.5, expectedErr: "not an integer"},
+		{name: "strict overflow", mode: NumericCoercionStrict, typeString: "int8", input: float64(300), expectedErr: "out of range"},
+		{name: "strict negative unsigned", mode: NumericCoercionStrict, typeString: "uint16", input: float64(-1), expectedErr: "out of range"},
+		{name: "strict string", mode: NumericCoercionStrict, typeString: "int64", input: "42", expectedErr: "got a string"},
+		{name: "strict float32 overflow", mode: NumericCoercionStrict, typeString: "float32", input: 1e300, expectedErr: "out of range"},
+		{name: "strings allowed", mode: NumericCoercionAllowStrings, typeString: "int64", input: "42", expected: int64(42)},
+		{name: "strings allowed fraction", mode: NumericCoercionAllowStrings, typeString: "int64", input: "4.5", expectedErr: "not an integer"},
+		{name: "strings allowed invalid", mode: NumericCoercionAllowStrings, typeString: "float64", input: "abc", expectedErr: "not a valid number"},
+		{name: "lenient fraction", mode: NumericCoercionLenient, typeString: "uint8", input: "4.9", expected: uint8(4)},
+		{name: "lenient overflow", mode: NumericCoercionLenient, typeString: "uint8", input: float64(256), expectedErr: "out of range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			mapper := DefaultFieldTypeMapper().WithNumericCoercion(tt.mode)
+			toInternalValue, err := mapper.ToInternalValue(tt.typeString)
+			assert.NoError(t, err)
+
+			// when
+			internalValue, internalValueErr := toInternalValue(tt.input)
+
+			// then
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, internalValueErr, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, internalValueErr)
+			assert.Equal(t, tt.expected, internalValue)
+		})
+	}
+}
+
+func TestSafeIntegers(t *testing.T) {
+	tests := []struct {
+		name        string
+		typeString  string
+		input       any
+		expected    any
+		expectedErr string
+	}{
+		{name: "small number", typeString: "int64", input: float64(42), expected: int64(42)},
+		{name: "big string", typeString: "int64", input: "9007199254740993", expected: int64(9007199254740993)},
+		{name: "negative big string", typeString: "int64", input: "-9223372036854775808", expected: int64(-9223372036854775808)},
+		{name: "max uint64 string", typeString: "uint64", input: "18446744073709551615", expected: uint64(18446744073709551615)},
+		{name: "big number", typeString: "int64", input: float64(1 << 60), expectedErr: "has to be sent as a string"},
+		{name: "overflowing string", typeString: "int64", input: "9223372036854775808", expectedErr: "not a valid integer"},
+		{name: "negative unsigned string", typeString: "uint", input: "-1", expectedErr: "not a valid integer"},
+		{name: "fraction string", typeString: "int", input: "4.5", expectedErr: "not a valid integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			mapper := DefaultFieldTypeMapper().WithSafeIntegers()
+			toInternalValue, err := mapper.ToInternalValue(tt.typeString)
+			assert.NoError(t, err)
+
+			// when
+			internalValue, internalValueErr := toInternalValue(tt.input)
+
+			// then
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, internalValueErr, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, internalValueErr)
+			assert.Equal(t, tt.expected, internalValue)
+		})
+	}
+}
+
+func TestConvertIntegerToSafeNumber(t *testing.T) {
+	// given
+	big := uint64(1<<53 + 1)
+	var missing *int64
+
+	// when
+	toSafe, toString := ConvertIntegerToSafeNumber(false), ConvertIntegerToSafeNumber(true)
+
+	// then
+	for input, expected := range map[any]any{
+		int64(MaxSafeInteger):      int64(MaxSafeInteger),
+		int64(-MaxSafeInteger - 1): "-9007199254740992",
+		big:                        "9007199254740993",
+		&big:                       "9007199254740993",
+		missing:                    nil,
+		"text":                     "text",
+	} {
+		represented, err := toSafe(input)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, represented)
+	}
+	represented, err := toString(42)
+	assert.NoError(t, err)
+	assert.Equal(t, "42", represented)
+}
@@ -0,0 +1,92 @@
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries/common"
+)
+
+// Regex accepts the texts matching the pattern, the pattern is compiled immediately, so the invalid
+// patterns panic during the configuration
+func Regex(pattern string) Func {
+	compiled := regexp.MustCompile(pattern)
+	return func(value any, _ *gin.Context) error {
+		text, ok := value.(string)
+		if !ok {
+			return errors.New("expected a text")
+		}
+		if !compiled.MatchString(text) {
+			return fmt.Errorf("does not match the pattern `%s`", pattern)
+		}
+		return nil
+	}
+}
+
+// Length accepts the texts, lists and objects with the length between min and max, inclusive. The
+// texts are measured in characters, a negative max doesn't limit the length.
+func Length(min, max int) Func {
+	return func(value any, _ *gin.Context) error {
+		var length int
+		if text, ok := value.(string); ok {
+			length = utf8.RuneCountInString(text)
+		} else {
+			reflected := reflect.ValueOf(value)
+			switch reflected.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map:
+				length = reflected.Len()
+			default:
+				return errors.New("expected a text, a list or an object")
+			}
+		}
+		if length < min {
+			return fmt.Errorf("has to be at least %d long", min)
+		}
+		if max >= 0 && length > max {
+			return fmt.Errorf("has to be at most %d long", max)
+		}
+		return nil
+	}
+}
+
+// Range accepts the numbers between min and max, inclusive
+func Range(min, max float64) Func {
+	return func(value any, _ *gin.Context) error {
+		number, ok := common.AsFloat(value)
+		if !ok {
+			return errors.New("expected a number")
+		}
+		if number < min || number > max {
+			return fmt.Errorf("has to be between %v and %v", min, max)
+		}
+		return nil
+	}
+}
+
+// OneOf accepts the values equal to one of the choices, the numbers are compared regardless of
+// their types
+func OneOf(choices ...any) Func {
+	formatted := make([]string, 0, len(choices))
+	for _, choice := range choices {
+		formatted = append(formatted, fmt.Sprint(choice))
+	}
+	return func(value any, _ *gin.Context) error {
+		if number, ok := common.AsFloat(value); ok {
+			value = number
+		}
+		for _, choice := range choices {
+			if number, ok := common.AsFloat(choice); ok {
+				choice = number
+			}
+			if reflect.DeepEqual(value, choice) {
+				return nil
+			}
+		}
+		return fmt.Errorf("has to be one of %s", strings.Join(formatted, ", "))
+	}
+}
@@ -0,0 +1,78 @@
+package validators
+
+import (
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// Condition decides if the conditional rules apply to the request, for example using its action,
+// the authenticated user or the other submitted fields
+type Condition func(intVal models.InternalValue, ctx *gin.Context) bool
+
+// When runs the validation function only if the condition holds, like
+// When(OnActions(grfctx.ActionCreate), Required("sku"))
+func When(condition Condition, validationFunc serializers.ValidationFunc) serializers.ValidationFunc {
+	return func(intVal models.InternalValue, ctx *gin.Context) error {
+		if !condition(intVal, ctx) {
+			return nil
+		}
+		return validationFunc(intVal, ctx)
+	}
+}
+
+// OnActions holds for the requests handled by the viewset actions, see grfctx.CurrentAction
+func OnActions(actions ...grfctx.Action) Condition {
+	return func(_ models.InternalValue, ctx *gin.Context) bool {
+		current := grfctx.CurrentAction(ctx)
+		for _, action := range actions {
+			if action == current {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FieldEquals holds if the submitted field has the value, the numbers are compared regardless of
+// their types. The updates are validated merged into the stored object, so the fields missing in
+// partial updates hold their stored values.
+func FieldEquals(fieldName string, value any) Condition {
+	matches := OneOf(value)
+	return func(intVal models.InternalValue, ctx *gin.Context) bool {
+		submitted, present := intVal[fieldName]
+		if !present {
+			return false
+		}
+		if submitted == nil || value == nil {
+			return reflect.DeepEqual(submitted, value)
+		}
+		return matches(submitted, ctx) == nil
+	}
+}
+
+// Not negates the condition
+func Not(condition Condition) Condition {
+	return func(intVal models.InternalValue, ctx *gin.Context) bool {
+		return !condition(intVal, ctx)
+	}
+}
+
+// Required rejects the internal values without the field or with a null, used with When, as the
+// unconditionally required fields should use fields.Field.WithRequired. The partial updates are
+// validated merged into the stored object, the fields still missing from them are not rejected.
+func Required(fieldName string) serializers.ValidationFunc {
+	return func(intVal models.InternalValue, ctx *gin.Context) error {
+		value, present := intVal[fieldName]
+		if value != nil || (!present && grfctx.CurrentAction(ctx) == grfctx.ActionPartialUpdate) {
+			return nil
+		}
+		return &serializers.ValidationError{FieldErrors: map[string][]string{
+			fieldName: {fields.ErrFieldRequired.Error()},
+		}}
+	}
+}
@@ -0,0 +1,72 @@
+// Package validators holds the named, reusable validation rules, like the format of the SKUs or the
+// range of the discounts, registered once and attached to the fields of any ModelSerializer, so the
+// rules not expressible with the go-playground tags don't have to be repeated in every serializer.
+package validators
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/sirupsen/logrus"
+)
+
+// Func validates the internal value of a field, the nulls are not validated
+type Func func(value any, ctx *gin.Context) error
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Func{}
+)
+
+// Register registers the validator under the name, like Register("sku", Regex(`^[A-Z]{3}-\d{4}$`)).
+// Registering the same name twice is a configuration error.
+func Register(name string, validator Func) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[name]; exists {
+		logrus.Panicf("Validator `%s` is already registered", name)
+	}
+	registry[name] = validator
+}
+
+// Get returns the validator registered under the name
+func Get(name string) (Func, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	validator, ok := registry[name]
+	return validator, ok
+}
+
+// Field returns a validation function checking the field with the named validators, attached using
+// ModelSerializer.WithValidationFunc, like WithValidationFunc(validators.Field("sku", "sku")). The
+// validators are looked up immediately, so the unknown names panic during the configuration. The
+// missing fields and the nulls are skipped, all the errors are reported. The updates are validated
+// merged into the stored object, see ModelSerializer.WithValidationFunc.
+func Field(fieldName string, names ...string) serializers.ValidationFunc {
+	fieldValidators := make([]Func, 0, len(names))
+	for _, name := range names {
+		validator, ok := Get(name)
+		if !ok {
+			logrus.Panicf("Validator `%s` of field `%s` is not registered", name, fieldName)
+		}
+		fieldValidators = append(fieldValidators, validator)
+	}
+	return func(intVal models.InternalValue, ctx *gin.Context) error {
+		value := intVal[fieldName]
+		if value == nil {
+			return nil
+		}
+		messages := []string{}
+		for _, validator := range fieldValidators {
+			if validateErr := validator(value, ctx); validateErr != nil {
+				messages = append(messages, validateErr.Error())
+			}
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+		return &serializers.ValidationError{FieldErrors: map[string][]string{fieldName: messages}}
+	}
+}
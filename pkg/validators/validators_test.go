@@ -0,0 +1,112 @@
+package validators
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Func
+		value     any
+		expected  string
+	}{
+		{name: "regex matches", validator: Regex(`^[A-Z]{3}-\d{4}$`), value: "ABC-1234"},
+		{name: "regex does not match", validator: Regex(`^[A-Z]{3}-\d{4}$`), value: "abc", expected: "does not match the pattern `^[A-Z]{3}-\\d{4}$`"},
+		{name: "regex of a number", validator: Regex(`^\d+$`), value: 1.0, expected: "expected a text"},
+		{name: "length of a text", validator: Length(2, 4), value: "żółw"},
+		{name: "too short", validator: Length(2, 4), value: "a", expected: "has to be at least 2 long"},
+		{name: "too long list", validator: Length(0, 1), value: []any{1, 2}, expected: "has to be at most 1 long"},
+		{name: "unlimited length", validator: Length(1, -1), value: "a very long text"},
+		{name: "in range", validator: Range(0, 100), value: 50},
+		{name: "out of range", validator: Range(0, 100), value: 100.5, expected: "has to be between 0 and 100"},
+		{name: "range of a text", validator: Range(0, 100), value: "50", expected: "expected a number"},
+		{name: "one of numbers", validator: OneOf(1, 2, 3), value: 2.0},
+		{name: "one of texts", validator: OneOf("open", "closed"), value: "archived", expected: "has to be one of open, closed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			err := tt.validator(tt.value, nil)
+
+			// then
+			if tt.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestField(t *testing.T) {
+	// given
+	Register("test_sku", Regex(`^[A-Z]{3}-\d{4}$`))
+	Register("test_short", Length(0, 8))
+	Register("test_reserved", func(value any, _ *gin.Context) error {
+		if value == "XXX-0000" {
+			return errors.New("is reserved")
+		}
+		return nil
+	})
+	validate := Field("sku", "test_sku", "test_short", "test_reserved")
+
+	// when
+	validErr := validate(models.InternalValue{"sku": "ABC-1234"}, nil)
+	missingErr := validate(models.InternalValue{"name": "Shoes"}, nil)
+	invalidErr := validate(models.InternalValue{"sku": "ABC-12345"}, nil)
+	reservedErr := validate(models.InternalValue{"sku": "XXX-0000"}, nil)
+
+	// then
+	assert.NoError(t, validErr)
+	assert.NoError(t, missingErr)
+	assert.Equal(t, &serializers.ValidationError{FieldErrors: map[string][]string{
+		"sku": {"does not match the pattern `^[A-Z]{3}-\\d{4}$`", "has to be at most 8 long"},
+	}}, invalidErr)
+	assert.Equal(t, &serializers.ValidationError{FieldErrors: map[string][]string{"sku": {"is reserved"}}}, reservedErr)
+	assert.Panics(t, func() { Register("test_sku", Length(1, 2)) })
+	assert.Panics(t, func() { Field("sku", "unknown") })
+}
+
+func actionCtx(action grfctx.Action) *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	grfctx.Set(ctx, grfctx.Metadata{Action: action})
+	return ctx
+}
+
+func TestConditionalValidation(t *testing.T) {
+	// given
+	requiredOnCreate := When(OnActions(grfctx.ActionCreate), Required("sku"))
+	requiredIfShipped := When(FieldEquals("status", "shipped"), Required("tracking_number"))
+	requiredUnlessDraft := When(Not(FieldEquals("status", "draft")), Required("title"))
+
+	// when
+	createErr := requiredOnCreate(models.InternalValue{}, actionCtx(grfctx.ActionCreate))
+	updateErr := requiredOnCreate(models.InternalValue{}, actionCtx(grfctx.ActionUpdate))
+	shippedErr := requiredIfShipped(models.InternalValue{"status": "shipped", "tracking_number": nil}, nil)
+	pendingErr := requiredIfShipped(models.InternalValue{"status": "pending"}, nil)
+	draftErr := requiredUnlessDraft(models.InternalValue{"status": "draft"}, nil)
+	partialErr := When(OnActions(grfctx.ActionPartialUpdate), Required("title"))(
+		models.InternalValue{"status": "published"}, actionCtx(grfctx.ActionPartialUpdate),
+	)
+
+	// then
+	assert.Equal(t, &serializers.ValidationError{FieldErrors: map[string][]string{
+		"sku": {"This field is required"},
+	}}, createErr)
+	assert.NoError(t, updateErr)
+	assert.Equal(t, &serializers.ValidationError{FieldErrors: map[string][]string{
+		"tracking_number": {"This field is required"},
+	}}, shippedErr)
+	assert.NoError(t, pendingErr)
+	assert.NoError(t, draftErr)
+	assert.NoError(t, partialErr)
+}
@@ -0,0 +1,317 @@
+package views
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// BulkValidationError holds the validation errors of the elements of a bulk request, indexed like
+// the elements, and the errors of the whole request, see serializers.ListValidationError
+type BulkValidationError = serializers.ListValidationError
+
+// BulkCreateModelViewSetFunc creates the objects sent in a JSON array, a single object is created
+// like with CreateModelViewSetFunc. All the elements are validated before any of them is created,
+// if any of them is invalid, the request is rejected with the errors of every element. The objects
+// are created in one transaction, if the query driver implements queries.Transactional.
+func BulkCreateModelViewSetFunc[Model any](idf IDFunc, qd queries.Driver[Model], serializer serializers.Serializer) gin.HandlerFunc {
+	return bulkCreate(idf, qd, serializers.NewListSerializer[Model](serializer))
+}
+
+func bulkCreate[Model any](idf IDFunc, qd queries.Driver[Model], serializer *serializers.ListSerializer[Model]) gin.HandlerFunc {
+	createOne := CreateModelViewSetFunc(idf, qd, serializer.Child())
+	return func(ctx *gin.Context) {
+		body, readErr := io.ReadAll(ctx.Request.Body)
+		if readErr != nil {
+			WriteError(ctx, readErr)
+			return
+		}
+		var rawElements []any
+		if parseErr := parserFor(ctx).Parse(bytes.NewReader(body), &rawElements); parseErr != nil {
+			// Not an array, the body is parsed again as a single object
+			ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+			createOne(ctx)
+			return
+		}
+		grfctx.DeferUntilSaved(ctx)
+		internalValues, fromRawErr := serializer.ToInternalValues(rawElements, ctx)
+		if fromRawErr != nil {
+			WriteError(ctx, fromRawErr)
+			return
+		}
+		created := make([]models.InternalValue, 0, len(internalValues))
+		if atomicErr := queries.Atomic(ctx, qd, func() error {
+			for _, internalValue := range internalValues {
+				createdValue, createErr := qd.CRUD().Create(ctx, internalValue)
+				if createErr != nil {
+					return createErr
+				}
+				created = append(created, createdValue)
+			}
+			return grfctx.RunOnSaved(ctx)
+		}); atomicErr != nil {
+			WriteError(ctx, atomicErr)
+			return
+		}
+		representations, serializeErr := serializer.ToRepresentations(created, ctx)
+		if serializeErr != nil {
+			WriteError(ctx, serializeErr)
+			return
+		}
+		render(ctx, http.StatusCreated, representations)
+	}
+}
+
+// WithBulkCreate enables the create action accepting JSON arrays of objects, see
+// BulkCreateModelViewSetFunc. The validators validate the whole arrays, for example
+// serializers.MaxItems or serializers.UniqueItems.
+func (v *ViewSet[Model]) WithBulkCreate(validators ...serializers.ListValidator) *ViewSet[Model] {
+	return v.WithCreate(func(idf IDFunc, qd queries.Driver[Model], serializer serializers.Serializer) gin.HandlerFunc {
+		return bulkCreate(idf, qd, serializers.NewListSerializer[Model](serializer, validators...))
+	})
+}
+
+// BulkItemResult is the result of a single element of a bulk update or destroy request
+type BulkItemResult struct {
+	ID      any                 `json:"id"`
+	Status  int                 `json:"status"`
+	Data    any                 `json:"data,omitempty"`
+	Errors  map[string][]string `json:"errors,omitempty"`
+	Message string              `json:"message,omitempty"`
+}
+
+// BulkReport is the response of bulk update and destroy requests, with the results ordered like the
+// elements of the request
+type BulkReport struct {
+	Results []BulkItemResult `json:"results"`
+}
+
+// bulkItem is an element of a bulk request, identified by the value of the lookup field
+type bulkItem struct {
+	id       any
+	raw      map[string]any
+	old      models.InternalValue
+	incoming models.InternalValue
+}
+
+// errBulkRejected rolls back the transaction of a bulk request, when any of its elements failed
+var errBulkRejected = errors.New("some of the elements of the bulk request failed")
+
+// BulkUpdateModelViewSetFunc updates the objects sent in a JSON array, every object has to contain
+// the lookup field, at most once. All the elements are validated and their objects retrieved before
+// any of them is updated: if any of them fails, nothing is updated and the response is a BulkReport
+// with 400 status, in the error format of the view. Otherwise the objects are updated and the report
+// holds their representations. The whole batch runs in one transaction, if the query driver
+// implements queries.Transactional, so the objects can't change between the validation and the
+// updates.
+func BulkUpdateModelViewSetFunc[Model any](_ IDFunc, qd queries.Driver[Model], serializer serializers.Serializer) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		items, ok := parseBulkItems(ctx, false)
+		if !ok {
+			return
+		}
+		report := BulkReport{Results: make([]BulkItemResult, len(items))}
+		grfctx.DeferUntilSaved(ctx)
+		atomicErr := queries.Atomic(ctx, qd, func() error {
+			valid := true
+			for i, item := range items {
+				report.Results[i] = BulkItemResult{ID: item.id, Status: http.StatusOK}
+				old, fromRawErr := qd.CRUD().Retrieve(ctx, item.id)
+				if fromRawErr == nil {
+					items[i].old = old
+					grfctx.SetStored(ctx, old)
+					items[i].incoming, fromRawErr = serializer.ToInternalValue(item.raw, ctx)
+					grfctx.SetStored(ctx, nil)
+				}
+				if fromRawErr != nil {
+					report.Results[i] = bulkItemError(item.id, fromRawErr)
+					valid = false
+				}
+			}
+			if !valid {
+				return errBulkRejected
+			}
+			for i, item := range items {
+				updated, updateErr := qd.CRUD().Update(
+					ctx, item.old, item.old.Merge(item.incoming, models.MergeReplace), item.id,
+				)
+				if updateErr != nil {
+					return updateErr
+				}
+				representation, serializeErr := serializer.ToRepresentation(updated, ctx)
+				if serializeErr != nil {
+					return serializeErr
+				}
+				report.Results[i].Data = representation
+			}
+			return grfctx.RunOnSaved(ctx)
+		})
+		writeBulkReport(ctx, report, atomicErr)
+	}
+}
+
+// BulkDestroyModelViewSetFunc deletes the objects identified by a JSON array of lookup field values
+// or objects containing the lookup field, each object at most once. If any of the objects doesn't
+// exist, nothing is deleted and the response is a BulkReport with 400 status, in the error format of
+// the view. The whole batch runs in one transaction, if the query driver implements
+// queries.Transactional.
+func BulkDestroyModelViewSetFunc[Model any](_ IDFunc, qd queries.Driver[Model], _ serializers.Serializer) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		items, ok := parseBulkItems(ctx, true)
+		if !ok {
+			return
+		}
+		report := BulkReport{Results: make([]BulkItemResult, len(items))}
+		atomicErr := queries.Atomic(ctx, qd, func() error {
+			valid := true
+			for i, item := range items {
+				report.Results[i] = BulkItemResult{ID: item.id, Status: http.StatusNoContent}
+				if _, retrieveErr := qd.CRUD().Retrieve(ctx, item.id); retrieveErr != nil {
+					report.Results[i] = bulkItemError(item.id, retrieveErr)
+					valid = false
+				}
+			}
+			if !valid {
+				return errBulkRejected
+			}
+			for _, item := range items {
+				if destroyErr := qd.CRUD().Destroy(ctx, item.id); destroyErr != nil {
+					return destroyErr
+				}
+			}
+			return nil
+		})
+		writeBulkReport(ctx, report, atomicErr)
+	}
+}
+
+// writeBulkReport writes the report of the bulk request, with 400 status if any of its elements
+// failed, or the error of the transaction
+func writeBulkReport(ctx *gin.Context, report BulkReport, atomicErr error) {
+	switch {
+	case errors.Is(atomicErr, errBulkRejected):
+		writeErrorResponse(ctx, http.StatusBadRequest, gin.H{"results": report.Results})
+	case atomicErr != nil:
+		WriteError(ctx, atomicErr)
+	default:
+		render(ctx, http.StatusOK, report)
+	}
+}
+
+// parseBulkItems reads the JSON array of the request body, writing the error response if it's
+// invalid. Scalar elements are accepted as lookup field values if acceptScalars is set.
+func parseBulkItems(ctx *gin.Context, acceptScalars bool) ([]bulkItem, bool) {
+	var rawElements []any
+	if parseErr := parseBody(ctx, &rawElements); parseErr != nil {
+		WriteError(ctx, &serializers.ValidationError{
+			FieldErrors: map[string][]string{"all": {"expected an array"}},
+		})
+		return nil, false
+	}
+	lookupField := grfctx.LookupField(ctx)
+	items := make([]bulkItem, len(rawElements))
+	seen := map[string]int{}
+	validationErr := &BulkValidationError{Errors: make([]map[string][]string, len(rawElements))}
+	valid := true
+	for i, rawElement := range rawElements {
+		validationErr.Errors[i] = map[string][]string{}
+		switch element := rawElement.(type) {
+		case map[string]any:
+			id, hasID := element[lookupField]
+			if !hasID || id == nil {
+				validationErr.Errors[i][lookupField] = []string{"this field is required"}
+				valid = false
+				continue
+			}
+			items[i] = bulkItem{id: id, raw: element}
+		case string, float64:
+			if !acceptScalars {
+				validationErr.Errors[i]["all"] = []string{"expected an object"}
+				valid = false
+				continue
+			}
+			items[i] = bulkItem{id: element}
+		default:
+			validationErr.Errors[i]["all"] = []string{"expected an object or " + lookupField}
+			valid = false
+			continue
+		}
+		// The IDs are compared as text, as the drivers parse the numbers and the strings alike
+		key := fmt.Sprint(items[i].id)
+		if first, duplicate := seen[key]; duplicate {
+			validationErr.Errors[i][lookupField] = []string{fmt.Sprintf("duplicates the element %d", first)}
+			valid = false
+			continue
+		}
+		seen[key] = i
+	}
+	if !valid {
+		WriteError(ctx, validationErr)
+		return nil, false
+	}
+	return items, true
+}
+
+func bulkItemError(id any, err error) BulkItemResult {
+	var validationErr *serializers.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return BulkItemResult{ID: id, Status: http.StatusBadRequest, Errors: validationErr.FieldErrors}
+	case errors.Is(err, common.ErrorNotFound):
+		return BulkItemResult{ID: id, Status: http.StatusNotFound, Message: err.Error()}
+	}
+	return BulkItemResult{ID: id, Status: http.StatusBadRequest, Message: err.Error()}
+}
+
+// WithBulkUpdate adds PUT and PATCH routes to the collection, updating the objects sent in JSON
+// arrays with the serializers of the update and partial update actions, see
+// BulkUpdateModelViewSetFunc. The routes are only added for the enabled actions.
+func (v *ViewSet[Model]) WithBulkUpdate() *ViewSet[Model] {
+	v.bulkUpdate = true
+	return v
+}
+
+// WithBulkDestroy adds a DELETE route to the collection, deleting the objects identified by
+// a JSON array, see BulkDestroyModelViewSetFunc. The route is only added if the destroy action is
+// enabled.
+func (v *ViewSet[Model]) WithBulkDestroy() *ViewSet[Model] {
+	v.bulkDestroy = true
+	return v
+}
+
+func (v *ViewSet[Model]) bulkActionRoutes(queryDriver queries.Driver[Model]) []*ViewRoute {
+	routes := []struct {
+		enabled bool
+		method  string
+		action  *ViewSetAction[Model]
+		id      ActionID
+		factory ViewSetHandlerFactoryFunc[Model]
+	}{
+		{v.bulkUpdate, http.MethodPut, v.UpdateAction, ActionUpdate, BulkUpdateModelViewSetFunc[Model]},
+		{v.bulkUpdate, http.MethodPatch, v.PartialUpdateAction, ActionPartialUpdate, BulkUpdateModelViewSetFunc[Model]},
+		{v.bulkDestroy, http.MethodDelete, v.DestroyAction, ActionDestroy, BulkDestroyModelViewSetFunc[Model]},
+	}
+	viewRoutes := []*ViewRoute{}
+	for _, route := range routes {
+		if !route.enabled || route.action == nil {
+			continue
+		}
+		viewRoutes = append(viewRoutes, &ViewRoute{
+			Method: route.method,
+			Handler: v.withMetadata(
+				grfctx.Metadata{Action: route.id, View: v.viewSettings(false)},
+				route.factory(v.IDFunc, queryDriver, v.withHooks(route.action.Serializer)),
+			),
+		})
+	}
+	return viewRoutes
+}
@@ -0,0 +1,313 @@
+package views
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/crud"
+	"github.com/glothriel/grf/pkg/queries/dummy"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkCreate(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		expectedCode  int
+		expectedBody  string
+		expectedCount int
+	}{
+		{
+			name:          "array of objects",
+			body:          `[{"name": "Canned Beans", "price": 1}, {"name": "Canned Peas", "price": 2}]`,
+			expectedCode:  201,
+			expectedBody:  `[{"id": 1, "name": "Canned Beans", "price": 1}, {"id": 2, "name": "Canned Peas", "price": 2}]`,
+			expectedCount: 2,
+		},
+		{
+			name:          "single object",
+			body:          `{"name": "Canned Beans", "price": 1}`,
+			expectedCode:  201,
+			expectedBody:  `{"id": 1, "name": "Canned Beans", "price": 1}`,
+			expectedCount: 1,
+		},
+		{
+			name:         "invalid elements",
+			body:         `[{"name": "Canned Beans", "price": 1}, {"name": "", "price": 2}, 3]`,
+			expectedCode: 400,
+			expectedBody: `{"errors": [{}, {"name": ["name is required"]}, {"all": ["expected an object"]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			viewset := NewViewSet[anotherMockModel](
+				"/mocks",
+				queries.InMemory[anotherMockModel](),
+				serializers.NewValidatingSerializer[anotherMockModel](
+					serializers.NewModelSerializer[anotherMockModel](),
+					nonEmptyNameValidator{},
+				),
+			).WithActions(ActionList).WithBulkCreate()
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			viewset.Register(r)
+
+			// when
+			response := quickReq(r, quickReqParams{method: "POST", path: "/mocks", body: strBody(tt.body)})
+			list := quickReq(r, quickReqParams{method: "GET", path: "/mocks", body: noBody})
+
+			// then
+			assert.Equal(t, tt.expectedCode, response.Code)
+			assert.JSONEq(t, tt.expectedBody, response.Body.String())
+			var listed []map[string]any
+			assert.NoError(t, json.Unmarshal(list.Body.Bytes(), &listed))
+			assert.Len(t, listed, tt.expectedCount)
+		})
+	}
+}
+
+func TestBulkCreateRollsBackOnDriverError(t *testing.T) {
+	// given
+	driver := queries.InMemory[anotherMockModel]()
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", driver).WithBulkCreate().WithMiddleware(
+		func(next OperationFunc) OperationFunc {
+			return func(op *Operation) (*OperationResult, error) {
+				if op.Kind == OperationCreate && op.InternalValue["name"] == "Broken" {
+					return nil, errors.New("insert failed")
+				}
+				return next(op)
+			}
+		},
+	)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	response := quickReq(r, quickReqParams{
+		method: "POST", path: "/mocks", body: strBody(`[{"name": "Canned Beans", "price": 1}, {"name": "Broken", "price": 2}]`),
+	})
+	list := quickReq(r, quickReqParams{method: "GET", path: "/mocks", body: noBody})
+
+	// then
+	assert.Equal(t, 500, response.Code)
+	assert.JSONEq(t, `[]`, list.Body.String())
+}
+
+func TestBulkCreateWithListValidators(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "too many elements",
+			body:         `[{"name": "A", "price": 1}, {"name": "B", "price": 2}, {"name": "C", "price": 3}]`,
+			expectedCode: 400,
+			expectedBody: `{"errors": [], "list_errors": ["expected at most 2 elements"]}`,
+		},
+		{
+			name:         "duplicated elements",
+			body:         `[{"name": "A", "price": 1}, {"name": "A", "price": 2}]`,
+			expectedCode: 400,
+			expectedBody: `{"errors": [{}, {"name": ["duplicates the element 0"]}]}`,
+		},
+		{
+			name:         "valid elements",
+			body:         `[{"name": "A", "price": 1}, {"name": "B", "price": 2}]`,
+			expectedCode: 201,
+			expectedBody: `[{"id": 1, "name": "A", "price": 1}, {"id": 2, "name": "B", "price": 2}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel]()).
+				WithBulkCreate(serializers.MaxItems(2), serializers.UniqueItems("name"))
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			viewset.Register(r)
+
+			// when
+			response := quickReq(r, quickReqParams{method: "POST", path: "/mocks", body: strBody(tt.body)})
+
+			// then
+			assert.Equal(t, tt.expectedCode, response.Code)
+			assert.JSONEq(t, tt.expectedBody, response.Body.String())
+		})
+	}
+}
+
+type nonEmptyNameValidator struct{}
+
+func (nonEmptyNameValidator) Validate(intVal models.InternalValue) error {
+	if intVal["name"] == "" {
+		return &serializers.ValidationError{FieldErrors: map[string][]string{"name": {"name is required"}}}
+	}
+	return nil
+}
+
+func newBulkRouter() *gin.Engine {
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+		anotherMockModel{Price: 2.0, Name: "Canned Peas"},
+		anotherMockModel{Price: 3.0, Name: "Canned Corn"},
+	)).WithBulkUpdate().WithBulkDestroy()
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+	return r
+}
+
+func TestBulkUpdateAndDestroy(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		body          string
+		expectedCode  int
+		expectedBody  string
+		expectedNames []string
+	}{
+		{
+			name:         "partial update",
+			method:       "PATCH",
+			body:         `[{"id": 1, "price": 10}, {"id": 2, "name": "Peas"}]`,
+			expectedCode: 200,
+			expectedBody: `{"results": [
+				{"id": 1, "status": 200, "data": {"id": 1, "name": "Canned Beans", "price": 10}},
+				{"id": 2, "status": 200, "data": {"id": 2, "name": "Peas", "price": 2}}
+			]}`,
+			expectedNames: []string{"Canned Beans", "Peas", "Canned Corn"},
+		},
+		{
+			name:         "update with missing object changes nothing",
+			method:       "PUT",
+			body:         `[{"id": 1, "name": "Beans", "price": 10}, {"id": 7, "name": "Rice", "price": 1}]`,
+			expectedCode: 400,
+			expectedBody: `{"results": [
+				{"id": 1, "status": 200},
+				{"id": 7, "status": 404, "message": "not found"}
+			]}`,
+			expectedNames: []string{"Canned Beans", "Canned Peas", "Canned Corn"},
+		},
+		{
+			name:          "update without lookup field",
+			method:        "PATCH",
+			body:          `[{"name": "Beans"}]`,
+			expectedCode:  400,
+			expectedBody:  `{"errors": [{"id": ["this field is required"]}]}`,
+			expectedNames: []string{"Canned Beans", "Canned Peas", "Canned Corn"},
+		},
+		{
+			name:          "update with duplicate IDs changes nothing",
+			method:        "PATCH",
+			body:          `[{"id": 1, "price": 10}, {"id": 1, "price": 20}]`,
+			expectedCode:  400,
+			expectedBody:  `{"errors": [{}, {"id": ["duplicates the element 0"]}]}`,
+			expectedNames: []string{"Canned Beans", "Canned Peas", "Canned Corn"},
+		},
+		{
+			name:          "destroy with duplicate IDs deletes nothing",
+			method:        "DELETE",
+			body:          `[1, {"id": 1}]`,
+			expectedCode:  400,
+			expectedBody:  `{"errors": [{}, {"id": ["duplicates the element 0"]}]}`,
+			expectedNames: []string{"Canned Beans", "Canned Peas", "Canned Corn"},
+		},
+		{
+			name:          "destroy by IDs and objects",
+			method:        "DELETE",
+			body:          `[1, {"id": 3}]`,
+			expectedCode:  200,
+			expectedBody:  `{"results": [{"id": 1, "status": 204}, {"id": 3, "status": 204}]}`,
+			expectedNames: []string{"Canned Peas"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			r := newBulkRouter()
+
+			// when
+			response := quickReq(r, quickReqParams{method: tt.method, path: "/mocks", body: strBody(tt.body)})
+			list := quickReq(r, quickReqParams{method: "GET", path: "/mocks", body: noBody})
+
+			// then
+			assert.Equal(t, tt.expectedCode, response.Code)
+			assert.JSONEq(t, tt.expectedBody, response.Body.String())
+			var listed []map[string]any
+			assert.NoError(t, json.Unmarshal(list.Body.Bytes(), &listed))
+			names := []string{}
+			for _, element := range listed {
+				names = append(names, element["name"].(string))
+			}
+			assert.ElementsMatch(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestBulkUpdateReportProblemDetails(t *testing.T) {
+	// given
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+	)).WithBulkUpdate().WithErrorFormat(ErrorFormatProblem).Register(r)
+
+	// when
+	response := quickReq(r, quickReqParams{method: "PATCH", path: "/mocks", body: strBody(`[{"id": 7, "price": 1}]`)})
+
+	// then
+	assert.Equal(t, 400, response.Code)
+	assert.Equal(t, "application/problem+json", response.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank", "title": "Bad Request", "status": 400, "instance": "/mocks",
+		"detail": "the request is invalid", "results": [{"id": 7, "status": 404, "message": "not found"}]
+	}`, response.Body.String())
+}
+
+// transactionRecordingDriver counts the objects retrieved outside of the transactions
+type transactionRecordingDriver struct {
+	*dummy.InMemoryQueryDriver[anotherMockModel]
+	inTransaction    bool
+	retrievedOutside int
+}
+
+func (d *transactionRecordingDriver) Atomic(ctx *gin.Context, fn func() error) error {
+	d.inTransaction = true
+	defer func() { d.inTransaction = false }()
+	return d.InMemoryQueryDriver.Atomic(ctx, fn)
+}
+
+func (d *transactionRecordingDriver) CRUD() *crud.CRUD[anotherMockModel] {
+	queries := d.InMemoryQueryDriver.CRUD()
+	retrieve := queries.Retrieve
+	return queries.WithRetrieve(func(ctx *gin.Context, id any) (models.InternalValue, error) {
+		if !d.inTransaction {
+			d.retrievedOutside++
+		}
+		return retrieve(ctx, id)
+	})
+}
+
+func TestBulkUpdateAndDestroyRetrieveTheObjectsInTheTransaction(t *testing.T) {
+	// given
+	driver := &transactionRecordingDriver{InMemoryQueryDriver: queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+		anotherMockModel{Price: 2.0, Name: "Canned Peas"},
+	)}
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	NewModelViewSet[anotherMockModel]("/mocks", driver).WithBulkUpdate().WithBulkDestroy().Register(r)
+
+	// when
+	updated := quickReq(r, quickReqParams{method: "PATCH", path: "/mocks", body: strBody(`[{"id": 1, "price": 10}]`)})
+	destroyed := quickReq(r, quickReqParams{method: "DELETE", path: "/mocks", body: strBody(`[1, 2]`)})
+
+	// then
+	assert.Equal(t, 200, updated.Code)
+	assert.Equal(t, 200, destroyed.Code)
+	assert.Equal(t, 0, driver.retrievedOutside)
+}
@@ -0,0 +1,161 @@
+package views
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/glothriel/grf/pkg/tombstones"
+)
+
+// ChangesSinceQueryParam is the query parameter holding the cursor returned by the previous sync
+const ChangesSinceQueryParam = "since"
+
+// ChangesAction adds a `GET <path>/changes?since=<cursor>` endpoint, returning the objects created,
+// updated and deleted since the cursor, so offline clients can sync incrementally instead of
+// re-downloading the whole collection:
+//
+//	{"created": [...], "updated": [...], "deleted": [1, 2], "cursor": "2024-01-01T00:00:00Z"}
+//
+// Requests without the cursor return all the objects as created. The objects are listed using the
+// query driver, filtered by the `updated_at > since` predicate, see grfctx.AddPredicates, so only the
+// changed objects are loaded.
+type ChangesAction[Model any] struct {
+	updatedAtField string
+	createdAtField string
+	tombstones     tombstones.Tombstones
+}
+
+// WithCreatedAtField allows telling apart created and updated objects, without it, all the changed
+// objects are reported as updated
+func (a *ChangesAction[Model]) WithCreatedAtField(field string) *ChangesAction[Model] {
+	a.createdAtField = field
+	return a
+}
+
+// WithTombstones sets the storage of the deleted objects' IDs, without it the tombstones of the
+// viewset are used, if any, and if there are none, deletions are not reported. WithChanges makes
+// the viewset record the deletions in the tombstones, the IDs are reported as they appeared in the
+// URL of the destroy request.
+func (a *ChangesAction[Model]) WithTombstones(t tombstones.Tombstones) *ChangesAction[Model] {
+	a.tombstones = t
+	return a
+}
+
+func (a *ChangesAction[Model]) handler(_ IDFunc, qd queries.Driver[Model], serializer serializers.Serializer) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		cursor := time.Now().UTC()
+		var since time.Time
+		if rawSince := ctx.Query(ChangesSinceQueryParam); rawSince != "" {
+			var parseErr error
+			since, parseErr = time.Parse(time.RFC3339Nano, rawSince)
+			if parseErr != nil {
+				WriteError(ctx, &serializers.ValidationError{FieldErrors: map[string][]string{
+					ChangesSinceQueryParam: {"invalid cursor"},
+				}})
+				return
+			}
+			ctx.Set(changesSinceCtxKey, since)
+			grfctx.AddPredicates(ctx, grfctx.Predicate{
+				Field: a.updatedAtField, Operator: grfctx.OperatorGt, Value: since,
+			})
+		}
+		qd.Filter().Apply(ctx)
+		qd.Order().Apply(ctx)
+		internalValues, listErr := qd.CRUD().List(ctx)
+		if listErr != nil {
+			WriteError(ctx, listErr)
+			return
+		}
+		created, updated := []any{}, []any{}
+		for _, internalValue := range internalValues {
+			representation, toRawErr := serializer.ToRepresentation(internalValue, ctx)
+			if toRawErr != nil {
+				WriteError(ctx, toRawErr)
+				return
+			}
+			if since.IsZero() || a.isCreatedSince(internalValue, since) {
+				created = append(created, representation)
+			} else {
+				updated = append(updated, representation)
+			}
+		}
+		deleted := []any{}
+		if a.tombstones != nil && !since.IsZero() {
+			deletedSince, tombstonesErr := a.tombstones.DeletedSince(ctx, since)
+			if tombstonesErr != nil {
+				WriteError(ctx, tombstonesErr)
+				return
+			}
+			deleted = append(deleted, deletedSince...)
+		}
+		render(ctx, http.StatusOK, gin.H{
+			"created": created,
+			"updated": updated,
+			"deleted": deleted,
+			"cursor":  cursor.Format(time.RFC3339Nano),
+		})
+	}
+}
+
+func (a *ChangesAction[Model]) isCreatedSince(internalValue models.InternalValue, since time.Time) bool {
+	if a.createdAtField == "" {
+		return false
+	}
+	createdAt, createdAtErr := internalValue.GetTime(a.createdAtField)
+	return createdAtErr == nil && createdAt.After(since)
+}
+
+// NewChangesAction creates a ChangesAction, that detects changes using the updatedAtField, which
+// has to be updated on every modification of the object
+func NewChangesAction[Model any](updatedAtField string) *ChangesAction[Model] {
+	return &ChangesAction[Model]{updatedAtField: updatedAtField}
+}
+
+// WithChanges registers the changes action on the viewset's list path
+func (v *ViewSet[Model]) WithChanges(action *ChangesAction[Model]) *ViewSet[Model] {
+	if action.tombstones == nil {
+		action.tombstones = v.tombstones
+	} else if v.tombstones == nil {
+		v.WithTombstones(action.tombstones)
+	}
+	serializer := v.DefaultSerializer
+	if v.ListAction != nil {
+		serializer = v.ListAction.Serializer
+	}
+	return v.WithExtraAction(NewExtraAction[Model](http.MethodGet, "/changes", action.handler), serializer, false)
+}
+
+// WithTombstones makes the viewset record the IDs of the destroyed objects in the tombstones, for
+// example for delta sync or webhooks replay
+func (v *ViewSet[Model]) WithTombstones(t tombstones.Tombstones) *ViewSet[Model] {
+	v.tombstones = t
+	return v.WithMiddleware(func(next OperationFunc) OperationFunc {
+		return func(op *Operation) (*OperationResult, error) {
+			result, err := next(op)
+			if err == nil && op.Kind == OperationDestroy {
+				if recordErr := t.Record(op.Ctx, op.ID); recordErr != nil {
+					return nil, recordErr
+				}
+			}
+			return result, err
+		}
+	})
+}
+
+const changesSinceCtxKey = "grf:changes:since"
+
+// ChangesSince returns the cursor of the changes action being handled, the query drivers get it as
+// the predicate of the updated at field too
+func ChangesSince(ctx *gin.Context) (time.Time, bool) {
+	since, ok := ctx.Get(changesSinceCtxKey)
+	if !ok {
+		return time.Time{}, false
+	}
+	sinceTime, ok := since.(time.Time)
+	return sinceTime, ok
+}
@@ -0,0 +1,135 @@
+package views
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/tombstones"
+	"github.com/stretchr/testify/assert"
+)
+
+type syncedModel struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type changesResponse struct {
+	Created []syncedModel `json:"created"`
+	Updated []syncedModel `json:"updated"`
+	Deleted []any         `json:"deleted"`
+	Cursor  string        `json:"cursor"`
+}
+
+func changedNames(models []syncedModel) []string {
+	names := []string{}
+	for _, m := range models {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+func TestChangesAction(t *testing.T) {
+	// given
+	at := func(hour int) time.Time { return time.Date(2024, 1, 1, hour, 0, 0, 0, time.UTC) }
+	viewset := NewModelViewSet[syncedModel]("/things", queries.InMemory[syncedModel](
+		syncedModel{Name: "unchanged", CreatedAt: at(10), UpdatedAt: at(10)},
+		syncedModel{Name: "updated", CreatedAt: at(10), UpdatedAt: at(12)},
+		syncedModel{Name: "created", CreatedAt: at(12), UpdatedAt: at(12)},
+	)).WithChanges(
+		NewChangesAction[syncedModel]("updated_at").WithCreatedAtField("created_at").WithTombstones(
+			tombstones.NewInMemory(time.Hour),
+		),
+	)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+	changes := func(path string) (int, changesResponse) {
+		w := quickReq(r, quickReqParams{method: "GET", path: path, body: strBody("")})
+		var response changesResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	// when
+	beforeDeleteCursor := time.Now().UTC().Format(time.RFC3339Nano)
+	deleted := quickReq(r, quickReqParams{method: "DELETE", path: "/things/1", body: strBody("")})
+	fullCode, full := changes("/things/changes")
+	sinceCode, since := changes("/things/changes?since=2024-01-01T11:00:00Z")
+	_, afterDelete := changes("/things/changes?since=" + beforeDeleteCursor)
+	invalidCode, _ := changes("/things/changes?since=yesterday")
+
+	// then
+	assert.Equal(t, 204, deleted.Code)
+	assert.Equal(t, 200, fullCode)
+	assert.ElementsMatch(t, []string{"updated", "created"}, changedNames(full.Created))
+	assert.Empty(t, full.Updated)
+	assert.Empty(t, full.Deleted)
+	assert.NotEmpty(t, full.Cursor)
+	assert.Equal(t, 200, sinceCode)
+	assert.Equal(t, []string{"created"}, changedNames(since.Created))
+	assert.Equal(t, []string{"updated"}, changedNames(since.Updated))
+	assert.Equal(t, []any{"1"}, since.Deleted)
+	assert.Empty(t, afterDelete.Created)
+	assert.Empty(t, afterDelete.Updated)
+	assert.Equal(t, []any{"1"}, afterDelete.Deleted)
+	assert.Equal(t, 400, invalidCode)
+}
+
+func TestChangesSinceIsExposedToQueryDriver(t *testing.T) {
+	// given
+	var seen []time.Time
+	var predicates []grfctx.Predicate
+	viewset := NewModelViewSet[syncedModel]("/things", queries.InMemory[syncedModel]()).WithMiddleware(
+		func(next OperationFunc) OperationFunc {
+			return func(op *Operation) (*OperationResult, error) {
+				since, ok := ChangesSince(op.Ctx)
+				if ok {
+					seen = append(seen, since)
+					predicates = append(predicates, grfctx.Predicates(op.Ctx)...)
+				}
+				return next(op)
+			}
+		},
+	).WithChanges(NewChangesAction[syncedModel]("updated_at"))
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	quickReq(r, quickReqParams{method: "GET", path: "/things/changes?since=2024-01-01T11:00:00Z", body: strBody("")})
+	quickReq(r, quickReqParams{method: "GET", path: "/things", body: strBody("")})
+
+	// then
+	assert.Equal(t, []time.Time{time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)}, seen)
+	assert.Equal(t, []grfctx.Predicate{{
+		Field: "updated_at", Operator: grfctx.OperatorGt, Value: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+	}}, predicates)
+}
+
+func TestViewSetWithTombstonesRecordsDestroyedObjects(t *testing.T) {
+	// given
+	storage := tombstones.NewInMemory(0)
+	viewset := NewModelViewSet[syncedModel]("/things", queries.InMemory[syncedModel](
+		syncedModel{Name: "first"}, syncedModel{Name: "second"},
+	)).WithTombstones(storage)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+	from := time.Now()
+
+	// when
+	deleted := quickReq(r, quickReqParams{method: "DELETE", path: "/things/2", body: strBody("")})
+	missing := quickReq(r, quickReqParams{method: "DELETE", path: "/things/3", body: strBody("")})
+	recorded, recordedErr := storage.DeletedBetween(nil, from, time.Now().Add(time.Second))
+
+	// then
+	assert.Equal(t, 204, deleted.Code)
+	assert.Equal(t, 404, missing.Code)
+	assert.NoError(t, recordedErr)
+	assert.Len(t, recorded, 1)
+	assert.Equal(t, "2", recorded[0].ID)
+}
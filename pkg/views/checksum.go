@@ -0,0 +1,51 @@
+package views
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Checksum returns the hex encoded SHA-256 hash of the JSON encoded fields of the internal value,
+// the fields are encoded in the order of their names, so the hash doesn't depend on the order of
+// the arguments
+func Checksum(internalValue models.InternalValue, fieldNames ...string) (string, error) {
+	selected := make(map[string]any, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		selected[fieldName] = internalValue[fieldName]
+	}
+	encoded, encodeErr := json.Marshal(selected)
+	if encodeErr != nil {
+		return "", fmt.Errorf("could not encode the fields of the checksum: %w", encodeErr)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// WithChecksum stores the checksum of the source fields in the field on every create and update,
+// see Checksum, so the downstream systems can cheaply detect the changes of the objects or use it
+// as a cache key. The values of the field sent by the clients are overwritten, so it should be
+// read-only in the serializers, see serializers.ModelSerializer.ReadOnlyFields.
+func (v *ViewSet[Model]) WithChecksum(field string, sourceFields ...string) *ViewSet[Model] {
+	if len(sourceFields) == 0 {
+		logrus.Panicf("Checksum field `%s` requires at least one source field", field)
+	}
+	return v.WithMiddleware(func(next OperationFunc) OperationFunc {
+		return func(op *Operation) (*OperationResult, error) {
+			if op.Kind != OperationCreate && op.Kind != OperationUpdate {
+				return next(op)
+			}
+			checksum, checksumErr := Checksum(op.InternalValue, sourceFields...)
+			if checksumErr != nil {
+				return nil, checksumErr
+			}
+			op.InternalValue = op.InternalValue.Clone()
+			op.InternalValue[field] = checksum
+			return next(op)
+		}
+	})
+}
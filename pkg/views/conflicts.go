@@ -0,0 +1,155 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+)
+
+// ErrConflict is returned by conflict strategies rejecting updates of stale objects
+var ErrConflict = errors.New("the object was modified by another request")
+
+// ConflictError is reported with 409 Conflict, along with the current server copy of the object,
+// so the client can resolve the conflict and retry
+type ConflictError struct {
+	Current any
+}
+
+func (e *ConflictError) Error() string {
+	return ErrConflict.Error()
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// ConflictStrategy resolves an update based on a stale version of the object. It returns the
+// internal value to save, nil to skip the update and respond with the stored object, or an error.
+// The incoming internal value is already merged with the stored one, so it only differs from it in
+// the fields, that were sent by the client.
+type ConflictStrategy func(stored, incoming models.InternalValue) (models.InternalValue, error)
+
+// RejectConflicts rejects stale updates with 409 Conflict and the server copy of the object
+func RejectConflicts() ConflictStrategy {
+	return func(_, _ models.InternalValue) (models.InternalValue, error) {
+		return nil, ErrConflict
+	}
+}
+
+// LastWriteWins compares the timestampField of the stale update with the stored one, the update
+// is saved if it's newer, otherwise it's discarded and the server copy is returned. The timestamps
+// are set by the clients, so their clocks have to be reasonably synchronized.
+func LastWriteWins(timestampField string) ConflictStrategy {
+	return func(stored, incoming models.InternalValue) (models.InternalValue, error) {
+		storedAt, storedErr := stored.GetTime(timestampField)
+		if storedErr != nil {
+			return nil, storedErr
+		}
+		incomingAt, incomingErr := incoming.GetTime(timestampField)
+		if incomingErr != nil {
+			return nil, incomingErr
+		}
+		if incomingAt.After(storedAt) {
+			return incoming, nil
+		}
+		return nil, nil
+	}
+}
+
+// MergeConflicts saves the fields sent by the client on top of the server copy, keeping the
+// changes of other fields made since the client's version. It's meant for PATCH requests, as
+// clients send all the fields with PUT requests, overwriting the newer server values.
+func MergeConflicts() ConflictStrategy {
+	return func(_, incoming models.InternalValue) (models.InternalValue, error) {
+		return incoming, nil
+	}
+}
+
+// WithConflicts enables optimistic locking of the viewset's updates using the versionField, which
+// has to be a number. Every update increments the version and updates sending a version other than
+// the stored one are stale: they are resolved using the strategy. Updates without the version are
+// not checked. The update is only saved if the stored version didn't change since it was read, see
+// grfctx.SetUpdatePrecondition, otherwise it's rejected with 409 Conflict and the new server copy.
+func (v *ViewSet[Model]) WithConflicts(versionField string, strategy ConflictStrategy) *ViewSet[Model] {
+	return v.WithMiddleware(func(next OperationFunc) OperationFunc {
+		return func(op *Operation) (*OperationResult, error) {
+			if op.Kind != OperationUpdate {
+				return next(op)
+			}
+			stored, incoming := op.OldInternalValue, op.InternalValue
+			if fmt.Sprint(stored[versionField]) != fmt.Sprint(incoming[versionField]) {
+				resolved, resolveErr := strategy(stored, incoming)
+				if errors.Is(resolveErr, ErrConflict) {
+					return nil, v.conflictError(op, stored)
+				}
+				if resolveErr != nil {
+					return nil, resolveErr
+				}
+				if resolved == nil {
+					return &OperationResult{InternalValue: stored}, nil
+				}
+				incoming = resolved
+			}
+			nextVersion, versionErr := incrementVersion(stored[versionField])
+			if versionErr != nil {
+				return nil, versionErr
+			}
+			op.InternalValue = incoming.Clone()
+			op.InternalValue[versionField] = nextVersion
+			grfctx.SetUpdatePrecondition(op.Ctx, &grfctx.Predicate{
+				Field: versionField, Operator: grfctx.OperatorExact, Value: stored[versionField],
+			})
+			result, err := next(op)
+			grfctx.SetUpdatePrecondition(op.Ctx, nil)
+			if !errors.Is(err, common.ErrPreconditionFailed) {
+				return result, err
+			}
+			// another request updated the object since it was read
+			current, retrieveErr := next(&Operation{
+				Ctx: op.Ctx, Action: op.Action, Kind: OperationRetrieve, ModelName: op.ModelName, ID: op.ID,
+			})
+			if retrieveErr != nil {
+				return nil, retrieveErr
+			}
+			return nil, v.conflictError(op, current.InternalValue)
+		}
+	})
+}
+
+func (v *ViewSet[Model]) conflictError(op *Operation, stored models.InternalValue) error {
+	action := v.UpdateAction
+	if op.Action == ActionPartialUpdate && v.PartialUpdateAction != nil {
+		action = v.PartialUpdateAction
+	}
+	if action == nil {
+		return &ConflictError{}
+	}
+	current, toRawErr := v.withHooks(action.Serializer).ToRepresentation(stored, op.Ctx)
+	if toRawErr != nil {
+		return toRawErr
+	}
+	return &ConflictError{Current: current}
+}
+
+func incrementVersion(version any) (any, error) {
+	value := reflect.ValueOf(version)
+	if !value.IsValid() {
+		return nil, errors.New("version field is missing")
+	}
+	next := reflect.New(value.Type()).Elem()
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		next.SetInt(value.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		next.SetUint(value.Uint() + 1)
+	case reflect.Float32, reflect.Float64:
+		next.SetFloat(value.Float() + 1)
+	default:
+		return nil, fmt.Errorf("version field has to be a number, got %T", version)
+	}
+	return next.Interface(), nil
+}
@@ -0,0 +1,142 @@
+package views
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+type versionedModel struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	Version   uint   `json:"version"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func newVersionedRouter(strategy ConflictStrategy) *gin.Engine {
+	viewset := NewModelViewSet[versionedModel]("/things", queries.InMemory[versionedModel](
+		versionedModel{Name: "server", Color: "red", Version: 2, UpdatedAt: "2024-01-01T12:00:00Z"},
+	)).WithConflicts("version", strategy)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+	return r
+}
+
+func TestConflictStrategies(t *testing.T) {
+	tests := []struct {
+		name         string
+		strategy     ConflictStrategy
+		method       string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "current version is saved and incremented",
+			strategy:     RejectConflicts(),
+			method:       "PUT",
+			body:         `{"name": "client", "color": "blue", "version": 2, "updated_at": "2024-01-01T13:00:00Z"}`,
+			expectedCode: 200,
+			expectedBody: `{"id": 1, "name": "client", "color": "blue", "version": 3, "updated_at": "2024-01-01T13:00:00Z"}`,
+		},
+		{
+			name:         "update without version is not checked",
+			strategy:     RejectConflicts(),
+			method:       "PATCH",
+			body:         `{"name": "client"}`,
+			expectedCode: 200,
+			expectedBody: `{"id": 1, "name": "client", "color": "red", "version": 3, "updated_at": "2024-01-01T12:00:00Z"}`,
+		},
+		{
+			name:         "stale version is rejected with server copy",
+			strategy:     RejectConflicts(),
+			method:       "PUT",
+			body:         `{"name": "client", "color": "blue", "version": 1, "updated_at": "2024-01-01T13:00:00Z"}`,
+			expectedCode: 409,
+			expectedBody: `{
+				"message": "the object was modified by another request",
+				"current": {"id": 1, "name": "server", "color": "red", "version": 2, "updated_at": "2024-01-01T12:00:00Z"}
+			}`,
+		},
+		{
+			name:         "stale but newer write wins",
+			strategy:     LastWriteWins("updated_at"),
+			method:       "PUT",
+			body:         `{"name": "client", "color": "blue", "version": 1, "updated_at": "2024-01-01T13:00:00Z"}`,
+			expectedCode: 200,
+			expectedBody: `{"id": 1, "name": "client", "color": "blue", "version": 3, "updated_at": "2024-01-01T13:00:00Z"}`,
+		},
+		{
+			name:         "stale and older write is discarded",
+			strategy:     LastWriteWins("updated_at"),
+			method:       "PUT",
+			body:         `{"name": "client", "color": "blue", "version": 1, "updated_at": "2024-01-01T11:00:00Z"}`,
+			expectedCode: 200,
+			expectedBody: `{"id": 1, "name": "server", "color": "red", "version": 2, "updated_at": "2024-01-01T12:00:00Z"}`,
+		},
+		{
+			name:         "stale fields are merged into server copy",
+			strategy:     MergeConflicts(),
+			method:       "PATCH",
+			body:         `{"color": "blue", "version": 1}`,
+			expectedCode: 200,
+			expectedBody: `{"id": 1, "name": "server", "color": "blue", "version": 3, "updated_at": "2024-01-01T12:00:00Z"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			r := newVersionedRouter(tt.strategy)
+
+			// when
+			w := quickReq(r, quickReqParams{method: tt.method, path: "/things/1", body: strBody(tt.body)})
+			stored := quickReq(r, quickReqParams{method: "GET", path: "/things/1", body: noBody})
+
+			// then
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			if tt.expectedCode == 200 {
+				assert.JSONEq(t, tt.expectedBody, stored.Body.String())
+			}
+		})
+	}
+}
+
+func TestConflictsRejectConcurrentUpdates(t *testing.T) {
+	// given
+	driver := queries.InMemory[versionedModel](versionedModel{Name: "server", Color: "red", Version: 2})
+	viewset := NewModelViewSet[versionedModel]("/things", driver).
+		WithConflicts("version", MergeConflicts()).
+		WithMiddleware(func(next OperationFunc) OperationFunc {
+			return func(op *Operation) (*OperationResult, error) {
+				if op.Kind == OperationUpdate {
+					// another request saves the object after it was read by this one
+					concurrentCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+					_, concurrentErr := driver.CRUD().Update(concurrentCtx, op.OldInternalValue, models.InternalValue{
+						"id": uint(1), "name": "concurrent", "color": "green", "version": uint(3), "updated_at": "",
+					}, uint(1))
+					assert.NoError(t, concurrentErr)
+				}
+				return next(op)
+			}
+		})
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	w := quickReq(r, quickReqParams{method: "PATCH", path: "/things/1", body: strBody(`{"color": "blue", "version": 2}`)})
+	stored := quickReq(r, quickReqParams{method: "GET", path: "/things/1", body: noBody})
+
+	// then
+	assert.Equal(t, 409, w.Code)
+	assert.JSONEq(t, `{
+		"message": "the object was modified by another request",
+		"current": {"id": 1, "name": "concurrent", "color": "green", "version": 3, "updated_at": ""}
+	}`, w.Body.String())
+	assert.JSONEq(t, `{"id": 1, "name": "concurrent", "color": "green", "version": 3, "updated_at": ""}`, stored.Body.String())
+}
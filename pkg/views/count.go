@@ -0,0 +1,33 @@
+package views
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/glothriel/grf/pkg/queries"
+)
+
+// CountResponse is the response of the `/count` route
+type CountResponse struct {
+	Count int `json:"count"`
+}
+
+// WithCount adds a `/count` collection route, responding with the number of the objects matching
+// the filters of the list action, like `{"count": 42}`. The objects are counted using
+// queries.Counter, which the GORM driver executes as COUNT(*). Drivers not implementing it list all
+// the objects instead.
+func (v *ViewSet[Model]) WithCount() *ViewSet[Model] {
+	return v.WithExtraAction(NewExtraActionFunc(http.MethodGet, "/count", func(ctx *ActionContext[Model]) error {
+		ctx.QueryDriver.Filter().Apply(ctx.Context)
+		count, countErr := queries.Count(ctx.Context, ctx.QueryDriver)
+		if errors.Is(countErr, queries.ErrCountUnsupported) {
+			internalValues, listErr := ctx.QueryDriver.CRUD().List(ctx.Context)
+			count, countErr = len(internalValues), listErr
+		}
+		if countErr != nil {
+			return countErr
+		}
+		render(ctx.Context, http.StatusOK, CountResponse{Count: count})
+		return nil
+	}), nil, false)
+}
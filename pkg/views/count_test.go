@@ -0,0 +1,35 @@
+package views
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver queries.Driver[anotherMockModel]
+	}{
+		{name: "counting driver", driver: queries.InMemory(seedProducts(3)...)},
+		{name: "driver without counting", driver: plainDriver[anotherMockModel]{queries.InMemory(seedProducts(3)...)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			viewset := NewModelViewSet[anotherMockModel]("/mocks", tt.driver).WithCount()
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			viewset.Register(r)
+
+			// when
+			response := quickReq(r, quickReqParams{method: "GET", path: "/mocks/count", body: noBody})
+
+			// then
+			assert.Equal(t, 200, response.Code)
+			assert.JSONEq(t, `{"count": 3}`, response.Body.String())
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
 	"github.com/glothriel/grf/pkg/queries"
 	"github.com/glothriel/grf/pkg/serializers"
 )
@@ -13,18 +14,22 @@ import (
 func CreateModelViewSetFunc[Model any](idf IDFunc, qd queries.Driver[Model], serializer serializers.Serializer) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var rawElement map[string]any
-		if parseErr := ctx.ShouldBindJSON(&rawElement); parseErr != nil {
+		if parseErr := parseBody(ctx, &rawElement); parseErr != nil {
 			WriteError(ctx, parseErr)
 			return
 		}
+		grfctx.DeferUntilSaved(ctx)
 		internalValue, fromRawErr := serializer.ToInternalValue(rawElement, ctx)
 		if fromRawErr != nil {
 			WriteError(ctx, fromRawErr)
 			return
 		}
-		internalValue, createErr := qd.CRUD().Create(ctx, internalValue)
-		if createErr != nil {
-			WriteError(ctx, createErr)
+		if saveErr := saveWithCallbacks(ctx, qd, func() error {
+			var createErr error
+			internalValue, createErr = qd.CRUD().Create(ctx, internalValue)
+			return createErr
+		}); saveErr != nil {
+			WriteError(ctx, saveErr)
 			return
 		}
 		representation, serializeErr := serializer.ToRepresentation(internalValue, ctx)
@@ -32,6 +37,24 @@ func CreateModelViewSetFunc[Model any](idf IDFunc, qd queries.Driver[Model], ser
 			WriteError(ctx, serializeErr)
 			return
 		}
-		ctx.JSON(http.StatusCreated, representation)
+		render(ctx, http.StatusCreated, representation)
 	}
 }
+
+// saveWithCallbacks saves the object and runs the callbacks deferred by its conversion, see
+// grfctx.OnSaved, in one transaction, so the object isn't saved if any of them fails. The objects
+// without the callbacks are saved without the transaction.
+func saveWithCallbacks[Model any](ctx *gin.Context, qd queries.Driver[Model], save func() error) error {
+	if !grfctx.HasOnSaved(ctx) {
+		if saveErr := save(); saveErr != nil {
+			return saveErr
+		}
+		return grfctx.RunOnSaved(ctx)
+	}
+	return queries.Atomic(ctx, qd, func() error {
+		if saveErr := save(); saveErr != nil {
+			return saveErr
+		}
+		return grfctx.RunOnSaved(ctx)
+	})
+}
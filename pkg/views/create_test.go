@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/grfctx"
 	"github.com/glothriel/grf/pkg/models"
 	"github.com/glothriel/grf/pkg/queries"
 	"github.com/glothriel/grf/pkg/queries/dummy"
@@ -140,3 +141,35 @@ func TestCreateModelView(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateModelViewRunsOnSavedCallbacksAfterSaving(t *testing.T) {
+	// given
+	saved := []string{}
+	serializer := serializers.NewModelSerializer[MockModel]().WithNewField(
+		fields.NewField[MockModel]("upload").WithInternalValueFunc(
+			func(raw map[string]any, name string, ctx *gin.Context) (any, error) {
+				return nil, grfctx.OnSaved(ctx, func() error {
+					saved = append(saved, raw[name].(string))
+					return nil
+				})
+			},
+		),
+	)
+	failingDriver := queries.InMemory[MockModel]().WithCreate(
+		func(*gin.Context, models.InternalValue) (models.InternalValue, error) {
+			return nil, errors.New("foo")
+		},
+	)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	r.POST("/foos", CreateModelViewSetFunc(IDFromQueryParamIDFunc, queries.InMemory[MockModel](), serializer))
+	r.POST("/failing", CreateModelViewSetFunc(IDFromQueryParamIDFunc, failingDriver, serializer))
+
+	// when
+	created := quickReq(r, quickReqParams{method: "POST", path: "/foos", body: strBody(`{"upload": "created"}`)})
+	failed := quickReq(r, quickReqParams{method: "POST", path: "/failing", body: strBody(`{"upload": "failed"}`)})
+
+	// then
+	assert.Equal(t, 201, created.Code)
+	assert.Equal(t, 500, failed.Code)
+	assert.Equal(t, []string{"created"}, saved)
+}
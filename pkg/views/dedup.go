@@ -0,0 +1,113 @@
+package views
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/glothriel/grf/pkg/cache"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/throttling"
+)
+
+// ErrDuplicateSubmission is returned when an identical object was created by the same client
+// within the deduplication window
+var ErrDuplicateSubmission = errors.New("identical submission was already received")
+
+// DuplicateBehavior decides how the Deduplicator handles the duplicates
+type DuplicateBehavior int
+
+const (
+	// RejectDuplicates responds to the duplicates with 409 Conflict
+	RejectDuplicates DuplicateBehavior = iota
+	// ReturnEarlierResult responds to the duplicates with the object created by the first
+	// submission, as if it was created again. Duplicates sent while the first submission is still
+	// processed are rejected.
+	ReturnEarlierResult
+)
+
+// pendingSubmission marks the submissions, that are still processed
+type pendingSubmission struct{}
+
+// Deduplicator prevents creating duplicates, for example when users double-click a submit button
+// or UIs retry requests. Create operations with an internal value identical to one created by the
+// same client within the window are detected as duplicates.
+type Deduplicator struct {
+	cache    cache.Cache
+	window   time.Duration
+	behavior DuplicateBehavior
+	keyFunc  throttling.KeyFunc
+
+	mu sync.Mutex
+}
+
+// WithBehavior sets how the duplicates are handled, RejectDuplicates by default
+func (d *Deduplicator) WithBehavior(behavior DuplicateBehavior) *Deduplicator {
+	d.behavior = behavior
+	return d
+}
+
+// WithKeyFunc changes how the clients are identified, by default by throttling.UserOrClientIP, so
+// the users behind the same NAT or proxy don't share the submissions
+func (d *Deduplicator) WithKeyFunc(keyFunc throttling.KeyFunc) *Deduplicator {
+	d.keyFunc = keyFunc
+	return d
+}
+
+// Middleware returns the Middleware deduplicating the create operations, for example:
+//
+//	viewSet.WithMiddleware(views.NewDeduplicator(cache.NewInMemory(), 10*time.Second).Middleware())
+func (d *Deduplicator) Middleware() Middleware {
+	return func(next OperationFunc) OperationFunc {
+		return func(op *Operation) (*OperationResult, error) {
+			if op.Kind != OperationCreate {
+				return next(op)
+			}
+			key, keyErr := d.key(op)
+			if keyErr != nil {
+				return nil, keyErr
+			}
+			d.mu.Lock()
+			earlier, duplicate := d.cache.Get(key)
+			if !duplicate {
+				d.cache.Set(key, pendingSubmission{}, d.window)
+			}
+			d.mu.Unlock()
+			if duplicate {
+				earlierValue, created := earlier.(models.InternalValue)
+				if created && d.behavior == ReturnEarlierResult {
+					return &OperationResult{InternalValue: earlierValue}, nil
+				}
+				return nil, ErrDuplicateSubmission
+			}
+			result, err := next(op)
+			if err != nil {
+				d.cache.Delete(key)
+				return result, err
+			}
+			d.cache.Set(key, result.InternalValue, d.window)
+			return result, nil
+		}
+	}
+}
+
+// key hashes the client key, the model name and the internal value. JSON encoding sorts the keys
+// of maps, so the key doesn't depend on the order of the fields in the payload.
+func (d *Deduplicator) key(op *Operation) (string, error) {
+	encoded, marshalErr := json.Marshal(op.InternalValue)
+	if marshalErr != nil {
+		return "", marshalErr
+	}
+	hash := sha256.New()
+	hash.Write([]byte(d.keyFunc(op.Ctx) + "\x00" + op.ModelName + "\x00"))
+	hash.Write(encoded)
+	return "dedup:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// NewDeduplicator creates a Deduplicator keeping the submissions in the cache for the window
+func NewDeduplicator(c cache.Cache, window time.Duration) *Deduplicator {
+	return &Deduplicator{cache: c, window: window, behavior: RejectDuplicates, keyFunc: throttling.UserOrClientIP}
+}
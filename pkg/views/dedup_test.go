@@ -0,0 +1,100 @@
+package views
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/authentication"
+	"github.com/glothriel/grf/pkg/cache"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicator(t *testing.T) {
+	tests := []struct {
+		name              string
+		behavior          DuplicateBehavior
+		expectedCode      int
+		expectedDuplicate string
+	}{
+		{
+			name:              "duplicates are rejected",
+			behavior:          RejectDuplicates,
+			expectedCode:      409,
+			expectedDuplicate: `{"message": "identical submission was already received"}`,
+		},
+		{
+			name:              "duplicates receive the earlier result",
+			behavior:          ReturnEarlierResult,
+			expectedCode:      201,
+			expectedDuplicate: `{"id": 1, "name": "Canned Beans", "price": 1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel]()).WithMiddleware(
+				NewDeduplicator(cache.NewInMemory(), time.Minute).WithBehavior(tt.behavior).Middleware(),
+			)
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			viewset.Register(r)
+
+			// when
+			first := quickReq(r, quickReqParams{
+				method: "POST", path: "/mocks", body: strBody(`{"name": "Canned Beans", "price": 1}`),
+			})
+			duplicate := quickReq(r, quickReqParams{
+				method: "POST", path: "/mocks", body: strBody(`{"price": 1, "name": "Canned Beans"}`),
+			})
+			other := quickReq(r, quickReqParams{
+				method: "POST", path: "/mocks", body: strBody(`{"name": "Canned Peas", "price": 1}`),
+			})
+			list := quickReq(r, quickReqParams{method: "GET", path: "/mocks", body: noBody})
+
+			// then
+			assert.Equal(t, 201, first.Code)
+			assert.Equal(t, tt.expectedCode, duplicate.Code)
+			assert.JSONEq(t, tt.expectedDuplicate, duplicate.Body.String())
+			assert.Equal(t, 201, other.Code)
+			var listed []map[string]any
+			assert.NoError(t, json.Unmarshal(list.Body.Bytes(), &listed))
+			assert.Len(t, listed, 2)
+		})
+	}
+}
+
+// headerAuthentication authenticates the requests as the user named by the X-User header
+type headerAuthentication struct{}
+
+func (a *headerAuthentication) Authenticate(ctx *gin.Context) (bool, error) {
+	ctx.Set("user", &authentication.User{Name: ctx.GetHeader("X-User"), Email: ctx.GetHeader("X-User") + "@example.com"})
+	return true, nil
+}
+
+func TestDeduplicatorIdentifiesClientsByAuthenticatedUsers(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel]()).
+		WithAuthentication(&headerAuthentication{}).
+		WithMiddleware(NewDeduplicator(cache.NewInMemory(), time.Minute).Middleware())
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+	submit := func(user string) int {
+		w := httptest.NewRecorder()
+		request := httptest.NewRequest("POST", "/mocks", strings.NewReader(`{"name": "Canned Beans", "price": 1}`))
+		request.Header.Set("X-User", user)
+		r.ServeHTTP(w, request)
+		return w.Code
+	}
+
+	// when
+	jane, john, janeAgain := submit("jane"), submit("john"), submit("jane")
+
+	// then
+	assert.Equal(t, 201, jane)
+	assert.Equal(t, 201, john)
+	assert.Equal(t, 409, janeAgain)
+}
@@ -15,6 +15,6 @@ func DestroyModelViewSetFunc[Model any](idf IDFunc, qd queries.Driver[Model], se
 			WriteError(ctx, deleteErr)
 			return
 		}
-		ctx.JSON(http.StatusNoContent, nil)
+		render(ctx, http.StatusNoContent, nil)
 	}
 }
@@ -0,0 +1,52 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/authentication"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/storage"
+)
+
+// NewFileDownloadView creates a View streaming the objects of the storage on GET and HEAD requests.
+// The key of the object is read from the request using keyFunc, for example from the `*key` param
+// of the path. The responses support Range requests and conditional requests using the ETag of the
+// object. Like other views, the view can be protected using WithAuthentication and WithThrottle.
+func NewFileDownloadView(path string, s storage.Storage, keyFunc IDFunc) *View {
+	handler := fileDownloadHandler(s, keyFunc)
+	return (&View{
+		path:          path,
+		authenticator: &authentication.AnonymousUserAuthentication{},
+		extraRoutes:   []*ViewRoute{},
+	}).Get(handler).WithRoute(&ViewRoute{Method: http.MethodHead, Handler: handler})
+}
+
+func fileDownloadHandler(s storage.Storage, keyFunc IDFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := keyFunc(ctx)
+		object, openErr := s.Open(ctx.Request.Context(), key)
+		if errors.Is(openErr, storage.ErrNotFound) {
+			WriteError(ctx, fmt.Errorf("%w: %s", common.ErrorNotFound, key))
+			return
+		}
+		if openErr != nil {
+			WriteError(ctx, openErr)
+			return
+		}
+		defer object.Content.Close()
+		header := ctx.Writer.Header()
+		header.Set("Content-Type", object.ContentType)
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": object.Name,
+		}))
+		if object.ETag != "" {
+			header.Set("ETag", object.ETag)
+		}
+		// ServeContent handles Range, If-Range, If-None-Match and If-Modified-Since headers
+		http.ServeContent(ctx.Writer, ctx.Request, object.Name, object.ModTime, object.Content)
+	}
+}
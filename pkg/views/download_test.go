@@ -0,0 +1,62 @@
+package views
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+type rejectingAuthentication struct{}
+
+func (a *rejectingAuthentication) Authenticate(*gin.Context) (bool, error) {
+	return false, errors.New("no credentials")
+}
+
+func TestFileDownloadView(t *testing.T) {
+	// given
+	files := storage.NewInMemory()
+	files.Put("reports/2024.txt", []byte("hello world"), "")
+	fileKey := func(ctx *gin.Context) string { return ctx.Param("key") }
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	NewFileDownloadView("/files/*key", files, fileKey).Register(r)
+	NewFileDownloadView("/private/*key", files, fileKey).WithAuthentication(&rejectingAuthentication{}).Register(r)
+	download := func(method, path string, headers map[string]string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// when
+	full := download("GET", "/files/reports/2024.txt", nil)
+	partial := download("GET", "/files/reports/2024.txt", map[string]string{"Range": "bytes=6-"})
+	notModified := download("GET", "/files/reports/2024.txt", map[string]string{"If-None-Match": full.Header().Get("ETag")})
+	head := download("HEAD", "/files/reports/2024.txt", nil)
+	missing := download("GET", "/files/reports/2023.txt", nil)
+	private := download("GET", "/private/reports/2024.txt", nil)
+
+	// then
+	assert.Equal(t, 200, full.Code)
+	assert.Equal(t, "hello world", full.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", full.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=2024.txt`, full.Header().Get("Content-Disposition"))
+	assert.NotEmpty(t, full.Header().Get("ETag"))
+	assert.Equal(t, "bytes", full.Header().Get("Accept-Ranges"))
+	assert.Equal(t, 206, partial.Code)
+	assert.Equal(t, "world", partial.Body.String())
+	assert.Equal(t, "bytes 6-10/11", partial.Header().Get("Content-Range"))
+	assert.Equal(t, 304, notModified.Code)
+	assert.Equal(t, 200, head.Code)
+	assert.Equal(t, "11", head.Header().Get("Content-Length"))
+	assert.Empty(t, head.Body.String())
+	assert.Equal(t, 404, missing.Code)
+	assert.Equal(t, 401, private.Code)
+}
@@ -0,0 +1,21 @@
+package views
+
+import (
+	"net/http"
+
+	"github.com/glothriel/grf/pkg/anonymize"
+)
+
+// WithErasure adds a `POST /:id/erase` detail action anonymizing the object in place using the
+// policy, for example to handle the erasure requests of the users. The action responds with the
+// anonymized object. Like the other actions, it should be restricted using the authentication.
+func (v *ViewSet[Model]) WithErasure(policy *anonymize.Policy[Model]) *ViewSet[Model] {
+	return v.WithExtraAction(NewExtraActionFunc(http.MethodPost, "/erase", func(ctx *ActionContext[Model]) error {
+		ctx.QueryDriver.Filter().Apply(ctx.Context)
+		erased, eraseErr := policy.Erase(ctx.Context, ctx.QueryDriver.CRUD(), ctx.ID)
+		if eraseErr != nil {
+			return eraseErr
+		}
+		return ctx.Respond(http.StatusOK, erased)
+	}), nil, true)
+}
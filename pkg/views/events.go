@@ -0,0 +1,128 @@
+package views
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/authentication"
+	"github.com/glothriel/grf/pkg/notifications"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+const (
+	// EventReplayPath is the conventional path of the event replay view
+	EventReplayPath = "/_grf/events"
+	// defaultEventReplayLimit and maxEventReplayLimit bound the `limit` query parameter
+	defaultEventReplayLimit = 100
+	maxEventReplayLimit     = 1000
+)
+
+// EventAuthorizer decides if the consumer of the request may see the event, for example by
+// comparing the authenticated user with the owner of the object
+type EventAuthorizer func(ctx *gin.Context, event notifications.LoggedEvent) bool
+
+// NewEventReplayView creates a View replaying the events of the log on
+// `GET <path>?since=<cursor>&types=Order.created,Invoice&limit=100`, so the webhook consumers,
+// that missed the deliveries, can catch up:
+//
+//	{"events": [{"cursor": "42", "type": "Order.updated", "time": "...", "object": {...}, ...}],
+//	 "cursor": "42", "has_more": false}
+//
+// The consumers pass the returned cursor as `since` of the next request, until `has_more` is
+// false. Cursors of events no longer retained by the log are answered with 410 Gone, the consumer
+// has to resync the objects then. The events expose the objects of all the users, so the consumers
+// are authenticated with the authenticator, and the events the authorizer rejects are skipped. It
+// panics if either is missing, or if the authenticator lets in anonymous users. See EventReplayPath
+// for the conventional path.
+func NewEventReplayView(
+	path string, log notifications.EventLog, authenticator authentication.Authentication, authorize EventAuthorizer,
+) *View {
+	if authenticator == nil || authorize == nil {
+		panic("the event replay view requires an authenticator and an authorizer")
+	}
+	if _, anonymous := authenticator.(*authentication.AnonymousUserAuthentication); anonymous {
+		panic("the event replay view can't be used by anonymous users")
+	}
+	return (&View{
+		path:              path,
+		authenticator:     authenticator,
+		authenticationSet: true,
+		extraRoutes:       []*ViewRoute{},
+	}).Get(eventReplayHandler(log, authorize))
+}
+
+func eventReplayHandler(log notifications.EventLog, authorize EventAuthorizer) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		limit := defaultEventReplayLimit
+		if rawLimit := ctx.Query("limit"); rawLimit != "" {
+			parsed, parseErr := strconv.Atoi(rawLimit)
+			if parseErr != nil || parsed < 1 || parsed > maxEventReplayLimit {
+				WriteError(ctx, &serializers.ValidationError{FieldErrors: map[string][]string{
+					"limit": {"expected an integer between 1 and " + strconv.Itoa(maxEventReplayLimit)},
+				}})
+				return
+			}
+			limit = parsed
+		}
+		types := []string{}
+		for _, rawTypes := range ctx.QueryArray("types") {
+			for _, eventType := range strings.Split(rawTypes, ",") {
+				if eventType = strings.TrimSpace(eventType); eventType != "" {
+					types = append(types, eventType)
+				}
+			}
+		}
+		cursor := ctx.Query(ChangesSinceQueryParam)
+		events, sinceErr := log.Since(ctx.Request.Context(), cursor, types, limit)
+		if errors.Is(sinceErr, notifications.ErrInvalidCursor) {
+			WriteError(ctx, &serializers.ValidationError{FieldErrors: map[string][]string{
+				ChangesSinceQueryParam: {"invalid cursor"},
+			}})
+			return
+		}
+		if errors.Is(sinceErr, notifications.ErrCursorExpired) {
+			writeErrorResponse(ctx, 410, gin.H{"message": sinceErr.Error()})
+			return
+		}
+		if sinceErr != nil {
+			WriteError(ctx, sinceErr)
+			return
+		}
+		replayed := []gin.H{}
+		for _, event := range events {
+			// the cursor advances past the rejected events, so they are not scanned again
+			cursor = event.Cursor
+			if !authorize(ctx, event) {
+				continue
+			}
+			replayed = append(replayed, eventRepresentation(event))
+		}
+		render(ctx, 200, gin.H{
+			"events":   replayed,
+			"cursor":   cursor,
+			"has_more": len(events) == limit,
+		})
+	}
+}
+
+// eventRepresentation follows the default payload of notifications.WebhookSender, so the consumers
+// can handle the replayed events like the delivered ones
+func eventRepresentation(event notifications.LoggedEvent) gin.H {
+	representation := gin.H{
+		"cursor": event.Cursor,
+		"type":   event.Event.Type(),
+		"time":   event.Time,
+		"event":  event.Event.Kind,
+		"model":  event.Event.Model,
+		"object": event.Event.Object,
+	}
+	if event.Event.Previous != nil {
+		representation["previous"] = event.Event.Previous
+	}
+	if event.Event.Changes != nil {
+		representation["changes"] = event.Event.Changes
+	}
+	return representation
+}
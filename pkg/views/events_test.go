@@ -0,0 +1,79 @@
+package views
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/authentication"
+	"github.com/glothriel/grf/pkg/notifications"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventReplayView(t *testing.T) {
+	// given
+	log := notifications.NewMemoryEventLog(2)
+	for _, event := range []notifications.Event{
+		{Kind: notifications.Created, Model: "Order", Object: map[string]any{"id": 1, "owner": "jane"}},
+		{Kind: notifications.Created, Model: "Order", Object: map[string]any{"id": 2, "owner": "john"}},
+		{Kind: notifications.Created, Model: "Order", Object: map[string]any{"id": 3, "owner": "jane"}},
+	} {
+		assert.NoError(t, log.Append(context.Background(), event))
+	}
+	ownedByJane := func(_ *gin.Context, event notifications.LoggedEvent) bool {
+		return event.Event.Object["owner"] == "jane"
+	}
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	NewEventReplayView(EventReplayPath, log, &consumerAuthentication{}, ownedByJane).Register(r)
+	replay := func(query string) (*httptest.ResponseRecorder, map[string]any) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", EventReplayPath+query, nil)
+		req.Header.Set("Authorization", "consumer")
+		r.ServeHTTP(w, req)
+		var body map[string]any
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return w, body
+	}
+
+	// when
+	firstResponse, firstPage := replay("?limit=1&types=Order")
+	secondResponse, secondPage := replay("?limit=1&types=Order&since=" + firstPage["cursor"].(string))
+	expired, _ := replay("?since=0")
+	invalid, _ := replay("?limit=0")
+	unauthenticated := httptest.NewRecorder()
+	r.ServeHTTP(unauthenticated, httptest.NewRequest("GET", EventReplayPath, nil))
+
+	// then
+	assert.Equal(t, 200, firstResponse.Code)
+	assert.Equal(t, []any{}, firstPage["events"])
+	assert.Equal(t, "2", firstPage["cursor"])
+	assert.Equal(t, true, firstPage["has_more"])
+	assert.Equal(t, 200, secondResponse.Code)
+	assert.Len(t, secondPage["events"], 1)
+	event := secondPage["events"].([]any)[0].(map[string]any)
+	assert.Equal(t, "Order.created", event["type"])
+	assert.Equal(t, map[string]any{"id": float64(3), "owner": "jane"}, event["object"])
+	assert.Equal(t, "3", secondPage["cursor"])
+	assert.Equal(t, true, secondPage["has_more"])
+	assert.Equal(t, 410, expired.Code)
+	assert.Equal(t, 400, invalid.Code)
+	assert.Equal(t, 401, unauthenticated.Code)
+	assert.Panics(t, func() { NewEventReplayView(EventReplayPath, log, &consumerAuthentication{}, nil) })
+	assert.Panics(t, func() {
+		NewEventReplayView(EventReplayPath, log, &authentication.AnonymousUserAuthentication{}, ownedByJane)
+	})
+}
+
+// consumerAuthentication authenticates the requests with the consumer's Authorization header
+type consumerAuthentication struct{}
+
+func (a *consumerAuthentication) Authenticate(ctx *gin.Context) (bool, error) {
+	if ctx.GetHeader("Authorization") != "consumer" {
+		return false, nil
+	}
+	ctx.Set("user", &authentication.User{Name: "consumer"})
+	return true, nil
+}
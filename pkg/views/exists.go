@@ -0,0 +1,68 @@
+package views
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// ExistsResponse is the response of GET requests to the `/exists` route
+type ExistsResponse struct {
+	Exists bool `json:"exists"`
+}
+
+// WithExists adds an `/exists` collection route, checking if an object with the fields equal to the
+// query parameters exists, for example `/users/exists?username=john` for username availability
+// checks. Only the passed fields may be used, at least one of them is required. GET requests are
+// responded with `{"exists": true}`, HEAD requests with 204 if the object exists and 404 otherwise.
+// The filters of the list action apply. The existence is checked using queries.Exister, which the
+// GORM driver executes as an EXISTS query. Drivers not implementing it list all the objects instead.
+func (v *ViewSet[Model]) WithExists(fields ...string) *ViewSet[Model] {
+	check := func(ctx *ActionContext[Model]) (bool, error) {
+		conditions := map[string]any{}
+		for _, field := range fields {
+			if value, ok := ctx.GetQuery(field); ok {
+				conditions[field] = value
+			}
+		}
+		if len(conditions) == 0 {
+			return false, &serializers.ValidationError{FieldErrors: map[string][]string{
+				"all": {"one of the query parameters is required: " + strings.Join(fields, ", ")},
+			}}
+		}
+		ctx.QueryDriver.Filter().Apply(ctx.Context)
+		exists, existsErr := queries.Exists(ctx.Context, ctx.QueryDriver, conditions)
+		if errors.Is(existsErr, queries.ErrExistsUnsupported) {
+			internalValues, listErr := ctx.QueryDriver.CRUD().List(ctx.Context)
+			exists = slices.ContainsFunc(internalValues, func(internalValue models.InternalValue) bool {
+				return common.Matches(internalValue, conditions)
+			})
+			existsErr = listErr
+		}
+		return exists, existsErr
+	}
+	return v.WithExtraAction(NewExtraActionFunc(http.MethodGet, "/exists", func(ctx *ActionContext[Model]) error {
+		exists, err := check(ctx)
+		if err != nil {
+			return err
+		}
+		render(ctx.Context, http.StatusOK, ExistsResponse{Exists: exists})
+		return nil
+	}), nil, false).WithExtraAction(NewExtraActionFunc(http.MethodHead, "/exists", func(ctx *ActionContext[Model]) error {
+		exists, err := check(ctx)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return common.ErrorNotFound
+		}
+		ctx.Status(http.StatusNoContent)
+		return nil
+	}), nil, false)
+}
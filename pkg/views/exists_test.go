@@ -0,0 +1,55 @@
+package views
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExists(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{name: "existing", method: "GET", path: "/mocks/exists?name=Beans", expectedCode: 200, expectedBody: `{"exists": true}`},
+		{name: "multiple fields", method: "GET", path: "/mocks/exists?name=Beans&price=2", expectedCode: 200, expectedBody: `{"exists": false}`},
+		{name: "missing", method: "GET", path: "/mocks/exists?name=Peas", expectedCode: 200, expectedBody: `{"exists": false}`},
+		{
+			name: "not allowed field", method: "GET", path: "/mocks/exists?id=1", expectedCode: 400,
+			expectedBody: `{"errors": {"all": ["one of the query parameters is required: name, price"]}}`,
+		},
+		{name: "existing head", method: "HEAD", path: "/mocks/exists?name=Beans", expectedCode: 204},
+		{name: "missing head", method: "HEAD", path: "/mocks/exists?name=Peas", expectedCode: 404},
+	}
+	drivers := map[string]queries.Driver[anotherMockModel]{
+		"existence checking driver": queries.InMemory(anotherMockModel{Name: "Beans", Price: 1}),
+		"driver without existence checks": plainDriver[anotherMockModel]{
+			queries.InMemory(anotherMockModel{Name: "Beans", Price: 1}),
+		},
+	}
+	for driverName, driver := range drivers {
+		for _, tt := range tests {
+			t.Run(driverName+" "+tt.name, func(t *testing.T) {
+				// given
+				viewset := NewModelViewSet[anotherMockModel]("/mocks", driver).WithExists("name", "price")
+				_, r := gin.CreateTestContext(httptest.NewRecorder())
+				viewset.Register(r)
+
+				// when
+				response := quickReq(r, quickReqParams{method: tt.method, path: tt.path, body: noBody})
+
+				// then
+				assert.Equal(t, tt.expectedCode, response.Code)
+				if tt.expectedBody != "" {
+					assert.JSONEq(t, tt.expectedBody, response.Body.String())
+				}
+			})
+		}
+	}
+}
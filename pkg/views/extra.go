@@ -1,5 +1,13 @@
 package views
 
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
 type ExtraAction[Model any] struct {
 	Method       string
 	RelativePath string
@@ -13,3 +21,94 @@ func NewExtraAction[Model any](method string, relativePath string, handler ViewS
 		Handler:      handler,
 	}
 }
+
+// ActionFunc handles a custom action, see NewExtraActionFunc
+type ActionFunc[Model any] func(ctx *ActionContext[Model]) error
+
+// ActionContext gives the custom actions access to the viewset's query driver and serializer
+type ActionContext[Model any] struct {
+	*gin.Context
+	// ID is the value of the lookup path param for detail actions, empty otherwise
+	ID          string
+	QueryDriver queries.Driver[Model]
+	Serializer  serializers.Serializer
+}
+
+// Object retrieves the object of a detail action
+func (c *ActionContext[Model]) Object() (models.InternalValue, error) {
+	c.QueryDriver.Filter().Apply(c.Context)
+	return c.QueryDriver.CRUD().Retrieve(c.Context, c.ID)
+}
+
+// Parse reads the body of the request, using the parser of the view, and converts it to an internal
+// value using the serializer
+func (c *ActionContext[Model]) Parse() (models.InternalValue, error) {
+	var raw map[string]any
+	if bindErr := parseBody(c.Context, &raw); bindErr != nil {
+		return nil, bindErr
+	}
+	return c.Serializer.ToInternalValue(raw, c.Context)
+}
+
+// Respond writes the internal value converted to its representation using the serializer, encoded by
+// the renderer of the view
+func (c *ActionContext[Model]) Respond(status int, intVal models.InternalValue) error {
+	representation, err := c.Serializer.ToRepresentation(intVal, c.Context)
+	if err != nil {
+		return err
+	}
+	render(c.Context, status, representation)
+	return nil
+}
+
+type extraAction[Model any] struct {
+	action     *ExtraAction[Model]
+	serializer serializers.Serializer
+	isDetail   bool
+}
+
+// NewExtraActionFunc creates an ExtraAction handled by the function, like DRF's @action, with the
+// access to the viewset's query driver and serializer. The returned errors are written using
+// WriteError, like the errors of the CRUD actions.
+func NewExtraActionFunc[Model any](method string, relativePath string, handler ActionFunc[Model]) *ExtraAction[Model] {
+	return NewExtraAction(method, relativePath, func(
+		idf IDFunc, qd queries.Driver[Model], serializer serializers.Serializer,
+	) gin.HandlerFunc {
+		return func(ctx *gin.Context) {
+			if err := handler(&ActionContext[Model]{
+				Context: ctx, ID: idf(ctx), QueryDriver: qd, Serializer: serializer,
+			}); err != nil {
+				WriteError(ctx, err)
+			}
+		}
+	})
+}
+
+func (v *ViewSet[Model]) extraActionRoutes(queryDriver queries.Driver[Model]) (listRoutes, detailRoutes []*ViewRoute) {
+	for _, extra := range v.extraActions {
+		serializer := extra.serializer
+		if serializer == nil {
+			serializer = v.actionSerializer(!extra.isDetail)
+		}
+		handler := extra.action.Handler(v.IDFunc, queryDriver, v.withHooks(serializer))
+		if !extra.isDetail {
+			handler = v.withListQuery(handler)
+		}
+		route := &ViewRoute{
+			Method:       extra.action.Method,
+			RelativePath: extra.action.RelativePath,
+			Handler: v.withMetadata(
+				grfctx.Metadata{
+					Action: ActionCustom, CustomAction: extra.action.RelativePath, View: v.viewSettings(extra.isDetail),
+				},
+				handler,
+			),
+		}
+		if extra.isDetail {
+			detailRoutes = append(detailRoutes, route)
+		} else {
+			listRoutes = append(listRoutes, route)
+		}
+	}
+	return listRoutes, detailRoutes
+}
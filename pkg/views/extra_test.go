@@ -0,0 +1,75 @@
+package views
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/anonymize"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewSetWithExtraActionFunc(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+	)).WithExtraAction(NewExtraActionFunc("POST", "/discount", func(ctx *ActionContext[anotherMockModel]) error {
+		object, err := ctx.Object()
+		if err != nil {
+			return err
+		}
+		object["price"] = object["price"].(float64) / 2
+		updated, err := ctx.QueryDriver.CRUD().Update(ctx.Context, object, object, ctx.ID)
+		if err != nil {
+			return err
+		}
+		return ctx.Respond(http.StatusOK, updated)
+	}), nil, true).WithExtraAction(NewExtraActionFunc("POST", "/preview", func(ctx *ActionContext[anotherMockModel]) error {
+		intVal, err := ctx.Parse()
+		if err != nil {
+			return err
+		}
+		return ctx.Respond(http.StatusOK, intVal)
+	}), nil, false)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	discounted := quickReq(r, quickReqParams{method: "POST", path: "/mocks/1/discount", body: noBody})
+	missing := quickReq(r, quickReqParams{method: "POST", path: "/mocks/2/discount", body: noBody})
+	preview := quickReq(r, quickReqParams{
+		method: "POST", path: "/mocks/preview", body: strBody(`{"name": "Canned Peas", "price": 2}`),
+	})
+	wrongMethod := quickReq(r, quickReqParams{method: "GET", path: "/mocks/1/discount", body: noBody})
+
+	// then
+	assert.Equal(t, 200, discounted.Code)
+	assert.JSONEq(t, `{"id": 1, "name": "Canned Beans", "price": 0.5}`, discounted.Body.String())
+	assert.Equal(t, 404, missing.Code)
+	assert.Equal(t, 200, preview.Code)
+	assert.JSONEq(t, `{"name": "Canned Peas", "price": 2}`, preview.Body.String())
+	assert.Equal(t, 405, wrongMethod.Code)
+	assert.Equal(t, "POST", wrongMethod.Header().Get("Allow"))
+}
+
+func TestViewSetWithErasure(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+	)).WithErasure(anonymize.NewPolicy[anotherMockModel]().WithField("name", anonymize.Constant("Erased")))
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	erased := quickReq(r, quickReqParams{method: "POST", path: "/mocks/1/erase", body: noBody})
+	missing := quickReq(r, quickReqParams{method: "POST", path: "/mocks/2/erase", body: noBody})
+	retrieved := quickReq(r, caseRetrieve.params)
+
+	// then
+	assert.Equal(t, 200, erased.Code)
+	assert.JSONEq(t, `{"id": 1, "name": "Erased", "price": 1}`, erased.Body.String())
+	assert.Equal(t, 404, missing.Code)
+	assert.JSONEq(t, `{"id": 1, "name": "Erased", "price": 1}`, retrieved.Body.String())
+}
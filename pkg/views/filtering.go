@@ -0,0 +1,144 @@
+package views
+
+import (
+	"errors"
+	"maps"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/filters"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// WithFilterField makes the list action filterable by the field using the query parameters, for
+// example `?name=John` or `?age__gte=18`. Only the exact operator is allowed if no operators are
+// passed. The predicates are passed to the query driver, see grfctx.Predicates, and also apply to
+// the collection custom actions, like `/count`.
+func (v *ViewSet[Model]) WithFilterField(field string, operators ...filters.Operator) *ViewSet[Model] {
+	if v.filterFields == nil {
+		v.filterFields = filters.Fields{}
+	}
+	v.filterFields[field] = operators
+	return v
+}
+
+// WithFilterSet filters the list action using the query parameters of the filter set, like the
+// fields declared using WithFilterField, see filters.FilterSet
+func (v *ViewSet[Model]) WithFilterSet(filterSet *filters.FilterSet[Model]) *ViewSet[Model] {
+	v.filterSets = append(v.filterSets, filterSet)
+	return v
+}
+
+// WithOrderingFields allows ordering the list action by the fields using the `ordering` query
+// parameter, for example `?ordering=name,-created_at`. Other fields are ignored. The ordering is
+// passed to the query driver, see grfctx.Ordering.
+func (v *ViewSet[Model]) WithOrderingFields(fields ...string) *ViewSet[Model] {
+	v.orderingFields = append(v.orderingFields, fields...)
+	return v
+}
+
+// WithDefaultOrdering sets the ordering used when the request doesn't specify a valid one, in the
+// format of the `ordering` query parameter, for example `-created_at,id`
+func (v *ViewSet[Model]) WithDefaultOrdering(ordering string) *ViewSet[Model] {
+	v.defaultOrdering = []grfctx.OrderBy{}
+	for _, term := range strings.Split(ordering, ",") {
+		field, descending := strings.CutPrefix(strings.TrimSpace(term), "-")
+		if field != "" {
+			v.defaultOrdering = append(v.defaultOrdering, grfctx.OrderBy{Field: field, Descending: descending})
+		}
+	}
+	return v
+}
+
+// WithSearchFields allows searching the list action using the `search` query parameter, for example
+// `?search=john doe` lists the objects containing both "john" and "doe" in any of the fields,
+// ignoring the case. The search is passed to the query driver, see grfctx.CurrentSearch.
+func (v *ViewSet[Model]) WithSearchFields(fields ...string) *ViewSet[Model] {
+	v.searchFields = append(v.searchFields, fields...)
+	return v
+}
+
+// WithQuerySerializer validates the query parameters of the list action and the collection custom
+// actions using the serializer, invalid parameters are rejected with 400 responses listing the
+// errors of the fields. The parsed values are available using QueryParams, and they replace the raw
+// ones in the filtering, the search, the ordering and the pagination, see grfctx.QueryValues.
+func (v *ViewSet[Model]) WithQuerySerializer(serializer *serializers.QuerySerializer) *ViewSet[Model] {
+	v.querySerializer = serializer
+	return v
+}
+
+// QueryParams returns the query parameters parsed by the serializer set using WithQuerySerializer
+func QueryParams(ctx *gin.Context) (models.InternalValue, bool) {
+	params, ok := grfctx.QueryParams(ctx)
+	return params, ok
+}
+
+// withListQuery parses the predicates, the search and the ordering from the query parameters before
+// calling the handler
+func (v *ViewSet[Model]) withListQuery(handler gin.HandlerFunc) gin.HandlerFunc {
+	if len(v.filterFields) == 0 && len(v.filterSets) == 0 && len(v.searchFields) == 0 &&
+		len(v.orderingFields) == 0 && len(v.defaultOrdering) == 0 && v.querySerializer == nil {
+		return handler
+	}
+	return func(ctx *gin.Context) {
+		if v.querySerializer != nil {
+			params, parseErr := v.querySerializer.Parse(ctx)
+			if parseErr != nil {
+				WriteError(ctx, parseErr)
+				return
+			}
+			grfctx.SetQueryParams(ctx, params)
+		}
+		predicates, parseErr := v.parsePredicates(ctx)
+		if parseErr != nil {
+			WriteError(ctx, parseErr)
+			return
+		}
+		grfctx.AddPredicates(ctx, predicates...)
+		if search, ok := filters.ParseSearch(grfctx.Query(ctx, filters.SearchQueryParam), v.searchFields); ok {
+			grfctx.SetSearch(ctx, search)
+		}
+		ordering := filters.ParseOrdering(grfctx.Query(ctx, filters.OrderingQueryParam), v.orderingFields)
+		if len(ordering) == 0 {
+			ordering = v.defaultOrdering
+		}
+		if len(ordering) > 0 {
+			grfctx.SetOrdering(ctx, ordering)
+		}
+		handler(ctx)
+	}
+}
+
+// parsePredicates parses the predicates of the filter fields and sets, merging their validation
+// errors
+func (v *ViewSet[Model]) parsePredicates(ctx *gin.Context) ([]grfctx.Predicate, error) {
+	parsers := []func(*gin.Context) ([]grfctx.Predicate, error){}
+	if len(v.filterFields) > 0 {
+		parsers = append(parsers, func(ctx *gin.Context) ([]grfctx.Predicate, error) {
+			return filters.Parse[Model](ctx, v.filterFields)
+		})
+	}
+	for _, filterSet := range v.filterSets {
+		parsers = append(parsers, filterSet.Parse)
+	}
+	predicates := []grfctx.Predicate{}
+	validationErr := &serializers.ValidationError{FieldErrors: map[string][]string{}}
+	for _, parse := range parsers {
+		parsed, parseErr := parse(ctx)
+		var fieldsErr *serializers.ValidationError
+		if errors.As(parseErr, &fieldsErr) {
+			maps.Copy(validationErr.FieldErrors, fieldsErr.FieldErrors)
+			continue
+		}
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		predicates = append(predicates, parsed...)
+	}
+	if len(validationErr.FieldErrors) > 0 {
+		return nil, validationErr
+	}
+	return predicates, nil
+}
@@ -0,0 +1,306 @@
+package views
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/filters"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		expectedCode  int
+		expectedNames []string
+		expectedBody  string
+	}{
+		{name: "no filters", path: "/mocks", expectedCode: 200, expectedNames: []string{"Beans", "Peas", "Canned Beans"}},
+		{name: "exact", path: "/mocks?name=Peas", expectedCode: 200, expectedNames: []string{"Peas"}},
+		{name: "operators", path: "/mocks?name__icontains=beans&price__gte=2", expectedCode: 200, expectedNames: []string{"Canned Beans"}},
+		{name: "in", path: "/mocks?price__in=1,2", expectedCode: 200, expectedNames: []string{"Beans", "Peas"}},
+		{name: "count", path: "/mocks/count?price__gte=2", expectedCode: 200, expectedBody: `{"count": 2}`},
+		{
+			name: "invalid", path: "/mocks?price__gte=cheap", expectedCode: 400,
+			expectedBody: `{"errors": {"price__gte": ["expected a number"]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory(
+				anotherMockModel{Name: "Beans", Price: 1},
+				anotherMockModel{Name: "Peas", Price: 2},
+				anotherMockModel{Name: "Canned Beans", Price: 3},
+			)).WithFilterField("name", filters.Exact, filters.IContains).WithFilterField(
+				"price", filters.Gte, filters.In,
+			).WithCount()
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			viewset.Register(r)
+
+			// when
+			response := quickReq(r, quickReqParams{method: "GET", path: tt.path, body: noBody})
+
+			// then
+			assert.Equal(t, tt.expectedCode, response.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, response.Body.String())
+				return
+			}
+			var listed []map[string]any
+			assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &listed))
+			names := []string{}
+			for _, item := range listed {
+				names = append(names, item["name"].(string))
+			}
+			assert.ElementsMatch(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestOrderingFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		expectedNames []string
+	}{
+		{name: "default", path: "/mocks", expectedNames: []string{"Beans", "Peas", "Canned Beans"}},
+		{name: "ascending", path: "/mocks?ordering=price", expectedNames: []string{"Canned Beans", "Beans", "Peas"}},
+		{name: "descending", path: "/mocks?ordering=-name", expectedNames: []string{"Peas", "Canned Beans", "Beans"}},
+		{name: "multiple", path: "/mocks?ordering=price,-name", expectedNames: []string{"Canned Beans", "Peas", "Beans"}},
+		{name: "not allowed", path: "/mocks?ordering=id", expectedNames: []string{"Beans", "Peas", "Canned Beans"}},
+		{
+			name: "filtered", path: "/mocks?ordering=-price&name__icontains=beans",
+			expectedNames: []string{"Beans", "Canned Beans"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory(
+				anotherMockModel{Name: "Beans", Price: 2},
+				anotherMockModel{Name: "Peas", Price: 2},
+				anotherMockModel{Name: "Canned Beans", Price: 1},
+			)).WithFilterField("name", filters.IContains).WithOrderingFields("name", "price").WithDefaultOrdering("-price,name")
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			viewset.Register(r)
+
+			// when
+			response := quickReq(r, quickReqParams{method: "GET", path: tt.path, body: noBody})
+
+			// then
+			assert.Equal(t, 200, response.Code)
+			var listed []map[string]any
+			assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &listed))
+			names := []string{}
+			for _, item := range listed {
+				names = append(names, item["name"].(string))
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestSearchFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		expectedNames []string
+	}{
+		{name: "no search", path: "/mocks", expectedNames: []string{"Green Peas", "Canned Beans", "Beans"}},
+		{name: "case insensitive", path: "/mocks?search=BEAN", expectedNames: []string{"Canned Beans", "Beans"}},
+		{name: "all terms", path: "/mocks?search=beans%20can", expectedNames: []string{"Canned Beans"}},
+		{name: "any field", path: "/mocks?search=2", expectedNames: []string{"Green Peas", "Beans"}},
+		{name: "with filters", path: "/mocks?search=2&name=Beans", expectedNames: []string{"Beans"}},
+		{name: "no match", path: "/mocks?search=carrots", expectedNames: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory(
+				anotherMockModel{Name: "Green Peas", Price: 12},
+				anotherMockModel{Name: "Canned Beans", Price: 3},
+				anotherMockModel{Name: "Beans", Price: 2},
+			)).WithSearchFields("name", "price").WithFilterField("name", filters.Exact).WithDefaultOrdering("-name")
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			viewset.Register(r)
+
+			// when
+			response := quickReq(r, quickReqParams{method: "GET", path: tt.path, body: noBody})
+
+			// then
+			assert.Equal(t, 200, response.Code)
+			var listed []map[string]any
+			assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &listed))
+			names := []string{}
+			for _, item := range listed {
+				names = append(names, item["name"].(string))
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestFilterSet(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		expectedCode  int
+		expectedNames []string
+		expectedBody  string
+	}{
+		{name: "filter set", path: "/mocks?max_price=2", expectedCode: 200, expectedNames: []string{"Beans", "Peas"}},
+		{name: "custom filter", path: "/mocks?canned=true", expectedCode: 200, expectedNames: []string{"Canned Beans"}},
+		{name: "with filter fields", path: "/mocks?max_price=2&name=Peas", expectedCode: 200, expectedNames: []string{"Peas"}},
+		{
+			name: "merged errors", path: "/mocks?max_price=cheap&price__gte=cheap", expectedCode: 400,
+			expectedBody: `{"errors": {"max_price": ["expected a number"], "price__gte": ["expected a number"]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			filterSet := filters.NewFilterSet[anotherMockModel]().WithFilter("max_price", "price", filters.Lte).WithCustomFilter(
+				"canned", func(_ *gin.Context, raw string) ([]grfctx.Predicate, error) {
+					return []grfctx.Predicate{{Field: "name", Operator: filters.StartsWith, Value: "Canned"}}, nil
+				},
+			)
+			viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory(
+				anotherMockModel{Name: "Beans", Price: 1},
+				anotherMockModel{Name: "Peas", Price: 2},
+				anotherMockModel{Name: "Canned Beans", Price: 3},
+			)).WithFilterSet(filterSet).WithFilterField("name").WithFilterField("price", filters.Gte)
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			viewset.Register(r)
+
+			// when
+			response := quickReq(r, quickReqParams{method: "GET", path: tt.path, body: noBody})
+
+			// then
+			assert.Equal(t, tt.expectedCode, response.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, response.Body.String())
+				return
+			}
+			var listed []map[string]any
+			assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &listed))
+			names := []string{}
+			for _, item := range listed {
+				names = append(names, item["name"].(string))
+			}
+			assert.ElementsMatch(t, tt.expectedNames, names)
+		})
+	}
+}
+
+type reportQuery struct {
+	MinPrice int  `json:"min_price"`
+	Verbose  bool `json:"verbose"`
+}
+
+func TestQuerySerializer(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		expectedCode   int
+		expectedBody   string
+		expectedParams models.InternalValue
+	}{
+		{
+			name: "valid", path: "/mocks?min_price=2&verbose=true&page=1", expectedCode: 200,
+			expectedParams: models.InternalValue{"min_price": 2, "verbose": true},
+		},
+		{
+			name: "missing optional", path: "/mocks?min_price=2", expectedCode: 200,
+			expectedParams: models.InternalValue{"min_price": 2},
+		},
+		{
+			name: "invalid", path: "/mocks?min_price=cheap&verbose=maybe", expectedCode: 400,
+			expectedBody: `{"errors": {
+				"min_price": ["Value ` + "`cheap`" + ` is not a valid number"],
+				"verbose": ["Value ` + "`maybe`" + ` is not a valid boolean"]
+			}}`,
+		},
+		{
+			name: "missing required", path: "/mocks?verbose=1", expectedCode: 400,
+			expectedBody: `{"errors": {"min_price": ["This field is required"]}}`,
+		},
+		{
+			name: "fractional integer", path: "/mocks?min_price=1.5", expectedCode: 400,
+			expectedBody: `{"errors": {"min_price": ["Value ` + "`1.5`" + ` is not a valid integer"]}}`,
+		},
+		{
+			name: "infinite integer", path: "/mocks?min_price=Inf", expectedCode: 400,
+			expectedBody: `{"errors": {"min_price": ["Value ` + "`Inf`" + ` is not a valid number"]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var params models.InternalValue
+			viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel]()).WithQuerySerializer(
+				serializers.NewQuerySerializer(
+					serializers.NewModelSerializer[reportQuery]().WithField("min_price", fields.Required()),
+				),
+			).WithMiddleware(func(next OperationFunc) OperationFunc {
+				return func(op *Operation) (*OperationResult, error) {
+					params, _ = QueryParams(op.Ctx)
+					return next(op)
+				}
+			})
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			viewset.Register(r)
+
+			// when
+			response := quickReq(r, quickReqParams{method: "GET", path: tt.path, body: noBody})
+
+			// then
+			assert.Equal(t, tt.expectedCode, response.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, response.Body.String())
+			}
+			assert.Equal(t, tt.expectedParams, params)
+		})
+	}
+}
+
+type nameQuery struct {
+	Name     string `json:"name"`
+	Ordering string `json:"ordering"`
+}
+
+func TestQuerySerializerValuesAreUsedByTheFilteringAndOrdering(t *testing.T) {
+	// given
+	lowercase := func(raw map[string]any, name string, _ *gin.Context) (any, error) {
+		value, _ := raw[name].(string)
+		return strings.ToLower(value), nil
+	}
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory(
+		anotherMockModel{Name: "beans", Price: 1}, anotherMockModel{Name: "BEANS", Price: 2},
+		anotherMockModel{Name: "beans", Price: 3},
+	)).WithFilterField("name").WithOrderingFields("price").WithQuerySerializer(
+		serializers.NewQuerySerializer(
+			serializers.NewModelSerializer[nameQuery]().
+				WithField("name", func(oldField fields.Field) { oldField.WithInternalValueFunc(lowercase) }).
+				WithField("ordering", func(oldField fields.Field) { oldField.WithInternalValueFunc(lowercase) }),
+		),
+	)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	response := quickReq(r, quickReqParams{method: "GET", path: "/mocks?name=Beans&ordering=-PRICE", body: noBody})
+
+	// then
+	assert.Equal(t, 200, response.Code)
+	assert.JSONEq(t, `[{"id": 3, "name": "beans", "price": 3}, {"id": 1, "name": "beans", "price": 1}]`, response.Body.String())
+}
@@ -0,0 +1,107 @@
+package views
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// FormField describes a writable field, so that UIs can render an input for it
+type FormField struct {
+	Name        string   `json:"name"`
+	Label       string   `json:"label"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required"`
+	Choices     []any    `json:"choices,omitempty"`
+	Validators  []string `json:"validators,omitempty"`
+	Default     any      `json:"default,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Example     any      `json:"example,omitempty"`
+}
+
+// FormMetadata is the response of the `_form` route of the viewsets, describing the writable fields
+// of the create serializer, or the update serializer if the create action is disabled
+type FormMetadata struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Fields      []FormField `json:"fields"`
+}
+
+func (v *ViewSet[Model]) formHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		render(ctx, http.StatusOK, v.form())
+	}
+}
+
+func (v *ViewSet[Model]) form() FormMetadata {
+	var m Model
+	modelType := reflect.TypeOf(m)
+	form := FormMetadata{Name: modelType.Name(), Description: v.description, Fields: []FormField{}}
+	action := v.CreateAction
+	if action == nil {
+		action = v.UpdateAction
+	}
+	if action == nil {
+		return form
+	}
+	describer, ok := action.Serializer.(serializers.Describer)
+	if !ok {
+		return form
+	}
+	described := describer.Describe()
+	// The fields are ordered like the model fields, the additional ones are appended by name
+	order := []string{}
+	for _, field := range reflect.VisibleFields(modelType) {
+		if name, included := models.FieldName(field); included && !field.Anonymous {
+			order = append(order, name)
+		}
+	}
+	names := make([]string, 0, len(described))
+	for name := range described {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		aIndex, bIndex := slices.Index(order, a), slices.Index(order, b)
+		switch {
+		case aIndex >= 0 && bIndex >= 0:
+			return aIndex - bIndex
+		case aIndex >= 0:
+			return -1
+		case bIndex >= 0:
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	for _, name := range names {
+		field := described[name]
+		if field.ReadOnly {
+			continue
+		}
+		form.Fields = append(form.Fields, FormField{
+			Name:        name,
+			Label:       label(name),
+			Type:        field.Type,
+			Required:    field.Required,
+			Choices:     field.Choices,
+			Validators:  field.Validators,
+			Default:     field.Default,
+			Description: field.Description,
+			Example:     field.Example,
+		})
+	}
+	return form
+}
+
+// label converts the field name to a human readable label, for example first_name to First name
+func label(name string) string {
+	words := strings.TrimSpace(strings.ReplaceAll(name, "_", " "))
+	if words == "" {
+		return name
+	}
+	return strings.ToUpper(words[:1]) + words[1:]
+}
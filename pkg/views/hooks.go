@@ -0,0 +1,33 @@
+package views
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// RepresentationHook post-processes a single representation, after it was produced by a serializer
+type RepresentationHook func(ctx *gin.Context, repr serializers.Representation) (serializers.Representation, error)
+
+type representationHookSerializer struct {
+	child serializers.Serializer
+	hooks []RepresentationHook
+}
+
+func (s *representationHookSerializer) ToInternalValue(raw map[string]any, ctx *gin.Context) (models.InternalValue, error) {
+	return s.child.ToInternalValue(raw, ctx)
+}
+
+func (s *representationHookSerializer) ToRepresentation(intVal models.InternalValue, ctx *gin.Context) (serializers.Representation, error) {
+	repr, err := s.child.ToRepresentation(intVal, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range s.hooks {
+		repr, err = hook(ctx, repr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return repr, nil
+}
@@ -6,24 +6,105 @@ import (
 	"io"
 
 	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/ids"
 	"github.com/glothriel/grf/pkg/queries/common"
 	"github.com/glothriel/grf/pkg/serializers"
 	"github.com/sirupsen/logrus"
 )
 
+// ErrNotAuthenticated is returned when the request could not be authenticated
+var ErrNotAuthenticated = errors.New("authentication required")
+
+// ErrPermissionDenied is returned when the authenticated request lacks the permissions of the view
+var ErrPermissionDenied = errors.New("permission denied")
+
+// ErrThrottled is returned when the request was rejected by one of the throttles
+var ErrThrottled = errors.New("request was throttled")
+
+// ErrMethodNotAllowed is returned when the view doesn't handle the method of the request
+var ErrMethodNotAllowed = errors.New("method not allowed")
+
 // WriteError checks for common error types and maps them to correct HTTP status codes
 func WriteError(ctx *gin.Context, err error) {
 	// Serializers validation
 	ve, isValidationErr := err.(*serializers.ValidationError)
 	if isValidationErr {
-		ctx.JSON(400, gin.H{
+		writeErrorResponse(ctx, 400, gin.H{
 			"errors": ve.FieldErrors,
 		})
 		return
 	}
+	// The images are stored after the objects are saved, see grfctx.OnSaved
+	var rejectedImageErr *fields.RejectedImageError
+	if errors.As(err, &rejectedImageErr) {
+		writeErrorResponse(ctx, 400, gin.H{
+			"errors": map[string][]string{rejectedImageErr.Field: {rejectedImageErr.Error()}},
+		})
+		return
+	}
+	var bulkErr *BulkValidationError
+	if errors.As(err, &bulkErr) {
+		response := gin.H{"errors": bulkErr.Errors}
+		if len(bulkErr.ListErrors) > 0 {
+			response["list_errors"] = bulkErr.ListErrors
+		}
+		writeErrorResponse(ctx, 400, response)
+		return
+	}
+	// Query drivers reject the IDs, that the generator of the model couldn't have generated
+	if errors.Is(err, ids.ErrInvalidID) {
+		writeErrorResponse(ctx, 400, gin.H{
+			"errors": map[string][]string{"id": {err.Error()}},
+		})
+		return
+	}
 	// QueryDriver returns common.ErrorNotFound when no entity is found
 	if errors.Is(err, common.ErrorNotFound) {
-		ctx.JSON(404, gin.H{
+		writeErrorResponse(ctx, 404, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, ErrNotAuthenticated) {
+		writeErrorResponse(ctx, 401, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, ErrPermissionDenied) {
+		writeErrorResponse(ctx, 403, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+	var conflictErr *ConflictError
+	if errors.As(err, &conflictErr) {
+		writeErrorResponse(ctx, 409, gin.H{
+			"message": err.Error(),
+			"current": conflictErr.Current,
+		})
+		return
+	}
+	var databaseErr *common.DatabaseError
+	if errors.As(err, &databaseErr) {
+		writeDatabaseError(ctx, databaseErr)
+		return
+	}
+	if errors.Is(err, ErrDuplicateSubmission) {
+		writeErrorResponse(ctx, 409, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, ErrMethodNotAllowed) || errors.Is(err, common.ErrOperationNotAllowed) {
+		writeErrorResponse(ctx, 405, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, ErrThrottled) {
+		writeErrorResponse(ctx, 429, gin.H{
 			"message": err.Error(),
 		})
 		return
@@ -31,7 +112,7 @@ func WriteError(ctx *gin.Context, err error) {
 	// Empty JSON body or JSON syntax error
 	_, isSyntaxErr := err.(*json.SyntaxError)
 	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) || isSyntaxErr {
-		ctx.JSON(400, gin.H{
+		writeErrorResponse(ctx, 400, gin.H{
 			"errors": map[string][]string{
 				"all": {"could not parse request body"},
 			},
@@ -39,7 +120,35 @@ func WriteError(ctx *gin.Context, err error) {
 		return
 	}
 	logrus.Errorf("Unexpected error of type %T: %s", err, err.Error())
-	ctx.JSON(500, gin.H{
+	writeErrorResponse(ctx, 500, gin.H{
 		"message": "internal server error",
 	})
 }
+
+// writeDatabaseError responds with 409 to the unique violations and serialization failures, as
+// the request conflicts with the state of the database, 400 to the foreign key violations and 422
+// to the check violations. The details of the database are not exposed, only the fields.
+func writeDatabaseError(ctx *gin.Context, err *common.DatabaseError) {
+	status, message := 400, "the referenced object does not exist or the object is still referenced"
+	switch {
+	case errors.Is(err, common.ErrUniqueViolation):
+		status, message = 409, "this value is already used"
+	case errors.Is(err, common.ErrCheckViolation):
+		status, message = 422, "this value is not allowed"
+	case errors.Is(err, common.ErrSerializationFailure):
+		writeErrorResponse(ctx, 409, gin.H{
+			"message": "the request conflicts with a concurrent update, please retry it",
+		})
+		return
+	}
+	fieldErrors := map[string][]string{}
+	for _, field := range err.Fields {
+		fieldErrors[field] = []string{message}
+	}
+	if len(fieldErrors) == 0 {
+		fieldErrors["all"] = []string{message}
+	}
+	writeErrorResponse(ctx, status, gin.H{
+		"errors": fieldErrors,
+	})
+}
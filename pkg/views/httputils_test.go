@@ -9,8 +9,11 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/queries"
 	"github.com/glothriel/grf/pkg/queries/common"
 	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/glothriel/grf/pkg/storage"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -47,6 +50,31 @@ func TestWriteError(t *testing.T) {
 			err:      io.EOF,
 			expected: http.StatusBadRequest,
 		},
+		{
+			name:     "unique violation",
+			err:      &common.DatabaseError{Kind: common.ErrUniqueViolation, Fields: []string{"sku"}, Err: errors.New("duplicate")},
+			expected: http.StatusConflict,
+		},
+		{
+			name:     "foreign key violation",
+			err:      &common.DatabaseError{Kind: common.ErrForeignKeyViolation, Err: errors.New("fk")},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "check violation",
+			err:      &common.DatabaseError{Kind: common.ErrCheckViolation, Err: errors.New("check")},
+			expected: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "serialization failure",
+			err:      &common.DatabaseError{Kind: common.ErrSerializationFailure, Err: errors.New("deadlock")},
+			expected: http.StatusConflict,
+		},
+		{
+			name:     "rejected image",
+			err:      &fields.RejectedImageError{Field: "avatar", Err: &storage.RejectedUploadError{Threat: "Eicar"}},
+			expected: http.StatusBadRequest,
+		},
 		{
 			name:     "generic error",
 			err:      errors.New("Some generic unknown error"),
@@ -70,3 +98,59 @@ func TestWriteError(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteErrorProblemDetails(t *testing.T) {
+	// given
+	type product struct {
+		ID   uint   `json:"id"`
+		Name string `json:"name"`
+	}
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	NewModelViewSet[product]("/products", queries.InMemory[product]()).
+		WithoutActions(ActionDestroy).
+		WithSerializer(serializers.NewModelSerializer[product]().WithField("name", fields.Required())).
+		WithErrorFormat(ErrorFormatProblem).
+		Register(r)
+
+	// when
+	invalid := quickReq(r, quickReqParams{method: "POST", path: "/products", body: strBody(`{}`)})
+	notFound := quickReq(r, quickReqParams{method: "GET", path: "/products/1", body: noBody})
+	notAllowed := quickReq(r, quickReqParams{method: "DELETE", path: "/products/1", body: noBody})
+
+	// then
+	assert.Equal(t, http.StatusBadRequest, invalid.Code)
+	assert.Equal(t, "application/problem+json", invalid.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank", "title": "Bad Request", "status": 400, "instance": "/products",
+		"detail": "the request is invalid", "errors": {"name": ["This field is required"]}
+	}`, invalid.Body.String())
+	assert.Equal(t, http.StatusNotFound, notFound.Code)
+	assert.JSONEq(t, `{
+		"type": "about:blank", "title": "Not Found", "status": 404, "instance": "/products/1", "detail": "not found"
+	}`, notFound.Body.String())
+	assert.Equal(t, http.StatusMethodNotAllowed, notAllowed.Code)
+	assert.Equal(t, "application/problem+json", notAllowed.Header().Get("Content-Type"))
+}
+
+func TestHandleUnmatchedRoutes(t *testing.T) {
+	// given
+	defaultFormat := DefaultErrorFormat
+	DefaultErrorFormat = ErrorFormatProblem
+	defer func() { DefaultErrorFormat = defaultFormat }()
+	r := gin.New()
+	HandleUnmatchedRoutes(r)
+	r.GET("/health", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	// when
+	notFound := quickReq(r, quickReqParams{method: "GET", path: "/missing", body: noBody})
+	notAllowed := quickReq(r, quickReqParams{method: "POST", path: "/health", body: noBody})
+
+	// then
+	assert.Equal(t, http.StatusNotFound, notFound.Code)
+	assert.Equal(t, "application/problem+json", notFound.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank", "title": "Not Found", "status": 404, "instance": "/missing", "detail": "not found"
+	}`, notFound.Body.String())
+	assert.Equal(t, http.StatusMethodNotAllowed, notAllowed.Code)
+	assert.Equal(t, "application/problem+json", notAllowed.Header().Get("Content-Type"))
+}
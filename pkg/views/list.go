@@ -35,6 +35,6 @@ func ListModelViewSetFunc[Model any](idf IDFunc, qd queries.Driver[Model], seria
 			WriteError(ctx, formatErr)
 			return
 		}
-		ctx.JSON(http.StatusOK, retVal)
+		render(ctx, http.StatusOK, retVal)
 	}
 }
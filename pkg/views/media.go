@@ -0,0 +1,226 @@
+package views
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Parser decodes the bodies of the requests sent in its media type, see View.WithParsers
+type Parser interface {
+	// MediaType is the media type of the parsed bodies, like `application/json`
+	MediaType() string
+	// Parse decodes the body into v, which is a pointer to a map or a slice
+	Parse(body io.Reader, v any) error
+}
+
+// Renderer encodes the responses in its media type, see View.WithRenderers
+type Renderer interface {
+	// MediaType is the media type of the rendered responses, like `application/json`
+	MediaType() string
+	// ContentType is the Content-Type header of the rendered responses, like
+	// `application/json; charset=utf-8`
+	ContentType() string
+	// Render encodes v, which is a representation of the objects, or the error, to w
+	Render(w io.Writer, v any) error
+}
+
+// JSONParser is the default Parser, decoding the JSON bodies
+type JSONParser struct{}
+
+func (JSONParser) MediaType() string {
+	return "application/json"
+}
+
+func (JSONParser) Parse(body io.Reader, v any) error {
+	return json.NewDecoder(body).Decode(v)
+}
+
+// JSONRenderer is the default Renderer, encoding the responses as JSON
+type JSONRenderer struct{}
+
+func (JSONRenderer) MediaType() string {
+	return "application/json"
+}
+
+func (JSONRenderer) ContentType() string {
+	return "application/json; charset=utf-8"
+}
+
+func (JSONRenderer) Render(w io.Writer, v any) error {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(encoded)
+	return err
+}
+
+const (
+	parsersCtxKey   = "grf.parsers"
+	renderersCtxKey = "grf.renderers"
+)
+
+// WithParsers sets the parsers of the request bodies of the view, replacing JSONParser. The parser
+// matching the Content-Type of the request is used, the first one if none of them matches.
+func (v *View) WithParsers(parsers ...Parser) *View {
+	v.parsers = parsers
+	return v
+}
+
+// WithRenderers sets the renderers of the responses of the view, replacing JSONRenderer. The
+// renderer preferred by the Accept header of the request is used, the first one if the request
+// doesn't accept any of them. The problem details errors are always rendered as JSON.
+func (v *View) WithRenderers(renderers ...Renderer) *View {
+	v.renderers = renderers
+	return v
+}
+
+// WithParsers sets the parsers of the request bodies of all the viewset's routes, see
+// View.WithParsers
+func (v *ViewSet[Model]) WithParsers(parsers ...Parser) *ViewSet[Model] {
+	v.ListCreateView.WithParsers(parsers...)
+	v.RetrieveUpdateDestroyView.WithParsers(parsers...)
+	return v
+}
+
+// WithRenderers sets the renderers of the responses of all the viewset's routes, see
+// View.WithRenderers
+func (v *ViewSet[Model]) WithRenderers(renderers ...Renderer) *ViewSet[Model] {
+	v.ListCreateView.WithRenderers(renderers...)
+	v.RetrieveUpdateDestroyView.WithRenderers(renderers...)
+	return v
+}
+
+// mediaTypesMiddleware stores the parsers and the renderers of the view, it runs before the
+// authentication, so its errors are rendered by them as well
+func mediaTypesMiddleware(parsers []Parser, renderers []Renderer) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if len(parsers) > 0 {
+			ctx.Set(parsersCtxKey, parsers)
+		}
+		if len(renderers) > 0 {
+			ctx.Set(renderersCtxKey, renderers)
+		}
+		ctx.Next()
+	}
+}
+
+var errMissingBody = errors.New("invalid request")
+
+// parseBody decodes the body of the request into v using the parser of the view matching its
+// Content-Type
+func parseBody(ctx *gin.Context, v any) error {
+	if ctx.Request == nil || ctx.Request.Body == nil {
+		return errMissingBody
+	}
+	return parserFor(ctx).Parse(ctx.Request.Body, v)
+}
+
+func parserFor(ctx *gin.Context) Parser {
+	stored, ok := ctx.Get(parsersCtxKey)
+	if !ok {
+		return JSONParser{}
+	}
+	parsers := stored.([]Parser)
+	mediaType, _, _ := mime.ParseMediaType(ctx.GetHeader("Content-Type"))
+	for _, parser := range parsers {
+		if parser.MediaType() == mediaType {
+			return parser
+		}
+	}
+	return parsers[0]
+}
+
+// render writes the response with the status, encoding v using the renderer of the view preferred
+// by the Accept header of the request
+func render(ctx *gin.Context, status int, v any) {
+	renderer := rendererFor(ctx)
+	ctx.Status(status)
+	if ctx.Writer.Header().Get("Content-Type") == "" {
+		ctx.Header("Content-Type", renderer.ContentType())
+	}
+	if !bodyAllowedForStatus(status) {
+		ctx.Writer.WriteHeaderNow()
+		return
+	}
+	if err := renderer.Render(ctx.Writer, v); err != nil {
+		_ = ctx.Error(err)
+		ctx.Abort()
+	}
+}
+
+func rendererFor(ctx *gin.Context) Renderer {
+	stored, ok := ctx.Get(renderersCtxKey)
+	if !ok {
+		return JSONRenderer{}
+	}
+	renderers := stored.([]Renderer)
+	for _, accepted := range acceptedMediaTypes(ctx.GetHeader("Accept")) {
+		for _, renderer := range renderers {
+			if mediaTypeMatches(accepted, renderer.MediaType()) {
+				return renderer
+			}
+		}
+	}
+	return renderers[0]
+}
+
+// acceptedMediaTypes returns the media ranges of the Accept header, the preferred ones first
+func acceptedMediaTypes(header string) []string {
+	type mediaRange struct {
+		mediaType string
+		quality   float64
+	}
+	ranges := []mediaRange{}
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if parsed, parseErr := strconv.ParseFloat(q, 64); parseErr == nil {
+				quality = parsed
+			}
+		}
+		if quality > 0 {
+			ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+	mediaTypes := make([]string, len(ranges))
+	for i, r := range ranges {
+		mediaTypes[i] = r.mediaType
+	}
+	return mediaTypes
+}
+
+// mediaTypeMatches returns whether the media range, like `application/*`, includes the media type
+func mediaTypeMatches(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	prefix, isWildcard := strings.CutSuffix(mediaRange, "/*")
+	return isWildcard && strings.HasPrefix(mediaType, prefix+"/")
+}
+
+// bodyAllowedForStatus follows gin, the responses with the 1xx, 204 and 304 statuses have no body
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
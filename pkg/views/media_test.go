@@ -0,0 +1,128 @@
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+// formParser decodes the url encoded forms into maps
+type formParser struct{}
+
+func (formParser) MediaType() string {
+	return "application/x-www-form-urlencoded"
+}
+
+func (formParser) Parse(body io.Reader, v any) error {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	values, err := url.ParseQuery(string(raw))
+	if err != nil {
+		return err
+	}
+	parsed := map[string]any{}
+	for key := range values {
+		parsed[key] = values.Get(key)
+	}
+	*(v.(*map[string]any)) = parsed
+	return nil
+}
+
+// textRenderer renders the objects as sorted `key: value` lines
+type textRenderer struct{}
+
+func (textRenderer) MediaType() string {
+	return "text/plain"
+}
+
+func (textRenderer) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+func (textRenderer) Render(w io.Writer, v any) error {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return err
+	}
+	lines := []string{}
+	for key, value := range fields {
+		lines = append(lines, fmt.Sprintf("%s: %v", key, value))
+	}
+	slices.Sort(lines)
+	_, err = io.WriteString(w, strings.Join(lines, "\n"))
+	return err
+}
+
+type namedModel struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func mediaReq(r *gin.Engine, method, path, contentType, accept, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", accept)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestViewSetWithParsersAndRenderers(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[namedModel]("/named", queries.InMemory[namedModel]()).
+		WithParsers(JSONParser{}, formParser{}).
+		WithRenderers(JSONRenderer{}, textRenderer{})
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	created := mediaReq(r, "POST", "/named", "application/x-www-form-urlencoded", "text/plain", "name=foo")
+	createdFromJSON := mediaReq(r, "POST", "/named", "application/json", "", `{"name": "bar"}`)
+	asJSON := mediaReq(r, "GET", "/named/1", "", "", "")
+	asText := mediaReq(r, "GET", "/named/1", "", "application/json;q=0.5, text/*", "")
+	notFound := mediaReq(r, "GET", "/named/3", "", "text/plain", "")
+
+	// then
+	assert.Equal(t, 201, created.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", created.Header().Get("Content-Type"))
+	assert.Equal(t, "id: 1\nname: foo", created.Body.String())
+	assert.Equal(t, 201, createdFromJSON.Code)
+	assert.JSONEq(t, `{"id": 2, "name": "bar"}`, createdFromJSON.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", asJSON.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"id": 1, "name": "foo"}`, asJSON.Body.String())
+	assert.Equal(t, "id: 1\nname: foo", asText.Body.String())
+	assert.Equal(t, 404, notFound.Code)
+	assert.Equal(t, "message: not found", notFound.Body.String())
+}
+
+func TestAcceptedMediaTypes(t *testing.T) {
+	assert.Equal(t, []string{}, acceptedMediaTypes(""))
+	assert.Equal(t,
+		[]string{"text/html", "application/json", "*/*"},
+		acceptedMediaTypes("application/json;q=0.9, text/html, */*;q=0.1, image/png;q=0"),
+	)
+}
+
+func TestMediaTypeMatches(t *testing.T) {
+	assert.True(t, mediaTypeMatches("*/*", "application/json"))
+	assert.True(t, mediaTypeMatches("application/*", "application/json"))
+	assert.True(t, mediaTypeMatches("application/json", "application/json"))
+	assert.False(t, mediaTypeMatches("text/*", "application/json"))
+	assert.False(t, mediaTypeMatches("application/xml", "application/json"))
+}
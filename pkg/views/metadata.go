@@ -0,0 +1,186 @@
+package views
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/fake"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/pagination"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// ViewMetadata is the response to OPTIONS requests, describing the view and the fields accepted by
+// its write actions, similarly to DRF's metadata
+type ViewMetadata struct {
+	Name           string                                           `json:"name"`
+	Description    string                                           `json:"description,omitempty"`
+	Example        any                                              `json:"example,omitempty"`
+	AllowedMethods []string                                         `json:"allowed_methods"`
+	Actions        map[string]map[string]*serializers.FieldMetadata `json:"actions,omitempty"`
+	// Samples are the example payloads of the actions, keyed by their methods
+	Samples map[string]*Sample `json:"samples,omitempty"`
+}
+
+// Sample is an example request body and response of an action, so the clients can learn the shape
+// of the payloads without external docs. The values are the examples of the fields, their defaults
+// or fake values.
+type Sample struct {
+	Request  map[string]any `json:"request,omitempty"`
+	Response any            `json:"response,omitempty"`
+}
+
+// WithDescription sets the description of the viewset, shown in the OPTIONS and form metadata
+func (v *ViewSet[Model]) WithDescription(description string) *ViewSet[Model] {
+	v.description = description
+	return v
+}
+
+// WithExample sets an example representation of the viewset's objects, shown in the OPTIONS and
+// form metadata
+func (v *ViewSet[Model]) WithExample(example any) *ViewSet[Model] {
+	v.example = example
+	return v
+}
+
+func (v *ViewSet[Model]) metadataHandler(isDetail bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		render(ctx, http.StatusOK, v.metadata(ctx, isDetail))
+	}
+}
+
+func (v *ViewSet[Model]) metadata(ctx *gin.Context, isDetail bool) ViewMetadata {
+	var m Model
+	methods := []struct {
+		method string
+		action *ViewSetAction[Model]
+		write  bool
+	}{
+		{http.MethodGet, v.ListAction, false},
+		{http.MethodPost, v.CreateAction, true},
+	}
+	if isDetail {
+		methods = []struct {
+			method string
+			action *ViewSetAction[Model]
+			write  bool
+		}{
+			{http.MethodGet, v.RetrieveAction, false},
+			{http.MethodPut, v.UpdateAction, true},
+			{http.MethodPatch, v.PartialUpdateAction, false},
+			{http.MethodDelete, v.DestroyAction, false},
+		}
+	}
+	metadata := ViewMetadata{
+		Name:           reflect.TypeOf(m).Name(),
+		Description:    v.description,
+		Example:        v.example,
+		AllowedMethods: []string{},
+		Actions:        map[string]map[string]*serializers.FieldMetadata{},
+		Samples:        map[string]*Sample{},
+	}
+	for _, method := range methods {
+		if method.action == nil {
+			continue
+		}
+		metadata.AllowedMethods = append(metadata.AllowedMethods, method.method)
+		describer, ok := method.action.Serializer.(serializers.Describer)
+		if !ok {
+			continue
+		}
+		if method.write {
+			metadata.Actions[method.method] = describer.Describe()
+			metadata.Samples[method.method] = &Sample{
+				Request:  v.sample(method.action.Serializer, true),
+				Response: v.responseSample(method.action.Serializer),
+			}
+		} else if method.method == http.MethodGet && isDetail {
+			metadata.Samples[method.method] = &Sample{Response: v.responseSample(method.action.Serializer)}
+		} else if method.method == http.MethodGet {
+			metadata.Samples[method.method] = &Sample{Response: v.listSample(ctx, method.action.Serializer)}
+		}
+	}
+	metadata.AllowedMethods = append(metadata.AllowedMethods, http.MethodOptions)
+	return metadata
+}
+
+// sampleTime is the value of the datetime fields in the samples, so the samples don't change
+// between the requests
+var sampleTime = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// responseSample returns the example of the viewset set using WithExample, or the sample of the
+// readable fields
+func (v *ViewSet[Model]) responseSample(serializer serializers.Serializer) any {
+	if v.example != nil {
+		return v.example
+	}
+	return v.sample(serializer, false)
+}
+
+// listSample returns the response sample of the list, a page of a single object in the envelope of
+// the paginator, see listPaginator, or of the query driver paginating the lists by itself
+func (v *ViewSet[Model]) listSample(ctx *gin.Context, serializer serializers.Serializer) any {
+	results := []any{v.responseSample(serializer)}
+	paginator := v.listPaginator()
+	if paginator == nil {
+		if v.QueryDriver == nil {
+			return results
+		}
+		formatted, formatErr := v.QueryDriver.Pagination().Format(ctx, results)
+		if formatErr != nil {
+			return results
+		}
+		return formatted
+	}
+	window, windowErr := paginator.Window(ctx)
+	if windowErr != nil {
+		return results
+	}
+	formatted, formatErr := paginator.Format(ctx, pagination.Page{
+		Results:        results,
+		InternalValues: []models.InternalValue{v.sample(serializer, false)},
+		Count:          len(results),
+		Window:         window,
+	})
+	if formatErr != nil {
+		return results
+	}
+	return formatted
+}
+
+// sample returns the example payload of the writable or the readable fields of the serializer, the
+// values are the examples of the fields, their defaults or fake values. The fake values are seeded,
+// so the request and the response samples of the action match.
+func (v *ViewSet[Model]) sample(serializer serializers.Serializer, write bool) map[string]any {
+	generator := fake.NewGenerator[Model](serializer).WithSeed(1)
+	described := serializer.(serializers.Describer).Describe()
+	names := make([]string, 0, len(described))
+	for name := range described {
+		names = append(names, name)
+	}
+	// The fields are generated in a stable order, so the seeded values don't change
+	sort.Strings(names)
+	sample := map[string]any{}
+	for _, name := range names {
+		field := described[name]
+		var value any
+		var ok bool
+		switch {
+		case field.Example != nil:
+			value, ok = field.Example, true
+		case field.Default != nil:
+			value, ok = field.Default, true
+		case field.Type == "datetime":
+			value, ok = sampleTime.Format(time.RFC3339), true
+		default:
+			value, ok = generator.Value(name)
+		}
+		if ok && !(write && field.ReadOnly) && !(!write && field.WriteOnly) {
+			sample[name] = value
+		}
+	}
+	return sample
+}
@@ -0,0 +1,204 @@
+package views
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/pagination"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+type describedModel struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Age       int       `json:"age"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func TestViewSetOptionsMetadata(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[describedModel]("/people", queries.InMemory[describedModel]()).WithSerializer(
+		serializers.NewValidatingSerializer[describedModel](
+			serializers.NewModelSerializer[describedModel](),
+			serializers.NewGoPlaygroundValidator[describedModel](map[string]any{"name": "required,max=50"}),
+		),
+	).WithoutActions(ActionDestroy)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	list := quickReq(r, quickReqParams{method: "OPTIONS", path: "/people", body: noBody})
+	detail := quickReq(r, quickReqParams{method: "OPTIONS", path: "/people/1", body: noBody})
+
+	// then
+	fields := `{
+		"id": {"type": "integer", "required": false, "read_only": true, "write_only": false},
+		"name": {"type": "string", "required": true, "read_only": false, "write_only": false, "validators": ["max=50"]},
+		"age": {"type": "integer", "required": false, "read_only": false, "write_only": false},
+		"created_at": {"type": "datetime", "required": false, "read_only": false, "write_only": false}
+	}`
+	assert.Equal(t, 200, list.Code)
+	var listMetadata ViewMetadata
+	assert.NoError(t, json.Unmarshal(list.Body.Bytes(), &listMetadata))
+	assert.Equal(t, "describedModel", listMetadata.Name)
+	assert.Equal(t, []string{"GET", "POST", "OPTIONS"}, listMetadata.AllowedMethods)
+	assertActionsJSONEq(t, `{"POST": `+fields+`}`, listMetadata.Actions)
+	assert.ElementsMatch(t, []string{"GET", "POST"}, mapKeys(listMetadata.Samples))
+	assertSampleShape(t, listMetadata.Samples["POST"].Request, false)
+	assertSampleShape(t, listMetadata.Samples["POST"].Response, true)
+	assert.Equal(t, listMetadata.Samples["POST"].Request["name"], listMetadata.Samples["POST"].Response.(map[string]any)["name"])
+	listSample, isArray := listMetadata.Samples["GET"].Response.([]any)
+	assert.True(t, isArray)
+	assert.Len(t, listSample, 1)
+	assertSampleShape(t, listSample[0], true)
+	assert.Nil(t, listMetadata.Samples["GET"].Request)
+
+	assert.Equal(t, 200, detail.Code)
+	var detailMetadata ViewMetadata
+	assert.NoError(t, json.Unmarshal(detail.Body.Bytes(), &detailMetadata))
+	assert.Equal(t, "describedModel", detailMetadata.Name)
+	assert.Equal(t, []string{"GET", "PUT", "PATCH", "OPTIONS"}, detailMetadata.AllowedMethods)
+	assertActionsJSONEq(t, `{"PUT": `+fields+`}`, detailMetadata.Actions)
+	assert.ElementsMatch(t, []string{"GET", "PUT"}, mapKeys(detailMetadata.Samples))
+	assertSampleShape(t, detailMetadata.Samples["GET"].Response, true)
+	assertSampleShape(t, detailMetadata.Samples["PUT"].Request, false)
+	assertSampleShape(t, detailMetadata.Samples["PUT"].Response, true)
+	assert.Equal(t, listMetadata.Samples["POST"], detailMetadata.Samples["PUT"])
+}
+
+func TestViewSetOptionsMetadataOfPaginatedList(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[describedModel]("/people", queries.InMemory[describedModel]()).
+		WithPagination(pagination.NewPageNumberPagination(10))
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	list := quickReq(r, quickReqParams{method: "OPTIONS", path: "/people", body: noBody})
+
+	// then
+	assert.Equal(t, 200, list.Code)
+	var metadata ViewMetadata
+	assert.NoError(t, json.Unmarshal(list.Body.Bytes(), &metadata))
+	envelope, isObject := metadata.Samples["GET"].Response.(map[string]any)
+	assert.True(t, isObject)
+	assert.ElementsMatch(t, []string{"count", "next", "previous", "results"}, mapKeys(envelope))
+	assert.Equal(t, float64(1), envelope["count"])
+	assert.Nil(t, envelope["next"])
+	assert.Nil(t, envelope["previous"])
+	results, isArray := envelope["results"].([]any)
+	assert.True(t, isArray)
+	assert.Len(t, results, 1)
+	assertSampleShape(t, results[0], true)
+}
+
+// assertActionsJSONEq compares the fields of the actions with the expected JSON
+func assertActionsJSONEq(t *testing.T, expected string, actions map[string]map[string]*serializers.FieldMetadata) {
+	encoded, encodeErr := json.Marshal(actions)
+	assert.NoError(t, encodeErr)
+	assert.JSONEq(t, expected, string(encoded))
+}
+
+// assertSampleShape checks the fields of a describedModel sample and the types of their values,
+// the id is only present in the responses
+func assertSampleShape(t *testing.T, raw any, response bool) {
+	sample, isObject := raw.(map[string]any)
+	if !assert.True(t, isObject, "the sample is not an object: %v", raw) {
+		return
+	}
+	expectedFields := []string{"name", "age", "created_at"}
+	if response {
+		expectedFields = append(expectedFields, "id")
+		assert.IsType(t, float64(0), sample["id"])
+	}
+	assert.ElementsMatch(t, expectedFields, mapKeys(sample))
+	assert.IsType(t, "", sample["name"])
+	assert.NotEmpty(t, sample["name"])
+	assert.IsType(t, float64(0), sample["age"])
+	assert.Equal(t, "2024-01-01T12:00:00Z", sample["created_at"])
+}
+
+type formModel struct {
+	ID        uint   `json:"id"`
+	Title     string `json:"title"`
+	Status    string `json:"status" gorm:"default:'draft'"`
+	Priority  int    `json:"priority" gorm:"not null;default:3"`
+	CreatedAt string `json:"created_at" gorm:"default:now()"`
+}
+
+func TestViewSetFormMetadata(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[formModel]("/things", queries.InMemory[formModel]()).WithSerializer(
+		serializers.NewValidatingSerializer[formModel](
+			serializers.NewModelSerializer[formModel]().WithNewField(fields.NewField[formModel]("notes")),
+			serializers.NewGoPlaygroundValidator[formModel](map[string]any{
+				"title": "required,max=100", "status": "oneof=draft published", "priority": "oneof=1 2 3",
+			}),
+		),
+	)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	form := quickReq(r, quickReqParams{method: "GET", path: "/things/_form", body: noBody})
+	detail := quickReq(r, quickReqParams{method: "GET", path: "/things/1", body: noBody})
+
+	// then
+	assert.Equal(t, 200, form.Code)
+	assert.JSONEq(t, `{
+		"name": "formModel",
+		"fields": [
+			{"name": "title", "label": "Title", "type": "string", "required": true, "validators": ["max=100"]},
+			{
+				"name": "status", "label": "Status", "type": "string", "required": false,
+				"choices": ["draft", "published"], "validators": ["oneof=draft published"], "default": "draft"
+			},
+			{
+				"name": "priority", "label": "Priority", "type": "integer", "required": false,
+				"choices": [1, 2, 3], "validators": ["oneof=1 2 3"], "default": 3
+			},
+			{"name": "created_at", "label": "Created at", "type": "string", "required": false},
+			{"name": "notes", "label": "Notes", "type": "field", "required": false}
+		]
+	}`, form.Body.String())
+	assert.Equal(t, 404, detail.Code)
+}
+
+func TestViewSetDescriptionsInMetadata(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[describedModel]("/people", queries.InMemory[describedModel]()).WithSerializer(
+		serializers.NewModelSerializer[describedModel]().WithField("name", fields.Described("Full name of the person", "Jane Doe")),
+	).WithDescription("People registered in the system").WithExample(map[string]any{"id": 1, "name": "Jane Doe"})
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	options := quickReq(r, quickReqParams{method: "OPTIONS", path: "/people", body: noBody})
+	form := quickReq(r, quickReqParams{method: "GET", path: "/people/_form", body: noBody})
+
+	// then
+	var metadata ViewMetadata
+	assert.NoError(t, json.Unmarshal(options.Body.Bytes(), &metadata))
+	assert.Equal(t, "People registered in the system", metadata.Description)
+	assert.Equal(t, map[string]any{"id": float64(1), "name": "Jane Doe"}, metadata.Example)
+	assert.Equal(t, "Full name of the person", metadata.Actions["POST"]["name"].Description)
+	assert.Equal(t, "Jane Doe", metadata.Actions["POST"]["name"].Example)
+	var formMetadata FormMetadata
+	assert.NoError(t, json.Unmarshal(form.Body.Bytes(), &formMetadata))
+	assert.Equal(t, "People registered in the system", formMetadata.Description)
+	assert.Equal(t, "Full name of the person", formMetadata.Fields[0].Description)
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
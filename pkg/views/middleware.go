@@ -0,0 +1,189 @@
+package views
+
+import (
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/queries/crud"
+)
+
+// OperationKind is the query driver function called by the view
+type OperationKind int
+
+const (
+	OperationList OperationKind = iota
+	OperationRetrieve
+	OperationCreate
+	OperationUpdate
+	OperationDestroy
+)
+
+// Operation describes a single call to the query driver, made while handling a view action. Please
+// note, that a single action may result in multiple operations, for example update action retrieves
+// the old value before updating it.
+type Operation struct {
+	Ctx       *gin.Context
+	Action    ActionID
+	Kind      OperationKind
+	ModelName string
+
+	// ID is set for retrieve, update and destroy operations, it's the value of the lookup field,
+	// see grfctx.LookupField
+	ID any
+	// InternalValue is the parsed incoming value for create and update operations
+	InternalValue models.InternalValue
+	// OldInternalValue is the stored value for update operations
+	OldInternalValue models.InternalValue
+}
+
+// OperationResult holds the result of an operation: InternalValues for list operations and
+// InternalValue for retrieve, create and update operations
+type OperationResult struct {
+	InternalValue  models.InternalValue
+	InternalValues []models.InternalValue
+}
+
+// OperationFunc executes an operation
+type OperationFunc func(op *Operation) (*OperationResult, error)
+
+// Middleware wraps the operations of the views, with access to the parsed internal values and
+// action metadata, unlike gin middleware, which only sees raw HTTP requests. It may modify the
+// operation before calling next, modify the result, or return an error to abort the action.
+type Middleware func(next OperationFunc) OperationFunc
+
+var globalMiddleware []Middleware
+
+// UseMiddleware registers middleware applied to all the viewsets registered afterwards
+func UseMiddleware(middleware ...Middleware) {
+	globalMiddleware = append(globalMiddleware, middleware...)
+}
+
+// middlewareDriver runs the operations of the child driver through the middleware chain
+type middlewareDriver[Model any] struct {
+	child     queries.Driver[Model]
+	pipeline  OperationFunc
+	modelName string
+}
+
+func (d *middlewareDriver[Model]) CRUD() *crud.CRUD[Model] {
+	return &crud.CRUD[Model]{
+		List: func(ctx *gin.Context) ([]models.InternalValue, error) {
+			result, err := d.run(&Operation{Ctx: ctx, Kind: OperationList})
+			if err != nil {
+				return nil, err
+			}
+			return result.InternalValues, nil
+		},
+		Retrieve: func(ctx *gin.Context, id any) (models.InternalValue, error) {
+			result, err := d.run(&Operation{Ctx: ctx, Kind: OperationRetrieve, ID: id})
+			if err != nil {
+				return nil, err
+			}
+			return result.InternalValue, nil
+		},
+		Create: func(ctx *gin.Context, new models.InternalValue) (models.InternalValue, error) {
+			result, err := d.run(&Operation{Ctx: ctx, Kind: OperationCreate, InternalValue: new})
+			if err != nil {
+				return nil, err
+			}
+			return result.InternalValue, nil
+		},
+		Update: func(ctx *gin.Context, old models.InternalValue, new models.InternalValue, id any) (
+			models.InternalValue, error,
+		) {
+			result, err := d.run(&Operation{
+				Ctx: ctx, Kind: OperationUpdate, ID: id, InternalValue: new, OldInternalValue: old,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return result.InternalValue, nil
+		},
+		Destroy: func(ctx *gin.Context, id any) error {
+			_, err := d.run(&Operation{Ctx: ctx, Kind: OperationDestroy, ID: id})
+			return err
+		},
+	}
+}
+
+func (d *middlewareDriver[Model]) run(op *Operation) (*OperationResult, error) {
+	op.Action = grfctx.CurrentAction(op.Ctx)
+	op.ModelName = d.modelName
+	return d.pipeline(op)
+}
+
+// execute calls the child driver, it's the innermost OperationFunc of the chain
+func (d *middlewareDriver[Model]) execute(op *Operation) (*OperationResult, error) {
+	queries := d.child.CRUD()
+	switch op.Kind {
+	case OperationList:
+		internalValues, err := queries.List(op.Ctx)
+		return &OperationResult{InternalValues: internalValues}, err
+	case OperationRetrieve:
+		internalValue, err := queries.Retrieve(op.Ctx, op.ID)
+		return &OperationResult{InternalValue: internalValue}, err
+	case OperationCreate:
+		internalValue, err := queries.Create(op.Ctx, op.InternalValue)
+		return &OperationResult{InternalValue: internalValue}, err
+	case OperationUpdate:
+		internalValue, err := queries.Update(op.Ctx, op.OldInternalValue, op.InternalValue, op.ID)
+		return &OperationResult{InternalValue: internalValue}, err
+	default:
+		return &OperationResult{}, queries.Destroy(op.Ctx, op.ID)
+	}
+}
+
+func (d *middlewareDriver[Model]) Pagination() common.Pagination {
+	return d.child.Pagination()
+}
+
+func (d *middlewareDriver[Model]) Filter() common.QueryMod {
+	return d.child.Filter()
+}
+
+func (d *middlewareDriver[Model]) Order() common.QueryMod {
+	return d.child.Order()
+}
+
+func (d *middlewareDriver[Model]) Atomic(ctx *gin.Context, fn func() error) error {
+	return queries.Atomic(ctx, d.child, fn)
+}
+
+func (d *middlewareDriver[Model]) Count(ctx *gin.Context) (int, error) {
+	return queries.Count(ctx, d.child)
+}
+
+func (d *middlewareDriver[Model]) Exists(ctx *gin.Context, conditions map[string]any) (bool, error) {
+	return queries.Exists(ctx, d.child, conditions)
+}
+
+func (d *middlewareDriver[Model]) Stats(ctx *gin.Context, field string, percentiles []float64) (common.Stats, error) {
+	return queries.Stats(ctx, d.child, field, percentiles)
+}
+
+func (d *middlewareDriver[Model]) Middleware() []gin.HandlerFunc {
+	return d.child.Middleware()
+}
+
+// withMiddleware returns a driver running the operations through the middleware, or the
+// driver itself if there is no middleware
+func withMiddleware[Model any](driver queries.Driver[Model], middleware []Middleware) queries.Driver[Model] {
+	if len(middleware) == 0 {
+		return driver
+	}
+	var m Model
+	d := &middlewareDriver[Model]{
+		child:     driver,
+		modelName: reflect.TypeOf(m).Name(),
+	}
+	d.pipeline = d.execute
+	// The first middleware is the outermost one
+	for i := len(middleware) - 1; i >= 0; i-- {
+		d.pipeline = middleware[i](d.pipeline)
+	}
+	return d
+}
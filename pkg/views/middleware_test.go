@@ -0,0 +1,147 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/notifications"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewSetMiddleware(t *testing.T) {
+	// given
+	type seenOperation struct {
+		action    ActionID
+		kind      OperationKind
+		modelName string
+	}
+	seen := []seenOperation{}
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+	)).WithMiddleware(
+		func(next OperationFunc) OperationFunc {
+			return func(op *Operation) (*OperationResult, error) {
+				seen = append(seen, seenOperation{op.Action, op.Kind, op.ModelName})
+				return next(op)
+			}
+		},
+		func(next OperationFunc) OperationFunc {
+			return func(op *Operation) (*OperationResult, error) {
+				if op.Kind == OperationCreate && op.InternalValue["name"] == "forbidden" {
+					return nil, errors.New("quota exceeded")
+				}
+				result, err := next(op)
+				if err == nil && op.Action == ActionRetrieve {
+					result.InternalValue["name"] = "***"
+				}
+				return result, err
+			}
+		},
+	)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	rt := quickReq(r, caseRetrieve.params)
+	up := quickReq(r, caseUpdate.params)
+	forbidden := quickReq(r, quickReqParams{method: "POST", path: "/mocks", body: strBody(`{"name": "forbidden"}`)})
+
+	// then
+	assert.Equal(t, 200, rt.Code)
+	assert.Equal(t, `{"id":1,"name":"***","price":1}`, rt.Body.String())
+	assert.Equal(t, 200, up.Code)
+	assert.Equal(t, 500, forbidden.Code)
+	assert.Equal(t, []seenOperation{
+		{ActionRetrieve, OperationRetrieve, "anotherMockModel"},
+		{ActionUpdate, OperationRetrieve, "anotherMockModel"},
+		{ActionUpdate, OperationUpdate, "anotherMockModel"},
+		{ActionCreate, OperationCreate, "anotherMockModel"},
+	}, seen)
+}
+
+type recordingSender struct {
+	mu       sync.Mutex
+	messages []notifications.Message
+}
+
+func (s *recordingSender) Send(_ context.Context, message notifications.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestViewSetWithNotifier(t *testing.T) {
+	// given
+	sender := &recordingSender{}
+	notifier := notifications.NewNotifier(
+		notifications.NewRule(sender, "{{.Object.name}} costs {{.Object.price}}").On(notifications.Updated).When(
+			notifications.FieldChanged("price"),
+		),
+		notifications.NewRule(sender, "{{.Object.name}} {{.Kind}}").On(notifications.Created, notifications.Destroyed),
+	)
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+	)).WithNotifier(notifier)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	up := quickReq(r, caseUpdate.params)
+	unchanged := quickReq(r, caseUpdate.params)
+	ds := quickReq(r, caseDestroy.params)
+	missing := quickReq(r, caseDestroy.params)
+	notifier.Wait()
+
+	// then
+	assert.Equal(t, 200, up.Code)
+	assert.Equal(t, 200, unchanged.Code)
+	assert.Equal(t, 204, ds.Code)
+	assert.Equal(t, 404, missing.Code)
+	bodies := []string{}
+	for _, message := range sender.messages {
+		bodies = append(bodies, message.Body)
+	}
+	assert.ElementsMatch(t, []string{"Canned Beans costs 2", "Canned Beans destroyed"}, bodies)
+}
+
+type checksummedMockModel struct {
+	ID       uint    `json:"id"`
+	Name     string  `json:"name"`
+	Price    float64 `json:"price"`
+	Checksum string  `json:"checksum"`
+}
+
+func TestViewSetWithChecksum(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[checksummedMockModel]("/mocks", queries.InMemory[checksummedMockModel]()).
+		WithSerializer(serializers.NewModelSerializer[checksummedMockModel]().ReadOnlyFields("checksum")).
+		WithChecksum("checksum", "price", "name")
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	created := quickReq(r, quickReqParams{
+		method: "POST", path: "/mocks", body: strBody(`{"name": "Beans", "price": 1, "checksum": "forged"}`),
+	})
+	updated := quickReq(r, quickReqParams{method: "PATCH", path: "/mocks/1", body: strBody(`{"price": 2}`)})
+
+	// then
+	assert.Equal(t, 201, created.Code)
+	assert.JSONEq(t, `{
+		"id": 1, "name": "Beans", "price": 1,
+		"checksum": "a8ee883fde64529dba92cb40f7c2e96e74e82e7f9b75b762a1c7d393da8a1b9a"
+	}`, created.Body.String())
+	assert.Equal(t, 200, updated.Code)
+	expected, checksumErr := Checksum(models.InternalValue{"name": "Beans", "price": 2.0}, "name", "price")
+	assert.NoError(t, checksumErr)
+	assert.JSONEq(t, `{"id": 1, "name": "Beans", "price": 2, "checksum": "`+expected+`"}`, updated.Body.String())
+	assert.Panics(t, func() { NewModelViewSet[checksummedMockModel]("/mocks", nil).WithChecksum("checksum") })
+}
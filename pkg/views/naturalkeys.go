@@ -0,0 +1,91 @@
+package views
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+)
+
+// NaturalKey makes the field holding the ID of the related object, like `country_id`, accept the
+// natural key of the object in the request bodies, like the country code, instead of its ID. The
+// key is resolved to the ID using the unique keyField of the related model, stored by the driver.
+// Unknown keys and keys matching multiple objects are rejected with validation errors. The
+// responses still contain the IDs. In the nested resources, see ViewSet.WithParent, the keys are
+// resolved among the related objects of the same parent, if the related model has the parent field.
+func NaturalKey[Related any](driver queries.Driver[Related], keyField string) func(oldField fields.Field) {
+	var related Related
+	relatedFields := models.AsInternalValue(related)
+	return func(oldField fields.Field) {
+		oldField.WithInternalValueFunc(func(raw map[string]any, name string, ctx *gin.Context) (any, error) {
+			key, present := raw[name]
+			if !present {
+				return nil, fields.NewErrorFieldIsNotPresentInPayload(name)
+			}
+			if key == nil {
+				return nil, nil
+			}
+			lookupCtx := detachedContext(ctx, driver)
+			if scope, scoped := grfctx.Parent(ctx); scoped {
+				if _, ok := relatedFields[scope.Field]; ok {
+					grfctx.SetParentScope(lookupCtx, scope)
+				}
+			}
+			grfctx.AddPredicates(lookupCtx, grfctx.Predicate{Field: keyField, Operator: grfctx.OperatorExact, Value: key})
+			// Two objects are enough to detect the ambiguous keys
+			grfctx.SetWindow(lookupCtx, grfctx.Window{Limit: 2})
+			matches, listErr := driver.CRUD().List(lookupCtx)
+			if listErr != nil {
+				return nil, fmt.Errorf("could not resolve %s `%v`: %w", keyField, key, listErr)
+			}
+			switch len(matches) {
+			case 0:
+				return nil, fmt.Errorf("Object with %s `%v` does not exist", keyField, key)
+			case 1:
+				return matches[0]["id"], nil
+			}
+			return nil, fmt.Errorf("Multiple objects with %s `%v` exist", keyField, key)
+		})
+	}
+}
+
+// detachedContext prepares the context of a query of another model, like scheduler.WithDriver, so
+// the state of the current request, like its filters or pagination, doesn't leak into the query.
+// The params, the headers and the values set by the application, like the user or the tenant, are
+// kept, so the drivers choosing the database by the request, see queries.DynamicGORM, use the same
+// one.
+func detachedContext[Related any](ctx *gin.Context, driver queries.Driver[Related]) *gin.Context {
+	request := &http.Request{Method: http.MethodGet, URL: &url.URL{}, Header: http.Header{}}
+	detached := &gin.Context{Request: request}
+	if ctx != nil {
+		if ctx.Request != nil {
+			if ctx.Request.Header != nil {
+				request.Header = ctx.Request.Header.Clone()
+			}
+			detached.Request = request.WithContext(ctx.Request.Context())
+		}
+		detached.Params = slices.Clone(ctx.Params)
+		for key, value := range ctx.Keys {
+			if !requestStateKey(key) {
+				detached.Set(key, value)
+			}
+		}
+	}
+	for _, middleware := range driver.Middleware() {
+		middleware(detached)
+	}
+	return detached
+}
+
+// requestStateKey checks if the context key holds the state of the request set by grf or the query
+// drivers, like the predicates, the window or the transaction
+func requestStateKey(key string) bool {
+	return strings.HasPrefix(key, "grf") || strings.HasPrefix(key, "db:gorm:")
+}
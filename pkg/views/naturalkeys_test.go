@@ -0,0 +1,110 @@
+package views
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/crud"
+	"github.com/glothriel/grf/pkg/queries/dummy"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+type naturalKeyCountry struct {
+	ID   uint   `json:"id"`
+	Code string `json:"code"`
+}
+
+type naturalKeyCity struct {
+	ID        uint   `json:"id"`
+	CountryID uint   `json:"country_id"`
+	Name      string `json:"name"`
+}
+
+func TestNaturalKey(t *testing.T) {
+	// given
+	countries := queries.InMemory[naturalKeyCountry](
+		naturalKeyCountry{Code: "PL"},
+		naturalKeyCountry{Code: "DE"},
+		naturalKeyCountry{Code: "XX"},
+		naturalKeyCountry{Code: "XX"},
+	)
+	viewset := NewModelViewSet[naturalKeyCity]("/cities", queries.InMemory[naturalKeyCity]()).WithSerializer(
+		serializers.NewModelSerializer[naturalKeyCity]().WithField("country_id", NaturalKey(countries, "code")),
+	)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	created := quickReq(r, quickReqParams{method: "POST", path: "/cities", body: strBody(`{"country_id": "DE", "name": "Berlin"}`)})
+	unknown := quickReq(r, quickReqParams{method: "POST", path: "/cities", body: strBody(`{"country_id": "FR", "name": "Paris"}`)})
+	ambiguous := quickReq(r, quickReqParams{method: "POST", path: "/cities", body: strBody(`{"country_id": "XX", "name": "Nowhere"}`)})
+	updated := quickReq(r, quickReqParams{method: "PATCH", path: "/cities/1", body: strBody(`{"country_id": "PL"}`)})
+
+	// then
+	assert.Equal(t, 201, created.Code)
+	assert.JSONEq(t, `{"id": 1, "country_id": 2, "name": "Berlin"}`, created.Body.String())
+	assert.Equal(t, 400, unknown.Code)
+	assert.JSONEq(t, `{"errors": {"country_id": ["Object with code `+"`FR`"+` does not exist"]}}`, unknown.Body.String())
+	assert.Equal(t, 400, ambiguous.Code)
+	assert.JSONEq(t, `{"errors": {"country_id": ["Multiple objects with code `+"`XX`"+` exist"]}}`, ambiguous.Body.String())
+	assert.Equal(t, 200, updated.Code)
+	assert.JSONEq(t, `{"id": 1, "country_id": 1, "name": "Berlin"}`, updated.Body.String())
+}
+
+type naturalKeyDistrict struct {
+	ID       uint   `json:"id"`
+	RegionID uint   `json:"region_id"`
+	Code     string `json:"code"`
+}
+
+type naturalKeyStreet struct {
+	ID         uint   `json:"id"`
+	RegionID   uint   `json:"region_id"`
+	DistrictID uint   `json:"district_id"`
+	Name       string `json:"name"`
+}
+
+// tenantRecordingDriver records the tenants of the contexts listing the objects
+type tenantRecordingDriver struct {
+	*dummy.InMemoryQueryDriver[naturalKeyDistrict]
+	tenants []any
+}
+
+func (d *tenantRecordingDriver) CRUD() *crud.CRUD[naturalKeyDistrict] {
+	queries := d.InMemoryQueryDriver.CRUD()
+	list := queries.List
+	return queries.WithList(func(ctx *gin.Context) ([]models.InternalValue, error) {
+		d.tenants = append(d.tenants, ctx.Value("tenant"))
+		return list(ctx)
+	})
+}
+
+func TestNaturalKeyIsResolvedInTheScopeOfTheRequest(t *testing.T) {
+	// given
+	districts := &tenantRecordingDriver{InMemoryQueryDriver: queries.InMemory[naturalKeyDistrict](
+		naturalKeyDistrict{RegionID: 1, Code: "OLD"},
+		naturalKeyDistrict{RegionID: 2, Code: "OLD"},
+	)}
+	viewset := NewModelViewSet[naturalKeyStreet]("/regions/:region_id/streets", queries.InMemory[naturalKeyStreet]()).
+		WithParent("region_id", "region_id").
+		WithSerializer(
+			serializers.NewModelSerializer[naturalKeyStreet]().WithField("district_id", NaturalKey[naturalKeyDistrict](districts, "code")),
+		)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	r.Use(func(ctx *gin.Context) {
+		ctx.Set("tenant", "acme")
+	})
+	viewset.Register(r)
+
+	// when
+	created := quickReq(r, quickReqParams{method: "POST", path: "/regions/2/streets", body: strBody(`{"district_id": "OLD", "name": "Main"}`)})
+
+	// then
+	assert.Equal(t, 201, created.Code)
+	assert.JSONEq(t, `{"id": 1, "region_id": 2, "district_id": 2, "name": "Main"}`, created.Body.String())
+	assert.Equal(t, []any{"acme"}, districts.tenants)
+}
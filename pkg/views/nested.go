@@ -0,0 +1,79 @@
+package views
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/sirupsen/logrus"
+)
+
+// WithParent makes the viewset a nested resource of the parent identified by the param of its
+// path, for example `/authors/:author_id/books`. The objects are scoped by the field referencing
+// the parent, so only the books of the author from the URL are listed, retrieved, updated and
+// destroyed. Created and updated objects get the parent key injected into the field. The query
+// driver receives the scope in grfctx.Parent.
+func (v *ViewSet[Model]) WithParent(param, field string) *ViewSet[Model] {
+	var m Model
+	fieldValue, ok := models.AsInternalValue(m)[field]
+	if !ok {
+		logrus.Panicf("WithParent: model `%T` does not have the `%s` field", m, field)
+	}
+	fieldType := reflect.TypeOf(fieldValue)
+	scopeMiddleware := func(ctx *gin.Context) {
+		value, parseErr := parseParentKey(ctx.Param(param), fieldType)
+		if parseErr != nil {
+			// The parent can't exist, if its key is not valid
+			WriteError(ctx, fmt.Errorf("%w: %s", common.ErrorNotFound, parseErr))
+			ctx.Abort()
+			return
+		}
+		grfctx.SetParentScope(ctx, grfctx.ParentScope{Field: field, Value: value})
+		ctx.Next()
+	}
+	v.ListCreateView.AddMiddleware(scopeMiddleware)
+	v.RetrieveUpdateDestroyView.AddMiddleware(scopeMiddleware)
+	return v.WithMiddleware(func(next OperationFunc) OperationFunc {
+		return func(op *Operation) (*OperationResult, error) {
+			scope, scoped := grfctx.Parent(op.Ctx)
+			if scoped && (op.Kind == OperationCreate || op.Kind == OperationUpdate) {
+				op.InternalValue = op.InternalValue.Clone()
+				op.InternalValue[field] = scope.Value
+			}
+			return next(op)
+		}
+	})
+}
+
+func parseParentKey(raw string, fieldType reflect.Type) (any, error) {
+	value := reflect.New(fieldType).Elem()
+	switch fieldType.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, parseErr := strconv.ParseInt(raw, 10, fieldType.Bits())
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, parseErr := strconv.ParseUint(raw, 10, fieldType.Bits())
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		value.SetUint(parsed)
+	default:
+		text, ok := value.Addr().Interface().(interface{ UnmarshalText([]byte) error })
+		if !ok {
+			return nil, fmt.Errorf("unsupported parent key type %s", fieldType)
+		}
+		if unmarshalErr := text.UnmarshalText([]byte(raw)); unmarshalErr != nil {
+			return nil, unmarshalErr
+		}
+	}
+	return value.Interface(), nil
+}
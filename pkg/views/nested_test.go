@@ -0,0 +1,54 @@
+package views
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+type nestedBook struct {
+	ID       uint   `json:"id"`
+	AuthorID uint   `json:"author_id"`
+	Title    string `json:"title"`
+}
+
+func TestViewSetWithParent(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[nestedBook]("/authors/:author_id/books", queries.InMemory[nestedBook](
+		nestedBook{AuthorID: 1, Title: "first"},
+		nestedBook{AuthorID: 2, Title: "second"},
+		nestedBook{AuthorID: 1, Title: "third"},
+	)).WithParent("author_id", "author_id")
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	listed := quickReq(r, quickReqParams{method: "GET", path: "/authors/1/books", body: noBody})
+	otherAuthors := quickReq(r, quickReqParams{method: "GET", path: "/authors/1/books/2", body: noBody})
+	created := quickReq(r, quickReqParams{method: "POST", path: "/authors/2/books", body: strBody(`{"title": "fourth"}`)})
+	moved := quickReq(r, quickReqParams{
+		method: "PATCH", path: "/authors/1/books/1", body: strBody(`{"author_id": 2, "title": "renamed"}`),
+	})
+	deletedOtherAuthors := quickReq(r, quickReqParams{method: "DELETE", path: "/authors/1/books/2", body: noBody})
+	invalidParent := quickReq(r, quickReqParams{method: "GET", path: "/authors/abc/books", body: noBody})
+
+	// then
+	var titles []map[string]any
+	assert.Equal(t, 200, listed.Code)
+	assert.NoError(t, json.Unmarshal(listed.Body.Bytes(), &titles))
+	assert.ElementsMatch(t, []map[string]any{
+		{"id": 1.0, "author_id": 1.0, "title": "first"},
+		{"id": 3.0, "author_id": 1.0, "title": "third"},
+	}, titles)
+	assert.Equal(t, 404, otherAuthors.Code)
+	assert.Equal(t, 201, created.Code)
+	assert.JSONEq(t, `{"id": 4, "author_id": 2, "title": "fourth"}`, created.Body.String())
+	assert.Equal(t, 200, moved.Code)
+	assert.JSONEq(t, `{"id": 1, "author_id": 1, "title": "renamed"}`, moved.Body.String())
+	assert.Equal(t, 404, deletedOtherAuthors.Code)
+	assert.Equal(t, 404, invalidParent.Code)
+}
@@ -0,0 +1,85 @@
+package views
+
+import (
+	"context"
+
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/notifications"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/sirupsen/logrus"
+)
+
+// WithNotifier sends the notifications of the notifier after the objects are created, updated or
+// destroyed, including by the bulk actions. The events hold the representations of the objects,
+// produced by the detail serializer of the viewset. The events are logged and sent after the
+// transaction of the operation commits, see queries.AfterCommit, and the messages are sent in the
+// background, see Notifier.Dispatch, so the objects are already stored and failures are only logged.
+func (v *ViewSet[Model]) WithNotifier(notifier *notifications.Notifier) *ViewSet[Model] {
+	return v.WithMiddleware(func(next OperationFunc) OperationFunc {
+		return func(op *Operation) (*OperationResult, error) {
+			event := notifications.Event{Model: op.ModelName}
+			switch op.Kind {
+			case OperationCreate:
+				event.Kind = notifications.Created
+			case OperationUpdate:
+				event.Kind = notifications.Updated
+			case OperationDestroy:
+				event.Kind = notifications.Destroyed
+			default:
+				return next(op)
+			}
+			if !notifier.Subscribed(event.Kind) {
+				return next(op)
+			}
+			var destroyed models.InternalValue
+			if op.Kind == OperationDestroy {
+				retrieved, retrieveErr := next(&Operation{
+					Ctx: op.Ctx, Action: op.Action, Kind: OperationRetrieve, ModelName: op.ModelName, ID: op.ID,
+				})
+				if retrieveErr != nil {
+					return nil, retrieveErr
+				}
+				destroyed = retrieved.InternalValue
+			}
+			result, err := next(op)
+			if err != nil {
+				return result, err
+			}
+			object := result.InternalValue
+			if op.Kind == OperationDestroy {
+				object = destroyed
+			}
+			if representationErr := v.fillRepresentations(op, &event, object); representationErr != nil {
+				logrus.Errorf("Failed to notify about %s %s: %s", event.Model, event.Kind, representationErr)
+				return result, nil
+			}
+			// the gin context is reused by other requests, so the background sends only get the request's
+			var requestCtx context.Context = context.Background()
+			if op.Ctx.Request != nil {
+				requestCtx = op.Ctx.Request.Context()
+			}
+			queries.AfterCommit(op.Ctx, func() {
+				if notifyErr := notifier.Dispatch(requestCtx, event); notifyErr != nil {
+					logrus.Errorf("Failed to notify about %s %s: %s", event.Model, event.Kind, notifyErr)
+				}
+			})
+			return result, nil
+		}
+	})
+}
+
+// fillRepresentations fills the representations of the event's objects, while the request is
+// still being handled
+func (v *ViewSet[Model]) fillRepresentations(op *Operation, event *notifications.Event, object models.InternalValue) error {
+	serializer := v.withHooks(v.actionSerializer(false))
+	var representationErr error
+	if event.Object, representationErr = serializer.ToRepresentation(object, op.Ctx); representationErr != nil {
+		return representationErr
+	}
+	if op.Kind == OperationUpdate && op.OldInternalValue != nil {
+		if event.Previous, representationErr = serializer.ToRepresentation(op.OldInternalValue, op.Ctx); representationErr != nil {
+			return representationErr
+		}
+	}
+	return nil
+}
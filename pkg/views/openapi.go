@@ -0,0 +1,223 @@
+package views
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/authentication"
+	"github.com/glothriel/grf/pkg/filters"
+	"github.com/glothriel/grf/pkg/openapi"
+	"github.com/glothriel/grf/pkg/pagination"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// OpenAPI adds the CRUD operations of the viewset to the document, the objects are described by a
+// schema in the components, named like the model, built using the metadata of the serializers, see
+// serializers.Describer, with the descriptions and the examples of the fields and of the viewset.
+// The paginated lists are described by the paginators implementing pagination.Documented. The extra
+// actions aren't described.
+func (v *ViewSet[Model]) OpenAPI(document *openapi.Document) {
+	var m Model
+	name := reflect.TypeOf(m).Name()
+	schema := v.openAPISchema()
+	if document.Components.Schemas == nil {
+		document.Components.Schemas = map[string]*openapi.Schema{}
+	}
+	document.Components.Schemas[name] = schema
+	ref := &openapi.Schema{Ref: "#/components/schemas/" + name}
+	tags := []string{name}
+	idSchema := &openapi.Schema{Type: "string"}
+	if id, ok := schema.Properties["id"]; ok && id.Type != nil {
+		idSchema = &openapi.Schema{Type: id.Type, Format: id.Format}
+	}
+
+	list := &openapi.PathItem{}
+	if v.ListAction != nil {
+		list.Get = &openapi.Operation{
+			Tags: tags, Summary: "List the " + name + " objects", Parameters: v.openAPIListParameters(),
+			Responses: map[string]*openapi.Response{"200": jsonResponse("OK", v.openAPIListSchema(ref))},
+		}
+	}
+	if v.CreateAction != nil {
+		list.Post = &openapi.Operation{
+			Tags: tags, Summary: "Create a " + name, RequestBody: jsonRequestBody(ref, true),
+			Responses: map[string]*openapi.Response{
+				"201": jsonResponse("Created", ref), "400": {Description: "Invalid payload"},
+			},
+		}
+	}
+	if list.Get != nil || list.Post != nil {
+		path := openAPIPath(v.ListCreateView.path)
+		list.Parameters = pathParameters(path, idSchema)
+		document.Paths[path] = list
+	}
+
+	detail := &openapi.PathItem{}
+	notFound := &openapi.Response{Description: "Not found"}
+	if v.RetrieveAction != nil {
+		detail.Get = &openapi.Operation{
+			Tags: tags, Summary: "Retrieve a " + name,
+			Responses: map[string]*openapi.Response{"200": jsonResponse("OK", ref), "404": notFound},
+		}
+	}
+	if v.UpdateAction != nil {
+		detail.Put = &openapi.Operation{
+			Tags: tags, Summary: "Update a " + name, RequestBody: jsonRequestBody(ref, true),
+			Responses: map[string]*openapi.Response{
+				"200": jsonResponse("OK", ref), "400": {Description: "Invalid payload"}, "404": notFound,
+			},
+		}
+	}
+	if v.PartialUpdateAction != nil {
+		detail.Patch = &openapi.Operation{
+			Tags: tags, Summary: "Partially update a " + name, RequestBody: jsonRequestBody(ref, false),
+			Description: "Only the fields present in the payload are updated",
+			Responses: map[string]*openapi.Response{
+				"200": jsonResponse("OK", ref), "400": {Description: "Invalid payload"}, "404": notFound,
+			},
+		}
+	}
+	if v.DestroyAction != nil {
+		detail.Delete = &openapi.Operation{
+			Tags: tags, Summary: "Delete a " + name,
+			Responses: map[string]*openapi.Response{"204": {Description: "Deleted"}, "404": notFound},
+		}
+	}
+	if detail.Get != nil || detail.Put != nil || detail.Patch != nil || detail.Delete != nil {
+		path := openAPIPath(v.RetrieveUpdateDestroyView.path)
+		detail.Parameters = pathParameters(path, idSchema)
+		document.Paths[path] = detail
+	}
+}
+
+// openAPISchema describes the fields of the serializers of the actions, the fields of the retrieve
+// and the list serializers come first, so the responses are described precisely
+func (v *ViewSet[Model]) openAPISchema() *openapi.Schema {
+	schema := &openapi.Schema{
+		Type: "object", Properties: map[string]*openapi.Schema{}, Description: v.description, Example: v.example,
+	}
+	for _, action := range []*ViewSetAction[Model]{v.RetrieveAction, v.ListAction, v.CreateAction, v.UpdateAction} {
+		if action == nil {
+			continue
+		}
+		describer, ok := action.Serializer.(serializers.Describer)
+		if !ok {
+			continue
+		}
+		for name, field := range describer.Describe() {
+			if _, exists := schema.Properties[name]; exists {
+				continue
+			}
+			schema.Properties[name] = fieldSchema(field)
+			if field.Required && !field.ReadOnly {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema
+}
+
+func (v *ViewSet[Model]) openAPIListParameters() []openapi.Parameter {
+	parameters := []openapi.Parameter{}
+	if documented, ok := v.listPaginator().(pagination.Documented); ok {
+		parameters = append(parameters, documented.OpenAPIParameters()...)
+	}
+	if len(v.searchFields) > 0 {
+		parameters = append(parameters, openapi.Parameter{
+			Name: filters.SearchQueryParam, In: "query", Schema: &openapi.Schema{Type: "string"},
+			Description: "Searches the " + strings.Join(v.searchFields, ", ") + " fields",
+		})
+	}
+	if len(v.orderingFields) > 0 {
+		parameters = append(parameters, openapi.Parameter{
+			Name: filters.OrderingQueryParam, In: "query", Schema: &openapi.Schema{Type: "string"},
+			Description: "Orders by the comma separated fields, descending if prefixed with `-`: " +
+				strings.Join(v.orderingFields, ", "),
+		})
+	}
+	return parameters
+}
+
+// openAPIListSchema describes the response of the list, the pagination envelope of the paginators
+// implementing pagination.Documented or the array of the objects
+func (v *ViewSet[Model]) openAPIListSchema(ref *openapi.Schema) *openapi.Schema {
+	if documented, ok := v.listPaginator().(pagination.Documented); ok {
+		return documented.OpenAPISchema(ref)
+	}
+	return &openapi.Schema{Type: "array", Items: ref}
+}
+
+// fieldSchema converts the metadata of the field to its JSON schema
+func fieldSchema(field *serializers.FieldMetadata) *openapi.Schema {
+	schema := &openapi.Schema{
+		ReadOnly:    field.ReadOnly,
+		WriteOnly:   field.WriteOnly,
+		Enum:        field.Choices,
+		Default:     field.Default,
+		Description: field.Description,
+		Example:     field.Example,
+	}
+	switch field.Type {
+	case "datetime":
+		schema.Type, schema.Format = "string", "date-time"
+	case "field":
+		// the type is unknown, any value is described
+	default:
+		schema.Type = field.Type
+	}
+	return schema
+}
+
+func jsonRequestBody(schema *openapi.Schema, required bool) *openapi.RequestBody {
+	return &openapi.RequestBody{
+		Required: required, Content: map[string]openapi.MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+func jsonResponse(description string, schema *openapi.Schema) *openapi.Response {
+	return &openapi.Response{
+		Description: description, Content: map[string]openapi.MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+// openAPIPath converts the gin path params, like `/products/:id`, to the OpenAPI ones, like
+// `/products/{id}`
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// pathParameters describes the params of the OpenAPI path, the IDs of the objects and of their
+// parents, see WithParent, are described by the schema
+func pathParameters(path string, idSchema *openapi.Schema) []openapi.Parameter {
+	var parameters []openapi.Parameter
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			parameters = append(parameters, openapi.Parameter{
+				Name: strings.Trim(segment, "{}"), In: "path", Required: true, Schema: idSchema,
+			})
+		}
+	}
+	return parameters
+}
+
+// NewOpenAPIView creates a View responding to the GET requests with the document, for example built
+// using ViewSet.OpenAPI
+func NewOpenAPIView(path string, document *openapi.Document) *View {
+	return (&View{
+		path:          path,
+		authenticator: &authentication.AnonymousUserAuthentication{},
+		extraRoutes:   []*ViewRoute{},
+	}).Get(func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, document)
+	})
+}
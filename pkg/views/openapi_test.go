@@ -0,0 +1,92 @@
+package views
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/openapi"
+	"github.com/glothriel/grf/pkg/pagination"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewSetOpenAPI(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[describedModel]("/people", queries.InMemory[describedModel]()).WithSerializer(
+		serializers.NewValidatingSerializer[describedModel](
+			serializers.NewModelSerializer[describedModel]().WithField("name", fields.Described("Full name of the person", "Jane Doe")),
+			serializers.NewGoPlaygroundValidator[describedModel](map[string]any{"name": "required,max=50"}),
+		),
+	).WithDescription("People registered in the system").
+		WithPagination(pagination.NewLimitOffsetPagination(10)).
+		WithoutActions(ActionDestroy)
+	document := openapi.NewDocument("People API", "1.0.0")
+	viewset.OpenAPI(document)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	NewOpenAPIView("/openapi.json", document).Register(r)
+
+	// when
+	w := quickReq(r, quickReqParams{method: "GET", path: "/openapi.json", body: noBody})
+
+	// then
+	assert.Equal(t, 200, w.Code)
+	var served map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &served))
+	assert.Equal(t, "3.0.3", served["openapi"])
+	assert.Equal(t, map[string]any{"title": "People API", "version": "1.0.0"}, served["info"])
+	schema, _ := json.Marshal(served["components"].(map[string]any)["schemas"].(map[string]any)["describedModel"])
+	assert.JSONEq(t, `{
+		"type": "object",
+		"description": "People registered in the system",
+		"properties": {
+			"id": {"type": "integer", "readOnly": true},
+			"name": {"type": "string", "description": "Full name of the person", "example": "Jane Doe"},
+			"age": {"type": "integer"},
+			"created_at": {"type": "string", "format": "date-time"}
+		},
+		"required": ["name"]
+	}`, string(schema))
+	list, _ := json.Marshal(served["paths"].(map[string]any)["/people"].(map[string]any)["get"])
+	assert.JSONEq(t, `{
+		"tags": ["describedModel"],
+		"summary": "List the describedModel objects",
+		"parameters": [
+			{
+				"name": "limit", "in": "query", "schema": {"type": "integer"},
+				"description": "The maximum number of the objects in the response"
+			},
+			{
+				"name": "offset", "in": "query", "schema": {"type": "integer"},
+				"description": "The number of the objects to skip"
+			}
+		],
+		"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {
+			"type": "object",
+			"properties": {
+				"count": {"type": "integer"},
+				"next": {"type": "string", "nullable": true},
+				"previous": {"type": "string", "nullable": true},
+				"results": {"type": "array", "items": {"$ref": "#/components/schemas/describedModel"}}
+			},
+			"required": ["count", "next", "previous", "results"]
+		}}}}}
+	}`, string(list))
+	detail := served["paths"].(map[string]any)["/people/{describedmodel_id}"].(map[string]any)
+	assert.ElementsMatch(t, []string{"get", "put", "patch", "parameters"}, keys(detail))
+	assert.Equal(t, []any{map[string]any{
+		"name": "describedmodel_id", "in": "path", "required": true, "schema": map[string]any{"type": "integer"},
+	}}, detail["parameters"])
+	assert.Empty(t, openapi.Diff(document, document))
+}
+
+func keys(m map[string]any) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
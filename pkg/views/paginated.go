@@ -0,0 +1,129 @@
+package views
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/pagination"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/sirupsen/logrus"
+)
+
+// PaginatedListModelViewSetFunc lists a single page of the model instances. The window of the page
+// is passed to the driver using grfctx.SetWindow and the objects are counted if the driver
+// implements queries.Counter. Otherwise all the objects are listed and the page is sliced in memory.
+// Keyset windows aren't counted and are applied to the listed objects again, in case the driver
+// doesn't support them. It panics if the query driver paginates the lists by itself, see
+// common.Paginates, the lists can't be paginated twice.
+func PaginatedListModelViewSetFunc[Model any](paginator pagination.Paginator) ViewSetHandlerFactoryFunc[Model] {
+	return func(_ IDFunc, qd queries.Driver[Model], serializer serializers.Serializer) gin.HandlerFunc {
+		if common.Paginates(qd.Pagination()) {
+			logrus.Panicf("The list is paginated by the query driver, it can't be paginated by %T too", paginator)
+		}
+		return func(ctx *gin.Context) {
+			window, windowErr := paginator.Window(ctx)
+			if windowErr != nil {
+				WriteError(ctx, windowErr)
+				return
+			}
+			qd.Filter().Apply(ctx)
+			qd.Order().Apply(ctx)
+			internalValues, count, listErr := listWindow(ctx, qd, window)
+			if listErr != nil {
+				WriteError(ctx, listErr)
+				return
+			}
+			page := pagination.Page{Results: []any{}, InternalValues: internalValues, Count: count, Window: window}
+			for _, internalValue := range internalValues {
+				representation, toRawErr := serializer.ToRepresentation(internalValue, ctx)
+				if toRawErr != nil {
+					WriteError(ctx, toRawErr)
+					return
+				}
+				page.Results = append(page.Results, representation)
+			}
+			retVal, formatErr := paginator.Format(ctx, page)
+			if formatErr != nil {
+				WriteError(ctx, formatErr)
+				return
+			}
+			render(ctx, http.StatusOK, retVal)
+		}
+	}
+}
+
+// listWindow lists the objects in the window and counts all of them, except for keyset windows
+func listWindow[Model any](
+	ctx *gin.Context, qd queries.Driver[Model], window grfctx.Window,
+) ([]models.InternalValue, int, error) {
+	if window.Keyset != nil {
+		grfctx.SetWindow(ctx, window)
+		internalValues, listErr := qd.CRUD().List(ctx)
+		return common.ApplyWindow(internalValues, window), 0, listErr
+	}
+	count, countErr := queries.Count(ctx, qd)
+	if countErr == nil {
+		grfctx.SetWindow(ctx, window)
+		internalValues, listErr := qd.CRUD().List(ctx)
+		return internalValues, count, listErr
+	}
+	if !errors.Is(countErr, queries.ErrCountUnsupported) {
+		return nil, 0, countErr
+	}
+	internalValues, listErr := qd.CRUD().List(ctx)
+	if listErr != nil {
+		return nil, 0, listErr
+	}
+	return common.ApplyWindow(internalValues, window), len(internalValues), nil
+}
+
+// WithPagination splits the results of the list action into pages using the paginator, see
+// PaginatedListModelViewSetFunc
+func (v *ViewSet[Model]) WithPagination(paginator pagination.Paginator) *ViewSet[Model] {
+	v.WithList(PaginatedListModelViewSetFunc[Model](paginator))
+	v.paginator = paginator
+	return v
+}
+
+// WithPageSize sets the default page size of the list and the maximum page size the clients can
+// request, replacing the ones of the paginator, so a shared paginator can be used by the viewsets
+// listing objects of different sizes. Non-positive values keep the paginator's settings.
+func (v *ViewSet[Model]) WithPageSize(pageSize, maxPageSize int) *ViewSet[Model] {
+	v.pageSize = pageSize
+	v.maxPageSize = maxPageSize
+	return v
+}
+
+// WithMandatoryPagination prevents unbounded lists. If no paginator was set using WithPagination
+// and the query driver doesn't paginate the lists by itself, the list is paginated using
+// PageNumberPagination with the page sizes set by WithPageSize, replacing the list handler set using
+// WithList. Without the maximum page size, the clients can request at most
+// pagination.DefaultMaxPageSize objects.
+func (v *ViewSet[Model]) WithMandatoryPagination() *ViewSet[Model] {
+	v.paginationRequired = true
+	return v
+}
+
+// listPaginator returns the paginator of the list, the one set by WithPagination or the one
+// required by WithMandatoryPagination, or nil if the list isn't paginated by the view
+func (v *ViewSet[Model]) listPaginator() pagination.Paginator {
+	if v.paginator != nil || !v.paginationRequired || v.driverPaginates() {
+		return v.paginator
+	}
+	maxPageSize := v.maxPageSize
+	if maxPageSize <= 0 {
+		maxPageSize = max(pagination.DefaultMaxPageSize, v.pageSize)
+	}
+	return pagination.NewPageNumberPagination(v.pageSize).WithMaxPageSize(maxPageSize)
+}
+
+// driverPaginates reports if the query driver paginates the lists by itself, for example using
+// gormq.ContinuationPagination
+func (v *ViewSet[Model]) driverPaginates() bool {
+	return v.QueryDriver != nil && common.Paginates(v.QueryDriver.Pagination())
+}
@@ -0,0 +1,286 @@
+package views
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/pagination"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+func seedProducts(n int) []anotherMockModel {
+	products := make([]anotherMockModel, 0, n)
+	for i := 1; i <= n; i++ {
+		products = append(products, anotherMockModel{Name: "Product", Price: float64(i)})
+	}
+	return products
+}
+
+func TestPageNumberPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "first page",
+			path:         "/mocks?page_size=2",
+			expectedCode: 200,
+			expectedBody: `{"count": 5, "next": "/mocks?page=2&page_size=2", "previous": null, "results": [
+				{"id": 1, "name": "Product", "price": 1}, {"id": 2, "name": "Product", "price": 2}
+			]}`,
+		},
+		{
+			name:         "second page",
+			path:         "/mocks?page=2&page_size=2",
+			expectedCode: 200,
+			expectedBody: `{"count": 5, "next": "/mocks?page=3&page_size=2", "previous": "/mocks?page_size=2", "results": [
+				{"id": 3, "name": "Product", "price": 3}, {"id": 4, "name": "Product", "price": 4}
+			]}`,
+		},
+		{
+			name:         "last page",
+			path:         "/mocks?page=3&page_size=2",
+			expectedCode: 200,
+			expectedBody: `{"count": 5, "next": null, "previous": "/mocks?page=2&page_size=2", "results": [
+				{"id": 5, "name": "Product", "price": 5}
+			]}`,
+		},
+		{
+			name:         "page size limited by max page size",
+			path:         "/mocks?page_size=100",
+			expectedCode: 200,
+			expectedBody: `{"count": 5, "next": "/mocks?page=2&page_size=100", "previous": null, "results": [
+				{"id": 1, "name": "Product", "price": 1}, {"id": 2, "name": "Product", "price": 2},
+				{"id": 3, "name": "Product", "price": 3}
+			]}`,
+		},
+		{
+			name:         "page out of range",
+			path:         "/mocks?page=4&page_size=2",
+			expectedCode: 404,
+			expectedBody: `{"message": "invalid page: not found"}`,
+		},
+		{
+			name:         "invalid page",
+			path:         "/mocks?page=first",
+			expectedCode: 404,
+			expectedBody: `{"message": "invalid page: not found"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			viewset := NewModelViewSet[anotherMockModel](
+				"/mocks", queries.InMemory(seedProducts(5)...),
+			).WithPagination(pagination.NewPageNumberPagination(2).WithMaxPageSize(3))
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			viewset.Register(r)
+
+			// when
+			response := quickReq(r, quickReqParams{method: "GET", path: tt.path, body: noBody})
+
+			// then
+			assert.Equal(t, tt.expectedCode, response.Code)
+			assert.JSONEq(t, tt.expectedBody, response.Body.String())
+		})
+	}
+}
+
+// plainDriver hides the optional interfaces implemented by the driver, like queries.Counter
+type plainDriver[Model any] struct {
+	queries.Driver[Model]
+}
+
+func TestPageNumberPaginationWithoutCounter(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel](
+		"/mocks", plainDriver[anotherMockModel]{queries.InMemory(seedProducts(5)...)},
+	).WithPagination(pagination.NewPageNumberPagination(2))
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	response := quickReq(r, quickReqParams{method: "GET", path: "/mocks?page=3", body: noBody})
+
+	// then
+	assert.Equal(t, 200, response.Code)
+	var page pagination.PageNumberResponse
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &page))
+	assert.Equal(t, 5, page.Count)
+	assert.Len(t, page.Results, 1)
+	assert.Nil(t, page.Next)
+	assert.Equal(t, "/mocks?page=2", *page.Previous)
+}
+
+func TestCursorPagination(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel](
+		"/mocks", queries.InMemory(seedProducts(5)...),
+	).WithPagination(pagination.NewCursorPagination("-price", 2))
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+	fetch := func(path string) (pagination.CursorResponse, []float64) {
+		response := quickReq(r, quickReqParams{method: "GET", path: path, body: noBody})
+		assert.Equal(t, 200, response.Code)
+		var page pagination.CursorResponse
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &page))
+		prices := []float64{}
+		for _, result := range page.Results {
+			prices = append(prices, result.(map[string]any)["price"].(float64))
+		}
+		return page, prices
+	}
+
+	// when
+	first, firstPrices := fetch("/mocks")
+	second, secondPrices := fetch(*first.Next)
+	third, thirdPrices := fetch(*second.Next)
+	_, backPrices := fetch(*third.Previous)
+
+	// then
+	assert.Equal(t, []float64{5, 4}, firstPrices)
+	assert.Nil(t, first.Previous)
+	assert.Equal(t, []float64{3, 2}, secondPrices)
+	assert.NotNil(t, second.Previous)
+	assert.Equal(t, []float64{1}, thirdPrices)
+	assert.Nil(t, third.Next)
+	assert.Equal(t, []float64{3, 2}, backPrices)
+}
+
+func TestCursorPaginationInvalidCursor(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel](
+		"/mocks", queries.InMemory(seedProducts(5)...),
+	).WithPagination(pagination.NewCursorPagination("id", 2))
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	response := quickReq(r, quickReqParams{method: "GET", path: "/mocks?cursor=bm90IGEgY3Vyc29y", body: noBody})
+
+	// then
+	assert.Equal(t, 404, response.Code)
+	assert.JSONEq(t, `{"message": "invalid cursor: not found"}`, response.Body.String())
+}
+
+func TestViewSetPageSize(t *testing.T) {
+	tests := []struct {
+		name            string
+		path            string
+		expectedResults int
+	}{
+		{name: "default page size of the view", path: "/mocks", expectedResults: 3},
+		{name: "requested page size", path: "/mocks?limit=2", expectedResults: 2},
+		{name: "requested page size limited by the view", path: "/mocks?limit=100", expectedResults: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			viewset := NewModelViewSet[anotherMockModel](
+				"/mocks", queries.InMemory(seedProducts(10)...),
+			).WithPagination(pagination.NewLimitOffsetPagination(5).WithMaxLimit(50)).WithPageSize(3, 4)
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			viewset.Register(r)
+
+			// when
+			response := quickReq(r, quickReqParams{method: "GET", path: tt.path, body: noBody})
+
+			// then
+			assert.Equal(t, 200, response.Code)
+			var page pagination.PageNumberResponse
+			assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &page))
+			assert.Equal(t, 10, page.Count)
+			assert.Len(t, page.Results, tt.expectedResults)
+		})
+	}
+}
+
+func TestViewSetMandatoryPagination(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel](
+		"/mocks", queries.InMemory(seedProducts(5)...),
+	).WithPageSize(2, 3).WithMandatoryPagination()
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	response := quickReq(r, quickReqParams{method: "GET", path: "/mocks?page_size=100", body: noBody})
+
+	// then
+	assert.Equal(t, 200, response.Code)
+	var page pagination.PageNumberResponse
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &page))
+	assert.Equal(t, 5, page.Count)
+	assert.Len(t, page.Results, 3)
+	assert.Equal(t, "/mocks?page=2&page_size=100", *page.Next)
+	assert.Nil(t, viewset.paginator)
+}
+
+func TestViewSetMandatoryPaginationLimitsThePageSizeByDefault(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel](
+		"/mocks", queries.InMemory(seedProducts(pagination.DefaultMaxPageSize+1)...),
+	).WithMandatoryPagination()
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	response := quickReq(r, quickReqParams{method: "GET", path: "/mocks?page_size=100000", body: noBody})
+
+	// then
+	assert.Equal(t, 200, response.Code)
+	var page pagination.PageNumberResponse
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &page))
+	assert.Equal(t, pagination.DefaultMaxPageSize+1, page.Count)
+	assert.Len(t, page.Results, pagination.DefaultMaxPageSize)
+}
+
+// firstObjectPagination is a pagination of the query driver, listing only the first object
+type firstObjectPagination struct{}
+
+func (firstObjectPagination) Apply(_ *gin.Context, internalValues []models.InternalValue) []models.InternalValue {
+	return internalValues[:min(1, len(internalValues))]
+}
+
+func (firstObjectPagination) Format(_ *gin.Context, elems []any) (any, error) {
+	return elems, nil
+}
+
+func TestViewSetPaginationPanicsIfTheDriverPaginatesTheList(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel](
+		"/mocks", queries.InMemory(seedProducts(3)...).WithPagination(firstObjectPagination{}),
+	).WithPagination(pagination.NewLimitOffsetPagination(2))
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+
+	// when
+	register := func() { viewset.Register(r) }
+
+	// then
+	assert.Panics(t, register)
+}
+
+func TestViewSetMandatoryPaginationKeepsThePaginationOfTheDriver(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel](
+		"/mocks", queries.InMemory(seedProducts(3)...).WithPagination(firstObjectPagination{}),
+	).WithMandatoryPagination()
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	response := quickReq(r, quickReqParams{method: "GET", path: "/mocks", body: noBody})
+
+	// then
+	assert.Equal(t, 200, response.Code)
+	var results []map[string]any
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &results))
+	assert.Len(t, results, 1)
+	assert.Nil(t, viewset.listPaginator())
+}
@@ -0,0 +1,100 @@
+package views
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries/common"
+)
+
+// ErrorFormat selects how WriteError renders the errors
+type ErrorFormat int
+
+const (
+	// ErrorFormatDefault renders the errors as `{"message": ...}` or `{"errors": {...}}`
+	ErrorFormatDefault ErrorFormat = iota
+	// ErrorFormatProblem renders the errors as the RFC 7807 problem details, with the
+	// `application/problem+json` content type. The errors of the fields are kept in the `errors`
+	// extension member.
+	ErrorFormatProblem
+)
+
+// DefaultErrorFormat is the format of the errors of the views, that don't set their own format using
+// WithErrorFormat. It should be set before the views start serving the requests.
+var DefaultErrorFormat = ErrorFormatDefault
+
+const errorFormatCtxKey = "grf.errorFormat"
+
+// WithErrorFormat sets the format of the errors of the view, taking precedence over
+// DefaultErrorFormat
+func (v *View) WithErrorFormat(format ErrorFormat) *View {
+	v.errorFormat = &format
+	return v
+}
+
+// WithErrorFormat sets the format of the errors of all the viewset's routes, taking precedence over
+// DefaultErrorFormat
+func (v *ViewSet[Model]) WithErrorFormat(format ErrorFormat) *ViewSet[Model] {
+	v.ListCreateView.WithErrorFormat(format)
+	v.RetrieveUpdateDestroyView.WithErrorFormat(format)
+	return v
+}
+
+// errorFormatMiddleware stores the format of the errors of the view, it runs before the
+// authentication, so its errors use the format as well
+func errorFormatMiddleware(format ErrorFormat) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(errorFormatCtxKey, format)
+		ctx.Next()
+	}
+}
+
+func currentErrorFormat(ctx *gin.Context) ErrorFormat {
+	if format, ok := ctx.Get(errorFormatCtxKey); ok {
+		return format.(ErrorFormat)
+	}
+	return DefaultErrorFormat
+}
+
+// HandleUnmatchedRoutes responds to the requests, that don't match any route of the engine, with
+// the errors in DefaultErrorFormat instead of gin's plain text ones: 404, or 405 if the path is
+// routed for other methods, enabling engine.HandleMethodNotAllowed. The routes of the views answer
+// the unhandled methods with 405 on their own.
+func HandleUnmatchedRoutes(engine *gin.Engine) {
+	engine.HandleMethodNotAllowed = true
+	engine.NoRoute(func(ctx *gin.Context) {
+		WriteError(ctx, common.ErrorNotFound)
+	})
+	engine.NoMethod(func(ctx *gin.Context) {
+		WriteError(ctx, ErrMethodNotAllowed)
+	})
+}
+
+// writeErrorResponse writes the body of the error in the format of the view
+func writeErrorResponse(ctx *gin.Context, status int, body gin.H) {
+	if currentErrorFormat(ctx) != ErrorFormatProblem {
+		render(ctx, status, body)
+		return
+	}
+	ctx.Header("Content-Type", "application/problem+json")
+	ctx.JSON(status, problemDetails(ctx, status, body))
+}
+
+// problemDetails converts the body of the error to the problem details: the message becomes the
+// `detail` member and the other members, like the errors of the fields, become the extension members
+func problemDetails(ctx *gin.Context, status int, body gin.H) gin.H {
+	problem := gin.H{"type": "about:blank", "title": http.StatusText(status), "status": status}
+	if ctx.Request != nil && ctx.Request.URL != nil {
+		problem["instance"] = ctx.Request.URL.Path
+	}
+	for key, value := range body {
+		if key == "message" {
+			key = "detail"
+		}
+		problem[key] = value
+	}
+	if _, hasDetail := problem["detail"]; !hasDetail {
+		problem["detail"] = "the request is invalid"
+	}
+	return problem
+}
@@ -0,0 +1,152 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/sirupsen/logrus"
+)
+
+// Projection keeps the denormalized copies of the fields of the related objects in sync, like the
+// name of the author stored on the books as `author_name`, so the hot list endpoints don't need
+// joins. The Target objects reference the Source objects using the foreign key field, the copies
+// are populated when the Target objects are written, see Populate, and refreshed when the Source
+// objects change, see Refresh.
+type Projection[Target any, Source any] struct {
+	target     queries.Driver[Target]
+	source     queries.Driver[Source]
+	foreignKey string
+	// fields maps the Target fields to the Source fields they copy
+	fields map[string]string
+}
+
+// NewProjection creates a Projection without fields, the foreign key is the Target field holding
+// the ID of the Source object
+func NewProjection[Target any, Source any](
+	target queries.Driver[Target], source queries.Driver[Source], foreignKey string,
+) *Projection[Target, Source] {
+	mustHaveField[Target](foreignKey)
+	return &Projection[Target, Source]{
+		target: target, source: source, foreignKey: foreignKey, fields: map[string]string{},
+	}
+}
+
+// WithField copies the Source field to the Target field, like WithField("author_name", "name")
+func (p *Projection[Target, Source]) WithField(targetField, sourceField string) *Projection[Target, Source] {
+	mustHaveField[Target](targetField)
+	mustHaveField[Source](sourceField)
+	p.fields[targetField] = sourceField
+	return p
+}
+
+// Populate is the middleware of the Target viewset, copying the fields of the referenced Source
+// object to the created and updated objects. References to missing objects are rejected with a
+// validation error of the foreign key, null references clear the copies. The copied fields should
+// be read-only in the serializer of the viewset.
+func (p *Projection[Target, Source]) Populate() Middleware {
+	return func(next OperationFunc) OperationFunc {
+		return func(op *Operation) (*OperationResult, error) {
+			if op.Kind != OperationCreate && op.Kind != OperationUpdate {
+				return next(op)
+			}
+			source, sourceErr := p.retrieveSource(op.Ctx, op.InternalValue[p.foreignKey])
+			if sourceErr != nil {
+				return nil, sourceErr
+			}
+			op.InternalValue = op.InternalValue.Clone()
+			p.copyFields(op.InternalValue, source)
+			return next(op)
+		}
+	}
+}
+
+// Refresh is the middleware of the Source viewset, refreshing the copies stored in the Target
+// objects after the Source objects are updated. The Source objects are already stored when the
+// copies are refreshed, so failures are only logged, RefreshSource can be used to retry them.
+func (p *Projection[Target, Source]) Refresh() Middleware {
+	return func(next OperationFunc) OperationFunc {
+		return func(op *Operation) (*OperationResult, error) {
+			result, err := next(op)
+			if err != nil || op.Kind != OperationUpdate || !p.changed(op.OldInternalValue, result.InternalValue, true) {
+				return result, err
+			}
+			if refreshErr := p.RefreshSource(op.Ctx, result.InternalValue); refreshErr != nil {
+				logrus.Errorf("Failed to refresh the projection of %s: %s", op.ModelName, refreshErr)
+			}
+			return result, nil
+		}
+	}
+}
+
+// RefreshSource updates the copies stored in the Target objects referencing the Source object, for
+// example when the Source objects are changed outside of the viewsets, like by the consumers of a
+// message queue
+func (p *Projection[Target, Source]) RefreshSource(ctx *gin.Context, source models.InternalValue) error {
+	listCtx := detachedContext(ctx, p.target)
+	grfctx.AddPredicates(listCtx, grfctx.Predicate{Field: p.foreignKey, Operator: grfctx.OperatorExact, Value: source["id"]})
+	targets, listErr := p.target.CRUD().List(listCtx)
+	if listErr != nil {
+		return fmt.Errorf("could not list the objects referencing %v: %w", source["id"], listErr)
+	}
+	errs := []error{}
+	for _, target := range targets {
+		refreshed := target.Clone()
+		p.copyFields(refreshed, source)
+		if !p.changed(target, refreshed, false) {
+			continue
+		}
+		if _, updateErr := p.target.CRUD().Update(detachedContext(ctx, p.target), target, refreshed, target["id"]); updateErr != nil {
+			errs = append(errs, fmt.Errorf("could not refresh %v: %w", target["id"], updateErr))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Projection[Target, Source]) retrieveSource(ctx *gin.Context, key any) (models.InternalValue, error) {
+	if key == nil {
+		return nil, nil
+	}
+	source, retrieveErr := p.source.CRUD().Retrieve(detachedContext(ctx, p.source), key)
+	if errors.Is(retrieveErr, common.ErrorNotFound) {
+		return nil, &serializers.ValidationError{FieldErrors: map[string][]string{
+			p.foreignKey: {fmt.Sprintf("Object with id `%v` does not exist", key)},
+		}}
+	}
+	return source, retrieveErr
+}
+
+// copyFields copies the fields of the source to the target, a nil source clears them
+func (p *Projection[Target, Source]) copyFields(target, source models.InternalValue) {
+	for targetField, sourceField := range p.fields {
+		target[targetField] = source[sourceField]
+	}
+}
+
+// changed checks if any of the projected fields differ, the fields of the Source objects are compared
+// if compareSource is true, the fields of the Target objects otherwise
+func (p *Projection[Target, Source]) changed(old, current models.InternalValue, compareSource bool) bool {
+	for targetField, sourceField := range p.fields {
+		field := targetField
+		if compareSource {
+			field = sourceField
+		}
+		if !reflect.DeepEqual(old[field], current[field]) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustHaveField[Model any](field string) {
+	var m Model
+	if _, ok := models.AsInternalValue(m)[field]; !ok {
+		logrus.Panicf("Model `%T` does not have the `%s` field", m, field)
+	}
+}
@@ -0,0 +1,53 @@
+package views
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+type projectedAuthor struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type projectedBook struct {
+	ID         uint   `json:"id"`
+	AuthorID   uint   `json:"author_id"`
+	AuthorName string `json:"author_name"`
+	Title      string `json:"title"`
+}
+
+func TestProjection(t *testing.T) {
+	// given
+	authors := queries.InMemory[projectedAuthor](projectedAuthor{Name: "Ursula"}, projectedAuthor{Name: "Terry"})
+	books := queries.InMemory[projectedBook]()
+	projection := NewProjection[projectedBook, projectedAuthor](books, authors, "author_id").WithField("author_name", "name")
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	NewModelViewSet[projectedAuthor]("/authors", authors).WithMiddleware(projection.Refresh()).Register(r)
+	NewModelViewSet[projectedBook]("/books", books).WithSerializer(
+		serializers.NewModelSerializer[projectedBook]().ReadOnlyFields("author_name"),
+	).WithMiddleware(projection.Populate()).Register(r)
+
+	// when
+	created := quickReq(r, quickReqParams{method: "POST", path: "/books", body: strBody(`{"author_id": 1, "title": "Earthsea", "author_name": "x"}`)})
+	moved := quickReq(r, quickReqParams{method: "PATCH", path: "/books/1", body: strBody(`{"author_id": 2}`)})
+	missing := quickReq(r, quickReqParams{method: "POST", path: "/books", body: strBody(`{"author_id": 7, "title": "Mort"}`)})
+	renamed := quickReq(r, quickReqParams{method: "PATCH", path: "/authors/2", body: strBody(`{"name": "Sir Terry"}`)})
+	refreshed := quickReq(r, quickReqParams{method: "GET", path: "/books/1", body: noBody})
+
+	// then
+	assert.Equal(t, 201, created.Code)
+	assert.JSONEq(t, `{"id": 1, "author_id": 1, "author_name": "Ursula", "title": "Earthsea"}`, created.Body.String())
+	assert.Equal(t, 200, moved.Code)
+	assert.JSONEq(t, `{"id": 1, "author_id": 2, "author_name": "Terry", "title": "Earthsea"}`, moved.Body.String())
+	assert.Equal(t, 400, missing.Code)
+	assert.JSONEq(t, `{"errors": {"author_id": ["Object with id `+"`7`"+` does not exist"]}}`, missing.Body.String())
+	assert.Equal(t, 200, renamed.Code)
+	assert.JSONEq(t, `{"id": 1, "author_id": 2, "author_name": "Sir Terry", "title": "Earthsea"}`, refreshed.Body.String())
+	assert.Panics(t, func() { projection.WithField("author_name", "surname") })
+}
@@ -21,6 +21,6 @@ func RetrieveModelViewSetFunc[Model any](idf IDFunc, qd queries.Driver[Model], s
 			WriteError(ctx, toRawErr)
 			return
 		}
-		ctx.JSON(http.StatusOK, formattedElement)
+		render(ctx, http.StatusOK, formattedElement)
 	}
 }
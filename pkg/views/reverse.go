@@ -0,0 +1,167 @@
+package views
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// URLs is the registry of the paths of the registered viewsets, used to build the links to the
+// objects, for example by serializers.HyperlinkedModelSerializer
+var URLs = NewURLRegistry()
+
+type registeredRoute struct {
+	detailPath string
+	idParam    string
+}
+
+// URLRegistry maps the names of the viewsets to their paths, so the URLs of the objects can be built
+// from their IDs and the IDs can be extracted from the URLs
+type URLRegistry struct {
+	mu     sync.RWMutex
+	routes map[string]registeredRoute
+}
+
+// NewURLRegistry creates an empty URLRegistry
+func NewURLRegistry() *URLRegistry {
+	return &URLRegistry{routes: map[string]registeredRoute{}}
+}
+
+// Add registers the detail path of the viewset, like `/api/products/:product_id`, under the name.
+// It panics if another path is already registered under the name, the viewsets of the same model
+// need different names, see ViewSet.WithName. Registering the same path again does nothing.
+func (r *URLRegistry) Add(name, detailPath, idParam string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	route := registeredRoute{detailPath: detailPath, idParam: idParam}
+	if registered, exists := r.routes[name]; exists && registered != route {
+		logrus.Panicf(
+			"Could not register `%s` under the name `%s`, it's already taken by `%s`, use WithName to rename the viewset",
+			detailPath, name, registered.detailPath,
+		)
+	}
+	r.routes[name] = route
+}
+
+// Reverse returns the absolute URL of the object with the ID, using the scheme and the host of the
+// request. The other params of the path, like the parents of the nested viewsets, are taken from
+// the request's path.
+func (r *URLRegistry) Reverse(ctx *gin.Context, name string, id any) (string, error) {
+	route, ok := r.route(name)
+	if !ok {
+		return "", fmt.Errorf("no viewset registered under the name `%s`", name)
+	}
+	segments := strings.Split(route.detailPath, "/")
+	for i, segment := range segments {
+		param, isParam := strings.CutPrefix(segment, ":")
+		if !isParam {
+			continue
+		}
+		value := fmt.Sprint(id)
+		if param != route.idParam {
+			value = ctx.Param(param)
+			if value == "" {
+				return "", fmt.Errorf("could not reverse `%s`: the request doesn't have the `%s` param", name, param)
+			}
+		}
+		segments[i] = url.PathEscape(value)
+	}
+	return baseURL(ctx) + strings.Join(segments, "/"), nil
+}
+
+// Resolve returns the ID of the object from its URL, if the path of the URL matches the detail path
+// registered under the name
+func (r *URLRegistry) Resolve(name, rawURL string) (string, error) {
+	route, ok := r.route(name)
+	if !ok {
+		return "", fmt.Errorf("no viewset registered under the name `%s`", name)
+	}
+	parsed, parseErr := url.Parse(rawURL)
+	if parseErr != nil {
+		return "", fmt.Errorf("invalid URL: %w", parseErr)
+	}
+	expected := strings.Split(route.detailPath, "/")
+	actual := strings.Split(strings.TrimSuffix(parsed.EscapedPath(), "/"), "/")
+	if len(expected) != len(actual) {
+		return "", fmt.Errorf("URL `%s` does not point to `%s`", rawURL, name)
+	}
+	var id string
+	for i, segment := range expected {
+		param, isParam := strings.CutPrefix(segment, ":")
+		if !isParam {
+			if segment != actual[i] {
+				return "", fmt.Errorf("URL `%s` does not point to `%s`", rawURL, name)
+			}
+			continue
+		}
+		if param == route.idParam {
+			unescaped, unescapeErr := url.PathUnescape(actual[i])
+			if unescapeErr != nil || unescaped == "" {
+				return "", fmt.Errorf("URL `%s` does not point to `%s`", rawURL, name)
+			}
+			id = unescaped
+		}
+	}
+	return id, nil
+}
+
+func (r *URLRegistry) route(name string) (registeredRoute, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	route, ok := r.routes[name]
+	return route, ok
+}
+
+// baseURL returns the scheme and the host of the request, honoring the X-Forwarded-Proto header set
+// by the proxies terminating TLS, if they're trusted by the gin engine, see
+// gin.Engine.SetTrustedProxies
+func baseURL(ctx *gin.Context) string {
+	if ctx == nil || ctx.Request == nil || ctx.Request.Host == "" {
+		return ""
+	}
+	scheme := "http"
+	if ctx.Request.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := ctx.GetHeader("X-Forwarded-Proto"); forwarded != "" && forwardedByTrustedProxy(ctx) {
+		scheme = forwarded
+	}
+	return scheme + "://" + ctx.Request.Host
+}
+
+// probeIP is a reserved address, see RFC 5737, that the clients never connect from
+const probeIP = "192.0.2.1"
+
+// forwardedByTrustedProxy checks if the request was sent by a proxy trusted by the gin engine. Gin
+// doesn't expose the check, so the client IP of a copy of the request, forwarded for the probe
+// address, is compared with it: gin only honors the forwarded addresses of the trusted proxies.
+func forwardedByTrustedProxy(ctx *gin.Context) bool {
+	probe := ctx.Copy()
+	probe.Request = ctx.Request.Clone(ctx.Request.Context())
+	probe.Request.Header = http.Header{"X-Forwarded-For": {probeIP}, "X-Real-Ip": {probeIP}}
+	return probe.ClientIP() == probeIP
+}
+
+// WithName sets the name, under which the viewset is registered in URLs, the default is the name of
+// the model in lower case, like `product`
+func (v *ViewSet[Model]) WithName(name string) *ViewSet[Model] {
+	v.name = name
+	return v
+}
+
+// registerURL adds the detail path of the viewset, including the path of the router group, to URLs
+func (v *ViewSet[Model]) registerURL(r Router) {
+	name := v.name
+	if name == "" {
+		var m Model
+		name = strings.ToLower(reflect.TypeOf(m).Name())
+	}
+	URLs.Add(name, path.Join(r.BasePath(), v.Path, ":"+v.IDParam), v.IDParam)
+}
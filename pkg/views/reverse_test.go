@@ -0,0 +1,112 @@
+package views
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/stretchr/testify/assert"
+)
+
+type linkedAuthor struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type linkedBook struct {
+	ID       uint   `json:"id"`
+	AuthorID uint   `json:"author_id"`
+	Title    string `json:"title"`
+}
+
+func TestHyperlinkedModelSerializer(t *testing.T) {
+	// given
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	api := r.Group("/api")
+	NewModelViewSet[linkedAuthor]("/authors", queries.InMemory[linkedAuthor](
+		linkedAuthor{Name: "Ursula"},
+		linkedAuthor{Name: "Terry"},
+	)).WithName("author").Register(api)
+	NewModelViewSet[linkedBook]("/books", queries.InMemory[linkedBook](
+		linkedBook{AuthorID: 1, Title: "Earthsea"},
+	)).WithSerializer(
+		serializers.NewHyperlinkedModelSerializer[linkedBook](URLs, "book").WithHyperlinkedRelation("author_id", "author"),
+	).WithName("book").Register(api)
+
+	// when
+	retrieved := quickReq(r, quickReqParams{method: "GET", path: "/api/books/1", body: noBody})
+	created := quickReq(r, quickReqParams{
+		method: "POST", path: "/api/books", body: strBody(`{"author_id": "http://example.com/api/authors/2", "title": "Mort"}`),
+	})
+	wrongResource := quickReq(r, quickReqParams{
+		method: "POST", path: "/api/books", body: strBody(`{"author_id": "/api/books/1", "title": "Mort"}`),
+	})
+
+	// then
+	assert.Equal(t, 200, retrieved.Code)
+	assert.JSONEq(t, `{"id": 1, "url": "/api/books/1", "author_id": "/api/authors/1", "title": "Earthsea"}`, retrieved.Body.String())
+	assert.Equal(t, 201, created.Code)
+	assert.JSONEq(t, `{"id": 2, "url": "/api/books/2", "author_id": "/api/authors/2", "title": "Mort"}`, created.Body.String())
+	assert.Equal(t, 400, wrongResource.Code)
+	assert.Contains(t, wrongResource.Body.String(), "does not point to `author`")
+}
+
+func TestURLRegistryReverse(t *testing.T) {
+	// given
+	registry := NewURLRegistry()
+	registry.Add("photo", "/products/:product_id/photos/:photo_id", "photo_id")
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", "https://shop.example.com/products/7/photos", nil)
+	ctx.Params = gin.Params{{Key: "product_id", Value: "7"}}
+
+	// when
+	reversed, reverseErr := registry.Reverse(ctx, "photo", 3)
+	resolved, resolveErr := registry.Resolve("photo", reversed)
+	_, unknownErr := registry.Reverse(ctx, "unknown", 3)
+
+	// then
+	assert.NoError(t, reverseErr)
+	assert.Equal(t, "https://shop.example.com/products/7/photos/3", reversed)
+	assert.NoError(t, resolveErr)
+	assert.Equal(t, "3", resolved)
+	assert.Error(t, unknownErr)
+}
+
+func TestURLRegistryReverseHonorsForwardedProtoOfTrustedProxies(t *testing.T) {
+	// given
+	registry := NewURLRegistry()
+	registry.Add("product", "/products/:product_id", "product_id")
+	reverse := func(trustedProxies []string) string {
+		ctx, r := gin.CreateTestContext(httptest.NewRecorder())
+		assert.NoError(t, r.SetTrustedProxies(trustedProxies))
+		ctx.Request = httptest.NewRequest("GET", "http://shop.example.com/products", nil)
+		ctx.Request.RemoteAddr = "10.0.0.1:1234"
+		ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+		reversed, _ := registry.Reverse(ctx, "product", 3)
+		return reversed
+	}
+
+	// when
+	trusted := reverse([]string{"10.0.0.0/8"})
+	untrusted := reverse(nil)
+
+	// then
+	assert.Equal(t, "https://shop.example.com/products/3", trusted)
+	assert.Equal(t, "http://shop.example.com/products/3", untrusted)
+}
+
+func TestURLRegistryAddPanicsOnDuplicateNames(t *testing.T) {
+	// given
+	registry := NewURLRegistry()
+	registry.Add("product", "/products/:product_id", "product_id")
+
+	// when
+	same := func() { registry.Add("product", "/products/:product_id", "product_id") }
+	other := func() { registry.Add("product", "/v2/products/:product_id", "product_id") }
+
+	// then
+	assert.NotPanics(t, same)
+	assert.Panics(t, other)
+}
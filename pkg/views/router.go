@@ -0,0 +1,95 @@
+package views
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Router is the router, that the views register their routes on. The views only add routes to it,
+// so they can be mounted on routers other than gin by implementing it, like the ones of the
+// adapters package. Register uses GinRouter, which is the default.
+type Router interface {
+	// Handle registers the handlers of the method and the path, which is relative to BasePath and
+	// has the path params written like in gin, for example `/things/:id`. The handlers are run one
+	// after another, like a gin handlers chain.
+	Handle(method, path string, handlers ...gin.HandlerFunc)
+	// BasePath is the path, that the routes are registered under
+	BasePath() string
+}
+
+type ginRouter struct {
+	r gin.IRouter
+}
+
+// GinRouter returns the Router registering the routes on a gin engine or a group
+func GinRouter(r gin.IRouter) Router {
+	return ginRouter{r: r}
+}
+
+func (g ginRouter) Handle(method, path string, handlers ...gin.HandlerFunc) {
+	g.r.Handle(method, path, handlers...)
+}
+
+func (g ginRouter) BasePath() string {
+	if group, ok := g.r.(interface{ BasePath() string }); ok {
+		return group.BasePath()
+	}
+	return "/"
+}
+
+type prefixedRouter struct {
+	r      Router
+	prefix string
+}
+
+// PrefixedRouter returns the Router registering the routes on r under the prefix, like a gin group
+func PrefixedRouter(r Router, prefix string) Router {
+	return prefixedRouter{r: r, prefix: prefix}
+}
+
+func (p prefixedRouter) Handle(method, path string, handlers ...gin.HandlerFunc) {
+	p.r.Handle(method, JoinPaths(p.prefix, path), handlers...)
+}
+
+func (p prefixedRouter) BasePath() string {
+	return JoinPaths(p.r.BasePath(), p.prefix)
+}
+
+// JoinPaths joins the base path and the relative path of a route like gin does, keeping the
+// trailing slash of the relative path
+func JoinPaths(base, relative string) string {
+	if relative == "" {
+		return base
+	}
+	joined := path.Join(base, relative)
+	if relative[len(relative)-1] == '/' && joined[len(joined)-1] != '/' {
+		return joined + "/"
+	}
+	return joined
+}
+
+// RouteRunner runs the handlers of the routes registered on the routers other than gin. The views
+// read the requests and write the responses using gin.Context, so the runner keeps the routes on a
+// gin engine, which creates the contexts, extracts the path params and runs the handlers of the
+// route, once the other router matched the request to it. The engine doesn't route the requests
+// on its own and no gin middleware is used, the middleware of the other router applies instead.
+type RouteRunner struct {
+	engine *gin.Engine
+}
+
+// NewRouteRunner creates the RouteRunner. The values of the contexts of the requests, like the ones
+// set by the middleware of the other router, can be read from gin.Context.
+func NewRouteRunner() *RouteRunner {
+	engine := gin.New()
+	engine.ContextWithFallback = true
+	return &RouteRunner{engine: engine}
+}
+
+// Add adds the route with the absolute path and returns the handler running its handlers, that
+// the other router has to call for the requests matching the method and the path
+func (r *RouteRunner) Add(method, path string, handlers ...gin.HandlerFunc) http.Handler {
+	r.engine.Handle(method, path, handlers...)
+	return r.engine
+}
@@ -0,0 +1,73 @@
+package views
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingRouter records the paths of the routes registered on it
+type recordingRouter struct {
+	paths []string
+}
+
+func (r *recordingRouter) Handle(method, path string, handlers ...gin.HandlerFunc) {
+	r.paths = append(r.paths, method+" "+path)
+}
+
+func (r *recordingRouter) BasePath() string {
+	return "/"
+}
+
+func TestPrefixedRouter(t *testing.T) {
+	// given
+	recorder := &recordingRouter{}
+	view := &View{path: "/things"}
+	view.Get(func(ctx *gin.Context) {})
+
+	// when
+	view.RegisterOn(PrefixedRouter(recorder, "/api"))
+
+	// then
+	assert.Contains(t, recorder.paths, "GET /api/things")
+	assert.Contains(t, recorder.paths, "HEAD /api/things")
+	assert.Contains(t, recorder.paths, "POST /api/things")
+	assert.Equal(t, "/api/v1", PrefixedRouter(PrefixedRouter(recorder, "/api"), "v1").BasePath())
+}
+
+func TestJoinPaths(t *testing.T) {
+	assert.Equal(t, "/things", JoinPaths("/things", ""))
+	assert.Equal(t, "/api/things/:id", JoinPaths("/api", "things/:id"))
+	assert.Equal(t, "/api/things/", JoinPaths("/api", "/things/"))
+	assert.Equal(t, "/things", JoinPaths("/", "/things"))
+}
+
+type requestContextKey struct{}
+
+func TestRouteRunnerRunsTheHandlersOfTheRoute(t *testing.T) {
+	// given
+	runner := NewRouteRunner()
+	handler := runner.Add(http.MethodGet, "/things/:id",
+		func(ctx *gin.Context) {
+			ctx.Header("X-Middleware", "called")
+			ctx.Next()
+		},
+		func(ctx *gin.Context) {
+			ctx.String(http.StatusOK, "%s %v", ctx.Param("id"), ctx.Value(requestContextKey{}))
+		},
+	)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/things/42", nil)
+
+	// when
+	handler.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), requestContextKey{}, "set by the router")))
+
+	// then
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "called", w.Header().Get("X-Middleware"))
+	assert.Equal(t, "42 set by the router", w.Body.String())
+}
@@ -0,0 +1,127 @@
+package views
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// Routes is the table of the registered viewsets, which can be printed when the application starts,
+// so the misconfigured viewsets are easy to spot in projects with dozens of them
+var Routes = NewRouteTable()
+
+// RouteInfo describes a registered viewset
+type RouteInfo struct {
+	// Path is the path of the list route, including the path of the router group
+	Path    string
+	Model   string
+	Actions []string
+	// Authentication is the type of the authentication, like `*authentication.AnonymousUserAuthentication`
+	Authentication string
+	Throttles      []string
+	// Fields are the external names of the fields of the default serializer, if it describes them
+	Fields []string
+}
+
+// RouteTable collects the descriptions of the registered viewsets
+type RouteTable struct {
+	mu     sync.RWMutex
+	routes []RouteInfo
+}
+
+// NewRouteTable creates an empty RouteTable
+func NewRouteTable() *RouteTable {
+	return &RouteTable{}
+}
+
+// Add adds the description of the viewset
+func (t *RouteTable) Add(route RouteInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.routes = append(t.routes, route)
+}
+
+// All returns the descriptions of the viewsets, ordered by their paths
+func (t *RouteTable) All() []RouteInfo {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	routes := append([]RouteInfo{}, t.routes...)
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].Path < routes[j].Path })
+	return routes
+}
+
+// Print writes the table of the viewsets, with a row per viewset and the columns aligned
+func (t *RouteTable) Print(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "PATH\tMODEL\tACTIONS\tAUTHENTICATION\tTHROTTLES\tFIELDS")
+	for _, route := range t.All() {
+		fmt.Fprintf(
+			table, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			route.Path, route.Model, joinOrDash(route.Actions), orDash(route.Authentication),
+			joinOrDash(route.Throttles), joinOrDash(route.Fields),
+		)
+	}
+	return table.Flush()
+}
+
+func joinOrDash(values []string) string {
+	return orDash(strings.Join(values, ", "))
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// registerRoute adds the description of the viewset to Routes
+func (v *ViewSet[Model]) registerRoute(r Router) {
+	var m Model
+	route := RouteInfo{
+		Path:           path.Join(r.BasePath(), v.Path),
+		Model:          reflect.TypeOf(m).Name(),
+		Authentication: fmt.Sprintf("%T", v.ListCreateView.authenticator),
+		Throttles:      []string{},
+		Fields:         []string{},
+	}
+	for _, action := range []struct {
+		id      ActionID
+		enabled bool
+	}{
+		{ActionList, v.ListAction != nil},
+		{ActionCreate, v.CreateAction != nil},
+		{ActionRetrieve, v.RetrieveAction != nil},
+		{ActionUpdate, v.UpdateAction != nil},
+		{ActionPartialUpdate, v.PartialUpdateAction != nil},
+		{ActionDestroy, v.DestroyAction != nil},
+	} {
+		if action.enabled {
+			route.Actions = append(route.Actions, action.id.String())
+		}
+	}
+	for _, extra := range v.extraActions {
+		path := extra.action.RelativePath
+		if extra.isDetail {
+			path = "/:id" + path
+		}
+		route.Actions = append(route.Actions, extra.action.Method+" "+path)
+	}
+	for _, throttle := range v.throttles {
+		route.Throttles = append(route.Throttles, fmt.Sprintf("%T", throttle))
+	}
+	if describer, ok := v.DefaultSerializer.(serializers.Describer); ok {
+		for fieldName := range describer.Describe() {
+			route.Fields = append(route.Fields, fieldName)
+		}
+		sort.Strings(route.Fields)
+	}
+	Routes.Add(route)
+}
@@ -0,0 +1,66 @@
+package views
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/throttling"
+	"github.com/stretchr/testify/assert"
+)
+
+type routedOrder struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status"`
+}
+
+func TestViewSetRegistersRoute(t *testing.T) {
+	// given
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	NewModelViewSet[routedOrder]("/routed-orders", queries.InMemory[routedOrder]()).
+		WithoutActions(ActionDestroy, ActionPartialUpdate).
+		WithThrottle(throttling.NewRateThrottle(10, time.Minute)).
+		WithExtraAction(NewExtraActionFunc("POST", "/cancel", func(ctx *ActionContext[routedOrder]) error { return nil }), nil, true).
+		Register(r.Group("/api"))
+
+	// when
+	var registered RouteInfo
+	for _, route := range Routes.All() {
+		if route.Path == "/api/routed-orders" {
+			registered = route
+		}
+	}
+
+	// then
+	assert.Equal(t, RouteInfo{
+		Path:           "/api/routed-orders",
+		Model:          "routedOrder",
+		Actions:        []string{"list", "create", "retrieve", "update", "POST /:id/cancel"},
+		Authentication: "*authentication.AnonymousUserAuthentication",
+		Throttles:      []string{"*throttling.RateThrottle"},
+		Fields:         []string{"id", "status"},
+	}, registered)
+}
+
+func TestRouteTablePrint(t *testing.T) {
+	// given
+	table := NewRouteTable()
+	table.Add(RouteInfo{Path: "/products", Model: "Product", Actions: []string{"list"}, Fields: []string{"id", "name"}})
+	table.Add(RouteInfo{Path: "/orders", Model: "Order", Actions: []string{"list", "create"}, Authentication: "*auth.Token"})
+	var out bytes.Buffer
+
+	// when
+	err := table.Print(&out)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, ""+
+		"PATH       MODEL    ACTIONS       AUTHENTICATION  THROTTLES  FIELDS\n"+
+		"/orders    Order    list, create  *auth.Token     -          -\n"+
+		"/products  Product  list          -               -          id, name\n",
+		out.String(),
+	)
+}
@@ -0,0 +1,48 @@
+package views
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/queries/common"
+)
+
+// WithFirstAndLast adds `/first` and `/last` collection routes, returning the first and the last
+// object matching the filters, ordered by the field, descending if prefixed with `-`, for example
+// `-created_at`. The query drivers receive a keyset window of a single object, see
+// grfctx.CurrentWindow, so the GORM driver only fetches one row.
+func (v *ViewSet[Model]) WithFirstAndLast(ordering string) *ViewSet[Model] {
+	field, descending := strings.CutPrefix(ordering, "-")
+	return v.WithExtraAction(NewExtraActionFunc(http.MethodGet, "/first", singleObjectAction[Model](grfctx.Window{
+		Limit: 1, Keyset: &grfctx.Keyset{Field: field, Descending: descending},
+	})), nil, false).WithExtraAction(NewExtraActionFunc(http.MethodGet, "/last", singleObjectAction[Model](grfctx.Window{
+		Limit: 1, Keyset: &grfctx.Keyset{Field: field, Descending: !descending},
+	})), nil, false)
+}
+
+// WithRandom adds a `/random` collection route, returning an object matching the filters picked at
+// random, the GORM driver fetches the row at a random offset
+func (v *ViewSet[Model]) WithRandom() *ViewSet[Model] {
+	return v.WithExtraAction(NewExtraActionFunc(http.MethodGet, "/random", singleObjectAction[Model](grfctx.Window{
+		Limit: 1, Random: true,
+	})), nil, false)
+}
+
+// singleObjectAction responds with the first object of the window, or 404 if there are none. The
+// window is applied to the listed objects again, in case the driver doesn't support windows.
+func singleObjectAction[Model any](window grfctx.Window) ActionFunc[Model] {
+	return func(ctx *ActionContext[Model]) error {
+		ctx.QueryDriver.Filter().Apply(ctx.Context)
+		grfctx.SetWindow(ctx.Context, window)
+		internalValues, listErr := ctx.QueryDriver.CRUD().List(ctx.Context)
+		if listErr != nil {
+			return listErr
+		}
+		internalValues = common.ApplyWindow(internalValues, window)
+		if len(internalValues) == 0 {
+			return common.ErrorNotFound
+		}
+		return ctx.Respond(http.StatusOK, internalValues[0])
+	}
+}
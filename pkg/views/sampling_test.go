@@ -0,0 +1,65 @@
+package views
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirstLastAndRandom(t *testing.T) {
+	tests := []struct {
+		name         string
+		seed         []anotherMockModel
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "first",
+			seed:         seedProducts(3),
+			path:         "/mocks/first",
+			expectedCode: 200,
+			expectedBody: `{"id": 3, "name": "Product", "price": 3}`,
+		},
+		{
+			name:         "last",
+			seed:         seedProducts(3),
+			path:         "/mocks/last",
+			expectedCode: 200,
+			expectedBody: `{"id": 1, "name": "Product", "price": 1}`,
+		},
+		{
+			name:         "random",
+			seed:         seedProducts(1),
+			path:         "/mocks/random",
+			expectedCode: 200,
+			expectedBody: `{"id": 1, "name": "Product", "price": 1}`,
+		},
+		{
+			name:         "empty collection",
+			path:         "/mocks/first",
+			expectedCode: 404,
+			expectedBody: `{"message": "not found"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			viewset := NewModelViewSet[anotherMockModel](
+				"/mocks", queries.InMemory(tt.seed...),
+			).WithFirstAndLast("-price").WithRandom()
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			viewset.Register(r)
+
+			// when
+			response := quickReq(r, quickReqParams{method: "GET", path: tt.path, body: noBody})
+
+			// then
+			assert.Equal(t, tt.expectedCode, response.Code)
+			assert.JSONEq(t, tt.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package views
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/filters"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// WithSparseFields lets the clients select the fields of the list and retrieve responses using the
+// `fields` query parameter, for example `?fields=id,name`. Only the allowed fields can be selected,
+// requesting others is responded with 400 Bad Request, so hidden fields are never exposed. It's a
+// representation hook, so it only sees the fields added by the hooks added before it.
+func (v *ViewSet[Model]) WithSparseFields(allowed ...string) *ViewSet[Model] {
+	return v.WithRepresentationHook(func(ctx *gin.Context, repr serializers.Representation) (serializers.Representation, error) {
+		if ctx.Request.Method != http.MethodGet {
+			return repr, nil
+		}
+		selected, parseErr := filters.ParseFields(ctx.Query(filters.FieldsQueryParam), allowed)
+		if parseErr != nil || selected == nil {
+			return repr, parseErr
+		}
+		sparse := serializers.Representation{}
+		for _, field := range selected {
+			if value, ok := repr[field]; ok {
+				sparse[field] = value
+			}
+		}
+		return sparse, nil
+	})
+}
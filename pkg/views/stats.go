@@ -0,0 +1,77 @@
+package views
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/serializers"
+)
+
+// DefaultStatsPercentiles are computed by the `/stats` route if the request doesn't specify them
+var DefaultStatsPercentiles = []float64{25, 50, 75}
+
+// StatsResponse is the response of the `/stats` route
+type StatsResponse struct {
+	Field string `json:"field"`
+	common.Stats
+}
+
+// WithStats adds a `/stats` collection route, responding with the count, minimum, maximum, average
+// and percentiles of a numeric field of the objects matching the filters, for example to power
+// range slider UIs. Only the passed fields may be used:
+//
+//	GET /products/stats?field=price&percentiles=10,50,90
+//
+// The field parameter may be omitted if there's a single field. The statistics are computed using
+// queries.Aggregator, which the GORM driver implements with aggregate queries. Drivers not
+// implementing it list all the objects instead.
+func (v *ViewSet[Model]) WithStats(fields ...string) *ViewSet[Model] {
+	return v.WithExtraAction(NewExtraActionFunc(http.MethodGet, "/stats", func(ctx *ActionContext[Model]) error {
+		field := ctx.Query("field")
+		if field == "" && len(fields) == 1 {
+			field = fields[0]
+		}
+		if !slices.Contains(fields, field) {
+			return &serializers.ValidationError{FieldErrors: map[string][]string{
+				"field": {"expected one of: " + strings.Join(fields, ", ")},
+			}}
+		}
+		percentiles, parseErr := parsePercentiles(ctx.Query("percentiles"))
+		if parseErr != nil {
+			return parseErr
+		}
+		ctx.QueryDriver.Filter().Apply(ctx.Context)
+		stats, statsErr := queries.Stats(ctx.Context, ctx.QueryDriver, field, percentiles)
+		if errors.Is(statsErr, queries.ErrStatsUnsupported) {
+			internalValues, listErr := ctx.QueryDriver.CRUD().List(ctx.Context)
+			stats, statsErr = common.ComputeStats(common.NumericValues(internalValues, field), percentiles), listErr
+		}
+		if statsErr != nil {
+			return statsErr
+		}
+		render(ctx.Context, http.StatusOK, StatsResponse{Field: field, Stats: stats})
+		return nil
+	}), nil, false)
+}
+
+func parsePercentiles(raw string) ([]float64, error) {
+	if raw == "" {
+		return DefaultStatsPercentiles, nil
+	}
+	percentiles := []float64{}
+	for _, rawPercentile := range strings.Split(raw, ",") {
+		percentile, parseErr := strconv.ParseFloat(strings.TrimSpace(rawPercentile), 64)
+		if parseErr != nil || percentile < 0 || percentile > 100 {
+			return nil, &serializers.ValidationError{FieldErrors: map[string][]string{
+				"percentiles": {"expected comma separated numbers between 0 and 100"},
+			}}
+		}
+		percentiles = append(percentiles, percentile)
+	}
+	return percentiles, nil
+}
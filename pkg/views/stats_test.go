@@ -0,0 +1,80 @@
+package views
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/filters"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "default percentiles",
+			path:         "/mocks/stats?field=price",
+			expectedCode: 200,
+			expectedBody: `{"field": "price", "count": 5, "min": 1, "max": 5, "avg": 3, "percentiles": {"25": 2, "50": 3, "75": 4}}`,
+		},
+		{
+			name:         "interpolated percentiles",
+			path:         "/mocks/stats?field=price&percentiles=10,62.5",
+			expectedCode: 200,
+			expectedBody: `{"field": "price", "count": 5, "min": 1, "max": 5, "avg": 3, "percentiles": {"10": 1.4, "62.5": 3.5}}`,
+		},
+		{
+			name:         "filtered",
+			path:         "/mocks/stats?field=price&price__gte=4",
+			expectedCode: 200,
+			expectedBody: `{"field": "price", "count": 2, "min": 4, "max": 5, "avg": 4.5, "percentiles": {"25": 4.25, "50": 4.5, "75": 4.75}}`,
+		},
+		{
+			name:         "empty",
+			path:         "/mocks/stats?field=price&price__gte=10",
+			expectedCode: 200,
+			expectedBody: `{"field": "price", "count": 0, "min": null, "max": null, "avg": null, "percentiles": {}}`,
+		},
+		{
+			name:         "not allowed field",
+			path:         "/mocks/stats?field=id",
+			expectedCode: 400,
+			expectedBody: `{"errors": {"field": ["expected one of: price"]}}`,
+		},
+		{
+			name:         "invalid percentiles",
+			path:         "/mocks/stats?field=price&percentiles=200",
+			expectedCode: 400,
+			expectedBody: `{"errors": {"percentiles": ["expected comma separated numbers between 0 and 100"]}}`,
+		},
+	}
+	drivers := map[string]queries.Driver[anotherMockModel]{
+		"aggregating driver":          queries.InMemory(seedProducts(5)...),
+		"driver without aggregations": plainDriver[anotherMockModel]{queries.InMemory(seedProducts(5)...)},
+	}
+	for driverName, driver := range drivers {
+		for _, tt := range tests {
+			t.Run(driverName+" "+tt.name, func(t *testing.T) {
+				// given
+				viewset := NewModelViewSet[anotherMockModel]("/mocks", driver).WithStats("price").WithFilterField(
+					"price", filters.Gte,
+				)
+				_, r := gin.CreateTestContext(httptest.NewRecorder())
+				viewset.Register(r)
+
+				// when
+				response := quickReq(r, quickReqParams{method: "GET", path: tt.path, body: noBody})
+
+				// then
+				assert.Equal(t, tt.expectedCode, response.Code)
+				assert.JSONEq(t, tt.expectedBody, response.Body.String())
+			})
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package views
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/glothriel/grf/pkg/authentication"
+	"github.com/sirupsen/logrus"
+)
+
+// StrictCheck is one of the guardrails of the strict mode, see GroupSettings.Strict
+type StrictCheck string
+
+const (
+	// StrictAuthentication requires the views to set an authentication, other than the anonymous
+	// one, themselves or using the API group
+	StrictAuthentication StrictCheck = "authentication"
+	// StrictPermissions requires the views to set the permissions, themselves or using the API
+	// group, the public views declare it using authentication.AllowAny
+	StrictPermissions StrictCheck = "permissions"
+	// StrictPagination requires the viewsets with the list action to paginate it, using
+	// WithPagination or WithMandatoryPagination
+	StrictPagination StrictCheck = "pagination"
+)
+
+// WithoutStrictChecks opts the viewset out of the checks of the strict mode, like the public
+// endpoints opting out of StrictAuthentication
+func (v *ViewSet[Model]) WithoutStrictChecks(checks ...StrictCheck) *ViewSet[Model] {
+	v.strictOptOuts = append(v.strictOptOuts, checks...)
+	return v
+}
+
+// WithoutStrictChecks opts the view out of the checks of the strict mode
+func (v *View) WithoutStrictChecks(checks ...StrictCheck) *View {
+	v.strictOptOuts = append(v.strictOptOuts, checks...)
+	return v
+}
+
+// checkStrict panics if the strict mode is enabled and the viewset fails the checks of its own
+// settings, the checks of the views, including the extra actions, run when they are registered
+func (v *ViewSet[Model]) checkStrict() {
+	if !v.strict {
+		return
+	}
+	if v.ListAction != nil && v.paginator == nil && !v.paginationRequired && !v.driverPaginates() &&
+		!slices.Contains(v.strictOptOuts, StrictPagination) {
+		panicStrict(v.Path, []string{"its list action is not paginated"})
+	}
+}
+
+// checkStrict panics if the strict mode is enabled and the view fails any of the checks, it runs
+// after the group settings are applied
+func (v *View) checkStrict() {
+	if !v.strict {
+		return
+	}
+	violations := []string{}
+	if _, anonymous := v.authenticator.(*authentication.AnonymousUserAuthentication); (v.authenticator == nil || anonymous) &&
+		!slices.Contains(v.strictOptOuts, StrictAuthentication) {
+		violations = append(violations, "it does not set the authentication")
+	}
+	if len(v.permissions) == 0 && !slices.Contains(v.strictOptOuts, StrictPermissions) {
+		violations = append(violations, "it does not set the permissions")
+	}
+	if len(violations) > 0 {
+		panicStrict(v.path, violations)
+	}
+}
+
+func panicStrict(path string, violations []string) {
+	logrus.Panicf(
+		"Strict mode forbids registering `%s`: %s, use WithoutStrictChecks to opt out deliberately",
+		path, strings.Join(violations, " and "),
+	)
+}
@@ -0,0 +1,95 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/models"
+	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/sirupsen/logrus"
+)
+
+// WithUniqueValidator rejects the created and updated objects with the same value of any of the
+// fields as another stored object, like the email of the user, with a validation error of the
+// field, instead of failing on the unique constraint of the database. The fields are checked
+// separately using serializers.UniqueValidator, the objects are looked up using the query driver of
+// the viewset, ignoring the filters and the parent scope, like the database constraints. Concurrent
+// requests can still pass the check, so the constraints are still needed.
+func (v *ViewSet[Model]) WithUniqueValidator(fieldNames ...string) *ViewSet[Model] {
+	for _, fieldName := range fieldNames {
+		mustHaveField[Model](fieldName)
+	}
+	validators := make([]serializers.UniqueValidator, 0, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		validators = append(validators, serializers.UniqueValidator{Fields: []string{fieldName}})
+	}
+	return v.withUniqueValidators(validators)
+}
+
+// WithUniqueTogetherValidator rejects the created and updated objects with the same combination of
+// the values of the fields as another stored object, like WithUniqueTogetherValidator("tenant_id",
+// "slug"), with the errors of each of the fields. The partial updates are checked using the values
+// merged with the stored object, the objects are looked up like in WithUniqueValidator.
+func (v *ViewSet[Model]) WithUniqueTogetherValidator(fieldNames ...string) *ViewSet[Model] {
+	if len(fieldNames) < 2 {
+		logrus.Panicf("Unique together validator needs at least two fields, got %v", fieldNames)
+	}
+	for _, fieldName := range fieldNames {
+		mustHaveField[Model](fieldName)
+	}
+	return v.withUniqueValidators([]serializers.UniqueValidator{{Fields: fieldNames}})
+}
+
+// withUniqueValidators checks the created and updated objects using the validators, merging their
+// errors
+func (v *ViewSet[Model]) withUniqueValidators(validators []serializers.UniqueValidator) *ViewSet[Model] {
+	return v.WithMiddleware(func(next OperationFunc) OperationFunc {
+		return func(op *Operation) (*OperationResult, error) {
+			if op.Kind != OperationCreate && op.Kind != OperationUpdate {
+				return next(op)
+			}
+			fieldErrors := map[string][]string{}
+			for _, validator := range validators {
+				validateErr := validator.Validate(op.InternalValue, op.OldInternalValue, func(values map[string]any) (bool, error) {
+					return v.valueTaken(op.Ctx, values, op.OldInternalValue)
+				})
+				if validateErr == nil {
+					continue
+				}
+				var validationErr *serializers.ValidationError
+				if !errors.As(validateErr, &validationErr) {
+					return nil, validateErr
+				}
+				for field, messages := range validationErr.FieldErrors {
+					fieldErrors[field] = append(fieldErrors[field], messages...)
+				}
+			}
+			if len(fieldErrors) > 0 {
+				return nil, &serializers.ValidationError{FieldErrors: fieldErrors}
+			}
+			return next(op)
+		}
+	})
+}
+
+// valueTaken checks if any object other than the current one has the values of the fields
+func (v *ViewSet[Model]) valueTaken(ctx *gin.Context, values map[string]any, current models.InternalValue) (bool, error) {
+	lookupCtx := detachedContext(ctx, v.QueryDriver)
+	for fieldName, value := range values {
+		grfctx.AddPredicates(lookupCtx, grfctx.Predicate{Field: fieldName, Operator: grfctx.OperatorExact, Value: value})
+	}
+	// The current object and one other are enough
+	grfctx.SetWindow(lookupCtx, grfctx.Window{Limit: 2})
+	matches, listErr := v.QueryDriver.CRUD().List(lookupCtx)
+	if listErr != nil {
+		return false, listErr
+	}
+	for _, match := range matches {
+		if current == nil || fmt.Sprint(match["id"]) != fmt.Sprint(current["id"]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
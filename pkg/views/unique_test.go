@@ -0,0 +1,88 @@
+package views
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+type uniqueUser struct {
+	ID       uint   `json:"id"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+func TestViewSetWithUniqueValidator(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[uniqueUser]("/users", queries.InMemory[uniqueUser](
+		uniqueUser{Email: "alice@example.com", Username: "alice"},
+		uniqueUser{Email: "bob@example.com", Username: "bob"},
+	)).WithUniqueValidator("email", "username")
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	duplicated := quickReq(r, quickReqParams{
+		method: "POST", path: "/users", body: strBody(`{"email": "alice@example.com", "username": "bob"}`),
+	})
+	created := quickReq(r, quickReqParams{
+		method: "POST", path: "/users", body: strBody(`{"email": "carol@example.com", "username": "carol"}`),
+	})
+	unchanged := quickReq(r, quickReqParams{
+		method: "PUT", path: "/users/1", body: strBody(`{"email": "alice@example.com", "username": "alice"}`),
+	})
+	taken := quickReq(r, quickReqParams{method: "PATCH", path: "/users/1", body: strBody(`{"email": "bob@example.com"}`)})
+
+	// then
+	assert.Equal(t, 400, duplicated.Code)
+	assert.JSONEq(t, `{"errors": {
+		"email": ["this value is already used"],
+		"username": ["this value is already used"]
+	}}`, duplicated.Body.String())
+	assert.Equal(t, 201, created.Code)
+	assert.Equal(t, 200, unchanged.Code)
+	assert.Equal(t, 400, taken.Code)
+	assert.Panics(t, func() { viewset.WithUniqueValidator("phone") })
+}
+
+type uniqueArticle struct {
+	ID       uint   `json:"id"`
+	TenantID uint   `json:"tenant_id"`
+	Slug     string `json:"slug"`
+	Title    string `json:"title"`
+}
+
+func TestViewSetWithUniqueTogetherValidator(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[uniqueArticle]("/articles", queries.InMemory[uniqueArticle](
+		uniqueArticle{TenantID: 1, Slug: "intro"},
+		uniqueArticle{TenantID: 1, Slug: "outro"},
+	)).WithUniqueTogetherValidator("tenant_id", "slug")
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	duplicated := quickReq(r, quickReqParams{
+		method: "POST", path: "/articles", body: strBody(`{"tenant_id": 1, "slug": "intro"}`),
+	})
+	otherTenant := quickReq(r, quickReqParams{
+		method: "POST", path: "/articles", body: strBody(`{"tenant_id": 2, "slug": "intro"}`),
+	})
+	unchanged := quickReq(r, quickReqParams{method: "PATCH", path: "/articles/1", body: strBody(`{"title": "Intro"}`)})
+	taken := quickReq(r, quickReqParams{method: "PATCH", path: "/articles/2", body: strBody(`{"slug": "intro"}`)})
+
+	// then
+	assert.Equal(t, 400, duplicated.Code)
+	assert.JSONEq(t, `{"errors": {
+		"tenant_id": ["the combination of tenant_id, slug is already used"],
+		"slug": ["the combination of tenant_id, slug is already used"]
+	}}`, duplicated.Body.String())
+	assert.Equal(t, 201, otherTenant.Code)
+	assert.Equal(t, 200, unchanged.Code)
+	assert.Equal(t, 400, taken.Code)
+	assert.Panics(t, func() { viewset.WithUniqueTogetherValidator("slug") })
+	assert.Panics(t, func() { viewset.WithUniqueTogetherValidator("tenant_id", "name") })
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/grfctx"
 	"github.com/glothriel/grf/pkg/models"
 	"github.com/glothriel/grf/pkg/queries"
 	"github.com/glothriel/grf/pkg/serializers"
@@ -16,40 +17,41 @@ import (
 func UpdateModelViewSetFunc[Model any](idf IDFunc, qd queries.Driver[Model], serializer serializers.Serializer) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var parsedBody map[string]any
-		if parseErr := ctx.ShouldBindJSON(&parsedBody); parseErr != nil {
+		if parseErr := parseBody(ctx, &parsedBody); parseErr != nil {
 			WriteError(ctx, parseErr)
 			return
 		}
 
-		updates, idEnrichErr := enrichBodyWithID[Model](ctx, hasNumericID[Model](), idf, parsedBody)
+		// the models looked up by other fields may have no id at all, so its type is only checked
+		// when the URL holds it
+		isNumeric := grfctx.LookupField(ctx) == "id" && hasNumericID[Model]()
+		updates, idEnrichErr := enrichBodyWithID[Model](ctx, isNumeric, idf, parsedBody)
 		if idEnrichErr != nil {
 			WriteError(ctx, idEnrichErr)
 			return
 		}
 
 		effectiveSerializer := serializer
-		incomingIntVal, fromRawErr := effectiveSerializer.ToInternalValue(updates, ctx)
-		if fromRawErr != nil {
-			WriteError(ctx, fromRawErr)
-			return
-		}
 		oldIntVal, oldErr := qd.CRUD().Retrieve(ctx, idf(ctx))
 		if oldErr != nil {
 			WriteError(ctx, oldErr)
 			return
 		}
-		newIntVal := models.InternalValue{}
-		for k, v := range oldIntVal {
-			newIntVal[k] = v
-		}
-		for k, v := range incomingIntVal {
-			newIntVal[k] = v
+		grfctx.SetStored(ctx, oldIntVal)
+		grfctx.DeferUntilSaved(ctx)
+		incomingIntVal, fromRawErr := effectiveSerializer.ToInternalValue(updates, ctx)
+		if fromRawErr != nil {
+			WriteError(ctx, fromRawErr)
+			return
 		}
-		updatedIntVal, updateErr := qd.CRUD().Update(
-			ctx, oldIntVal, newIntVal, idf(ctx),
-		)
-		if updateErr != nil {
-			WriteError(ctx, updateErr)
+		newIntVal := oldIntVal.Merge(incomingIntVal, models.MergeReplace)
+		var updatedIntVal models.InternalValue
+		if saveErr := saveWithCallbacks(ctx, qd, func() error {
+			var updateErr error
+			updatedIntVal, updateErr = qd.CRUD().Update(ctx, oldIntVal, newIntVal, idf(ctx))
+			return updateErr
+		}); saveErr != nil {
+			WriteError(ctx, saveErr)
 			return
 		}
 		rawElement, toRawErr := effectiveSerializer.ToRepresentation(updatedIntVal, ctx)
@@ -57,11 +59,21 @@ func UpdateModelViewSetFunc[Model any](idf IDFunc, qd queries.Driver[Model], ser
 			WriteError(ctx, toRawErr)
 			return
 		}
-		ctx.JSON(http.StatusOK, rawElement)
+		render(ctx, http.StatusOK, rawElement)
 	}
 }
 
+// PartialUpdateModelViewSetFunc handles PATCH requests. The fields missing in the request body are
+// kept unchanged, as the incoming internal value is merged with the stored one.
+func PartialUpdateModelViewSetFunc[Model any](idf IDFunc, qd queries.Driver[Model], serializer serializers.Serializer) gin.HandlerFunc {
+	return UpdateModelViewSetFunc[Model](idf, qd, serializer)
+}
+
 func enrichBodyWithID[Model any](ctx *gin.Context, isNumeric bool, idf IDFunc, b map[string]any) (map[string]any, error) {
+	if grfctx.LookupField(ctx) != "id" {
+		// The URL doesn't hold the ID, the ID of the stored object is kept by the merge
+		return b, nil
+	}
 	idFromURLStr := idf(ctx)
 	if !isNumeric {
 		if idFromBody, ok := b["id"]; ok {
@@ -1,9 +1,15 @@
 package views
 
 import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/glothriel/grf/pkg/authentication"
 	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/throttling"
 )
 
 type ViewRoute struct {
@@ -21,6 +27,17 @@ type View struct {
 	patchHandler  func(*gin.Context)
 	extraRoutes   []*ViewRoute
 	authenticator authentication.Authentication
+	// authenticationSet is false for the anonymous authentication set by default, so the view uses
+	// the authentication of its API group
+	authenticationSet bool
+	permissions       []authentication.Permission
+	throttles         []throttling.Throttle
+	throttleQueue     *throttling.Queue
+	errorFormat       *ErrorFormat
+	parsers           []Parser
+	renderers         []Renderer
+	strict            bool
+	strictOptOuts     []StrictCheck
 
 	middleware []gin.HandlerFunc
 }
@@ -62,26 +79,260 @@ func (v *View) AddMiddleware(m ...gin.HandlerFunc) *View {
 	return v
 }
 
+// WithAuthentication sets the authentication used by the view, by default all the users are anonymous
+func (v *View) WithAuthentication(a authentication.Authentication) *View {
+	v.authenticator = a
+	v.authenticationSet = true
+	return v
+}
+
+// WithPermissions requires the authenticated requests to have all the permissions, the other ones
+// are responded with 403
+func (v *View) WithPermissions(permissions ...authentication.Permission) *View {
+	v.permissions = permissions
+	return v
+}
+
+// WithThrottle limits the rate of the requests to the view, replacing the previously set throttles
+func (v *View) WithThrottle(throttles ...throttling.Throttle) *View {
+	v.throttles = throttles
+	return v
+}
+
+// WithThrottleQueue makes throttled write requests wait in the queue until the throttles allow
+// them, instead of rejecting them immediately
+func (v *View) WithThrottleQueue(queue *throttling.Queue) *View {
+	v.throttleQueue = queue
+	return v
+}
+
+// ApplyGroupSettings applies the settings shared by the API group, see grf.NewAPIGroup. The
+// authentication, the permissions and the throttles configured on the view itself take precedence,
+// the group's middleware only applies to the viewsets.
+func (v *View) ApplyGroupSettings(settings GroupSettings) {
+	if !v.authenticationSet && settings.Authentication != nil {
+		v.authenticator = settings.Authentication
+	}
+	if v.permissions == nil {
+		v.permissions = settings.Permissions
+	}
+	if v.throttles == nil {
+		v.throttles = settings.Throttles
+	}
+	if v.throttleQueue == nil {
+		v.throttleQueue = settings.ThrottleQueue
+	}
+	v.strict = v.strict || settings.Strict
+}
+
 func (v *View) Register(r gin.IRouter) {
-	rg := r.Group(v.path, v.middleware...)
-	if v.getHandler != nil {
-		rg.GET("", v.getHandler)
+	v.RegisterOn(GinRouter(r))
+}
+
+// RegisterOn registers the view's routes on any Router, for example the ones of the adapters
+// package, mounting the view on routers other than gin
+func (v *View) RegisterOn(r Router) {
+	v.register(r, nil)
+}
+
+// register registers the view's routes along with the given extra routes, which aren't kept on the
+// view so it can be registered more than once
+func (v *View) register(r Router, extraRoutes []*ViewRoute) {
+	v.checkStrict()
+	handlers := []gin.HandlerFunc{}
+	if v.parsers != nil || v.renderers != nil {
+		handlers = append(handlers, mediaTypesMiddleware(v.parsers, v.renderers))
+	}
+	if v.errorFormat != nil {
+		handlers = append(handlers, errorFormatMiddleware(*v.errorFormat))
 	}
-	if v.postHandler != nil {
-		rg.POST("", v.postHandler)
+	// The unhandled methods are answered before the authentication and the throttles, so they
+	// aren't counted nor rejected by them
+	unhandled := slices.Clip(handlers)
+	handlers = append(handlers, authenticationMiddleware(v.authenticator))
+	if len(v.permissions) > 0 {
+		handlers = append(handlers, permissionsMiddleware(v.permissions))
 	}
-	if v.putHandler != nil {
-		rg.PUT("", v.putHandler)
+	if len(v.throttles) > 0 {
+		handlers = append(handlers, throttlingMiddleware(v.throttles, v.throttleQueue))
 	}
-	if v.deleteHandler != nil {
-		rg.DELETE("", v.deleteHandler)
+	handlers = slices.Clip(append(handlers, v.middleware...))
+	routes := []*ViewRoute{
+		{Method: http.MethodGet, Handler: v.getHandler},
+		{Method: http.MethodPost, Handler: v.postHandler},
+		{Method: http.MethodPut, Handler: v.putHandler},
+		{Method: http.MethodDelete, Handler: v.deleteHandler},
+		{Method: http.MethodPatch, Handler: v.patchHandler},
 	}
-	if v.patchHandler != nil {
-		rg.PATCH("", v.patchHandler)
+	allowed := map[string][]string{}
+	getHandlers := map[string]gin.HandlerFunc{}
+	paths := []string{}
+	routes = append(routes, v.extraRoutes...)
+	for _, route := range append(routes, extraRoutes...) {
+		if route.Handler == nil {
+			continue
+		}
+		r.Handle(route.Method, JoinPaths(v.path, route.RelativePath), append(handlers, route.Handler)...)
+		if _, ok := allowed[route.RelativePath]; !ok {
+			paths = append(paths, route.RelativePath)
+		}
+		allowed[route.RelativePath] = append(allowed[route.RelativePath], route.Method)
+		if route.Method == http.MethodGet {
+			getHandlers[route.RelativePath] = route.Handler
+		}
 	}
-	for _, extraAction := range v.extraRoutes {
-		rg.Handle(extraAction.Method, extraAction.RelativePath, extraAction.Handler)
+	for _, path := range paths {
+		// HEAD requests are answered like GET ones, unless the view handles them on its own, the
+		// server discards the body
+		if getHandler, ok := getHandlers[path]; ok && !slices.Contains(allowed[path], http.MethodHead) {
+			r.Handle(http.MethodHead, JoinPaths(v.path, path), append(handlers, getHandler)...)
+			allowed[path] = slices.Insert(allowed[path], slices.Index(allowed[path], http.MethodGet)+1, http.MethodHead)
+		}
+		// Other methods are answered with 405 instead of gin's default 404
+		handler := methodNotAllowedHandler(allowed[path])
+		for _, method := range routedMethods {
+			if !slices.Contains(allowed[path], method) {
+				r.Handle(method, JoinPaths(v.path, path), append(unhandled, handler)...)
+			}
+		}
+	}
+}
+
+// routedMethods are the methods answered with 405 if the view doesn't handle them
+var routedMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+func methodNotAllowedHandler(allowed []string) gin.HandlerFunc {
+	allow := strings.Join(allowed, ", ")
+	return func(ctx *gin.Context) {
+		ctx.Header("Allow", allow)
+		WriteError(ctx, ErrMethodNotAllowed)
+	}
+}
+
+func authenticationMiddleware(a authentication.Authentication) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authenticated, err := a.Authenticate(ctx)
+		if err != nil || !authenticated {
+			WriteError(ctx, ErrNotAuthenticated)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func permissionsMiddleware(permissions []authentication.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		for _, permission := range permissions {
+			if !permission.HasPermission(ctx) {
+				WriteError(ctx, ErrPermissionDenied)
+				ctx.Abort()
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
+
+// throttlingMiddleware rejects throttled requests, or queues them if they are writes and the
+// queue is set. The quota of the most restrictive throttle is exposed in the rate limit headers of
+// every response, so clients can self-regulate.
+func throttlingMiddleware(throttles []throttling.Throttle, queue *throttling.Queue) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		allowed, quota := checkThrottles(ctx, throttles)
+		if !allowed && queue != nil && isWriteMethod(ctx.Request.Method) {
+			var resetAt time.Time
+			if quota != nil && quota.Remaining == 0 {
+				resetAt = quota.Reset
+			}
+			allowed = queue.Wait(ctx.Request.Context(), resetAt, func() (bool, time.Time) {
+				allowed, quota = checkThrottles(ctx, throttles)
+				if quota != nil && quota.Remaining == 0 {
+					return allowed, quota.Reset
+				}
+				return allowed, time.Time{}
+			})
+		}
+		if quota != nil {
+			quota.SetHeaders(ctx.Writer.Header(), time.Now())
+		}
+		if !allowed {
+			if quota != nil && quota.Remaining == 0 {
+				ctx.Header("Retry-After", ctx.Writer.Header().Get("RateLimit-Reset"))
+			}
+			WriteError(ctx, ErrThrottled)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// checkThrottles returns whether the request is allowed by all the throttles and the quota of the
+// most restrictive one. The throttles implementing throttling.PeekingThrottle are checked first,
+// without counting the request, so it consumes their quota only if all of them allow it. The other
+// throttles can't be checked without counting the request, they count it before the peeking ones.
+func checkThrottles(ctx *gin.Context, throttles []throttling.Throttle) (bool, *throttling.Quota) {
+	var quota *throttling.Quota
+	report := func(current throttling.Quota) {
+		if quota == nil || current.Remaining < quota.Remaining {
+			quota = &current
+		}
+	}
+	for _, throttle := range throttles {
+		if peeking, ok := throttle.(throttling.PeekingThrottle); ok {
+			allowed, current := peeking.Peek(ctx)
+			report(current)
+			if !allowed {
+				return false, quota
+			}
+		}
+	}
+	quota = nil
+	var peeking []throttling.QuotaThrottle
+	for _, throttle := range throttles {
+		if peekingThrottle, ok := throttle.(throttling.PeekingThrottle); ok {
+			peeking = append(peeking, peekingThrottle)
+			continue
+		}
+		quotaThrottle, reportsQuota := throttle.(throttling.QuotaThrottle)
+		if !reportsQuota {
+			if !throttle.Allow(ctx) {
+				return false, quota
+			}
+			continue
+		}
+		allowed, current := quotaThrottle.AllowWithQuota(ctx)
+		report(current)
+		if !allowed {
+			return false, quota
+		}
+	}
+	for _, throttle := range peeking {
+		// the quota may have been consumed by the concurrent requests since it was peeked
+		allowed, current := throttle.AllowWithQuota(ctx)
+		report(current)
+		if !allowed {
+			return false, quota
+		}
+	}
+	return true, quota
+}
+
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
 	}
+	return false
 }
 
 func NewView[Model any](path string, queryDriver queries.Driver[Model]) *View {
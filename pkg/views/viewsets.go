@@ -2,30 +2,58 @@ package views
 
 import (
 	"fmt"
+	"net/http"
 	"path"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/authentication"
+	"github.com/glothriel/grf/pkg/filters"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/locale"
+	"github.com/glothriel/grf/pkg/pagination"
 	"github.com/glothriel/grf/pkg/queries"
 	"github.com/glothriel/grf/pkg/queries/crud"
 	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/glothriel/grf/pkg/throttling"
+	"github.com/glothriel/grf/pkg/tombstones"
 	"github.com/glothriel/grf/pkg/types"
 )
 
 const (
-	ActionCreate = iota
-	ActionUpdate
-	ActionDestroy
-	ActionList
-	ActionRetrieve
+	ActionCreate   = grfctx.ActionCreate
+	ActionUpdate   = grfctx.ActionUpdate
+	ActionDestroy  = grfctx.ActionDestroy
+	ActionList     = grfctx.ActionList
+	ActionRetrieve = grfctx.ActionRetrieve
+	ActionCustom   = grfctx.ActionCustom
+
+	ActionPartialUpdate = grfctx.ActionPartialUpdate
 )
 
-type ActionID int
+type ActionID = grfctx.Action
+
+// GroupSettings are shared by all the viewsets registered in an API group, see grf.NewAPIGroup
+type GroupSettings struct {
+	Authentication authentication.Authentication
+	Permissions    []authentication.Permission
+	Throttles      []throttling.Throttle
+	ThrottleQueue  *throttling.Queue
+	Middleware     []Middleware
+	// Strict makes the views of the group panic during the registration, if they rely on the unsafe
+	// defaults, like the anonymous authentication, the missing permissions or the unbounded lists.
+	// The views opt out of the checks deliberately using WithoutStrictChecks.
+	Strict bool
+}
 
 type ViewSet[Model any] struct {
-	Path        string
-	IDFunc      IDFunc
+	Path    string
+	IDParam string
+	IDFunc  IDFunc
+	// LookupField is the model field matched against the IDParam on detail routes
+	LookupField string
 	QueryDriver queries.Driver[Model]
 
 	ListAction     *ViewSetAction[Model]
@@ -34,62 +62,294 @@ type ViewSet[Model any] struct {
 	UpdateAction   *ViewSetAction[Model]
 	DestroyAction  *ViewSetAction[Model]
 
+	PartialUpdateAction *ViewSetAction[Model]
+
 	DefaultSerializer serializers.Serializer
 
 	ListCreateView            *View
 	RetrieveUpdateDestroyView *View
+
+	listSerializer      serializers.Serializer
+	detailSerializer    serializers.Serializer
+	representationHooks []RepresentationHook
+	middleware          []Middleware
+	authentication      authentication.Authentication
+	throttles           []throttling.Throttle
+	throttleQueue       *throttling.Queue
+	tombstones          tombstones.Tombstones
+	extraActions        []extraAction[Model]
+	filterFields        filters.Fields
+	filterSets          []*filters.FilterSet[Model]
+	searchFields        []string
+	orderingFields      []string
+	defaultOrdering     []grfctx.OrderBy
+	querySerializer     *serializers.QuerySerializer
+	pageSize            int
+	maxPageSize         int
+	paginator           pagination.Paginator
+	paginationRequired  bool
+	bulkUpdate          bool
+	bulkDestroy         bool
+	description         string
+	example             any
+	name                string
+	permissions         []authentication.Permission
+	strict              bool
+	strictOptOuts       []StrictCheck
 }
 
+// WithExtraAction adds a custom endpoint to the collection, or to the objects if isDetail is set,
+// for example `/users/:id/activate` for the relative path `/activate`. The action is registered with
+// the other routes, sharing the viewset's query driver with its middleware, authentication,
+// throttling and representation hooks. The serializer of the list action, or the detail actions,
+// is used if serializer is nil. The collection actions apply the filters, search and ordering of
+// the list.
 func (v *ViewSet[Model]) WithExtraAction(
 	action *ExtraAction[Model],
 	serializer serializers.Serializer,
 	isDetail bool,
 ) *ViewSet[Model] {
-	view := v.ListCreateView
-	if isDetail {
-		view = v.RetrieveUpdateDestroyView
-	}
-
-	view.WithRoute(&ViewRoute{
-		Method:       action.Method,
-		RelativePath: action.RelativePath,
-		Handler:      action.Handler(v.IDFunc, v.QueryDriver, serializer),
-	})
+	v.extraActions = append(v.extraActions, extraAction[Model]{action: action, serializer: serializer, isDetail: isDetail})
 	return v
 }
 
 func (v *ViewSet[Model]) Register(r gin.IRouter) {
+	v.RegisterOn(GinRouter(r))
+}
+
+// RegisterOn registers the viewset's routes on any Router, for example the ones of the adapters
+// package, mounting the viewset on routers other than gin
+func (v *ViewSet[Model]) RegisterOn(r Router) {
+	v.checkStrict()
+	queryDriver := withMiddleware(v.QueryDriver, v.allMiddleware())
+	for _, view := range []*View{v.ListCreateView, v.RetrieveUpdateDestroyView} {
+		if v.authentication != nil {
+			view.WithAuthentication(v.authentication)
+		}
+		if v.throttles != nil {
+			view.WithThrottle(v.throttles...)
+		}
+		if v.throttleQueue != nil {
+			view.WithThrottleQueue(v.throttleQueue)
+		}
+		if v.permissions != nil {
+			view.WithPermissions(v.permissions...)
+		}
+		view.strict = view.strict || v.strict
+		view.WithoutStrictChecks(v.strictOptOuts...)
+	}
 	if v.ListAction != nil {
-		v.ListCreateView.Get(v.ListAction.ViewSetHandlerFactoryFunc(v.IDFunc, v.QueryDriver, v.ListAction.Serializer))
+		listHandler := v.ListAction.ViewSetHandlerFactoryFunc
+		if paginator := v.listPaginator(); v.paginator == nil && paginator != nil {
+			listHandler = PaginatedListModelViewSetFunc[Model](paginator)
+		}
+		v.ListCreateView.Get(v.withMetadata(
+			grfctx.Metadata{Action: ActionList, View: v.viewSettings(false)},
+			v.withListQuery(listHandler(v.IDFunc, queryDriver, v.withHooks(v.ListAction.Serializer))),
+		))
 	}
 	if v.CreateAction != nil {
-		v.ListCreateView.Post(v.CreateAction.ViewSetHandlerFactoryFunc(v.IDFunc, v.QueryDriver, v.CreateAction.Serializer))
+		v.ListCreateView.Post(v.withMetadata(
+			grfctx.Metadata{Action: ActionCreate, View: v.viewSettings(false)},
+			v.CreateAction.ViewSetHandlerFactoryFunc(v.IDFunc, queryDriver, v.withHooks(v.CreateAction.Serializer)),
+		))
 	}
 	if v.RetrieveAction != nil {
-		v.RetrieveUpdateDestroyView.Get(v.RetrieveAction.ViewSetHandlerFactoryFunc(v.IDFunc, v.QueryDriver, v.RetrieveAction.Serializer))
+		v.RetrieveUpdateDestroyView.Get(v.withMetadata(
+			grfctx.Metadata{Action: ActionRetrieve, View: v.viewSettings(true)},
+			v.RetrieveAction.ViewSetHandlerFactoryFunc(v.IDFunc, queryDriver, v.withHooks(v.RetrieveAction.Serializer)),
+		))
 	}
 	if v.UpdateAction != nil {
-		v.RetrieveUpdateDestroyView.Put(v.UpdateAction.ViewSetHandlerFactoryFunc(v.IDFunc, v.QueryDriver, v.UpdateAction.Serializer))
+		v.RetrieveUpdateDestroyView.Put(v.withMetadata(
+			grfctx.Metadata{Action: ActionUpdate, View: v.viewSettings(true)},
+			v.UpdateAction.ViewSetHandlerFactoryFunc(v.IDFunc, queryDriver, v.withHooks(v.UpdateAction.Serializer)),
+		))
+	}
+	if v.PartialUpdateAction != nil {
+		v.RetrieveUpdateDestroyView.Patch(v.withMetadata(
+			grfctx.Metadata{Action: ActionPartialUpdate, View: v.viewSettings(true)},
+			v.PartialUpdateAction.ViewSetHandlerFactoryFunc(v.IDFunc, queryDriver, v.withHooks(v.PartialUpdateAction.Serializer)),
+		))
 	}
 	if v.DestroyAction != nil {
-		v.RetrieveUpdateDestroyView.Delete(v.DestroyAction.ViewSetHandlerFactoryFunc(v.IDFunc, v.QueryDriver, v.DestroyAction.Serializer))
+		v.RetrieveUpdateDestroyView.Delete(v.withMetadata(
+			grfctx.Metadata{Action: ActionDestroy, View: v.viewSettings(true)},
+			v.DestroyAction.ViewSetHandlerFactoryFunc(v.IDFunc, queryDriver, v.withHooks(v.DestroyAction.Serializer)),
+		))
+	}
+	// The routes added by the viewset are passed to the views on registration instead of being added
+	// to them, so the viewset can be registered more than once
+	listRoutes, detailRoutes := v.extraActionRoutes(queryDriver)
+	listRoutes = append(listRoutes, v.bulkActionRoutes(queryDriver)...)
+	if v.CreateAction != nil || v.UpdateAction != nil {
+		listRoutes = append(listRoutes, &ViewRoute{Method: http.MethodGet, RelativePath: "/_form", Handler: v.formHandler()})
+	}
+	listRoutes = append(listRoutes, &ViewRoute{Method: http.MethodOptions, Handler: v.metadataHandler(false)})
+	detailRoutes = append(detailRoutes, &ViewRoute{Method: http.MethodOptions, Handler: v.metadataHandler(true)})
+	v.ListCreateView.register(r, listRoutes)
+	v.RetrieveUpdateDestroyView.register(r, detailRoutes)
+	v.registerURL(r)
+	v.registerRoute(r)
+}
+
+// WithAuthentication sets the authentication used by all the viewset's routes
+func (v *ViewSet[Model]) WithAuthentication(a authentication.Authentication) *ViewSet[Model] {
+	v.authentication = a
+	return v
+}
+
+// WithPermissions requires the authenticated requests to all the viewset's routes to have all the
+// permissions, the other ones are responded with 403
+func (v *ViewSet[Model]) WithPermissions(permissions ...authentication.Permission) *ViewSet[Model] {
+	v.permissions = permissions
+	return v
+}
+
+// WithThrottle limits the rate of the requests to all the viewset's routes
+func (v *ViewSet[Model]) WithThrottle(throttles ...throttling.Throttle) *ViewSet[Model] {
+	v.throttles = throttles
+	return v
+}
+
+// WithThrottleQueue makes throttled write requests to the viewset wait in the queue, instead of
+// being rejected immediately
+func (v *ViewSet[Model]) WithThrottleQueue(queue *throttling.Queue) *ViewSet[Model] {
+	v.throttleQueue = queue
+	return v
+}
+
+// WithLocale resolves the locale of the requests to all the viewset's routes using the extractor,
+// after the authentication, so the user's profile can be used, see grfctx.CurrentLocale
+func (v *ViewSet[Model]) WithLocale(extractor *locale.Extractor) *ViewSet[Model] {
+	v.ListCreateView.AddMiddleware(extractor.Middleware())
+	v.RetrieveUpdateDestroyView.AddMiddleware(extractor.Middleware())
+	return v
+}
+
+// ApplyGroupSettings applies the settings shared by the API group. Authentication, permissions and
+// throttles configured on the viewset itself take precedence, the group's middleware runs before
+// the viewset's.
+func (v *ViewSet[Model]) ApplyGroupSettings(settings GroupSettings) {
+	if v.authentication == nil {
+		v.authentication = settings.Authentication
+	}
+	if v.permissions == nil {
+		v.permissions = settings.Permissions
+	}
+	v.strict = v.strict || settings.Strict
+	if v.throttles == nil {
+		v.throttles = settings.Throttles
+	}
+	if v.throttleQueue == nil {
+		v.throttleQueue = settings.ThrottleQueue
+	}
+	v.middleware = append(slices.Clone(settings.Middleware), v.middleware...)
+}
+
+func (v *ViewSet[Model]) allMiddleware() []Middleware {
+	return append(slices.Clone(globalMiddleware), v.middleware...)
+}
+
+// withMetadata exposes the metadata of the request using grfctx
+func (v *ViewSet[Model]) withMetadata(metadata grfctx.Metadata, handler gin.HandlerFunc) gin.HandlerFunc {
+	var m Model
+	metadata.ModelName = reflect.TypeOf(m).Name()
+	return func(ctx *gin.Context) {
+		grfctx.Set(ctx, metadata)
+		handler(ctx)
+	}
+}
+
+func (v *ViewSet[Model]) viewSettings(isDetail bool) grfctx.ViewSettings {
+	actions := []grfctx.Action{}
+	for _, action := range []struct {
+		id      ActionID
+		enabled bool
+	}{
+		{ActionCreate, v.CreateAction != nil},
+		{ActionUpdate, v.UpdateAction != nil},
+		{ActionDestroy, v.DestroyAction != nil},
+		{ActionList, v.ListAction != nil},
+		{ActionRetrieve, v.RetrieveAction != nil},
+		{ActionPartialUpdate, v.PartialUpdateAction != nil},
+	} {
+		if action.enabled {
+			actions = append(actions, action.id)
+		}
+	}
+	return grfctx.ViewSettings{
+		Path:        v.Path,
+		IDParam:     v.IDParam,
+		LookupField: v.LookupField,
+		Detail:      isDetail,
+		Actions:     actions,
+		PageSize:    v.pageSize,
+		MaxPageSize: v.maxPageSize,
 	}
-	v.ListCreateView.Register(r)
-	v.RetrieveUpdateDestroyView.Register(r)
+}
+
+// WithLookupField makes the detail routes look the objects up using the field instead of the ID,
+// for example `/articles/:article_id` matches the articles by slug with WithLookupField("slug").
+// The query driver receives the field in grfctx.LookupField and the value in place of the ID.
+func (v *ViewSet[Model]) WithLookupField(field string) *ViewSet[Model] {
+	v.LookupField = field
+	return v
+}
+
+// WithLookupURLParam renames the path param of the detail routes, for example to `uuid`, so the
+// routes become `/articles/:uuid`. The detail extra actions use the renamed param as well.
+func (v *ViewSet[Model]) WithLookupURLParam(param string) *ViewSet[Model] {
+	v.IDParam = param
+	v.IDFunc = IDFromPathParam(param)
+	v.RetrieveUpdateDestroyView.path = path.Join(v.Path, fmt.Sprintf(":%s", param))
+	return v
+}
+
+// WithMiddleware adds grf middleware, that wraps all the query driver operations of the viewset.
+// It runs after the middleware registered globally with UseMiddleware.
+func (v *ViewSet[Model]) WithMiddleware(middleware ...Middleware) *ViewSet[Model] {
+	v.middleware = append(v.middleware, middleware...)
+	return v
 }
 
 func (v *ViewSet[Model]) WithSerializer(serializer serializers.Serializer) *ViewSet[Model] {
 	v.DefaultSerializer = serializer
-	return v.WithListSerializer(serializer).WithRetrieveSerializer(serializer).WithUpdateSerializer(serializer).WithCreateSerializer(serializer).WithDestroySerializer(serializer)
+	v.detailSerializer = nil
+	return v.WithListSerializer(serializer).WithRetrieveSerializer(serializer).WithUpdateSerializer(serializer).WithPartialUpdateSerializer(serializer).WithCreateSerializer(serializer).WithDestroySerializer(serializer)
 }
 
+// WithListSerializer sets the serializer of the list action, for example to return slim summaries
+// of the objects in collections. It's also used if the list action is enabled afterwards.
 func (v *ViewSet[Model]) WithListSerializer(serializer serializers.Serializer) *ViewSet[Model] {
+	v.listSerializer = serializer
 	if v.ListAction != nil {
 		v.ListAction.Serializer = serializer
 	}
 	return v
 }
 
+// WithDetailSerializer sets the serializer of all the actions operating on a single object:
+// retrieve, create, update, partial update and destroy, as well as the detail custom actions, so
+// they return full objects while collections use the list serializer. It's also used by the actions
+// enabled afterwards.
+func (v *ViewSet[Model]) WithDetailSerializer(serializer serializers.Serializer) *ViewSet[Model] {
+	v.detailSerializer = serializer
+	return v.WithRetrieveSerializer(serializer).WithCreateSerializer(serializer).WithUpdateSerializer(serializer).WithPartialUpdateSerializer(serializer).WithDestroySerializer(serializer)
+}
+
+// actionSerializer returns the serializer of a newly enabled action: the list or detail serializer
+// if set, the default one otherwise
+func (v *ViewSet[Model]) actionSerializer(list bool) serializers.Serializer {
+	if list && v.listSerializer != nil {
+		return v.listSerializer
+	}
+	if !list && v.detailSerializer != nil {
+		return v.detailSerializer
+	}
+	return v.DefaultSerializer
+}
+
 func (v *ViewSet[Model]) WithRetrieveSerializer(serializer serializers.Serializer) *ViewSet[Model] {
 	if v.RetrieveAction != nil {
 		v.RetrieveAction.Serializer = serializer
@@ -104,6 +364,13 @@ func (v *ViewSet[Model]) WithUpdateSerializer(serializer serializers.Serializer)
 	return v
 }
 
+func (v *ViewSet[Model]) WithPartialUpdateSerializer(serializer serializers.Serializer) *ViewSet[Model] {
+	if v.PartialUpdateAction != nil {
+		v.PartialUpdateAction.Serializer = serializer
+	}
+	return v
+}
+
 func (v *ViewSet[Model]) WithCreateSerializer(serializer serializers.Serializer) *ViewSet[Model] {
 	if v.CreateAction != nil {
 		v.CreateAction.Serializer = serializer
@@ -118,6 +385,24 @@ func (v *ViewSet[Model]) WithDestroySerializer(serializer serializers.Serializer
 	return v
 }
 
+// WithRepresentationHook adds a hook, that is run on every representation produced by the viewset's
+// serializers, after serialization. Useful for last-mile tweaks like injecting links or stripping nulls.
+// Hooks run in the order they were added.
+func (v *ViewSet[Model]) WithRepresentationHook(hook RepresentationHook) *ViewSet[Model] {
+	v.representationHooks = append(v.representationHooks, hook)
+	return v
+}
+
+func (v *ViewSet[Model]) withHooks(serializer serializers.Serializer) serializers.Serializer {
+	if len(v.representationHooks) == 0 || serializer == nil {
+		return serializer
+	}
+	return &representationHookSerializer{
+		child: serializer,
+		hooks: slices.Clone(v.representationHooks),
+	}
+}
+
 func (v *ViewSet[Model]) WithFieldTypeMapper(fieldTypeMapper *types.FieldTypeMapper) *ViewSet[Model] {
 	return v
 }
@@ -128,12 +413,13 @@ func (v *ViewSet[Model]) WithList(handlerFactoryFunc ViewSetHandlerFactoryFunc[M
 			Path:                      v.Path,
 			View:                      v.ListCreateView,
 			ViewSetHandlerFactoryFunc: handlerFactoryFunc,
-			Serializer:                v.DefaultSerializer,
+			Serializer:                v.actionSerializer(true),
 			QueryDriver:               v.QueryDriver,
 		}
 	} else {
 		v.ListAction.ViewSetHandlerFactoryFunc = handlerFactoryFunc
 	}
+	v.paginator = nil
 	return v
 }
 
@@ -143,7 +429,7 @@ func (v *ViewSet[Model]) WithCreate(handlerFactoryFunc ViewSetHandlerFactoryFunc
 			Path:                      v.Path,
 			View:                      v.ListCreateView,
 			ViewSetHandlerFactoryFunc: handlerFactoryFunc,
-			Serializer:                v.DefaultSerializer,
+			Serializer:                v.actionSerializer(false),
 			QueryDriver:               v.QueryDriver,
 		}
 	} else {
@@ -158,7 +444,7 @@ func (v *ViewSet[Model]) WithRetrieve(handlerFactoryFunc ViewSetHandlerFactoryFu
 			Path:                      v.Path,
 			View:                      v.RetrieveUpdateDestroyView,
 			ViewSetHandlerFactoryFunc: handlerFactoryFunc,
-			Serializer:                v.DefaultSerializer,
+			Serializer:                v.actionSerializer(false),
 			QueryDriver:               v.QueryDriver,
 		}
 	} else {
@@ -173,7 +459,7 @@ func (v *ViewSet[Model]) WithUpdate(handlerFactoryFunc ViewSetHandlerFactoryFunc
 			Path:                      v.Path,
 			View:                      v.RetrieveUpdateDestroyView,
 			ViewSetHandlerFactoryFunc: handlerFactoryFunc,
-			Serializer:                v.DefaultSerializer,
+			Serializer:                v.actionSerializer(false),
 			QueryDriver:               v.QueryDriver,
 		}
 	} else {
@@ -182,13 +468,30 @@ func (v *ViewSet[Model]) WithUpdate(handlerFactoryFunc ViewSetHandlerFactoryFunc
 	return v
 }
 
+// WithPartialUpdate enables the partial update action, handling PATCH requests, which only change
+// the fields present in the request body
+func (v *ViewSet[Model]) WithPartialUpdate(handlerFactoryFunc ViewSetHandlerFactoryFunc[Model]) *ViewSet[Model] {
+	if v.PartialUpdateAction == nil {
+		v.PartialUpdateAction = &ViewSetAction[Model]{
+			Path:                      v.Path,
+			View:                      v.RetrieveUpdateDestroyView,
+			ViewSetHandlerFactoryFunc: handlerFactoryFunc,
+			Serializer:                v.actionSerializer(false),
+			QueryDriver:               v.QueryDriver,
+		}
+	} else {
+		v.PartialUpdateAction.ViewSetHandlerFactoryFunc = handlerFactoryFunc
+	}
+	return v
+}
+
 func (v *ViewSet[Model]) WithDestroy(handlerFactoryFunc ViewSetHandlerFactoryFunc[Model]) *ViewSet[Model] {
 	if v.DestroyAction == nil {
 		v.DestroyAction = &ViewSetAction[Model]{
 			Path:                      v.Path,
 			View:                      v.RetrieveUpdateDestroyView,
 			ViewSetHandlerFactoryFunc: handlerFactoryFunc,
-			Serializer:                v.DefaultSerializer,
+			Serializer:                v.actionSerializer(false),
 			QueryDriver:               v.QueryDriver,
 		}
 	} else {
@@ -209,6 +512,7 @@ func (v *ViewSet[Model]) WithActions(actions ...ActionID) *ViewSet[Model] {
 		{ActionDestroy, v.WithDestroy, &v.DestroyAction, DestroyModelViewSetFunc[Model]},
 		{ActionList, v.WithList, &v.ListAction, ListModelViewSetFunc[Model]},
 		{ActionRetrieve, v.WithRetrieve, &v.RetrieveAction, RetrieveModelViewSetFunc[Model]},
+		{ActionPartialUpdate, v.WithPartialUpdate, &v.PartialUpdateAction, PartialUpdateModelViewSetFunc[Model]},
 	}
 
 	actionSet := make(map[ActionID]bool)
@@ -227,6 +531,30 @@ func (v *ViewSet[Model]) WithActions(actions ...ActionID) *ViewSet[Model] {
 	return v
 }
 
+// WithoutActions disables the passed actions, keeping the other ones, for example to make a
+// ModelViewSet read-only:
+//
+//	views.NewModelViewSet[Person]("/people", driver).WithoutActions(views.ActionCreate, views.ActionDestroy)
+func (v *ViewSet[Model]) WithoutActions(actions ...ActionID) *ViewSet[Model] {
+	for _, action := range actions {
+		switch action {
+		case ActionCreate:
+			v.CreateAction = nil
+		case ActionUpdate:
+			v.UpdateAction = nil
+		case ActionPartialUpdate:
+			v.PartialUpdateAction = nil
+		case ActionDestroy:
+			v.DestroyAction = nil
+		case ActionList:
+			v.ListAction = nil
+		case ActionRetrieve:
+			v.RetrieveAction = nil
+		}
+	}
+	return v
+}
+
 func (v *ViewSet[Model]) OnCreate(modFunc func(c crud.CreateQueryFunc) crud.CreateQueryFunc) *ViewSet[Model] {
 	v.QueryDriver.CRUD().WithCreate(modFunc(v.QueryDriver.CRUD().Create))
 	return v
@@ -242,8 +570,12 @@ func (v *ViewSet[Model]) OnDestroy(modFunc func(d crud.DestroyQueryFunc) crud.De
 	return v
 }
 
+// NewModelViewSet creates a viewset with all the actions enabled: list, create, retrieve, update,
+// partial update and destroy. Use WithoutActions or WithActions to disable some of them.
 func NewModelViewSet[Model any](path string, queryDriver queries.Driver[Model]) *ViewSet[Model] {
-	return NewViewSet(path, queryDriver, serializers.NewModelSerializer[Model]()).WithActions(ActionCreate, ActionUpdate, ActionDestroy, ActionList, ActionRetrieve)
+	return NewViewSet(path, queryDriver, serializers.NewModelSerializer[Model]()).WithActions(
+		ActionCreate, ActionUpdate, ActionPartialUpdate, ActionDestroy, ActionList, ActionRetrieve,
+	)
 }
 
 func NewViewSet[Model any](
@@ -265,8 +597,10 @@ func NewViewSet[Model any](
 
 	return &ViewSet[Model]{
 		Path:                      routerPath,
+		IDParam:                   idParamName,
 		QueryDriver:               queryDriver,
 		IDFunc:                    IDFromPathParam(idParamName),
+		LookupField:               "id",
 		DefaultSerializer:         defaultSerializer,
 		ListCreateView:            NewView(routerPath, queryDriver),
 		RetrieveUpdateDestroyView: NewView(retrieveUpdateDestroyPath, queryDriver),
@@ -6,10 +6,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/glothriel/grf/pkg/fields"
+	"github.com/glothriel/grf/pkg/grfctx"
+	"github.com/glothriel/grf/pkg/locale"
+	"github.com/glothriel/grf/pkg/models"
 	"github.com/glothriel/grf/pkg/queries"
+	"github.com/glothriel/grf/pkg/queries/common"
+	"github.com/glothriel/grf/pkg/queries/crud"
 	"github.com/glothriel/grf/pkg/serializers"
+	"github.com/glothriel/grf/pkg/throttling"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -86,6 +94,15 @@ var caseUpdate viewsetTestCase = viewsetTestCase{
 	},
 }
 
+var casePartialUpdate viewsetTestCase = viewsetTestCase{
+	name: "PATCH request to single item endpoint",
+	params: quickReqParams{
+		method: "PATCH",
+		path:   "/mocks/1",
+		body:   strBody(`{"price": 3.0}`),
+	},
+}
+
 var caseDestroy viewsetTestCase = viewsetTestCase{
 	name: "DELETE request to single item endpoint",
 	params: quickReqParams{
@@ -95,7 +112,7 @@ var caseDestroy viewsetTestCase = viewsetTestCase{
 	},
 }
 
-func TestEmptyViewsetRespondsWithMethodNotFound(t *testing.T) {
+func TestEmptyViewsetRespondsWithMethodNotAllowed(t *testing.T) {
 	viewset := NewViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](), serializers.NewModelSerializer[anotherMockModel]())
 	_, r := gin.CreateTestContext(httptest.NewRecorder())
 	viewset.Register(r)
@@ -109,11 +126,12 @@ func TestEmptyViewsetRespondsWithMethodNotFound(t *testing.T) {
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			w := quickReq(r, tc.params)
-			assert.Equal(t, 404, w.Code)
+			assert.Equal(t, 405, w.Code)
+			assert.Equal(t, "OPTIONS", w.Header().Get("Allow"))
 		})
 	}
 }
-func TestViewsetWhenOnlyListActionRegisteredAllOthersReturn404(t *testing.T) {
+func TestViewsetWhenOnlyListActionRegisteredAllOthersReturn405(t *testing.T) {
 	viewset := NewViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](), serializers.NewModelSerializer[anotherMockModel]()).WithActions(ActionList)
 	_, r := gin.CreateTestContext(httptest.NewRecorder())
 	viewset.Register(r)
@@ -122,11 +140,12 @@ func TestViewsetWhenOnlyListActionRegisteredAllOthersReturn404(t *testing.T) {
 		caseCreate,
 		caseRetrieve,
 		caseUpdate,
+		casePartialUpdate,
 		caseDestroy,
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			w := quickReq(r, tt.params)
-			assert.Equal(t, 404, w.Code)
+			assert.Equal(t, 405, w.Code)
 		})
 	}
 
@@ -205,6 +224,29 @@ func TestCustomRetrieveSerializer(t *testing.T) {
 	assert.Equal(t, `{"name":"Canned Beans"}`, rt.Body.String())
 }
 
+func TestListAndDetailSerializers(t *testing.T) {
+	// given
+	viewset := NewViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+	), nameOnlySerializer).WithListSerializer(
+		serializers.NewModelSerializer[anotherMockModel]().WithModelFields([]string{"id", "name"}),
+	).WithDetailSerializer(
+		serializers.NewModelSerializer[anotherMockModel](),
+	).WithActions(ActionList, ActionRetrieve, ActionCreate)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	ls := quickReq(r, caseList.params)
+	rt := quickReq(r, caseRetrieve.params)
+	cr := quickReq(r, quickReqParams{method: "POST", path: "/mocks", body: strBody(`{"name": "Canned Peas", "price": 2}`)})
+
+	// then
+	assert.Equal(t, `[{"id":1,"name":"Canned Beans"}]`, ls.Body.String())
+	assert.Equal(t, `{"id":1,"name":"Canned Beans","price":1}`, rt.Body.String())
+	assert.Equal(t, `{"id":2,"name":"Canned Peas","price":2}`, cr.Body.String())
+}
+
 var testCases = []struct {
 	name     string
 	endpoint string
@@ -368,3 +410,422 @@ func TestOverridingActionsWithCustomView(t *testing.T) {
 		})
 	}
 }
+
+func TestRepresentationHook(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+	)).WithSerializer(nameOnlySerializer).WithRepresentationHook(
+		func(ctx *gin.Context, repr serializers.Representation) (serializers.Representation, error) {
+			repr["link"] = "/mocks/" + repr["name"].(string)
+			return repr, nil
+		},
+	).WithRepresentationHook(
+		func(ctx *gin.Context, repr serializers.Representation) (serializers.Representation, error) {
+			delete(repr, "name")
+			return repr, nil
+		},
+	)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	ls := quickReq(r, caseList.params)
+	rt := quickReq(r, caseRetrieve.params)
+
+	// then
+	assert.Equal(t, 200, ls.Code)
+	assert.Equal(t, `[{"link":"/mocks/Canned Beans"}]`, ls.Body.String())
+	assert.Equal(t, 200, rt.Code)
+	assert.Equal(t, `{"link":"/mocks/Canned Beans"}`, rt.Body.String())
+}
+
+func TestSparseFields(t *testing.T) {
+	tests := []struct {
+		name         string
+		params       quickReqParams
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "list",
+			params:       quickReqParams{method: "GET", path: "/mocks?fields=name,id", body: noBody},
+			expectedCode: 200,
+			expectedBody: `[{"id": 1, "name": "Canned Beans"}]`,
+		},
+		{
+			name:         "retrieve",
+			params:       quickReqParams{method: "GET", path: "/mocks/1?fields=price", body: noBody},
+			expectedCode: 200,
+			expectedBody: `{"price": 1}`,
+		},
+		{
+			name:         "all fields",
+			params:       caseRetrieve.params,
+			expectedCode: 200,
+			expectedBody: `{"id": 1, "name": "Canned Beans", "price": 1}`,
+		},
+		{
+			name:         "field not allowed",
+			params:       quickReqParams{method: "GET", path: "/mocks?fields=id,secret,price", body: noBody},
+			expectedCode: 400,
+			expectedBody: `{"errors": {"fields": ["unknown field: secret"]}}`,
+		},
+		{
+			name: "writes",
+			params: quickReqParams{
+				method: "PUT", path: "/mocks/1?fields=id", body: strBody(`{"price": 1.0, "name": "Canned Beans"}`),
+			},
+			expectedCode: 200,
+			expectedBody: `{"id": 1, "name": "Canned Beans", "price": 1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+				anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+			)).WithSparseFields("id", "name", "price")
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+			viewset.Register(r)
+
+			// when
+			response := quickReq(r, tt.params)
+
+			// then
+			assert.Equal(t, tt.expectedCode, response.Code)
+			assert.JSONEq(t, tt.expectedBody, response.Body.String())
+		})
+	}
+}
+
+func TestViewSetWithLocale(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1234.5, Name: "Canned Beans"},
+	)).WithSerializer(
+		serializers.NewModelSerializer[anotherMockModel]().
+			WithModelFields([]string{"price"}).
+			WithField("price", fields.NewMoneyField().Field()),
+	).WithLocale(locale.NewExtractor(grfctx.Locale{Language: "en", Currency: "USD"}).WithLanguages("en", "de"))
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	defaults := quickReq(r, caseList.params)
+	requested := quickReq(r, quickReqParams{method: "GET", path: "/mocks/1?lang=de-AT&currency=eur", body: noBody})
+
+	// then
+	assert.Equal(t, 200, defaults.Code)
+	assert.JSONEq(t, `[{"price":{"amount":1234.5,"currency":"USD","formatted":"$1,234.50"}}]`, defaults.Body.String())
+	assert.Equal(t, 200, requested.Code)
+	assert.JSONEq(t, `{"price":{"amount":1234.5,"currency":"EUR","formatted":"1.234,50 €"}}`, requested.Body.String())
+}
+
+func TestViewSetExposesRequestMetadata(t *testing.T) {
+	// given
+	seen := []grfctx.Metadata{}
+	recordMetadata := func(ctx *gin.Context, repr serializers.Representation) (serializers.Representation, error) {
+		metadata, _ := grfctx.Get(ctx)
+		seen = append(seen, metadata)
+		return repr, nil
+	}
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+	)).WithActions(ActionList, ActionRetrieve).WithRepresentationHook(recordMetadata)
+	viewset.WithExtraAction(
+		NewExtraAction(http.MethodGet, "/custom", ListModelViewSetFunc[anotherMockModel]),
+		nameOnlySerializer,
+		false,
+	)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	quickReq(r, caseList.params)
+	quickReq(r, caseRetrieve.params)
+	quickReq(r, quickReqParams{method: http.MethodGet, path: "/mocks/custom", body: noBody})
+
+	// then
+	listSettings := grfctx.ViewSettings{
+		Path: "/mocks", IDParam: "anothermockmodel_id", LookupField: "id",
+		Actions: []grfctx.Action{ActionList, ActionRetrieve},
+	}
+	retrieveSettings := listSettings
+	retrieveSettings.Detail = true
+	assert.Equal(t, []grfctx.Metadata{
+		{Action: ActionList, ModelName: "anotherMockModel", View: listSettings},
+		{Action: ActionRetrieve, ModelName: "anotherMockModel", View: retrieveSettings},
+		{Action: ActionCustom, CustomAction: "/custom", ModelName: "anotherMockModel", View: listSettings},
+	}, seen)
+	assert.Equal(t, "retrieve", ActionRetrieve.String())
+}
+
+func TestThrottledViewSetSetsRateLimitHeaders(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel]()).WithThrottle(
+		throttling.NewRateThrottle(2, time.Hour),
+	)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+	list := quickReqParams{method: "GET", path: "/mocks", body: strBody("")}
+
+	// when
+	first, second, third := quickReq(r, list), quickReq(r, list), quickReq(r, list)
+
+	// then
+	assert.Equal(t, 200, first.Code)
+	assert.Equal(t, "2", first.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, first.Header().Get("X-RateLimit-Reset"))
+	assert.Equal(t, 200, second.Code)
+	assert.Equal(t, "0", second.Header().Get("RateLimit-Remaining"))
+	assert.Empty(t, second.Header().Get("Retry-After"))
+	assert.Equal(t, 429, third.Code)
+	assert.Equal(t, "0", third.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, third.Header().Get("RateLimit-Reset"), third.Header().Get("Retry-After"))
+}
+
+func TestDeniedRequestsDontConsumeTheQuotaOfOtherThrottles(t *testing.T) {
+	// given
+	hourly, burst := throttling.NewRateThrottle(10, time.Hour), throttling.NewRateThrottle(1, time.Hour)
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel]()).WithThrottle(
+		hourly, burst,
+	)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+	list := quickReqParams{method: "GET", path: "/mocks", body: strBody("")}
+
+	// when
+	first, second, third := quickReq(r, list), quickReq(r, list), quickReq(r, list)
+
+	// then
+	assert.Equal(t, 200, first.Code)
+	assert.Equal(t, 429, second.Code)
+	assert.Equal(t, 429, third.Code)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request, _ = http.NewRequest("GET", "/mocks", nil)
+	_, quota := hourly.Peek(ctx)
+	assert.Equal(t, 9, quota.Remaining)
+}
+
+func TestThrottleQueueDelaysWritesInsteadOfRejecting(t *testing.T) {
+	// given
+	queue := throttling.NewQueue(5, time.Second)
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel]()).WithThrottle(
+		throttling.NewRateThrottle(1, 50*time.Millisecond),
+	).WithThrottleQueue(queue)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	first := quickReq(r, quickReqParams{method: "POST", path: "/mocks", body: strBody(`{"name": "first"}`)})
+	read := quickReq(r, quickReqParams{method: "GET", path: "/mocks", body: strBody("")})
+	second := quickReq(r, quickReqParams{method: "POST", path: "/mocks", body: strBody(`{"name": "second"}`)})
+
+	// then
+	assert.Equal(t, 201, first.Code)
+	assert.Equal(t, 429, read.Code)
+	assert.Equal(t, 201, second.Code)
+	assert.Equal(t, throttling.QueueStats{Depth: 0, Queued: 1, Admitted: 1, Rejected: 0}, queue.Stats())
+}
+
+func TestModelViewSetPartialUpdate(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+	))
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	patched := quickReq(r, casePartialUpdate.params)
+	retrieved := quickReq(r, caseRetrieve.params)
+
+	// then
+	assert.Equal(t, 200, patched.Code)
+	assert.JSONEq(t, `{"id": 1, "name": "Canned Beans", "price": 3}`, patched.Body.String())
+	assert.JSONEq(t, `{"id": 1, "name": "Canned Beans", "price": 3}`, retrieved.Body.String())
+}
+
+func TestModelViewSetWithoutActions(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+	)).WithoutActions(ActionCreate, ActionUpdate, ActionPartialUpdate, ActionDestroy)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	codes := map[string]int{}
+	for _, tt := range []viewsetTestCase{
+		caseList, caseRetrieve, caseCreate, caseUpdate, casePartialUpdate, caseDestroy,
+	} {
+		codes[tt.params.method+" "+tt.params.path] = quickReq(r, tt.params).Code
+	}
+
+	// then
+	assert.Equal(t, map[string]int{
+		"GET /mocks":      200,
+		"GET /mocks/1":    200,
+		"POST /mocks":     405,
+		"PUT /mocks/1":    405,
+		"PATCH /mocks/1":  405,
+		"DELETE /mocks/1": 405,
+	}, codes)
+}
+
+type sluggedModel struct {
+	ID    uint   `json:"id"`
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+}
+
+func TestViewSetWithLookupField(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[sluggedModel]("/articles", queries.InMemory[sluggedModel](
+		sluggedModel{Slug: "hello-world", Title: "Hello"},
+		sluggedModel{Slug: "goodbye", Title: "Goodbye"},
+	)).WithLookupField("slug").WithLookupURLParam("slug")
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	retrieved := quickReq(r, quickReqParams{method: "GET", path: "/articles/hello-world", body: noBody})
+	updated := quickReq(r, quickReqParams{
+		method: "PATCH", path: "/articles/hello-world", body: strBody(`{"title": "Hello again"}`),
+	})
+	deleted := quickReq(r, quickReqParams{method: "DELETE", path: "/articles/goodbye", body: noBody})
+	byID := quickReq(r, quickReqParams{method: "GET", path: "/articles/1", body: noBody})
+	afterDelete := quickReq(r, quickReqParams{method: "GET", path: "/articles/goodbye", body: noBody})
+
+	// then
+	assert.Equal(t, 200, retrieved.Code)
+	assert.JSONEq(t, `{"id": 1, "slug": "hello-world", "title": "Hello"}`, retrieved.Body.String())
+	assert.Equal(t, 200, updated.Code)
+	assert.JSONEq(t, `{"id": 1, "slug": "hello-world", "title": "Hello again"}`, updated.Body.String())
+	assert.Equal(t, 204, deleted.Code)
+	assert.Equal(t, 404, byID.Code)
+	assert.Equal(t, 404, afterDelete.Code)
+}
+
+type idlessArticle struct {
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+}
+
+// idlessArticleDriver stores the articles by slug, as the model has no id field
+type idlessArticleDriver struct {
+	storage map[any]models.InternalValue
+}
+
+func (d *idlessArticleDriver) CRUD() *crud.CRUD[idlessArticle] {
+	return (&crud.CRUD[idlessArticle]{}).WithRetrieve(func(_ *gin.Context, id any) (models.InternalValue, error) {
+		stored, ok := d.storage[id]
+		if !ok {
+			return nil, common.ErrorNotFound
+		}
+		return stored, nil
+	}).WithUpdate(func(_ *gin.Context, _, new models.InternalValue, id any) (models.InternalValue, error) {
+		d.storage[id] = new
+		return new, nil
+	})
+}
+
+func (d *idlessArticleDriver) Pagination() common.Pagination { return common.NoPagination{} }
+func (d *idlessArticleDriver) Filter() common.QueryMod       { return common.NewCompositeQueryMod() }
+func (d *idlessArticleDriver) Order() common.QueryMod        { return common.NewCompositeQueryMod() }
+func (d *idlessArticleDriver) Middleware() []gin.HandlerFunc { return nil }
+
+func TestViewSetWithLookupFieldUpdatesModelsWithoutID(t *testing.T) {
+	// given
+	driver := &idlessArticleDriver{storage: map[any]models.InternalValue{
+		"hello-world": {"slug": "hello-world", "title": "Hello"},
+	}}
+	viewset := NewModelViewSet[idlessArticle]("/articles", driver).
+		WithLookupField("slug").
+		WithLookupURLParam("slug")
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	updated := quickReq(r, quickReqParams{
+		method: "PUT", path: "/articles/hello-world", body: strBody(`{"slug": "hello-world", "title": "Hello again"}`),
+	})
+	partiallyUpdated := quickReq(r, quickReqParams{
+		method: "PATCH", path: "/articles/hello-world", body: strBody(`{"title": "Hello once more"}`),
+	})
+
+	// then
+	assert.Equal(t, 200, updated.Code)
+	assert.JSONEq(t, `{"slug": "hello-world", "title": "Hello again"}`, updated.Body.String())
+	assert.Equal(t, 200, partiallyUpdated.Code)
+	assert.JSONEq(t, `{"slug": "hello-world", "title": "Hello once more"}`, partiallyUpdated.Body.String())
+}
+
+func TestViewSetRespondsWithAllowHeaderForUnhandledMethods(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+	)).WithoutActions(ActionDestroy)
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	collection := quickReq(r, quickReqParams{method: "DELETE", path: "/mocks", body: noBody})
+	detail := quickReq(r, caseDestroy.params)
+	head := quickReq(r, quickReqParams{method: "HEAD", path: "/mocks/1", body: noBody})
+
+	// then
+	assert.Equal(t, 405, collection.Code)
+	assert.Equal(t, "GET, HEAD, POST, OPTIONS", collection.Header().Get("Allow"))
+	assert.JSONEq(t, `{"message": "method not allowed"}`, collection.Body.String())
+	assert.Equal(t, 405, detail.Code)
+	assert.Equal(t, "GET, HEAD, PUT, PATCH, OPTIONS", detail.Header().Get("Allow"))
+	assert.Equal(t, 200, head.Code)
+}
+
+func TestViewSetRespondsWithMethodNotAllowedBeforeAuthentication(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel]()).
+		WithoutActions(ActionDestroy).
+		WithAuthentication(&rejectingAuthentication{})
+	_, r := gin.CreateTestContext(httptest.NewRecorder())
+	viewset.Register(r)
+
+	// when
+	unhandled := quickReq(r, caseDestroy.params)
+	handled := quickReq(r, caseRetrieve.params)
+
+	// then
+	assert.Equal(t, 405, unhandled.Code)
+	assert.Equal(t, 401, handled.Code)
+}
+
+func TestViewSetCanBeRegisteredTwice(t *testing.T) {
+	// given
+	viewset := NewModelViewSet[anotherMockModel]("/mocks", queries.InMemory[anotherMockModel](
+		anotherMockModel{Price: 1.0, Name: "Canned Beans"},
+	)).WithBulkUpdate().WithBulkDestroy().WithExtraAction(
+		NewExtraAction(http.MethodGet, "/custom", ListModelViewSetFunc[anotherMockModel]),
+		nameOnlySerializer,
+		false,
+	)
+	_, first := gin.CreateTestContext(httptest.NewRecorder())
+	_, second := gin.CreateTestContext(httptest.NewRecorder())
+
+	// when
+	assert.NotPanics(t, func() {
+		viewset.Register(first)
+		viewset.Register(second)
+	})
+
+	// then
+	for _, r := range []*gin.Engine{first, second} {
+		assert.Equal(t, 200, quickReq(r, caseList.params).Code)
+		assert.Equal(t, 200, quickReq(r, quickReqParams{method: "GET", path: "/mocks/custom", body: noBody}).Code)
+		assert.Equal(t, 200, quickReq(r, quickReqParams{method: "GET", path: "/mocks/_form", body: noBody}).Code)
+		assert.Equal(t, 200, quickReq(r, quickReqParams{method: "OPTIONS", path: "/mocks/1", body: noBody}).Code)
+	}
+}